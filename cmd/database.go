@@ -125,6 +125,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction tag index table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplit))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction split table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionTemplate))
 
 	if err != nil {
@@ -133,6 +141,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction template table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionTemplateOccurrenceOverride))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction template occurrence override table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionPictureInfo))
 
 	if err != nil {
@@ -157,6 +173,30 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user custom exchange rate table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.ExchangeRateSnapshot))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] exchange rate snapshot table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.NetWorthSnapshot))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] net worth snapshot table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.NetWorthSnapshotState))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] net worth snapshot state table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserApplicationCloudSetting))
 
 	if err != nil {
@@ -181,5 +221,77 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] insights explorer table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Budget))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] budget table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionRule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction rule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Payee))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] payee table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.AmortizationSchedule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] amortization schedule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentTransaction))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment transaction table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.SecurityPrice))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] security price table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Ledger))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] ledger table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.LedgerMember))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] ledger member table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction history table maintained successfully")
+
 	return nil
 }
@@ -339,6 +339,25 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "transaction-history",
+			Usage:  "List all change histories of the specified transaction",
+			Action: bindAction(listUserTransactionHistories),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.Int64Flag{
+					Name:     "id",
+					Aliases:  []string{"i"},
+					Required: true,
+					Usage:    "Specific transaction id",
+				},
+			},
+		},
 		{
 			Name:   "transaction-import",
 			Usage:  "Import transactions to specified user",
@@ -385,7 +404,45 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Export file type, support csv or tsv, default is csv",
+					Usage:    "Export file type, support csv, tsv, ofx, qif, beancount, ledger, gnucash or xlsx, default is csv",
+				},
+			},
+		},
+		{
+			Name:   "user-backup",
+			Usage:  "Backup user all data to archive file",
+			Action: bindAction(backupUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup archive file path (e.g. backup.zip)",
+				},
+			},
+		},
+		{
+			Name:   "user-restore",
+			Usage:  "Restore user all data from archive file to specified user without any data",
+			Action: bindAction(restoreUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup archive file path (e.g. backup.zip)",
 				},
 			},
 		},
@@ -843,6 +900,38 @@ func fixTransactionTagIndexNotHaveTransactionTime(c *core.CliContext) error {
 	return nil
 }
 
+func listUserTransactionHistories(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	transactionId := c.Int64("id")
+	histories, err := clis.UserData.GetTransactionHistories(c, username, transactionId)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.listUserTransactionHistories] error occurs when getting transaction histories")
+		return err
+	}
+
+	if len(histories) < 1 {
+		log.CliInfof(c, "[user_data.listUserTransactionHistories] transaction \"id:%d\" of user \"%s\" has no change history", transactionId, username)
+		return nil
+	}
+
+	for i := 0; i < len(histories); i++ {
+		printTransactionHistoryInfo(histories[i])
+
+		if i < len(histories)-1 {
+			fmt.Printf("---\n")
+		}
+	}
+
+	return nil
+}
+
 func exportUserTransaction(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -858,7 +947,7 @@ func exportUserTransaction(c *core.CliContext) error {
 		fileType = "csv"
 	}
 
-	if fileType != "csv" && fileType != "tsv" {
+	if fileType != "csv" && fileType != "tsv" && fileType != "ofx" && fileType != "qif" && fileType != "beancount" && fileType != "ledger" && fileType != "gnucash" && fileType != "xlsx" {
 		log.CliErrorf(c, "[user_data.exportUserTransaction] export file type is not supported")
 		return errs.ErrNotSupported
 	}
@@ -945,6 +1034,92 @@ func importUserTransaction(c *core.CliContext) error {
 	return nil
 }
 
+func backupUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.backupUserData] backup file path is unspecified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if fileExists {
+		log.CliErrorf(c, "[user_data.backupUserData] specified file path already exists")
+		return os.ErrExist
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] starting backing up user \"%s\" data", username)
+
+	content, err := clis.UserData.BackupUserData(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] error occurs when backing up user data")
+		return err
+	}
+
+	err = utils.WriteFile(filePath, content)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] failed to write to %s", filePath)
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] user data have been backed up to %s", filePath)
+
+	return nil
+}
+
+func restoreUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file path is not specified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if !fileExists {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file does not exist")
+		return os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(filePath)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] failed to load backup file")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] start restoring data to user \"%s\"", username)
+
+	err = clis.UserData.RestoreUserData(c, username, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] error occurs when restoring user data")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] user data have been restored to user \"%s\"", username)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
@@ -997,3 +1172,19 @@ func printTokenInfo(token *models.TokenRecord) {
 	fmt.Printf("[LastSeen] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.LastSeenUnixTime), token.LastSeenUnixTime)
 	fmt.Printf("[UserAgent] %s\n", token.UserAgent)
 }
+
+func printTransactionHistoryInfo(history *models.TransactionHistory) {
+	fmt.Printf("[Time] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(history.CreatedUnixTime), history.CreatedUnixTime)
+	fmt.Printf("[Action] %s (%d)\n", history.Action, history.Action)
+	fmt.Printf("[OperatorUid] %d\n", history.OperatorUid)
+	fmt.Printf("[OperatorTokenId] %s\n", history.OperatorTokenId)
+	fmt.Printf("[OperatorIp] %s\n", history.OperatorIp)
+
+	if history.Changes != nil {
+		changes := *history.Changes
+
+		for i := 0; i < len(changes); i++ {
+			fmt.Printf("[Change] %s: \"%s\" -> \"%s\"\n", changes[i].Field, changes[i].OldValue, changes[i].NewValue)
+		}
+	}
+}
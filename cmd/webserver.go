@@ -329,6 +329,7 @@ func startWebServer(c *core.CliContext) error {
 			// Users
 			apiV1Route.GET("/users/profile/get.json", bindApi(api.Users.UserProfileHandler))
 			apiV1Route.POST("/users/profile/update.json", bindApiWithTokenUpdate(api.Users.UserUpdateProfileHandler, config))
+			apiV1Route.POST("/users/transaction_lock/update.json", bindApi(api.Users.UserUpdateTransactionLockTimeHandler))
 
 			if config.AvatarProvider == core.USER_AVATAR_PROVIDER_INTERNAL {
 				apiV1Route.POST("/users/avatar/update.json", bindApi(api.Users.UserUpdateAvatarHandler))
@@ -368,8 +369,36 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+				apiV1Route.GET("/data/export.ofx", bindOfx(api.DataManagements.ExportDataToOFXHandler))
+				apiV1Route.GET("/data/export.qif", bindQif(api.DataManagements.ExportDataToQIFHandler))
+				apiV1Route.GET("/data/export.beancount", bindBeancount(api.DataManagements.ExportDataToBeancountHandler))
+				apiV1Route.GET("/data/export.ledger", bindLedger(api.DataManagements.ExportDataToLedgerHandler))
+				apiV1Route.GET("/data/export.gnucash", bindGnuCash(api.DataManagements.ExportDataToGnuCashHandler))
+				apiV1Route.GET("/data/export.xlsx", bindXlsx(api.DataManagements.ExportDataToXlsxHandler))
+				apiV1Route.GET("/data/backup.zip", bindZip(api.DataManagements.DataBackupHandler))
 			}
 
+			if config.EnableDataImport {
+				apiV1Route.POST("/data/restore.json", bindApi(api.DataManagements.DataRestoreHandler))
+			}
+
+			// Ledgers
+			apiV1Route.GET("/ledgers/list.json", bindApi(api.Ledgers.LedgerListHandler))
+			apiV1Route.GET("/ledgers/get.json", bindApi(api.Ledgers.LedgerGetHandler))
+			apiV1Route.POST("/ledgers/add.json", bindApi(api.Ledgers.LedgerCreateHandler))
+			apiV1Route.POST("/ledgers/modify.json", bindApi(api.Ledgers.LedgerModifyHandler))
+			apiV1Route.POST("/ledgers/hide.json", bindApi(api.Ledgers.LedgerHideHandler))
+			apiV1Route.POST("/ledgers/move.json", bindApi(api.Ledgers.LedgerMoveHandler))
+			apiV1Route.POST("/ledgers/delete.json", bindApi(api.Ledgers.LedgerDeleteHandler))
+
+			// Ledger Members
+			apiV1Route.GET("/ledgers/members/list.json", bindApi(api.LedgerMembers.LedgerMemberListHandler))
+			apiV1Route.POST("/ledgers/members/add.json", bindApi(api.LedgerMembers.LedgerMemberAddHandler))
+			apiV1Route.POST("/ledgers/members/modify.json", bindApi(api.LedgerMembers.LedgerMemberModifyHandler))
+			apiV1Route.POST("/ledgers/members/delete.json", bindApi(api.LedgerMembers.LedgerMemberDeleteHandler))
+			apiV1Route.GET("/ledgers/shared/list.json", bindApi(api.LedgerMembers.SharedLedgerListHandler))
+			apiV1Route.POST("/ledgers/shared/leave.json", bindApi(api.LedgerMembers.SharedLedgerLeaveHandler))
+
 			// Accounts
 			apiV1Route.GET("/accounts/list.json", bindApi(api.Accounts.AccountListHandler))
 			apiV1Route.GET("/accounts/get.json", bindApi(api.Accounts.AccountGetHandler))
@@ -379,6 +408,9 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/accounts/move.json", bindApi(api.Accounts.AccountMoveHandler))
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
 			apiV1Route.POST("/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler))
+			apiV1Route.GET("/accounts/trash/list.json", bindApi(api.Accounts.AccountTrashListHandler))
+			apiV1Route.POST("/accounts/trash/restore.json", bindApi(api.Accounts.AccountRestoreHandler))
+			apiV1Route.POST("/accounts/trash/purge.json", bindApi(api.Accounts.AccountPurgeHandler))
 
 			// Transactions
 			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
@@ -386,15 +418,23 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/transactions/list/by_month.json", bindApi(api.Transactions.TransactionMonthListHandler))
 			apiV1Route.GET("/transactions/list/all.json", bindApi(api.Transactions.TransactionListAllHandler))
 			apiV1Route.GET("/transactions/reconciliation_statements.json", bindApi(api.Transactions.TransactionReconciliationStatementHandler))
+			apiV1Route.POST("/transactions/reconciliation/set_status.json", bindApi(api.Transactions.TransactionReconciliationStatusSetHandler))
+			apiV1Route.POST("/transactions/reconciliation/reconcile.json", bindApi(api.Transactions.TransactionReconcileHandler))
 			apiV1Route.GET("/transactions/statistics.json", bindApi(api.Transactions.TransactionStatisticsHandler))
 			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
 			apiV1Route.GET("/transactions/statistics/asset_trends.json", bindApi(api.Transactions.TransactionStatisticsAssetTrendsHandler))
+			apiV1Route.GET("/transactions/statistics/net_worth_trends.json", bindApi(api.Transactions.TransactionStatisticsNetWorthTrendsHandler))
+			apiV1Route.GET("/transactions/cash_flow_forecast.json", bindApi(api.Transactions.TransactionCashFlowForecastHandler))
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
+			apiV1Route.GET("/transactions/history.json", bindApi(api.Transactions.TransactionHistoryListHandler))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
 			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
 			apiV1Route.POST("/transactions/move/all.json", bindApi(api.Transactions.TransactionMoveAllBetweenAccountsHandler))
 			apiV1Route.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
+			apiV1Route.GET("/transactions/trash/list.json", bindApi(api.Transactions.TransactionTrashListHandler))
+			apiV1Route.POST("/transactions/trash/restore.json", bindApi(api.Transactions.TransactionRestoreHandler))
+			apiV1Route.POST("/transactions/trash/purge.json", bindApi(api.Transactions.TransactionPurgeHandler))
 
 			if config.EnableDataImport {
 				apiV1Route.POST("/transactions/parse_custom_file.json", bindApi(api.Transactions.TransactionParseImportCustomFileDataHandler))
@@ -418,6 +458,9 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/categories/hide.json", bindApi(api.TransactionCategories.CategoryHideHandler))
 			apiV1Route.POST("/transaction/categories/move.json", bindApi(api.TransactionCategories.CategoryMoveHandler))
 			apiV1Route.POST("/transaction/categories/delete.json", bindApi(api.TransactionCategories.CategoryDeleteHandler))
+			apiV1Route.GET("/transaction/categories/trash/list.json", bindApi(api.TransactionCategories.CategoryTrashListHandler))
+			apiV1Route.POST("/transaction/categories/trash/restore.json", bindApi(api.TransactionCategories.CategoryRestoreHandler))
+			apiV1Route.POST("/transaction/categories/trash/purge.json", bindApi(api.TransactionCategories.CategoryPurgeHandler))
 
 			// Transaction Tag Groups
 			apiV1Route.GET("/transaction/tags/groups/list.json", bindApi(api.TransactionTagGroups.TagGroupListHandler))
@@ -436,15 +479,53 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/tags/hide.json", bindApi(api.TransactionTags.TagHideHandler))
 			apiV1Route.POST("/transaction/tags/move.json", bindApi(api.TransactionTags.TagMoveHandler))
 			apiV1Route.POST("/transaction/tags/delete.json", bindApi(api.TransactionTags.TagDeleteHandler))
+			apiV1Route.GET("/transaction/tags/trash/list.json", bindApi(api.TransactionTags.TagTrashListHandler))
+			apiV1Route.POST("/transaction/tags/trash/restore.json", bindApi(api.TransactionTags.TagRestoreHandler))
+			apiV1Route.POST("/transaction/tags/trash/purge.json", bindApi(api.TransactionTags.TagPurgeHandler))
 
 			// Transaction Templates
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
 			apiV1Route.GET("/transaction/templates/get.json", bindApi(api.TransactionTemplates.TemplateGetHandler))
+			apiV1Route.GET("/transaction/templates/scheduled_preview.json", bindApi(api.TransactionTemplates.TemplateScheduledPreviewHandler))
 			apiV1Route.POST("/transaction/templates/add.json", bindApi(api.TransactionTemplates.TemplateCreateHandler))
 			apiV1Route.POST("/transaction/templates/modify.json", bindApi(api.TransactionTemplates.TemplateModifyHandler))
 			apiV1Route.POST("/transaction/templates/hide.json", bindApi(api.TransactionTemplates.TemplateHideHandler))
 			apiV1Route.POST("/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler))
 			apiV1Route.POST("/transaction/templates/delete.json", bindApi(api.TransactionTemplates.TemplateDeleteHandler))
+			apiV1Route.POST("/transaction/templates/occurrences/skip.json", bindApi(api.TransactionTemplates.TemplateOccurrenceSkipHandler))
+			apiV1Route.POST("/transaction/templates/occurrences/postpone.json", bindApi(api.TransactionTemplates.TemplateOccurrencePostponeHandler))
+			apiV1Route.POST("/transaction/templates/occurrences/restore.json", bindApi(api.TransactionTemplates.TemplateOccurrenceRestoreHandler))
+
+			// Transaction Rules
+			apiV1Route.GET("/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler))
+			apiV1Route.GET("/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler))
+			apiV1Route.POST("/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler))
+			apiV1Route.POST("/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler))
+			apiV1Route.POST("/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler))
+			apiV1Route.POST("/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler))
+			apiV1Route.POST("/transaction/rules/apply.json", bindApi(api.TransactionRules.RuleApplyHandler))
+
+			// Payees
+			apiV1Route.GET("/payees/list.json", bindApi(api.Payees.PayeeListHandler))
+			apiV1Route.GET("/payees/get.json", bindApi(api.Payees.PayeeGetHandler))
+			apiV1Route.POST("/payees/add.json", bindApi(api.Payees.PayeeCreateHandler))
+			apiV1Route.POST("/payees/add_batch.json", bindApi(api.Payees.PayeeCreateBatchHandler))
+			apiV1Route.POST("/payees/modify.json", bindApi(api.Payees.PayeeModifyHandler))
+			apiV1Route.POST("/payees/hide.json", bindApi(api.Payees.PayeeHideHandler))
+			apiV1Route.POST("/payees/move.json", bindApi(api.Payees.PayeeMoveHandler))
+			apiV1Route.POST("/payees/delete.json", bindApi(api.Payees.PayeeDeleteHandler))
+
+			// Amortization Schedules
+			apiV1Route.GET("/amortization_schedules/list.json", bindApi(api.AmortizationSchedules.AmortizationScheduleListHandler))
+			apiV1Route.GET("/amortization_schedules/get.json", bindApi(api.AmortizationSchedules.AmortizationScheduleGetHandler))
+			apiV1Route.POST("/amortization_schedules/add.json", bindApi(api.AmortizationSchedules.AmortizationScheduleCreateHandler))
+			apiV1Route.POST("/amortization_schedules/delete.json", bindApi(api.AmortizationSchedules.AmortizationScheduleDeleteHandler))
+
+			// Investments
+			apiV1Route.GET("/investments/transactions/list.json", bindApi(api.Investments.InvestmentTransactionListHandler))
+			apiV1Route.POST("/investments/transactions/add.json", bindApi(api.Investments.InvestmentTransactionCreateHandler))
+			apiV1Route.POST("/investments/transactions/delete.json", bindApi(api.Investments.InvestmentTransactionDeleteHandler))
+			apiV1Route.GET("/investments/holdings/list.json", bindApi(api.Investments.InvestmentHoldingListHandler))
 
 			// Insights Explorers
 			apiV1Route.GET("/insights/explorers/list.json", bindApi(api.InsightsExplorers.InsightsExplorerListHandler))
@@ -455,6 +536,16 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/insights/explorers/move.json", bindApi(api.InsightsExplorers.InsightsExplorerMoveHandler))
 			apiV1Route.POST("/insights/explorers/delete.json", bindApi(api.InsightsExplorers.InsightsExplorerDeleteHandler))
 
+			// Budgets
+			apiV1Route.GET("/budgets/list.json", bindApi(api.Budgets.BudgetListHandler))
+			apiV1Route.GET("/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler))
+			apiV1Route.GET("/budgets/usages.json", bindApi(api.Budgets.BudgetUsageListHandler))
+			apiV1Route.POST("/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler))
+			apiV1Route.POST("/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler))
+			apiV1Route.POST("/budgets/hide.json", bindApi(api.Budgets.BudgetHideHandler))
+			apiV1Route.POST("/budgets/move.json", bindApi(api.Budgets.BudgetMoveHandler))
+			apiV1Route.POST("/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler))
+
 			// Large Language Models
 			if config.ReceiptImageRecognitionLLMConfig != nil && config.ReceiptImageRecognitionLLMConfig.LLMProvider != "" {
 				if config.TransactionFromAIImageRecognition {
@@ -470,9 +561,16 @@ func startWebServer(c *core.CliContext) error {
 
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/at_date.json", bindApi(api.ExchangeRates.ExchangeRatesAtDateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler))
 
+			// Security Prices
+			apiV1Route.GET("/security_prices/latest.json", bindApi(api.SecurityPrices.LatestSecurityPriceHandler))
+			apiV1Route.GET("/security_prices/user_custom/list.json", bindApi(api.SecurityPrices.UserCustomSecurityPriceListHandler))
+			apiV1Route.POST("/security_prices/user_custom/update.json", bindApi(api.SecurityPrices.UserCustomSecurityPriceUpdateHandler))
+			apiV1Route.POST("/security_prices/user_custom/delete.json", bindApi(api.SecurityPrices.UserCustomSecurityPriceDeleteHandler))
+
 			// System
 			apiV1Route.GET("/systems/version.json", bindApi(api.Systems.VersionHandler))
 		}
@@ -639,6 +737,97 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindOfx(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/x-ofx; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindQif(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/qif; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindBeancount(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "text/plain; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindLedger(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "text/plain; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindGnuCash(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/gzip", fileName, result)
+		}
+	}
+}
+
+func bindXlsx(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", fileName, result)
+		}
+	}
+}
+
+func bindZip(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/zip", fileName, result)
+		}
+	}
+}
+
 func bindImage(fn core.ImageHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
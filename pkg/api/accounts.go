@@ -47,8 +47,9 @@ func (a *AccountsApi) AccountListHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountListHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -107,8 +108,9 @@ func (a *AccountsApi) AccountGetHandler(c *core.WebContext) (any, *errs.Error) {
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, ledgerId, accountGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountGetHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", accountGetReq.Id, uid, err.Error())
@@ -231,16 +233,17 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrAccountTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
-	maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, accountCreateReq.Category)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, ledgerId, accountCreateReq.Category)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	mainAccount := a.createNewAccountModel(uid, &accountCreateReq, false, maxOrderId+1)
-	childrenAccounts, childrenAccountBalanceTimes := a.createSubAccountModels(uid, &accountCreateReq)
+	mainAccount := a.createNewAccountModel(uid, ledgerId, &accountCreateReq, false, maxOrderId+1)
+	childrenAccounts, childrenAccountBalanceTimes := a.createSubAccountModels(uid, ledgerId, &accountCreateReq)
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && accountCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT, uid, accountCreateReq.ClientSessionId)
@@ -250,7 +253,7 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 			accountId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountId)
+				accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, ledgerId, accountId)
 
 				if err != nil {
 					log.Errorf(c, "[accounts.AccountCreateHandler] failed to get existed account \"id:%d\" for user \"uid:%d\", because %s", accountId, uid, err.Error())
@@ -332,8 +335,9 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.ErrCannotSetStatementDateForNonCreditCard
 	}
 
-	uid := c.GetCurrentUid()
-	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, ledgerId, accountModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountModifyHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", accountModifyReq.Id, uid, err.Error())
@@ -434,11 +438,11 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 	var toAddAccountBalanceTimes []int64
 	var toDeleteAccountIds []int64
 
-	toUpdateAccount := a.getToUpdateAccount(uid, &accountModifyReq, mainAccount, false)
+	toUpdateAccount := a.getToUpdateAccount(uid, ledgerId, &accountModifyReq, mainAccount, false)
 
 	if toUpdateAccount != nil {
 		if toUpdateAccount.Category != mainAccount.Category {
-			maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, toUpdateAccount.Category)
+			maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, uid, ledgerId, toUpdateAccount.Category)
 
 			if err != nil {
 				log.Errorf(c, "[accounts.AccountModifyHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -474,7 +478,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		if _, exists := accountMap[subAccountReq.Id]; !exists {
 			anythingUpdate = true
 			maxOrderId = maxOrderId + 1
-			newSubAccount := a.createNewSubAccountModelForModify(uid, mainAccount.Type, subAccountReq, maxOrderId)
+			newSubAccount := a.createNewSubAccountModelForModify(uid, ledgerId, mainAccount.Type, subAccountReq, maxOrderId)
 			toAddAccounts = append(toAddAccounts, newSubAccount)
 
 			if subAccountReq.BalanceTime != nil {
@@ -483,7 +487,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 				toAddAccountBalanceTimes = append(toAddAccountBalanceTimes, 0)
 			}
 		} else {
-			toUpdateSubAccount := a.getToUpdateAccount(uid, subAccountReq, accountMap[subAccountReq.Id], true)
+			toUpdateSubAccount := a.getToUpdateAccount(uid, ledgerId, subAccountReq, accountMap[subAccountReq.Id], true)
 
 			if toUpdateSubAccount != nil {
 				anythingUpdate = true
@@ -504,7 +508,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 			accountId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, accountId)
+				accountAndSubAccounts, err := a.accounts.GetAccountAndSubAccountsByAccountId(c, uid, ledgerId, accountId)
 
 				if err != nil {
 					log.Errorf(c, "[accounts.AccountModifyHandler] failed to get existed account \"id:%d\" for user \"uid:%d\", because %s", accountId, uid, err.Error())
@@ -617,8 +621,9 @@ func (a *AccountsApi) AccountHideHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.accounts.HideAccount(c, uid, []int64{accountHideReq.Id}, accountHideReq.Hidden)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.accounts.HideAccount(c, uid, ledgerId, []int64{accountHideReq.Id}, accountHideReq.Hidden)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountHideHandler] failed to hide account \"id:%d\" for user \"uid:%d\", because %s", accountHideReq.Id, uid, err.Error())
@@ -639,13 +644,15 @@ func (a *AccountsApi) AccountMoveHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	accounts := make([]*models.Account, len(accountMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(accountMoveReq.NewDisplayOrders); i++ {
 		newDisplayOrder := accountMoveReq.NewDisplayOrders[i]
 		account := &models.Account{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			AccountId:    newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -653,7 +660,7 @@ func (a *AccountsApi) AccountMoveHandler(c *core.WebContext) (any, *errs.Error)
 		accounts[i] = account
 	}
 
-	err = a.accounts.ModifyAccountDisplayOrders(c, uid, accounts)
+	err = a.accounts.ModifyAccountDisplayOrders(c, uid, ledgerId, accounts)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountMoveHandler] failed to move accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -674,8 +681,9 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.accounts.DeleteAccount(c, uid, accountDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.accounts.DeleteAccount(c, uid, ledgerId, accountDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.AccountDeleteHandler] failed to delete account \"id:%d\" for user \"uid:%d\", because %s", accountDeleteReq.Id, uid, err.Error())
@@ -696,8 +704,9 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.accounts.DeleteSubAccount(c, uid, accountDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.accounts.DeleteSubAccount(c, uid, ledgerId, accountDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[accounts.SubAccountDeleteHandler] failed to delete sub-account \"id:%d\" for user \"uid:%d\", because %s", accountDeleteReq.Id, uid, err.Error())
@@ -708,7 +717,73 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
-func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
+// AccountTrashListHandler returns deleted account list in trash bin of current user
+func (a *AccountsApi) AccountTrashListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accounts, err := a.accounts.GetAllDeletedAccountsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountTrashListHandler] failed to get deleted accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountResps := make([]*models.DeletedAccountInfoResponse, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountResps[i] = accounts[i].ToDeletedAccountInfoResponse()
+	}
+
+	return accountResps, nil
+}
+
+// AccountRestoreHandler restores a deleted account from trash bin by request parameters for current user
+func (a *AccountsApi) AccountRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var accountRestoreReq models.AccountRestoreRequest
+	err := c.ShouldBindJSON(&accountRestoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.accounts.RestoreAccount(c, uid, ledgerId, accountRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountRestoreHandler] failed to restore account \"id:%d\" for user \"uid:%d\", because %s", accountRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[accounts.AccountRestoreHandler] user \"uid:%d\" has restored account \"id:%d\"", uid, accountRestoreReq.Id)
+	return true, nil
+}
+
+// AccountPurgeHandler permanently deletes a deleted account from trash bin by request parameters for current user
+func (a *AccountsApi) AccountPurgeHandler(c *core.WebContext) (any, *errs.Error) {
+	var accountPurgeReq models.AccountPurgeRequest
+	err := c.ShouldBindJSON(&accountPurgeReq)
+
+	if err != nil {
+		log.Warnf(c, "[accounts.AccountPurgeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.accounts.PurgeAccount(c, uid, ledgerId, accountPurgeReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[accounts.AccountPurgeHandler] failed to purge account \"id:%d\" for user \"uid:%d\", because %s", accountPurgeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[accounts.AccountPurgeHandler] user \"uid:%d\" has purged account \"id:%d\"", uid, accountPurgeReq.Id)
+	return true, nil
+}
+
+func (a *AccountsApi) createNewAccountModel(uid int64, ledgerId int64, accountCreateReq *models.AccountCreateRequest, isSubAccount bool, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
 	if !isSubAccount && accountCreateReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
@@ -717,6 +792,7 @@ func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.
 
 	return &models.Account{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         accountCreateReq.Name,
 		DisplayOrder: order,
 		Category:     accountCreateReq.Category,
@@ -730,11 +806,12 @@ func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.
 	}
 }
 
-func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, accountType models.AccountType, accountModifyReq *models.AccountModifyRequest, order int32) *models.Account {
+func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, ledgerId int64, accountType models.AccountType, accountModifyReq *models.AccountModifyRequest, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
 	return &models.Account{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         accountModifyReq.Name,
 		DisplayOrder: order,
 		Category:     accountModifyReq.Category,
@@ -748,7 +825,7 @@ func (a *AccountsApi) createNewSubAccountModelForModify(uid int64, accountType m
 	}
 }
 
-func (a *AccountsApi) createSubAccountModels(uid int64, accountCreateReq *models.AccountCreateRequest) ([]*models.Account, []int64) {
+func (a *AccountsApi) createSubAccountModels(uid int64, ledgerId int64, accountCreateReq *models.AccountCreateRequest) ([]*models.Account, []int64) {
 	if len(accountCreateReq.SubAccounts) <= 0 {
 		return nil, nil
 	}
@@ -757,14 +834,14 @@ func (a *AccountsApi) createSubAccountModels(uid int64, accountCreateReq *models
 	childrenAccountBalanceTimes := make([]int64, len(accountCreateReq.SubAccounts))
 
 	for i := int32(0); i < int32(len(accountCreateReq.SubAccounts)); i++ {
-		childrenAccounts[i] = a.createNewAccountModel(uid, accountCreateReq.SubAccounts[i], true, i+1)
+		childrenAccounts[i] = a.createNewAccountModel(uid, ledgerId, accountCreateReq.SubAccounts[i], true, i+1)
 		childrenAccountBalanceTimes[i] = accountCreateReq.SubAccounts[i].BalanceTime
 	}
 
 	return childrenAccounts, childrenAccountBalanceTimes
 }
 
-func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.AccountModifyRequest, oldAccount *models.Account, isSubAccount bool) *models.Account {
+func (a *AccountsApi) getToUpdateAccount(uid int64, ledgerId int64, accountModifyReq *models.AccountModifyRequest, oldAccount *models.Account, isSubAccount bool) *models.Account {
 	newAccountExtend := &models.AccountExtend{}
 
 	if !isSubAccount && accountModifyReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
@@ -774,6 +851,7 @@ func (a *AccountsApi) getToUpdateAccount(uid int64, accountModifyReq *models.Acc
 	newAccount := &models.Account{
 		AccountId:    oldAccount.AccountId,
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         accountModifyReq.Name,
 		DisplayOrder: oldAccount.DisplayOrder,
 		Category:     accountModifyReq.Category,
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// AmortizationSchedulesApi represents amortization schedule api
+type AmortizationSchedulesApi struct {
+	ApiUsingConfig
+	schedules *services.AmortizationScheduleService
+}
+
+// Initialize an amortization schedule api singleton instance
+var (
+	AmortizationSchedules = &AmortizationSchedulesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		schedules: services.AmortizationSchedules,
+	}
+)
+
+// AmortizationScheduleListHandler returns amortization schedule list with remaining principal and total interest paid of current user
+func (a *AmortizationSchedulesApi) AmortizationScheduleListHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleListReq models.AmortizationScheduleListRequest
+	err := c.ShouldBindQuery(&scheduleListReq)
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	schedules, err := a.schedules.GetAllSchedulesByUid(c, uid, ledgerId, scheduleListReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleListHandler] failed to get amortization schedules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now().Unix()
+	scheduleResps := make(models.AmortizationScheduleInfoResponseSlice, 0, len(schedules))
+
+	for i := 0; i < len(schedules); i++ {
+		installments, err := schedules[i].GetInstallments()
+
+		if err != nil {
+			log.Warnf(c, "[amortization_schedules.AmortizationScheduleListHandler] cannot calculate installments of amortization schedule \"id:%d\" for user \"uid:%d\", because %s", schedules[i].ScheduleId, uid, err.Error())
+			continue
+		}
+
+		scheduleResps = append(scheduleResps, schedules[i].ToAmortizationScheduleInfoResponse(installments, schedules[i].GetPaidInstallmentCount(now), false))
+	}
+
+	sort.Sort(scheduleResps)
+
+	return scheduleResps, nil
+}
+
+// AmortizationScheduleGetHandler returns one specific amortization schedule with all installments of current user
+func (a *AmortizationSchedulesApi) AmortizationScheduleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleGetReq models.AmortizationScheduleGetRequest
+	err := c.ShouldBindQuery(&scheduleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	schedule, err := a.schedules.GetScheduleByScheduleId(c, uid, ledgerId, scheduleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleGetHandler] failed to get amortization schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	installments, err := schedule.GetInstallments()
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleGetHandler] failed to calculate installments of amortization schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return schedule.ToAmortizationScheduleInfoResponse(installments, schedule.GetPaidInstallmentCount(time.Now().Unix()), true), nil
+}
+
+// AmortizationScheduleCreateHandler saves a new amortization schedule and its scheduled transaction templates by request parameters for current user
+func (a *AmortizationSchedulesApi) AmortizationScheduleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleCreateReq models.AmortizationScheduleCreateRequest
+	err := c.ShouldBindJSON(&scheduleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	if !scheduleCreateReq.Method.IsValid() {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] amortization method invalid, method is %d", scheduleCreateReq.Method)
+		return nil, errs.ErrAmortizationMethodInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	schedule, err := a.createNewScheduleModel(uid, ledgerId, &scheduleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] failed to create new amortization schedule for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	annualInterestRate, err := schedule.GetAnnualInterestRate()
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] annual interest rate \"%s\" invalid", scheduleCreateReq.AnnualInterestRate)
+		return nil, errs.Or(err, errs.ErrAmortizationAnnualInterestRateInvalid)
+	}
+
+	if annualInterestRate > 0 && schedule.InterestCategoryId <= 0 {
+		return nil, errs.ErrAmortizationInterestCategoryRequired
+	} else if annualInterestRate == 0 {
+		schedule.InterestCategoryId = 0
+	}
+
+	err = a.schedules.CreateSchedule(c, schedule)
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] failed to create amortization schedule \"id:%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[amortization_schedules.AmortizationScheduleCreateHandler] user \"uid:%d\" has created a new amortization schedule \"id:%d\" successfully", uid, schedule.ScheduleId)
+
+	installments, err := schedule.GetInstallments()
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleCreateHandler] failed to calculate installments of amortization schedule \"id:%d\" for user \"uid:%d\", because %s", schedule.ScheduleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return schedule.ToAmortizationScheduleInfoResponse(installments, schedule.GetPaidInstallmentCount(time.Now().Unix()), true), nil
+}
+
+// AmortizationScheduleDeleteHandler deletes an existed amortization schedule and its scheduled transaction templates by request parameters for current user
+func (a *AmortizationSchedulesApi) AmortizationScheduleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var scheduleDeleteReq models.AmortizationScheduleDeleteRequest
+	err := c.ShouldBindJSON(&scheduleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[amortization_schedules.AmortizationScheduleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.schedules.DeleteSchedule(c, uid, ledgerId, scheduleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[amortization_schedules.AmortizationScheduleDeleteHandler] failed to delete amortization schedule \"id:%d\" for user \"uid:%d\", because %s", scheduleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[amortization_schedules.AmortizationScheduleDeleteHandler] user \"uid:%d\" has deleted amortization schedule \"id:%d\"", uid, scheduleDeleteReq.Id)
+	return true, nil
+}
+
+func (a *AmortizationSchedulesApi) createNewScheduleModel(uid int64, ledgerId int64, scheduleCreateReq *models.AmortizationScheduleCreateRequest) (*models.AmortizationSchedule, error) {
+	firstPaymentTime, err := utils.ParseFromLongDateFirstTime(scheduleCreateReq.FirstPaymentDate, scheduleCreateReq.UtcOffset)
+
+	if err != nil || firstPaymentTime.Day() > models.MaximumPaymentDayOfAmortizationSchedule {
+		return nil, errs.ErrAmortizationFirstPaymentDateInvalid
+	}
+
+	return &models.AmortizationSchedule{
+		Uid:                   uid,
+		LedgerId:              ledgerId,
+		AccountId:             scheduleCreateReq.AccountId,
+		Name:                  scheduleCreateReq.Name,
+		Method:                scheduleCreateReq.Method,
+		Principal:             scheduleCreateReq.Principal,
+		AnnualInterestRate:    scheduleCreateReq.AnnualInterestRate,
+		InstallmentCount:      scheduleCreateReq.InstallmentCount,
+		FirstPaymentYearMonth: int32(firstPaymentTime.Year())*100 + int32(firstPaymentTime.Month()),
+		PaymentDay:            int32(firstPaymentTime.Day()),
+		TimezoneUtcOffset:     scheduleCreateReq.UtcOffset,
+		PaymentAccountId:      scheduleCreateReq.PaymentAccountId,
+		PrincipalCategoryId:   scheduleCreateReq.PrincipalCategoryId,
+		InterestCategoryId:    scheduleCreateReq.InterestCategoryId,
+		Comment:               scheduleCreateReq.Comment,
+	}, nil
+}
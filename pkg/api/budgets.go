@@ -0,0 +1,491 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	ApiUsingConfig
+	budgets               *services.BudgetService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+	accounts              *services.AccountService
+	users                 *services.UserService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		budgets:               services.Budgets,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+		accounts:              services.Accounts,
+		users:                 services.Users,
+	}
+)
+
+// BudgetListHandler returns budget list of current user
+func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetListReq models.BudgetListRequest
+	err := c.ShouldBindQuery(&budgetListReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid, ledgerId, budgetListReq.VisibleOnly)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make(models.BudgetInfoResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	sort.Sort(budgetResps)
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current user
+func (a *BudgetsApi) BudgetGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, ledgerId, budgetGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetUsageListHandler returns the planned, rollover and actual amounts of all budgets of current user in the specified period
+func (a *BudgetsApi) BudgetUsageListHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetUsageListReq models.BudgetUsageListRequest
+	err := c.ShouldBindQuery(&budgetUsageListReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetUsageListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetUsageListHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	yearMonth, err := a.parseBudgetYearMonth(budgetUsageListReq.YearMonth)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetUsageListHandler] cannot parse year month \"%s\"", budgetUsageListReq.YearMonth)
+		return nil, errs.ErrBudgetYearMonthInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[budgets.BudgetUsageListHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid, ledgerId, false)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetUsageListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetUsageResps := make([]*models.BudgetUsageResponse, 0, len(budgets))
+
+	if len(budgets) < 1 {
+		return budgetUsageResps, nil
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, ledgerId, models.CATEGORY_TYPE_EXPENSE, -1)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetUsageListHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetUsageListHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	startYearMonth, endYearMonth := a.budgets.GetBudgetUsagesYearMonthRange(budgets, yearMonth, user.FiscalYearStart)
+	currencyMonthlyTotalAmounts, err := a.getBudgetCurrencyMonthlyTotalAmounts(c, uid, ledgerId, budgets, accounts, startYearMonth, endYearMonth, clientTimezone, budgetUsageListReq.UseTransactionTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetUsageListHandler] failed to get accounts and categories monthly inflow and outflow for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetUsages := a.budgets.GetBudgetUsages(budgets, yearMonth, user.FiscalYearStart, currencyMonthlyTotalAmounts, a.transactionCategories.GetCategoryMapByList(categories), a.accounts.GetAccountMapByList(accounts))
+
+	for i := 0; i < len(budgetUsages); i++ {
+		budgetUsageResps = append(budgetUsageResps, budgetUsages[i].ToBudgetUsageResponse())
+	}
+
+	return budgetUsageResps, nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current user
+func (a *BudgetsApi) BudgetCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetCreateReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget period type invalid, type is %d", budgetCreateReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	startYearMonth, err := a.parseBudgetYearMonth(budgetCreateReq.StartYearMonth)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] cannot parse start year month \"%s\"", budgetCreateReq.StartYearMonth)
+		return nil, errs.ErrBudgetYearMonthInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.checkBudgetCategoryAndAccount(c, uid, ledgerId, budgetCreateReq.CategoryId, budgetCreateReq.AccountId, budgetCreateReq.Currency)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget category or account of user \"uid:%d\" is invalid, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.budgets.GetMaxDisplayOrder(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budget := &models.Budget{
+		Uid:            uid,
+		LedgerId:       ledgerId,
+		Name:           budgetCreateReq.Name,
+		PeriodType:     budgetCreateReq.PeriodType,
+		CategoryId:     budgetCreateReq.CategoryId,
+		AccountId:      budgetCreateReq.AccountId,
+		Currency:       budgetCreateReq.Currency,
+		Amount:         budgetCreateReq.Amount,
+		Rollover:       budgetCreateReq.Rollover,
+		StartYearMonth: startYearMonth,
+		DisplayOrder:   maxOrderId + 1,
+		Comment:        budgetCreateReq.Comment,
+	}
+
+	err = a.budgets.CreateBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to create budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetModifyReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget period type invalid, type is %d", budgetModifyReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	startYearMonth, err := a.parseBudgetYearMonth(budgetModifyReq.StartYearMonth)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] cannot parse start year month \"%s\"", budgetModifyReq.StartYearMonth)
+		return nil, errs.ErrBudgetYearMonthInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, ledgerId, budgetModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newBudget := &models.Budget{
+		BudgetId:       budget.BudgetId,
+		Uid:            uid,
+		LedgerId:       ledgerId,
+		Name:           budgetModifyReq.Name,
+		PeriodType:     budgetModifyReq.PeriodType,
+		CategoryId:     budgetModifyReq.CategoryId,
+		AccountId:      budgetModifyReq.AccountId,
+		Currency:       budgetModifyReq.Currency,
+		Amount:         budgetModifyReq.Amount,
+		Rollover:       budgetModifyReq.Rollover,
+		StartYearMonth: startYearMonth,
+		Comment:        budgetModifyReq.Comment,
+	}
+
+	if newBudget.Name == budget.Name &&
+		newBudget.PeriodType == budget.PeriodType &&
+		newBudget.CategoryId == budget.CategoryId &&
+		newBudget.AccountId == budget.AccountId &&
+		newBudget.Currency == budget.Currency &&
+		newBudget.Amount == budget.Amount &&
+		newBudget.Rollover == budget.Rollover &&
+		newBudget.StartYearMonth == budget.StartYearMonth &&
+		newBudget.Comment == budget.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkBudgetCategoryAndAccount(c, uid, ledgerId, newBudget.CategoryId, newBudget.AccountId, newBudget.Currency)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget category or account of user \"uid:%d\" is invalid, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.budgets.ModifyBudget(c, newBudget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	newBudget.DisplayOrder = budget.DisplayOrder
+	newBudget.Hidden = budget.Hidden
+
+	return newBudget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetHideHandler hides a budget by request parameters for current user
+func (a *BudgetsApi) BudgetHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetHideReq models.BudgetHideRequest
+	err := c.ShouldBindJSON(&budgetHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.budgets.HideBudget(c, uid, ledgerId, []int64{budgetHideReq.Id}, budgetHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetHideHandler] failed to hide budget \"id:%d\" for user \"uid:%d\", because %s", budgetHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetHideHandler] user \"uid:%d\" has hidden budget \"id:%d\"", uid, budgetHideReq.Id)
+	return true, nil
+}
+
+// BudgetMoveHandler moves display order of existed budgets by request parameters for current user
+func (a *BudgetsApi) BudgetMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetMoveReq models.BudgetMoveRequest
+	err := c.ShouldBindJSON(&budgetMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	budgets := make([]*models.Budget, len(budgetMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(budgetMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := budgetMoveReq.NewDisplayOrders[i]
+		budget := &models.Budget{
+			Uid:          uid,
+			LedgerId:     ledgerId,
+			BudgetId:     newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		budgets[i] = budget
+	}
+
+	err = a.budgets.ModifyBudgetDisplayOrders(c, uid, ledgerId, budgets)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetMoveHandler] failed to move budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetMoveHandler] user \"uid:%d\" has moved budgets", uid)
+	return true, nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.budgets.DeleteBudget(c, uid, ledgerId, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+func (a *BudgetsApi) parseBudgetYearMonth(yearMonth string) (int32, error) {
+	year, month, err := utils.ParseNumericYearMonth(yearMonth)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if year < 1 || month < 1 || month > 12 {
+		return 0, errs.ErrBudgetYearMonthInvalid
+	}
+
+	return year*100 + month, nil
+}
+
+func (a *BudgetsApi) checkBudgetCategoryAndAccount(c *core.WebContext, uid int64, ledgerId int64, categoryId int64, accountId int64, currency string) error {
+	if categoryId > 0 {
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, ledgerId, categoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.Type != models.CATEGORY_TYPE_EXPENSE {
+			return errs.ErrBudgetCategoryTypeInvalid
+		}
+	}
+
+	if accountId > 0 {
+		account, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, accountId)
+
+		if err != nil {
+			return err
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_SINGLE_ACCOUNT && account.Currency != currency {
+			return errs.ErrBudgetCurrencyNotMatchAccount
+		}
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) getBudgetCurrencyMonthlyTotalAmounts(c *core.WebContext, uid int64, ledgerId int64, budgets []*models.Budget, accounts []*models.Account, startYearMonth int32, endYearMonth int32, clientTimezone *time.Location, useTransactionTimezone bool) (map[string]map[int32][]*models.Transaction, error) {
+	currencyMonthlyTotalAmounts := make(map[string]map[int32][]*models.Transaction)
+
+	for i := 0; i < len(budgets); i++ {
+		currency := budgets[i].Currency
+
+		if _, exists := currencyMonthlyTotalAmounts[currency]; exists {
+			continue
+		}
+
+		var amountConverter models.TransactionAmountConverter
+
+		// the amounts of the accounts in other currencies should be converted into the budget currency
+		if a.hasAccountsInOtherCurrency(accounts, currency) {
+			startTime, endTime, err := getUnixTimeRangeByYearMonthRange(startYearMonth/100, startYearMonth%100, endYearMonth/100, endYearMonth%100)
+
+			if err != nil {
+				return nil, err
+			}
+
+			amountConverter, _, err = exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), currency, true, startTime, endTime)
+
+			if err != nil {
+				log.Errorf(c, "[budgets.getBudgetCurrencyMonthlyTotalAmounts] failed to get exchange rates of currency \"%s\" for user \"uid:%d\", because %s", currency, uid, err.Error())
+				return nil, err
+			}
+		}
+
+		monthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, ledgerId, startYearMonth/100, startYearMonth%100, endYearMonth/100, endYearMonth%100, nil, false, "", clientTimezone, useTransactionTimezone, amountConverter)
+
+		if err != nil {
+			return nil, err
+		}
+
+		currencyMonthlyTotalAmounts[currency] = monthlyTotalAmounts
+	}
+
+	return currencyMonthlyTotalAmounts, nil
+}
+
+func (a *BudgetsApi) hasAccountsInOtherCurrency(accounts []*models.Account, currency string) bool {
+	for i := 0; i < len(accounts); i++ {
+		if accounts[i].Type != models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS && accounts[i].Currency != currency {
+			return true
+		}
+	}
+
+	return false
+}
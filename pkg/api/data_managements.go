@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/converters"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
@@ -28,11 +30,19 @@ type DataManagementsApi struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	splits                  *services.TransactionSplitService
 	tagGroups               *services.TransactionTagGroupService
 	pictures                *services.TransactionPictureService
 	templates               *services.TransactionTemplateService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
 	insightsExploreres      *services.InsightsExplorerService
+	budgets                 *services.BudgetService
+	rules                   *services.TransactionRuleService
+	payees                  *services.PayeeService
+	amortizationSchedules   *services.AmortizationScheduleService
+	investmentTransactions  *services.InvestmentTransactionService
+	securityPrices          *services.SecurityPriceService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a data management api singleton instance
@@ -47,11 +57,19 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		splits:                  services.TransactionSplits,
 		tagGroups:               services.TransactionTagGroups,
 		pictures:                services.TransactionPictures,
 		templates:               services.TransactionTemplates,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
 		insightsExploreres:      services.InsightsExplorers,
+		budgets:                 services.Budgets,
+		rules:                   services.TransactionRules,
+		payees:                  services.Payees,
+		amortizationSchedules:   services.AmortizationSchedules,
+		investmentTransactions:  services.InvestmentTransactions,
+		securityPrices:          services.SecurityPrices,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
@@ -65,31 +83,62 @@ func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebCont
 	return a.getExportedFileContent(c, "tsv")
 }
 
+// ExportDataToOFXHandler returns exported data in open financial exchange (ofx) format
+func (a *DataManagementsApi) ExportDataToOFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ofx")
+}
+
+// ExportDataToQIFHandler returns exported data in quicken interchange format (qif)
+func (a *DataManagementsApi) ExportDataToQIFHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qif")
+}
+
+// ExportDataToBeancountHandler returns exported data in beancount format
+func (a *DataManagementsApi) ExportDataToBeancountHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "beancount")
+}
+
+// ExportDataToLedgerHandler returns exported data in ledger journal format
+func (a *DataManagementsApi) ExportDataToLedgerHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ledger")
+}
+
+// ExportDataToGnuCashHandler returns exported data in gzip compressed gnucash xml format
+func (a *DataManagementsApi) ExportDataToGnuCashHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "gnucash")
+}
+
+// ExportDataToXlsxHandler returns exported data in excel (Office Open XML) format
+func (a *DataManagementsApi) ExportDataToXlsxHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "xlsx")
+}
+
 // DataStatisticsHandler returns user data statistics
 func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total account count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalTransactionCategoryCount, err := a.categories.GetTotalCategoryCountByUid(c, uid)
+	totalTransactionCategoryCount, err := a.categories.GetTotalCategoryCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction category count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalTransactionTagCount, err := a.tags.GetTotalTagCountByUid(c, uid)
+	totalTransactionTagCount, err := a.tags.GetTotalTagCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction tag count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalTransactionCount, err := a.transactions.GetTotalTransactionCountByUid(c, uid)
+	totalTransactionCount, err := a.transactions.GetTotalTransactionCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -103,21 +152,28 @@ func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalInsightsExplorerCount, err := a.insightsExploreres.GetTotalInsightsExplorersCountByUid(c, uid)
+	totalInsightsExplorerCount, err := a.insightsExploreres.GetTotalInsightsExplorersCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total insights explorer count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalTransactionTemplateCount, err := a.templates.GetTotalNormalTemplateCountByUid(c, uid)
+	totalBudgetCount, err := a.budgets.GetTotalBudgetCountByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total budget count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionTemplateCount, err := a.templates.GetTotalNormalTemplateCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction template count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalScheduledTransactionCount, err := a.templates.GetTotalScheduledTemplateCountByUid(c, uid)
+	totalScheduledTransactionCount, err := a.templates.GetTotalScheduledTemplateCountByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total scheduled transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -131,6 +187,7 @@ func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *er
 		TotalTransactionCount:          totalTransactionCount,
 		TotalTransactionPictureCount:   totalTransactionPictureCount,
 		TotalInsightsExplorerCount:     totalInsightsExplorerCount,
+		TotalBudgetCount:               totalBudgetCount,
 		TotalTransactionTemplateCount:  totalTransactionTemplateCount,
 		TotalScheduledTransactionCount: totalScheduledTransactionCount,
 	}
@@ -138,6 +195,118 @@ func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *er
 	return dataStatisticsResp, nil
 }
 
+// DataBackupHandler returns the backup archive which contains all the data of current user
+func (a *DataManagementsApi) DataBackupHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	if !a.CurrentConfig().EnableDataExport {
+		return nil, "", errs.ErrDataExportNotAllowed
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.DataBackupHandler] cannot get client timezone, because %s", err.Error())
+		clientTimezone = time.Local
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.DataBackupHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, "", errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
+		return nil, "", errs.ErrNotPermittedToPerformThisAction
+	}
+
+	result, err := a.userDataBackups.CreateBackupArchive(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataBackupHandler] failed to create backup archive for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[data_managements.DataBackupHandler] user \"uid:%d\" has created backup archive", uid)
+
+	return result, a.getFileName(user, clientTimezone, "zip"), nil
+}
+
+// DataRestoreHandler restores all the data in the uploaded backup archive to current user
+func (a *DataManagementsApi) DataRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableDataImport {
+		return nil, errs.ErrDataImportNotAllowed
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.DataRestoreHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataRestoreHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	backupFiles := form.File["file"]
+
+	if len(backupFiles) < 1 {
+		log.Warnf(c, "[data_managements.DataRestoreHandler] there is no backup file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if backupFiles[0].Size < 1 {
+		log.Warnf(c, "[data_managements.DataRestoreHandler] the size of backup file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if backupFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[data_managements.DataRestoreHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", backupFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	backupFile, err := backupFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataRestoreHandler] failed to get backup file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer backupFile.Close()
+	fileData, err := io.ReadAll(backupFile)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataRestoreHandler] failed to read backup file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.userDataBackups.RestoreBackupArchive(c, user, fileData)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.DataRestoreHandler] failed to restore backup archive for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[data_managements.DataRestoreHandler] user \"uid:%d\" has restored backup archive", uid)
+
+	return true, nil
+}
+
 // ClearAllDataHandler deletes all user data
 func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs.Error) {
 	var clearDataReq models.ClearDataRequest
@@ -148,7 +317,8 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -167,52 +337,98 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	err = a.templates.DeleteAllTemplates(c, uid)
+	// delete all transactions first, so that nothing is deleted if there are transactions before transaction lock date
+	err = a.transactions.DeleteAllTransactions(c, uid, ledgerId, true)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction templates, because %s", err.Error())
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transactions, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.transactions.DeleteAllTransactions(c, uid, true)
+	err = a.templates.DeleteAllTemplates(c, uid, ledgerId)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transactions, because %s", err.Error())
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction templates, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.categories.DeleteAllCategories(c, uid)
+	err = a.categories.DeleteAllCategories(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction categories, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.tags.DeleteAllTags(c, uid)
+	err = a.tags.DeleteAllTags(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction tags, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.tagGroups.DeleteAllTagGroups(c, uid)
+	err = a.tagGroups.DeleteAllTagGroups(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction tag groups, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.userCustomExchangeRates.DeleteAllCustomExchangeRates(c, uid)
+	// user custom exchange rates and security prices are not owned by any ledger, so only clear them along with the default ledger
+	if ledgerId == models.DefaultLedgerId {
+		err = a.userCustomExchangeRates.DeleteAllCustomExchangeRates(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all user custom exchange rates, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		err = a.securityPrices.DeleteAllPrices(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all user custom security prices, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	err = a.insightsExploreres.DeleteAllInsightsExplorers(c, uid, ledgerId)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all user custom exchange rates, because %s", err.Error())
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all insights explorers, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	err = a.insightsExploreres.DeleteAllInsightsExplorers(c, uid)
+	err = a.budgets.DeleteAllBudgets(c, uid, ledgerId)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all insights explorers, because %s", err.Error())
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all budgets, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.DeleteAllRules(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction rules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.payees.DeleteAllPayees(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all payees, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.amortizationSchedules.DeleteAllSchedules(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all amortization schedules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.investmentTransactions.DeleteAllTransactions(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all investment transactions, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
@@ -230,7 +446,8 @@ func (a *DataManagementsApi) ClearAllTransactionsHandler(c *core.WebContext) (an
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -249,7 +466,7 @@ func (a *DataManagementsApi) ClearAllTransactionsHandler(c *core.WebContext) (an
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	err = a.transactions.DeleteAllTransactions(c, uid, false)
+	err = a.transactions.DeleteAllTransactions(c, uid, ledgerId, false)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllTransactionsHandler] failed to delete all transactions, because %s", err.Error())
@@ -270,7 +487,8 @@ func (a *DataManagementsApi) ClearAllTransactionsByAccountHandler(c *core.WebCon
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -289,7 +507,7 @@ func (a *DataManagementsApi) ClearAllTransactionsByAccountHandler(c *core.WebCon
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	account, err := a.accounts.GetAccountByAccountId(c, uid, clearDataReq.AccountId)
+	account, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, clearDataReq.AccountId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllTransactionsByAccountHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", uid, clearDataReq.AccountId, err.Error())
@@ -304,7 +522,7 @@ func (a *DataManagementsApi) ClearAllTransactionsByAccountHandler(c *core.WebCon
 		return nil, errs.ErrCannotDeleteTransactionInParentAccount
 	}
 
-	err = a.transactions.DeleteAllTransactionsOfAccount(c, uid, account.AccountId, pageCountForClearTransactions)
+	err = a.transactions.DeleteAllTransactionsOfAccount(c, uid, ledgerId, account.AccountId, pageCountForClearTransactions, getTransactionHistoryOperator(c))
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ClearAllTransactionsByAccountHandler] failed to delete all transactions in account \"id:%d\", because %s", account.AccountId, err.Error())
@@ -335,7 +553,8 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		clientTimezone = time.Local
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -350,21 +569,21 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		return nil, "", errs.ErrNotPermittedToPerformThisAction
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
-	categories, err := a.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := a.categories.GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
-	tags, err := a.tags.GetAllTagsByUid(c, uid)
+	tags, err := a.tags.GetAllTagsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -378,18 +597,25 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		return nil, "", errs.ErrOperationFailed
 	}
 
+	allTransactionSplits, err := a.splits.GetAllSplitsOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
 	accountMap := a.accounts.GetAccountMapByList(accounts)
 	categoryMap := a.categories.GetCategoryMapByList(categories)
 	tagMap := a.tags.GetTagMapByList(tags)
 
-	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, exportTransactionDataReq.AccountIds, uid)
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, exportTransactionDataReq.AccountIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[data_managements.getExportedFileContent] get account error, because %s", err.Error())
 		return nil, "", errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allCategoryIds, err := a.categories.GetCategoryOrSubCategoryIds(c, exportTransactionDataReq.CategoryIds, uid)
+	allCategoryIds, err := a.categories.GetCategoryOrSubCategoryIds(c, exportTransactionDataReq.CategoryIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[data_managements.getExportedFileContent] get transaction category error, because %s", err.Error())
@@ -419,20 +645,31 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, ledgerId, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
-	dataExporter := converters.GetTransactionDataExporter(fileType)
+	if exportTransactionDataReq.UseHistoricalExchangeRates {
+		amountConverter, exchangeRatesConversion, err := exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), "", true, exportTransactionDataReq.MinTime, exportTransactionDataReq.MaxTime)
+
+		if err != nil {
+			log.Errorf(c, "[data_managements.getExportedFileContent] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, "", errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		allTransactions, accountMap, allTransactionSplits = a.convertTransactionAmountsToCurrency(allTransactions, accountMap, allTransactionSplits, amountConverter, exchangeRatesConversion.Currency, clientTimezone)
+	}
+
+	dataExporter := converters.GetTransactionDataExporter(fileType, user)
 
 	if dataExporter == nil {
 		return nil, "", errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes, allTransactionSplits)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.getExportedFileContent] failed to get exported data for \"uid:%d\", because %s", uid, err.Error())
@@ -444,6 +681,48 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 	return result, fileName, nil
 }
 
+func (a *DataManagementsApi) convertTransactionAmountsToCurrency(transactions []*models.Transaction, accountMap map[int64]*models.Account, allTransactionSplits map[int64][]*models.TransactionSplit, amountConverter models.TransactionAmountConverter, currency string, clientTimezone *time.Location) ([]*models.Transaction, map[int64]*models.Account, map[int64][]*models.TransactionSplit) {
+	convertedTransactions := make([]*models.Transaction, len(transactions))
+	convertedTransactionSplits := make(map[int64][]*models.TransactionSplit, len(allTransactionSplits))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := *transactions[i]
+		yearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), clientTimezone)
+		transaction.Amount = amountConverter(transaction.AccountId, yearMonthDay, transaction.Amount)
+
+		if transaction.RelatedAccountId > 0 {
+			transaction.RelatedAccountAmount = amountConverter(transaction.RelatedAccountId, yearMonthDay, transaction.RelatedAccountAmount)
+		}
+
+		convertedTransactions[i] = &transaction
+		splits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists {
+			continue
+		}
+
+		convertedSplits := make([]*models.TransactionSplit, len(splits))
+
+		for j := 0; j < len(splits); j++ {
+			split := *splits[j]
+			split.Amount = amountConverter(transaction.AccountId, yearMonthDay, split.Amount)
+			convertedSplits[j] = &split
+		}
+
+		convertedTransactionSplits[transaction.TransactionId] = convertedSplits
+	}
+
+	convertedAccountMap := make(map[int64]*models.Account, len(accountMap))
+
+	for accountId, account := range accountMap {
+		convertedAccount := *account
+		convertedAccount.Currency = currency
+		convertedAccountMap[accountId] = &convertedAccount
+	}
+
+	return convertedTransactions, convertedAccountMap, convertedTransactionSplits
+}
+
 func (a *DataManagementsApi) getFileName(user *models.User, clientTimezone *time.Location, fileExtension string) string {
 	currentTime := utils.FormatUnixTimeToLongDateTimeWithoutSecond(time.Now().Unix(), clientTimezone)
 	currentTime = strings.Replace(currentTime, "-", "_", -1)
@@ -8,6 +8,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // ExchangeRatesApi represents exchange rate api
@@ -39,6 +40,38 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// ExchangeRatesAtDateHandler returns the exchange rate data in effect on the specified date
+func (a *ExchangeRatesApi) ExchangeRatesAtDateHandler(c *core.WebContext) (any, *errs.Error) {
+	var exchangeRatesAtDateReq models.ExchangeRatesAtDateRequest
+	err := c.ShouldBindQuery(&exchangeRatesAtDateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.ExchangeRatesAtDateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	rateTime, err := utils.ParseFromLongDateFirstTime(exchangeRatesAtDateReq.Date, 0)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.ExchangeRatesAtDateHandler] date \"%s\" invalid", exchangeRatesAtDateReq.Date)
+		return nil, errs.ErrExchangeRateDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	yearMonthDay := int32(rateTime.Year())*10000 + int32(rateTime.Month())*100 + int32(rateTime.Day())
+	exchangeRateResponse, err := exchangerates.Container.GetExchangeRatesAtDate(c, uid, yearMonthDay, a.CurrentConfig())
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[exchange_rates.ExchangeRatesAtDateHandler] failed to get exchange rates at \"%s\" for user \"uid:%d\", because %s", exchangeRatesAtDateReq.Date, uid, err.Error())
+		}
+
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return exchangeRateResponse, nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
@@ -25,8 +25,9 @@ var (
 
 // InsightsExplorerListHandler returns insights explorer list of current user
 func (a *InsightsExplorersApi) InsightsExplorerListHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	explorers, err := a.insightsExploreres.GetAllInsightsExplorerNamesByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	explorers, err := a.insightsExploreres.GetAllInsightsExplorerNamesByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerListHandler] failed to get insights explorers for user \"uid:%d\", because %s", uid, err.Error())
@@ -59,8 +60,9 @@ func (a *InsightsExplorersApi) InsightsExplorerGetHandler(c *core.WebContext) (a
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	explorer, err := a.insightsExploreres.GetInsightsExplorerByExplorerId(c, uid, explorerGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	explorer, err := a.insightsExploreres.GetInsightsExplorerByExplorerId(c, uid, ledgerId, explorerGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerGetHandler] failed to get insights explorer \"id:%d\" for user \"uid:%d\", because %s", explorerGetReq.Id, uid, err.Error())
@@ -87,16 +89,17 @@ func (a *InsightsExplorersApi) InsightsExplorerCreateHandler(c *core.WebContext)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	maxOrderId, err := a.insightsExploreres.GetMaxDisplayOrder(c, uid)
+	maxOrderId, err := a.insightsExploreres.GetMaxDisplayOrder(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	explorer, err := a.createNewInsightsExplorerModel(uid, &explorerCreateReq, maxOrderId+1)
+	explorer, err := a.createNewInsightsExplorerModel(uid, ledgerId, &explorerCreateReq, maxOrderId+1)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerCreateHandler] failed to parse insights explorer data for user \"uid:%d\", because %s", uid, err.Error())
@@ -132,8 +135,9 @@ func (a *InsightsExplorersApi) InsightsExplorerModifyHandler(c *core.WebContext)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	explorer, err := a.insightsExploreres.GetInsightsExplorerByExplorerId(c, uid, explorerModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	explorer, err := a.insightsExploreres.GetInsightsExplorerByExplorerId(c, uid, ledgerId, explorerModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerModifyHandler] failed to get insights explorer \"id:%d\" for user \"uid:%d\", because %s", explorerModifyReq.Id, uid, err.Error())
@@ -150,6 +154,7 @@ func (a *InsightsExplorersApi) InsightsExplorerModifyHandler(c *core.WebContext)
 	newExplorer := &models.InsightsExplorer{
 		ExplorerId: explorer.ExplorerId,
 		Uid:        uid,
+		LedgerId:   ledgerId,
 		Name:       explorerModifyReq.Name,
 		Data:       string(newData),
 	}
@@ -189,8 +194,9 @@ func (a *InsightsExplorersApi) InsightsExplorerHideHandler(c *core.WebContext) (
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.insightsExploreres.HideInsightsExplorer(c, uid, []int64{explorerHideReq.Id}, explorerHideReq.Hidden)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.insightsExploreres.HideInsightsExplorer(c, uid, ledgerId, []int64{explorerHideReq.Id}, explorerHideReq.Hidden)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerHideHandler] failed to hide insights explorer \"id:%d\" for user \"uid:%d\", because %s", explorerHideReq.Id, uid, err.Error())
@@ -211,13 +217,15 @@ func (a *InsightsExplorersApi) InsightsExplorerMoveHandler(c *core.WebContext) (
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	explorers := make([]*models.InsightsExplorer, len(explorerMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(explorerMoveReq.NewDisplayOrders); i++ {
 		newDisplayOrder := explorerMoveReq.NewDisplayOrders[i]
 		explorer := &models.InsightsExplorer{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			ExplorerId:   newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -225,7 +233,7 @@ func (a *InsightsExplorersApi) InsightsExplorerMoveHandler(c *core.WebContext) (
 		explorers[i] = explorer
 	}
 
-	err = a.insightsExploreres.ModifyInsightsExplorerDisplayOrders(c, uid, explorers)
+	err = a.insightsExploreres.ModifyInsightsExplorerDisplayOrders(c, uid, ledgerId, explorers)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerMoveHandler] failed to move insights explorers for user \"uid:%d\", because %s", uid, err.Error())
@@ -246,8 +254,9 @@ func (a *InsightsExplorersApi) InsightsExplorerDeleteHandler(c *core.WebContext)
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.insightsExploreres.DeleteInsightsExplorer(c, uid, explorerDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.insightsExploreres.DeleteInsightsExplorer(c, uid, ledgerId, explorerDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[explorers.InsightsExplorerDeleteHandler] failed to delete insights explorer \"id:%d\" for user \"uid:%d\", because %s", explorerDeleteReq.Id, uid, err.Error())
@@ -258,7 +267,7 @@ func (a *InsightsExplorersApi) InsightsExplorerDeleteHandler(c *core.WebContext)
 	return true, nil
 }
 
-func (a *InsightsExplorersApi) createNewInsightsExplorerModel(uid int64, explorerCreateReq *models.InsightsExplorerCreateRequest, order int32) (*models.InsightsExplorer, error) {
+func (a *InsightsExplorersApi) createNewInsightsExplorerModel(uid int64, ledgerId int64, explorerCreateReq *models.InsightsExplorerCreateRequest, order int32) (*models.InsightsExplorer, error) {
 	data, err := json.Marshal(explorerCreateReq.Data)
 
 	if err != nil {
@@ -267,6 +276,7 @@ func (a *InsightsExplorersApi) createNewInsightsExplorerModel(uid int64, explore
 
 	return &models.InsightsExplorer{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         explorerCreateReq.Name,
 		Data:         string(data),
 		DisplayOrder: order,
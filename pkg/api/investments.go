@@ -0,0 +1,258 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/securityprices"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentsApi represents investment api
+type InvestmentsApi struct {
+	ApiUsingConfig
+	investmentTransactions *services.InvestmentTransactionService
+	transactions           *services.TransactionService
+	users                  *services.UserService
+}
+
+// Initialize an investment api singleton instance
+var (
+	Investments = &InvestmentsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		investmentTransactions: services.InvestmentTransactions,
+		transactions:           services.Transactions,
+		users:                  services.Users,
+	}
+)
+
+// InvestmentTransactionListHandler returns investment transaction list of current user
+func (a *InvestmentsApi) InvestmentTransactionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionListReq models.InvestmentTransactionListRequest
+	err := c.ShouldBindQuery(&transactionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	transactions, err := a.investmentTransactions.GetAllTransactionsByUid(c, uid, ledgerId, transactionListReq.AccountId, models.NormalizeSecuritySymbol(transactionListReq.Symbol))
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionListHandler] failed to get investment transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionResps := make(models.InvestmentTransactionInfoResponseSlice, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionResps[i] = transactions[i].ToInvestmentTransactionInfoResponse()
+	}
+
+	sort.Sort(transactionResps)
+
+	return transactionResps, nil
+}
+
+// InvestmentTransactionCreateHandler saves a new investment transaction and its cash transaction by request parameters for current user
+func (a *InvestmentsApi) InvestmentTransactionCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionCreateReq models.InvestmentTransactionCreateRequest
+	err := c.ShouldBindJSON(&transactionCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !transactionCreateReq.Type.IsValid() {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] investment transaction type invalid, type is %d", transactionCreateReq.Type)
+		return nil, errs.ErrInvestmentTransactionTypeInvalid
+	}
+
+	symbol := models.NormalizeSecuritySymbol(transactionCreateReq.Symbol)
+
+	if symbol == "" {
+		return nil, errs.ErrInvestmentSymbolInvalid
+	}
+
+	quantity, err := models.ParseInvestmentQuantity(transactionCreateReq.Quantity)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] quantity \"%s\" invalid", transactionCreateReq.Quantity)
+		return nil, errs.ErrInvestmentQuantityInvalid
+	}
+
+	if transactionCreateReq.Type == models.INVESTMENT_TRANSACTION_TYPE_DIVIDEND {
+		quantity = 0
+	} else if quantity <= 0 {
+		return nil, errs.ErrInvestmentQuantityInvalid
+	}
+
+	if transactionCreateReq.Type != models.INVESTMENT_TRANSACTION_TYPE_BUY && transactionCreateReq.Fee > transactionCreateReq.Amount {
+		return nil, errs.ErrInvestmentFeeExceedsAmount
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+
+	investmentTransaction := &models.InvestmentTransaction{
+		Uid:               uid,
+		LedgerId:          ledgerId,
+		AccountId:         transactionCreateReq.AccountId,
+		TransactionTime:   transactionCreateReq.Time,
+		TimezoneUtcOffset: transactionCreateReq.UtcOffset,
+		Type:              transactionCreateReq.Type,
+		Symbol:            symbol,
+		Quantity:          quantity,
+		Amount:            transactionCreateReq.Amount,
+		Fee:               transactionCreateReq.Fee,
+		Comment:           transactionCreateReq.Comment,
+	}
+
+	var cashTransaction *models.Transaction
+
+	if transactionCreateReq.CategoryId > 0 {
+		user, err := a.users.GetUserById(c, uid)
+
+		if err != nil {
+			if !errs.IsCustomError(err) {
+				log.Errorf(c, "[investments.InvestmentTransactionCreateHandler] failed to get user, because %s", err.Error())
+			}
+
+			return nil, errs.ErrUserNotFound
+		}
+
+		cashTransaction = a.createCashTransactionModel(investmentTransaction, transactionCreateReq.CategoryId, c.GetCurrentUid(), c.ClientIP())
+
+		if !user.CanEditTransactionByTransactionTime(cashTransaction.TransactionTime, clientTimezone) {
+			return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+		}
+	}
+
+	err = a.investmentTransactions.CreateTransaction(c, investmentTransaction, cashTransaction)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionCreateHandler] failed to create investment transaction \"id:%d\" for user \"uid:%d\", because %s", investmentTransaction.InvestmentTransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.InvestmentTransactionCreateHandler] user \"uid:%d\" has created a new investment transaction \"id:%d\" successfully", uid, investmentTransaction.InvestmentTransactionId)
+
+	return investmentTransaction.ToInvestmentTransactionInfoResponse(), nil
+}
+
+// InvestmentTransactionDeleteHandler deletes an existed investment transaction and its cash transaction by request parameters for current user
+func (a *InvestmentsApi) InvestmentTransactionDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionDeleteReq models.InvestmentTransactionDeleteRequest
+	err := c.ShouldBindJSON(&transactionDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	investmentTransaction, err := a.investmentTransactions.DeleteTransaction(c, uid, ledgerId, transactionDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionDeleteHandler] failed to delete investment transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if investmentTransaction.TransactionId > 0 {
+		err = a.transactions.DeleteTransaction(c, uid, ledgerId, investmentTransaction.TransactionId, getTransactionHistoryOperator(c))
+
+		if err != nil && err != errs.ErrTransactionNotFound {
+			log.Errorf(c, "[investments.InvestmentTransactionDeleteHandler] failed to delete cash transaction \"id:%d\" for user \"uid:%d\", because %s", investmentTransaction.TransactionId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	log.Infof(c, "[investments.InvestmentTransactionDeleteHandler] user \"uid:%d\" has deleted investment transaction \"id:%d\"", uid, transactionDeleteReq.Id)
+	return true, nil
+}
+
+// InvestmentHoldingListHandler returns holding list with market value and unrealized gain of current user
+func (a *InvestmentsApi) InvestmentHoldingListHandler(c *core.WebContext) (any, *errs.Error) {
+	var holdingListReq models.InvestmentHoldingListRequest
+	err := c.ShouldBindQuery(&holdingListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentHoldingListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	transactions, err := a.investmentTransactions.GetAllTransactionsByUid(c, uid, ledgerId, holdingListReq.AccountId, "")
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to get investment transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	holdings, err := models.CalculateInvestmentHoldings(transactions)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to calculate investment holdings for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	latestPrices, err := securityprices.Container.GetLatestSecurityPrices(c, uid, a.CurrentConfig())
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to get latest security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	priceMap := latestPrices.GetPriceMap()
+	holdingResps := make([]*models.InvestmentHoldingInfoResponse, len(holdings))
+
+	for i := 0; i < len(holdings); i++ {
+		price, exists := priceMap[holdings[i].Symbol]
+		holdingResps[i] = holdings[i].ToInvestmentHoldingInfoResponse(price, exists)
+	}
+
+	return holdingResps, nil
+}
+
+func (a *InvestmentsApi) createCashTransactionModel(investmentTransaction *models.InvestmentTransaction, categoryId int64, createdByUid int64, clientIp string) *models.Transaction {
+	transaction := &models.Transaction{
+		Uid:               investmentTransaction.Uid,
+		LedgerId:          investmentTransaction.LedgerId,
+		CategoryId:        categoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(investmentTransaction.TransactionTime),
+		TimezoneUtcOffset: investmentTransaction.TimezoneUtcOffset,
+		AccountId:         investmentTransaction.AccountId,
+		Comment:           investmentTransaction.Comment,
+		CreatedIp:         clientIp,
+		CreatedByUid:      createdByUid,
+	}
+
+	if investmentTransaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
+		transaction.Type = models.TRANSACTION_DB_TYPE_EXPENSE
+		transaction.Amount = investmentTransaction.Amount + investmentTransaction.Fee
+	} else {
+		transaction.Type = models.TRANSACTION_DB_TYPE_INCOME
+		transaction.Amount = investmentTransaction.Amount - investmentTransaction.Fee
+	}
+
+	return transaction
+}
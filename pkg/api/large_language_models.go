@@ -71,7 +71,8 @@ func (a *LargeLanguageModelsApi) prepareRecognitionContext(c *core.WebContext, c
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -86,7 +87,7 @@ func (a *LargeLanguageModelsApi) prepareRecognitionContext(c *core.WebContext, c
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.%s] failed to get all accounts for user \"uid:%d\", because %s", callerName, uid, err.Error())
@@ -104,7 +105,7 @@ func (a *LargeLanguageModelsApi) prepareRecognitionContext(c *core.WebContext, c
 		accountNames = append(accountNames, accounts[i].Name)
 	}
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.%s] failed to get categories for user \"uid:%d\", because %s", callerName, uid, err.Error())
@@ -139,7 +140,7 @@ func (a *LargeLanguageModelsApi) prepareRecognitionContext(c *core.WebContext, c
 		}
 	}
 
-	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+	tags, err := a.transactionTags.GetAllTagsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[large_language_models.%s] failed to get tags for user \"uid:%d\", because %s", callerName, uid, err.Error())
@@ -15,6 +15,7 @@ import (
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/httpclient"
 	"github.com/mayswind/ezbookkeeping/pkg/llm"
 	"github.com/mayswind/ezbookkeeping/pkg/llm/data"
@@ -239,7 +240,8 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	_, userErr := a.users.GetUserById(c, uid)
 
 	if userErr != nil {
@@ -250,7 +252,7 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 		return nil, errs.ErrUserNotFound
 	}
 
-	transactions, transactionErr := a.getAIAssistantTransactionsForKnowledge(c, uid, clientTimezone)
+	transactions, transactionErr := a.getAIAssistantTransactionsForKnowledge(c, uid, ledgerId, clientTimezone)
 
 	if transactionErr != nil {
 		log.Errorf(c, "[large_language_models.prepareAIAssistantPromptContext] failed to get transactions for user \"uid:%d\", because %s", uid, transactionErr.Error())
@@ -264,7 +266,7 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 		}, nil
 	}
 
-	accounts, accountErr := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, accountErr := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 
 	if accountErr != nil {
 		log.Errorf(c, "[large_language_models.prepareAIAssistantPromptContext] failed to get all accounts for user \"uid:%d\", because %s", uid, accountErr.Error())
@@ -287,7 +289,7 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 		categoryIds[i] = transaction.CategoryId
 	}
 
-	categories, categoryErr := a.transactionCategories.GetCategoriesByCategoryIds(c, uid, utils.ToUniqueInt64Slice(categoryIds))
+	categories, categoryErr := a.transactionCategories.GetCategoriesByCategoryIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(categoryIds))
 
 	if categoryErr != nil {
 		log.Errorf(c, "[large_language_models.prepareAIAssistantPromptContext] failed to get categories for user \"uid:%d\", because %s", uid, categoryErr.Error())
@@ -305,7 +307,7 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 	allTagIds := utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds))
 
 	if len(allTagIds) > 0 {
-		tagMap, tagErr = a.transactionTags.GetTagsByTagIds(c, uid, allTagIds)
+		tagMap, tagErr = a.transactionTags.GetTagsByTagIds(c, uid, ledgerId, allTagIds)
 
 		if tagErr != nil {
 			log.Errorf(c, "[large_language_models.prepareAIAssistantPromptContext] failed to get tags for user \"uid:%d\", because %s", uid, tagErr.Error())
@@ -333,6 +335,14 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 	retrievedKnowledgeItems := selectTopAIAssistantKnowledgeItems(queryEmbedding, knowledgeItems, aiAssistantKnowledgeBaseTopK)
 	retrievedKnowledgeText := buildRetrievedKnowledgePromptContent(retrievedKnowledgeItems)
 	financialSnapshot := buildAIAssistantFinancialSnapshot(knowledgeItems, clientTimezone)
+	convertedCashFlowSnapshot, convertErr := a.buildAIAssistantConvertedCashFlowSnapshot(c, uid, ledgerId, currentConfig, clientTimezone)
+
+	if convertErr != nil {
+		log.Warnf(c, "[large_language_models.prepareAIAssistantPromptContext] failed to convert cash flow into default currency for user \"uid:%d\", because %s", uid, convertErr.Error())
+	} else {
+		financialSnapshot = financialSnapshot + convertedCashFlowSnapshot
+	}
+
 	systemPromptTemplate, templateErr := templates.GetTemplate(templates.SYSTEM_PROMPT_PERSONAL_FINANCE_ASSISTANT)
 
 	if templateErr != nil {
@@ -364,7 +374,7 @@ func (a *LargeLanguageModelsApi) prepareAIAssistantPromptContext(c *core.WebCont
 	}, nil
 }
 
-func (a *LargeLanguageModelsApi) getAIAssistantTransactionsForKnowledge(c *core.WebContext, uid int64, clientTimezone *time.Location) ([]*models.Transaction, error) {
+func (a *LargeLanguageModelsApi) getAIAssistantTransactionsForKnowledge(c *core.WebContext, uid int64, ledgerId int64, clientTimezone *time.Location) ([]*models.Transaction, error) {
 	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	coverageStartUnixTime := getAIAssistantKnowledgeCoverageStartUnixTime(clientTimezone)
 	page := int32(1)
@@ -378,7 +388,7 @@ func (a *LargeLanguageModelsApi) getAIAssistantTransactionsForKnowledge(c *core.
 			pageCount = int32(remainingCount)
 		}
 
-		pageTransactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", page, pageCount, false, true)
+		pageTransactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", page, pageCount, false, true)
 
 		if err != nil {
 			return nil, err
@@ -1197,6 +1207,71 @@ func buildAIAssistantFinancialSnapshot(knowledgeItems []*aiAssistantKnowledgeIte
 	return snapshotBuilder.String()
 }
 
+func (a *LargeLanguageModelsApi) buildAIAssistantConvertedCashFlowSnapshot(c *core.WebContext, uid int64, ledgerId int64, currentConfig *settings.Config, clientTimezone *time.Location) (string, error) {
+	amountConverter, exchangeRatesConversion, err := exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, currentConfig, "", false, 0, 0)
+
+	if err != nil {
+		return "", err
+	}
+
+	nowUnixTime := time.Now().Unix()
+	nowInClientTimezone := time.Now().In(clientTimezone)
+	currentMonthStartUnixTime := time.Date(nowInClientTimezone.Year(), nowInClientTimezone.Month(), 1, 0, 0, 0, 0, clientTimezone).Unix()
+	overallTotalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, ledgerId, getAIAssistantKnowledgeCoverageStartUnixTime(clientTimezone), nowUnixTime, nil, false, "", clientTimezone, false, false, amountConverter)
+
+	if err != nil {
+		return "", err
+	}
+
+	thisMonthTotalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, ledgerId, currentMonthStartUnixTime, nowUnixTime, nil, false, "", clientTimezone, false, false, amountConverter)
+
+	if err != nil {
+		return "", err
+	}
+
+	snapshotBuilder := &strings.Builder{}
+	snapshotBuilder.WriteString("\nCash flow converted into ")
+	snapshotBuilder.WriteString(exchangeRatesConversion.Currency)
+	snapshotBuilder.WriteString(" at the latest exchange rates (data source: ")
+	snapshotBuilder.WriteString(exchangeRatesConversion.DataSource)
+	snapshotBuilder.WriteString("):")
+	appendConvertedCashFlowLine(snapshotBuilder, "Overall", overallTotalAmounts)
+	appendConvertedCashFlowLine(snapshotBuilder, "This month", thisMonthTotalAmounts)
+
+	for i := 0; i < len(exchangeRatesConversion.ExchangeRates); i++ {
+		exchangeRate := exchangeRatesConversion.ExchangeRates[i]
+		snapshotBuilder.WriteString("\n- Rate: 1 ")
+		snapshotBuilder.WriteString(exchangeRate.Currency)
+		snapshotBuilder.WriteString(" = ")
+		snapshotBuilder.WriteString(exchangeRate.Rate)
+		snapshotBuilder.WriteString(" ")
+		snapshotBuilder.WriteString(exchangeRatesConversion.Currency)
+
+		if exchangeRate.DataSource == settings.UserCustomExchangeRatesDataSource {
+			snapshotBuilder.WriteString(" (user custom rate)")
+		}
+	}
+
+	if len(exchangeRatesConversion.UnconvertibleCurrencies) > 0 {
+		snapshotBuilder.WriteString("\n- Not converted (no exchange rate): ")
+		snapshotBuilder.WriteString(strings.Join(exchangeRatesConversion.UnconvertibleCurrencies, ", "))
+	}
+
+	return snapshotBuilder.String(), nil
+}
+
+func appendConvertedCashFlowLine(snapshotBuilder *strings.Builder, name string, totalAmounts []*models.Transaction) {
+	totalIncomeAmount, totalExpenseAmount := models.GetTotalIncomeAndExpenseAmounts(totalAmounts)
+	snapshotBuilder.WriteString("\n- ")
+	snapshotBuilder.WriteString(name)
+	snapshotBuilder.WriteString(": income ")
+	snapshotBuilder.WriteString(utils.FormatAmount(totalIncomeAmount))
+	snapshotBuilder.WriteString(", expense ")
+	snapshotBuilder.WriteString(utils.FormatAmount(totalExpenseAmount))
+	snapshotBuilder.WriteString(", net ")
+	snapshotBuilder.WriteString(utils.FormatAmount(totalIncomeAmount - totalExpenseAmount))
+}
+
 func appendCurrencyOverviewLines(snapshotBuilder *strings.Builder, overviewMap map[string]*aiAssistantCurrencyOverview) {
 	if len(overviewMap) < 1 {
 		snapshotBuilder.WriteString("\n- No data")
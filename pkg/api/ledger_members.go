@@ -0,0 +1,229 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// LedgerMembersApi represents ledger member api
+type LedgerMembersApi struct {
+	ledgers       *services.LedgerService
+	ledgerMembers *services.LedgerMemberService
+	users         *services.UserService
+}
+
+// Initialize a ledger member api singleton instance
+var (
+	LedgerMembers = &LedgerMembersApi{
+		ledgers:       services.Ledgers,
+		ledgerMembers: services.LedgerMembers,
+		users:         services.Users,
+	}
+)
+
+// LedgerMemberListHandler returns member list of the specified ledger owned by current user
+func (a *LedgerMembersApi) LedgerMemberListHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberListReq models.LedgerMemberListRequest
+	err := c.ShouldBindQuery(&memberListReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	_, err = a.ledgers.GetLedgerByLedgerId(c, uid, memberListReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberListHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", memberListReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	members, err := a.ledgerMembers.GetAllMembersByLedgerId(c, uid, memberListReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberListHandler] failed to get members of ledger \"id:%d\" for user \"uid:%d\", because %s", memberListReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	memberResps := make([]*models.LedgerMemberInfoResponse, 0, len(members))
+
+	for i := 0; i < len(members); i++ {
+		member := members[i]
+		memberUser, err := a.users.GetUserById(c, member.MemberUid)
+
+		if err != nil {
+			log.Warnf(c, "[ledger_members.LedgerMemberListHandler] failed to get member user \"uid:%d\" of ledger \"id:%d\", because %s", member.MemberUid, member.LedgerId, err.Error())
+			continue
+		}
+
+		memberResps = append(memberResps, member.ToLedgerMemberInfoResponse(memberUser))
+	}
+
+	return memberResps, nil
+}
+
+// LedgerMemberAddHandler shares the specified ledger owned by current user with another user by request parameters
+func (a *LedgerMembersApi) LedgerMemberAddHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberAddReq models.LedgerMemberAddRequest
+	err := c.ShouldBindJSON(&memberAddReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberAddHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !memberAddReq.Role.IsShareable() {
+		log.Warnf(c, "[ledger_members.LedgerMemberAddHandler] ledger member role \"%d\" is invalid", memberAddReq.Role)
+		return nil, errs.ErrLedgerMemberRoleInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	memberUser, err := a.users.GetUserByUsername(c, memberAddReq.Username)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberAddHandler] failed to get user \"%s\", because %s", memberAddReq.Username, err.Error())
+		return nil, errs.Or(err, errs.ErrUserNotFound)
+	}
+
+	member := &models.LedgerMember{
+		Uid:       uid,
+		LedgerId:  memberAddReq.LedgerId,
+		MemberUid: memberUser.Uid,
+		Role:      memberAddReq.Role,
+	}
+
+	err = a.ledgerMembers.CreateMember(c, member)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberAddHandler] failed to share ledger \"id:%d\" of user \"uid:%d\" with user \"uid:%d\", because %s", memberAddReq.LedgerId, uid, memberUser.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberAddHandler] user \"uid:%d\" has shared ledger \"id:%d\" with user \"uid:%d\" successfully", uid, memberAddReq.LedgerId, memberUser.Uid)
+
+	return member.ToLedgerMemberInfoResponse(memberUser), nil
+}
+
+// LedgerMemberModifyHandler updates the role of an existed ledger member by request parameters for current user
+func (a *LedgerMembersApi) LedgerMemberModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberModifyReq models.LedgerMemberModifyRequest
+	err := c.ShouldBindJSON(&memberModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	member, err := a.ledgerMembers.GetMemberByMemberId(c, uid, memberModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberModifyHandler] failed to get ledger member \"id:%d\" for user \"uid:%d\", because %s", memberModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if member.Role == memberModifyReq.Role {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.ledgerMembers.ModifyMemberRole(c, uid, memberModifyReq.Id, memberModifyReq.Role)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberModifyHandler] failed to update ledger member \"id:%d\" for user \"uid:%d\", because %s", memberModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberModifyHandler] user \"uid:%d\" has updated ledger member \"id:%d\" successfully", uid, memberModifyReq.Id)
+
+	member.Role = memberModifyReq.Role
+	memberUser, err := a.users.GetUserById(c, member.MemberUid)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberModifyHandler] failed to get member user \"uid:%d\", because %s", member.MemberUid, err.Error())
+		memberUser = nil
+	}
+
+	return member.ToLedgerMemberInfoResponse(memberUser), nil
+}
+
+// LedgerMemberDeleteHandler deletes an existed ledger member by request parameters for current user
+func (a *LedgerMembersApi) LedgerMemberDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberDeleteReq models.LedgerMemberDeleteRequest
+	err := c.ShouldBindJSON(&memberDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.LedgerMemberDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.DeleteMember(c, uid, memberDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.LedgerMemberDeleteHandler] failed to delete ledger member \"id:%d\" for user \"uid:%d\", because %s", memberDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.LedgerMemberDeleteHandler] user \"uid:%d\" has deleted ledger member \"id:%d\"", uid, memberDeleteReq.Id)
+	return true, nil
+}
+
+// SharedLedgerListHandler returns the list of ledgers which are shared with current user
+func (a *LedgerMembersApi) SharedLedgerListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	members, err := a.ledgerMembers.GetAllSharedLedgerMembersByMemberUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.SharedLedgerListHandler] failed to get shared ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	sharedLedgerResps := make([]*models.SharedLedgerInfoResponse, 0, len(members))
+
+	for i := 0; i < len(members); i++ {
+		member := members[i]
+		ledger, err := a.ledgers.GetLedgerByLedgerId(c, member.Uid, member.LedgerId)
+
+		if err != nil {
+			log.Warnf(c, "[ledger_members.SharedLedgerListHandler] failed to get ledger \"id:%d\" of user \"uid:%d\", because %s", member.LedgerId, member.Uid, err.Error())
+			continue
+		}
+
+		owner, err := a.users.GetUserById(c, member.Uid)
+
+		if err != nil {
+			log.Warnf(c, "[ledger_members.SharedLedgerListHandler] failed to get owner user \"uid:%d\" of ledger \"id:%d\", because %s", member.Uid, member.LedgerId, err.Error())
+			continue
+		}
+
+		sharedLedgerResps = append(sharedLedgerResps, member.ToSharedLedgerInfoResponse(ledger, owner))
+	}
+
+	return sharedLedgerResps, nil
+}
+
+// SharedLedgerLeaveHandler removes current user from the specified ledger which is shared with current user
+func (a *LedgerMembersApi) SharedLedgerLeaveHandler(c *core.WebContext) (any, *errs.Error) {
+	var sharedLedgerLeaveReq models.SharedLedgerLeaveRequest
+	err := c.ShouldBindJSON(&sharedLedgerLeaveReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledger_members.SharedLedgerLeaveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgerMembers.LeaveSharedLedger(c, uid, sharedLedgerLeaveReq.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledger_members.SharedLedgerLeaveHandler] failed to leave shared ledger \"id:%d\" for user \"uid:%d\", because %s", sharedLedgerLeaveReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledger_members.SharedLedgerLeaveHandler] user \"uid:%d\" has left shared ledger \"id:%d\"", uid, sharedLedgerLeaveReq.LedgerId)
+	return true, nil
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// LedgersApi represents ledger api
+type LedgersApi struct {
+	ledgers *services.LedgerService
+}
+
+// Initialize a ledger api singleton instance
+var (
+	Ledgers = &LedgersApi{
+		ledgers: services.Ledgers,
+	}
+)
+
+// LedgerListHandler returns ledger list of current user
+func (a *LedgersApi) LedgerListHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerListReq models.LedgerListRequest
+	err := c.ShouldBindQuery(&ledgerListReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	ledgers, err := a.ledgers.GetAllLedgersByUid(c, uid, ledgerListReq.VisibleOnly)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerListHandler] failed to get ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgerResps := make(models.LedgerInfoResponseSlice, len(ledgers))
+
+	for i := 0; i < len(ledgers); i++ {
+		ledgerResps[i] = ledgers[i].ToLedgerInfoResponse()
+	}
+
+	sort.Sort(ledgerResps)
+
+	return ledgerResps, nil
+}
+
+// LedgerGetHandler returns one specific ledger of current user
+func (a *LedgersApi) LedgerGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerGetReq models.LedgerGetRequest
+	err := c.ShouldBindQuery(&ledgerGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	ledger, err := a.ledgers.GetLedgerByLedgerId(c, uid, ledgerGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerGetHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return ledger.ToLedgerInfoResponse(), nil
+}
+
+// LedgerCreateHandler saves a new ledger by request parameters for current user
+func (a *LedgersApi) LedgerCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerCreateReq models.LedgerCreateRequest
+	err := c.ShouldBindJSON(&ledgerCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	maxOrderId, err := a.ledgers.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledger := &models.Ledger{
+		Uid:          uid,
+		Name:         ledgerCreateReq.Name,
+		DisplayOrder: maxOrderId + 1,
+		Comment:      ledgerCreateReq.Comment,
+	}
+
+	err = a.ledgers.CreateLedger(c, ledger)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerCreateHandler] failed to create ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerCreateHandler] user \"uid:%d\" has created a new ledger \"id:%d\" successfully", uid, ledger.LedgerId)
+
+	return ledger.ToLedgerInfoResponse(), nil
+}
+
+// LedgerModifyHandler saves an existed ledger by request parameters for current user
+func (a *LedgersApi) LedgerModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerModifyReq models.LedgerModifyRequest
+	err := c.ShouldBindJSON(&ledgerModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	ledger, err := a.ledgers.GetLedgerByLedgerId(c, uid, ledgerModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerModifyHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newLedger := &models.Ledger{
+		LedgerId: ledger.LedgerId,
+		Uid:      uid,
+		Name:     ledgerModifyReq.Name,
+		Comment:  ledgerModifyReq.Comment,
+	}
+
+	if newLedger.Name == ledger.Name && newLedger.Comment == ledger.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.ledgers.ModifyLedger(c, newLedger)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerModifyHandler] failed to update ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerModifyHandler] user \"uid:%d\" has updated ledger \"id:%d\" successfully", uid, ledgerModifyReq.Id)
+
+	newLedger.DisplayOrder = ledger.DisplayOrder
+	newLedger.Hidden = ledger.Hidden
+
+	return newLedger.ToLedgerInfoResponse(), nil
+}
+
+// LedgerHideHandler hides a ledger by request parameters for current user
+func (a *LedgersApi) LedgerHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerHideReq models.LedgerHideRequest
+	err := c.ShouldBindJSON(&ledgerHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgers.HideLedger(c, uid, []int64{ledgerHideReq.Id}, ledgerHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerHideHandler] failed to hide ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerHideHandler] user \"uid:%d\" has hidden ledger \"id:%d\"", uid, ledgerHideReq.Id)
+	return true, nil
+}
+
+// LedgerMoveHandler moves display order of existed ledgers by request parameters for current user
+func (a *LedgersApi) LedgerMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerMoveReq models.LedgerMoveRequest
+	err := c.ShouldBindJSON(&ledgerMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	ledgers := make([]*models.Ledger, len(ledgerMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ledgerMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ledgerMoveReq.NewDisplayOrders[i]
+		ledger := &models.Ledger{
+			Uid:          uid,
+			LedgerId:     newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		ledgers[i] = ledger
+	}
+
+	err = a.ledgers.ModifyLedgerDisplayOrders(c, uid, ledgers)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMoveHandler] failed to move ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerMoveHandler] user \"uid:%d\" has moved ledgers", uid)
+	return true, nil
+}
+
+// LedgerDeleteHandler deletes an existed ledger by request parameters for current user
+func (a *LedgersApi) LedgerDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerDeleteReq models.LedgerDeleteRequest
+	err := c.ShouldBindJSON(&ledgerDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.ledgers.DeleteLedger(c, uid, ledgerDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerDeleteHandler] failed to delete ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerDeleteHandler] user \"uid:%d\" has deleted ledger \"id:%d\"", uid, ledgerDeleteReq.Id)
+	return true, nil
+}
@@ -0,0 +1,418 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// PayeesApi represents payee api
+type PayeesApi struct {
+	payees                *services.PayeeService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+}
+
+// Initialize a payee api singleton instance
+var (
+	Payees = &PayeesApi{
+		payees:                services.Payees,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+	}
+)
+
+// PayeeListHandler returns payee list of current user
+func (a *PayeesApi) PayeeListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	payees, err := a.payees.GetAllPayeesByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeListHandler] failed to get payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeResps := make(models.PayeeInfoResponseSlice, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		payeeResps[i] = payees[i].ToPayeeInfoResponse()
+	}
+
+	sort.Sort(payeeResps)
+
+	return payeeResps, nil
+}
+
+// PayeeGetHandler returns one specific payee of current user
+func (a *PayeesApi) PayeeGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeGetReq models.PayeeGetRequest
+	err := c.ShouldBindQuery(&payeeGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, ledgerId, payeeGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeGetHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return payee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeCreateHandler saves a new payee by request parameters for current user
+func (a *PayeesApi) PayeeCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeCreateReq models.PayeeCreateRequest
+	err := c.ShouldBindJSON(&payeeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	payee, err := a.createNewPayeeModel(uid, ledgerId, &payeeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] parse default tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	err = a.checkPayee(c, uid, ledgerId, payee)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] payee of user \"uid:%d\" is invalid, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.payees.GetMaxDisplayOrder(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payee.DisplayOrder = maxOrderId + 1
+
+	err = a.payees.CreatePayee(c, payee)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateHandler] failed to create payee \"id:%d\" for user \"uid:%d\", because %s", payee.PayeeId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeCreateHandler] user \"uid:%d\" has created a new payee \"id:%d\" successfully", uid, payee.PayeeId)
+
+	return payee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeCreateBatchHandler saves some new payees by request parameters for current user
+func (a *PayeesApi) PayeeCreateBatchHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeCreateBatchReq models.PayeeCreateBatchRequest
+	err := c.ShouldBindJSON(&payeeCreateBatchReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateBatchHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+
+	maxOrderId, err := a.payees.GetMaxDisplayOrder(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateBatchHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payees := make([]*models.Payee, len(payeeCreateBatchReq.Payees))
+
+	for i := 0; i < len(payeeCreateBatchReq.Payees); i++ {
+		payee, err := a.createNewPayeeModel(uid, ledgerId, payeeCreateBatchReq.Payees[i])
+
+		if err != nil {
+			log.Warnf(c, "[payees.PayeeCreateBatchHandler] parse default tag ids of payee#%d failed, because %s", i, err.Error())
+			return nil, errs.ErrTransactionTagIdInvalid
+		}
+
+		err = a.checkPayee(c, uid, ledgerId, payee)
+
+		if err != nil {
+			log.Warnf(c, "[payees.PayeeCreateBatchHandler] payee#%d of user \"uid:%d\" is invalid, because %s", i, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		payee.DisplayOrder = maxOrderId + 1 + int32(i)
+		payees[i] = payee
+	}
+
+	err = a.payees.CreatePayees(c, uid, ledgerId, payees, payeeCreateBatchReq.SkipExists)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateBatchHandler] failed to create payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeCreateBatchHandler] user \"uid:%d\" has created payees successfully", uid)
+
+	payeeResps := make(models.PayeeInfoResponseSlice, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		payeeResps[i] = payees[i].ToPayeeInfoResponse()
+	}
+
+	sort.Sort(payeeResps)
+
+	return payeeResps, nil
+}
+
+// PayeeModifyHandler saves an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeModifyReq models.PayeeModifyRequest
+	err := c.ShouldBindJSON(&payeeModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, ledgerId, payeeModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newPayee, err := a.createNewPayeeModel(uid, ledgerId, &models.PayeeCreateRequest{
+		Name:              payeeModifyReq.Name,
+		Aliases:           payeeModifyReq.Aliases,
+		DefaultCategoryId: payeeModifyReq.DefaultCategoryId,
+		DefaultTagIds:     payeeModifyReq.DefaultTagIds,
+	})
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] parse default tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	newPayee.PayeeId = payee.PayeeId
+	newPayee.DisplayOrder = payee.DisplayOrder
+	newPayee.Hidden = payee.Hidden
+
+	payeeNameChanged := newPayee.Name != payee.Name
+
+	if !payeeNameChanged &&
+		a.isPayeeAliasesEqual(newPayee.Aliases, payee.Aliases) &&
+		newPayee.DefaultCategoryId == payee.DefaultCategoryId &&
+		newPayee.DefaultTagIds == payee.DefaultTagIds {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkPayee(c, uid, ledgerId, newPayee)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] payee \"id:%d\" of user \"uid:%d\" is invalid, because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.payees.ModifyPayee(c, newPayee, payeeNameChanged)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to update payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeModifyHandler] user \"uid:%d\" has updated payee \"id:%d\" successfully", uid, payeeModifyReq.Id)
+
+	return newPayee.ToPayeeInfoResponse(), nil
+}
+
+// PayeeHideHandler hides a payee by request parameters for current user
+func (a *PayeesApi) PayeeHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeHideReq models.PayeeHideRequest
+	err := c.ShouldBindJSON(&payeeHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.payees.HidePayee(c, uid, ledgerId, []int64{payeeHideReq.Id}, payeeHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeHideHandler] failed to hide payee \"id:%d\" for user \"uid:%d\", because %s", payeeHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeHideHandler] user \"uid:%d\" has hidden payee \"id:%d\"", uid, payeeHideReq.Id)
+	return true, nil
+}
+
+// PayeeMoveHandler moves display order of existed payees by request parameters for current user
+func (a *PayeesApi) PayeeMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeMoveReq models.PayeeMoveRequest
+	err := c.ShouldBindJSON(&payeeMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	payees := make([]*models.Payee, len(payeeMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(payeeMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := payeeMoveReq.NewDisplayOrders[i]
+		payee := &models.Payee{
+			Uid:          uid,
+			LedgerId:     ledgerId,
+			PayeeId:      newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		payees[i] = payee
+	}
+
+	err = a.payees.ModifyPayeeDisplayOrders(c, uid, ledgerId, payees)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeMoveHandler] failed to move payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeMoveHandler] user \"uid:%d\" has moved payees", uid)
+	return true, nil
+}
+
+// PayeeDeleteHandler deletes an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeDeleteReq models.PayeeDeleteRequest
+	err := c.ShouldBindJSON(&payeeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.payees.DeletePayee(c, uid, ledgerId, payeeDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeDeleteHandler] failed to delete payee \"id:%d\" for user \"uid:%d\", because %s", payeeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeDeleteHandler] user \"uid:%d\" has deleted payee \"id:%d\"", uid, payeeDeleteReq.Id)
+	return true, nil
+}
+
+func (a *PayeesApi) createNewPayeeModel(uid int64, ledgerId int64, payeeCreateReq *models.PayeeCreateRequest) (*models.Payee, error) {
+	defaultTagIds, err := utils.StringArrayToInt64Array(payeeCreateReq.DefaultTagIds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(payeeCreateReq.Name)
+	aliases := make(models.PayeeAliases, 0, len(payeeCreateReq.Aliases))
+	aliasExists := make(map[string]bool, len(payeeCreateReq.Aliases))
+
+	for i := 0; i < len(payeeCreateReq.Aliases); i++ {
+		alias := strings.TrimSpace(payeeCreateReq.Aliases[i])
+		lowerAlias := strings.ToLower(alias)
+
+		if alias == "" || strings.EqualFold(alias, name) || aliasExists[lowerAlias] {
+			continue
+		}
+
+		aliasExists[lowerAlias] = true
+		aliases = append(aliases, alias)
+	}
+
+	return &models.Payee{
+		Uid:               uid,
+		LedgerId:          ledgerId,
+		Name:              name,
+		Aliases:           aliases,
+		DefaultCategoryId: payeeCreateReq.DefaultCategoryId,
+		DefaultTagIds:     strings.Join(utils.Int64ArrayToStringArray(defaultTagIds), ","),
+	}, nil
+}
+
+func (a *PayeesApi) checkPayee(c *core.WebContext, uid int64, ledgerId int64, payee *models.Payee) error {
+	if payee.Name == "" {
+		return errs.ErrPayeeNameIsEmpty
+	}
+
+	if len(payee.Aliases) > models.MaximumAliasesCountOfPayee {
+		return errs.ErrPayeeHasTooManyAliases
+	}
+
+	defaultTagIds := payee.GetDefaultTagIds()
+
+	if len(defaultTagIds) > models.MaximumTagsCountOfTransaction {
+		return errs.ErrPayeeHasTooManyTags
+	}
+
+	if payee.DefaultCategoryId > 0 {
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, ledgerId, payee.DefaultCategoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+	}
+
+	if len(defaultTagIds) > 0 {
+		tagMap, err := a.transactionTags.GetTagsByTagIds(c, uid, ledgerId, defaultTagIds)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(defaultTagIds); i++ {
+			if _, exists := tagMap[defaultTagIds[i]]; !exists {
+				return errs.ErrTransactionTagNotFound
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *PayeesApi) isPayeeAliasesEqual(aliases1 models.PayeeAliases, aliases2 models.PayeeAliases) bool {
+	if len(aliases1) != len(aliases2) {
+		return false
+	}
+
+	for i := 0; i < len(aliases1); i++ {
+		if aliases1[i] != aliases2[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/securityprices"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// SecurityPricesApi represents security price api
+type SecurityPricesApi struct {
+	ApiUsingConfig
+	securityPrices *services.SecurityPriceService
+}
+
+// Initialize a security price api singleton instance
+var (
+	SecurityPrices = &SecurityPricesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		securityPrices: services.SecurityPrices,
+	}
+)
+
+// LatestSecurityPriceHandler returns latest security price data
+func (a *SecurityPricesApi) LatestSecurityPriceHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	latestSecurityPriceResponse, err := securityprices.Container.GetLatestSecurityPrices(c, uid, a.CurrentConfig())
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return latestSecurityPriceResponse, nil
+}
+
+// UserCustomSecurityPriceListHandler returns all user custom prices of the specified security for current user
+func (a *SecurityPricesApi) UserCustomSecurityPriceListHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityPriceListReq models.SecurityPriceListRequest
+	err := c.ShouldBindQuery(&securityPriceListReq)
+
+	if err != nil {
+		log.Warnf(c, "[security_prices.UserCustomSecurityPriceListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	symbol := models.NormalizeSecuritySymbol(securityPriceListReq.Symbol)
+	securityPrices, err := a.securityPrices.GetAllPricesBySymbol(c, uid, symbol)
+
+	if err != nil {
+		log.Errorf(c, "[security_prices.UserCustomSecurityPriceListHandler] failed to get security prices \"symbol:%s\" for user \"uid:%d\", because %s", symbol, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	securityPriceResps := make(models.SecurityPriceInfoResponseSlice, len(securityPrices))
+
+	for i := 0; i < len(securityPrices); i++ {
+		securityPriceResps[i] = securityPrices[i].ToSecurityPriceInfoResponse()
+	}
+
+	sort.Sort(securityPriceResps)
+
+	return securityPriceResps, nil
+}
+
+// UserCustomSecurityPriceUpdateHandler updates user custom security price data by request parameters for current user
+func (a *SecurityPricesApi) UserCustomSecurityPriceUpdateHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityPriceUpdateReq models.SecurityPriceUpdateRequest
+	err := c.ShouldBindJSON(&securityPriceUpdateReq)
+
+	if err != nil {
+		log.Warnf(c, "[security_prices.UserCustomSecurityPriceUpdateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	symbol := models.NormalizeSecuritySymbol(securityPriceUpdateReq.Symbol)
+
+	if symbol == "" {
+		return nil, errs.ErrInvestmentSymbolInvalid
+	}
+
+	priceDate, err := models.ParseSecurityPriceDate(securityPriceUpdateReq.Date)
+
+	if err != nil {
+		log.Warnf(c, "[security_prices.UserCustomSecurityPriceUpdateHandler] price date \"%s\" invalid", securityPriceUpdateReq.Date)
+		return nil, errs.ErrSecurityPriceDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	securityPrice, err := a.securityPrices.UpdatePrice(c, uid, symbol, priceDate, securityPriceUpdateReq.Price)
+
+	if err != nil {
+		log.Errorf(c, "[security_prices.UserCustomSecurityPriceUpdateHandler] failed to update security price \"symbol:%s\" for user \"uid:%d\", because %s", symbol, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[security_prices.UserCustomSecurityPriceUpdateHandler] user \"uid:%d\" has updated security price \"symbol:%s\" successfully", uid, symbol)
+	return securityPrice.ToSecurityPriceInfoResponse(), nil
+}
+
+// UserCustomSecurityPriceDeleteHandler deletes user custom security price data by request parameters for current user
+func (a *SecurityPricesApi) UserCustomSecurityPriceDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityPriceDeleteReq models.SecurityPriceDeleteRequest
+	err := c.ShouldBindJSON(&securityPriceDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[security_prices.UserCustomSecurityPriceDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	priceDate, err := models.ParseSecurityPriceDate(securityPriceDeleteReq.Date)
+
+	if err != nil {
+		log.Warnf(c, "[security_prices.UserCustomSecurityPriceDeleteHandler] price date \"%s\" invalid", securityPriceDeleteReq.Date)
+		return nil, errs.ErrSecurityPriceDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	symbol := models.NormalizeSecuritySymbol(securityPriceDeleteReq.Symbol)
+	err = a.securityPrices.DeletePrice(c, uid, symbol, priceDate)
+
+	if err != nil {
+		log.Errorf(c, "[security_prices.UserCustomSecurityPriceDeleteHandler] failed to delete security price \"symbol:%s\" for user \"uid:%d\", because %s", symbol, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[security_prices.UserCustomSecurityPriceDeleteHandler] user \"uid:%d\" has deleted security price \"symbol:%s\"", uid, symbol)
+	return true, nil
+}
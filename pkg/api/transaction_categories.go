@@ -48,8 +48,9 @@ func (a *TransactionCategoriesApi) CategoryListHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	categories, err := a.categories.GetAllCategoriesByUid(c, uid, categoryListReq.Type, categoryListReq.ParentId)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	categories, err := a.categories.GetAllCategoriesByUid(c, uid, ledgerId, categoryListReq.Type, categoryListReq.ParentId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryListHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -69,8 +70,9 @@ func (a *TransactionCategoriesApi) CategoryGetHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	category, err := a.categories.GetCategoryByCategoryId(c, uid, ledgerId, categoryGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryGetHandler] failed to get category \"id:%d\" for user \"uid:%d\", because %s", categoryGetReq.Id, uid, err.Error())
@@ -97,10 +99,11 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 		return nil, errs.ErrTransactionCategoryTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	if categoryCreateReq.ParentId > 0 {
-		parentCategory, err := a.categories.GetCategoryByCategoryId(c, uid, categoryCreateReq.ParentId)
+		parentCategory, err := a.categories.GetCategoryByCategoryId(c, uid, ledgerId, categoryCreateReq.ParentId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_categories.CategoryCreateHandler] failed to get parent category \"id:%d\" for user \"uid:%d\", because %s", categoryCreateReq.ParentId, uid, err.Error())
@@ -121,9 +124,9 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 	var maxOrderId int32
 
 	if categoryCreateReq.ParentId <= 0 {
-		maxOrderId, err = a.categories.GetMaxDisplayOrder(c, uid, categoryCreateReq.Type)
+		maxOrderId, err = a.categories.GetMaxDisplayOrder(c, uid, ledgerId, categoryCreateReq.Type)
 	} else {
-		maxOrderId, err = a.categories.GetMaxSubCategoryDisplayOrder(c, uid, categoryCreateReq.Type, categoryCreateReq.ParentId)
+		maxOrderId, err = a.categories.GetMaxSubCategoryDisplayOrder(c, uid, ledgerId, categoryCreateReq.Type, categoryCreateReq.ParentId)
 	}
 
 	if err != nil {
@@ -131,7 +134,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	category := a.createNewCategoryModel(uid, &categoryCreateReq, maxOrderId+1)
+	category := a.createNewCategoryModel(uid, ledgerId, &categoryCreateReq, maxOrderId+1)
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && categoryCreateReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_CATEGORY, uid, categoryCreateReq.ClientSessionId)
@@ -141,7 +144,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 			categoryId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				category, err = a.categories.GetCategoryByCategoryId(c, uid, categoryId)
+				category, err = a.categories.GetCategoryByCategoryId(c, uid, ledgerId, categoryId)
 
 				if err != nil {
 					log.Errorf(c, "[transaction_categories.CategoryCreateHandler] failed to get existed category \"id:%d\" for user \"uid:%d\", because %s", categoryId, uid, err.Error())
@@ -180,9 +183,10 @@ func (a *TransactionCategoriesApi) CategoryCreateBatchHandler(c *core.WebContext
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	categories, err := a.createBatchCategories(c, uid, &categoryCreateBatchReq)
+	categories, err := a.createBatchCategories(c, uid, ledgerId, &categoryCreateBatchReq)
 
 	if err != nil {
 		return nil, errs.Or(err, errs.ErrOperationFailed)
@@ -201,8 +205,9 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	category, err := a.categories.GetCategoryByCategoryId(c, uid, ledgerId, categoryModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryModifyHandler] failed to get category \"id:%d\" for user \"uid:%d\", because %s", categoryModifyReq.Id, uid, err.Error())
@@ -212,6 +217,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 	newCategory := &models.TransactionCategory{
 		CategoryId:       category.CategoryId,
 		Uid:              uid,
+		LedgerId:         ledgerId,
 		ParentCategoryId: categoryModifyReq.ParentId,
 		Name:             categoryModifyReq.Name,
 		DisplayOrder:     category.DisplayOrder,
@@ -239,14 +245,14 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 	}
 
 	if newCategory.ParentCategoryId != category.ParentCategoryId {
-		fromPrimaryCategory, err := a.categories.GetCategoryByCategoryId(c, uid, category.ParentCategoryId)
+		fromPrimaryCategory, err := a.categories.GetCategoryByCategoryId(c, uid, ledgerId, category.ParentCategoryId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_categories.CategoryModifyHandler] failed to get old primary category \"id:%d\" of category \"id:%d\" for user \"uid:%d\", because %s", category.ParentCategoryId, categoryModifyReq.Id, uid, err.Error())
 			return nil, errs.Or(err, errs.ErrOperationFailed)
 		}
 
-		toPrimaryCategory, err := a.categories.GetCategoryByCategoryId(c, uid, newCategory.ParentCategoryId)
+		toPrimaryCategory, err := a.categories.GetCategoryByCategoryId(c, uid, ledgerId, newCategory.ParentCategoryId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_categories.CategoryModifyHandler] failed to get new primary category \"id:%d\" of category \"id:%d\" for user \"uid:%d\", because %s", newCategory.ParentCategoryId, categoryModifyReq.Id, uid, err.Error())
@@ -261,7 +267,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 			return nil, errs.Or(err, errs.ErrNotAllowUseSecondaryTransactionAsPrimaryCategory)
 		}
 
-		maxOrderId, err := a.categories.GetMaxSubCategoryDisplayOrder(c, uid, category.Type, newCategory.ParentCategoryId)
+		maxOrderId, err := a.categories.GetMaxSubCategoryDisplayOrder(c, uid, ledgerId, category.Type, newCategory.ParentCategoryId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_categories.CategoryModifyHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -296,8 +302,9 @@ func (a *TransactionCategoriesApi) CategoryHideHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.categories.HideCategory(c, uid, []int64{categoryHideReq.Id}, categoryHideReq.Hidden)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.categories.HideCategory(c, uid, ledgerId, []int64{categoryHideReq.Id}, categoryHideReq.Hidden)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryHideHandler] failed to hide category \"id:%d\" for user \"uid:%d\", because %s", categoryHideReq.Id, uid, err.Error())
@@ -318,13 +325,15 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	categories := make([]*models.TransactionCategory, len(categoryMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(categoryMoveReq.NewDisplayOrders); i++ {
 		newDisplayOrder := categoryMoveReq.NewDisplayOrders[i]
 		category := &models.TransactionCategory{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			CategoryId:   newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -332,7 +341,7 @@ func (a *TransactionCategoriesApi) CategoryMoveHandler(c *core.WebContext) (any,
 		categories[i] = category
 	}
 
-	err = a.categories.ModifyCategoryDisplayOrders(c, uid, categories)
+	err = a.categories.ModifyCategoryDisplayOrders(c, uid, ledgerId, categories)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryMoveHandler] failed to move categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -353,8 +362,9 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.categories.DeleteCategory(c, uid, categoryDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.categories.DeleteCategory(c, uid, ledgerId, categoryDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.CategoryDeleteHandler] failed to delete category \"id:%d\" for user \"uid:%d\", because %s", categoryDeleteReq.Id, uid, err.Error())
@@ -365,7 +375,73 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 	return true, nil
 }
 
-func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid int64, categoryCreateBatchReq *models.TransactionCategoryCreateBatchRequest) ([]*models.TransactionCategory, error) {
+// CategoryTrashListHandler returns deleted transaction category list in trash bin of current user
+func (a *TransactionCategoriesApi) CategoryTrashListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	categories, err := a.categories.GetAllDeletedCategoriesByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.CategoryTrashListHandler] failed to get deleted categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryResps := make([]*models.DeletedTransactionCategoryInfoResponse, len(categories))
+
+	for i := 0; i < len(categories); i++ {
+		categoryResps[i] = categories[i].ToDeletedTransactionCategoryInfoResponse()
+	}
+
+	return categoryResps, nil
+}
+
+// CategoryRestoreHandler restores a deleted transaction category from trash bin by request parameters for current user
+func (a *TransactionCategoriesApi) CategoryRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var categoryRestoreReq models.TransactionCategoryRestoreRequest
+	err := c.ShouldBindJSON(&categoryRestoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_categories.CategoryRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.categories.RestoreCategory(c, uid, ledgerId, categoryRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.CategoryRestoreHandler] failed to restore category \"id:%d\" for user \"uid:%d\", because %s", categoryRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_categories.CategoryRestoreHandler] user \"uid:%d\" has restored category \"id:%d\"", uid, categoryRestoreReq.Id)
+	return true, nil
+}
+
+// CategoryPurgeHandler permanently deletes a deleted transaction category from trash bin by request parameters for current user
+func (a *TransactionCategoriesApi) CategoryPurgeHandler(c *core.WebContext) (any, *errs.Error) {
+	var categoryPurgeReq models.TransactionCategoryPurgeRequest
+	err := c.ShouldBindJSON(&categoryPurgeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_categories.CategoryPurgeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.categories.PurgeCategory(c, uid, ledgerId, categoryPurgeReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories.CategoryPurgeHandler] failed to purge category \"id:%d\" for user \"uid:%d\", because %s", categoryPurgeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_categories.CategoryPurgeHandler] user \"uid:%d\" has purged category \"id:%d\"", uid, categoryPurgeReq.Id)
+	return true, nil
+}
+
+func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid int64, ledgerId int64, categoryCreateBatchReq *models.TransactionCategoryCreateBatchRequest) ([]*models.TransactionCategory, error) {
 	var err error
 	categoryTypeMaxOrderMap := make(map[models.TransactionCategoryType]int32)
 	categoriesMap := make(map[*models.TransactionCategory][]*models.TransactionCategory)
@@ -377,7 +453,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 		var maxOrderId, exists = categoryTypeMaxOrderMap[categoryCreateReq.Type]
 
 		if !exists {
-			maxOrderId, err = a.categories.GetMaxDisplayOrder(c, uid, categoryCreateReq.Type)
+			maxOrderId, err = a.categories.GetMaxDisplayOrder(c, uid, ledgerId, categoryCreateReq.Type)
 
 			if err != nil {
 				log.Errorf(c, "[transaction_categories.CategoryCreateBatchHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -385,7 +461,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 			}
 		}
 
-		category := a.createNewCategoryModel(uid, &models.TransactionCategoryCreateRequest{
+		category := a.createNewCategoryModel(uid, ledgerId, &models.TransactionCategoryCreateRequest{
 			Name:  categoryCreateReq.Name,
 			Type:  categoryCreateReq.Type,
 			Icon:  categoryCreateReq.Icon,
@@ -395,7 +471,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 		categoriesMap[category] = make([]*models.TransactionCategory, len(categoryCreateReq.SubCategories))
 
 		for j := int32(0); j < int32(len(categoryCreateReq.SubCategories)); j++ {
-			subCategory := a.createNewCategoryModel(uid, categoryCreateReq.SubCategories[j], j+1)
+			subCategory := a.createNewCategoryModel(uid, ledgerId, categoryCreateReq.SubCategories[j], j+1)
 			categoriesMap[category][j] = subCategory
 			totalCount++
 		}
@@ -405,7 +481,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 		totalCount++
 	}
 
-	categories, err := a.categories.CreateCategories(c, uid, categoriesMap)
+	categories, err := a.categories.CreateCategories(c, uid, ledgerId, categoriesMap)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_categories.createBatchCategories] failed to create categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -417,9 +493,10 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 	return categories, nil
 }
 
-func (a *TransactionCategoriesApi) createNewCategoryModel(uid int64, categoryCreateReq *models.TransactionCategoryCreateRequest, order int32) *models.TransactionCategory {
+func (a *TransactionCategoriesApi) createNewCategoryModel(uid int64, ledgerId int64, categoryCreateReq *models.TransactionCategoryCreateRequest, order int32) *models.TransactionCategory {
 	return &models.TransactionCategory{
 		Uid:              uid,
+		LedgerId:         ledgerId,
 		Name:             categoryCreateReq.Name,
 		Type:             categoryCreateReq.Type,
 		ParentCategoryId: categoryCreateReq.ParentId,
@@ -38,7 +38,7 @@ var (
 
 // TransactionPictureUploadHandler saves transaction picture by request parameters for current user
 func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
 	form, err := c.MultipartForm()
 
 	if err != nil {
@@ -77,7 +77,7 @@ func (a *TransactionPicturesApi) TransactionPictureUploadHandler(c *core.WebCont
 		return nil, errs.ErrOperationFailed
 	}
 
-	pictureInfo := a.createNewPictureInfoModel(uid, fileExtension, c.ClientIP())
+	pictureInfo := a.createNewPictureInfoModel(c, fileExtension)
 
 	clientSessionIds := form.Value["clientSessionId"]
 	clientSessionId := ""
@@ -138,7 +138,7 @@ func (a *TransactionPicturesApi) TransactionPictureGetHandler(c *core.WebContext
 		return nil, "", errs.ErrTransactionPictureIdInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
 	pictureData, err := a.pictures.GetPictureByPictureId(c, uid, pictureId, fileExtension)
 
 	if err != nil {
@@ -162,8 +162,9 @@ func (a *TransactionPicturesApi) TransactionPictureRemoveUnusedHandler(c *core.W
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.pictures.RemoveUnusedTransactionPicture(c, uid, pictureDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.pictures.RemoveUnusedTransactionPicture(c, uid, ledgerId, pictureDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_pictures.TransactionPictureRemoveUnusedHandler] failed to remove unused transaction picture for user \"uid:%d\", because %s", uid, err.Error())
@@ -173,11 +174,12 @@ func (a *TransactionPicturesApi) TransactionPictureRemoveUnusedHandler(c *core.W
 	return true, nil
 }
 
-func (a *TransactionPicturesApi) createNewPictureInfoModel(uid int64, fileExtension string, clientIp string) *models.TransactionPictureInfo {
+func (a *TransactionPicturesApi) createNewPictureInfoModel(c *core.WebContext, fileExtension string) *models.TransactionPictureInfo {
 	return &models.TransactionPictureInfo{
-		Uid:              uid,
+		Uid:              c.GetCurrentLedgerOwnerUid(),
+		LedgerId:         c.GetCurrentLedgerId(),
 		TransactionId:    models.TransactionPictureNewPictureTransactionId,
 		PictureExtension: fileExtension,
-		CreatedIp:        clientIp,
+		CreatedIp:        c.ClientIP(),
 	}
 }
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestCreateNewPictureInfoModel_UploadedByLedgerMember(t *testing.T) {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest("POST", "/api/v1/transaction/pictures/upload.json", nil)
+	context := core.WrapWebContext(ginContext)
+	context.SetTokenClaims(&core.UserTokenClaims{Uid: 2})
+	context.SetCurrentLedgerId(3)
+	context.SetCurrentLedgerOwnerUid(1)
+	context.SetCurrentLedgerRole(core.LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR)
+
+	pictureInfo := TransactionPictures.createNewPictureInfoModel(context, "png")
+	assert.Equal(t, int64(1), pictureInfo.Uid)
+	assert.Equal(t, int64(3), pictureInfo.LedgerId)
+	assert.Equal(t, models.TransactionPictureNewPictureTransactionId, pictureInfo.TransactionId)
+	assert.Equal(t, "png", pictureInfo.PictureExtension)
+}
+
+func TestCreateNewPictureInfoModel_UploadedByLedgerOwner(t *testing.T) {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginContext.Request = httptest.NewRequest("POST", "/api/v1/transaction/pictures/upload.json", nil)
+	context := core.WrapWebContext(ginContext)
+	context.SetTokenClaims(&core.UserTokenClaims{Uid: 1})
+
+	pictureInfo := TransactionPictures.createNewPictureInfoModel(context, "jpg")
+	assert.Equal(t, int64(1), pictureInfo.Uid)
+	assert.Equal(t, models.DefaultLedgerId, pictureInfo.LedgerId)
+}
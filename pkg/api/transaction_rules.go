@@ -0,0 +1,568 @@
+package api
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const pageCountForApplyingTransactionRules = 1000
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	rules                 *services.TransactionRuleService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+	transactionSplits     *services.TransactionSplitService
+	payees                *services.PayeeService
+	accounts              *services.AccountService
+	users                 *services.UserService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		rules:                 services.TransactionRules,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+		transactionSplits:     services.TransactionSplits,
+		payees:                services.Payees,
+		accounts:              services.Accounts,
+		users:                 services.Users,
+	}
+)
+
+// RuleListHandler returns transaction rule list of current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	rules, err := a.rules.GetAllRulesByUid(c, uid, ledgerId, false)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleListHandler] failed to get rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current user
+func (a *TransactionRulesApi) RuleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ledgerId, ruleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleGetHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, err := utils.StringArrayToInt64Array(ruleCreateReq.AddTagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+
+	rule := &models.TransactionRule{
+		Uid:                  uid,
+		LedgerId:             ledgerId,
+		Name:                 ruleCreateReq.Name,
+		TransactionType:      ruleCreateReq.TransactionType,
+		CommentKeyword:       ruleCreateReq.CommentKeyword,
+		CommentRegex:         ruleCreateReq.CommentRegex,
+		MinAmount:            ruleCreateReq.MinAmount,
+		MaxAmount:            ruleCreateReq.MaxAmount,
+		AccountId:            ruleCreateReq.AccountId,
+		CounterpartyKeyword:  strings.TrimSpace(ruleCreateReq.CounterpartyKeyword),
+		OriginalCategoryName: strings.TrimSpace(ruleCreateReq.OriginalCategoryName),
+		SetCategoryId:        ruleCreateReq.SetCategoryId,
+		AddTagIds:            strings.Join(utils.Int64ArrayToStringArray(addTagIds), ","),
+		RewriteComment:       ruleCreateReq.RewriteComment,
+		SetHideAmount:        ruleCreateReq.SetHideAmount,
+		StopProcessing:       ruleCreateReq.StopProcessing,
+		Disabled:             ruleCreateReq.Disabled,
+	}
+
+	err = a.checkRule(c, uid, ledgerId, rule)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] rule of user \"uid:%d\" is invalid, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule.DisplayOrder = maxOrderId + 1
+
+	err = a.rules.CreateRule(c, rule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to create rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, err := utils.StringArrayToInt64Array(ruleModifyReq.AddTagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse tag ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ledgerId, ruleModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:               rule.RuleId,
+		Uid:                  uid,
+		LedgerId:             ledgerId,
+		Name:                 ruleModifyReq.Name,
+		TransactionType:      ruleModifyReq.TransactionType,
+		CommentKeyword:       ruleModifyReq.CommentKeyword,
+		CommentRegex:         ruleModifyReq.CommentRegex,
+		MinAmount:            ruleModifyReq.MinAmount,
+		MaxAmount:            ruleModifyReq.MaxAmount,
+		AccountId:            ruleModifyReq.AccountId,
+		CounterpartyKeyword:  strings.TrimSpace(ruleModifyReq.CounterpartyKeyword),
+		OriginalCategoryName: strings.TrimSpace(ruleModifyReq.OriginalCategoryName),
+		SetCategoryId:        ruleModifyReq.SetCategoryId,
+		AddTagIds:            strings.Join(utils.Int64ArrayToStringArray(addTagIds), ","),
+		RewriteComment:       ruleModifyReq.RewriteComment,
+		SetHideAmount:        ruleModifyReq.SetHideAmount,
+		StopProcessing:       ruleModifyReq.StopProcessing,
+		Disabled:             ruleModifyReq.Disabled,
+	}
+
+	if newRule.Name == rule.Name &&
+		newRule.TransactionType == rule.TransactionType &&
+		newRule.CommentKeyword == rule.CommentKeyword &&
+		newRule.CommentRegex == rule.CommentRegex &&
+		a.isAmountBoundEqual(newRule.MinAmount, rule.MinAmount) &&
+		a.isAmountBoundEqual(newRule.MaxAmount, rule.MaxAmount) &&
+		newRule.AccountId == rule.AccountId &&
+		newRule.CounterpartyKeyword == rule.CounterpartyKeyword &&
+		newRule.OriginalCategoryName == rule.OriginalCategoryName &&
+		newRule.SetCategoryId == rule.SetCategoryId &&
+		newRule.AddTagIds == rule.AddTagIds &&
+		newRule.RewriteComment == rule.RewriteComment &&
+		newRule.SetHideAmount == rule.SetHideAmount &&
+		newRule.StopProcessing == rule.StopProcessing &&
+		newRule.Disabled == rule.Disabled {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkRule(c, uid, ledgerId, newRule)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] rule of user \"uid:%d\" is invalid, because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.ModifyRule(c, newRule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to update rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	newRule.DisplayOrder = rule.DisplayOrder
+
+	return newRule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleMoveHandler moves display order of existed transaction rules by request parameters for current user
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			Uid:          uid,
+			LedgerId:     ledgerId,
+			RuleId:       newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, ledgerId, rules)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleMoveHandler] failed to move rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.rules.DeleteRule(c, uid, ledgerId, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to delete rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleApplyHandler applies the specified transaction rules (or all enabled transaction rules) to existed transactions of current user
+func (a *TransactionRulesApi) RuleApplyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleApplyReq models.TransactionRuleApplyRequest
+	err := c.ShouldBindJSON(&ruleApplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if ruleApplyReq.StartTime > 0 && ruleApplyReq.EndTime > 0 && ruleApplyReq.StartTime > ruleApplyReq.EndTime {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] start time %d is later than end time %d", ruleApplyReq.StartTime, ruleApplyReq.EndTime)
+		return nil, errs.ErrTransactionRuleTimeRangeInvalid
+	}
+
+	ruleIds, err := utils.StringArrayToInt64Array(ruleApplyReq.RuleIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] parse rule ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	maxTransactionTime := int64(math.MaxInt64)
+	minTransactionTime := int64(0)
+
+	if ruleApplyReq.EndTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(ruleApplyReq.EndTime)
+	}
+
+	if ruleApplyReq.StartTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(ruleApplyReq.StartTime)
+	}
+
+	rules, categoryMap, tagMap, err := a.rules.GetRulesAndItemsForApplying(c, uid, ledgerId, ruleIds)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	result := &models.TransactionRuleApplyResponse{}
+
+	if len(rules) < 1 {
+		return result, nil
+	}
+
+	payees, err := a.payees.GetAllPayeesByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeMap := a.payees.GetPayeeMapByList(payees)
+	operator := getTransactionHistoryOperator(c)
+
+	for maxTransactionTime > 0 {
+		pageTransactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, minTransactionTime, 0, nil, nil, nil, false, "", "", 1, pageCountForApplyingTransactionRules, false, true)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if len(pageTransactions) < pageCountForApplyingTransactionRules {
+			maxTransactionTime = 0
+		} else {
+			maxTransactionTime = pageTransactions[len(pageTransactions)-1].TransactionTime - 1
+		}
+
+		err = a.applyRulesToTransactions(c, user, ledgerId, clientTimezone, rules, categoryMap, tagMap, payeeMap, pageTransactions, operator, result)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to apply rules to transactions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	log.Infof(c, "[transaction_rules.RuleApplyHandler] user \"uid:%d\" has applied rules to transactions, %d matched, %d updated, %d failed, %d category changes skipped", uid, result.MatchedCount, result.UpdatedCount, result.FailedCount, result.CategorySkippedCount)
+
+	return result, nil
+}
+
+func (a *TransactionRulesApi) applyRulesToTransactions(c *core.WebContext, user *models.User, ledgerId int64, clientTimezone *time.Location, rules []*models.TransactionRule, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, payeeMap map[int64]*models.Payee, allTransactions []*models.Transaction, operator *models.TransactionHistoryOperator, result *models.TransactionRuleApplyResponse) error {
+	uid := user.Uid
+	transactions := make([]*models.Transaction, 0, len(allTransactions))
+	transactionIds := make([]int64, 0, len(allTransactions))
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		transactions = append(transactions, transaction)
+		transactionIds = append(transactionIds, transaction.TransactionId)
+	}
+
+	if len(transactions) < 1 {
+		return nil
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		return err
+	}
+
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		return err
+	}
+
+	targets := make([]*models.TransactionRuleTarget, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		payeeName := ""
+
+		if payee, exists := payeeMap[transaction.PayeeId]; exists {
+			payeeName = payee.Name
+		}
+
+		targets[i] = models.NewTransactionRuleTarget(transaction, allTransactionTagIds[transaction.TransactionId], payeeName, "", len(allTransactionSplits[transaction.TransactionId]) > 0)
+	}
+
+	a.rules.FillOriginalCategoryNames(categoryMap, targets)
+	result.MatchedCount += a.rules.ApplyRules(rules, categoryMap, tagMap, targets)
+
+	changedTransactions := make([]*models.Transaction, 0, len(transactions))
+	changedTargets := make([]*models.TransactionRuleTarget, 0, len(targets))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		target := targets[i]
+
+		if target.CategorySkipped {
+			result.CategorySkippedCount++
+		}
+
+		if target.CategoryId == transaction.CategoryId &&
+			target.Comment == transaction.Comment &&
+			target.HideAmount == transaction.HideAmount &&
+			len(target.TagIds) == len(allTransactionTagIds[transaction.TransactionId]) {
+			continue
+		}
+
+		if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone) {
+			result.FailedCount++
+			continue
+		}
+
+		changedTransactions = append(changedTransactions, transaction)
+		changedTargets = append(changedTargets, target)
+	}
+
+	updatedCount, err := a.transactions.ModifyTransactionsByRuleTargets(c, uid, ledgerId, changedTransactions, changedTargets, allTransactionTagIds, operator)
+
+	if err != nil {
+		return err
+	}
+
+	result.UpdatedCount += updatedCount
+	result.FailedCount += len(changedTransactions) - updatedCount
+
+	return nil
+}
+
+func (a *TransactionRulesApi) checkRule(c *core.WebContext, uid int64, ledgerId int64, rule *models.TransactionRule) error {
+	if !rule.IsTransactionTypeValid() {
+		return errs.ErrTransactionRuleTypeInvalid
+	}
+
+	if !rule.HasConditions() {
+		return errs.ErrTransactionRuleNoConditions
+	}
+
+	if !rule.HasActions() {
+		return errs.ErrTransactionRuleNoActions
+	}
+
+	if rule.CommentRegex != "" {
+		if _, err := regexp.Compile(rule.CommentRegex); err != nil {
+			return errs.ErrTransactionRuleCommentRegexInvalid
+		}
+	}
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	addTagIds := rule.GetAddTagIds()
+
+	if len(addTagIds) > models.MaximumTagsCountOfTransaction {
+		return errs.ErrTransactionRuleHasTooManyTags
+	}
+
+	if rule.SetCategoryId > 0 {
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, ledgerId, rule.SetCategoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId ||
+			(rule.TransactionType == models.TRANSACTION_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+			(rule.TransactionType == models.TRANSACTION_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+			(rule.TransactionType == models.TRANSACTION_TYPE_TRANSFER && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+			return errs.ErrTransactionRuleCategoryTypeInvalid
+		}
+	}
+
+	if rule.AccountId > 0 {
+		_, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, rule.AccountId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(addTagIds) > 0 {
+		tagMap, err := a.transactionTags.GetTagsByTagIds(c, uid, ledgerId, addTagIds)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(addTagIds); i++ {
+			if _, exists := tagMap[addTagIds[i]]; !exists {
+				return errs.ErrTransactionTagNotFound
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *TransactionRulesApi) isAmountBoundEqual(amount1 *int64, amount2 *int64) bool {
+	if amount1 == nil || amount2 == nil {
+		return amount1 == nil && amount2 == nil
+	}
+
+	return *amount1 == *amount2
+}
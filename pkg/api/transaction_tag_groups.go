@@ -24,8 +24,9 @@ var (
 
 // TagGroupListHandler returns transaction tag group list of current user
 func (a *TransactionTagGroupsApi) TagGroupListHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	tagGroups, err := a.tagGroups.GetAllTagGroupsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tagGroups, err := a.tagGroups.GetAllTagGroupsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupListHandler] failed to get tag groups for user \"uid:%d\", because %s", uid, err.Error())
@@ -53,8 +54,9 @@ func (a *TransactionTagGroupsApi) TagGroupGetHandler(c *core.WebContext) (any, *
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagGroupGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, ledgerId, tagGroupGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupGetHandler] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupGetReq.Id, uid, err.Error())
@@ -76,16 +78,17 @@ func (a *TransactionTagGroupsApi) TagGroupCreateHandler(c *core.WebContext) (any
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	maxOrderId, err := a.tagGroups.GetMaxDisplayOrder(c, uid)
+	maxOrderId, err := a.tagGroups.GetMaxDisplayOrder(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tagGroup := a.createNewTagGroupModel(uid, &tagGroupCreateReq, maxOrderId+1)
+	tagGroup := a.createNewTagGroupModel(uid, ledgerId, &tagGroupCreateReq, maxOrderId+1)
 
 	err = a.tagGroups.CreateTagGroup(c, tagGroup)
 
@@ -111,8 +114,9 @@ func (a *TransactionTagGroupsApi) TagGroupModifyHandler(c *core.WebContext) (any
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagGroupModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, ledgerId, tagGroupModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupModifyHandler] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupModifyReq.Id, uid, err.Error())
@@ -122,6 +126,7 @@ func (a *TransactionTagGroupsApi) TagGroupModifyHandler(c *core.WebContext) (any
 	newTagGroup := &models.TransactionTagGroup{
 		TagGroupId: tagGroup.TagGroupId,
 		Uid:        uid,
+		LedgerId:   ledgerId,
 		Name:       tagGroupModifyReq.Name,
 	}
 
@@ -154,13 +159,15 @@ func (a *TransactionTagGroupsApi) TagGroupMoveHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	tagGroups := make([]*models.TransactionTagGroup, len(tagGroupMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(tagGroupMoveReq.NewDisplayOrders); i++ {
 		newDisplayOrder := tagGroupMoveReq.NewDisplayOrders[i]
 		tagGroup := &models.TransactionTagGroup{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			TagGroupId:   newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -168,7 +175,7 @@ func (a *TransactionTagGroupsApi) TagGroupMoveHandler(c *core.WebContext) (any,
 		tagGroups[i] = tagGroup
 	}
 
-	err = a.tagGroups.ModifyTagGroupDisplayOrders(c, uid, tagGroups)
+	err = a.tagGroups.ModifyTagGroupDisplayOrders(c, uid, ledgerId, tagGroups)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupMoveHandler] failed to move tag groups for user \"uid:%d\", because %s", uid, err.Error())
@@ -189,8 +196,9 @@ func (a *TransactionTagGroupsApi) TagGroupDeleteHandler(c *core.WebContext) (any
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.tagGroups.DeleteTagGroup(c, uid, tagGroupDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.tagGroups.DeleteTagGroup(c, uid, ledgerId, tagGroupDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tag_groups.TagGroupDeleteHandler] failed to delete tag group \"id:%d\" for user \"uid:%d\", because %s", tagGroupDeleteReq.Id, uid, err.Error())
@@ -201,9 +209,10 @@ func (a *TransactionTagGroupsApi) TagGroupDeleteHandler(c *core.WebContext) (any
 	return true, nil
 }
 
-func (a *TransactionTagGroupsApi) createNewTagGroupModel(uid int64, tagGroupCreateReq *models.TransactionTagGroupCreateRequest, order int32) *models.TransactionTagGroup {
+func (a *TransactionTagGroupsApi) createNewTagGroupModel(uid int64, ledgerId int64, tagGroupCreateReq *models.TransactionTagGroupCreateRequest, order int32) *models.TransactionTagGroup {
 	return &models.TransactionTagGroup{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         tagGroupCreateReq.Name,
 		DisplayOrder: order,
 	}
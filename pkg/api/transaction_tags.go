@@ -26,8 +26,9 @@ var (
 
 // TagListHandler returns transaction tag list of current user
 func (a *TransactionTagsApi) TagListHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	tags, err := a.tags.GetAllTagsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tags, err := a.tags.GetAllTagsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagListHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -55,8 +56,9 @@ func (a *TransactionTagsApi) TagGetHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	tag, err := a.tags.GetTagByTagId(c, uid, tagGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tag, err := a.tags.GetTagByTagId(c, uid, ledgerId, tagGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagGetHandler] failed to get tag \"id:%d\" for user \"uid:%d\", because %s", tagGetReq.Id, uid, err.Error())
@@ -78,10 +80,11 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	if tagCreateReq.GroupId > 0 {
-		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagCreateReq.GroupId)
+		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, ledgerId, tagCreateReq.GroupId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_tags.TagCreateHandler] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagCreateReq.GroupId, uid, err.Error())
@@ -94,14 +97,14 @@ func (a *TransactionTagsApi) TagCreateHandler(c *core.WebContext) (any, *errs.Er
 		}
 	}
 
-	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, tagCreateReq.GroupId)
+	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, ledgerId, tagCreateReq.GroupId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tag := a.createNewTagModel(uid, &tagCreateReq, maxOrderId+1)
+	tag := a.createNewTagModel(uid, ledgerId, &tagCreateReq, maxOrderId+1)
 
 	err = a.tags.CreateTag(c, tag)
 
@@ -134,10 +137,11 @@ func (a *TransactionTagsApi) TagCreateBatchHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	if tagCreateBatchReq.GroupId > 0 {
-		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagCreateBatchReq.GroupId)
+		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, ledgerId, tagCreateBatchReq.GroupId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_tags.TagCreateBatchHandler] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagCreateBatchReq.GroupId, uid, err.Error())
@@ -150,16 +154,16 @@ func (a *TransactionTagsApi) TagCreateBatchHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, tagCreateBatchReq.GroupId)
+	maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, ledgerId, tagCreateBatchReq.GroupId)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagCreateBatchHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	tags := a.createNewTagModels(uid, &tagCreateBatchReq, maxOrderId+1)
+	tags := a.createNewTagModels(uid, ledgerId, &tagCreateBatchReq, maxOrderId+1)
 
-	err = a.tags.CreateTags(c, uid, tags, tagCreateBatchReq.SkipExists)
+	err = a.tags.CreateTags(c, uid, ledgerId, tags, tagCreateBatchReq.SkipExists)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagCreateBatchHandler] failed to create tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -189,8 +193,9 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	tag, err := a.tags.GetTagByTagId(c, uid, tagModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tag, err := a.tags.GetTagByTagId(c, uid, ledgerId, tagModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagModifyHandler] failed to get tag \"id:%d\" for user \"uid:%d\", because %s", tagModifyReq.Id, uid, err.Error())
@@ -198,7 +203,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 	}
 
 	if tagModifyReq.GroupId != tag.TagGroupId && tagModifyReq.GroupId > 0 {
-		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, tagModifyReq.GroupId)
+		tagGroup, err := a.tagGroups.GetTagGroupByTagGroupId(c, uid, ledgerId, tagModifyReq.GroupId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_tags.TagModifyHandler] failed to get tag group \"id:%d\" for user \"uid:%d\", because %s", tagModifyReq.GroupId, uid, err.Error())
@@ -214,6 +219,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 	newTag := &models.TransactionTag{
 		TagId:        tag.TagId,
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         tagModifyReq.Name,
 		TagGroupId:   tagModifyReq.GroupId,
 		DisplayOrder: tag.DisplayOrder,
@@ -226,7 +232,7 @@ func (a *TransactionTagsApi) TagModifyHandler(c *core.WebContext) (any, *errs.Er
 	}
 
 	if newTag.TagGroupId != tag.TagGroupId {
-		maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, newTag.TagGroupId)
+		maxOrderId, err := a.tags.GetMaxDisplayOrder(c, uid, ledgerId, newTag.TagGroupId)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_tags.TagModifyHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -263,8 +269,9 @@ func (a *TransactionTagsApi) TagHideHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.tags.HideTag(c, uid, []int64{tagHideReq.Id}, tagHideReq.Hidden)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.tags.HideTag(c, uid, ledgerId, []int64{tagHideReq.Id}, tagHideReq.Hidden)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagHideHandler] failed to hide tag \"id:%d\" for user \"uid:%d\", because %s", tagHideReq.Id, uid, err.Error())
@@ -285,13 +292,15 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	tags := make([]*models.TransactionTag, len(tagMoveReq.NewDisplayOrders))
 
 	for i := 0; i < len(tagMoveReq.NewDisplayOrders); i++ {
 		newDisplayOrder := tagMoveReq.NewDisplayOrders[i]
 		tag := &models.TransactionTag{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			TagId:        newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -299,7 +308,7 @@ func (a *TransactionTagsApi) TagMoveHandler(c *core.WebContext) (any, *errs.Erro
 		tags[i] = tag
 	}
 
-	err = a.tags.ModifyTagDisplayOrders(c, uid, tags)
+	err = a.tags.ModifyTagDisplayOrders(c, uid, ledgerId, tags)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagMoveHandler] failed to move tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -320,8 +329,9 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	err = a.tags.DeleteTag(c, uid, tagDeleteReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.tags.DeleteTag(c, uid, ledgerId, tagDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_tags.TagDeleteHandler] failed to delete tag \"id:%d\" for user \"uid:%d\", because %s", tagDeleteReq.Id, uid, err.Error())
@@ -332,21 +342,88 @@ func (a *TransactionTagsApi) TagDeleteHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
-func (a *TransactionTagsApi) createNewTagModel(uid int64, tagCreateReq *models.TransactionTagCreateRequest, order int32) *models.TransactionTag {
+// TagTrashListHandler returns deleted transaction tag list in trash bin of current user
+func (a *TransactionTagsApi) TagTrashListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tags, err := a.tags.GetAllDeletedTagsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.TagTrashListHandler] failed to get deleted tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tagResps := make([]*models.DeletedTransactionTagInfoResponse, len(tags))
+
+	for i := 0; i < len(tags); i++ {
+		tagResps[i] = tags[i].ToDeletedTransactionTagInfoResponse()
+	}
+
+	return tagResps, nil
+}
+
+// TagRestoreHandler restores a deleted transaction tag from trash bin by request parameters for current user
+func (a *TransactionTagsApi) TagRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagRestoreReq models.TransactionTagRestoreRequest
+	err := c.ShouldBindJSON(&tagRestoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tags.TagRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.tags.RestoreTag(c, uid, ledgerId, tagRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.TagRestoreHandler] failed to restore tag \"id:%d\" for user \"uid:%d\", because %s", tagRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tags.TagRestoreHandler] user \"uid:%d\" has restored tag \"id:%d\"", uid, tagRestoreReq.Id)
+	return true, nil
+}
+
+// TagPurgeHandler permanently deletes a deleted transaction tag from trash bin by request parameters for current user
+func (a *TransactionTagsApi) TagPurgeHandler(c *core.WebContext) (any, *errs.Error) {
+	var tagPurgeReq models.TransactionTagPurgeRequest
+	err := c.ShouldBindJSON(&tagPurgeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_tags.TagPurgeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.tags.PurgeTag(c, uid, ledgerId, tagPurgeReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_tags.TagPurgeHandler] failed to purge tag \"id:%d\" for user \"uid:%d\", because %s", tagPurgeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_tags.TagPurgeHandler] user \"uid:%d\" has purged tag \"id:%d\"", uid, tagPurgeReq.Id)
+	return true, nil
+}
+
+func (a *TransactionTagsApi) createNewTagModel(uid int64, ledgerId int64, tagCreateReq *models.TransactionTagCreateRequest, order int32) *models.TransactionTag {
 	return &models.TransactionTag{
 		Uid:          uid,
+		LedgerId:     ledgerId,
 		Name:         tagCreateReq.Name,
 		TagGroupId:   tagCreateReq.GroupId,
 		DisplayOrder: order,
 	}
 }
 
-func (a *TransactionTagsApi) createNewTagModels(uid int64, tagCreateBatchReq *models.TransactionTagCreateBatchRequest, order int32) []*models.TransactionTag {
+func (a *TransactionTagsApi) createNewTagModels(uid int64, ledgerId int64, tagCreateBatchReq *models.TransactionTagCreateBatchRequest, order int32) []*models.TransactionTag {
 	tags := make([]*models.TransactionTag, len(tagCreateBatchReq.Tags))
 
 	for i := 0; i < len(tagCreateBatchReq.Tags); i++ {
 		tagCreateReq := tagCreateBatchReq.Tags[i]
-		tag := a.createNewTagModel(uid, tagCreateReq, order+int32(i))
+		tag := a.createNewTagModel(uid, ledgerId, tagCreateReq, order+int32(i))
 		tag.TagGroupId = tagCreateBatchReq.GroupId
 		tags[i] = tag
 	}
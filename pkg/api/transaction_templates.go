@@ -59,8 +59,9 @@ func (a *TransactionTemplatesApi) TemplateListHandler(c *core.WebContext) (any,
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
 
-	uid := c.GetCurrentUid()
-	templates, err := a.templates.GetAllTemplatesByUid(c, uid, templateListReq.TemplateType)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	templates, err := a.templates.GetAllTemplatesByUid(c, uid, ledgerId, templateListReq.TemplateType)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateListHandler] failed to get templates for user \"uid:%d\", because %s", uid, err.Error())
@@ -89,8 +90,9 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateGetReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateGetHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateGetReq.Id, uid, err.Error())
@@ -107,6 +109,213 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 	return templateResp, nil
 }
 
+// TemplateScheduledPreviewHandler returns the next transactions which would be created by the scheduled transaction template or the specified schedule of current user
+func (a *TransactionTemplatesApi) TemplateScheduledPreviewHandler(c *core.WebContext) (any, *errs.Error) {
+	var previewReq models.TransactionTemplateScheduledPreviewRequest
+	err := c.ShouldBindQuery(&previewReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduledPreviewHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+
+	var template *models.TransactionTemplate
+
+	if previewReq.Id > 0 {
+		template, err = a.templates.GetTemplateByTemplateId(c, uid, ledgerId, previewReq.Id)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_templates.TemplateScheduledPreviewHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", previewReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if template.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+			return nil, errs.ErrTransactionTemplateTypeInvalid
+		}
+	} else {
+		if previewReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || previewReq.ScheduledFrequency == "" {
+			return nil, errs.ErrScheduledTransactionFrequencyInvalid
+		}
+
+		template = &models.TransactionTemplate{
+			TemplateType:               models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+			ScheduledFrequencyType:     previewReq.ScheduledFrequencyType,
+			ScheduledFrequency:         a.getNormalizedFrequencyValue(previewReq.ScheduledFrequencyType, previewReq.ScheduledFrequency),
+			ScheduledTimezoneUtcOffset: previewReq.ScheduledTimezoneUtcOffset,
+		}
+
+		if previewReq.ScheduledStartDate != "" {
+			startTime, err := utils.ParseFromLongDateFirstTime(previewReq.ScheduledStartDate, previewReq.ScheduledTimezoneUtcOffset)
+
+			if err != nil {
+				log.Warnf(c, "[transaction_templates.TemplateScheduledPreviewHandler] failed to parse scheduled start date, because %s", err.Error())
+				return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+			}
+
+			startUnixTime := startTime.Unix()
+			template.ScheduledStartTime = &startUnixTime
+		}
+
+		if previewReq.ScheduledEndDate != "" {
+			endTime, err := utils.ParseFromLongDateLastTime(previewReq.ScheduledEndDate, previewReq.ScheduledTimezoneUtcOffset)
+
+			if err != nil {
+				log.Warnf(c, "[transaction_templates.TemplateScheduledPreviewHandler] failed to parse scheduled end date, because %s", err.Error())
+				return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+			}
+
+			endUnixTime := endTime.Unix()
+			template.ScheduledEndTime = &endUnixTime
+		}
+
+		if template.ScheduledStartTime != nil && template.ScheduledEndTime != nil && *template.ScheduledStartTime > *template.ScheduledEndTime {
+			return nil, errs.ErrScheduledTransactionTemplateStartDataLaterThanEndDate
+		}
+	}
+
+	var overrides []*models.TransactionTemplateOccurrenceOverride
+
+	if template.TemplateId > 0 {
+		overrides, err = a.templates.GetAllOccurrenceOverridesByTemplateId(c, uid, template.TemplateId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_templates.TemplateScheduledPreviewHandler] failed to get skipped or postponed occurrences of template \"id:%d\" for user \"uid:%d\", because %s", template.TemplateId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	occurrencesResp, err := template.GetNextScheduledOccurrences(time.Now().Unix(), previewReq.Count, overrides)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduledPreviewHandler] failed to get next scheduled transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrScheduledTransactionFrequencyInvalid)
+	}
+
+	return occurrencesResp, nil
+}
+
+// TemplateOccurrenceSkipHandler skips one upcoming occurrence of the scheduled transaction template without modifying the template for current user
+func (a *TransactionTemplatesApi) TemplateOccurrenceSkipHandler(c *core.WebContext) (any, *errs.Error) {
+	var skipReq models.TransactionTemplateOccurrenceSkipRequest
+	err := c.ShouldBindJSON(&skipReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrenceSkipHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	template, occurrenceUnixTime, err := a.getUpcomingScheduledOccurrence(c, skipReq.Id, skipReq.Date)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrenceSkipHandler] failed to get occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", skipReq.Date, skipReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	override := &models.TransactionTemplateOccurrenceOverride{
+		Uid:            uid,
+		TemplateId:     template.TemplateId,
+		OccurrenceTime: occurrenceUnixTime,
+		OverrideType:   models.TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_SKIP,
+	}
+
+	err = a.templates.SaveOccurrenceOverride(c, override)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateOccurrenceSkipHandler] failed to skip occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", skipReq.Date, skipReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateOccurrenceSkipHandler] user \"uid:%d\" has skipped occurrence \"%s\" of template \"id:%d\"", uid, skipReq.Date, skipReq.Id)
+	return true, nil
+}
+
+// TemplateOccurrencePostponeHandler postpones one upcoming occurrence of the scheduled transaction template to a later date without modifying the template for current user
+func (a *TransactionTemplatesApi) TemplateOccurrencePostponeHandler(c *core.WebContext) (any, *errs.Error) {
+	var postponeReq models.TransactionTemplateOccurrencePostponeRequest
+	err := c.ShouldBindJSON(&postponeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrencePostponeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	template, occurrenceUnixTime, err := a.getUpcomingScheduledOccurrence(c, postponeReq.Id, postponeReq.Date)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrencePostponeHandler] failed to get occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", postponeReq.Date, postponeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	postponedTime, err := utils.ParseFromLongDateFirstTime(postponeReq.PostponedDate, template.ScheduledTimezoneUtcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrencePostponeHandler] failed to parse postponed date, because %s", err.Error())
+		return nil, errs.ErrScheduledTransactionPostponedDateInvalid
+	}
+
+	postponedUnixTime := postponedTime.Unix()
+
+	if postponedUnixTime <= occurrenceUnixTime || (template.ScheduledEndTime != nil && postponedUnixTime > *template.ScheduledEndTime) {
+		return nil, errs.ErrScheduledTransactionPostponedDateInvalid
+	}
+
+	override := &models.TransactionTemplateOccurrenceOverride{
+		Uid:            uid,
+		TemplateId:     template.TemplateId,
+		OccurrenceTime: occurrenceUnixTime,
+		OverrideType:   models.TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE,
+		PostponedTime:  postponedUnixTime,
+	}
+
+	err = a.templates.SaveOccurrenceOverride(c, override)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateOccurrencePostponeHandler] failed to postpone occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", postponeReq.Date, postponeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateOccurrencePostponeHandler] user \"uid:%d\" has postponed occurrence \"%s\" of template \"id:%d\" to \"%s\"", uid, postponeReq.Date, postponeReq.Id, postponeReq.PostponedDate)
+	return true, nil
+}
+
+// TemplateOccurrenceRestoreHandler restores one skipped or postponed upcoming occurrence of the scheduled transaction template for current user
+func (a *TransactionTemplatesApi) TemplateOccurrenceRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var restoreReq models.TransactionTemplateOccurrenceRestoreRequest
+	err := c.ShouldBindJSON(&restoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrenceRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	template, occurrenceUnixTime, err := a.getUpcomingScheduledOccurrence(c, restoreReq.Id, restoreReq.Date)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateOccurrenceRestoreHandler] failed to get occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", restoreReq.Date, restoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.templates.DeleteOccurrenceOverride(c, uid, template.TemplateId, occurrenceUnixTime)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateOccurrenceRestoreHandler] failed to restore occurrence \"%s\" of template \"id:%d\" for user \"uid:%d\", because %s", restoreReq.Date, restoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateOccurrenceRestoreHandler] user \"uid:%d\" has restored occurrence \"%s\" of template \"id:%d\"", uid, restoreReq.Date, restoreReq.Id)
+	return true, nil
+}
+
 // TemplateCreateHandler saves a new transaction template by request parameters for current user
 func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any, *errs.Error) {
 	var templateCreateReq models.TransactionTemplateCreateRequest
@@ -143,15 +352,22 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 		} else if *templateCreateReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateCreateReq.ScheduledFrequency == "" {
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
+
+		if *templateCreateReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+			if _, err := models.ParseTransactionScheduleRecurrenceRule(*templateCreateReq.ScheduledFrequency); err != nil {
+				return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+			}
+		}
 	}
 
 	if len(templateCreateReq.TagIds) > maximumTagsCountOfTemplate {
 		return nil, errs.ErrTransactionTemplateHasTooManyTags
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, templateCreateReq.TemplateType)
+	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, ledgerId, templateCreateReq.TemplateType)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
@@ -159,7 +375,7 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 	}
 
 	serverUtcOffset := utils.GetServerTimezoneOffsetMinutes()
-	template, err := a.createNewTemplateModel(uid, &templateCreateReq, maxOrderId+1)
+	template, err := a.createNewTemplateModel(uid, ledgerId, &templateCreateReq, maxOrderId+1)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to create new template for user \"uid:%d\", because %s", uid, err.Error())
@@ -174,7 +390,7 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 			templateId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				template, err = a.templates.GetTemplateByTemplateId(c, uid, templateId)
+				template, err = a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateId)
 
 				if err != nil {
 					log.Errorf(c, "[transaction_templates.TemplateCreateHandler] failed to get existed template \"id:%d\" for user \"uid:%d\", because %s", templateId, uid, err.Error())
@@ -218,8 +434,9 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		return nil, errs.ErrTransactionTypeInvalid
 	}
 
-	uid := c.GetCurrentUid()
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateModifyReq.Id)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateModifyHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateModifyReq.Id, uid, err.Error())
@@ -230,6 +447,11 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
 
+	if template.AmortizationScheduleId > 0 {
+		log.Warnf(c, "[transaction_templates.TemplateModifyHandler] template \"id:%d\" is managed by amortization schedule \"id:%d\"", template.TemplateId, template.AmortizationScheduleId)
+		return nil, errs.ErrTransactionTemplateManagedByAmortizationSchedule
+	}
+
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
 		if templateModifyReq.ScheduledFrequencyType == nil ||
 			templateModifyReq.ScheduledFrequency == nil ||
@@ -242,6 +464,12 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		} else if *templateModifyReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateModifyReq.ScheduledFrequency == "" {
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
+
+		if *templateModifyReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+			if _, err := models.ParseTransactionScheduleRecurrenceRule(*templateModifyReq.ScheduledFrequency); err != nil {
+				return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+			}
+		}
 	}
 
 	if len(templateModifyReq.TagIds) > maximumTagsCountOfTemplate {
@@ -251,6 +479,7 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 	newTemplate := &models.TransactionTemplate{
 		TemplateId:           template.TemplateId,
 		Uid:                  uid,
+		LedgerId:             ledgerId,
 		Name:                 templateModifyReq.Name,
 		Type:                 templateModifyReq.Type,
 		CategoryId:           templateModifyReq.CategoryId,
@@ -265,7 +494,7 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
 		newTemplate.ScheduledFrequencyType = *templateModifyReq.ScheduledFrequencyType
-		newTemplate.ScheduledFrequency = a.getOrderedFrequencyValues(*templateModifyReq.ScheduledFrequency)
+		newTemplate.ScheduledFrequency = a.getNormalizedFrequencyValue(*templateModifyReq.ScheduledFrequencyType, *templateModifyReq.ScheduledFrequency)
 		newTemplate.ScheduledAt = a.getUTCScheduledAt(*templateModifyReq.ScheduledTimezoneUtcOffset)
 		newTemplate.ScheduledTimezoneUtcOffset = *templateModifyReq.ScheduledTimezoneUtcOffset
 
@@ -350,9 +579,10 @@ func (a *TransactionTemplatesApi) TemplateHideHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateHideReq.Id)
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateHideReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateHideHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateHideReq.Id, uid, err.Error())
@@ -363,7 +593,7 @@ func (a *TransactionTemplatesApi) TemplateHideHandler(c *core.WebContext) (any,
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
 
-	err = a.templates.HideTemplate(c, uid, []int64{templateHideReq.Id}, templateHideReq.Hidden)
+	err = a.templates.HideTemplate(c, uid, ledgerId, []int64{templateHideReq.Id}, templateHideReq.Hidden)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateHideHandler] failed to hide template \"id:%d\" for user \"uid:%d\", because %s", templateHideReq.Id, uid, err.Error())
@@ -384,10 +614,11 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	if len(templateMoveReq.NewDisplayOrders) > 0 {
-		template, err := a.templates.GetTemplateByTemplateId(c, uid, templateMoveReq.NewDisplayOrders[0].Id)
+		template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateMoveReq.NewDisplayOrders[0].Id)
 
 		if err != nil {
 			log.Errorf(c, "[transaction_templates.TemplateMoveHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateMoveReq.NewDisplayOrders[0].Id, uid, err.Error())
@@ -405,6 +636,7 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 		newDisplayOrder := templateMoveReq.NewDisplayOrders[i]
 		template := &models.TransactionTemplate{
 			Uid:          uid,
+			LedgerId:     ledgerId,
 			TemplateId:   newDisplayOrder.Id,
 			DisplayOrder: newDisplayOrder.DisplayOrder,
 		}
@@ -412,7 +644,7 @@ func (a *TransactionTemplatesApi) TemplateMoveHandler(c *core.WebContext) (any,
 		templates[i] = template
 	}
 
-	err = a.templates.ModifyTemplateDisplayOrders(c, uid, templates)
+	err = a.templates.ModifyTemplateDisplayOrders(c, uid, ledgerId, templates)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateMoveHandler] failed to move templates for user \"uid:%d\", because %s", uid, err.Error())
@@ -433,9 +665,10 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateDeleteReq.Id)
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateDeleteHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", templateDeleteReq.Id, uid, err.Error())
@@ -446,7 +679,12 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 		return nil, errs.ErrScheduledTransactionNotEnabled
 	}
 
-	err = a.templates.DeleteTemplate(c, uid, templateDeleteReq.Id)
+	if template.AmortizationScheduleId > 0 {
+		log.Warnf(c, "[transaction_templates.TemplateDeleteHandler] template \"id:%d\" is managed by amortization schedule \"id:%d\"", template.TemplateId, template.AmortizationScheduleId)
+		return nil, errs.ErrTransactionTemplateManagedByAmortizationSchedule
+	}
+
+	err = a.templates.DeleteTemplate(c, uid, ledgerId, templateDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transaction_templates.TemplateDeleteHandler] failed to delete template \"id:%d\" for user \"uid:%d\", because %s", templateDeleteReq.Id, uid, err.Error())
@@ -457,9 +695,10 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 	return true, nil
 }
 
-func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
+func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, ledgerId int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
 	template := &models.TransactionTemplate{
 		Uid:                  uid,
+		LedgerId:             ledgerId,
 		TemplateType:         templateCreateReq.TemplateType,
 		Name:                 templateCreateReq.Name,
 		Type:                 templateCreateReq.Type,
@@ -476,7 +715,7 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 
 	if templateCreateReq.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
 		template.ScheduledFrequencyType = *templateCreateReq.ScheduledFrequencyType
-		template.ScheduledFrequency = a.getOrderedFrequencyValues(*templateCreateReq.ScheduledFrequency)
+		template.ScheduledFrequency = a.getNormalizedFrequencyValue(*templateCreateReq.ScheduledFrequencyType, *templateCreateReq.ScheduledFrequency)
 		template.ScheduledAt = a.getUTCScheduledAt(*templateCreateReq.ScheduledTimezoneUtcOffset)
 		template.ScheduledTimezoneUtcOffset = *templateCreateReq.ScheduledTimezoneUtcOffset
 
@@ -510,6 +749,51 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 	return template, nil
 }
 
+func (a *TransactionTemplatesApi) getUpcomingScheduledOccurrence(c *core.WebContext, templateId int64, date string) (*models.TransactionTemplate, int64, error) {
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, 0, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, ledgerId, templateId)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if template.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		return nil, 0, errs.ErrTransactionTemplateTypeInvalid
+	}
+
+	if template.AmortizationScheduleId > 0 {
+		return nil, 0, errs.ErrTransactionTemplateManagedByAmortizationSchedule
+	}
+
+	occurrenceTime, err := utils.ParseFromLongDateFirstTime(date, template.ScheduledTimezoneUtcOffset)
+
+	if err != nil {
+		return nil, 0, errs.ErrScheduledTransactionOccurrenceNotFound
+	}
+
+	occurrenceUnixTime := occurrenceTime.Unix()
+
+	// the transaction of the occurrence is created at the beginning of the day, so only the occurrences after now can be changed
+	if occurrenceUnixTime <= time.Now().Unix() || occurrenceUnixTime <= template.ScheduledLastOccurrenceTime {
+		return nil, 0, errs.ErrScheduledTransactionOccurrenceNotFound
+	}
+
+	transactionUnixTimes, err := template.GetScheduledTransactionTimes(occurrenceUnixTime, occurrenceUnixTime)
+
+	if err != nil {
+		return nil, 0, err
+	} else if len(transactionUnixTimes) < 1 {
+		return nil, 0, errs.ErrScheduledTransactionOccurrenceNotFound
+	}
+
+	return template, occurrenceUnixTime, nil
+}
+
 func (a *TransactionTemplatesApi) getUTCScheduledAt(scheduledTimezoneUtcOffset int16) int16 {
 	templateTimeZone := time.FixedZone("Template Timezone", int(scheduledTimezoneUtcOffset)*60)
 	transactionTime := time.Date(2020, 1, 1, 0, 0, 0, 0, templateTimeZone)
@@ -520,6 +804,14 @@ func (a *TransactionTemplatesApi) getUTCScheduledAt(scheduledTimezoneUtcOffset i
 	return int16(minutesElapsedOfDayInUtc)
 }
 
+func (a *TransactionTemplatesApi) getNormalizedFrequencyValue(frequencyType models.TransactionScheduleFrequencyType, frequencyValue string) string {
+	if frequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+		return strings.TrimSpace(frequencyValue)
+	}
+
+	return a.getOrderedFrequencyValues(frequencyValue)
+}
+
 func (a *TransactionTemplatesApi) getOrderedFrequencyValues(frequencyValue string) string {
 	if frequencyValue == "" {
 		return ""
@@ -17,8 +17,10 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/securityprices"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -30,12 +32,19 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionPictures   *services.TransactionPictureService
-	accounts              *services.AccountService
-	users                 *services.UserService
+	transactions           *services.TransactionService
+	transactionCategories  *services.TransactionCategoryService
+	transactionTags        *services.TransactionTagService
+	transactionSplits      *services.TransactionSplitService
+	transactionPictures    *services.TransactionPictureService
+	transactionHistories   *services.TransactionHistoryService
+	transactionRules       *services.TransactionRuleService
+	payees                 *services.PayeeService
+	investmentTransactions *services.InvestmentTransactionService
+	netWorthSnapshots      *services.NetWorthSnapshotService
+	cashFlowForecasts      *services.CashFlowForecastService
+	accounts               *services.AccountService
+	users                  *services.UserService
 }
 
 // Initialize a transaction api singleton instance
@@ -50,12 +59,19 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionPictures:   services.TransactionPictures,
-		accounts:              services.Accounts,
-		users:                 services.Users,
+		transactions:           services.Transactions,
+		transactionCategories:  services.TransactionCategories,
+		transactionTags:        services.TransactionTags,
+		transactionSplits:      services.TransactionSplits,
+		transactionPictures:    services.TransactionPictures,
+		transactionHistories:   services.TransactionHistories,
+		transactionRules:       services.TransactionRules,
+		payees:                 services.Payees,
+		investmentTransactions: services.InvestmentTransactions,
+		netWorthSnapshots:      services.NetWorthSnapshots,
+		cashFlowForecasts:      services.CashFlowForecasts,
+		accounts:               services.Accounts,
+		users:                  services.Users,
 	}
 )
 
@@ -69,16 +85,17 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionCountReq.AccountIds, uid)
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionCountReq.AccountIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionCountHandler] get account error, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionCountReq.CategoryIds, uid)
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionCountReq.CategoryIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionCountHandler] get transaction category error, because %s", err.Error())
@@ -97,7 +114,7 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		}
 	}
 
-	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
+	totalCount, err := a.transactions.GetTransactionCount(c, uid, ledgerId, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCountHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -128,7 +145,8 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -139,14 +157,14 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		return nil, errs.ErrUserNotFound
 	}
 
-	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionListReq.AccountIds, uid)
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionListReq.AccountIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionListHandler] get account error, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionListReq.CategoryIds, uid)
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionListReq.CategoryIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionListHandler] get transaction category error, because %s", err.Error())
@@ -168,7 +186,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 	var totalCount int64
 
 	if transactionListReq.WithCount {
-		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
+		totalCount, err = a.transactions.GetTransactionCount(c, uid, ledgerId, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -176,7 +194,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, ledgerId, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transactions earlier than \"%d\" for user \"uid:%d\", because %s", transactionListReq.MaxTime, uid, err.Error())
@@ -192,7 +210,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		transactions = transactions[:transactionListReq.Count]
 	}
 
-	accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, err := a.getTransactionEssentialDataByTransactionIds(c, user, transactions, transactionListReq.WithPictures, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, err := a.getTransactionEssentialDataByTransactionIds(c, user, transactions, transactionListReq.WithPictures, transactionListReq.TrimCategory, transactionListReq.TrimTag)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get essential data for assembling transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -200,7 +218,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 	}
 
 	transactions = a.filterTransactions(c, uid, transactions, accountMap)
-	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, clientTimezone, transactionListReq.WithPictures, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, clientTimezone, transactionListReq.WithPictures, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -239,7 +257,8 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -250,14 +269,14 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		return nil, errs.ErrUserNotFound
 	}
 
-	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionListReq.AccountIds, uid)
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionListReq.AccountIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionMonthListHandler] get account error, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionListReq.CategoryIds, uid)
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionListReq.CategoryIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionMonthListHandler] get transaction category error, because %s", err.Error())
@@ -276,14 +295,14 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
+	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, ledgerId, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionListReq.AmountFilter, transactionListReq.Keyword)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get transactions in month \"%d-%d\" for user \"uid:%d\", because %s", transactionListReq.Year, transactionListReq.Month, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, err := a.getTransactionEssentialDataByTransactionIds(c, user, transactions, transactionListReq.WithPictures, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, err := a.getTransactionEssentialDataByTransactionIds(c, user, transactions, transactionListReq.WithPictures, transactionListReq.TrimCategory, transactionListReq.TrimTag)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get essential data for assembling transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -291,7 +310,7 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 	}
 
 	transactions = a.filterTransactions(c, uid, transactions, accountMap)
-	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, clientTimezone, transactionListReq.WithPictures, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
+	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, clientTimezone, transactionListReq.WithPictures, transactionListReq.TrimAccount, transactionListReq.TrimCategory, transactionListReq.TrimTag)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -323,7 +342,8 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -334,14 +354,14 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		return nil, errs.ErrUserNotFound
 	}
 
-	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionAllListReq.AccountIds, uid)
+	allAccountIds, err := a.accounts.GetAccountOrSubAccountIds(c, transactionAllListReq.AccountIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionListAllHandler] get account error, because %s", err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionAllListReq.CategoryIds, uid)
+	allCategoryIds, err := a.transactionCategories.GetCategoryOrSubCategoryIds(c, transactionAllListReq.CategoryIds, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[transactions.TransactionListAllHandler] get transaction category error, because %s", err.Error())
@@ -371,7 +391,7 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionAllListReq.StartTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, transactionAllListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionAllListReq.AmountFilter, transactionAllListReq.Keyword, pageCountForDataExport, true)
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionAllListReq.Type, allCategoryIds, allAccountIds, tagFilters, noTags, transactionAllListReq.AmountFilter, transactionAllListReq.Keyword, pageCountForDataExport, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListAllHandler] failed to get all transactions for user \"uid:%d\", because %s", uid, err.Error())
@@ -382,12 +402,13 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var allTransactionTagIds map[int64][]int64
+	var allTransactionSplits map[int64][]*models.TransactionSplit
 	var pictureInfoMap map[int64][]*models.TransactionPictureInfo
 
 	if minTransactionTime == 0 && maxTransactionTime == math.MaxInt64 && len(allCategoryIds) < 1 && len(allAccountIds) < 1 && len(tagFilters) < 1 && transactionAllListReq.AmountFilter == "" && transactionAllListReq.Keyword == "" {
-		accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, err = a.getTransactionAllEssentialData(c, user, transactionAllListReq.WithPictures, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
+		accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, err = a.getTransactionAllEssentialData(c, user, transactionAllListReq.WithPictures, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
 	} else {
-		accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, err = a.getTransactionEssentialDataByTransactionIds(c, user, allTransactions, transactionAllListReq.WithPictures, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
+		accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, err = a.getTransactionEssentialDataByTransactionIds(c, user, allTransactions, transactionAllListReq.WithPictures, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
 	}
 
 	if err != nil {
@@ -396,7 +417,7 @@ func (a *TransactionsApi) TransactionListAllHandler(c *core.WebContext) (any, *e
 	}
 
 	allTransactions = a.filterTransactions(c, uid, allTransactions, accountMap)
-	transactionResult, err := a.getTransactionResponseListResult(c, user, allTransactions, accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, clientTimezone, transactionAllListReq.WithPictures, transactionAllListReq.TrimAccount, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
+	transactionResult, err := a.getTransactionResponseListResult(c, user, allTransactions, accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, clientTimezone, transactionAllListReq.WithPictures, transactionAllListReq.TrimAccount, transactionAllListReq.TrimCategory, transactionAllListReq.TrimTag)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListAllHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -423,7 +444,8 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -434,7 +456,7 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		return nil, errs.ErrUserNotFound
 	}
 
-	account, err := a.accounts.GetAccountByAccountId(c, uid, reconciliationStatementRequest.AccountId)
+	account, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, reconciliationStatementRequest.AccountId)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionReconciliationStatementHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", reconciliationStatementRequest.AccountId, uid, err.Error())
@@ -458,7 +480,7 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(reconciliationStatementRequest.StartTime)
 	}
 
-	transactionsWithAccountBalance, totalInflows, totalOutflows, openingBalance, closingBalance, err := a.transactions.GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c, uid, pageCountForAccountStatement, maxTransactionTime, minTransactionTime, reconciliationStatementRequest.AccountId, account.Category)
+	transactionsWithAccountBalance, totalInflows, totalOutflows, openingBalance, closingBalance, err := a.transactions.GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c, uid, ledgerId, pageCountForAccountStatement, maxTransactionTime, minTransactionTime, reconciliationStatementRequest.AccountId, account.Category)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionReconciliationStatementHandler] failed to get transactions from \"%d\" to \"%d\" for user \"uid:%d\", because %s", reconciliationStatementRequest.StartTime, reconciliationStatementRequest.EndTime, uid, err.Error())
@@ -485,14 +507,14 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 		}
 	}
 
-	allAccounts, err := a.accounts.GetAccountsByAccountIds(c, uid, utils.ToUniqueInt64Slice(allAccountIds))
+	allAccounts, err := a.accounts.GetAccountsByAccountIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(allAccountIds))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionReconciliationStatementHandler] failed to get essential data for assembling transaction result for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, allAccounts, nil, nil, nil, nil, clientTimezone, false, true, true, true)
+	transactionResult, err := a.getTransactionResponseListResult(c, user, transactions, allAccounts, nil, nil, nil, nil, nil, clientTimezone, false, true, true, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionReconciliationStatementHandler] failed to assemble transaction result for user \"uid:%d\", because %s", uid, err.Error())
@@ -531,6 +553,90 @@ func (a *TransactionsApi) TransactionReconciliationStatementHandler(c *core.WebC
 	return reconciliationStatementResp, nil
 }
 
+// TransactionReconciliationStatusSetHandler sets the reconciliation status of transactions in one account for current user
+func (a *TransactionsApi) TransactionReconciliationStatusSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var statusSetReq models.TransactionReconciliationStatusSetRequest
+	err := c.ShouldBindJSON(&statusSetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationStatusSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(statusSetReq.Ids)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconciliationStatusSetHandler] parse transaction ids failed, because %s", err.Error())
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, statusSetReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationStatusSetHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", statusSetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[transactions.TransactionReconciliationStatusSetHandler] account \"id:%d\" for user \"uid:%d\" is not a single account", statusSetReq.AccountId, uid)
+		return nil, errs.ErrAccountTypeInvalid
+	}
+
+	err = a.transactions.SetTransactionsReconciliationStatus(c, uid, ledgerId, statusSetReq.AccountId, transactionIds, statusSetReq.Status, statusSetReq.Unlock)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconciliationStatusSetHandler] failed to set reconciliation status of transactions in account \"id:%d\" for user \"uid:%d\", because %s", statusSetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionReconciliationStatusSetHandler] user \"uid:%d\" has set reconciliation status of %d transactions in account \"id:%d\" to %s", uid, len(transactionIds), statusSetReq.AccountId, statusSetReq.Status)
+	return true, nil
+}
+
+// TransactionReconcileHandler marks all cleared transactions in one account up to the statement time as reconciled for current user
+func (a *TransactionsApi) TransactionReconcileHandler(c *core.WebContext) (any, *errs.Error) {
+	var reconcileReq models.TransactionReconcileRequest
+	err := c.ShouldBindJSON(&reconcileReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionReconcileHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, ledgerId, reconcileReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconcileHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", reconcileReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[transactions.TransactionReconcileHandler] account \"id:%d\" for user \"uid:%d\" is not a single account", reconcileReq.AccountId, uid)
+		return nil, errs.ErrAccountTypeInvalid
+	}
+
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(reconcileReq.StatementTime)
+	reconciledCount, clearedBalance, err := a.transactions.ReconcileTransactions(c, uid, ledgerId, reconcileReq.AccountId, maxTransactionTime, reconcileReq.EndingBalance)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionReconcileHandler] failed to reconcile transactions in account \"id:%d\" for user \"uid:%d\", because %s", reconcileReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionReconcileHandler] user \"uid:%d\" has reconciled %d transactions in account \"id:%d\"", uid, reconciledCount, reconcileReq.AccountId)
+
+	reconcileResp := &models.TransactionReconcileResponse{
+		ReconciledCount: reconciledCount,
+		ClearedBalance:  clearedBalance,
+	}
+
+	return reconcileResp, nil
+}
+
 // TransactionStatisticsHandler returns transaction statistics of current user
 func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
 	var statisticReq models.TransactionStatisticRequest
@@ -560,8 +666,21 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 		}
 	}
 
-	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	var amountConverter models.TransactionAmountConverter
+	var exchangeRatesConversion *models.ExchangeRatesConversionResponse
+
+	if statisticReq.IsAmountConversionRequired() {
+		amountConverter, exchangeRatesConversion, err = exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), statisticReq.TargetCurrency, statisticReq.UseHistoricalExchangeRates, statisticReq.StartTime, statisticReq.EndTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, ledgerId, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, clientTimezone, statisticReq.UseTransactionTimezone, statisticReq.GroupByPayee, amountConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -573,6 +692,13 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 		EndTime:   statisticReq.EndTime,
 	}
 
+	if exchangeRatesConversion != nil {
+		totalIncomeAmount, totalExpenseAmount := models.GetTotalIncomeAndExpenseAmounts(totalAmounts)
+		statisticResp.TotalIncomeAmount = &totalIncomeAmount
+		statisticResp.TotalExpenseAmount = &totalExpenseAmount
+		statisticResp.ExchangeRates = exchangeRatesConversion
+	}
+
 	statisticResp.Items = make([]*models.TransactionStatisticResponseItem, len(totalAmounts))
 
 	for i := 0; i < len(totalAmounts); i++ {
@@ -580,9 +706,14 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 		statisticResp.Items[i] = &models.TransactionStatisticResponseItem{
 			CategoryId:  totalAmountItem.CategoryId,
 			AccountId:   totalAmountItem.AccountId,
+			PayeeId:     totalAmountItem.PayeeId,
 			TotalAmount: totalAmountItem.Amount,
 		}
 
+		if exchangeRatesConversion != nil {
+			statisticResp.Items[i].Currency = exchangeRatesConversion.Currency
+		}
+
 		if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 			statisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 			statisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
@@ -628,8 +759,28 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 		}
 	}
 
-	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	var amountConverter models.TransactionAmountConverter
+	var exchangeRatesConversion *models.ExchangeRatesConversionResponse
+
+	if statisticTrendsReq.IsAmountConversionRequired() {
+		startTime, endTime, err := getUnixTimeRangeByYearMonthRange(startYear, startMonth, endYear, endMonth)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionStatisticsTrendsHandler] cannot get time range of year month, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		amountConverter, exchangeRatesConversion, err = exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), statisticTrendsReq.TargetCurrency, statisticTrendsReq.UseHistoricalExchangeRates, startTime, endTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, ledgerId, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, clientTimezone, statisticTrendsReq.UseTransactionTimezone, amountConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -645,6 +796,13 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 			Items: make([]*models.TransactionStatisticResponseItem, len(monthlyTotalAmounts)),
 		}
 
+		if exchangeRatesConversion != nil {
+			totalIncomeAmount, totalExpenseAmount := models.GetTotalIncomeAndExpenseAmounts(monthlyTotalAmounts)
+			monthlyStatisticResp.TotalIncomeAmount = &totalIncomeAmount
+			monthlyStatisticResp.TotalExpenseAmount = &totalExpenseAmount
+			monthlyStatisticResp.ExchangeRates = exchangeRatesConversion
+		}
+
 		for i := 0; i < len(monthlyTotalAmounts); i++ {
 			totalAmountItem := monthlyTotalAmounts[i]
 			monthlyStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
@@ -653,6 +811,10 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 				TotalAmount: totalAmountItem.Amount,
 			}
 
+			if exchangeRatesConversion != nil {
+				monthlyStatisticResp.Items[i].Currency = exchangeRatesConversion.Currency
+			}
+
 			if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 				monthlyStatisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 				monthlyStatisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
@@ -684,7 +846,8 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	maxTransactionTime := int64(0)
 
@@ -698,13 +861,42 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(statisticAssetTrendsReq.StartTime)
 	}
 
-	accountDailyBalances, err := a.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, maxTransactionTime, minTransactionTime, clientTimezone)
+	accountDailyBalances, err := a.transactions.GetAllAccountsDailyOpeningAndClosingBalance(c, uid, ledgerId, maxTransactionTime, minTransactionTime, clientTimezone)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get transactions from \"%d\" to \"%d\" for user \"uid:%d\", because %s", statisticAssetTrendsReq.StartTime, statisticAssetTrendsReq.EndTime, uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	investmentTransactions, err := a.investmentTransactions.GetAllTransactionsByUid(c, uid, ledgerId, 0, "")
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get investment transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	dailyInvestmentValuations, err := a.getDailyInvestmentAccountValuations(c, uid, investmentTransactions, accountDailyBalances, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to calculate investment valuations for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var amountConverter models.TransactionAmountConverter
+	var amountCurrency string
+
+	if statisticAssetTrendsReq.UseHistoricalExchangeRates {
+		var exchangeRatesConversion *models.ExchangeRatesConversionResponse
+		amountConverter, exchangeRatesConversion, err = exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), "", true, statisticAssetTrendsReq.StartTime, statisticAssetTrendsReq.EndTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		amountCurrency = exchangeRatesConversion.Currency
+	}
+
 	statisticAssetTrendsResp := make(models.TransactionStatisticAssetTrendsResponseItemSlice, 0)
 
 	for yearMonthDay, dailyAccountBalances := range accountDailyBalances {
@@ -722,6 +914,20 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 				AccountOpeningBalance: accountBalance.AccountOpeningBalance,
 				AccountClosingBalance: accountBalance.AccountClosingBalance,
 			}
+
+			if valuation, exists := dailyInvestmentValuations[yearMonthDay][accountBalance.AccountId]; exists {
+				dailyStatisticResp.Items[i].MarketValue = valuation.MarketValue
+				dailyStatisticResp.Items[i].UnrealizedGain = valuation.UnrealizedGain
+			}
+
+			if amountConverter != nil {
+				dataItem := dailyStatisticResp.Items[i]
+				dataItem.AccountOpeningBalance = amountConverter(dataItem.AccountId, yearMonthDay, dataItem.AccountOpeningBalance)
+				dataItem.AccountClosingBalance = amountConverter(dataItem.AccountId, yearMonthDay, dataItem.AccountClosingBalance)
+				dataItem.MarketValue = amountConverter(dataItem.AccountId, yearMonthDay, dataItem.MarketValue)
+				dataItem.UnrealizedGain = amountConverter(dataItem.AccountId, yearMonthDay, dataItem.UnrealizedGain)
+				dataItem.Currency = amountCurrency
+			}
 		}
 
 		statisticAssetTrendsResp = append(statisticAssetTrendsResp, dailyStatisticResp)
@@ -732,6 +938,128 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 	return statisticAssetTrendsResp, nil
 }
 
+// TransactionStatisticsNetWorthTrendsHandler returns the monthly net worth trends of current user according to the net worth snapshots
+func (a *TransactionsApi) TransactionStatisticsNetWorthTrendsHandler(c *core.WebContext) (any, *errs.Error) {
+	var netWorthTrendsReq models.TransactionStatisticNetWorthTrendsRequest
+	err := c.ShouldBindQuery(&netWorthTrendsReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	startYear, startMonth, endYear, endMonth, err := netWorthTrendsReq.GetNumericYearMonthRange()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] cannot parse year month, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	now := time.Now().Unix()
+	startYearMonth := startYear*100 + startMonth
+	endYearMonth := endYear*100 + endMonth
+	lastSnapshotYearMonth := models.GetNetWorthSnapshotLastYearMonth(now)
+	currentYearMonth := utils.AddMonthsToNumericYearMonth(lastSnapshotYearMonth, 1)
+
+	err = a.netWorthSnapshots.UpdateSnapshots(c, uid, ledgerId, lastSnapshotYearMonth, exchangerates.Container.GetNetWorthSnapshotAmountConverterGetter(a.CurrentConfig()))
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] failed to update net worth snapshots for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxSnapshotYearMonth := lastSnapshotYearMonth
+
+	if endYearMonth > 0 && endYearMonth < maxSnapshotYearMonth {
+		maxSnapshotYearMonth = endYearMonth
+	}
+
+	snapshots, err := a.netWorthSnapshots.GetSnapshotsByYearMonthRange(c, uid, ledgerId, startYearMonth, maxSnapshotYearMonth)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] failed to get net worth snapshots for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	amountConverter, exchangeRatesConversion, err := exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, a.CurrentConfig(), "", true, now, now)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsNetWorthTrendsHandler] failed to get exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	// the current month has not ended yet, so its net worth is calculated from the current account balances
+	if startYearMonth <= currentYearMonth && (endYearMonth <= 0 || endYearMonth >= currentYearMonth) {
+		today := utils.FormatUnixTimeToNumericYearMonthDay(now, time.Local)
+
+		for i := 0; i < len(accounts); i++ {
+			account := accounts[i]
+
+			if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || account.Balance == 0 {
+				continue
+			}
+
+			snapshots = append(snapshots, &models.NetWorthSnapshot{
+				Uid:                             uid,
+				LedgerId:                        ledgerId,
+				YearMonth:                       currentYearMonth,
+				AccountId:                       account.AccountId,
+				Currency:                        account.Currency,
+				ClosingBalance:                  account.Balance,
+				DefaultCurrency:                 exchangeRatesConversion.Currency,
+				ClosingBalanceInDefaultCurrency: amountConverter(account.AccountId, today, account.Balance),
+			})
+		}
+	}
+
+	netWorthTrendsResp := &models.TransactionStatisticNetWorthTrendsResponse{
+		Currency:      exchangeRatesConversion.Currency,
+		Items:         models.ToTransactionStatisticNetWorthTrendsResponseItems(snapshots, a.accounts.GetAccountMapByList(accounts)),
+		ExchangeRates: exchangeRatesConversion,
+	}
+
+	return netWorthTrendsResp, nil
+}
+
+// TransactionCashFlowForecastHandler returns the projected daily balances of all accounts of current user
+func (a *TransactionsApi) TransactionCashFlowForecastHandler(c *core.WebContext) (any, *errs.Error) {
+	var forecastReq models.TransactionCashFlowForecastRequest
+	err := c.ShouldBindQuery(&forecastReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCashFlowForecastHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCashFlowForecastHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+
+	forecastResp, err := a.cashFlowForecasts.GetCashFlowForecast(c, uid, ledgerId, time.Now().Unix(), forecastReq.Months, forecastReq.IncludeHistoricalAverage, forecastReq.HistoricalMonths, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionCashFlowForecastHandler] failed to get cash flow forecast of %d months for user \"uid:%d\", because %s", forecastReq.Months, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return forecastResp, nil
+}
+
 // TransactionAmountsHandler returns transaction amounts of current user
 func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *errs.Error) {
 	var transactionAmountsReq models.TransactionAmountsRequest
@@ -785,9 +1113,10 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 	accountMap := a.accounts.GetAccountMapByList(accounts)
 
 	if err != nil {
@@ -800,7 +1129,7 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 	for i := 0; i < len(requestItems); i++ {
 		requestItem := requestItems[i]
 
-		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, uid, requestItem.StartTime, requestItem.EndTime, excludeAccountIds, excludeCategoryIds, clientTimezone, transactionAmountsReq.UseTransactionTimezone)
+		incomeAmounts, expenseAmounts, err := a.transactions.GetAccountsTotalIncomeAndExpense(c, uid, ledgerId, requestItem.StartTime, requestItem.EndTime, excludeAccountIds, excludeCategoryIds, clientTimezone, transactionAmountsReq.UseTransactionTimezone)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionAmountsHandler] failed to get transaction amounts item for user \"uid:%d\", because %s", uid, err.Error())
@@ -888,7 +1217,8 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -899,7 +1229,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionGetReq.Id)
+	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, ledgerId, transactionGetReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionGetReq.Id, uid, err.Error())
@@ -918,7 +1248,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		accountIds = utils.ToUniqueInt64Slice(accountIds)
 	}
 
-	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, ledgerId, accountIds)
 
 	if _, exists := accountMap[transaction.AccountId]; !exists {
 		log.Warnf(c, "[transactions.TransactionGetHandler] account of transaction \"id:%d\" does not exist for user \"uid:%d\"", transaction.TransactionId, uid)
@@ -939,21 +1269,40 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionSplits := allTransactionSplits[transaction.TransactionId]
+
 	var category *models.TransactionCategory
+	var splitCategoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfos []*models.TransactionPictureInfo
 
 	if !transactionGetReq.TrimCategory {
-		category, err = a.transactionCategories.GetCategoryByCategoryId(c, uid, transaction.CategoryId)
+		category, err = a.transactionCategories.GetCategoryByCategoryId(c, uid, ledgerId, transaction.CategoryId)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions category for user \"uid:%d\", because %s", uid, err.Error())
 			return nil, errs.Or(err, errs.ErrOperationFailed)
 		}
+
+		if len(transactionSplits) > 0 {
+			splitCategoryMap, err = a.transactionCategories.GetCategoriesByCategoryIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(a.transactionSplits.GetTransactionSplitCategoryIds(allTransactionSplits)))
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions split categories for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+		}
 	}
 
 	if !transactionGetReq.TrimTag {
-		tagMap, err = a.transactionTags.GetTagsByTagIds(c, uid, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
+		tagMap, err = a.transactionTags.GetTagsByTagIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -994,6 +1343,10 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		transactionResp.Tags = a.getTransactionTagInfoResponses(transactionTagIds, tagMap)
 	}
 
+	if len(transactionSplits) > 0 {
+		transactionResp.Splits = a.getTransactionSplitInfoResponses(transactionSplits, splitCategoryMap, transactionGetReq.TrimCategory)
+	}
+
 	if transactionGetReq.WithPictures && a.CurrentConfig().EnableTransactionPictures {
 		transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 	}
@@ -1040,6 +1393,10 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	if len(transactionCreateReq.Splits) > models.MaximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManySplits
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1063,7 +1420,8 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1074,7 +1432,39 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
+	transaction := a.createNewTransactionModel(uid, ledgerId, &transactionCreateReq, c.GetCurrentUid(), c.ClientIP())
+	transactionSplits := a.createNewTransactionSplitModels(transactionCreateReq.Splits)
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		payeeName := ""
+
+		if transaction.PayeeId > 0 {
+			payee, err := a.payees.GetPayeeByPayeeId(c, uid, ledgerId, transaction.PayeeId)
+
+			if err != nil {
+				log.Warnf(c, "[transactions.TransactionCreateHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", transaction.PayeeId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			payeeName = payee.Name
+		}
+
+		ruleTarget := models.NewTransactionRuleTarget(transaction, tagIds, payeeName, "", len(transactionSplits) > 0)
+		matchedCount, err := a.transactionRules.ApplyRulesToTargets(c, uid, ledgerId, nil, []*models.TransactionRuleTarget{ruleTarget})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] failed to apply transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if matchedCount > 0 {
+			transaction.CategoryId = ruleTarget.CategoryId
+			transaction.Comment = ruleTarget.Comment
+			transaction.HideAmount = ruleTarget.HideAmount
+			tagIds = ruleTarget.TagIds
+		}
+	}
+
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
 
 	if !transactionEditable {
@@ -1107,7 +1497,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 			transactionId, err := utils.StringToInt64(remark)
 
 			if err == nil {
-				transaction, err = a.transactions.GetTransactionByTransactionId(c, uid, transactionId)
+				transaction, err = a.transactions.GetTransactionByTransactionId(c, uid, ledgerId, transactionId)
 
 				if err != nil {
 					log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get existed transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
@@ -1117,12 +1507,16 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 				transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 				transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 
+				if len(transactionSplits) > 0 {
+					transactionResp.Splits = a.getTransactionSplitInfoResponses(transactionSplits, nil, true)
+				}
+
 				return transactionResp, nil
 			}
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, transactionSplits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -1135,6 +1529,10 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 
+	if len(transactionSplits) > 0 {
+		transactionResp.Splits = a.getTransactionSplitInfoResponses(transactionSplits, nil, true)
+	}
+
 	return transactionResp, nil
 }
 
@@ -1177,7 +1575,12 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
-	uid := c.GetCurrentUid()
+	if len(transactionModifyReq.Splits) > models.MaximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManySplits
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1188,7 +1591,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionModifyReq.Id)
+	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, ledgerId, transactionModifyReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -1203,7 +1606,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId != 0 {
 		log.Warnf(c, "[transactions.TransactionModifyHandler] balance modification transaction cannot set category id")
 		return nil, errs.ErrBalanceModificationTransactionCannotSetCategory
-	} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId == 0 {
+	} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transactionModifyReq.CategoryId == 0 && len(transactionModifyReq.Splits) < 1 {
 		log.Warnf(c, "[transactions.TransactionModifyHandler] non-balance modification transaction must set category id")
 		return nil, errs.ErrIncompleteOrIncorrectSubmission
 	}
@@ -1230,14 +1633,26 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
 
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionSplits := allTransactionSplits[transaction.TransactionId]
+	newTransactionSplits := a.createNewTransactionSplitModels(transactionModifyReq.Splits)
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
+		LedgerId:          ledgerId,
 		CategoryId:        transactionModifyReq.CategoryId,
 		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionModifyReq.Time),
 		TimezoneUtcOffset: transactionModifyReq.UtcOffset,
 		AccountId:         transactionModifyReq.SourceAccountId,
 		Amount:            transactionModifyReq.SourceAmount,
+		PayeeId:           transactionModifyReq.PayeeId,
 		HideAmount:        transactionModifyReq.HideAmount,
 		Comment:           transactionModifyReq.Comment,
 	}
@@ -1252,6 +1667,10 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.GeoLatitude = transactionModifyReq.GeoLocation.Latitude
 	}
 
+	if len(newTransactionSplits) > 0 {
+		newTransaction.CategoryId = newTransactionSplits[0].CategoryId
+	}
+
 	if newTransaction.CategoryId == transaction.CategoryId &&
 		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
 		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
@@ -1259,12 +1678,14 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.Amount == transaction.Amount &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
+		newTransaction.PayeeId == transaction.PayeeId &&
 		newTransaction.HideAmount == transaction.HideAmount &&
 		newTransaction.Comment == transaction.Comment &&
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		a.isTransactionSplitsEqual(newTransactionSplits, transactionSplits) {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -1322,7 +1743,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, newTransactionSplits, getTransactionHistoryOperator(c))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -1335,6 +1756,10 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
 
+	if len(newTransactionSplits) > 0 {
+		newTransactionResp.Splits = a.getTransactionSplitInfoResponses(newTransactionSplits, nil, true)
+	}
+
 	return newTransactionResp, nil
 }
 
@@ -1352,8 +1777,9 @@ func (a *TransactionsApi) TransactionMoveAllBetweenAccountsHandler(c *core.WebCo
 		return nil, errs.ErrCannotMoveTransactionToSameAccount
 	}
 
-	uid := c.GetCurrentUid()
-	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId})
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, ledgerId, []int64{transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId})
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMoveAllBetweenAccountsHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -1384,7 +1810,7 @@ func (a *TransactionsApi) TransactionMoveAllBetweenAccountsHandler(c *core.WebCo
 		return nil, errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
 	}
 
-	err = a.transactions.MoveAllTransactionsBetweenAccounts(c, uid, transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId)
+	err = a.transactions.MoveAllTransactionsBetweenAccounts(c, uid, ledgerId, transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId, getTransactionHistoryOperator(c))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMoveAllBetweenAccountsHandler] failed to move all transactions from account \"id:%d\" to account \"id:%d\" for user \"uid:%d\", because %s", transactionMoveReq.FromAccountId, transactionMoveReq.ToAccountId, uid, err.Error())
@@ -1412,7 +1838,8 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -1423,7 +1850,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionDeleteReq.Id)
+	transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, ledgerId, transactionDeleteReq.Id)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionDeleteHandler] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
@@ -1441,7 +1868,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrCannotDeleteTransactionWithThisTransactionTime
 	}
 
-	err = a.transactions.DeleteTransaction(c, uid, transactionDeleteReq.Id)
+	err = a.transactions.DeleteTransaction(c, uid, ledgerId, transactionDeleteReq.Id, getTransactionHistoryOperator(c))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionDeleteHandler] failed to delete transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
@@ -1452,6 +1879,179 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 	return true, nil
 }
 
+// TransactionHistoryListHandler returns the change history list of one specific transaction of current user
+func (a *TransactionsApi) TransactionHistoryListHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionHistoryListReq models.TransactionHistoryListRequest
+	err := c.ShouldBindQuery(&transactionHistoryListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionHistoryListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	histories, err := a.transactionHistories.GetHistoriesByTransactionId(c, uid, transactionHistoryListReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionHistoryListHandler] failed to get histories of transaction \"id:%d\" for user \"uid:%d\", because %s", transactionHistoryListReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	historyResps := make([]*models.TransactionHistoryInfoResponse, 0, len(histories))
+
+	for i := 0; i < len(histories); i++ {
+		if histories[i].LedgerId != ledgerId {
+			continue
+		}
+
+		historyResps = append(historyResps, histories[i].ToTransactionHistoryInfoResponse())
+	}
+
+	return historyResps, nil
+}
+
+// TransactionTrashListHandler returns deleted transaction list in trash bin of current user
+func (a *TransactionsApi) TransactionTrashListHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionTrashListReq models.TransactionTrashListRequest
+	err := c.ShouldBindQuery(&transactionTrashListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionTrashListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionTrashListHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionTrashListHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	transactions, err := a.transactions.GetDeletedTransactionsByPage(c, uid, ledgerId, transactionTrashListReq.Page, transactionTrashListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionTrashListHandler] failed to get deleted transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsOfDeletedTransactions(c, uid, transactions)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionTrashListHandler] failed to get tag ids of deleted transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionResps := make([]*models.DeletedTransactionInfoResponse, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
+		transactionResp := transaction.ToDeletedTransactionInfoResponse(allTransactionTagIds[transaction.TransactionId], transactionEditable)
+
+		if transactionResp.TransactionInfoResponse == nil {
+			continue
+		}
+
+		transactionResps = append(transactionResps, transactionResp)
+	}
+
+	return transactionResps, nil
+}
+
+// TransactionRestoreHandler restores a deleted transaction from trash bin by request parameters for current user
+func (a *TransactionsApi) TransactionRestoreHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionRestoreReq models.TransactionRestoreRequest
+	err := c.ShouldBindJSON(&transactionRestoreReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionRestoreHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionRestoreHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transactions.TransactionRestoreHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	transaction, err := a.transactions.GetDeletedTransactionByTransactionId(c, uid, ledgerId, transactionRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRestoreHandler] failed to get deleted transaction \"id:%d\" for user \"uid:%d\", because %s", transactionRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		log.Warnf(c, "[transactions.TransactionRestoreHandler] cannot restore transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionRestoreReq.Id, uid)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
+
+	if !transactionEditable {
+		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+	}
+
+	err = a.transactions.RestoreTransaction(c, uid, ledgerId, transactionRestoreReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionRestoreHandler] failed to restore transaction \"id:%d\" for user \"uid:%d\", because %s", transactionRestoreReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionRestoreHandler] user \"uid:%d\" has restored transaction \"id:%d\"", uid, transactionRestoreReq.Id)
+	return true, nil
+}
+
+// TransactionPurgeHandler permanently deletes a deleted transaction from trash bin by request parameters for current user
+func (a *TransactionsApi) TransactionPurgeHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionPurgeReq models.TransactionPurgeRequest
+	err := c.ShouldBindJSON(&transactionPurgeReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionPurgeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	err = a.transactions.PurgeTransaction(c, uid, ledgerId, transactionPurgeReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionPurgeHandler] failed to purge transaction \"id:%d\" for user \"uid:%d\", because %s", transactionPurgeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transactions.TransactionPurgeHandler] user \"uid:%d\" has purged transaction \"id:%d\"", uid, transactionPurgeReq.Id)
+	return true, nil
+}
+
 // TransactionParseImportCustomFileDataHandler returns the parsed file data by request parameters for current user
 func (a *TransactionsApi) TransactionParseImportCustomFileDataHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
@@ -1531,7 +2131,8 @@ func (a *TransactionsApi) TransactionParseImportCustomFileDataHandler(c *core.We
 
 // TransactionParseImportFileHandler returns the parsed transaction data by request parameters for current user
 func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 	form, err := c.MultipartForm()
 
 	if err != nil {
@@ -1711,7 +2312,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid)
+	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get accounts for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1720,7 +2321,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 
 	accountMap := a.accounts.GetVisibleAccountNameMapByList(accounts)
 
-	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, 0, -1)
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, ledgerId, 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get categories for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1729,7 +2330,7 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 
 	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetVisibleSubCategoryNameMapByList(categories)
 
-	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid)
+	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get tags for user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -1745,6 +2346,22 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	payees, err := a.payees.GetAllPayeesByUid(c, user.Uid, ledgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get payees for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.applyPayeesToImportedTransactions(parsedTransactions, payees, categories, tags)
+
+	err = a.applyTransactionRulesToImportedTransactions(c, uid, ledgerId, parsedTransactions, tags)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to apply transaction rules to imported data for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
 
 	if len(parsedTransactionRespsList) < 1 {
@@ -1776,7 +2393,8 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
 
 	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionImportReq.ClientSessionId != "" {
 		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId)
@@ -1860,7 +2478,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
-		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction := a.createNewTransactionModel(uid, ledgerId, transactionCreateReq, c.GetCurrentUid(), c.ClientIP())
 		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, clientTimezone)
 
 		if !transactionEditable {
@@ -1898,7 +2516,7 @@ func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (a
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	uid := c.GetCurrentUid()
+	uid := c.GetCurrentLedgerOwnerUid()
 
 	if !a.CurrentConfig().EnableDuplicateSubmissionsCheck {
 		return nil, nil
@@ -1978,13 +2596,30 @@ func (a *TransactionsApi) getTransactionTagInfoResponses(tagIds []int64, allTran
 	return allTags
 }
 
-func (a *TransactionsApi) getTransactionAllEssentialData(c *core.WebContext, user *models.User, withPictures bool, trimCategory bool, trimTag bool) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, pictureInfoMap map[int64][]*models.TransactionPictureInfo, err error) {
+func (a *TransactionsApi) getTransactionSplitInfoResponses(transactionSplits []*models.TransactionSplit, categoryMap map[int64]*models.TransactionCategory, trimCategory bool) []*models.TransactionSplitInfoResponse {
+	allSplits := make([]*models.TransactionSplitInfoResponse, len(transactionSplits))
+
+	for i := 0; i < len(transactionSplits); i++ {
+		allSplits[i] = transactionSplits[i].ToTransactionSplitInfoResponse()
+
+		if !trimCategory && categoryMap != nil {
+			if category := categoryMap[transactionSplits[i].CategoryId]; category != nil {
+				allSplits[i].Category = category.ToTransactionCategoryInfoResponse()
+			}
+		}
+	}
+
+	return allSplits
+}
+
+func (a *TransactionsApi) getTransactionAllEssentialData(c *core.WebContext, user *models.User, withPictures bool, trimCategory bool, trimTag bool) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit, pictureInfoMap map[int64][]*models.TransactionPictureInfo, err error) {
 	uid := user.Uid
-	allAccounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+	ledgerId := c.GetCurrentLedgerId()
+	allAccounts, err := a.accounts.GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
 	accountMap = a.accounts.GetAccountMapByList(allAccounts)
@@ -1993,28 +2628,35 @@ func (a *TransactionsApi) getTransactionAllEssentialData(c *core.WebContext, use
 
 	if err != nil {
 		log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
 	allTransactionTagIds = a.transactionTags.GetGroupedTransactionTagIds(allTagIndexes)
 
+	allTransactionSplits, err = a.transactionSplits.GetAllSplitsOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
 	if !trimCategory {
-		allCategories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+		allCategories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all transactions categories for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 
 		categoryMap = a.transactionCategories.GetCategoryMapByList(allCategories)
 	}
 
 	if !trimTag {
-		allTags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+		allTags, err := a.transactionTags.GetAllTagsByUid(c, uid, ledgerId)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all transactions tags for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 
 		tagMap = a.transactionTags.GetTagMapByList(allTags)
@@ -2025,15 +2667,16 @@ func (a *TransactionsApi) getTransactionAllEssentialData(c *core.WebContext, use
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionAllEssentialData] failed to get all transactions pictures for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 	}
 
-	return accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, nil
+	return accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, nil
 }
 
-func (a *TransactionsApi) getTransactionEssentialDataByTransactionIds(c *core.WebContext, user *models.User, transactions []*models.Transaction, withPictures bool, trimCategory bool, trimTag bool) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, pictureInfoMap map[int64][]*models.TransactionPictureInfo, err error) {
+func (a *TransactionsApi) getTransactionEssentialDataByTransactionIds(c *core.WebContext, user *models.User, transactions []*models.Transaction, withPictures bool, trimCategory bool, trimTag bool) (accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit, pictureInfoMap map[int64][]*models.TransactionPictureInfo, err error) {
 	uid := user.Uid
+	ledgerId := c.GetCurrentLedgerId()
 	transactionIds := make([]int64, len(transactions))
 	accountIds := make([]int64, 0, len(transactions)*2)
 	categoryIds := make([]int64, 0, len(transactions))
@@ -2055,35 +2698,44 @@ func (a *TransactionsApi) getTransactionEssentialDataByTransactionIds(c *core.We
 		categoryIds = append(categoryIds, transactions[i].CategoryId)
 	}
 
-	accountMap, err = a.accounts.GetAccountsByAccountIds(c, uid, utils.ToUniqueInt64Slice(accountIds))
+	accountMap, err = a.accounts.GetAccountsByAccountIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(accountIds))
 
 	if err != nil {
 		log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
 	allTransactionTagIds, err = a.transactionTags.GetAllTagIdsOfTransactions(c, uid, transactionIds)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	allTransactionSplits, err = a.transactionSplits.GetAllSplitsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
+	categoryIds = append(categoryIds, a.transactionSplits.GetTransactionSplitCategoryIds(allTransactionSplits)...)
+
 	if !trimCategory {
-		categoryMap, err = a.transactionCategories.GetCategoriesByCategoryIds(c, uid, utils.ToUniqueInt64Slice(categoryIds))
+		categoryMap, err = a.transactionCategories.GetCategoriesByCategoryIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(categoryIds))
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get transactions categories for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 	}
 
 	if !trimTag {
-		tagMap, err = a.transactionTags.GetTagsByTagIds(c, uid, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
+		tagMap, err = a.transactionTags.GetTagsByTagIds(c, uid, ledgerId, utils.ToUniqueInt64Slice(a.transactionTags.GetTransactionTagIds(allTransactionTagIds)))
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get transactions tags for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 	}
 
@@ -2092,14 +2744,14 @@ func (a *TransactionsApi) getTransactionEssentialDataByTransactionIds(c *core.We
 
 		if err != nil {
 			log.Errorf(c, "[transactions.getTransactionEssentialDataByTransactionIds] failed to get transactions pictures for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 	}
 
-	return accountMap, categoryMap, tagMap, allTransactionTagIds, pictureInfoMap, nil
+	return accountMap, categoryMap, tagMap, allTransactionTagIds, allTransactionSplits, pictureInfoMap, nil
 }
 
-func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, user *models.User, transactions []*models.Transaction, allAccounts map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, pictureInfoMap map[int64][]*models.TransactionPictureInfo, clientTimezone *time.Location, withPictures bool, trimAccount bool, trimCategory bool, trimTag bool) (models.TransactionInfoResponseSlice, error) {
+func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, user *models.User, transactions []*models.Transaction, allAccounts map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit, pictureInfoMap map[int64][]*models.TransactionPictureInfo, clientTimezone *time.Location, withPictures bool, trimAccount bool, trimCategory bool, trimTag bool) (models.TransactionInfoResponseSlice, error) {
 	result := make(models.TransactionInfoResponseSlice, len(transactions))
 
 	for i := 0; i < len(transactions); i++ {
@@ -2133,6 +2785,10 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 			result[i].Tags = a.getTransactionTagInfoResponses(transactionTagIds, tagMap)
 		}
 
+		if transactionSplits, exists := allTransactionSplits[transaction.TransactionId]; exists {
+			result[i].Splits = a.getTransactionSplitInfoResponses(transactionSplits, categoryMap, trimCategory)
+		}
+
 		if withPictures && a.CurrentConfig().EnableTransactionPictures && pictureInfoMap != nil {
 			pictureInfos, exists := pictureInfoMap[transaction.TransactionId]
 
@@ -2147,7 +2803,203 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
-func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
+func (a *TransactionsApi) applyPayeesToImportedTransactions(importedTransactions models.ImportedTransactionSlice, payees []*models.Payee, categories []*models.TransactionCategory, tags []*models.TransactionTag) {
+	if len(payees) < 1 {
+		return
+	}
+
+	categoryMap := a.transactionCategories.GetCategoryMapByList(categories)
+	tagMap := a.transactionTags.GetTagMapByList(tags)
+
+	for i := 0; i < len(importedTransactions); i++ {
+		importedTransaction := importedTransactions[i]
+
+		if importedTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			continue
+		}
+
+		payee := a.payees.GetPayeeByCounterpartyName(payees, importedTransaction.OriginalCounterpartyName)
+
+		if payee == nil {
+			continue
+		}
+
+		importedTransaction.PayeeId = payee.PayeeId
+
+		if importedTransaction.CategoryId == 0 && payee.DefaultCategoryId > 0 {
+			category, exists := categoryMap[payee.DefaultCategoryId]
+
+			if exists && !category.Hidden && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId &&
+				((importedTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME && category.Type == models.CATEGORY_TYPE_INCOME) ||
+					(importedTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE && category.Type == models.CATEGORY_TYPE_EXPENSE) ||
+					(importedTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && category.Type == models.CATEGORY_TYPE_TRANSFER)) {
+				importedTransaction.CategoryId = category.CategoryId
+			}
+		}
+
+		defaultTagIds := payee.GetDefaultTagIds()
+		existedTagIds := make(map[string]bool, len(importedTransaction.TagIds))
+
+		for j := 0; j < len(importedTransaction.TagIds); j++ {
+			existedTagIds[importedTransaction.TagIds[j]] = true
+		}
+
+		for j := 0; j < len(defaultTagIds) && len(importedTransaction.TagIds) < models.MaximumTagsCountOfTransaction; j++ {
+			tag, exists := tagMap[defaultTagIds[j]]
+
+			if !exists || tag.Hidden {
+				continue
+			}
+
+			tagId := utils.Int64ToString(tag.TagId)
+
+			if existedTagIds[tagId] {
+				continue
+			}
+
+			existedTagIds[tagId] = true
+			importedTransaction.TagIds = append(importedTransaction.TagIds, tagId)
+			importedTransaction.OriginalTagNames = append(importedTransaction.OriginalTagNames, tag.Name)
+		}
+	}
+}
+
+func (a *TransactionsApi) applyTransactionRulesToImportedTransactions(c *core.WebContext, uid int64, ledgerId int64, importedTransactions models.ImportedTransactionSlice, tags []*models.TransactionTag) error {
+	targets := make([]*models.TransactionRuleTarget, len(importedTransactions))
+
+	for i := 0; i < len(importedTransactions); i++ {
+		importedTransaction := importedTransactions[i]
+		tagIds, err := utils.StringArrayToInt64Array(importedTransaction.TagIds)
+
+		if err != nil {
+			return err
+		}
+
+		targets[i] = models.NewTransactionRuleTarget(importedTransaction.Transaction, tagIds, importedTransaction.OriginalCounterpartyName, importedTransaction.OriginalCategoryName, false)
+	}
+
+	matchedCount, err := a.transactionRules.ApplyRulesToTargets(c, uid, ledgerId, nil, targets)
+
+	if err != nil || matchedCount < 1 {
+		return err
+	}
+
+	tagMap := a.transactionTags.GetTagMapByList(tags)
+
+	for i := 0; i < len(importedTransactions); i++ {
+		importedTransaction := importedTransactions[i]
+		target := targets[i]
+
+		importedTransaction.CategoryId = target.CategoryId
+		importedTransaction.Comment = target.Comment
+		importedTransaction.HideAmount = target.HideAmount
+
+		for j := len(importedTransaction.TagIds); j < len(target.TagIds); j++ {
+			tag, exists := tagMap[target.TagIds[j]]
+
+			if !exists {
+				continue
+			}
+
+			importedTransaction.TagIds = append(importedTransaction.TagIds, utils.Int64ToString(tag.TagId))
+			importedTransaction.OriginalTagNames = append(importedTransaction.OriginalTagNames, tag.Name)
+		}
+	}
+
+	return nil
+}
+
+func (a *TransactionsApi) createNewTransactionSplitModels(splitCreateReqs []*models.TransactionSplitCreateRequest) []*models.TransactionSplit {
+	transactionSplits := make([]*models.TransactionSplit, len(splitCreateReqs))
+
+	for i := 0; i < len(splitCreateReqs); i++ {
+		transactionSplits[i] = &models.TransactionSplit{
+			CategoryId: splitCreateReqs[i].CategoryId,
+			Amount:     splitCreateReqs[i].Amount,
+			Comment:    splitCreateReqs[i].Comment,
+		}
+	}
+
+	return transactionSplits
+}
+
+func (a *TransactionsApi) isTransactionSplitsEqual(transactionSplits1 []*models.TransactionSplit, transactionSplits2 []*models.TransactionSplit) bool {
+	if len(transactionSplits1) != len(transactionSplits2) {
+		return false
+	}
+
+	for i := 0; i < len(transactionSplits1); i++ {
+		if transactionSplits1[i].CategoryId != transactionSplits2[i].CategoryId ||
+			transactionSplits1[i].Amount != transactionSplits2[i].Amount ||
+			transactionSplits1[i].Comment != transactionSplits2[i].Comment {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *TransactionsApi) getDailyInvestmentAccountValuations(c *core.WebContext, uid int64, investmentTransactions []*models.InvestmentTransaction, accountDailyBalances map[int32][]*models.TransactionWithAccountBalance, clientTimezone *time.Location) (map[int32]map[int64]*models.InvestmentAccountValuation, error) {
+	dailyValuations := make(map[int32]map[int64]*models.InvestmentAccountValuation, len(accountDailyBalances))
+
+	if len(investmentTransactions) < 1 {
+		return dailyValuations, nil
+	}
+
+	symbolSet := make(map[string]bool)
+	symbols := make([]string, 0)
+
+	for i := 0; i < len(investmentTransactions); i++ {
+		if !symbolSet[investmentTransactions[i].Symbol] {
+			symbolSet[investmentTransactions[i].Symbol] = true
+			symbols = append(symbols, investmentTransactions[i].Symbol)
+		}
+	}
+
+	historicalPrices, err := securityprices.Container.GetHistoricalSecurityPrices(c, uid, symbols, a.CurrentConfig())
+
+	if err != nil {
+		return nil, err
+	}
+
+	priceLookup := models.NewSecurityPriceLookup(historicalPrices)
+	calculator := models.NewInvestmentHoldingCalculator()
+	allYearMonthDays := make([]int32, 0, len(accountDailyBalances))
+
+	for yearMonthDay := range accountDailyBalances {
+		allYearMonthDays = append(allYearMonthDays, yearMonthDay)
+	}
+
+	sort.Slice(allYearMonthDays, func(i, j int) bool {
+		return allYearMonthDays[i] < allYearMonthDays[j]
+	})
+
+	transactionIndex := 0
+
+	for i := 0; i < len(allYearMonthDays); i++ {
+		yearMonthDay := allYearMonthDays[i]
+
+		for ; transactionIndex < len(investmentTransactions); transactionIndex++ {
+			investmentTransaction := investmentTransactions[transactionIndex]
+
+			if utils.FormatUnixTimeToNumericYearMonthDay(investmentTransaction.TransactionTime, clientTimezone) > yearMonthDay {
+				break
+			}
+
+			err = calculator.AddTransaction(investmentTransaction)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		dailyValuations[yearMonthDay] = calculator.GetAccountValuations(priceLookup, yearMonthDay)
+	}
+
+	return dailyValuations, nil
+}
+
+func (a *TransactionsApi) createNewTransactionModel(uid int64, ledgerId int64, transactionCreateReq *models.TransactionCreateRequest, createdByUid int64, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_MODIFY_BALANCE {
@@ -2161,16 +3013,20 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 	}
 
 	transaction := &models.Transaction{
-		Uid:               uid,
-		Type:              transactionDbType,
-		CategoryId:        transactionCreateReq.CategoryId,
-		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionCreateReq.Time),
-		TimezoneUtcOffset: transactionCreateReq.UtcOffset,
-		AccountId:         transactionCreateReq.SourceAccountId,
-		Amount:            transactionCreateReq.SourceAmount,
-		HideAmount:        transactionCreateReq.HideAmount,
-		Comment:           transactionCreateReq.Comment,
-		CreatedIp:         clientIp,
+		Uid:                  uid,
+		LedgerId:             ledgerId,
+		Type:                 transactionDbType,
+		CategoryId:           transactionCreateReq.CategoryId,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(transactionCreateReq.Time),
+		TimezoneUtcOffset:    transactionCreateReq.UtcOffset,
+		AccountId:            transactionCreateReq.SourceAccountId,
+		Amount:               transactionCreateReq.SourceAmount,
+		PayeeId:              transactionCreateReq.PayeeId,
+		HideAmount:           transactionCreateReq.HideAmount,
+		Comment:              transactionCreateReq.Comment,
+		ReconciliationStatus: transactionCreateReq.ReconciliationStatus,
+		CreatedIp:            clientIp,
+		CreatedByUid:         createdByUid,
 	}
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER {
@@ -2185,3 +3041,53 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 
 	return transaction
 }
+
+func getTransactionHistoryOperator(c *core.WebContext) *models.TransactionHistoryOperator {
+	operator := &models.TransactionHistoryOperator{
+		Uid:      c.GetCurrentUid(),
+		ClientIp: c.ClientIP(),
+	}
+
+	claims := c.GetTokenClaims()
+
+	if claims != nil {
+		userTokenId, err := utils.StringToInt64(claims.UserTokenId)
+
+		if err == nil {
+			operator.TokenId = services.Tokens.GenerateTokenId(&models.TokenRecord{
+				Uid:             claims.Uid,
+				UserTokenId:     userTokenId,
+				CreatedUnixTime: claims.IssuedAt,
+			})
+		}
+	}
+
+	return operator
+}
+
+func getUnixTimeRangeByYearMonthRange(startYear int32, startMonth int32, endYear int32, endMonth int32) (int64, int64, error) {
+	startTime := int64(0)
+	endTime := int64(0)
+
+	if startYear > 0 && startMonth > 0 {
+		minTransactionTime, _, err := utils.GetTransactionTimeRangeByYearMonth(startYear, startMonth)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		startTime = utils.GetUnixTimeFromTransactionTime(minTransactionTime)
+	}
+
+	if endYear > 0 && endMonth > 0 {
+		_, maxTransactionTime, err := utils.GetTransactionTimeRangeByYearMonth(endYear, endMonth)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		endTime = utils.GetUnixTimeFromTransactionTime(maxTransactionTime)
+	}
+
+	return startTime, endTime, nil
+}
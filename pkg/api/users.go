@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin/binding"
+	"github.com/pquerna/otp/totp"
 
 	"github.com/mayswind/ezbookkeeping/pkg/avatars"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -22,9 +23,11 @@ import (
 type UsersApi struct {
 	ApiUsingConfig
 	ApiWithUserInfo
-	users    *services.UserService
-	tokens   *services.TokenService
-	accounts *services.AccountService
+	users                   *services.UserService
+	tokens                  *services.TokenService
+	accounts                *services.AccountService
+	ledgers                 *services.LedgerService
+	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 }
 
 // Initialize a user api singleton instance
@@ -41,9 +44,11 @@ var (
 				container: avatars.Container,
 			},
 		},
-		users:    services.Users,
-		tokens:   services.Tokens,
-		accounts: services.Accounts,
+		users:                   services.Users,
+		tokens:                  services.Tokens,
+		accounts:                services.Accounts,
+		ledgers:                 services.Ledgers,
+		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 	}
 )
 
@@ -95,7 +100,7 @@ func (a *UsersApi) UserRegisterHandler(c *core.WebContext) (any, *errs.Error) {
 	presetCategoriesSaved := false
 
 	if len(userRegisterReq.Categories) > 0 {
-		_, err = TransactionCategories.createBatchCategories(c, user.Uid, &userRegisterReq.TransactionCategoryCreateBatchRequest)
+		_, err = TransactionCategories.createBatchCategories(c, user.Uid, models.DefaultLedgerId, &userRegisterReq.TransactionCategoryCreateBatchRequest)
 
 		if err == nil {
 			presetCategoriesSaved = true
@@ -242,6 +247,7 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 	}
 
 	uid := c.GetCurrentUid()
+	ledgerId := c.GetCurrentLedgerId()
 	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
@@ -295,7 +301,7 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 	}
 
 	if userUpdateReq.DefaultAccountId > 0 && userUpdateReq.DefaultAccountId != user.DefaultAccountId {
-		accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{userUpdateReq.DefaultAccountId})
+		accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, ledgerId, []int64{userUpdateReq.DefaultAccountId})
 
 		if err != nil || len(accountMap) < 1 {
 			return nil, errs.Or(err, errs.ErrUserDefaultAccountIsInvalid)
@@ -600,6 +606,73 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 	return resp, nil
 }
 
+// UserUpdateTransactionLockTimeHandler saves the transaction lock date of current user or the overridden transaction lock date of the specified ledger after the password or passcode is confirmed
+func (a *UsersApi) UserUpdateTransactionLockTimeHandler(c *core.WebContext) (any, *errs.Error) {
+	var lockTimeUpdateReq models.UserTransactionLockTimeUpdateRequest
+	err := c.ShouldBindJSON(&lockTimeUpdateReq)
+
+	if err != nil {
+		log.Warnf(c, "[users.UserUpdateTransactionLockTimeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[users.UserUpdateTransactionLockTimeHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if lockTimeUpdateReq.Password != "" {
+		if !a.users.IsPasswordEqualsUserPassword(lockTimeUpdateReq.Password, user) {
+			return nil, errs.ErrUserPasswordWrong
+		}
+	} else if lockTimeUpdateReq.Passcode != "" && a.CurrentConfig().EnableTwoFactor {
+		twoFactorSetting, err := a.twoFactorAuthorizations.GetUserTwoFactorSettingByUid(c, uid)
+
+		if err != nil {
+			if !errs.IsCustomError(err) {
+				log.Errorf(c, "[users.UserUpdateTransactionLockTimeHandler] failed to get two-factor setting for user \"uid:%d\", because %s", uid, err.Error())
+			}
+
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if !totp.Validate(lockTimeUpdateReq.Passcode, twoFactorSetting.Secret) {
+			log.Warnf(c, "[users.UserUpdateTransactionLockTimeHandler] passcode is invalid for user \"uid:%d\"", uid)
+			return nil, errs.ErrPasscodeInvalid
+		}
+	} else {
+		return nil, errs.ErrTransactionLockTimeChangeNotConfirmed
+	}
+
+	if lockTimeUpdateReq.LedgerId > 0 {
+		err = a.ledgers.UpdateLedgerTransactionLockTime(c, uid, lockTimeUpdateReq.LedgerId, lockTimeUpdateReq.Overridden, lockTimeUpdateReq.LockTime)
+
+		if err != nil {
+			log.Errorf(c, "[users.UserUpdateTransactionLockTimeHandler] failed to update transaction lock date of ledger \"id:%d\" for user \"uid:%d\", because %s", lockTimeUpdateReq.LedgerId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		log.Infof(c, "[users.UserUpdateTransactionLockTimeHandler] user \"uid:%d\" has updated transaction lock date of ledger \"id:%d\" to %d (overridden: %t)", uid, lockTimeUpdateReq.LedgerId, lockTimeUpdateReq.LockTime, lockTimeUpdateReq.Overridden)
+		return true, nil
+	}
+
+	err = a.users.UpdateUserTransactionLockTime(c, uid, lockTimeUpdateReq.LockTime)
+
+	if err != nil {
+		log.Errorf(c, "[users.UserUpdateTransactionLockTimeHandler] failed to update transaction lock date for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[users.UserUpdateTransactionLockTimeHandler] user \"uid:%d\" has updated transaction lock date to %d", uid, lockTimeUpdateReq.LockTime)
+	return true, nil
+}
+
 // UserUpdateAvatarHandler saves user avatar by request parameters for current user
 func (a *UsersApi) UserUpdateAvatarHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
@@ -26,10 +26,13 @@ type UserDataCli struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	splits                  *services.TransactionSplitService
+	histories               *services.TransactionHistoryService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a user data cli singleton instance
@@ -42,10 +45,13 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		splits:                  services.TransactionSplits,
+		histories:               services.TransactionHistories,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
@@ -583,7 +589,7 @@ func (l *UserDataCli) CheckTransactionAndAccount(c *core.CliContext, username st
 		}
 	}
 
-	allTransactions, err := l.transactions.GetAllTransactions(c, uid, pageCountForGettingTransactions, false)
+	allTransactions, err := l.transactions.GetAllTransactions(c, uid, models.DefaultLedgerId, pageCountForGettingTransactions, false)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.CheckTransactionAndAccount] failed to all transactions for user \"%s\", because %s", username, err.Error())
@@ -719,7 +725,7 @@ func (l *UserDataCli) FixTransactionTagIndexWithTransactionTime(c *core.CliConte
 		return false, errs.ErrOperationFailed
 	}
 
-	allTransactions, err := l.transactions.GetAllTransactions(c, uid, pageCountForGettingTransactions, false)
+	allTransactions, err := l.transactions.GetAllTransactions(c, uid, models.DefaultLedgerId, pageCountForGettingTransactions, false)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.FixTransactionTagIndexWithTransactionTime] failed to all transactions for user \"%s\", because %s", username, err.Error())
@@ -749,6 +755,30 @@ func (l *UserDataCli) FixTransactionTagIndexWithTransactionTime(c *core.CliConte
 	return true, nil
 }
 
+// GetTransactionHistories returns all change histories of the specified transaction of the specified user
+func (l *UserDataCli) GetTransactionHistories(c *core.CliContext, username string, transactionId int64) ([]*models.TransactionHistory, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.GetTransactionHistories] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.GetTransactionHistories] error occurs when getting user id by user name")
+		return nil, err
+	}
+
+	histories, err := l.histories.GetHistoriesByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.GetTransactionHistories] failed to get histories of transaction \"id:%d\" of user \"%s\", because %s", transactionId, username, err.Error())
+		return nil, err
+	}
+
+	return histories, nil
+}
+
 // ExportTransaction returns csv file content according user all transactions
 func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fileType string) ([]byte, error) {
 	if username == "" {
@@ -756,13 +786,15 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, errs.ErrUsernameIsEmpty
 	}
 
-	uid, err := l.getUserIdByUsername(c, username)
+	user, err := l.GetUserByUsername(c, username)
 
 	if err != nil {
-		log.CliErrorf(c, "[user_data.ExportTransaction] error occurs when getting user id by user name")
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get user by user name \"%s\", because %s", username, err.Error())
 		return nil, err
 	}
 
+	uid := user.Uid
+
 	accountMap, categoryMap, tagMap, _, tagIndexesMap, err := l.getUserEssentialData(c, uid, username)
 
 	if err != nil {
@@ -770,20 +802,27 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, err
 	}
 
-	allTransactions, err := l.transactions.GetAllTransactions(c, uid, pageCountForDataExport, true)
+	allTransactions, err := l.transactions.GetAllTransactions(c, uid, models.DefaultLedgerId, pageCountForDataExport, true)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to all transactions for user \"%s\", because %s", username, err.Error())
 		return nil, err
 	}
 
-	dataExporter := converters.GetTransactionDataExporter(fileType)
+	allTransactionSplits, err := l.splits.GetAllSplitsOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction splits for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	dataExporter := converters.GetTransactionDataExporter(fileType, user)
 
 	if dataExporter == nil {
 		return nil, errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap, allTransactionSplits)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
@@ -879,6 +918,54 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 	return nil
 }
 
+// BackupUserData returns the backup archive which contains all the data of specified user
+func (l *UserDataCli) BackupUserData(c *core.CliContext, username string) ([]byte, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.BackupUserData] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	result, err := l.userDataBackups.CreateBackupArchive(c, user)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to create backup archive for \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreUserData restores all the data in the backup archive to specified user which does not have any data
+func (l *UserDataCli) RestoreUserData(c *core.CliContext, username string, data []byte) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RestoreUserData] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	err = l.userDataBackups.RestoreBackupArchive(c, user, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to restore backup archive for \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	return nil
+}
+
 func (l *UserDataCli) getUserIdByUsername(c *core.CliContext, username string) (int64, error) {
 	user, err := l.GetUserByUsername(c, username)
 
@@ -896,7 +983,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 		return nil, nil, nil, nil, nil, errs.ErrUserIdInvalid
 	}
 
-	accounts, err := l.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := l.accounts.GetAllAccountsByUid(c, uid, models.DefaultLedgerId)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get accounts for user \"%s\", because %s", username, err.Error())
@@ -905,7 +992,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 
 	accountMap = l.accounts.GetAccountMapByList(accounts)
 
-	categories, err := l.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := l.categories.GetAllCategoriesByUid(c, uid, models.DefaultLedgerId, 0, -1)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get categories for user \"%s\", because %s", username, err.Error())
@@ -914,7 +1001,7 @@ func (l *UserDataCli) getUserEssentialData(c *core.CliContext, uid int64, userna
 
 	categoryMap = l.categories.GetCategoryMapByList(categories)
 
-	tags, err := l.tags.GetAllTagsByUid(c, uid)
+	tags, err := l.tags.GetAllTagsByUid(c, uid, models.DefaultLedgerId)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialData] failed to get tags for user \"%s\", because %s", username, err.Error())
@@ -941,7 +1028,7 @@ func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int6
 		return nil, nil, nil, nil, nil, errs.ErrUserIdInvalid
 	}
 
-	accounts, err := l.accounts.GetAllAccountsByUid(c, uid)
+	accounts, err := l.accounts.GetAllAccountsByUid(c, uid, models.DefaultLedgerId)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get accounts for user \"%s\", because %s", username, err.Error())
@@ -950,7 +1037,7 @@ func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int6
 
 	accountMap = l.accounts.GetVisibleAccountNameMapByList(accounts)
 
-	categories, err := l.categories.GetAllCategoriesByUid(c, uid, 0, -1)
+	categories, err := l.categories.GetAllCategoriesByUid(c, uid, models.DefaultLedgerId, 0, -1)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get categories for user \"%s\", because %s", username, err.Error())
@@ -959,7 +1046,7 @@ func (l *UserDataCli) getUserEssentialDataForImport(c *core.CliContext, uid int6
 
 	expenseCategoryMap, incomeCategoryMap, transferCategoryMap = l.categories.GetVisibleSubCategoryNameMapByList(categories)
 
-	tags, err := l.tags.GetAllTagsByUid(c, uid)
+	tags, err := l.tags.GetAllTagsByUid(c, uid, models.DefaultLedgerId)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.getUserEssentialDataForImport] failed to get tags for user \"%s\", because %s", username, err.Error())
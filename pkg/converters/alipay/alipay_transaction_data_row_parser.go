@@ -80,6 +80,12 @@ func (p *alipayTransactionDataRowParser) Parse(ctx core.Context, user *models.Us
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if p.hasOriginalColumn(p.columns.targetNameColumnName) {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = dataRow.GetData(p.columns.targetNameColumnName)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	relatedAccountName := ""
 
 	if p.hasOriginalColumn(p.columns.relatedAccountColumnName) {
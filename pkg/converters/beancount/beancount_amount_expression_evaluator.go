@@ -221,7 +221,8 @@ func evaluatePostfixExpr(ctx core.Context, tokens []string) (*big.Int, error) {
 	return stack[0], nil
 }
 
-func evaluateBeancountAmountExpression(ctx core.Context, expr string) (string, error) {
+// EvaluateAmountExpression returns the textual amount evaluated from the arithmetic expression which contains numbers, ( ) * / - +
+func EvaluateAmountExpression(ctx core.Context, expr string) (string, error) {
 	if expr == "" {
 		return "", nil
 	}
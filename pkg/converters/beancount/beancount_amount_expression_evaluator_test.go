@@ -142,94 +142,94 @@ func TestEvaluatePostfixExpr_InvalidExpression(t *testing.T) {
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 }
 
-func TestEvaluateBeancountAmountExpression_ValidExpression(t *testing.T) {
+func TestEvaluateAmountExpression_ValidExpression(t *testing.T) {
 	context := core.NewNullContext()
 
-	result, err := evaluateBeancountAmountExpression(context, "")
+	result, err := EvaluateAmountExpression(context, "")
 	assert.Nil(t, err)
 	assert.Equal(t, "", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "1+2")
+	result, err = EvaluateAmountExpression(context, "1+2")
 	assert.Nil(t, err)
 	assert.Equal(t, "3.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "(1+2)*3")
+	result, err = EvaluateAmountExpression(context, "(1+2)*3")
 	assert.Nil(t, err)
 	assert.Equal(t, "9.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "-1+2")
+	result, err = EvaluateAmountExpression(context, "-1+2")
 	assert.Nil(t, err)
 	assert.Equal(t, "1.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "1.5+2.5")
+	result, err = EvaluateAmountExpression(context, "1.5+2.5")
 	assert.Nil(t, err)
 	assert.Equal(t, "4.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "1+2*3-(4/2)")
+	result, err = EvaluateAmountExpression(context, "1+2*3-(4/2)")
 	assert.Nil(t, err)
 	assert.Equal(t, "5.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "2*-3-3/-2")
+	result, err = EvaluateAmountExpression(context, "2*-3-3/-2")
 	assert.Nil(t, err)
 	assert.Equal(t, "-4.50", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "-1.2-3.4*(-5.6/7.8*(9.0-1.2))")
+	result, err = EvaluateAmountExpression(context, "-1.2-3.4*(-5.6/7.8*(9.0-1.2))")
 	assert.Nil(t, err)
 	assert.Equal(t, "17.84", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "(((2+3)))*(((((-5+7)))))")
+	result, err = EvaluateAmountExpression(context, "(((2+3)))*(((((-5+7)))))")
 	assert.Nil(t, err)
 	assert.Equal(t, "10.00", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "3.5+0.1")
+	result, err = EvaluateAmountExpression(context, "3.5+0.1")
 	assert.Nil(t, err)
 	assert.Equal(t, "3.60", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "3.55+0.11")
+	result, err = EvaluateAmountExpression(context, "3.55+0.11")
 	assert.Nil(t, err)
 	assert.Equal(t, "3.66", result)
 
-	result, err = evaluateBeancountAmountExpression(context, "3.555+0.111")
+	result, err = EvaluateAmountExpression(context, "3.555+0.111")
 	assert.Nil(t, err)
 	assert.Equal(t, "3.66", result)
 }
 
-func TestEvaluateBeancountAmountExpression_InvalidExpression(t *testing.T) {
+func TestEvaluateAmountExpression_InvalidExpression(t *testing.T) {
 	context := core.NewNullContext()
 
-	_, err := evaluateBeancountAmountExpression(context, "1++2")
+	_, err := EvaluateAmountExpression(context, "1++2")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1^2")
+	_, err = EvaluateAmountExpression(context, "1^2")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "+-*/")
+	_, err = EvaluateAmountExpression(context, "+-*/")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "a+b")
+	_, err = EvaluateAmountExpression(context, "a+b")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1/0")
+	_, err = EvaluateAmountExpression(context, "1/0")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1+(2*3")
+	_, err = EvaluateAmountExpression(context, "1+(2*3")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1+2*3)")
+	_, err = EvaluateAmountExpression(context, "1+2*3)")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1+((((2*3)))")
+	_, err = EvaluateAmountExpression(context, "1+((((2*3)))")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1+2(3)")
+	_, err = EvaluateAmountExpression(context, "1+2(3)")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "1)*(2")
+	_, err = EvaluateAmountExpression(context, "1)*(2")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "0.abcd+1")
+	_, err = EvaluateAmountExpression(context, "0.abcd+1")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 
-	_, err = evaluateBeancountAmountExpression(context, "0.1234567+1")
+	_, err = EvaluateAmountExpression(context, "0.1234567+1")
 	assert.Equal(t, errs.ErrInvalidAmountExpression, err)
 }
@@ -422,7 +422,7 @@ func (r *beancountDataReader) readTransactionPostingLine(ctx core.Context, lineI
 		return nil, errs.ErrAmountInvalid
 	}
 
-	finalAmount, err := evaluateBeancountAmountExpression(ctx, transactionPositing.OriginalAmount)
+	finalAmount, err := EvaluateAmountExpression(ctx, transactionPositing.OriginalAmount)
 
 	if err != nil {
 		log.Warnf(ctx, "[beancount_data_reader.readTransactionPostingLine] cannot evaluate amount expression in line#%d \"%s\", because %s", lineIndex, strings.Join(items, " "), err.Error())
@@ -0,0 +1,416 @@
+package beancount
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const beancountExportedEquityOpeningBalanceAccountName = beancountDefaultEquityAccountTypeName + beancountAccountNameItemsSeparator + beancountEquityAccountNameOpeningBalance
+
+// beancountTransactionDataExporter defines the structure of Beancount exporter for transaction data
+type beancountTransactionDataExporter struct {
+}
+
+// beancountExportedEntry defines the structure of the exported transaction entry
+type beancountExportedEntry struct {
+	transactionTime int64
+	date            string
+	narration       string
+	tags            []string
+	postings        []*beancountExportedPosting
+}
+
+// beancountExportedPosting defines the structure of the exported transaction posting
+type beancountExportedPosting struct {
+	account            string
+	amount             int64
+	commodity          string
+	totalCost          int64
+	totalCostCommodity string
+}
+
+// beancountExportedAccountNames defines the structure of the Beancount account names of all exported accounts and categories
+type beancountExportedAccountNames struct {
+	accountMap       map[int64]*models.Account
+	categoryMap      map[int64]*models.TransactionCategory
+	accountNames     map[int64]string
+	categoryNames    map[int64]string
+	allUsedNames     map[string]bool
+	accountOpenDates map[string]string
+	accountCurrency  map[string]string
+}
+
+// Initialize a Beancount transaction data exporter singleton instance
+var (
+	BeancountTransactionDataExporter = &beancountTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported Beancount data, which contains the commodity directives of all currencies, the open directives of all used accounts and the transaction entries in time order
+func (c *beancountTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transaction.TransactionId] = true
+		}
+	}
+
+	accountNames := &beancountExportedAccountNames{
+		accountMap:       accountMap,
+		categoryMap:      categoryMap,
+		accountNames:     make(map[int64]string),
+		categoryNames:    make(map[int64]string),
+		allUsedNames:     make(map[string]bool),
+		accountOpenDates: make(map[string]string),
+		accountCurrency:  make(map[string]string),
+	}
+
+	entries := make([]*beancountExportedEntry, 0, len(transactions))
+	commodityDates := make(map[string]string)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		date := utils.FormatUnixTimeToLongDate(transactionUnixTime, transactionTimeZone)
+		tags := c.getExportedTags(transaction.TransactionId, allTagIndexes, tagMap)
+		transactionSplits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			entry := c.createExportedEntry(transaction, transaction.CategoryId, transaction.Amount, transaction.Comment, accountNames)
+
+			if entry != nil {
+				entry.date = date
+				entry.tags = tags
+				entries = append(entries, entry)
+			}
+
+			continue
+		}
+
+		// each split of the transaction is exported as a separate entry with its own category and amount
+		for j := 0; j < len(transactionSplits); j++ {
+			split := transactionSplits[j]
+			comment := transaction.Comment
+
+			if split.Comment != "" {
+				comment = split.Comment
+			}
+
+			entry := c.createExportedEntry(transaction, split.CategoryId, split.Amount, comment, accountNames)
+
+			if entry != nil {
+				entry.date = date
+				entry.tags = tags
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].transactionTime < entries[j].transactionTime
+	})
+
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+
+		for j := 0; j < len(entry.postings); j++ {
+			posting := entry.postings[j]
+
+			if openDate, exists := accountNames.accountOpenDates[posting.account]; !exists || entry.date < openDate {
+				accountNames.accountOpenDates[posting.account] = entry.date
+			}
+
+			if commodityDate, exists := commodityDates[posting.commodity]; !exists || entry.date < commodityDate {
+				commodityDates[posting.commodity] = entry.date
+			}
+		}
+	}
+
+	var ret strings.Builder
+
+	commodities := make([]string, 0, len(commodityDates))
+
+	for commodity := range commodityDates {
+		commodities = append(commodities, commodity)
+	}
+
+	sort.Strings(commodities)
+
+	for i := 0; i < len(commodities); i++ {
+		ret.WriteString(fmt.Sprintf("%s %s %s\n", commodityDates[commodities[i]], beancountDirectiveCommodity, commodities[i]))
+	}
+
+	if len(commodities) > 0 {
+		ret.WriteString("\n")
+	}
+
+	openAccountNames := make([]string, 0, len(accountNames.accountOpenDates))
+
+	for accountName := range accountNames.accountOpenDates {
+		openAccountNames = append(openAccountNames, accountName)
+	}
+
+	sort.Strings(openAccountNames)
+
+	for i := 0; i < len(openAccountNames); i++ {
+		accountName := openAccountNames[i]
+		ret.WriteString(fmt.Sprintf("%s %s %s", accountNames.accountOpenDates[accountName], beancountDirectiveOpen, accountName))
+
+		if currency, exists := accountNames.accountCurrency[accountName]; exists {
+			ret.WriteString(" " + currency)
+		}
+
+		ret.WriteString("\n")
+	}
+
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+
+		ret.WriteString("\n")
+		ret.WriteString(fmt.Sprintf("%s %s \"%s\"", entry.date, beancountDirectiveCompletedTransaction, entry.narration))
+
+		for j := 0; j < len(entry.tags); j++ {
+			ret.WriteString(" " + string(beancountTagPrefix) + entry.tags[j])
+		}
+
+		ret.WriteString("\n")
+
+		for j := 0; j < len(entry.postings); j++ {
+			posting := entry.postings[j]
+			ret.WriteString(fmt.Sprintf("  %s  %s %s", posting.account, utils.FormatAmount(posting.amount), posting.commodity))
+
+			if posting.totalCostCommodity != "" {
+				ret.WriteString(fmt.Sprintf(" %c%c %s %s", beancountPricePrefix, beancountPricePrefix, utils.FormatAmount(posting.totalCost), posting.totalCostCommodity))
+			}
+
+			ret.WriteString("\n")
+		}
+	}
+
+	return []byte(ret.String()), nil
+}
+
+func (c *beancountTransactionDataExporter) createExportedEntry(transaction *models.Transaction, categoryId int64, amount int64, comment string, accountNames *beancountExportedAccountNames) *beancountExportedEntry {
+	entry := &beancountExportedEntry{
+		transactionTime: transaction.TransactionTime,
+		narration:       c.getExportedNarration(comment),
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		entry.postings = []*beancountExportedPosting{
+			{account: beancountExportedEquityOpeningBalanceAccountName, amount: -amount, commodity: currency},
+			{account: account, amount: amount, commodity: currency},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		entry.postings = []*beancountExportedPosting{
+			{account: accountNames.getCategoryName(categoryId, beancountDefaultIncomeAccountTypeName), amount: -amount, commodity: currency},
+			{account: account, amount: amount, commodity: currency},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		entry.postings = []*beancountExportedPosting{
+			{account: accountNames.getCategoryName(categoryId, beancountDefaultExpenseAccountTypeName), amount: amount, commodity: currency},
+			{account: account, amount: -amount, commodity: currency},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		fromAccountId := transaction.AccountId
+		fromAmount := transaction.Amount
+		toAccountId := transaction.RelatedAccountId
+		toAmount := transaction.RelatedAccountAmount
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			fromAccountId = transaction.RelatedAccountId
+			fromAmount = transaction.RelatedAccountAmount
+			toAccountId = transaction.AccountId
+			toAmount = transaction.Amount
+		}
+
+		fromAccount, fromCurrency := accountNames.getAccountNameAndCurrency(fromAccountId)
+		toAccount, toCurrency := accountNames.getAccountNameAndCurrency(toAccountId)
+
+		if fromAccount == "" || toAccount == "" {
+			return nil
+		}
+
+		fromPosting := &beancountExportedPosting{account: fromAccount, amount: -fromAmount, commodity: fromCurrency}
+
+		// the postings must be balanced, so the total cost is required when the currencies are different
+		if fromCurrency != toCurrency {
+			fromPosting.totalCost = toAmount
+			fromPosting.totalCostCommodity = toCurrency
+		}
+
+		entry.postings = []*beancountExportedPosting{
+			fromPosting,
+			{account: toAccount, amount: toAmount, commodity: toCurrency},
+		}
+	} else {
+		return nil
+	}
+
+	return entry
+}
+
+func (c *beancountTransactionDataExporter) getExportedNarration(comment string) string {
+	comment = strings.ReplaceAll(comment, "\r\n", " ")
+	comment = strings.ReplaceAll(comment, "\r", " ")
+	comment = strings.ReplaceAll(comment, "\n", " ")
+	comment = strings.ReplaceAll(comment, "\"", "'")
+
+	return comment
+}
+
+func (c *beancountTransactionDataExporter) getExportedTags(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIndexes, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tags := make([]string, 0, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		// the tag name can only contain letters, numbers, dashes, underscores, slashes and periods
+		tagName := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '/' || r == '.' {
+				return r
+			}
+
+			return '-'
+		}, tag.Name)
+
+		tags = append(tags, tagName)
+	}
+
+	return tags
+}
+
+func (n *beancountExportedAccountNames) getAccountNameAndCurrency(accountId int64) (string, string) {
+	account, exists := n.accountMap[accountId]
+
+	if !exists {
+		return "", ""
+	}
+
+	if name, exists := n.accountNames[accountId]; exists {
+		return name, account.Currency
+	}
+
+	accountTypeName := beancountDefaultAssetsAccountTypeName
+
+	if account.Category.IsLiability() {
+		accountTypeName = beancountDefaultLiabilitiesAccountTypeName
+	}
+
+	name := accountTypeName + beancountAccountNameItemsSeparator + n.getAccountNameComponent(account.Name)
+
+	if parentAccount, exists := n.accountMap[account.ParentAccountId]; exists && account.ParentAccountId > 0 {
+		name = accountTypeName + beancountAccountNameItemsSeparator + n.getAccountNameComponent(parentAccount.Name) + beancountAccountNameItemsSeparator + n.getAccountNameComponent(account.Name)
+	}
+
+	name = n.getUniqueName(name, accountId)
+	n.accountNames[accountId] = name
+	n.accountCurrency[name] = account.Currency
+
+	return name, account.Currency
+}
+
+func (n *beancountExportedAccountNames) getCategoryName(categoryId int64, accountTypeName string) string {
+	if name, exists := n.categoryNames[categoryId]; exists {
+		return name
+	}
+
+	category, exists := n.categoryMap[categoryId]
+
+	if !exists {
+		return accountTypeName + beancountAccountNameItemsSeparator + "Uncategorized"
+	}
+
+	name := accountTypeName + beancountAccountNameItemsSeparator + n.getAccountNameComponent(category.Name)
+
+	if parentCategory, exists := n.categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		name = accountTypeName + beancountAccountNameItemsSeparator + n.getAccountNameComponent(parentCategory.Name) + beancountAccountNameItemsSeparator + n.getAccountNameComponent(category.Name)
+	}
+
+	name = n.getUniqueName(name, categoryId)
+	n.categoryNames[categoryId] = name
+
+	return name
+}
+
+// getAccountNameComponent returns the valid account name component, which only contains letters, numbers and single dashes and starts with a capital letter or number
+func (n *beancountExportedAccountNames) getAccountNameComponent(name string) string {
+	component := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+
+		return '-'
+	}, strings.TrimSpace(name))
+
+	for strings.Contains(component, "--") {
+		component = strings.ReplaceAll(component, "--", "-")
+	}
+
+	component = strings.Trim(component, "-")
+
+	if component == "" {
+		return "X"
+	}
+
+	firstRune := []rune(component)[0]
+
+	if unicode.IsLower(firstRune) {
+		return string(unicode.ToUpper(firstRune)) + component[len(string(firstRune)):]
+	}
+
+	return component
+}
+
+func (n *beancountExportedAccountNames) getUniqueName(name string, id int64) string {
+	if n.allUsedNames[name] {
+		name = name + "-" + utils.Int64ToString(id)
+	}
+
+	n.allUsedNames[name] = true
+
+	return name
+}
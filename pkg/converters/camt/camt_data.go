@@ -62,10 +62,21 @@ type camtEntryDetails struct {
 
 type camtTransactionDetails struct {
 	AmountDetails                    *camtAmountDetails         `xml:"AmtDtls"`
+	RelatedParties                   *camtRelatedParties        `xml:"RltdPties"`
 	RemittanceInformation            *camtRemittanceInformation `xml:"RmtInf"`
 	AdditionalTransactionInformation string                     `xml:"AddtlTxInf"`
 }
 
+type camtRelatedParties struct {
+	Debtor   *camtParty `xml:"Dbtr"`
+	Creditor *camtParty `xml:"Cdtr"`
+}
+
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
 type camtAmountDetails struct {
 	InstructedAmount  *camtAmount `xml:"InstdAmt>Amt"`
 	TransactionAmount *camtAmount `xml:"TxAmt>Amt"`
@@ -74,3 +85,16 @@ type camtAmountDetails struct {
 type camtRemittanceInformation struct {
 	Unstructured []string `xml:"Ustrd"`
 }
+
+// GetName returns the name of the party
+func (p *camtParty) GetName() string {
+	if p == nil {
+		return ""
+	}
+
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.PartyName
+}
@@ -22,6 +22,7 @@ var camtTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]b
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 // camtStatementTransactionDataTable defines the structure of camt statement transaction data table
@@ -300,6 +301,14 @@ func (t *camtStatementTransactionDataRowIterator) parseTransaction(ctx core.Cont
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if transactionDetails != nil && transactionDetails.RelatedParties != nil && entry.CreditDebitIndicator == CAMT_INDICATOR_CREDIT {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = strings.TrimSpace(transactionDetails.RelatedParties.Debtor.GetName())
+	} else if transactionDetails != nil && transactionDetails.RelatedParties != nil && entry.CreditDebitIndicator == CAMT_INDICATOR_DEBIT {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = strings.TrimSpace(transactionDetails.RelatedParties.Creditor.GetName())
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	return data, nil
 }
 
@@ -54,7 +54,7 @@ func (c *camt052TransactionDataImporter) ParseImportedData(ctx core.Context, use
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(camtTransactionTypeNameMapping)
+	dataTableImporter := converter.CreateNewBankStatementImporterWithTypeNameMapping(camtTransactionTypeNameMapping)
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
@@ -83,7 +83,7 @@ func (c *camt053TransactionDataImporter) ParseImportedData(ctx core.Context, use
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(camtTransactionTypeNameMapping)
+	dataTableImporter := converter.CreateNewBankStatementImporterWithTypeNameMapping(camtTransactionTypeNameMapping)
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
@@ -79,6 +79,7 @@ func TestCamt052TransactionDataFileParseImportedData_MinimumValidData(t *testing
 
 	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
 	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED, allNewTransactions[0].ReconciliationStatus)
 	assert.Equal(t, int64(1725125025), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
 	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
 	assert.Equal(t, "123", allNewTransactions[0].OriginalSourceAccountName)
@@ -182,6 +183,7 @@ func TestCamt053TransactionDataFileParseImportedData_MinimumValidData(t *testing
 
 	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
 	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED, allNewTransactions[0].ReconciliationStatus)
 	assert.Equal(t, int64(1725125025), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
 	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
 	assert.Equal(t, "123", allNewTransactions[0].OriginalSourceAccountName)
@@ -746,6 +748,86 @@ func TestCamt053TransactionDataFileParseImportedData_ParseDescription(t *testing
 	assert.Equal(t, "Test Entry", allNewTransactions[0].Comment)
 }
 
+func TestCamt053TransactionDataFileParseImportedData_ParseRelatedParties(t *testing.T) {
+	importer := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Dbtr>
+										<Nm>Test Debtor</Nm>
+									</Dbtr>
+									<Cdtr>
+										<Nm>Test Creditor</Nm>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Dbtr>
+										<Pty>
+											<Nm>Test Debtor</Nm>
+										</Pty>
+									</Dbtr>
+									<Cdtr>
+										<Pty>
+											<Nm>Test Creditor</Nm>
+										</Pty>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, "Test Debtor", allNewTransactions[0].OriginalCounterpartyName)
+	assert.Equal(t, "Test Creditor", allNewTransactions[1].OriginalCounterpartyName)
+	assert.Equal(t, "", allNewTransactions[2].OriginalCounterpartyName)
+}
+
 func TestCamt053TransactionDataFileParseImportedData_MissingAccountNode(t *testing.T) {
 	importer := Camt053TransactionDataImporter
 	context := core.NewNullContext()
@@ -19,7 +19,7 @@ type DataTableTransactionDataExporter struct {
 }
 
 // BuildExportedContent writes the exported transaction data to the data table builder
-func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) error {
+func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder datatable.TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) error {
 	existsTransferOutTransactions := make(map[int64]bool)
 
 	for i := 0; i < len(transactions); i++ {
@@ -71,7 +71,32 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, transaction.TransactionId, allTagIndexes, tagMap)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(transaction.Comment)
 
-		dataTableBuilder.AppendTransaction(dataRowMap)
+		transactionSplits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			dataTableBuilder.AppendTransaction(dataRowMap)
+			continue
+		}
+
+		// each split of the transaction is exported as a separate line with its own category and amount
+		for j := 0; j < len(transactionSplits); j++ {
+			split := transactionSplits[j]
+			splitDataRowMap := make(map[datatable.TransactionDataTableColumn]string, len(dataRowMap))
+
+			for column, value := range dataRowMap {
+				splitDataRowMap[column] = value
+			}
+
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = c.getExportedTransactionCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = c.getExportedTransactionSubCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(split.Amount)
+
+			if split.Comment != "" {
+				splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(split.Comment)
+			}
+
+			dataTableBuilder.AppendTransaction(splitDataRowMap)
+		}
 	}
 
 	return nil
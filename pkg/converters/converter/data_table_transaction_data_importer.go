@@ -27,6 +27,7 @@ type DataTableTransactionDataImporter struct {
 	geoLocationSeparator    string
 	geoLocationOrder        TransactionGeoLocationOrder
 	transactionTagSeparator string
+	reconciliationStatus    models.TransactionReconciliationStatus
 }
 
 // ParseImportedData returns the imported transaction data
@@ -368,6 +369,16 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			description = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
 		}
 
+		counterpartyName := ""
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_PAYEE) {
+			counterpartyName = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
+		}
+
+		if counterpartyName == "" && dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_MERCHANT) {
+			counterpartyName = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_MERCHANT)
+		}
+
 		transaction := &models.ImportTransaction{
 			Transaction: &models.Transaction{
 				Uid:                  user.Uid,
@@ -384,6 +395,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 				GeoLongitude:         geoLongitude,
 				GeoLatitude:          geoLatitude,
 				CreatedIp:            ctx.ClientIP(),
+				ReconciliationStatus: c.reconciliationStatus,
 			},
 			TagIds:                             tagIds,
 			OriginalCategoryName:               subCategoryName,
@@ -392,6 +404,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			OriginalDestinationAccountName:     account2Name,
 			OriginalDestinationAccountCurrency: account2Currency,
 			OriginalTagNames:                   tagNames,
+			OriginalCounterpartyName:           counterpartyName,
 		}
 
 		allNewTransactions = append(allNewTransactions, transaction)
@@ -553,6 +566,14 @@ func CreateNewSimpleImporterWithTypeNameMapping(transactionTypeMapping map[model
 	}
 }
 
+// CreateNewBankStatementImporterWithTypeNameMapping returns a new data table transaction data importer for bank statement according to the specified arguments, all the imported transactions are marked as cleared
+func CreateNewBankStatementImporterWithTypeNameMapping(transactionTypeMapping map[models.TransactionType]string) *DataTableTransactionDataImporter {
+	return &DataTableTransactionDataImporter{
+		transactionTypeMapping: buildTransactionNameTypeMap(transactionTypeMapping),
+		reconciliationStatus:   models.TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+}
+
 func buildTransactionNameTypeMap(transactionTypeMapping map[models.TransactionType]string) map[string]models.TransactionType {
 	if transactionTypeMapping == nil {
 		return nil
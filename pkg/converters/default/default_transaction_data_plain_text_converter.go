@@ -61,7 +61,7 @@ var ezbookkeepingDataColumns = []datatable.TransactionDataTableColumn{
 }
 
 // ToExportedContent returns the exported transaction plain text data
-func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
 	dataTableBuilder := createNewDefaultTransactionPlainTextDataTableBuilder(
 		len(transactions),
 		ezbookkeepingDataColumns,
@@ -76,7 +76,7 @@ func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Co
 		ezbookkeepingTagSeparator,
 	)
 
-	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allTransactionSplits)
 
 	if err != nil {
 		return nil, err
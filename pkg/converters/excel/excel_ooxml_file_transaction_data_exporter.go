@@ -0,0 +1,653 @@
+package excel
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const excelOOXMLTransactionsSheetName = "Transactions"
+const excelOOXMLAccountsSheetName = "Accounts"
+const excelOOXMLCategoriesSheetName = "Categories"
+const excelOOXMLTagsSheetName = "Tags"
+const excelOOXMLMonthlySummarySheetName = "Monthly Summary"
+
+const excelOOXMLGeoLocationSeparator = " "
+const excelOOXMLTagSeparator = ";"
+
+var excelOOXMLTransactionDataColumnNameMapping = map[datatable.TransactionDataTableColumn]string{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         "Time",
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE:     "Timezone",
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         "Type",
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 "Category",
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             "Sub Category",
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             "Account",
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         "Account Currency",
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   "Amount",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     "Account2",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: "Account2 Currency",
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           "Account2 Amount",
+	datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION:      "Geographic Location",
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     "Tags",
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              "Description",
+}
+
+var excelOOXMLTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: "Balance Modification",
+	models.TRANSACTION_TYPE_INCOME:         "Income",
+	models.TRANSACTION_TYPE_EXPENSE:        "Expense",
+	models.TRANSACTION_TYPE_TRANSFER:       "Transfer",
+}
+
+var excelOOXMLTransactionDataColumns = []datatable.TransactionDataTableColumn{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT,
+	datatable.TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION,
+	datatable.TRANSACTION_DATA_TABLE_TAGS,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+}
+
+var excelOOXMLAccountCategoryNameMapping = map[models.AccountCategory]string{
+	models.ACCOUNT_CATEGORY_CASH:                   "Cash",
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       "Checking Account",
+	models.ACCOUNT_CATEGORY_CREDIT_CARD:            "Credit Card",
+	models.ACCOUNT_CATEGORY_VIRTUAL:                "Virtual Account",
+	models.ACCOUNT_CATEGORY_DEBT:                   "Debt Account",
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            "Receivables",
+	models.ACCOUNT_CATEGORY_INVESTMENT:             "Investment Account",
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        "Savings Account",
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: "Certificate of Deposit",
+}
+
+var excelOOXMLCategoryTypeNameMapping = map[models.TransactionCategoryType]string{
+	models.CATEGORY_TYPE_INCOME:   "Income",
+	models.CATEGORY_TYPE_EXPENSE:  "Expense",
+	models.CATEGORY_TYPE_TRANSFER: "Transfer",
+}
+
+var excelOOXMLDateNumberFormats = map[core.LongDateFormat]string{
+	core.LONG_DATE_FORMAT_YYYY_M_D: "yyyy-mm-dd",
+	core.LONG_DATE_FORMAT_M_D_YYYY: "mm/dd/yyyy",
+	core.LONG_DATE_FORMAT_D_M_YYYY: "dd/mm/yyyy",
+}
+
+var excelOOXMLYearMonthNumberFormats = map[core.LongDateFormat]string{
+	core.LONG_DATE_FORMAT_YYYY_M_D: "yyyy-mm",
+	core.LONG_DATE_FORMAT_M_D_YYYY: "mm/yyyy",
+	core.LONG_DATE_FORMAT_D_M_YYYY: "mm/yyyy",
+}
+
+var excelOOXMLTimeNumberFormats = map[core.LongTimeFormat]string{
+	core.LONG_TIME_FORMAT_HH_MM_SS:   "hh:mm:ss",
+	core.LONG_TIME_FORMAT_A_HH_MM_SS: "AM/PM h:mm:ss",
+	core.LONG_TIME_FORMAT_HH_MM_SS_A: "h:mm:ss AM/PM",
+}
+
+// ExcelOOXMLFileTransactionDataExporter defines the structure of excel (Office Open XML) file exporter for transaction data
+type ExcelOOXMLFileTransactionDataExporter struct {
+	dateTimeNumberFormat  string
+	yearMonthNumberFormat string
+}
+
+// excelOOXMLTransactionDataSheetBuilder defines the structure of excel (Office Open XML) transaction data sheet builder
+type excelOOXMLTransactionDataSheetBuilder struct {
+	allData []map[datatable.TransactionDataTableColumn]string
+}
+
+// excelOOXMLSheetWriter defines the structure of excel (Office Open XML) file sheet writer
+type excelOOXMLSheetWriter struct {
+	file          *excelize.File
+	streamWriter  *excelize.StreamWriter
+	headerStyleId int
+	styleIds      map[string]int
+	currentRow    int
+}
+
+// excelOOXMLMonthlySummary defines the structure of the income and expense summary of one month in one currency
+type excelOOXMLMonthlySummary struct {
+	month    time.Time
+	currency string
+	income   int64
+	expense  int64
+}
+
+// AppendTransaction appends the specified transaction to data builder
+func (b *excelOOXMLTransactionDataSheetBuilder) AppendTransaction(data map[datatable.TransactionDataTableColumn]string) {
+	b.allData = append(b.allData, data)
+}
+
+// ReplaceDelimiters returns the text after removing the delimiters, the cell of excel file does not have any delimiter
+func (b *excelOOXMLTransactionDataSheetBuilder) ReplaceDelimiters(text string) string {
+	return text
+}
+
+// ToExportedContent returns the exported excel (Office Open XML) file content, which contains the sheets of transactions, accounts, categories, tags and monthly summaries
+func (c *ExcelOOXMLFileTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	dataTableBuilder := &excelOOXMLTransactionDataSheetBuilder{
+		allData: make([]map[datatable.TransactionDataTableColumn]string, 0, len(transactions)),
+	}
+
+	dataTableExporter := converter.CreateNewExporter(
+		excelOOXMLTransactionTypeNameMapping,
+		excelOOXMLGeoLocationSeparator,
+		excelOOXMLTagSeparator,
+	)
+
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allTransactionSplits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	err = file.SetSheetName(file.GetSheetName(0), excelOOXMLTransactionsSheetName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writeTransactionsSheet(file, dataTableBuilder.allData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writeAccountsSheet(file, accountMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writeCategoriesSheet(file, categoryMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writeTagsSheet(file, tagMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.writeMonthlySummarySheet(file, transactions, accountMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, err := file.WriteToBuffer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) writeTransactionsSheet(file *excelize.File, allData []map[datatable.TransactionDataTableColumn]string) error {
+	header := make([]string, len(excelOOXMLTransactionDataColumns))
+
+	for i := 0; i < len(excelOOXMLTransactionDataColumns); i++ {
+		header[i] = excelOOXMLTransactionDataColumnNameMapping[excelOOXMLTransactionDataColumns[i]]
+	}
+
+	writer, err := createNewExcelOOXMLSheetWriter(file, excelOOXMLTransactionsSheetName, header)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(allData); i++ {
+		data := allData[i]
+		row := make([]any, len(excelOOXMLTransactionDataColumns))
+
+		for j := 0; j < len(excelOOXMLTransactionDataColumns); j++ {
+			column := excelOOXMLTransactionDataColumns[j]
+			value := data[column]
+
+			switch column {
+			case datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:
+				row[j], err = writer.getDateTimeCell(value, c.dateTimeNumberFormat)
+			case datatable.TRANSACTION_DATA_TABLE_AMOUNT:
+				row[j], err = writer.getAmountCell(value, data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY])
+			case datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:
+				row[j], err = writer.getAmountCell(value, data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY])
+			default:
+				row[j] = value
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writer.appendRow(row)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) writeAccountsSheet(file *excelize.File, accountMap map[int64]*models.Account) error {
+	writer, err := createNewExcelOOXMLSheetWriter(file, excelOOXMLAccountsSheetName, []string{"Account", "Parent Account", "Category", "Currency", "Balance", "Hidden", "Description"})
+
+	if err != nil {
+		return err
+	}
+
+	accounts := make([]*models.Account, 0, len(accountMap))
+
+	for _, account := range accountMap {
+		accounts = append(accounts, account)
+	}
+
+	// the sub accounts are listed after their parent account
+	sort.Slice(accounts, func(i, j int) bool {
+		parentAccountI := c.getParentAccount(accounts[i], accountMap)
+		parentAccountJ := c.getParentAccount(accounts[j], accountMap)
+
+		if parentAccountI.Category != parentAccountJ.Category {
+			return parentAccountI.Category < parentAccountJ.Category
+		} else if parentAccountI.DisplayOrder != parentAccountJ.DisplayOrder {
+			return parentAccountI.DisplayOrder < parentAccountJ.DisplayOrder
+		} else if parentAccountI.AccountId != parentAccountJ.AccountId {
+			return parentAccountI.AccountId < parentAccountJ.AccountId
+		} else if (accounts[i].ParentAccountId == models.LevelOneAccountParentId) != (accounts[j].ParentAccountId == models.LevelOneAccountParentId) {
+			return accounts[i].ParentAccountId == models.LevelOneAccountParentId
+		} else if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		parentAccountName := ""
+		currency := ""
+		var balance any = ""
+
+		if parentAccount, exists := accountMap[account.ParentAccountId]; exists && account.ParentAccountId != models.LevelOneAccountParentId {
+			parentAccountName = parentAccount.Name
+		}
+
+		// the account which has sub accounts does not have its own currency and balance
+		if account.Type != models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			currency = account.Currency
+			balance, err = writer.getAmountCell(utils.FormatAmount(account.Balance), currency)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writer.appendRow([]any{account.Name, parentAccountName, excelOOXMLAccountCategoryNameMapping[account.Category], currency, balance, account.Hidden, account.Comment})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) writeCategoriesSheet(file *excelize.File, categoryMap map[int64]*models.TransactionCategory) error {
+	writer, err := createNewExcelOOXMLSheetWriter(file, excelOOXMLCategoriesSheetName, []string{"Type", "Category", "Sub Category", "Hidden", "Description"})
+
+	if err != nil {
+		return err
+	}
+
+	categories := make([]*models.TransactionCategory, 0, len(categoryMap))
+
+	for _, category := range categoryMap {
+		categories = append(categories, category)
+	}
+
+	// the sub categories are listed after their parent category
+	sort.Slice(categories, func(i, j int) bool {
+		parentCategoryI := c.getParentCategory(categories[i], categoryMap)
+		parentCategoryJ := c.getParentCategory(categories[j], categoryMap)
+
+		if parentCategoryI.Type != parentCategoryJ.Type {
+			return parentCategoryI.Type < parentCategoryJ.Type
+		} else if parentCategoryI.DisplayOrder != parentCategoryJ.DisplayOrder {
+			return parentCategoryI.DisplayOrder < parentCategoryJ.DisplayOrder
+		} else if parentCategoryI.CategoryId != parentCategoryJ.CategoryId {
+			return parentCategoryI.CategoryId < parentCategoryJ.CategoryId
+		} else if (categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId) != (categories[j].ParentCategoryId == models.LevelOneTransactionCategoryParentId) {
+			return categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId
+		} else if categories[i].DisplayOrder != categories[j].DisplayOrder {
+			return categories[i].DisplayOrder < categories[j].DisplayOrder
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+		categoryName := category.Name
+		subCategoryName := ""
+
+		if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			categoryName = parentCategory.Name
+			subCategoryName = category.Name
+		}
+
+		err = writer.appendRow([]any{excelOOXMLCategoryTypeNameMapping[category.Type], categoryName, subCategoryName, category.Hidden, category.Comment})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) writeTagsSheet(file *excelize.File, tagMap map[int64]*models.TransactionTag) error {
+	writer, err := createNewExcelOOXMLSheetWriter(file, excelOOXMLTagsSheetName, []string{"Tag", "Hidden"})
+
+	if err != nil {
+		return err
+	}
+
+	tags := make([]*models.TransactionTag, 0, len(tagMap))
+
+	for _, tag := range tagMap {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].TagGroupId != tags[j].TagGroupId {
+			return tags[i].TagGroupId < tags[j].TagGroupId
+		} else if tags[i].DisplayOrder != tags[j].DisplayOrder {
+			return tags[i].DisplayOrder < tags[j].DisplayOrder
+		}
+
+		return tags[i].TagId < tags[j].TagId
+	})
+
+	for i := 0; i < len(tags); i++ {
+		err = writer.appendRow([]any{tags[i].Name, tags[i].Hidden})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) writeMonthlySummarySheet(file *excelize.File, transactions []*models.Transaction, accountMap map[int64]*models.Account) error {
+	writer, err := createNewExcelOOXMLSheetWriter(file, excelOOXMLMonthlySummarySheetName, []string{"Month", "Currency", "Income", "Expense", "Net Income"})
+
+	if err != nil {
+		return err
+	}
+
+	summaries := make([]*excelOOXMLMonthlySummary, 0)
+	summaryMap := make(map[string]*excelOOXMLMonthlySummary)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(transactionTimeZone)
+		month := time.Date(transactionTime.Year(), transactionTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+		summaryKey := fmt.Sprintf("%s_%s", month.Format("2006-01"), account.Currency)
+		summary, exists := summaryMap[summaryKey]
+
+		if !exists {
+			summary = &excelOOXMLMonthlySummary{
+				month:    month,
+				currency: account.Currency,
+			}
+
+			summaries = append(summaries, summary)
+			summaryMap[summaryKey] = summary
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			summary.income += transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			summary.expense += transaction.Amount
+		}
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].month.Equal(summaries[j].month) {
+			return summaries[i].month.Before(summaries[j].month)
+		}
+
+		return summaries[i].currency < summaries[j].currency
+	})
+
+	for i := 0; i < len(summaries); i++ {
+		summary := summaries[i]
+		monthStyleId, err := writer.getStyleId(c.yearMonthNumberFormat)
+
+		if err != nil {
+			return err
+		}
+
+		income, err := writer.getAmountCell(utils.FormatAmount(summary.income), summary.currency)
+
+		if err != nil {
+			return err
+		}
+
+		expense, err := writer.getAmountCell(utils.FormatAmount(summary.expense), summary.currency)
+
+		if err != nil {
+			return err
+		}
+
+		netIncome, err := writer.getAmountCell(utils.FormatAmount(summary.income-summary.expense), summary.currency)
+
+		if err != nil {
+			return err
+		}
+
+		err = writer.appendRow([]any{excelize.Cell{StyleID: monthStyleId, Value: summary.month}, summary.currency, income, expense, netIncome})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.flush()
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) getParentAccount(account *models.Account, accountMap map[int64]*models.Account) *models.Account {
+	if parentAccount, exists := accountMap[account.ParentAccountId]; exists && account.ParentAccountId != models.LevelOneAccountParentId {
+		return parentAccount
+	}
+
+	return account
+}
+
+func (c *ExcelOOXMLFileTransactionDataExporter) getParentCategory(category *models.TransactionCategory, categoryMap map[int64]*models.TransactionCategory) *models.TransactionCategory {
+	if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		return parentCategory
+	}
+
+	return category
+}
+
+// appendRow writes the specified values to the next row of the sheet
+func (w *excelOOXMLSheetWriter) appendRow(values []any) error {
+	w.currentRow++
+	cell, err := excelize.CoordinatesToCellName(1, w.currentRow)
+
+	if err != nil {
+		return err
+	}
+
+	return w.streamWriter.SetRow(cell, values)
+}
+
+// getDateTimeCell returns the numeric date time cell of the specified textual date time, which is displayed in the specified number format
+func (w *excelOOXMLSheetWriter) getDateTimeCell(value string, numberFormat string) (any, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	// the date time cell does not contain timezone, so the date time is stored in the wall clock time of the transaction
+	dateTime, err := utils.ParseFromLongDateTimeInFixedUtcOffset(value, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	styleId, err := w.getStyleId(numberFormat)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return excelize.Cell{StyleID: styleId, Value: dateTime}, nil
+}
+
+// getAmountCell returns the numeric amount cell of the specified textual amount, which is displayed with the specified currency code
+func (w *excelOOXMLSheetWriter) getAmountCell(value string, currency string) (any, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	amount, err := utils.ParseAmount(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	numberFormat := "#,##0.00"
+
+	if currency != "" {
+		numberFormat = fmt.Sprintf("#,##0.00 \"%s\"", currency)
+	}
+
+	styleId, err := w.getStyleId(numberFormat)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return excelize.Cell{StyleID: styleId, Value: float64(amount) / 100}, nil
+}
+
+func (w *excelOOXMLSheetWriter) getStyleId(numberFormat string) (int, error) {
+	if styleId, exists := w.styleIds[numberFormat]; exists {
+		return styleId, nil
+	}
+
+	styleId, err := w.file.NewStyle(&excelize.Style{
+		CustomNumFmt: &numberFormat,
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	w.styleIds[numberFormat] = styleId
+
+	return styleId, nil
+}
+
+func (w *excelOOXMLSheetWriter) flush() error {
+	return w.streamWriter.Flush()
+}
+
+func createNewExcelOOXMLSheetWriter(file *excelize.File, sheetName string, header []string) (*excelOOXMLSheetWriter, error) {
+	if index, err := file.GetSheetIndex(sheetName); err != nil || index < 0 {
+		if _, err := file.NewSheet(sheetName); err != nil {
+			return nil, err
+		}
+	}
+
+	streamWriter, err := file.NewStreamWriter(sheetName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	headerStyleId, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &excelOOXMLSheetWriter{
+		file:          file,
+		streamWriter:  streamWriter,
+		headerStyleId: headerStyleId,
+		styleIds:      make(map[string]int),
+	}
+
+	headerRow := make([]any, len(header))
+
+	for i := 0; i < len(header); i++ {
+		headerRow[i] = excelize.Cell{StyleID: headerStyleId, Value: header[i]}
+	}
+
+	err = writer.appendRow(headerRow)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// CreateNewExcelOOXMLFileTransactionDataExporter returns a new excel (Office Open XML) file exporter for transaction data, the time cells are displayed in the specified date and time format, or the default format of the specified language
+func CreateNewExcelOOXMLFileTransactionDataExporter(longDateFormat core.LongDateFormat, longTimeFormat core.LongTimeFormat, language string) *ExcelOOXMLFileTransactionDataExporter {
+	defaultTypes := locales.GetLocaleTextItems(language).DefaultTypes
+
+	if _, exists := excelOOXMLDateNumberFormats[longDateFormat]; !exists {
+		longDateFormat = defaultTypes.LongDateFormat
+	}
+
+	if _, exists := excelOOXMLTimeNumberFormats[longTimeFormat]; !exists {
+		longTimeFormat = defaultTypes.LongTimeFormat
+	}
+
+	return &ExcelOOXMLFileTransactionDataExporter{
+		dateTimeNumberFormat:  excelOOXMLDateNumberFormats[longDateFormat] + " " + excelOOXMLTimeNumberFormats[longTimeFormat],
+		yearMonthNumberFormat: excelOOXMLYearMonthNumberFormats[longDateFormat],
+	}
+}
@@ -0,0 +1,206 @@
+package excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestExcelOOXMLFileTransactionDataExporterToExportedContent(t *testing.T) {
+	exporter := CreateNewExcelOOXMLFileTransactionDataExporter(core.LONG_DATE_FORMAT_YYYY_M_D, core.LONG_TIME_FORMAT_HH_MM_SS, "en")
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(content), excelize.Options{RawCellValue: true})
+	assert.Nil(t, err)
+	defer file.Close()
+
+	assert.Equal(t, []string{"Transactions", "Accounts", "Categories", "Tags", "Monthly Summary"}, file.GetSheetList())
+
+	rows, err := file.GetRows("Transactions")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(rows))
+	assert.Equal(t, []string{"Time", "Timezone", "Type", "Category", "Sub Category", "Account", "Account Currency", "Amount", "Account2", "Account2 Currency", "Account2 Amount", "Geographic Location", "Tags", "Description"}, rows[0])
+	assert.Equal(t, []string{"45536.333333333336", "+08:00", "Balance Modification", "", "", "Bank", "CNY", "1000"}, rows[1])
+	assert.Equal(t, []string{"45537.833333333336", "+08:00", "Income", "Work", "Salary", "Bank", "CNY", "123.45", "", "", "", "", "Monthly", "Salary"}, rows[2])
+	assert.Equal(t, []string{"45567", "+08:00", "Expense", "Food", "Dinner", "Credit Card", "USD", "10.5"}, rows[3])
+	assert.Equal(t, []string{"45568", "+08:00", "Transfer", "", "", "Bank", "CNY", "70", "Credit Card", "USD", "10"}, rows[4])
+
+	assertCellNumberFormat(t, file, "Transactions", "A2", "yyyy-mm-dd hh:mm:ss")
+	assertCellNumberFormat(t, file, "Transactions", "H2", "#,##0.00 \"CNY\"")
+	assertCellNumberFormat(t, file, "Transactions", "H4", "#,##0.00 \"USD\"")
+	assertCellNumberFormat(t, file, "Transactions", "K5", "#,##0.00 \"USD\"")
+
+	cellType, err := file.GetCellType("Transactions", "H3")
+	assert.Nil(t, err)
+	assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+	assert.NotEqual(t, excelize.CellTypeInlineString, cellType)
+
+	rows, err = file.GetRows("Accounts")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, []string{"Account", "Parent Account", "Category", "Currency", "Balance", "Hidden", "Description"}, rows[0])
+	assert.Equal(t, []string{"Bank", "", "Checking Account", "CNY", "1053.45", "0", "Salary account"}, rows[1])
+	assert.Equal(t, []string{"Cards", "", "Credit Card", "", "", "0"}, rows[2])
+	assert.Equal(t, []string{"Credit Card", "Cards", "Credit Card", "USD", "-0.5", "1"}, rows[3])
+
+	assertCellNumberFormat(t, file, "Accounts", "E2", "#,##0.00 \"CNY\"")
+	assertCellNumberFormat(t, file, "Accounts", "E4", "#,##0.00 \"USD\"")
+
+	rows, err = file.GetRows("Categories")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"Type", "Category", "Sub Category", "Hidden", "Description"},
+		{"Income", "Work", "", "0"},
+		{"Income", "Work", "Salary", "0"},
+		{"Expense", "Food", "", "0"},
+		{"Expense", "Food", "Dinner", "0", "Restaurant"},
+	}, rows)
+
+	rows, err = file.GetRows("Tags")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"Tag", "Hidden"},
+		{"Monthly", "0"},
+	}, rows)
+
+	rows, err = file.GetRows("Monthly Summary")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"Month", "Currency", "Income", "Expense", "Net Income"},
+		{"45536", "CNY", "123.45", "0", "123.45"},
+		{"45566", "USD", "0", "10.5", "-10.5"},
+	}, rows)
+
+	assertCellNumberFormat(t, file, "Monthly Summary", "A2", "yyyy-mm")
+	assertCellNumberFormat(t, file, "Monthly Summary", "E3", "#,##0.00 \"USD\"")
+}
+
+func TestExcelOOXMLFileTransactionDataExporterToExportedContent_DefaultDateTimeFormat(t *testing.T) {
+	exporter := CreateNewExcelOOXMLFileTransactionDataExporter(core.LONG_DATE_FORMAT_DEFAULT, core.LONG_TIME_FORMAT_DEFAULT, "en")
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	assert.Nil(t, err)
+	defer file.Close()
+
+	assertCellNumberFormat(t, file, "Transactions", "A2", "mm/dd/yyyy h:mm:ss AM/PM")
+	assertCellNumberFormat(t, file, "Monthly Summary", "A2", "mm/yyyy")
+}
+
+func TestExcelOOXMLFileTransactionDataExporterToExportedContent_SpecifiedDateTimeFormat(t *testing.T) {
+	exporter := CreateNewExcelOOXMLFileTransactionDataExporter(core.LONG_DATE_FORMAT_D_M_YYYY, core.LONG_TIME_FORMAT_A_HH_MM_SS, "en")
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := createTestExcelOOXMLExportedTransactions()
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	assert.Nil(t, err)
+	defer file.Close()
+
+	assertCellNumberFormat(t, file, "Transactions", "A2", "dd/mm/yyyy AM/PM h:mm:ss")
+}
+
+func assertCellNumberFormat(t *testing.T, file *excelize.File, sheetName string, cell string, expectedNumberFormat string) {
+	styleId, err := file.GetCellStyle(sheetName, cell)
+	assert.Nil(t, err)
+
+	style, err := file.GetStyle(styleId)
+	assert.Nil(t, err)
+	assert.NotNil(t, style.CustomNumFmt)
+	assert.Equal(t, expectedNumberFormat, *style.CustomNumFmt)
+}
+
+func createTestExcelOOXMLExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725148800000,
+			Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+			TimezoneUtcOffset: 480,
+			AccountId:         1,
+			Amount:            100000,
+		},
+		{
+			TransactionId:     2,
+			TransactionTime:   1725278400000,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			TimezoneUtcOffset: 480,
+			CategoryId:        2,
+			AccountId:         1,
+			Amount:            12345,
+			Comment:           "Salary",
+		},
+		{
+			TransactionId:     3,
+			TransactionTime:   1727798400000,
+			Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+			TimezoneUtcOffset: 480,
+			CategoryId:        4,
+			AccountId:         3,
+			Amount:            1050,
+		},
+		{
+			TransactionId:        5,
+			TransactionTime:      1727884800000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			TimezoneUtcOffset:    480,
+			AccountId:            3,
+			Amount:               1000,
+			RelatedId:            4,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 7000,
+		},
+		{
+			TransactionId:        4,
+			TransactionTime:      1727884800000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			TimezoneUtcOffset:    480,
+			AccountId:            1,
+			Amount:               7000,
+			RelatedId:            5,
+			RelatedAccountId:     3,
+			RelatedAccountAmount: 1000,
+		},
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY", Balance: 105345, Comment: "Salary account"},
+		2: {AccountId: 2, Name: "Cards", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS},
+		3: {AccountId: 3, Name: "Credit Card", ParentAccountId: 2, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "USD", Balance: -50, Hidden: true},
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		1: {CategoryId: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Work"},
+		2: {CategoryId: 2, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 1, Name: "Salary"},
+		3: {CategoryId: 3, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Food"},
+		4: {CategoryId: 4, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 3, Name: "Dinner", Comment: "Restaurant"},
+	}
+
+	tagMap := map[int64]*models.TransactionTag{
+		1: {TagId: 1, Name: "Monthly"},
+	}
+
+	allTagIndexes := map[int64][]int64{
+		2: {1},
+	}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes
+}
@@ -4,12 +4,18 @@ import "encoding/xml"
 
 const gnucashCommodityCurrencySpace = "CURRENCY"
 const gnucashRootAccountType = "ROOT"
+const gnucashAssetAccountType = "ASSET"
+const gnucashBankAccountType = "BANK"
+const gnucashCashAccountType = "CASH"
+const gnucashCreditAccountType = "CREDIT"
+const gnucashLiabilityAccountType = "LIABILITY"
 const gnucashEquityAccountType = "EQUITY"
 const gnucashIncomeAccountType = "INCOME"
 const gnucashExpenseAccountType = "EXPENSE"
 
 const gnucashSlotEquityType = "equity-type"
 const gnucashSlotEquityTypeOpeningBalance = "opening-balance"
+const gnucashSlotPlaceholder = "placeholder"
 
 var gnucashAssetOrLiabilityAccountTypes = map[string]bool{
 	"ASSET":      true,
@@ -80,6 +86,7 @@ type gnucashTransactionData struct {
 // gnucashTransactionSplitData represents the struct of gnucash transaction split data
 type gnucashTransactionSplitData struct {
 	Id              string `xml:"id"`
+	Memo            string `xml:"memo"`
 	ReconciledState string `xml:"reconciled-state"`
 	Value           string `xml:"value"`
 	Quantity        string `xml:"quantity"`
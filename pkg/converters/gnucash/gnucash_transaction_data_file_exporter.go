@@ -0,0 +1,540 @@
+package gnucash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const gnucashExportedDateTimeFormat = "2006-01-02 15:04:05 -0700"
+const gnucashExportedAmountDenominator = "100"
+
+const gnucashExportedRootAccountName = "Root Account"
+const gnucashExportedAssetsAccountName = "Assets"
+const gnucashExportedLiabilitiesAccountName = "Liabilities"
+const gnucashExportedEquityAccountName = "Equity"
+const gnucashExportedIncomeAccountName = "Income"
+const gnucashExportedExpensesAccountName = "Expenses"
+const gnucashExportedOpeningBalancesAccountName = "Opening Balances"
+const gnucashExportedUncategorizedAccountName = "Uncategorized"
+
+const gnucashExportedNotReconciledState = "n"
+const gnucashExportedClearedState = "c"
+const gnucashExportedReconciledState = "y"
+
+// gnucashTransactionDataExporter defines the structure of gnucash exporter for transaction data
+type gnucashTransactionDataExporter struct {
+}
+
+// gnucashExportedAccount defines the structure of the exported gnucash account
+type gnucashExportedAccount struct {
+	id             string
+	name           string
+	accountType    string
+	currency       string
+	description    string
+	parent         *gnucashExportedAccount
+	placeholder    bool
+	openingBalance bool
+}
+
+// gnucashExportedTransaction defines the structure of the exported gnucash transaction
+type gnucashExportedTransaction struct {
+	id          string
+	currency    string
+	postedDate  string
+	enteredDate string
+	description string
+	splits      []*gnucashExportedSplit
+}
+
+// gnucashExportedSplit defines the structure of the exported gnucash transaction split
+type gnucashExportedSplit struct {
+	id              string
+	memo            string
+	reconciledState string
+	value           int64
+	quantity        int64
+	account         *gnucashExportedAccount
+}
+
+// gnucashExportedBook defines the structure of the gnucash book which contains all exported accounts
+type gnucashExportedBook struct {
+	uid           int64
+	accountMap    map[int64]*models.Account
+	categoryMap   map[int64]*models.TransactionCategory
+	accounts      []*gnucashExportedAccount
+	accountsByKey map[string]*gnucashExportedAccount
+}
+
+// Initialize a gnucash transaction data exporter singleton instance
+var (
+	GnuCashTransactionDataExporter = &gnucashTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported gzip compressed gnucash xml file content, which contains a book with the account tree of all used accounts and categories and all transactions in time order
+func (c *gnucashTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+	allTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transaction.TransactionId] = true
+		}
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	// the accounts are created in the order of use, so the transactions must be sorted first to make the currency of each account stable
+	sort.SliceStable(allTransactions, func(i, j int) bool {
+		return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+	})
+
+	book := &gnucashExportedBook{
+		uid:           uid,
+		accountMap:    accountMap,
+		categoryMap:   categoryMap,
+		accounts:      make([]*gnucashExportedAccount, 0),
+		accountsByKey: make(map[string]*gnucashExportedAccount),
+	}
+
+	book.getRootAccount()
+
+	exportedTransactions := make([]*gnucashExportedTransaction, 0, len(allTransactions))
+
+	for i := 0; i < len(allTransactions); i++ {
+		exportedTransaction := c.createExportedTransaction(allTransactions[i], allTransactionSplits, book)
+
+		if exportedTransaction != nil {
+			exportedTransactions = append(exportedTransactions, exportedTransaction)
+		}
+	}
+
+	currencies := make([]string, 0)
+	allCurrencies := make(map[string]bool)
+
+	for i := 0; i < len(book.accounts); i++ {
+		currency := book.accounts[i].currency
+
+		if currency != "" && !allCurrencies[currency] {
+			currencies = append(currencies, currency)
+			allCurrencies[currency] = true
+		}
+	}
+
+	sort.Strings(currencies)
+
+	var ret strings.Builder
+
+	ret.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\" ?>\n")
+	ret.WriteString("<gnc-v2\n")
+	ret.WriteString("     xmlns:gnc=\"http://www.gnucash.org/XML/gnc\"\n")
+	ret.WriteString("     xmlns:act=\"http://www.gnucash.org/XML/act\"\n")
+	ret.WriteString("     xmlns:book=\"http://www.gnucash.org/XML/book\"\n")
+	ret.WriteString("     xmlns:cd=\"http://www.gnucash.org/XML/cd\"\n")
+	ret.WriteString("     xmlns:cmdty=\"http://www.gnucash.org/XML/cmdty\"\n")
+	ret.WriteString("     xmlns:slot=\"http://www.gnucash.org/XML/slot\"\n")
+	ret.WriteString("     xmlns:split=\"http://www.gnucash.org/XML/split\"\n")
+	ret.WriteString("     xmlns:trn=\"http://www.gnucash.org/XML/trn\"\n")
+	ret.WriteString("     xmlns:ts=\"http://www.gnucash.org/XML/ts\">\n")
+	ret.WriteString("<gnc:count-data cd:type=\"book\">1</gnc:count-data>\n")
+	ret.WriteString("<gnc:book version=\"2.0.0\">\n")
+	ret.WriteString(fmt.Sprintf("<book:id type=\"guid\">%s</book:id>\n", book.getGuid("book")))
+	ret.WriteString(fmt.Sprintf("<gnc:count-data cd:type=\"commodity\">%d</gnc:count-data>\n", len(currencies)))
+	ret.WriteString(fmt.Sprintf("<gnc:count-data cd:type=\"account\">%d</gnc:count-data>\n", len(book.accounts)))
+	ret.WriteString(fmt.Sprintf("<gnc:count-data cd:type=\"transaction\">%d</gnc:count-data>\n", len(exportedTransactions)))
+
+	for i := 0; i < len(currencies); i++ {
+		ret.WriteString("<gnc:commodity version=\"2.0.0\">\n")
+		c.writeElement(&ret, "  ", "cmdty:space", gnucashCommodityCurrencySpace)
+		c.writeElement(&ret, "  ", "cmdty:id", currencies[i])
+		ret.WriteString("</gnc:commodity>\n")
+	}
+
+	for i := 0; i < len(book.accounts); i++ {
+		c.writeAccount(&ret, book.accounts[i])
+	}
+
+	for i := 0; i < len(exportedTransactions); i++ {
+		c.writeTransaction(&ret, exportedTransactions[i])
+	}
+
+	ret.WriteString("</gnc:book>\n")
+	ret.WriteString("</gnc-v2>\n")
+
+	var compressedData bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressedData)
+
+	if _, err := gzipWriter.Write([]byte(ret.String())); err != nil {
+		return nil, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressedData.Bytes(), nil
+}
+
+func (c *gnucashTransactionDataExporter) createExportedTransaction(transaction *models.Transaction, allTransactionSplits map[int64][]*models.TransactionSplit, book *gnucashExportedBook) *gnucashExportedTransaction {
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	postedDate := time.Unix(transactionUnixTime, 0).In(transactionTimeZone).Format(gnucashExportedDateTimeFormat)
+	enteredDate := postedDate
+
+	if transaction.CreatedUnixTime > 0 {
+		enteredDate = time.Unix(transaction.CreatedUnixTime, 0).In(time.UTC).Format(gnucashExportedDateTimeFormat)
+	}
+
+	exportedTransaction := &gnucashExportedTransaction{
+		id:          book.getGuid("transaction:" + utils.Int64ToString(transaction.TransactionId)),
+		postedDate:  postedDate,
+		enteredDate: enteredDate,
+		description: transaction.Comment,
+	}
+
+	reconciledState := c.getReconciledState(transaction.ReconciliationStatus)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		account := book.getAssetOrLiabilityAccount(transaction.AccountId)
+
+		if account == nil {
+			return nil
+		}
+
+		exportedTransaction.currency = account.currency
+		exportedTransaction.splits = []*gnucashExportedSplit{
+			{reconciledState: reconciledState, value: transaction.Amount, quantity: transaction.Amount, account: account},
+			{reconciledState: reconciledState, value: -transaction.Amount, quantity: -transaction.Amount, account: book.getOpeningBalancesAccount(account.currency)},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		account := book.getAssetOrLiabilityAccount(transaction.AccountId)
+
+		if account == nil {
+			return nil
+		}
+
+		// the amount of income is credited to the income accounts and debited to the asset account, and the expense is the opposite
+		sign := int64(1)
+		categoryAccountType := gnucashExpenseAccountType
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			sign = -1
+			categoryAccountType = gnucashIncomeAccountType
+		}
+
+		exportedTransaction.currency = account.currency
+		exportedTransaction.splits = []*gnucashExportedSplit{
+			{reconciledState: reconciledState, value: -sign * transaction.Amount, quantity: -sign * transaction.Amount, account: account},
+		}
+
+		if transactionSplits, exists := allTransactionSplits[transaction.TransactionId]; exists && len(transactionSplits) > 0 {
+			for i := 0; i < len(transactionSplits); i++ {
+				transactionSplit := transactionSplits[i]
+				exportedTransaction.splits = append(exportedTransaction.splits, &gnucashExportedSplit{
+					memo:            transactionSplit.Comment,
+					reconciledState: reconciledState,
+					value:           sign * transactionSplit.Amount,
+					quantity:        sign * transactionSplit.Amount,
+					account:         book.getCategoryAccount(transactionSplit.CategoryId, categoryAccountType, account.currency),
+				})
+			}
+		} else {
+			exportedTransaction.splits = append(exportedTransaction.splits, &gnucashExportedSplit{
+				reconciledState: reconciledState,
+				value:           sign * transaction.Amount,
+				quantity:        sign * transaction.Amount,
+				account:         book.getCategoryAccount(transaction.CategoryId, categoryAccountType, account.currency),
+			})
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		fromAccountId := transaction.AccountId
+		fromAmount := transaction.Amount
+		toAccountId := transaction.RelatedAccountId
+		toAmount := transaction.RelatedAccountAmount
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			fromAccountId = transaction.RelatedAccountId
+			fromAmount = transaction.RelatedAccountAmount
+			toAccountId = transaction.AccountId
+			toAmount = transaction.Amount
+		}
+
+		fromAccount := book.getAssetOrLiabilityAccount(fromAccountId)
+		toAccount := book.getAssetOrLiabilityAccount(toAccountId)
+
+		if fromAccount == nil || toAccount == nil {
+			return nil
+		}
+
+		// the values of all splits are in the currency of source account, and the quantity of destination split is in its own currency
+		exportedTransaction.currency = fromAccount.currency
+		exportedTransaction.splits = []*gnucashExportedSplit{
+			{reconciledState: reconciledState, value: -fromAmount, quantity: -fromAmount, account: fromAccount},
+			{reconciledState: reconciledState, value: fromAmount, quantity: toAmount, account: toAccount},
+		}
+	} else {
+		return nil
+	}
+
+	for i := 0; i < len(exportedTransaction.splits); i++ {
+		exportedTransaction.splits[i].id = book.getGuid(fmt.Sprintf("split:%d:%d", transaction.TransactionId, i))
+	}
+
+	return exportedTransaction
+}
+
+func (c *gnucashTransactionDataExporter) writeAccount(ret *strings.Builder, account *gnucashExportedAccount) {
+	ret.WriteString("<gnc:account version=\"2.0.0\">\n")
+	c.writeElement(ret, "  ", "act:name", account.name)
+	ret.WriteString(fmt.Sprintf("  <act:id type=\"guid\">%s</act:id>\n", account.id))
+	c.writeElement(ret, "  ", "act:type", account.accountType)
+
+	if account.currency != "" {
+		c.writeCommodity(ret, "  ", "act:commodity", account.currency)
+		c.writeElement(ret, "  ", "act:commodity-scu", gnucashExportedAmountDenominator)
+	}
+
+	if account.description != "" {
+		c.writeElement(ret, "  ", "act:description", account.description)
+	}
+
+	if account.placeholder || account.openingBalance {
+		ret.WriteString("  <act:slots>\n")
+
+		if account.placeholder {
+			c.writeSlot(ret, "    ", gnucashSlotPlaceholder, "true")
+		}
+
+		if account.openingBalance {
+			c.writeSlot(ret, "    ", gnucashSlotEquityType, gnucashSlotEquityTypeOpeningBalance)
+		}
+
+		ret.WriteString("  </act:slots>\n")
+	}
+
+	if account.parent != nil {
+		ret.WriteString(fmt.Sprintf("  <act:parent type=\"guid\">%s</act:parent>\n", account.parent.id))
+	}
+
+	ret.WriteString("</gnc:account>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeTransaction(ret *strings.Builder, transaction *gnucashExportedTransaction) {
+	ret.WriteString("<gnc:transaction version=\"2.0.0\">\n")
+	ret.WriteString(fmt.Sprintf("  <trn:id type=\"guid\">%s</trn:id>\n", transaction.id))
+	c.writeCommodity(ret, "  ", "trn:currency", transaction.currency)
+	ret.WriteString("  <trn:date-posted>\n")
+	c.writeElement(ret, "    ", "ts:date", transaction.postedDate)
+	ret.WriteString("  </trn:date-posted>\n")
+	ret.WriteString("  <trn:date-entered>\n")
+	c.writeElement(ret, "    ", "ts:date", transaction.enteredDate)
+	ret.WriteString("  </trn:date-entered>\n")
+	c.writeElement(ret, "  ", "trn:description", transaction.description)
+	ret.WriteString("  <trn:splits>\n")
+
+	for i := 0; i < len(transaction.splits); i++ {
+		split := transaction.splits[i]
+
+		ret.WriteString("    <trn:split>\n")
+		ret.WriteString(fmt.Sprintf("      <split:id type=\"guid\">%s</split:id>\n", split.id))
+
+		if split.memo != "" {
+			c.writeElement(ret, "      ", "split:memo", split.memo)
+		}
+
+		c.writeElement(ret, "      ", "split:reconciled-state", split.reconciledState)
+		c.writeElement(ret, "      ", "split:value", c.formatAmount(split.value))
+		c.writeElement(ret, "      ", "split:quantity", c.formatAmount(split.quantity))
+		ret.WriteString(fmt.Sprintf("      <split:account type=\"guid\">%s</split:account>\n", split.account.id))
+		ret.WriteString("    </trn:split>\n")
+	}
+
+	ret.WriteString("  </trn:splits>\n")
+	ret.WriteString("</gnc:transaction>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeCommodity(ret *strings.Builder, indent string, elementName string, currency string) {
+	ret.WriteString(indent + "<" + elementName + ">\n")
+	c.writeElement(ret, indent+"  ", "cmdty:space", gnucashCommodityCurrencySpace)
+	c.writeElement(ret, indent+"  ", "cmdty:id", currency)
+	ret.WriteString(indent + "</" + elementName + ">\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeSlot(ret *strings.Builder, indent string, key string, value string) {
+	ret.WriteString(indent + "<slot>\n")
+	c.writeElement(ret, indent+"  ", "slot:key", key)
+	ret.WriteString(indent + "  <slot:value type=\"string\">")
+	_ = xml.EscapeText(ret, []byte(value))
+	ret.WriteString("</slot:value>\n")
+	ret.WriteString(indent + "</slot>\n")
+}
+
+func (c *gnucashTransactionDataExporter) writeElement(ret *strings.Builder, indent string, elementName string, value string) {
+	ret.WriteString(indent + "<" + elementName + ">")
+	_ = xml.EscapeText(ret, []byte(value))
+	ret.WriteString("</" + elementName + ">\n")
+}
+
+// formatAmount returns the amount in gnucash numeric format, e.g. 12345/100
+func (c *gnucashTransactionDataExporter) formatAmount(amount int64) string {
+	return utils.Int64ToString(amount) + "/" + gnucashExportedAmountDenominator
+}
+
+func (c *gnucashTransactionDataExporter) getReconciledState(status models.TransactionReconciliationStatus) string {
+	if status == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return gnucashExportedReconciledState
+	} else if status == models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+		return gnucashExportedClearedState
+	}
+
+	return gnucashExportedNotReconciledState
+}
+
+func (b *gnucashExportedBook) getRootAccount() *gnucashExportedAccount {
+	if account, exists := b.accountsByKey["root"]; exists {
+		return account
+	}
+
+	return b.createAccount("root", gnucashExportedRootAccountName, gnucashRootAccountType, "", "", nil)
+}
+
+func (b *gnucashExportedBook) getAssetOrLiabilityAccount(accountId int64) *gnucashExportedAccount {
+	account, exists := b.accountMap[accountId]
+
+	if !exists {
+		return nil
+	}
+
+	accountType := b.getAccountType(account)
+	parent := b.getPlaceholderAccount("top:"+gnucashExportedAssetsAccountName, gnucashExportedAssetsAccountName, gnucashAssetAccountType, account.Currency, b.getRootAccount())
+
+	if account.Category.IsLiability() {
+		parent = b.getPlaceholderAccount("top:"+gnucashExportedLiabilitiesAccountName, gnucashExportedLiabilitiesAccountName, gnucashLiabilityAccountType, account.Currency, b.getRootAccount())
+	}
+
+	if parentAccount, exists := b.accountMap[account.ParentAccountId]; exists && account.ParentAccountId > 0 {
+		parent = b.getPlaceholderAccount("account:"+utils.Int64ToString(parentAccount.AccountId), parentAccount.Name, accountType, account.Currency, parent)
+
+		if parent.description == "" {
+			parent.description = parentAccount.Comment
+		}
+	}
+
+	return b.getLeafAccount("account:"+utils.Int64ToString(accountId), account.Name, accountType, account.Currency, account.Comment, parent)
+}
+
+func (b *gnucashExportedBook) getCategoryAccount(categoryId int64, accountType string, currency string) *gnucashExportedAccount {
+	topLevelAccountName := gnucashExportedExpensesAccountName
+
+	if accountType == gnucashIncomeAccountType {
+		topLevelAccountName = gnucashExportedIncomeAccountName
+	}
+
+	parent := b.getPlaceholderAccount("top:"+topLevelAccountName, topLevelAccountName, accountType, currency, b.getRootAccount())
+	category, exists := b.categoryMap[categoryId]
+
+	if !exists {
+		return b.getLeafAccount("uncategorized:"+accountType, gnucashExportedUncategorizedAccountName, accountType, currency, "", parent)
+	}
+
+	if parentCategory, exists := b.categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		parent = b.getPlaceholderAccount("category:"+utils.Int64ToString(parentCategory.CategoryId), parentCategory.Name, accountType, currency, parent)
+
+		if parent.description == "" {
+			parent.description = parentCategory.Comment
+		}
+	}
+
+	return b.getLeafAccount("category:"+utils.Int64ToString(categoryId), category.Name, accountType, currency, category.Comment, parent)
+}
+
+func (b *gnucashExportedBook) getOpeningBalancesAccount(currency string) *gnucashExportedAccount {
+	parent := b.getPlaceholderAccount("top:"+gnucashExportedEquityAccountName, gnucashExportedEquityAccountName, gnucashEquityAccountType, currency, b.getRootAccount())
+	account := b.getLeafAccount("opening-balances", gnucashExportedOpeningBalancesAccountName, gnucashEquityAccountType, currency, "", parent)
+	account.openingBalance = true
+
+	return account
+}
+
+func (b *gnucashExportedBook) getPlaceholderAccount(key string, name string, accountType string, currency string, parent *gnucashExportedAccount) *gnucashExportedAccount {
+	if account, exists := b.accountsByKey[key]; exists {
+		return account
+	}
+
+	account := b.createAccount(key, name, accountType, currency, "", parent)
+	account.placeholder = true
+
+	return account
+}
+
+// getLeafAccount returns the account which can hold splits, the gnucash account can only hold the amount of its own currency, so the same account used in another currency would be created as a sibling account with currency suffix
+func (b *gnucashExportedBook) getLeafAccount(key string, name string, accountType string, currency string, description string, parent *gnucashExportedAccount) *gnucashExportedAccount {
+	account, exists := b.accountsByKey[key]
+
+	if !exists {
+		return b.createAccount(key, name, accountType, currency, description, parent)
+	}
+
+	if account.currency == currency {
+		return account
+	}
+
+	return b.getLeafAccount(key+":"+currency, name+" ("+currency+")", accountType, currency, description, parent)
+}
+
+func (b *gnucashExportedBook) createAccount(key string, name string, accountType string, currency string, description string, parent *gnucashExportedAccount) *gnucashExportedAccount {
+	account := &gnucashExportedAccount{
+		id:          b.getGuid("account:" + key),
+		name:        name,
+		accountType: accountType,
+		currency:    currency,
+		description: description,
+		parent:      parent,
+	}
+
+	b.accounts = append(b.accounts, account)
+	b.accountsByKey[key] = account
+
+	return account
+}
+
+func (b *gnucashExportedBook) getAccountType(account *models.Account) string {
+	switch account.Category {
+	case models.ACCOUNT_CATEGORY_CASH:
+		return gnucashCashAccountType
+	case models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT, models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT:
+		return gnucashBankAccountType
+	case models.ACCOUNT_CATEGORY_CREDIT_CARD:
+		return gnucashCreditAccountType
+	}
+
+	if account.Category.IsLiability() {
+		return gnucashLiabilityAccountType
+	}
+
+	return gnucashAssetAccountType
+}
+
+// getGuid returns the stable guid of the specified object of current user
+func (b *gnucashExportedBook) getGuid(key string) string {
+	return utils.MD5EncodeToString([]byte(utils.Int64ToString(b.uid) + ":" + key))
+}
@@ -0,0 +1,295 @@
+package gnucash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGnuCashTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap := createTestExportedTransactions()
+	transactionSplits := make(map[int64][]*models.TransactionSplit, 1)
+	transactionSplits[6] = []*models.TransactionSplit{
+		{TransactionId: 6, CategoryId: 4, Amount: 300, Comment: "Dinner"},
+		{TransactionId: 6, CategoryId: 5, Amount: 200, Comment: "Coffee & Tea"},
+	}
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, transactionSplits)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x1F), content[0])
+	assert.Equal(t, byte(0x8B), content[1])
+
+	reader, err := createNewGnuCashDatabaseReader(content)
+	assert.Nil(t, err)
+
+	database, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(database.Counts))
+	assert.Equal(t, "book", database.Counts[0].Key)
+	assert.Equal(t, "1", database.Counts[0].Value)
+	assert.Equal(t, 1, len(database.Books))
+
+	book := database.Books[0]
+	assert.Equal(t, 32, len(book.Id))
+	assert.Equal(t, 3, len(book.Counts))
+	assert.Equal(t, "commodity", book.Counts[0].Key)
+	assert.Equal(t, "2", book.Counts[0].Value)
+	assert.Equal(t, "account", book.Counts[1].Key)
+	assert.Equal(t, "16", book.Counts[1].Value)
+	assert.Equal(t, "transaction", book.Counts[2].Key)
+	assert.Equal(t, "6", book.Counts[2].Value)
+
+	accounts := make(map[string]*gnucashAccountData, len(book.Accounts))
+	accountPaths := make([]string, 0, len(book.Accounts))
+
+	for i := 0; i < len(book.Accounts); i++ {
+		accounts[book.Accounts[i].Id] = book.Accounts[i]
+	}
+
+	for i := 0; i < len(book.Accounts); i++ {
+		account := book.Accounts[i]
+		path := account.Name + ":" + account.AccountType
+
+		if account.Commodity != nil {
+			path = path + ":" + account.Commodity.Id
+		}
+
+		for parent := accounts[account.ParentId]; parent != nil; parent = accounts[parent.ParentId] {
+			path = parent.Name + "/" + path
+		}
+
+		accountPaths = append(accountPaths, path)
+	}
+
+	assert.Equal(t, []string{
+		"Root Account:ROOT",
+		"Root Account/Assets:ASSET:CNY",
+		"Root Account/Assets/Bank:BANK:CNY",
+		"Root Account/Equity:EQUITY:CNY",
+		"Root Account/Equity/Opening Balances:EQUITY:CNY",
+		"Root Account/Income:INCOME:CNY",
+		"Root Account/Income/Work:INCOME:CNY",
+		"Root Account/Income/Work/Salary:INCOME:CNY",
+		"Root Account/Liabilities:LIABILITY:USD",
+		"Root Account/Liabilities/Cards:CREDIT:USD",
+		"Root Account/Liabilities/Cards/Credit Card:CREDIT:USD",
+		"Root Account/Expenses:EXPENSE:USD",
+		"Root Account/Expenses/Food:EXPENSE:USD",
+		"Root Account/Expenses/Food/Dinner:EXPENSE:USD",
+		"Root Account/Expenses/Food/Dinner (CNY):EXPENSE:CNY",
+		"Root Account/Expenses/Food/Coffee & Tea:EXPENSE:CNY",
+	}, accountPaths)
+
+	assert.Equal(t, []*gnucashSlotData{{Key: "placeholder", Value: "true"}}, book.Accounts[1].Slots)
+	assert.Equal(t, []*gnucashSlotData{{Key: "equity-type", Value: "opening-balance"}}, book.Accounts[4].Slots)
+	assert.Equal(t, 0, len(book.Accounts[2].Slots))
+	assert.Equal(t, "Salary account", book.Accounts[2].Description)
+
+	assert.Equal(t, 6, len(book.Transactions))
+
+	assert.Equal(t, "2024-09-01 08:00:00 +0800", book.Transactions[0].PostedDate)
+	assert.Equal(t, "CNY", book.Transactions[0].Currency.Id)
+
+	assert.Equal(t, "2024-09-04 00:00:00 +0000", book.Transactions[3].PostedDate)
+	assert.Equal(t, "CNY", book.Transactions[3].Currency.Id)
+	assert.Equal(t, 2, len(book.Transactions[3].Splits))
+	assert.Equal(t, "y", book.Transactions[3].Splits[0].ReconciledState)
+	assert.Equal(t, "-7000/100", book.Transactions[3].Splits[0].Value)
+	assert.Equal(t, "-7000/100", book.Transactions[3].Splits[0].Quantity)
+	assert.Equal(t, "7000/100", book.Transactions[3].Splits[1].Value)
+	assert.Equal(t, "1000/100", book.Transactions[3].Splits[1].Quantity)
+
+	assert.Equal(t, "Dinner and coffee", book.Transactions[5].Description)
+	assert.Equal(t, 3, len(book.Transactions[5].Splits))
+	assert.Equal(t, "c", book.Transactions[5].Splits[0].ReconciledState)
+	assert.Equal(t, "-500/100", book.Transactions[5].Splits[0].Quantity)
+	assert.Equal(t, "Bank", accounts[book.Transactions[5].Splits[0].Account].Name)
+	assert.Equal(t, "Dinner", book.Transactions[5].Splits[1].Memo)
+	assert.Equal(t, "Dinner (CNY)", accounts[book.Transactions[5].Splits[1].Account].Name)
+	assert.Equal(t, "300/100", book.Transactions[5].Splits[1].Quantity)
+	assert.Equal(t, "Coffee & Tea", book.Transactions[5].Splits[2].Memo)
+	assert.Equal(t, "Coffee & Tea", accounts[book.Transactions[5].Splits[2].Account].Name)
+	assert.Equal(t, "200/100", book.Transactions[5].Splits[2].Quantity)
+}
+
+func TestGnuCashTransactionDataFileExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	importer := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap := createTestExportedTransactions()
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Bank", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Bank", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1050), allNewTransactions[2].Amount)
+	assert.Equal(t, "Credit Card", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[2].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Dinner", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(7000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(1000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Bank", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[3].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[3].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, int64(2000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Bank", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Dinner (CNY)", allNewTransactions[4].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, int64(500), allNewTransactions[5].Amount)
+	assert.Equal(t, "Bank", allNewTransactions[5].OriginalSourceAccountName)
+	assert.Equal(t, "Dinner (CNY)", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, "Dinner and coffee", allNewTransactions[5].Comment)
+}
+
+func TestGnuCashTransactionDataFileExporterToExportedContent_ImportExportedSplitTransaction(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	importer := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap := createTestExportedTransactions()
+	transactionSplits := make(map[int64][]*models.TransactionSplit, 1)
+	transactionSplits[6] = []*models.TransactionSplit{
+		{TransactionId: 6, CategoryId: 4, Amount: 300},
+		{TransactionId: 6, CategoryId: 5, Amount: 200},
+	}
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, transactionSplits)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+}
+
+func createTestExportedTransactions() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory) {
+	transactions := []*models.Transaction{
+		{
+			TransactionId:     1,
+			TransactionTime:   1725148800000,
+			Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+			TimezoneUtcOffset: 480,
+			AccountId:         1,
+			Amount:            100000,
+		},
+		{
+			TransactionId:   2,
+			TransactionTime: 1725235200000,
+			Type:            models.TRANSACTION_DB_TYPE_INCOME,
+			CategoryId:      2,
+			AccountId:       1,
+			Amount:          12345,
+			Comment:         "Salary",
+		},
+		{
+			TransactionId:   3,
+			TransactionTime: 1725321600000,
+			Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:      4,
+			AccountId:       3,
+			Amount:          1050,
+		},
+		{
+			TransactionId:        5,
+			TransactionTime:      1725408000000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			AccountId:            3,
+			Amount:               1000,
+			RelatedId:            4,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 7000,
+			ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED,
+		},
+		{
+			TransactionId:        4,
+			TransactionTime:      1725408000000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			AccountId:            1,
+			Amount:               7000,
+			RelatedId:            5,
+			RelatedAccountId:     3,
+			RelatedAccountAmount: 1000,
+			ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED,
+		},
+		{
+			TransactionId:        6,
+			TransactionTime:      1725580800000,
+			Type:                 models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:           4,
+			AccountId:            1,
+			Amount:               500,
+			Comment:              "Dinner and coffee",
+			ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+		},
+		{
+			TransactionId:   7,
+			TransactionTime: 1725494400000,
+			Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:      4,
+			AccountId:       1,
+			Amount:          2000,
+		},
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY", Comment: "Salary account"},
+		2: {AccountId: 2, Name: "Cards", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS},
+		3: {AccountId: 3, Name: "Credit Card", ParentAccountId: 2, Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "USD"},
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		1: {CategoryId: 1, Type: models.CATEGORY_TYPE_INCOME, Name: "Work"},
+		2: {CategoryId: 2, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 1, Name: "Salary"},
+		3: {CategoryId: 3, Type: models.CATEGORY_TYPE_EXPENSE, Name: "Food"},
+		4: {CategoryId: 4, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 3, Name: "Dinner"},
+		5: {CategoryId: 5, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 3, Name: "Coffee & Tea"},
+	}
+
+	return transactions, accountMap, categoryMap
+}
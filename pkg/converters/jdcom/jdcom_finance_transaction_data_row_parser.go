@@ -42,6 +42,7 @@ var jdComFinanceTransactionSupportedColumns = map[datatable.TransactionDataTable
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 var jdComFinanceTransactionTypeNameMapping = map[models.TransactionType]string{
@@ -94,6 +95,12 @@ func (p *jdComFinanceTransactionDataRowParser) Parse(ctx core.Context, user *mod
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if p.hasOriginalColumn(jdComFinanceTransactionMerchantNameColumnName) {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = dataRow.GetData(jdComFinanceTransactionMerchantNameColumnName)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	if data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] == jdComFinanceTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER] {
 		memo := dataRow.GetData(jdComFinanceTransactionMemoColumnName)
 
@@ -0,0 +1,99 @@
+package ledger
+
+import "strings"
+
+// ledgerAccountType represents the ledger account type
+type ledgerAccountType byte
+
+// Ledger account types
+const (
+	ledgerUnknownAccountType     ledgerAccountType = 0
+	ledgerAssetsAccountType      ledgerAccountType = 1
+	ledgerLiabilitiesAccountType ledgerAccountType = 2
+	ledgerEquityAccountType      ledgerAccountType = 3
+	ledgerIncomeAccountType      ledgerAccountType = 4
+	ledgerExpensesAccountType    ledgerAccountType = 5
+)
+
+// ledgerTransactionState represents the ledger transaction state
+type ledgerTransactionState string
+
+// Ledger transaction states
+const (
+	ledgerTransactionStateUncleared ledgerTransactionState = ""
+	ledgerTransactionStateCleared   ledgerTransactionState = "*"
+	ledgerTransactionStatePending   ledgerTransactionState = "!"
+)
+
+// ledgerData defines the structure of ledger journal data
+type ledgerData struct {
+	Accounts        map[string]*ledgerAccount
+	CommodityPrices map[string][]*ledgerCommodityPrice
+	Transactions    []*ledgerTransactionEntry
+}
+
+// ledgerAccount defines the structure of ledger account
+type ledgerAccount struct {
+	Name        string
+	AccountType ledgerAccountType
+	Declared    bool
+}
+
+// ledgerCommodityPrice defines the structure of ledger commodity price which is declared by P directive
+type ledgerCommodityPrice struct {
+	Date           string
+	Commodity      string
+	Price          string
+	PriceCommodity string
+}
+
+// ledgerTransactionEntry defines the structure of ledger transaction entry
+type ledgerTransactionEntry struct {
+	Date        string
+	State       ledgerTransactionState
+	Code        string
+	Description string
+	Postings    []*ledgerPosting
+	Tags        []string
+}
+
+// ledgerPosting defines the structure of ledger transaction posting
+type ledgerPosting struct {
+	Account            string
+	Virtual            bool
+	Amount             string
+	OriginalAmount     string
+	Commodity          string
+	TotalCost          string
+	TotalCostCommodity string
+	Tags               []string
+}
+
+func (a *ledgerAccount) isOpeningBalanceEquityAccount() bool {
+	if a.AccountType != ledgerEquityAccountType {
+		return false
+	}
+
+	nameItems := strings.Split(a.Name, ledgerAccountNameItemsSeparator)
+
+	if len(nameItems) != 2 {
+		return false
+	}
+
+	name := strings.ToLower(nameItems[1])
+	name = strings.ReplaceAll(name, " ", "")
+	name = strings.ReplaceAll(name, "-", "")
+
+	return name == ledgerEquityAccountNameOpeningBalances || name == ledgerEquityAccountNameOpeningBalance
+}
+
+// getPayee returns the payee of the transaction description, hledger uses the part before the pipe character as payee
+func (t *ledgerTransactionEntry) getPayee() string {
+	pipeIndex := strings.Index(t.Description, ledgerDescriptionPayeeSeparator)
+
+	if pipeIndex < 0 {
+		return t.Description
+	}
+
+	return strings.TrimSpace(t.Description[:pipeIndex])
+}
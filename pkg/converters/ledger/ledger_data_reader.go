@@ -0,0 +1,771 @@
+package ledger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerAccountNameItemsSeparator = ":"
+const ledgerDescriptionPayeeSeparator = "|"
+const ledgerEquityAccountNameOpeningBalances = "openingbalances"
+const ledgerEquityAccountNameOpeningBalance = "openingbalance"
+
+const ledgerCommentPrefix = ';'
+const ledgerLineCommentPrefixes = ";#%|*"
+const ledgerTagSeparator = ':'
+const ledgerTagsSeparator = ","
+const ledgerCostPrefix = "@"
+const ledgerTotalCostPrefix = "@@"
+const ledgerBalanceAssertionPrefix = '='
+const ledgerCommodityQuote = '"'
+const ledgerAccountTypeTagName = "type"
+
+const ledgerDirectiveAccount = "account"
+const ledgerDirectiveCommodityPrice = "P"
+const ledgerDirectiveInclude = "include"
+const ledgerDirectiveHledgerInclude = "!include"
+const ledgerDirectiveComment = "comment"
+const ledgerDirectiveTest = "test"
+const ledgerDirectiveEndComment = "end comment"
+const ledgerDirectiveEndTest = "end test"
+const ledgerDirectiveYear = "year"
+const ledgerDirectiveShortYear = "Y"
+const ledgerDirectiveApplyYear = "apply year"
+
+var ledgerAccountTypeNameMap = map[string]ledgerAccountType{
+	"assets":      ledgerAssetsAccountType,
+	"asset":       ledgerAssetsAccountType,
+	"liabilities": ledgerLiabilitiesAccountType,
+	"liability":   ledgerLiabilitiesAccountType,
+	"equity":      ledgerEquityAccountType,
+	"income":      ledgerIncomeAccountType,
+	"revenue":     ledgerIncomeAccountType,
+	"revenues":    ledgerIncomeAccountType,
+	"expenses":    ledgerExpensesAccountType,
+	"expense":     ledgerExpensesAccountType,
+}
+
+// ledgerAccountTypeTagValueMap is the account type mapping of the "type" tag in hledger account directive
+var ledgerAccountTypeTagValueMap = map[string]ledgerAccountType{
+	"a":         ledgerAssetsAccountType,
+	"asset":     ledgerAssetsAccountType,
+	"c":         ledgerAssetsAccountType,
+	"cash":      ledgerAssetsAccountType,
+	"l":         ledgerLiabilitiesAccountType,
+	"liability": ledgerLiabilitiesAccountType,
+	"e":         ledgerEquityAccountType,
+	"equity":    ledgerEquityAccountType,
+	"r":         ledgerIncomeAccountType,
+	"revenue":   ledgerIncomeAccountType,
+	"x":         ledgerExpensesAccountType,
+	"expense":   ledgerExpensesAccountType,
+}
+
+// ledgerDataReader defines the structure of ledger journal data reader
+type ledgerDataReader struct {
+	allLines []string
+}
+
+// read returns the imported ledger journal data
+// Reference: https://ledger-cli.org/doc/ledger3.html#Journal-Format
+// Reference: https://hledger.org/hledger.html#journal
+func (r *ledgerDataReader) read(ctx core.Context) (*ledgerData, error) {
+	if len(r.allLines) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	data := &ledgerData{
+		Accounts:        make(map[string]*ledgerAccount),
+		CommodityPrices: make(map[string][]*ledgerCommodityPrice),
+		Transactions:    make([]*ledgerTransactionEntry, 0),
+	}
+
+	var err error
+	var currentTransactionEntry *ledgerTransactionEntry
+	var currentTransactionPosting *ledgerPosting
+	var currentAccount *ledgerAccount
+	defaultYear := ""
+	inBlockComment := false
+	skipIndentedLines := false
+
+	for i := 0; i < len(r.allLines); i++ {
+		line := strings.TrimRight(r.allLines[i], " \t\r")
+
+		if inBlockComment {
+			if line == ledgerDirectiveEndComment || line == ledgerDirectiveEndTest {
+				inBlockComment = false
+			}
+
+			continue
+		}
+
+		if len(line) == 0 { // empty line ends the current transaction
+			currentTransactionEntry, currentTransactionPosting, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' { // original line has space prefix, maybe transaction posting, comment or sub directive line
+			if skipIndentedLines {
+				continue
+			}
+
+			content := strings.TrimSpace(line)
+
+			if currentTransactionEntry != nil {
+				if content[0] == ledgerCommentPrefix { // comment line of transaction or posting
+					tags, _ := r.readTagsFromComment(content[1:])
+
+					if currentTransactionPosting != nil {
+						currentTransactionPosting.Tags = r.appendTags(currentTransactionPosting.Tags, tags)
+					} else {
+						currentTransactionEntry.Tags = r.appendTags(currentTransactionEntry.Tags, tags)
+					}
+
+					continue
+				}
+
+				currentTransactionPosting, err = r.readTransactionPostingLine(ctx, i, content, data)
+
+				if err != nil {
+					return nil, err
+				}
+
+				currentTransactionEntry.Postings = append(currentTransactionEntry.Postings, currentTransactionPosting)
+			} else if currentAccount != nil && content[0] == ledgerCommentPrefix { // comment line of account directive
+				r.setAccountTypeByComment(currentAccount, content[1:])
+			}
+
+			continue
+		}
+
+		// the line without space prefix ends the current transaction or directive
+		currentTransactionEntry, currentTransactionPosting, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+		if err != nil {
+			return nil, err
+		}
+
+		currentAccount = nil
+		skipIndentedLines = false
+
+		if strings.IndexByte(ledgerLineCommentPrefixes, line[0]) >= 0 { // skip comment lines
+			continue
+		}
+
+		if '0' <= line[0] && line[0] <= '9' { // original line has date as first item
+			currentTransactionEntry, err = r.readTransactionLine(ctx, i, line, defaultYear)
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		directive, directiveArgument := r.splitFirstItem(line)
+
+		if directive == ledgerDirectiveInclude || directive == ledgerDirectiveHledgerInclude { // not support include directive
+			return nil, errs.ErrLedgerFileNotSupportInclude
+		} else if directive == ledgerDirectiveAccount {
+			currentAccount = r.readAccountLine(ctx, i, directiveArgument, data)
+		} else if directive == ledgerDirectiveCommodityPrice {
+			r.readCommodityPriceLine(ctx, i, directiveArgument, defaultYear, data)
+		} else if line == ledgerDirectiveComment || line == ledgerDirectiveTest {
+			inBlockComment = true
+		} else if directive == ledgerDirectiveYear || directive == ledgerDirectiveShortYear || strings.HasPrefix(line, ledgerDirectiveApplyYear+" ") {
+			_, year := r.splitFirstItem(strings.TrimPrefix(line, "apply "))
+
+			if _, err := utils.StringToInt(year); err == nil && len(year) == 4 {
+				defaultYear = year
+			} else {
+				log.Warnf(ctx, "[ledger_data_reader.read] cannot parse year line#%d \"%s\", because year is invalid", i, line)
+			}
+		} else { // skip automated transactions, periodic transactions and other directives with their sub directives
+			skipIndentedLines = true
+		}
+	}
+
+	_, _, err = r.updateCurrentState(ctx, data, currentTransactionEntry)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *ledgerDataReader) updateCurrentState(ctx core.Context, data *ledgerData, currentTransactionEntry *ledgerTransactionEntry) (*ledgerTransactionEntry, *ledgerPosting, error) {
+	if currentTransactionEntry != nil {
+		err := r.fillElidedPostingAmount(ctx, currentTransactionEntry)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data.Transactions = append(data.Transactions, currentTransactionEntry)
+	}
+
+	return nil, nil, nil
+}
+
+// fillElidedPostingAmount sets the amount of the posting without amount, so that the transaction is balanced
+func (r *ledgerDataReader) fillElidedPostingAmount(ctx core.Context, transactionEntry *ledgerTransactionEntry) error {
+	var elidedPosting *ledgerPosting
+	commodityTotalAmounts := make(map[string]int64)
+
+	for i := 0; i < len(transactionEntry.Postings); i++ {
+		posting := transactionEntry.Postings[i]
+
+		if posting.Virtual { // unbalanced virtual postings are not required to be balanced
+			continue
+		}
+
+		if posting.Amount == "" {
+			if elidedPosting != nil {
+				log.Warnf(ctx, "[ledger_data_reader.fillElidedPostingAmount] cannot parse transaction in \"%s\", because there are more than one postings without amount", transactionEntry.Date)
+				return errs.ErrInvalidLedgerFile
+			}
+
+			elidedPosting = posting
+			continue
+		}
+
+		textualAmount := posting.Amount
+		commodity := posting.Commodity
+
+		// the posting with cost is balanced by its total cost
+		if posting.TotalCost != "" {
+			textualAmount = posting.TotalCost
+			commodity = posting.TotalCostCommodity
+		}
+
+		amount, err := utils.ParseAmount(textualAmount)
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.fillElidedPostingAmount] cannot parse amount \"%s\", because %s", textualAmount, err.Error())
+			return errs.ErrAmountInvalid
+		}
+
+		commodityTotalAmounts[commodity] += amount
+	}
+
+	if elidedPosting == nil {
+		return nil
+	}
+
+	if len(commodityTotalAmounts) != 1 {
+		log.Warnf(ctx, "[ledger_data_reader.fillElidedPostingAmount] cannot infer amount of posting \"%s\" in \"%s\", because other postings have %d commodities", elidedPosting.Account, transactionEntry.Date, len(commodityTotalAmounts))
+		return errs.ErrInvalidLedgerFile
+	}
+
+	for commodity, totalAmount := range commodityTotalAmounts {
+		elidedPosting.Amount = utils.FormatAmount(-totalAmount)
+		elidedPosting.Commodity = commodity
+	}
+
+	return nil
+}
+
+func (r *ledgerDataReader) readTransactionLine(ctx core.Context, lineIndex int, line string, defaultYear string) (*ledgerTransactionEntry, error) {
+	// DATE[=DATE2] [STATE] [(CODE)] DESCRIPTION [; COMMENT]
+	content, comment := r.splitComment(line)
+	dateText, remain := r.splitFirstItem(content)
+	date, err := r.parseDate(dateText, defaultYear)
+
+	if err != nil {
+		log.Warnf(ctx, "[ledger_data_reader.readTransactionLine] cannot parse transaction line#%d \"%s\", because date is invalid", lineIndex, line)
+		return nil, errs.ErrTransactionTimeInvalid
+	}
+
+	transactionEntry := &ledgerTransactionEntry{
+		Date:     date,
+		State:    ledgerTransactionStateUncleared,
+		Postings: make([]*ledgerPosting, 0),
+		Tags:     make([]string, 0),
+	}
+
+	if strings.HasPrefix(remain, string(ledgerTransactionStateCleared)) {
+		transactionEntry.State = ledgerTransactionStateCleared
+		remain = strings.TrimSpace(remain[len(ledgerTransactionStateCleared):])
+	} else if strings.HasPrefix(remain, string(ledgerTransactionStatePending)) {
+		transactionEntry.State = ledgerTransactionStatePending
+		remain = strings.TrimSpace(remain[len(ledgerTransactionStatePending):])
+	}
+
+	if strings.HasPrefix(remain, "(") {
+		codeEndIndex := strings.Index(remain, ")")
+
+		if codeEndIndex > 0 {
+			transactionEntry.Code = remain[1:codeEndIndex]
+			remain = strings.TrimSpace(remain[codeEndIndex+1:])
+		}
+	}
+
+	transactionEntry.Description = remain
+	transactionEntry.Tags, _ = r.readTagsFromComment(comment)
+
+	return transactionEntry, nil
+}
+
+func (r *ledgerDataReader) readTransactionPostingLine(ctx core.Context, lineIndex int, line string, data *ledgerData) (*ledgerPosting, error) {
+	// [STATE] ACCOUNT  [AMOUNT] [@ PRICE | @@ TOTAL PRICE] [= BALANCE ASSERTION] [; COMMENT]
+	content, comment := r.splitComment(line)
+
+	if strings.HasPrefix(content, string(ledgerTransactionStateCleared)+" ") || strings.HasPrefix(content, string(ledgerTransactionStatePending)+" ") {
+		content = strings.TrimSpace(content[1:])
+	}
+
+	accountName, amountText := r.splitAccountNameAndAmount(content)
+	posting := &ledgerPosting{}
+	posting.Tags, _ = r.readTagsFromComment(comment)
+
+	if len(accountName) > 2 && accountName[0] == '(' && accountName[len(accountName)-1] == ')' { // unbalanced virtual posting
+		posting.Virtual = true
+		accountName = accountName[1 : len(accountName)-1]
+	} else if len(accountName) > 2 && accountName[0] == '[' && accountName[len(accountName)-1] == ']' { // balanced virtual posting
+		accountName = accountName[1 : len(accountName)-1]
+	}
+
+	if accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse transaction posting line#%d \"%s\", because missing account name", lineIndex, line)
+		return nil, errs.ErrMissingAccountData
+	}
+
+	posting.Account = accountName
+
+	if balanceAssertionIndex := strings.IndexByte(amountText, ledgerBalanceAssertionPrefix); balanceAssertionIndex >= 0 {
+		if strings.TrimSpace(amountText[:balanceAssertionIndex]) == "" {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse transaction posting line#%d \"%s\", because balance assignment is not supported", lineIndex, line)
+			return nil, errs.ErrAmountInvalid
+		}
+
+		amountText = amountText[:balanceAssertionIndex]
+	}
+
+	amountText = r.removeLotPrice(amountText)
+	costText := ""
+	isTotalCost := false
+
+	if totalCostIndex := strings.Index(amountText, ledgerTotalCostPrefix); totalCostIndex >= 0 {
+		costText = strings.TrimSpace(amountText[totalCostIndex+len(ledgerTotalCostPrefix):])
+		amountText = amountText[:totalCostIndex]
+		isTotalCost = true
+	} else if costIndex := strings.Index(amountText, ledgerCostPrefix); costIndex >= 0 {
+		costText = strings.TrimSpace(amountText[costIndex+len(ledgerCostPrefix):])
+		amountText = amountText[:costIndex]
+	}
+
+	posting.OriginalAmount = strings.TrimSpace(amountText)
+
+	if posting.OriginalAmount != "" {
+		amountExpression, commodity, err := r.parseAmountExpressionAndCommodity(posting.OriginalAmount)
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse amount in line#%d \"%s\", because %s", lineIndex, line, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		posting.Amount, err = beancount.EvaluateAmountExpression(ctx, amountExpression)
+
+		if err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot evaluate amount expression in line#%d \"%s\", because %s", lineIndex, line, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		posting.Commodity = commodity
+
+		if costText != "" {
+			costExpression, costCommodity, err := r.parseAmountExpressionAndCommodity(costText)
+
+			if err != nil {
+				log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse cost in line#%d \"%s\", because %s", lineIndex, line, err.Error())
+				return nil, errs.ErrAmountInvalid
+			}
+
+			if !isTotalCost { // the total cost is the amount multiplied by the unit cost
+				costExpression = "(" + amountExpression + ")*(" + costExpression + ")"
+			}
+
+			posting.TotalCost, err = beancount.EvaluateAmountExpression(ctx, costExpression)
+
+			if err != nil {
+				log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot evaluate cost expression in line#%d \"%s\", because %s", lineIndex, line, err.Error())
+				return nil, errs.ErrAmountInvalid
+			}
+
+			// the total cost has the same sign as the amount
+			if isTotalCost && strings.HasPrefix(posting.Amount, "-") && !strings.HasPrefix(posting.TotalCost, "-") {
+				posting.TotalCost = "-" + posting.TotalCost
+			}
+
+			posting.TotalCostCommodity = costCommodity
+		}
+	}
+
+	if _, exists := data.Accounts[posting.Account]; !exists {
+		r.createAccount(data, posting.Account)
+	}
+
+	return posting, nil
+}
+
+func (r *ledgerDataReader) readAccountLine(ctx core.Context, lineIndex int, content string, data *ledgerData) *ledgerAccount {
+	// account ACCOUNT [; COMMENT]
+	content, comment := r.splitComment(content)
+	accountName, _ := r.splitAccountNameAndAmount(content)
+
+	if accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readAccountLine] cannot parse account line#%d \"%s\", because missing account name", lineIndex, content)
+		return nil
+	}
+
+	account, exists := data.Accounts[accountName]
+
+	if !exists {
+		account = r.createAccount(data, accountName)
+	}
+
+	account.Declared = true
+	r.setAccountTypeByComment(account, comment)
+
+	return account
+}
+
+func (r *ledgerDataReader) readCommodityPriceLine(ctx core.Context, lineIndex int, content string, defaultYear string, data *ledgerData) {
+	// P DATE [TIME] COMMODITY PRICE [; COMMENT]
+	content, _ = r.splitComment(content)
+	dateText, remain := r.splitFirstItem(content)
+	date, err := r.parseDate(dateText, defaultYear)
+
+	if err != nil {
+		log.Warnf(ctx, "[ledger_data_reader.readCommodityPriceLine] cannot parse commodity price line#%d \"%s\", because date is invalid", lineIndex, content)
+		return
+	}
+
+	if timeText, timeRemain := r.splitFirstItem(remain); strings.Contains(timeText, ":") {
+		remain = timeRemain
+	}
+
+	commodity := ""
+
+	if len(remain) > 0 && remain[0] == ledgerCommodityQuote {
+		commodityEndIndex := strings.IndexByte(remain[1:], ledgerCommodityQuote)
+
+		if commodityEndIndex >= 0 {
+			commodity = remain[1 : commodityEndIndex+1]
+			remain = strings.TrimSpace(remain[commodityEndIndex+2:])
+		}
+	} else {
+		commodity, remain = r.splitFirstItem(remain)
+	}
+
+	priceExpression, priceCommodity, err := r.parseAmountExpressionAndCommodity(remain)
+
+	if commodity == "" || err != nil {
+		log.Warnf(ctx, "[ledger_data_reader.readCommodityPriceLine] cannot parse commodity price line#%d \"%s\", because commodity or price is invalid", lineIndex, content)
+		return
+	}
+
+	data.CommodityPrices[commodity] = append(data.CommodityPrices[commodity], &ledgerCommodityPrice{
+		Date:           date,
+		Commodity:      commodity,
+		Price:          priceExpression,
+		PriceCommodity: priceCommodity,
+	})
+}
+
+func (r *ledgerDataReader) createAccount(data *ledgerData, accountName string) *ledgerAccount {
+	account := &ledgerAccount{
+		Name:        accountName,
+		AccountType: ledgerUnknownAccountType,
+	}
+
+	accountNameItems := strings.Split(accountName, ledgerAccountNameItemsSeparator)
+
+	if accountType, exists := ledgerAccountTypeNameMap[strings.ToLower(accountNameItems[0])]; exists {
+		account.AccountType = accountType
+	}
+
+	data.Accounts[accountName] = account
+	return account
+}
+
+func (r *ledgerDataReader) setAccountTypeByComment(account *ledgerAccount, comment string) {
+	_, tagValues := r.readTagsFromComment(comment)
+	accountTypeTagValue, exists := tagValues[ledgerAccountTypeTagName]
+
+	if !exists {
+		return
+	}
+
+	if accountType, exists := ledgerAccountTypeTagValueMap[strings.ToLower(accountTypeTagValue)]; exists {
+		account.AccountType = accountType
+	}
+}
+
+// readTagsFromComment returns the tag names and tag values in the comment, which supports ":tag1:tag2:" format of ledger and "tag1:, tag2:value" format of hledger
+func (r *ledgerDataReader) readTagsFromComment(comment string) ([]string, map[string]string) {
+	tags := make([]string, 0)
+	tagValues := make(map[string]string)
+	remainBuilder := strings.Builder{}
+	items := strings.Fields(comment)
+
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+
+		if len(item) > 2 && item[0] == ledgerTagSeparator && item[len(item)-1] == ledgerTagSeparator { // :tag1:tag2:
+			tagNames := strings.Split(item[1:len(item)-1], string(ledgerTagSeparator))
+
+			for j := 0; j < len(tagNames); j++ {
+				if tagNames[j] != "" {
+					tags = r.appendTags(tags, []string{tagNames[j]})
+				}
+			}
+
+			continue
+		}
+
+		if remainBuilder.Len() > 0 {
+			remainBuilder.WriteRune(' ')
+		}
+
+		remainBuilder.WriteString(item)
+	}
+
+	tagItems := strings.Split(remainBuilder.String(), ledgerTagsSeparator)
+
+	for i := 0; i < len(tagItems); i++ {
+		tagItem := tagItems[i]
+		tagSeparatorIndex := strings.IndexByte(tagItem, ledgerTagSeparator)
+
+		if tagSeparatorIndex <= 0 {
+			continue
+		}
+
+		// the tag name is the last word before the tag separator
+		tagName := tagItem[:tagSeparatorIndex]
+
+		if tagNameStartIndex := strings.LastIndexAny(tagName, " \t"); tagNameStartIndex >= 0 {
+			tagName = tagName[tagNameStartIndex+1:]
+		}
+
+		if tagName == "" {
+			continue
+		}
+
+		tags = r.appendTags(tags, []string{tagName})
+		tagValues[tagName] = strings.TrimSpace(tagItem[tagSeparatorIndex+1:])
+	}
+
+	return tags, tagValues
+}
+
+func (r *ledgerDataReader) appendTags(tags []string, newTags []string) []string {
+	for i := 0; i < len(newTags); i++ {
+		exists := false
+
+		for j := 0; j < len(tags); j++ {
+			if tags[j] == newTags[i] {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			tags = append(tags, newTags[i])
+		}
+	}
+
+	return tags
+}
+
+// parseAmountExpressionAndCommodity returns the amount expression and the commodity of the textual amount, such as "$1,000.00", "-10 USD", "USD 10", "10 \"ABC 1\"" or "($10 * 2)"
+func (r *ledgerDataReader) parseAmountExpressionAndCommodity(textualAmount string) (string, string, error) {
+	expressionBuilder := strings.Builder{}
+	commodity := ""
+	runes := []rune(strings.TrimSpace(textualAmount))
+
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		currentCommodity := ""
+
+		if r.isAmountExpressionChar(ch) {
+			expressionBuilder.WriteRune(ch)
+			continue
+		} else if ch == ',' { // thousands separator
+			continue
+		} else if ch == ledgerCommodityQuote {
+			commodityEndIndex := i + 1
+
+			for commodityEndIndex < len(runes) && runes[commodityEndIndex] != ledgerCommodityQuote {
+				commodityEndIndex++
+			}
+
+			if commodityEndIndex >= len(runes) {
+				return "", "", errs.ErrAmountInvalid
+			}
+
+			currentCommodity = string(runes[i+1 : commodityEndIndex])
+			i = commodityEndIndex
+		} else {
+			commodityEndIndex := i
+
+			for commodityEndIndex < len(runes) && !r.isAmountExpressionChar(runes[commodityEndIndex]) && runes[commodityEndIndex] != ',' && runes[commodityEndIndex] != ledgerCommodityQuote {
+				commodityEndIndex++
+			}
+
+			currentCommodity = string(runes[i:commodityEndIndex])
+			i = commodityEndIndex - 1
+		}
+
+		if commodity != "" && commodity != currentCommodity {
+			return "", "", errs.ErrAmountInvalid
+		}
+
+		commodity = currentCommodity
+	}
+
+	expression := strings.TrimSpace(expressionBuilder.String())
+
+	if expression == "" {
+		return "", "", errs.ErrAmountInvalid
+	}
+
+	return expression, commodity, nil
+}
+
+func (r *ledgerDataReader) isAmountExpressionChar(ch rune) bool {
+	return ('0' <= ch && ch <= '9') || ch == '.' || ch == '(' || ch == ')' || ch == '*' || ch == '/' || ch == '-' || ch == '+' || ch == ' ' || ch == '\t'
+}
+
+// removeLotPrice returns the textual amount without lot price, such as "10 AAPL {$150}"
+func (r *ledgerDataReader) removeLotPrice(textualAmount string) string {
+	for {
+		lotPriceStartIndex := strings.IndexByte(textualAmount, '{')
+
+		if lotPriceStartIndex < 0 {
+			return textualAmount
+		}
+
+		lotPriceEndIndex := strings.LastIndexByte(textualAmount, '}')
+
+		if lotPriceEndIndex < lotPriceStartIndex {
+			return textualAmount
+		}
+
+		textualAmount = textualAmount[:lotPriceStartIndex] + textualAmount[lotPriceEndIndex+1:]
+	}
+}
+
+// parseDate returns the date in "YYYY-MM-DD" format, the year of date can be omitted when year directive exists
+func (r *ledgerDataReader) parseDate(textualDate string, defaultYear string) (string, error) {
+	if auxiliaryDateIndex := strings.IndexByte(textualDate, '='); auxiliaryDateIndex >= 0 {
+		textualDate = textualDate[:auxiliaryDateIndex]
+	}
+
+	textualDate = strings.ReplaceAll(textualDate, "/", "-")
+	textualDate = strings.ReplaceAll(textualDate, ".", "-")
+	dateItems := strings.Split(textualDate, "-")
+
+	if len(dateItems) == 2 && defaultYear != "" {
+		dateItems = append([]string{defaultYear}, dateItems...)
+	}
+
+	if len(dateItems) != 3 || len(dateItems[0]) != 4 || len(dateItems[1]) < 1 || len(dateItems[1]) > 2 || len(dateItems[2]) < 1 || len(dateItems[2]) > 2 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	if len(dateItems[1]) == 1 {
+		dateItems[1] = "0" + dateItems[1]
+	}
+
+	if len(dateItems[2]) == 1 {
+		dateItems[2] = "0" + dateItems[2]
+	}
+
+	date := strings.Join(dateItems, "-")
+
+	if _, err := utils.ParseFromLongDateFirstTime(date, 0); err != nil {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	return date, nil
+}
+
+// splitComment returns the content and the comment of the line
+func (r *ledgerDataReader) splitComment(line string) (string, string) {
+	commentIndex := strings.IndexByte(line, ledgerCommentPrefix)
+
+	if commentIndex < 0 {
+		return strings.TrimSpace(line), ""
+	}
+
+	return strings.TrimSpace(line[:commentIndex]), strings.TrimSpace(line[commentIndex+1:])
+}
+
+// splitFirstItem returns the first item separated by whitespace and the remain content
+func (r *ledgerDataReader) splitFirstItem(content string) (string, string) {
+	content = strings.TrimSpace(content)
+	separatorIndex := strings.IndexAny(content, " \t")
+
+	if separatorIndex < 0 {
+		return content, ""
+	}
+
+	return content[:separatorIndex], strings.TrimSpace(content[separatorIndex+1:])
+}
+
+// splitAccountNameAndAmount returns the account name and the remain content, the account name can contain single space and it ends with two or more spaces or a tab
+func (r *ledgerDataReader) splitAccountNameAndAmount(content string) (string, string) {
+	content = strings.TrimSpace(content)
+	separatorIndex := -1
+
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\t' || (content[i] == ' ' && i+1 < len(content) && (content[i+1] == ' ' || content[i+1] == '\t')) {
+			separatorIndex = i
+			break
+		}
+	}
+
+	if separatorIndex < 0 {
+		return content, ""
+	}
+
+	return content[:separatorIndex], strings.TrimSpace(content[separatorIndex+1:])
+}
+
+func createNewLedgerDataReader(ctx core.Context, data []byte) (*ledgerDataReader, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+	content, err := io.ReadAll(reader)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.createNewLedgerDataReader] cannot read data, because %s", err.Error())
+		return nil, errs.ErrInvalidLedgerFile
+	}
+
+	allLines := strings.Split(string(content), "\n")
+
+	if len(allLines) > 0 && allLines[len(allLines)-1] == "" {
+		allLines = allLines[:len(allLines)-1]
+	}
+
+	return &ledgerDataReader{
+		allLines: allLines,
+	}, nil
+}
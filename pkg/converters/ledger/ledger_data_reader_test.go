@@ -0,0 +1,238 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestLedgerDataReaderRead(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"; Test Ledger Data\n"+
+		"account Assets:Test Account\n"+
+		"account Savings:Test Account2  ; type: A\n"+
+		"account Credit Card\n"+
+		"    ; type: Liability\n"+
+		"    note test note\n"+
+		"\n"+
+		"P 2024/01/01 AAPL $150.00\n"+
+		"P 2024/01/03 12:00:00 \"ABC 1\" 2.5 USD\n"+
+		"\n"+
+		"2024/01/05 * (123) Payee Name | Foo Bar  ; :tag1:tag2:\n"+
+		"    ; tag3:, tag4: value\n"+
+		"    Income:Test Category    $-123.45\n"+
+		"    Assets:Test Account\n"+
+		"2024-1-6 ! Test  ; comment without tags\n"+
+		"    Credit Card    -1,000.12 USD  ; :tag5:\n"+
+		"    Expenses:Test Category2\t(500.06 USD * 2)\n"+
+		"    (Budget:Food)  -1000 USD\n"+
+		"2024.01.07\n"+
+		"    Savings:Test Account2   10 AAPL @ $150.00 = 10 AAPL\n"+
+		"    Assets:Test Account  -1500.00 USD\n"+
+		"2024/01/08\n"+
+		"    [Savings:Test Account2]   -2 \"ABC 1\" @@ 5 USD\n"+
+		"    Assets:Test Account\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(actualData.Accounts))
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Assets:Test Account"].AccountType)
+	assert.True(t, actualData.Accounts["Assets:Test Account"].Declared)
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Savings:Test Account2"].AccountType)
+	assert.Equal(t, ledgerLiabilitiesAccountType, actualData.Accounts["Credit Card"].AccountType)
+	assert.Equal(t, ledgerIncomeAccountType, actualData.Accounts["Income:Test Category"].AccountType)
+	assert.False(t, actualData.Accounts["Income:Test Category"].Declared)
+	assert.Equal(t, ledgerExpensesAccountType, actualData.Accounts["Expenses:Test Category2"].AccountType)
+	assert.Equal(t, ledgerUnknownAccountType, actualData.Accounts["Budget:Food"].AccountType)
+
+	assert.Equal(t, 2, len(actualData.CommodityPrices))
+	assert.Equal(t, 1, len(actualData.CommodityPrices["AAPL"]))
+	assert.Equal(t, "2024-01-01", actualData.CommodityPrices["AAPL"][0].Date)
+	assert.Equal(t, "150.00", actualData.CommodityPrices["AAPL"][0].Price)
+	assert.Equal(t, "$", actualData.CommodityPrices["AAPL"][0].PriceCommodity)
+	assert.Equal(t, 1, len(actualData.CommodityPrices["ABC 1"]))
+	assert.Equal(t, "2024-01-03", actualData.CommodityPrices["ABC 1"][0].Date)
+	assert.Equal(t, "2.5", actualData.CommodityPrices["ABC 1"][0].Price)
+	assert.Equal(t, "USD", actualData.CommodityPrices["ABC 1"][0].PriceCommodity)
+
+	assert.Equal(t, 4, len(actualData.Transactions))
+
+	assert.Equal(t, "2024-01-05", actualData.Transactions[0].Date)
+	assert.Equal(t, ledgerTransactionStateCleared, actualData.Transactions[0].State)
+	assert.Equal(t, "123", actualData.Transactions[0].Code)
+	assert.Equal(t, "Payee Name | Foo Bar", actualData.Transactions[0].Description)
+	assert.Equal(t, []string{"tag1", "tag2", "tag3", "tag4"}, actualData.Transactions[0].Tags)
+	assert.Equal(t, 2, len(actualData.Transactions[0].Postings))
+	assert.Equal(t, "Income:Test Category", actualData.Transactions[0].Postings[0].Account)
+	assert.Equal(t, "-123.45", actualData.Transactions[0].Postings[0].Amount)
+	assert.Equal(t, "$-123.45", actualData.Transactions[0].Postings[0].OriginalAmount)
+	assert.Equal(t, "$", actualData.Transactions[0].Postings[0].Commodity)
+	assert.Equal(t, "Assets:Test Account", actualData.Transactions[0].Postings[1].Account)
+	assert.Equal(t, "123.45", actualData.Transactions[0].Postings[1].Amount)
+	assert.Equal(t, "", actualData.Transactions[0].Postings[1].OriginalAmount)
+	assert.Equal(t, "$", actualData.Transactions[0].Postings[1].Commodity)
+
+	assert.Equal(t, "2024-01-06", actualData.Transactions[1].Date)
+	assert.Equal(t, ledgerTransactionStatePending, actualData.Transactions[1].State)
+	assert.Equal(t, "Test", actualData.Transactions[1].Description)
+	assert.Equal(t, 0, len(actualData.Transactions[1].Tags))
+	assert.Equal(t, 3, len(actualData.Transactions[1].Postings))
+	assert.Equal(t, "Credit Card", actualData.Transactions[1].Postings[0].Account)
+	assert.Equal(t, "-1000.12", actualData.Transactions[1].Postings[0].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[1].Postings[0].Commodity)
+	assert.Equal(t, []string{"tag5"}, actualData.Transactions[1].Postings[0].Tags)
+	assert.Equal(t, "Expenses:Test Category2", actualData.Transactions[1].Postings[1].Account)
+	assert.Equal(t, "1000.12", actualData.Transactions[1].Postings[1].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[1].Postings[1].Commodity)
+	assert.Equal(t, "Budget:Food", actualData.Transactions[1].Postings[2].Account)
+	assert.True(t, actualData.Transactions[1].Postings[2].Virtual)
+
+	assert.Equal(t, "2024-01-07", actualData.Transactions[2].Date)
+	assert.Equal(t, ledgerTransactionStateUncleared, actualData.Transactions[2].State)
+	assert.Equal(t, "10.00", actualData.Transactions[2].Postings[0].Amount)
+	assert.Equal(t, "AAPL", actualData.Transactions[2].Postings[0].Commodity)
+	assert.Equal(t, "1500.00", actualData.Transactions[2].Postings[0].TotalCost)
+	assert.Equal(t, "$", actualData.Transactions[2].Postings[0].TotalCostCommodity)
+
+	assert.Equal(t, "2024-01-08", actualData.Transactions[3].Date)
+	assert.Equal(t, "Savings:Test Account2", actualData.Transactions[3].Postings[0].Account)
+	assert.False(t, actualData.Transactions[3].Postings[0].Virtual)
+	assert.Equal(t, "-2.00", actualData.Transactions[3].Postings[0].Amount)
+	assert.Equal(t, "ABC 1", actualData.Transactions[3].Postings[0].Commodity)
+	assert.Equal(t, "-5.00", actualData.Transactions[3].Postings[0].TotalCost)
+	assert.Equal(t, "USD", actualData.Transactions[3].Postings[0].TotalCostCommodity)
+	assert.Equal(t, "5.00", actualData.Transactions[3].Postings[1].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[3].Postings[1].Commodity)
+}
+
+func TestLedgerDataReaderRead_SkipCommentsAndOtherDirectives(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"# comment\n"+
+		"% comment\n"+
+		"| comment\n"+
+		"* comment\n"+
+		"comment\n"+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test  $1\n"+
+		"    Assets:Test\n"+
+		"end comment\n"+
+		"commodity $\n"+
+		"    format $1,000.00\n"+
+		"= expr true\n"+
+		"    (Budget)  1\n"+
+		"~ Monthly\n"+
+		"    Expenses:Rent  $500\n"+
+		"    Assets:Test\n"+
+		"Y 2024\n"+
+		"01/02 Test2\n"+
+		"    Expenses:Test  $2\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(actualData.Accounts))
+	assert.Equal(t, 1, len(actualData.Transactions))
+	assert.Equal(t, "2024-01-02", actualData.Transactions[0].Date)
+	assert.Equal(t, "Test2", actualData.Transactions[0].Description)
+	assert.Equal(t, "2.00", actualData.Transactions[0].Postings[0].Amount)
+	assert.Equal(t, "-2.00", actualData.Transactions[0].Postings[1].Amount)
+}
+
+func TestLedgerDataReaderRead_NotSupportInclude(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"include other.ledger\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrLedgerFileNotSupportInclude.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"!include other.journal\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrLedgerFileNotSupportInclude.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidElidedAmount(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test  $1\n"+
+		"    Expenses:Test2  1 EUR\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidAmount(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test  $1 EUR\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test  (1 ++ 2) USD\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024/01/01 Test\n"+
+		"    Expenses:Test  = $100\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidDate(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024/13/01 Test\n"+
+		"    Expenses:Test  $1\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"01/02 Test\n"+
+		"    Expenses:Test  $1\n"+
+		"    Assets:Test\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
@@ -0,0 +1,404 @@
+package ledger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerExportedAssetsAccountTypeName = "Assets"
+const ledgerExportedLiabilitiesAccountTypeName = "Liabilities"
+const ledgerExportedIncomeAccountTypeName = "Income"
+const ledgerExportedExpensesAccountTypeName = "Expenses"
+const ledgerExportedEquityOpeningBalanceAccountName = "Equity:Opening Balances"
+const ledgerExportedUncategorizedName = "Uncategorized"
+
+// ledgerExportedAccountTypeTagValues is the value of hledger "type" tag of each account type in exported account directives
+var ledgerExportedAccountTypeTagValues = map[ledgerAccountType]string{
+	ledgerAssetsAccountType:      "A",
+	ledgerLiabilitiesAccountType: "L",
+	ledgerEquityAccountType:      "E",
+	ledgerIncomeAccountType:      "R",
+	ledgerExpensesAccountType:    "X",
+}
+
+// ledgerTransactionDataExporter defines the structure of ledger / hledger journal exporter for transaction data
+type ledgerTransactionDataExporter struct {
+}
+
+// ledgerExportedEntry defines the structure of the exported transaction entry
+type ledgerExportedEntry struct {
+	transactionTime int64
+	date            string
+	state           ledgerTransactionState
+	description     string
+	tags            []string
+	postings        []*ledgerExportedPosting
+}
+
+// ledgerExportedPosting defines the structure of the exported transaction posting, the amount is elided when commodity is empty
+type ledgerExportedPosting struct {
+	account            string
+	amount             int64
+	commodity          string
+	totalCost          int64
+	totalCostCommodity string
+}
+
+// ledgerExportedAccountNames defines the structure of the ledger account names of all exported accounts and categories
+type ledgerExportedAccountNames struct {
+	accountMap    map[int64]*models.Account
+	categoryMap   map[int64]*models.TransactionCategory
+	accountNames  map[int64]string
+	categoryNames map[int64]string
+	allUsedNames  map[string]bool
+	accountTypes  map[string]ledgerAccountType
+}
+
+// Initialize a ledger / hledger journal transaction data exporter singleton instance
+var (
+	LedgerTransactionDataExporter = &ledgerTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported ledger / hledger journal data, which contains the account directives of all used accounts and the transaction entries in time order
+func (c *ledgerTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transaction.TransactionId] = true
+		}
+	}
+
+	accountNames := &ledgerExportedAccountNames{
+		accountMap:    accountMap,
+		categoryMap:   categoryMap,
+		accountNames:  make(map[int64]string),
+		categoryNames: make(map[int64]string),
+		allUsedNames:  make(map[string]bool),
+		accountTypes:  make(map[string]ledgerAccountType),
+	}
+
+	entries := make([]*ledgerExportedEntry, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		date := utils.FormatUnixTimeToLongDate(transactionUnixTime, transactionTimeZone)
+		tags := c.getExportedTags(transaction.TransactionId, allTagIndexes, tagMap)
+		transactionSplits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			entry := c.createExportedEntry(transaction, transaction.CategoryId, transaction.Amount, transaction.Comment, accountNames)
+
+			if entry != nil {
+				entry.date = date
+				entry.tags = tags
+				entries = append(entries, entry)
+			}
+
+			continue
+		}
+
+		// each split of the transaction is exported as a separate entry with its own category and amount
+		for j := 0; j < len(transactionSplits); j++ {
+			split := transactionSplits[j]
+			comment := transaction.Comment
+
+			if split.Comment != "" {
+				comment = split.Comment
+			}
+
+			entry := c.createExportedEntry(transaction, split.CategoryId, split.Amount, comment, accountNames)
+
+			if entry != nil {
+				entry.date = date
+				entry.tags = tags
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].transactionTime < entries[j].transactionTime
+	})
+
+	var ret strings.Builder
+
+	allAccountNames := make([]string, 0, len(accountNames.accountTypes))
+
+	for accountName := range accountNames.accountTypes {
+		allAccountNames = append(allAccountNames, accountName)
+	}
+
+	sort.Strings(allAccountNames)
+
+	for i := 0; i < len(allAccountNames); i++ {
+		accountName := allAccountNames[i]
+		ret.WriteString(fmt.Sprintf("%s %s  %c %s%c %s\n", ledgerDirectiveAccount, accountName, ledgerCommentPrefix, ledgerAccountTypeTagName, ledgerTagSeparator, ledgerExportedAccountTypeTagValues[accountNames.accountTypes[accountName]]))
+	}
+
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+
+		ret.WriteString("\n")
+		ret.WriteString(entry.date)
+
+		if entry.state != ledgerTransactionStateUncleared {
+			ret.WriteString(" " + string(entry.state))
+		}
+
+		if entry.description != "" {
+			ret.WriteString(" " + entry.description)
+		}
+
+		if len(entry.tags) > 0 {
+			ret.WriteString(fmt.Sprintf("  %c %c%s%c", ledgerCommentPrefix, ledgerTagSeparator, strings.Join(entry.tags, string(ledgerTagSeparator)), ledgerTagSeparator))
+		}
+
+		ret.WriteString("\n")
+
+		for j := 0; j < len(entry.postings); j++ {
+			posting := entry.postings[j]
+			ret.WriteString("    " + posting.account)
+
+			if posting.commodity != "" {
+				ret.WriteString(fmt.Sprintf("  %s %s", utils.FormatAmount(posting.amount), posting.commodity))
+			}
+
+			if posting.totalCostCommodity != "" {
+				ret.WriteString(fmt.Sprintf(" %s %s %s", ledgerTotalCostPrefix, utils.FormatAmount(posting.totalCost), posting.totalCostCommodity))
+			}
+
+			ret.WriteString("\n")
+		}
+	}
+
+	return []byte(ret.String()), nil
+}
+
+func (c *ledgerTransactionDataExporter) createExportedEntry(transaction *models.Transaction, categoryId int64, amount int64, comment string, accountNames *ledgerExportedAccountNames) *ledgerExportedEntry {
+	entry := &ledgerExportedEntry{
+		transactionTime: transaction.TransactionTime,
+		state:           c.getExportedState(transaction.ReconciliationStatus),
+		description:     c.getExportedDescription(comment),
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		accountNames.accountTypes[ledgerExportedEquityOpeningBalanceAccountName] = ledgerEquityAccountType
+
+		entry.postings = []*ledgerExportedPosting{
+			{account: account, amount: amount, commodity: currency},
+			{account: ledgerExportedEquityOpeningBalanceAccountName},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		entry.postings = []*ledgerExportedPosting{
+			{account: account, amount: amount, commodity: currency},
+			{account: accountNames.getCategoryName(categoryId, ledgerExportedIncomeAccountTypeName, ledgerIncomeAccountType)},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		account, currency := accountNames.getAccountNameAndCurrency(transaction.AccountId)
+
+		if account == "" {
+			return nil
+		}
+
+		entry.postings = []*ledgerExportedPosting{
+			{account: accountNames.getCategoryName(categoryId, ledgerExportedExpensesAccountTypeName, ledgerExpensesAccountType), amount: amount, commodity: currency},
+			{account: account},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		fromAccountId := transaction.AccountId
+		fromAmount := transaction.Amount
+		toAccountId := transaction.RelatedAccountId
+		toAmount := transaction.RelatedAccountAmount
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			fromAccountId = transaction.RelatedAccountId
+			fromAmount = transaction.RelatedAccountAmount
+			toAccountId = transaction.AccountId
+			toAmount = transaction.Amount
+		}
+
+		fromAccount, fromCurrency := accountNames.getAccountNameAndCurrency(fromAccountId)
+		toAccount, toCurrency := accountNames.getAccountNameAndCurrency(toAccountId)
+
+		if fromAccount == "" || toAccount == "" {
+			return nil
+		}
+
+		fromPosting := &ledgerExportedPosting{account: fromAccount}
+
+		// the amount of source account can be elided only when the currencies are the same, otherwise the total cost is required
+		if fromCurrency != toCurrency {
+			fromPosting.amount = -fromAmount
+			fromPosting.commodity = fromCurrency
+			fromPosting.totalCost = toAmount
+			fromPosting.totalCostCommodity = toCurrency
+		}
+
+		entry.postings = []*ledgerExportedPosting{
+			{account: toAccount, amount: toAmount, commodity: toCurrency},
+			fromPosting,
+		}
+	} else {
+		return nil
+	}
+
+	return entry
+}
+
+// getExportedState returns the ledger transaction state, hledger regards pending as cleared but not reconciled
+func (c *ledgerTransactionDataExporter) getExportedState(reconciliationStatus models.TransactionReconciliationStatus) ledgerTransactionState {
+	if reconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return ledgerTransactionStateCleared
+	} else if reconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+		return ledgerTransactionStatePending
+	}
+
+	return ledgerTransactionStateUncleared
+}
+
+func (c *ledgerTransactionDataExporter) getExportedDescription(comment string) string {
+	comment = strings.ReplaceAll(comment, "\r\n", " ")
+	comment = strings.ReplaceAll(comment, "\r", " ")
+	comment = strings.ReplaceAll(comment, "\n", " ")
+	comment = strings.ReplaceAll(comment, string(ledgerCommentPrefix), ",")
+
+	return strings.TrimSpace(comment)
+}
+
+func (c *ledgerTransactionDataExporter) getExportedTags(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIndexes, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tags := make([]string, 0, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		// the tag name cannot contain spaces, colons and commas
+		tagName := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) || r == ledgerTagSeparator || r == ',' {
+				return '-'
+			}
+
+			return r
+		}, tag.Name)
+
+		tags = append(tags, tagName)
+	}
+
+	return tags
+}
+
+func (n *ledgerExportedAccountNames) getAccountNameAndCurrency(accountId int64) (string, string) {
+	account, exists := n.accountMap[accountId]
+
+	if !exists {
+		return "", ""
+	}
+
+	if name, exists := n.accountNames[accountId]; exists {
+		return name, account.Currency
+	}
+
+	accountType := ledgerAssetsAccountType
+	accountTypeName := ledgerExportedAssetsAccountTypeName
+
+	if account.Category.IsLiability() {
+		accountType = ledgerLiabilitiesAccountType
+		accountTypeName = ledgerExportedLiabilitiesAccountTypeName
+	}
+
+	name := accountTypeName + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(account.Name)
+
+	if parentAccount, exists := n.accountMap[account.ParentAccountId]; exists && account.ParentAccountId > 0 {
+		name = accountTypeName + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(parentAccount.Name) + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(account.Name)
+	}
+
+	name = n.getUniqueName(name, accountId)
+	n.accountNames[accountId] = name
+	n.accountTypes[name] = accountType
+
+	return name, account.Currency
+}
+
+func (n *ledgerExportedAccountNames) getCategoryName(categoryId int64, accountTypeName string, accountType ledgerAccountType) string {
+	if name, exists := n.categoryNames[categoryId]; exists {
+		return name
+	}
+
+	name := accountTypeName + ledgerAccountNameItemsSeparator + ledgerExportedUncategorizedName
+
+	if category, exists := n.categoryMap[categoryId]; exists {
+		name = accountTypeName + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(category.Name)
+
+		if parentCategory, exists := n.categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			name = accountTypeName + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(parentCategory.Name) + ledgerAccountNameItemsSeparator + n.getAccountNameComponent(category.Name)
+		}
+
+		name = n.getUniqueName(name, categoryId)
+		n.categoryNames[categoryId] = name
+	}
+
+	n.accountTypes[name] = accountType
+
+	return name
+}
+
+// getAccountNameComponent returns the valid account name component, which does not contain colons, semicolons or consecutive whitespaces
+func (n *ledgerExportedAccountNames) getAccountNameComponent(name string) string {
+	name = strings.ReplaceAll(name, ledgerAccountNameItemsSeparator, "-")
+	name = strings.ReplaceAll(name, string(ledgerCommentPrefix), "-")
+	name = strings.Join(strings.Fields(name), " ")
+
+	if name == "" {
+		return ledgerExportedUncategorizedName
+	}
+
+	return name
+}
+
+func (n *ledgerExportedAccountNames) getUniqueName(name string, id int64) string {
+	if n.allUsedNames[name] {
+		name = name + "-" + utils.Int64ToString(id)
+	}
+
+	n.allUsedNames[name] = true
+
+	return name
+}
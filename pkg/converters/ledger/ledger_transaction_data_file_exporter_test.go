@@ -0,0 +1,292 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 3)
+	transactions[0] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725408000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    0,
+		AccountId:            1,
+		Amount:               12345,
+		RelatedId:            4,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+		Comment:              "Transfer; Hello",
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:        2,
+		TransactionTime:      1725194096000,
+		Type:                 models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset:    0,
+		CategoryId:           2,
+		AccountId:            2,
+		Amount:               10,
+		Comment:              "Dinner\nwith friends",
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725100000000,
+		Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset: 480,
+		AccountId:         1,
+		Amount:            100000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "My  Bank",
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Credit: Card",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 2)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Food & Drink",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Dinner",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+	tagMap[2] = &models.TransactionTag{
+		TagId: 2,
+		Name:  "Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[2] = []int64{1, 2}
+
+	expectedContent := "account Assets:My Bank  ; type: A\n" +
+		"account Equity:Opening Balances  ; type: E\n" +
+		"account Expenses:Food & Drink:Dinner  ; type: X\n" +
+		"account Liabilities:Credit- Card  ; type: L\n" +
+		"\n" +
+		"2024-08-31\n" +
+		"    Assets:My Bank  1000.00 CNY\n" +
+		"    Equity:Opening Balances\n" +
+		"\n" +
+		"2024-09-01 ! Dinner with friends  ; :Test-Tag:Tag2:\n" +
+		"    Expenses:Food & Drink:Dinner  0.10 USD\n" +
+		"    Liabilities:Credit- Card\n" +
+		"\n" +
+		"2024-09-04 * Transfer, Hello\n" +
+		"    Liabilities:Credit- Card  17.35 USD\n" +
+		"    Assets:My Bank  -123.45 CNY @@ 17.35 USD\n"
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestLedgerTransactionDataFileExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 6)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725148800000,
+		Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset: 0,
+		AccountId:         1,
+		Amount:            100000,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725235200000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Salary",
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     3,
+		TransactionTime:   1725321600000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        4,
+		AccountId:         2,
+		Amount:            1050,
+		Comment:           "Dinner",
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725408000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               7000,
+		RelatedId:            5,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1000,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725408000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            2,
+		Amount:               1000,
+		RelatedId:            4,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 7000,
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:        6,
+		TransactionTime:      1725494400000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               500,
+		RelatedId:            7,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 500,
+	}
+
+	accountMap := make(map[int64]*models.Account, 3)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Bank",
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Credit Card",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+	}
+	accountMap[3] = &models.Account{
+		AccountId: 3,
+		Name:      "Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 4)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Work",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: 1,
+		Name:             "Salary",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId: 3,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Food",
+	}
+	categoryMap[4] = &models.TransactionCategory{
+		CategoryId:       4,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Dinner",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 1)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[3] = []int64{1}
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, allNewTags, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Bank", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Assets:Bank", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Income:Work:Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(1050), allNewTransactions[2].Amount)
+	assert.Equal(t, "Liabilities:Credit Card", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[2].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Expenses:Food:Dinner", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, []string{"Test-Tag"}, allNewTransactions[2].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725408000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(7000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(1000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Bank", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[3].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Liabilities:Credit Card", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[3].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+	assert.Equal(t, int64(1725494400), utils.GetUnixTimeFromTransactionTime(allNewTransactions[4].TransactionTime))
+	assert.Equal(t, int64(500), allNewTransactions[4].Amount)
+	assert.Equal(t, int64(500), allNewTransactions[4].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Cash", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Assets:Bank", allNewTransactions[4].OriginalDestinationAccountName)
+}
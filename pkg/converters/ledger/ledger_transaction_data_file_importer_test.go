@@ -0,0 +1,226 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"2024/09/01\n"+
+			"    Equity:Opening Balances  -123.45 CNY\n"+
+			"    Assets:Test Account  123.45 CNY\n"+
+			"2024/09/02\n"+
+			"    assets:Test Account  0.12 CNY\n"+
+			"    income:Test Category\n"+
+			"2024/09/03\n"+
+			"    Expenses:Test Category2  1.00\n"+
+			"    Assets:Test Account\n"+
+			"2024/09/04\n"+
+			"    Assets:Test Account  -0.05 CNY\n"+
+			"    Liabilities:Test Account2\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "assets:Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "income:Test Category", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Expenses:Test Category2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725408000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(5), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(5), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Liabilities:Test Account2", allNewTransactions[3].OriginalDestinationAccountName)
+
+	assert.Equal(t, "Assets:Test Account", allNewAccounts[0].Name)
+	assert.Equal(t, "CNY", allNewAccounts[0].Currency)
+	assert.Equal(t, "assets:Test Account", allNewAccounts[1].Name)
+	assert.Equal(t, "CNY", allNewAccounts[1].Currency)
+	assert.Equal(t, "Liabilities:Test Account2", allNewAccounts[2].Name)
+	assert.Equal(t, "CNY", allNewAccounts[2].Currency)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseCommodityAndPrice(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"P 2024/09/01 AAPL $150.00\n"+
+			"P 2024/09/03 AAPL $160.00\n"+
+			"\n"+
+			"2024/09/01 Buy stock\n"+
+			"    Assets:Broker  10 AAPL @ $150.00\n"+
+			"    Assets:Checking\n"+
+			"2024/09/02 Sell stock\n"+
+			"    Assets:Broker  -2 AAPL\n"+
+			"    Assets:Checking  $300.00\n"+
+			"2024/09/04 Sell stock\n"+
+			"    Assets:Broker  -1 AAPL\n"+
+			"    Assets:Checking  $160.00\n"+
+			"2024/09/05 Exchange\n"+
+			"    Assets:Checking  -$100.00\n"+
+			"    Assets:Cash  €90.00\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(150000), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(150000), allNewTransactions[0].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Assets:Broker", allNewTransactions[0].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(30000), allNewTransactions[1].Amount)
+	assert.Equal(t, int64(30000), allNewTransactions[1].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Broker", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Assets:Checking", allNewTransactions[1].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(16000), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(16000), allNewTransactions[2].RelatedAccountAmount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(9000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "USD", allNewTransactions[3].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Assets:Cash", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[3].OriginalDestinationAccountCurrency)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseAccountType(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"account Bank  ; type: A\n"+
+			"account Food\n"+
+			"    ; type: X\n"+
+			"\n"+
+			"2024/09/01\n"+
+			"    Food  1.00 CNY\n"+
+			"    Bank\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "Bank", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Food", allNewSubExpenseCategories[0].Name)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseDescriptionAndTags(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"2024/09/01 * Payee Name | Foo Bar  ; :tag1:tag2:\n"+
+			"    ; tag3:\n"+
+			"    Expenses:Test Category  1.00 CNY  ; tag4:value, tag1:\n"+
+			"    Assets:Test Account\n"+
+			"    (Budget:Food)  -1.00 CNY  ; tag5:\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Payee Name | Foo Bar", allNewTransactions[0].Comment)
+
+	assert.Equal(t, 4, len(allNewTags))
+	assert.Equal(t, "tag1", allNewTags[0].Name)
+	assert.Equal(t, "tag2", allNewTags[1].Name)
+	assert.Equal(t, "tag3", allNewTags[2].Name)
+	assert.Equal(t, "tag4", allNewTags[3].Name)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_NotSupportedTransactions(t *testing.T) {
+	importer := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024/09/01\n"+
+			"    Expenses:Test Category  1.00 CNY\n"+
+			"    Expenses:Test Category2  2.00 CNY\n"+
+			"    Assets:Test Account\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"2024/09/01\n"+
+			"    Expenses:Test Category  1.00 CNY\n"+
+			"    Income:Test Category2\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrThereAreNotSupportedTransactionType.Message)
+
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"2024/09/01\n"+
+			"    Expenses:Test Category  1.00 ABC\n"+
+			"    Assets:Test Account\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
+}
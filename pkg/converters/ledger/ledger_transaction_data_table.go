@@ -0,0 +1,365 @@
+package ledger
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+var ledgerTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+}
+
+// ledgerCommoditySymbolCurrencyMap is the currency mapping of the commonly used commodity symbols
+var ledgerCommoditySymbolCurrencyMap = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+	"₹": "INR",
+	"₩": "KRW",
+	"₽": "RUB",
+	"₴": "UAH",
+	"₺": "TRY",
+}
+
+var LEDGER_TRANSACTION_TAG_SEPARATOR = ":"
+
+// ledgerTransactionDataTable defines the structure of ledger transaction data table
+type ledgerTransactionDataTable struct {
+	allData         []*ledgerTransactionEntry
+	accountMap      map[string]*ledgerAccount
+	commodityPrices map[string][]*ledgerCommodityPrice
+}
+
+// ledgerTransactionDataRow defines the structure of ledger transaction data row
+type ledgerTransactionDataRow struct {
+	dataTable  *ledgerTransactionDataTable
+	data       *ledgerTransactionEntry
+	finalItems map[datatable.TransactionDataTableColumn]string
+}
+
+// ledgerTransactionDataRowIterator defines the structure of ledger transaction data row iterator
+type ledgerTransactionDataRowIterator struct {
+	dataTable    *ledgerTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *ledgerTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := ledgerTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &ledgerTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *ledgerTransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *ledgerTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := ledgerTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *ledgerTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *ledgerTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+	}, nil
+}
+
+func (t *ledgerTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, ledgerEntry *ledgerTransactionEntry) (map[datatable.TransactionDataTableColumn]string, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(ledgerTransactionSupportedColumns))
+
+	if ledgerEntry.Date == "" {
+		return nil, errs.ErrMissingTransactionTime
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = ledgerEntry.Date + " 00:00:00"
+
+	postings := make([]*ledgerPosting, 0, len(ledgerEntry.Postings))
+	tags := make([]string, 0, len(ledgerEntry.Tags))
+	allTags := make(map[string]bool, len(ledgerEntry.Tags))
+
+	for i := 0; i < len(ledgerEntry.Tags); i++ {
+		tags = append(tags, ledgerEntry.Tags[i])
+		allTags[ledgerEntry.Tags[i]] = true
+	}
+
+	for i := 0; i < len(ledgerEntry.Postings); i++ {
+		posting := ledgerEntry.Postings[i]
+
+		// unbalanced virtual postings do not affect the actual accounts
+		if posting.Virtual {
+			continue
+		}
+
+		postings = append(postings, posting)
+
+		for j := 0; j < len(posting.Tags); j++ {
+			if _, exists := allTags[posting.Tags[j]]; !exists {
+				tags = append(tags, posting.Tags[j])
+				allTags[posting.Tags[j]] = true
+			}
+		}
+	}
+
+	if len(postings) == 2 {
+		splitData1 := postings[0]
+		splitData2 := postings[1]
+
+		account1 := t.dataTable.accountMap[splitData1.Account]
+		account2 := t.dataTable.accountMap[splitData2.Account]
+
+		if account1 == nil || account2 == nil {
+			return nil, errs.ErrMissingAccountData
+		}
+
+		amount1, currency1, err := t.getPostingAmountAndCurrency(ctx, ledgerEntry, splitData1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		amount2, currency2, err := t.getPostingAmountAndCurrency(ctx, ledgerEntry, splitData2)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ((account1.AccountType == ledgerEquityAccountType || account1.AccountType == ledgerIncomeAccountType) && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType)) ||
+			((account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // income
+			fromAccount := account1
+			toAccount := account2
+			toCurrency := currency2
+			toAmount := amount2
+
+			if (account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				toAccount = account1
+				toCurrency = currency1
+				toAmount = amount1
+			}
+
+			if fromAccount.isOpeningBalanceEquityAccount() {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_MODIFY_BALANCE))
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(toAmount)
+		} else if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) ||
+			(account2.AccountType == ledgerExpensesAccountType && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // expense
+			fromAccount := account1
+			fromCurrency := currency1
+			fromAmount := amount1
+			toAccount := account2
+
+			if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				fromCurrency = currency2
+				fromAmount = amount2
+				toAccount = account1
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-fromAmount)
+		} else if (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) &&
+			(account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+			var fromAccount, toAccount *ledgerAccount
+			var fromAmount, toAmount int64
+			var fromCurrency, toCurrency string
+
+			if amount1 < 0 {
+				fromAccount = account1
+				fromCurrency = currency1
+				fromAmount = -amount1
+				toAccount = account2
+				toCurrency = currency2
+				toAmount = amount2
+			} else if amount2 < 0 {
+				fromAccount = account2
+				fromCurrency = currency2
+				fromAmount = -amount2
+				toAccount = account1
+				toCurrency = currency1
+				toAmount = amount1
+			} else {
+				log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transfer transaction, because unexcepted account amounts \"%d\" and \"%d\"", amount1, amount2)
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(fromAmount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
+		} else {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because unexcepted account types \"%d\" and \"%d\"", account1.AccountType, account2.AccountType)
+			return nil, errs.ErrThereAreNotSupportedTransactionType
+		}
+	} else if len(postings) <= 1 {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because postings count is %d", len(postings))
+		return nil, errs.ErrInvalidLedgerFile
+	} else {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse split transaction, because postings count is %d", len(postings))
+		return nil, errs.ErrNotSupportedSplitTransactions
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(tags, LEDGER_TRANSACTION_TAG_SEPARATOR)
+	data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ledgerEntry.getPayee()
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ledgerEntry.Description
+
+	return data, nil
+}
+
+// getPostingAmountAndCurrency returns the amount and currency of the posting, the commodity which is not a currency is converted by the cost or the latest commodity price
+func (t *ledgerTransactionDataRowIterator) getPostingAmountAndCurrency(ctx core.Context, ledgerEntry *ledgerTransactionEntry, posting *ledgerPosting) (int64, string, error) {
+	textualAmount := posting.Amount
+	currency := posting.Commodity
+
+	if posting.Commodity != "" && t.getCurrency(posting.Commodity) != "" {
+		currency = t.getCurrency(posting.Commodity)
+	} else if posting.Commodity != "" && posting.TotalCost != "" && (posting.TotalCostCommodity == "" || t.getCurrency(posting.TotalCostCommodity) != "") {
+		textualAmount = posting.TotalCost
+		currency = t.getCurrency(posting.TotalCostCommodity)
+	} else if commodityPrice := t.getLatestCommodityPrice(posting.Commodity, ledgerEntry.Date); posting.Commodity != "" && commodityPrice != nil {
+		convertedAmount, err := beancount.EvaluateAmountExpression(ctx, "("+posting.Amount+")*("+commodityPrice.Price+")")
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.getPostingAmountAndCurrency] cannot convert amount \"%s\" by commodity price \"%s\", because %s", posting.Amount, commodityPrice.Price, err.Error())
+			return 0, "", errs.ErrAmountInvalid
+		}
+
+		textualAmount = convertedAmount
+		currency = t.getCurrency(commodityPrice.PriceCommodity)
+	}
+
+	amount, err := utils.ParseAmount(textualAmount)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_transaction_data_table.getPostingAmountAndCurrency] cannot parse amount \"%s\", because %s", textualAmount, err.Error())
+		return 0, "", errs.ErrAmountInvalid
+	}
+
+	return amount, currency, nil
+}
+
+// getLatestCommodityPrice returns the latest price of the commodity in currency before or at the specified date, or the earliest price if not exists
+func (t *ledgerTransactionDataRowIterator) getLatestCommodityPrice(commodity string, date string) *ledgerCommodityPrice {
+	var latestPrice *ledgerCommodityPrice
+	var earliestPrice *ledgerCommodityPrice
+	commodityPrices := t.dataTable.commodityPrices[commodity]
+
+	for i := 0; i < len(commodityPrices); i++ {
+		commodityPrice := commodityPrices[i]
+
+		if commodityPrice.PriceCommodity != "" && t.getCurrency(commodityPrice.PriceCommodity) == "" {
+			continue
+		}
+
+		if commodityPrice.Date <= date && (latestPrice == nil || commodityPrice.Date >= latestPrice.Date) {
+			latestPrice = commodityPrice
+		}
+
+		if earliestPrice == nil || commodityPrice.Date < earliestPrice.Date {
+			earliestPrice = commodityPrice
+		}
+	}
+
+	if latestPrice != nil {
+		return latestPrice
+	}
+
+	return earliestPrice
+}
+
+// getCurrency returns the currency code of the commodity, or empty if the commodity is not a currency
+func (t *ledgerTransactionDataRowIterator) getCurrency(commodity string) string {
+	if _, exists := validators.AllCurrencyNames[commodity]; exists {
+		return commodity
+	}
+
+	if currency, exists := ledgerCommoditySymbolCurrencyMap[commodity]; exists {
+		return currency
+	}
+
+	return ""
+}
+
+func createNewLedgerTransactionDataTable(ledgerData *ledgerData) (*ledgerTransactionDataTable, error) {
+	if ledgerData == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &ledgerTransactionDataTable{
+		allData:         ledgerData.Transactions,
+		accountMap:      ledgerData.Accounts,
+		commodityPrices: ledgerData.CommodityPrices,
+	}, nil
+}
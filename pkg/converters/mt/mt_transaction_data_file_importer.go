@@ -38,7 +38,7 @@ func (c *mt940TransactionDataFileImporter) ParseImportedData(ctx core.Context, u
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(mt940TransactionTypeNameMapping)
+	dataTableImporter := converter.CreateNewBankStatementImporterWithTypeNameMapping(mt940TransactionTypeNameMapping)
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
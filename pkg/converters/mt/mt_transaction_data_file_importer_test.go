@@ -46,6 +46,7 @@ func TestMT940TransactionDataFileParseImportedData_MinimumValidData(t *testing.T
 
 	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
 	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED, allNewTransactions[0].ReconciliationStatus)
 	assert.Equal(t, int64(1748736000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
 	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
 	assert.Equal(t, "12345678", allNewTransactions[0].OriginalSourceAccountName)
@@ -88,12 +88,12 @@ type ofxFileHeader struct {
 
 // ofxBankMessageResponseV1 represents the struct of open financial exchange (ofx) bank message response v1
 type ofxBankMessageResponseV1 struct {
-	StatementTransactionResponse *ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
+	StatementTransactionResponses []*ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
 }
 
 // ofxCreditCardMessageResponseV1 represents the struct of open financial exchange (ofx) credit card message response v1
 type ofxCreditCardMessageResponseV1 struct {
-	StatementTransactionResponse *ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
+	StatementTransactionResponses []*ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
 }
 
 // ofxBankStatementTransactionResponse represents the struct of open financial exchange (ofx) bank statement transaction response
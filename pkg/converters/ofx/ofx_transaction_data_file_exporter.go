@@ -0,0 +1,223 @@
+package ofx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ofxExportedBankId = "EZBOOKKEEPING"
+
+// ofxTransactionDataExporter defines the structure of open financial exchange (ofx) file exporter for transaction data
+type ofxTransactionDataExporter struct {
+}
+
+// ofxExportedStatement defines the structure of the exported statement of one account
+type ofxExportedStatement struct {
+	account      *models.Account
+	transactions []*models.Transaction
+	minTime      int64
+	maxTime      int64
+}
+
+// Initialize a open financial exchange (ofx) transaction data exporter singleton instance
+var (
+	OFXTransactionDataExporter = &ofxTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported open financial exchange (ofx) 2.x file content, the transactions of each account are exported as a bank statement and the balance modification transactions are not exported
+func (c *ofxTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transaction.TransactionId] = true
+		}
+	}
+
+	statements := make([]*ofxExportedStatement, 0)
+	statementMap := make(map[int64]*ofxExportedStatement)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		// the transfer transaction is exported in the statement of the source account
+		accountId := transaction.AccountId
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			accountId = transaction.RelatedAccountId
+		}
+
+		account, exists := accountMap[accountId]
+
+		if !exists {
+			continue
+		}
+
+		statement, exists := statementMap[accountId]
+
+		if !exists {
+			statement = &ofxExportedStatement{
+				account: account,
+				minTime: transaction.TransactionTime,
+				maxTime: transaction.TransactionTime,
+			}
+
+			statements = append(statements, statement)
+			statementMap[accountId] = statement
+		}
+
+		statement.transactions = append(statement.transactions, transaction)
+
+		if transaction.TransactionTime < statement.minTime {
+			statement.minTime = transaction.TransactionTime
+		}
+
+		if transaction.TransactionTime > statement.maxTime {
+			statement.maxTime = transaction.TransactionTime
+		}
+	}
+
+	var ret strings.Builder
+
+	ret.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	ret.WriteString(fmt.Sprintf("<?OFX OFXHEADER=\"%s\" VERSION=\"211\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n", ofxVersion2))
+	ret.WriteString("<OFX>\n")
+	ret.WriteString("<SIGNONMSGSRSV1>\n")
+	ret.WriteString("<SONRS>\n")
+	c.writeStatus(&ret)
+	c.writeElement(&ret, "DTSERVER", c.formatDateTime(time.Now().Unix(), time.UTC))
+	c.writeElement(&ret, "LANGUAGE", "ENG")
+	ret.WriteString("</SONRS>\n")
+	ret.WriteString("</SIGNONMSGSRSV1>\n")
+	ret.WriteString("<BANKMSGSRSV1>\n")
+
+	for i := 0; i < len(statements); i++ {
+		statement := statements[i]
+
+		ret.WriteString("<STMTTRNRS>\n")
+		c.writeElement(&ret, "TRNUID", utils.IntToString(i))
+		c.writeStatus(&ret)
+		ret.WriteString("<STMTRS>\n")
+		c.writeElement(&ret, "CURDEF", statement.account.Currency)
+		c.writeBankAccount(&ret, "BANKACCTFROM", statement.account)
+		ret.WriteString("<BANKTRANLIST>\n")
+		c.writeElement(&ret, "DTSTART", c.formatDateTime(utils.GetUnixTimeFromTransactionTime(statement.minTime), time.UTC))
+		c.writeElement(&ret, "DTEND", c.formatDateTime(utils.GetUnixTimeFromTransactionTime(statement.maxTime), time.UTC))
+
+		for j := 0; j < len(statement.transactions); j++ {
+			c.writeStatementTransaction(&ret, statement.transactions[j], accountMap)
+		}
+
+		ret.WriteString("</BANKTRANLIST>\n")
+		ret.WriteString("</STMTRS>\n")
+		ret.WriteString("</STMTTRNRS>\n")
+	}
+
+	ret.WriteString("</BANKMSGSRSV1>\n")
+	ret.WriteString("</OFX>\n")
+
+	return []byte(ret.String()), nil
+}
+
+func (c *ofxTransactionDataExporter) writeStatementTransaction(ret *strings.Builder, transaction *models.Transaction, accountMap map[int64]*models.Account) {
+	var transactionType ofxTransactionType
+	var amount int64
+	var toAccount *models.Account
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		transactionType = ofxDepositTransaction
+		amount = transaction.Amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		transactionType = ofxGenericDebitTransaction
+		amount = -transaction.Amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transactionType = ofxTransferTransaction
+		amount = -transaction.Amount
+		toAccount = accountMap[transaction.RelatedAccountId]
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transactionType = ofxTransferTransaction
+		amount = -transaction.RelatedAccountAmount
+		toAccount = accountMap[transaction.AccountId]
+	} else {
+		return
+	}
+
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+
+	ret.WriteString("<STMTTRN>\n")
+	c.writeElement(ret, "TRNTYPE", string(transactionType))
+	c.writeElement(ret, "DTPOSTED", c.formatDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transactionTimeZone))
+	c.writeElement(ret, "TRNAMT", utils.FormatAmount(amount))
+	c.writeElement(ret, "FITID", utils.Int64ToString(transaction.TransactionId))
+
+	if toAccount != nil {
+		c.writeBankAccount(ret, "BANKACCTTO", toAccount)
+	}
+
+	if transaction.Comment != "" {
+		c.writeElement(ret, "MEMO", transaction.Comment)
+	}
+
+	ret.WriteString("</STMTTRN>\n")
+}
+
+func (c *ofxTransactionDataExporter) writeBankAccount(ret *strings.Builder, elementName string, account *models.Account) {
+	ret.WriteString("<" + elementName + ">\n")
+	c.writeElement(ret, "BANKID", ofxExportedBankId)
+	c.writeElement(ret, "ACCTID", account.Name)
+	c.writeElement(ret, "ACCTTYPE", string(c.getAccountType(account)))
+	ret.WriteString("</" + elementName + ">\n")
+}
+
+func (c *ofxTransactionDataExporter) writeStatus(ret *strings.Builder) {
+	ret.WriteString("<STATUS>\n")
+	c.writeElement(ret, "CODE", "0")
+	c.writeElement(ret, "SEVERITY", "INFO")
+	ret.WriteString("</STATUS>\n")
+}
+
+func (c *ofxTransactionDataExporter) writeElement(ret *strings.Builder, elementName string, value string) {
+	ret.WriteString("<" + elementName + ">")
+	_ = xml.EscapeText(ret, []byte(value))
+	ret.WriteString("</" + elementName + ">\n")
+}
+
+func (c *ofxTransactionDataExporter) getAccountType(account *models.Account) ofxAccountType {
+	if account.Category == models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT {
+		return ofxSavingsAccount
+	} else if account.Category == models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT {
+		return ofxCertificateOfDepositAccount
+	}
+
+	// the line of credit account would be imported as credit card account, so other accounts are all exported as checking account
+	return ofxCheckingAccount
+}
+
+// formatDateTime returns the datetime in format YYYYMMDDHHMMSS.XXX[gmt offset]
+func (c *ofxTransactionDataExporter) formatDateTime(unixTime int64, timezone *time.Location) string {
+	dateTime := time.Unix(unixTime, 0).In(timezone)
+	_, tzOffset := dateTime.Zone()
+	hoursOffset := utils.Float64ToString(float64(tzOffset) / 3600)
+
+	if tzOffset >= 0 {
+		hoursOffset = "+" + hoursOffset
+	}
+
+	return fmt.Sprintf("%s.000[%s]", dateTime.Format("20060102150405"), hoursOffset)
+}
@@ -0,0 +1,149 @@
+package ofx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestOFXTransactionDataFileExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	importer := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 6)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725100000000,
+		Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset: 480,
+		AccountId:         1,
+		Amount:            100000,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Salary <September> & Bonus",
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     3,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: -330,
+		CategoryId:        4,
+		AccountId:         2,
+		Amount:            1050,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               2000,
+		RelatedId:            5,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 2000,
+		Comment:              "Transfer",
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            3,
+		Amount:               2000,
+		RelatedId:            4,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 2000,
+		Comment:              "Transfer",
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:        6,
+		TransactionTime:      1725300000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               300,
+		RelatedId:            7,
+		RelatedAccountId:     3,
+		RelatedAccountAmount: 300,
+	}
+
+	accountMap := make(map[int64]*models.Account, 3)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Test Account2",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+	}
+	accountMap[3] = &models.Account{
+		AccountId: 3,
+		Name:      "Test Account3",
+		Category:  models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT,
+		Currency:  "CNY",
+	}
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725165296), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[0].TimezoneUtcOffset)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Salary <September> & Bonus", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725194096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int16(-330), allNewTransactions[1].TimezoneUtcOffset)
+	assert.Equal(t, int64(1050), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account2", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[1].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725212096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(2000), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(2000), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Test Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Test Account3", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "Transfer", allNewTransactions[2].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725300000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(300), allNewTransactions[3].Amount)
+	assert.Equal(t, "Test Account3", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Test Account", allNewTransactions[3].OriginalDestinationAccountName)
+}
@@ -44,7 +44,7 @@ func (c *ofxTransactionDataImporter) ParseImportedData(ctx core.Context, user *m
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(ofxTransactionTypeNameMapping)
+	dataTableImporter := converter.CreateNewBankStatementImporterWithTypeNameMapping(ofxTransactionTypeNameMapping)
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
@@ -0,0 +1,220 @@
+package qif
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const qifBankAccountType = "Bank"
+const qifCashAccountType = "Cash"
+const qifCreditCardAccountType = "CCard"
+const qifAssetAccountType = "Oth A"
+const qifLiabilityAccountType = "Oth L"
+
+// qifTransactionDataExporter defines the structure of quicken interchange format (qif) exporter for transaction data
+type qifTransactionDataExporter struct {
+}
+
+// qifExportedAccount defines the structure of the exported transactions of one account
+type qifExportedAccount struct {
+	account      *models.Account
+	transactions []*models.Transaction
+}
+
+// Initialize a quicken interchange format (qif) transaction data exporter singleton instance
+var (
+	QifTransactionDataExporter = &qifTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported quicken interchange format (qif) data, the transactions of each account are exported after the account entry and the dates are in year-month-day format
+func (c *qifTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allTransactionSplits map[int64][]*models.TransactionSplit) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transaction.TransactionId] = true
+		}
+	}
+
+	exportedAccounts := make([]*qifExportedAccount, 0)
+	exportedAccountMap := make(map[int64]*qifExportedAccount)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		// the transfer transaction is exported in the source account
+		accountId := transaction.AccountId
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			accountId = transaction.RelatedAccountId
+		}
+
+		account, exists := accountMap[accountId]
+
+		if !exists {
+			continue
+		}
+
+		exportedAccount, exists := exportedAccountMap[accountId]
+
+		if !exists {
+			exportedAccount = &qifExportedAccount{
+				account: account,
+			}
+
+			exportedAccounts = append(exportedAccounts, exportedAccount)
+			exportedAccountMap[accountId] = exportedAccount
+		}
+
+		exportedAccount.transactions = append(exportedAccount.transactions, transaction)
+	}
+
+	var ret strings.Builder
+
+	for i := 0; i < len(exportedAccounts); i++ {
+		exportedAccount := exportedAccounts[i]
+		accountType := c.getAccountType(exportedAccount.account)
+
+		ret.WriteString(qifAccountHeader + "\n")
+		c.writeLine(&ret, 'N', exportedAccount.account.Name)
+		c.writeLine(&ret, 'T', accountType)
+		ret.WriteRune(qifEntryEnd)
+		ret.WriteString("\n")
+		ret.WriteString(qifTypeHeaderPrefix + accountType + "\n")
+
+		for j := 0; j < len(exportedAccount.transactions); j++ {
+			transaction := exportedAccount.transactions[j]
+			transactionSplits, exists := allTransactionSplits[transaction.TransactionId]
+
+			if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+				c.writeTransaction(&ret, transaction, transaction.CategoryId, transaction.Amount, transaction.Comment, accountMap, categoryMap)
+				continue
+			}
+
+			// each split of the transaction is exported as a separate entry with its own category and amount
+			for k := 0; k < len(transactionSplits); k++ {
+				split := transactionSplits[k]
+				comment := transaction.Comment
+
+				if split.Comment != "" {
+					comment = split.Comment
+				}
+
+				c.writeTransaction(&ret, transaction, split.CategoryId, split.Amount, comment, accountMap, categoryMap)
+			}
+		}
+	}
+
+	return []byte(ret.String()), nil
+}
+
+func (c *qifTransactionDataExporter) writeTransaction(ret *strings.Builder, transaction *models.Transaction, categoryId int64, amount int64, comment string, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) {
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	date := utils.FormatUnixTimeToLongDate(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transactionTimeZone)
+
+	c.writeLine(ret, 'D', date)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		c.writeLine(ret, 'T', utils.FormatAmount(amount))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		c.writeLine(ret, 'T', utils.FormatAmount(amount))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		c.writeLine(ret, 'T', utils.FormatAmount(-amount))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		c.writeLine(ret, 'T', utils.FormatAmount(-amount))
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		c.writeLine(ret, 'T', utils.FormatAmount(-transaction.RelatedAccountAmount))
+	}
+
+	if transaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+		c.writeLine(ret, 'C', "*")
+	} else if transaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		c.writeLine(ret, 'C', string(qifClearedStatusReconciled))
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		c.writeLine(ret, 'P', qifOpeningBalancePayeeText)
+		c.writeLine(ret, 'L', "["+c.getAccountName(transaction.AccountId, accountMap)+"]")
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		c.writeLine(ret, 'L', "["+c.getAccountName(transaction.RelatedAccountId, accountMap)+"]")
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		c.writeLine(ret, 'L', "["+c.getAccountName(transaction.AccountId, accountMap)+"]")
+	} else {
+		c.writeLine(ret, 'L', c.getCategoryName(categoryId, categoryMap))
+	}
+
+	if comment != "" {
+		c.writeLine(ret, 'M', comment)
+	}
+
+	ret.WriteRune(qifEntryEnd)
+	ret.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) writeLine(ret *strings.Builder, fieldType rune, value string) {
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	value = strings.ReplaceAll(value, "\r", " ")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	ret.WriteRune(fieldType)
+	ret.WriteString(value)
+	ret.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) getAccountType(account *models.Account) string {
+	switch account.Category {
+	case models.ACCOUNT_CATEGORY_CASH:
+		return qifCashAccountType
+	case models.ACCOUNT_CATEGORY_CREDIT_CARD:
+		return qifCreditCardAccountType
+	case models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT, models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT:
+		return qifBankAccountType
+	}
+
+	if account.Category.IsLiability() {
+		return qifLiabilityAccountType
+	}
+
+	return qifAssetAccountType
+}
+
+func (c *qifTransactionDataExporter) getAccountName(accountId int64, accountMap map[int64]*models.Account) string {
+	account, exists := accountMap[accountId]
+
+	if exists {
+		return account.Name
+	} else {
+		return ""
+	}
+}
+
+// getCategoryName returns the category name in format category:subcategory
+func (c *qifTransactionDataExporter) getCategoryName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return ""
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return category.Name
+	}
+
+	parentCategory, exists := categoryMap[category.ParentCategoryId]
+
+	if !exists {
+		return category.Name
+	}
+
+	return parentCategory.Name + ":" + category.Name
+}
@@ -0,0 +1,184 @@
+package qif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestQIFTransactionDataFileExporterToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := QifTransactionDataExporter
+	importer := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 6)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725100000000,
+		Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset: 0,
+		AccountId:         1,
+		Amount:            100000,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:        2,
+		TransactionTime:      1725165296000,
+		Type:                 models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset:    480,
+		CategoryId:           2,
+		AccountId:            1,
+		Amount:               12345,
+		Comment:              "Salary\nSeptember",
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     3,
+		TransactionTime:   1725321600000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        4,
+		AccountId:         2,
+		Amount:            1050,
+		Comment:           "Dinner",
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        4,
+		TransactionTime:      1725408000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               2000,
+		RelatedId:            5,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 2000,
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:        5,
+		TransactionTime:      1725408000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            2,
+		Amount:               2000,
+		RelatedId:            4,
+		RelatedAccountId:     1,
+		RelatedAccountAmount: 2000,
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:     6,
+		TransactionTime:   1725494400000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        4,
+		AccountId:         1,
+		Amount:            500,
+		Comment:           "Shopping",
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Test Account2",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 5)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: 1,
+		Name:             "Test Sub Category",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId: 3,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category2",
+	}
+	categoryMap[4] = &models.TransactionCategory{
+		CategoryId:       4,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Test Sub Category2",
+	}
+	categoryMap[5] = &models.TransactionCategory{
+		CategoryId:       5,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Test Sub Category3",
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit, 1)
+	allTransactionSplits[6] = []*models.TransactionSplit{
+		{TransactionId: 6, CategoryId: 4, Amount: 300},
+		{TransactionId: 6, CategoryId: 5, Amount: 200, Comment: "Gift"},
+	}
+
+	content, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, allTransactionSplits)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725062400), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Test Sub Category", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Salary September", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(1050), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test Account2", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Test Sub Category2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725408000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(2000), allNewTransactions[3].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Test Account2", allNewTransactions[3].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, int64(300), allNewTransactions[4].Amount)
+	assert.Equal(t, "Test Sub Category2", allNewTransactions[4].OriginalCategoryName)
+	assert.Equal(t, "Shopping", allNewTransactions[4].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, int64(200), allNewTransactions[5].Amount)
+	assert.Equal(t, "Test Sub Category3", allNewTransactions[5].OriginalCategoryName)
+	assert.Equal(t, "Gift", allNewTransactions[5].Comment)
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/converters/custom"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/default"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/excel"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/feidee"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/fireflyIII"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/gnucash"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/iif"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/jdcom"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ledger"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/mt"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
@@ -22,11 +24,23 @@ import (
 )
 
 // GetTransactionDataExporter returns the transaction data exporter according to the file type
-func GetTransactionDataExporter(fileType string) converter.TransactionDataExporter {
+func GetTransactionDataExporter(fileType string, user *models.User) converter.TransactionDataExporter {
 	if fileType == "csv" {
 		return _default.DefaultTransactionDataCSVFileConverter
 	} else if fileType == "tsv" {
 		return _default.DefaultTransactionDataTSVFileConverter
+	} else if fileType == "ofx" {
+		return ofx.OFXTransactionDataExporter
+	} else if fileType == "qif" {
+		return qif.QifTransactionDataExporter
+	} else if fileType == "beancount" {
+		return beancount.BeancountTransactionDataExporter
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataExporter
+	} else if fileType == "gnucash" {
+		return gnucash.GnuCashTransactionDataExporter
+	} else if fileType == "xlsx" {
+		return excel.CreateNewExcelOOXMLFileTransactionDataExporter(user.LongDateFormat, user.LongTimeFormat, user.Language)
 	} else {
 		return nil
 	}
@@ -64,6 +78,8 @@ func GetTransactionDataImporter(fileType string) (converter.TransactionDataImpor
 		return fireflyIII.FireflyIIITransactionDataCsvFileImporter, nil
 	} else if fileType == "beancount" {
 		return beancount.BeancountTransactionDataImporter, nil
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataImporter, nil
 	} else if fileType == "feidee_mymoney_csv" {
 		return feidee.FeideeMymoneyAppTransactionDataCsvFileImporter, nil
 	} else if fileType == "feidee_mymoney_xls" {
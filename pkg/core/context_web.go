@@ -16,6 +16,9 @@ const webContextTextualTokenFieldKey = "TOKEN_STRING"
 const webContextTokenClaimsFieldKey = "TOKEN_CLAIMS"
 const webContextTokenContextFieldKey = "TOKEN_CONTEXT"
 const webContextResponseErrorFieldKey = "RESPONSE_ERROR"
+const webContextLedgerIdFieldKey = "LEDGER_ID"
+const webContextLedgerOwnerUidFieldKey = "LEDGER_OWNER_UID"
+const webContextLedgerRoleFieldKey = "LEDGER_ROLE"
 
 // AcceptLanguageHeaderName represents the header name of accept language
 const AcceptLanguageHeaderName = "Accept-Language"
@@ -29,6 +32,9 @@ const ClientTimezoneOffsetHeaderName = "X-Timezone-Offset"
 // ClientTimezoneNameHeaderName represents the header name of client timezone name
 const ClientTimezoneNameHeaderName = "X-Timezone-Name"
 
+// LedgerIdHeaderName represents the header name of the ledger id which current request operates on
+const LedgerIdHeaderName = "X-Ledger-Id"
+
 const tokenHeaderName = "Authorization"
 const tokenHeaderValuePrefix = "bearer "
 const tokenQueryStringParam = "token"
@@ -145,6 +151,59 @@ func (c *WebContext) GetCurrentUid() int64 {
 	return claims.Uid
 }
 
+// SetCurrentLedgerId sets the ledger id which current request operates on to context
+func (c *WebContext) SetCurrentLedgerId(ledgerId int64) {
+	c.Set(webContextLedgerIdFieldKey, ledgerId)
+}
+
+// GetCurrentLedgerId returns the ledger id which current request operates on
+func (c *WebContext) GetCurrentLedgerId() int64 {
+	ledgerId, exists := c.Get(webContextLedgerIdFieldKey)
+
+	if !exists {
+		return 0
+	}
+
+	return ledgerId.(int64)
+}
+
+// SetCurrentLedgerOwnerUid sets the owner uid of the ledger which current request operates on to context
+func (c *WebContext) SetCurrentLedgerOwnerUid(uid int64) {
+	c.Set(webContextLedgerOwnerUidFieldKey, uid)
+}
+
+// GetCurrentLedgerOwnerUid returns the owner uid of the ledger which current request operates on, returns current user uid if the ledger is not shared by others
+func (c *WebContext) GetCurrentLedgerOwnerUid() int64 {
+	uid, exists := c.Get(webContextLedgerOwnerUidFieldKey)
+
+	if !exists {
+		return c.GetCurrentUid()
+	}
+
+	return uid.(int64)
+}
+
+// SetCurrentLedgerRole sets the role of current user in the ledger which current request operates on to context
+func (c *WebContext) SetCurrentLedgerRole(role LedgerMemberRole) {
+	c.Set(webContextLedgerRoleFieldKey, role)
+}
+
+// GetCurrentLedgerRole returns the role of current user in the ledger which current request operates on
+func (c *WebContext) GetCurrentLedgerRole() LedgerMemberRole {
+	role, exists := c.Get(webContextLedgerRoleFieldKey)
+
+	if !exists {
+		return LEDGER_MEMBER_ROLE_OWNER
+	}
+
+	return role.(LedgerMemberRole)
+}
+
+// GetLedgerIdFromHeader returns the textual ledger id from the request header
+func (c *WebContext) GetLedgerIdFromHeader() string {
+	return c.GetHeader(LedgerIdHeaderName)
+}
+
 // GetTokenStringFromHeader returns the token string from the request header
 func (c *WebContext) GetTokenStringFromHeader() string {
 	tokenHeader := c.GetHeader(tokenHeaderName)
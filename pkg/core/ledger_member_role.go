@@ -0,0 +1,68 @@
+package core
+
+// LedgerMemberRole represents the role of a member in a ledger
+type LedgerMemberRole byte
+
+// Ledger member roles
+const (
+	LEDGER_MEMBER_ROLE_OWNER               LedgerMemberRole = 1
+	LEDGER_MEMBER_ROLE_EDITOR              LedgerMemberRole = 2
+	LEDGER_MEMBER_ROLE_VIEWER              LedgerMemberRole = 3
+	LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR LedgerMemberRole = 4
+)
+
+// LedgerPermission represents the permission of an operation in a ledger
+type LedgerPermission byte
+
+// Ledger permissions
+const (
+	LEDGER_PERMISSION_READ_BASIC_DATA LedgerPermission = 1
+	LEDGER_PERMISSION_READ            LedgerPermission = 2
+	LEDGER_PERMISSION_ADD_TRANSACTION LedgerPermission = 3
+	LEDGER_PERMISSION_WRITE           LedgerPermission = 4
+	LEDGER_PERMISSION_MANAGE          LedgerPermission = 5
+)
+
+// IsValid returns whether the role is valid
+func (r LedgerMemberRole) IsValid() bool {
+	return r >= LEDGER_MEMBER_ROLE_OWNER && r <= LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR
+}
+
+// IsShareable returns whether the role can be granted to a shared member
+func (r LedgerMemberRole) IsShareable() bool {
+	return r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_VIEWER || r == LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR
+}
+
+// HasPermission returns whether the role has the specified permission
+func (r LedgerMemberRole) HasPermission(permission LedgerPermission) bool {
+	switch permission {
+	case LEDGER_PERMISSION_READ_BASIC_DATA:
+		return r.IsValid()
+	case LEDGER_PERMISSION_READ:
+		return r == LEDGER_MEMBER_ROLE_OWNER || r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_VIEWER
+	case LEDGER_PERMISSION_ADD_TRANSACTION:
+		return r == LEDGER_MEMBER_ROLE_OWNER || r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR
+	case LEDGER_PERMISSION_WRITE:
+		return r == LEDGER_MEMBER_ROLE_OWNER || r == LEDGER_MEMBER_ROLE_EDITOR
+	case LEDGER_PERMISSION_MANAGE:
+		return r == LEDGER_MEMBER_ROLE_OWNER
+	default:
+		return false
+	}
+}
+
+// String returns a textual representation of the ledger member role
+func (r LedgerMemberRole) String() string {
+	switch r {
+	case LEDGER_MEMBER_ROLE_OWNER:
+		return "Owner"
+	case LEDGER_MEMBER_ROLE_EDITOR:
+		return "Editor"
+	case LEDGER_MEMBER_ROLE_VIEWER:
+		return "Viewer"
+	case LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR:
+		return "Transaction Creator"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerMemberRoleHasPermission_Owner(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_OWNER
+
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ_BASIC_DATA))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_WRITE))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_MANAGE))
+}
+
+func TestLedgerMemberRoleHasPermission_Editor(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_EDITOR
+
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ_BASIC_DATA))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_WRITE))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_MANAGE))
+}
+
+func TestLedgerMemberRoleHasPermission_Viewer(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_VIEWER
+
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ_BASIC_DATA))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_WRITE))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_MANAGE))
+}
+
+func TestLedgerMemberRoleHasPermission_TransactionCreator(t *testing.T) {
+	role := LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR
+
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_READ_BASIC_DATA))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_READ))
+	assert.Equal(t, true, role.HasPermission(LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_WRITE))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_MANAGE))
+}
+
+func TestLedgerMemberRoleHasPermission_InvalidRole(t *testing.T) {
+	role := LedgerMemberRole(0)
+
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_READ_BASIC_DATA))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_READ))
+	assert.Equal(t, false, role.HasPermission(LEDGER_PERMISSION_ADD_TRANSACTION))
+}
+
+func TestLedgerMemberRoleIsShareable(t *testing.T) {
+	assert.Equal(t, false, LEDGER_MEMBER_ROLE_OWNER.IsShareable())
+	assert.Equal(t, true, LEDGER_MEMBER_ROLE_EDITOR.IsShareable())
+	assert.Equal(t, true, LEDGER_MEMBER_ROLE_VIEWER.IsShareable())
+	assert.Equal(t, true, LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR.IsShareable())
+	assert.Equal(t, false, LedgerMemberRole(5).IsShareable())
+}
@@ -83,6 +83,22 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 
 	if config.EnableCreateScheduledTransaction {
 		Container.registerIntervalJob(ctx, CreateScheduledTransactionJob)
+
+		if config.EnableScheduledTransactionCatchUp && config.CatchUpScheduledTransactionOnStartup {
+			Container.registerIntervalJob(ctx, CatchUpScheduledTransactionJob)
+		}
+	}
+
+	if config.EnablePurgeExpiredDeletedData {
+		Container.registerIntervalJob(ctx, PurgeExpiredDeletedDataJob)
+	}
+
+	if config.EnableSaveExchangeRatesSnapshot {
+		Container.registerIntervalJob(ctx, SaveExchangeRatesSnapshotJob)
+	}
+
+	if config.EnableUpdateNetWorthSnapshots {
+		Container.registerIntervalJob(ctx, UpdateNetWorthSnapshotsJob)
 	}
 }
 
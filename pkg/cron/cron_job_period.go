@@ -33,6 +33,10 @@ type CronJobFixedTimePeriod struct {
 	Time time.Time
 }
 
+// CronJobStartupPeriod represents the period of execution once the scheduler starts
+type CronJobStartupPeriod struct {
+}
+
 // GetInterval returns the interval time of the period of CronJobIntervalPeriod
 func (p CronJobIntervalPeriod) GetInterval() time.Duration {
 	return p.Interval
@@ -77,3 +81,13 @@ func (p CronJobFixedTimePeriod) GetInterval() time.Duration {
 func (p CronJobFixedTimePeriod) ToJobDefinition() gocron.JobDefinition {
 	return gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(p.Time))
 }
+
+// GetInterval returns the interval time of the period of CronJobStartupPeriod
+func (p CronJobStartupPeriod) GetInterval() time.Duration {
+	return 0
+}
+
+// ToJobDefinition returns the gocron job definition of the period of CronJobStartupPeriod
+func (p CronJobStartupPeriod) ToJobDefinition() gocron.JobDefinition {
+	return gocron.OneTimeJob(gocron.OneTimeJobStartImmediately())
+}
@@ -194,3 +194,47 @@ func TestCronJobNextRunTimeWithFixedTimePeriod(t *testing.T) {
 	err = scheduler.Shutdown()
 	assert.Nil(t, err)
 }
+
+func TestCronJobRunWithStartupPeriod(t *testing.T) {
+	scheduler, err := gocron.NewScheduler(
+		gocron.WithLocation(time.Local),
+	)
+	assert.Nil(t, err)
+
+	runCount := make(chan int, 2)
+
+	job := CronJob{
+		Name:        "TestCronJobWithStartupPeriod",
+		Description: "The test cron job",
+		Period:      CronJobStartupPeriod{},
+		Run: func(c *core.CronContext) error {
+			runCount <- 1
+			return nil
+		},
+	}
+
+	_, err = scheduler.NewJob(
+		job.Period.ToJobDefinition(),
+		gocron.NewTask(job.doRun),
+		gocron.WithName(job.Name),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	assert.Nil(t, err)
+
+	scheduler.Start()
+
+	select {
+	case <-runCount:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "job has not run after the scheduler started")
+	}
+
+	select {
+	case <-runCount:
+		assert.Fail(t, "job has run more than once")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	err = scheduler.Shutdown()
+	assert.Nil(t, err)
+}
@@ -4,7 +4,10 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -27,6 +30,64 @@ var CreateScheduledTransactionJob = &CronJob{
 		Second: 0,
 	},
 	Run: func(c *core.CronContext) error {
-		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
+		currentConfig := settings.Container.GetCurrentConfig()
+		maxCatchUpDays := uint32(0)
+
+		if currentConfig.EnableScheduledTransactionCatchUp {
+			maxCatchUpDays = currentConfig.MaxScheduledTransactionCatchUpDays
+		}
+
+		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval(), maxCatchUpDays)
+	},
+}
+
+// CatchUpScheduledTransactionJob represents the cron job which create the missed transactions of scheduled transaction templates once the server starts
+var CatchUpScheduledTransactionJob = &CronJob{
+	Name:        "CatchUpScheduledTransaction",
+	Description: "Create the missed transactions of scheduled transaction templates once the server starts.",
+	Period:      CronJobStartupPeriod{},
+	Run: func(c *core.CronContext) error {
+		maxCatchUpDays := settings.Container.GetCurrentConfig().MaxScheduledTransactionCatchUpDays
+		return services.Transactions.CatchUpScheduledTransactions(c, time.Now().Unix(), maxCatchUpDays)
+	},
+}
+
+// PurgeExpiredDeletedDataJob represents the cron job which periodically purge the deleted data in trash bin which exceeds the retention period from the database
+var PurgeExpiredDeletedDataJob = &CronJob{
+	Name:        "PurgeExpiredDeletedData",
+	Description: "Periodically purge the deleted data in trash bin which exceeds the retention period from the database.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		retentionDays := settings.Container.GetCurrentConfig().DeletedDataRetentionDays
+		maxDeletedUnixTime := time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour).Unix()
+		return services.Trash.PurgeAllExpiredDeletedData(c, maxDeletedUnixTime)
+	},
+}
+
+// SaveExchangeRatesSnapshotJob represents the cron job which periodically save the latest exchange rates of the configured data source as daily snapshot
+var SaveExchangeRatesSnapshotJob = &CronJob{
+	Name:        "SaveExchangeRatesSnapshot",
+	Description: "Periodically save the latest exchange rates of the configured data source as daily snapshot.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 23,
+	},
+	Run: func(c *core.CronContext) error {
+		return exchangerates.Container.SaveLatestExchangeRatesSnapshot(c, settings.Container.GetCurrentConfig())
+	},
+}
+
+// UpdateNetWorthSnapshotsJob represents the cron job which periodically update the monthly net worth snapshots of the ledgers whose snapshots are outdated
+var UpdateNetWorthSnapshotsJob = &CronJob{
+	Name:        "UpdateNetWorthSnapshots",
+	Description: "Periodically update the monthly net worth snapshots of the ledgers whose snapshots are outdated.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 2,
+	},
+	Run: func(c *core.CronContext) error {
+		currentConfig := settings.Container.GetCurrentConfig()
+		lastYearMonth := models.GetNetWorthSnapshotLastYearMonth(time.Now().Unix())
+		return services.NetWorthSnapshots.UpdateAllSnapshots(c, lastYearMonth, exchangerates.Container.GetNetWorthSnapshotAmountConverterGetter(currentConfig))
 	},
 }
@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to amortization schedules
+var (
+	ErrAmortizationScheduleIdInvalid              = NewNormalError(NormalSubcategoryAmortizationSchedule, 0, http.StatusBadRequest, "amortization schedule id is invalid")
+	ErrAmortizationScheduleNotFound               = NewNormalError(NormalSubcategoryAmortizationSchedule, 1, http.StatusBadRequest, "amortization schedule not found")
+	ErrAmortizationMethodInvalid                  = NewNormalError(NormalSubcategoryAmortizationSchedule, 2, http.StatusBadRequest, "amortization method is invalid")
+	ErrAmortizationAnnualInterestRateInvalid      = NewNormalError(NormalSubcategoryAmortizationSchedule, 3, http.StatusBadRequest, "annual interest rate is invalid")
+	ErrAmortizationInstallmentCountInvalid        = NewNormalError(NormalSubcategoryAmortizationSchedule, 4, http.StatusBadRequest, "installment count is invalid")
+	ErrAmortizationFirstPaymentDateInvalid        = NewNormalError(NormalSubcategoryAmortizationSchedule, 5, http.StatusBadRequest, "first payment date is invalid")
+	ErrAmortizationScheduleAccountInvalid         = NewNormalError(NormalSubcategoryAmortizationSchedule, 6, http.StatusBadRequest, "amortization schedule can only be attached to debt or credit card account")
+	ErrAmortizationPaymentAccountInvalid          = NewNormalError(NormalSubcategoryAmortizationSchedule, 7, http.StatusBadRequest, "payment account is invalid")
+	ErrAmortizationPaymentAccountCurrencyNotMatch = NewNormalError(NormalSubcategoryAmortizationSchedule, 8, http.StatusBadRequest, "payment account currency does not match debt account currency")
+	ErrAmortizationInterestCategoryRequired       = NewNormalError(NormalSubcategoryAmortizationSchedule, 9, http.StatusBadRequest, "interest category is required")
+)
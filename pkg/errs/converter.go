@@ -31,4 +31,6 @@ var (
 	ErrInvalidXmlFile                      = NewNormalError(NormalSubcategoryConverter, 24, http.StatusBadRequest, "invalid xml file")
 	ErrInvalidMT940File                    = NewNormalError(NormalSubcategoryConverter, 25, http.StatusBadRequest, "invalid mt940 file")
 	ErrInvalidJSONFile                     = NewNormalError(NormalSubcategoryConverter, 26, http.StatusBadRequest, "invalid json file")
+	ErrInvalidLedgerFile                   = NewNormalError(NormalSubcategoryConverter, 27, http.StatusBadRequest, "invalid ledger file")
+	ErrLedgerFileNotSupportInclude         = NewNormalError(NormalSubcategoryConverter, 28, http.StatusBadRequest, "not support include directive for ledger file")
 )
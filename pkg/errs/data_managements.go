@@ -6,7 +6,10 @@ import (
 
 // Error codes related to data management
 var (
-	ErrDataExportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
-	ErrDataImportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
-	ErrImportTooManyTransaction = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrDataExportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataImportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
+	ErrImportTooManyTransaction          = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrUserDataBackupInvalid             = NewNormalError(NormalSubcategoryDataManagement, 4, http.StatusBadRequest, "user data backup file is invalid")
+	ErrUserDataBackupVersionNotSupported = NewNormalError(NormalSubcategoryDataManagement, 5, http.StatusBadRequest, "user data backup version is not supported")
+	ErrUserDataRestoreTargetNotEmpty     = NewNormalError(NormalSubcategoryDataManagement, 6, http.StatusBadRequest, "cannot restore user data backup to user with existing data")
 )
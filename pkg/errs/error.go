@@ -45,6 +45,14 @@ const (
 	NormalSubcategoryOAuth2                 = 17
 	NormalSubcategoryInsightsExplorer       = 18
 	NormalSubcategoryTagGroup               = 19
+	NormalSubcategoryBudget                 = 20
+	NormalSubcategoryLedger                 = 21
+	NormalSubcategoryTransactionRule        = 22
+	NormalSubcategoryPayee                  = 23
+	NormalSubcategoryAmortizationSchedule   = 24
+	NormalSubcategoryInvestment             = 25
+	NormalSubcategorySecurityPrice          = 26
+	NormalSubcategoryExchangeRateSnapshot   = 27
 )
 
 // Error represents the specific error returned to user
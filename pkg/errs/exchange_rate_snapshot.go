@@ -0,0 +1,10 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rate snapshots and data sources
+var (
+	ErrExchangeRateSnapshotNotFound = NewNormalError(NormalSubcategoryExchangeRateSnapshot, 0, http.StatusBadRequest, "no exchange rate data on or before the date")
+	ErrExchangeRateDateInvalid      = NewNormalError(NormalSubcategoryExchangeRateSnapshot, 1, http.StatusBadRequest, "exchange rate date is invalid")
+	ErrExchangeRatesDataIsStale     = NewNormalError(NormalSubcategoryExchangeRateSnapshot, 2, http.StatusBadRequest, "exchange rates data is stale")
+)
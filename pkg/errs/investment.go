@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to investments
+var (
+	ErrInvestmentTransactionIdInvalid       = NewNormalError(NormalSubcategoryInvestment, 0, http.StatusBadRequest, "investment transaction id is invalid")
+	ErrInvestmentTransactionNotFound        = NewNormalError(NormalSubcategoryInvestment, 1, http.StatusBadRequest, "investment transaction not found")
+	ErrInvestmentTransactionTypeInvalid     = NewNormalError(NormalSubcategoryInvestment, 2, http.StatusBadRequest, "investment transaction type is invalid")
+	ErrInvestmentAccountInvalid             = NewNormalError(NormalSubcategoryInvestment, 3, http.StatusBadRequest, "investment transaction can only be attached to investment account")
+	ErrInvestmentSymbolInvalid              = NewNormalError(NormalSubcategoryInvestment, 4, http.StatusBadRequest, "security symbol is invalid")
+	ErrInvestmentQuantityInvalid            = NewNormalError(NormalSubcategoryInvestment, 5, http.StatusBadRequest, "security quantity is invalid")
+	ErrInvestmentSellQuantityExceedsHolding = NewNormalError(NormalSubcategoryInvestment, 6, http.StatusBadRequest, "sell quantity exceeds holding quantity")
+	ErrInvestmentTransactionCannotBeDeleted = NewNormalError(NormalSubcategoryInvestment, 7, http.StatusBadRequest, "investment transaction cannot be deleted because later sell transactions depend on it")
+	ErrInvestmentFeeExceedsAmount           = NewNormalError(NormalSubcategoryInvestment, 8, http.StatusBadRequest, "fee cannot exceed amount of sell or dividend transaction")
+)
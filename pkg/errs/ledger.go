@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to ledgers
+var (
+	ErrLedgerIdInvalid               = NewNormalError(NormalSubcategoryLedger, 0, http.StatusBadRequest, "ledger id is invalid")
+	ErrLedgerNotFound                = NewNormalError(NormalSubcategoryLedger, 1, http.StatusBadRequest, "ledger not found")
+	ErrLedgerInUseCannotBeDeleted    = NewNormalError(NormalSubcategoryLedger, 2, http.StatusBadRequest, "ledger is in use and cannot be deleted")
+	ErrLedgerMemberIdInvalid         = NewNormalError(NormalSubcategoryLedger, 3, http.StatusBadRequest, "ledger member id is invalid")
+	ErrLedgerMemberNotFound          = NewNormalError(NormalSubcategoryLedger, 4, http.StatusBadRequest, "ledger member not found")
+	ErrLedgerMemberRoleInvalid       = NewNormalError(NormalSubcategoryLedger, 5, http.StatusBadRequest, "ledger member role is invalid")
+	ErrLedgerMemberAlreadyExists     = NewNormalError(NormalSubcategoryLedger, 6, http.StatusBadRequest, "user is already a member of this ledger")
+	ErrCannotShareLedgerWithYourself = NewNormalError(NormalSubcategoryLedger, 7, http.StatusBadRequest, "cannot share ledger with yourself")
+	ErrDefaultLedgerCannotBeShared   = NewNormalError(NormalSubcategoryLedger, 8, http.StatusBadRequest, "default ledger cannot be shared")
+	ErrNoPermissionToOperateLedger   = NewNormalError(NormalSubcategoryLedger, 9, http.StatusForbidden, "no permission to perform this operation in current ledger")
+)
@@ -0,0 +1,9 @@
+package errs
+
+import "net/http"
+
+// Error codes related to security prices
+var (
+	ErrSecurityPriceDateInvalid = NewNormalError(NormalSubcategorySecurityPrice, 0, http.StatusBadRequest, "security price date is invalid")
+	ErrSecurityPriceNotFound    = NewNormalError(NormalSubcategorySecurityPrice, 1, http.StatusBadRequest, "security price not found")
+)
@@ -45,4 +45,20 @@ var (
 	ErrCannotMoveTransactionFromOrToHiddenAccount                  = NewNormalError(NormalSubcategoryTransaction, 38, http.StatusBadRequest, "cannot move transaction from or to hidden account")
 	ErrCannotMoveTransactionFromOrToParentAccount                  = NewNormalError(NormalSubcategoryTransaction, 39, http.StatusBadRequest, "cannot move transaction from or to parent account")
 	ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies = NewNormalError(NormalSubcategoryTransaction, 40, http.StatusBadRequest, "cannot move transaction between accounts with different currencies")
+	ErrTransactionHasTooManySplits                                 = NewNormalError(NormalSubcategoryTransaction, 41, http.StatusBadRequest, "transaction has too many splits")
+	ErrTransactionSplitsTooFew                                     = NewNormalError(NormalSubcategoryTransaction, 42, http.StatusBadRequest, "split transaction must have at least two splits")
+	ErrTransactionTypeCannotBeSplit                                = NewNormalError(NormalSubcategoryTransaction, 43, http.StatusBadRequest, "only income or expense transaction can be split")
+	ErrTransactionSplitsAmountNotEqual                             = NewNormalError(NormalSubcategoryTransaction, 44, http.StatusBadRequest, "sum of split amounts must equal transaction amount")
+	ErrCannotModifyReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 45, http.StatusBadRequest, "cannot modify reconciled transaction")
+	ErrCannotDeleteReconciledTransaction                           = NewNormalError(NormalSubcategoryTransaction, 46, http.StatusBadRequest, "cannot delete reconciled transaction")
+	ErrCannotMoveReconciledTransaction                             = NewNormalError(NormalSubcategoryTransaction, 47, http.StatusBadRequest, "cannot move reconciled transaction")
+	ErrTransactionReconciliationStatusInvalid                      = NewNormalError(NormalSubcategoryTransaction, 48, http.StatusBadRequest, "transaction reconciliation status is invalid")
+	ErrReconciledTransactionNotUnlocked                            = NewNormalError(NormalSubcategoryTransaction, 49, http.StatusBadRequest, "reconciled transaction must be unlocked first")
+	ErrReconciliationEndingBalanceNotMatch                         = NewNormalError(NormalSubcategoryTransaction, 50, http.StatusBadRequest, "cleared balance does not match statement ending balance")
+	ErrCannotCreateTransactionBeforeLockTime                       = NewNormalError(NormalSubcategoryTransaction, 51, http.StatusBadRequest, "cannot add transaction before transaction lock date")
+	ErrCannotModifyTransactionBeforeLockTime                       = NewNormalError(NormalSubcategoryTransaction, 52, http.StatusBadRequest, "cannot modify transaction before transaction lock date")
+	ErrCannotDeleteTransactionBeforeLockTime                       = NewNormalError(NormalSubcategoryTransaction, 53, http.StatusBadRequest, "cannot delete transaction before transaction lock date")
+	ErrCannotMoveTransactionBeforeLockTime                         = NewNormalError(NormalSubcategoryTransaction, 54, http.StatusBadRequest, "cannot move transaction before transaction lock date")
+	ErrCannotRestoreTransactionBeforeLockTime                      = NewNormalError(NormalSubcategoryTransaction, 55, http.StatusBadRequest, "cannot restore transaction before transaction lock date")
+	ErrTransactionLockTimeChangeNotConfirmed                       = NewNormalError(NormalSubcategoryTransaction, 56, http.StatusBadRequest, "password or passcode is required to change transaction lock date")
 )
@@ -0,0 +1,17 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid           = NewNormalError(NormalSubcategoryTransactionRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound            = NewNormalError(NormalSubcategoryTransactionRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleTypeInvalid         = NewNormalError(NormalSubcategoryTransactionRule, 2, http.StatusBadRequest, "transaction rule transaction type is invalid")
+	ErrTransactionRuleNoConditions        = NewNormalError(NormalSubcategoryTransactionRule, 3, http.StatusBadRequest, "transaction rule must have at least one condition")
+	ErrTransactionRuleNoActions           = NewNormalError(NormalSubcategoryTransactionRule, 4, http.StatusBadRequest, "transaction rule must have at least one action")
+	ErrTransactionRuleCommentRegexInvalid = NewNormalError(NormalSubcategoryTransactionRule, 5, http.StatusBadRequest, "transaction rule comment regular expression is invalid")
+	ErrTransactionRuleAmountRangeInvalid  = NewNormalError(NormalSubcategoryTransactionRule, 6, http.StatusBadRequest, "transaction rule amount range is invalid")
+	ErrTransactionRuleCategoryTypeInvalid = NewNormalError(NormalSubcategoryTransactionRule, 7, http.StatusBadRequest, "transaction rule category type does not match transaction type")
+	ErrTransactionRuleHasTooManyTags      = NewNormalError(NormalSubcategoryTransactionRule, 8, http.StatusBadRequest, "transaction rule has too many tags")
+	ErrTransactionRuleTimeRangeInvalid    = NewNormalError(NormalSubcategoryTransactionRule, 9, http.StatusBadRequest, "transaction rule apply time range is invalid")
+)
@@ -11,4 +11,8 @@ var (
 	ErrScheduledTransactionFrequencyInvalid                  = NewNormalError(NormalSubcategoryTemplate, 4, http.StatusBadRequest, "scheduled transaction frequency is invalid")
 	ErrTransactionTemplateHasTooManyTags                     = NewNormalError(NormalSubcategoryTemplate, 5, http.StatusBadRequest, "transaction template has too many tags")
 	ErrScheduledTransactionTemplateStartDataLaterThanEndDate = NewNormalError(NormalSubcategoryTemplate, 6, http.StatusBadRequest, "scheduled transaction start date is later than end time")
+	ErrTransactionTemplateManagedByAmortizationSchedule      = NewNormalError(NormalSubcategoryTemplate, 7, http.StatusBadRequest, "transaction template is managed by amortization schedule")
+	ErrScheduledTransactionRecurrenceRuleInvalid             = NewNormalError(NormalSubcategoryTemplate, 8, http.StatusBadRequest, "scheduled transaction recurrence rule is invalid")
+	ErrScheduledTransactionOccurrenceNotFound                = NewNormalError(NormalSubcategoryTemplate, 9, http.StatusBadRequest, "scheduled transaction occurrence not found")
+	ErrScheduledTransactionPostponedDateInvalid              = NewNormalError(NormalSubcategoryTemplate, 10, http.StatusBadRequest, "scheduled transaction postponed date is invalid")
 )
@@ -0,0 +1,230 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const bankOfEnglandExchangeRateUrlFormat = "https://www.bankofengland.co.uk/boeapps/database/_iadb-fromshowcolumns.asp?csv.x=yes&Datefrom=%s&Dateto=now&SeriesCodes=%s&CSVF=TN&UsingCodes=Y&VPD=Y&VFD=N"
+const bankOfEnglandExchangeRateReferenceUrl = "https://www.bankofengland.co.uk/boeapps/database/Rates.asp"
+const bankOfEnglandDataSource = "Bank of England"
+const bankOfEnglandBaseCurrency = "GBP"
+
+const bankOfEnglandRequestDateFormat = "02/Jan/2006"
+const bankOfEnglandDataUpdateDateFormat = "02 Jan 2006 15:04"
+const bankOfEnglandDataUpdateDateTimezone = "Europe/London"
+const bankOfEnglandRequestRecentDays = 14
+
+// bankOfEnglandSeriesCodeCurrencies represents the series codes of daily spot exchange rates against sterling and their currencies
+var bankOfEnglandSeriesCodeCurrencies = map[string]string{
+	"XUDLADS":  "AUD",
+	"XUDLCDS":  "CAD",
+	"XUDLBK89": "CNY",
+	"XUDLDKS":  "DKK",
+	"XUDLERS":  "EUR",
+	"XUDLHDS":  "HKD",
+	"XUDLJYS":  "JPY",
+	"XUDLNDS":  "NZD",
+	"XUDLNKS":  "NOK",
+	"XUDLSRS":  "SAR",
+	"XUDLSGS":  "SGD",
+	"XUDLZRS":  "ZAR",
+	"XUDLSKS":  "SEK",
+	"XUDLSFS":  "CHF",
+	"XUDLTWS":  "TWD",
+	"XUDLUSS":  "USD",
+}
+
+// BankOfEnglandDataSource defines the structure of exchange rates data source of the Bank of England
+type BankOfEnglandDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// BankOfEnglandExchangeRateData represents the whole data from the Bank of England
+type BankOfEnglandExchangeRateData struct {
+	SeriesCodes []string
+	Rows        []*BankOfEnglandExchangeRateDataRow
+}
+
+// BankOfEnglandExchangeRateDataRow represents the exchange rates data of one day from the Bank of England
+type BankOfEnglandExchangeRateDataRow struct {
+	Date  string
+	Rates []string
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from the Bank of England
+func (e *BankOfEnglandExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if len(e.Rows) < 1 {
+		log.Errorf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] exchange rates rows is empty")
+		return nil
+	}
+
+	timezone, err := time.LoadLocation(bankOfEnglandDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfEnglandDataUpdateDateTimezone)
+		return nil
+	}
+
+	latestUpdateTime := int64(0)
+	latestCurrencyExchangeRateTime := make(map[string]int64)
+	latestExchangeRates := make(map[string]*models.LatestExchangeRate)
+
+	for i := 0; i < len(e.Rows); i++ {
+		row := e.Rows[i]
+		updateDateTime := row.Date + " 16:00" // The spot exchange rates are observed by the Bank's foreign exchange desk at 4pm London time
+		updateTime, err := time.ParseInLocation(bankOfEnglandDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.Warnf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+			continue
+		}
+
+		for j := 0; j < len(row.Rates) && j < len(e.SeriesCodes); j++ {
+			currency, exists := bankOfEnglandSeriesCodeCurrencies[e.SeriesCodes[j]]
+
+			if !exists || row.Rates[j] == "" {
+				continue
+			}
+
+			if latestTime, exists := latestCurrencyExchangeRateTime[currency]; exists && updateTime.Unix() <= latestTime {
+				continue
+			}
+
+			rate, err := utils.StringToFloat64(row.Rates[j])
+
+			if err != nil {
+				log.Warnf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] failed to parse rate, currency is %s, rate is %s", currency, row.Rates[j])
+				continue
+			}
+
+			if rate <= 0 {
+				log.Warnf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] rate is invalid, currency is %s, rate is %s", currency, row.Rates[j])
+				continue
+			}
+
+			latestCurrencyExchangeRateTime[currency] = updateTime.Unix()
+			latestExchangeRates[currency] = &models.LatestExchangeRate{
+				Currency: currency,
+				Rate:     row.Rates[j],
+			}
+
+			if updateTime.Unix() > latestUpdateTime {
+				latestUpdateTime = updateTime.Unix()
+			}
+		}
+	}
+
+	if len(latestExchangeRates) < 1 {
+		log.Errorf(c, "[bank_of_england_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestExchangeRates))
+
+	for _, exchangeRate := range latestExchangeRates {
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfEnglandDataSource,
+		ReferenceUrl:  bankOfEnglandExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime,
+		BaseCurrency:  bankOfEnglandBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// BuildRequests returns the Bank of England exchange rates http requests
+func (e *BankOfEnglandDataSource) BuildRequests() ([]*http.Request, error) {
+	seriesCodes := make([]string, 0, len(bankOfEnglandSeriesCodeCurrencies))
+
+	for seriesCode := range bankOfEnglandSeriesCodeCurrencies {
+		seriesCodes = append(seriesCodes, seriesCode)
+	}
+
+	sort.Strings(seriesCodes)
+
+	startDate := time.Now().AddDate(0, 0, -bankOfEnglandRequestRecentDays).Format(bankOfEnglandRequestDateFormat)
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfEnglandExchangeRateUrlFormat, startDate, strings.Join(seriesCodes, ",")), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Bank of England data source raw response
+func (e *BankOfEnglandDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+
+	bankOfEnglandData := &BankOfEnglandExchangeRateData{}
+
+	for {
+		items, err := csvReader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Errorf(c, "[bank_of_england_datasource.Parse] failed to parse csv data, content is %s, because %s", string(content), err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		if len(items) < 2 {
+			continue
+		}
+
+		if bankOfEnglandData.SeriesCodes == nil {
+			if strings.TrimSpace(items[0]) != "DATE" {
+				log.Errorf(c, "[bank_of_england_datasource.Parse] csv header is invalid, content is %s", string(content))
+				return nil, errs.ErrFailedToRequestRemoteApi
+			}
+
+			bankOfEnglandData.SeriesCodes = make([]string, len(items)-1)
+
+			for i := 1; i < len(items); i++ {
+				bankOfEnglandData.SeriesCodes[i-1] = strings.TrimSpace(items[i])
+			}
+
+			continue
+		}
+
+		row := &BankOfEnglandExchangeRateDataRow{
+			Date:  strings.TrimSpace(items[0]),
+			Rates: make([]string, len(items)-1),
+		}
+
+		for i := 1; i < len(items); i++ {
+			row.Rates[i-1] = strings.TrimSpace(items[i])
+		}
+
+		bankOfEnglandData.Rows = append(bankOfEnglandData.Rows, row)
+	}
+
+	latestExchangeRateResponse := bankOfEnglandData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[bank_of_england_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,126 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const bankOfEnglandMinimumRequiredContent = "DATE,XUDLUSS,XUDLERS,XUDLJYS\n" +
+	"11 Nov 2024,1.2880,1.2030,197.1500\n" +
+	"12 Nov 2024,1.2747,1.2012,196.3500\n"
+
+func TestBankOfEnglandDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "GBP", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfEnglandDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731427200), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestBankOfEnglandDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfEnglandMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 3)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.2747",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.2012",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "196.3500",
+	})
+}
+
+func TestBankOfEnglandDataSource_MissingValueOfLatestDate(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("DATE,XUDLUSS,XUDLERS\n"+
+		"11 Nov 2024,1.2880,1.2030\n"+
+		"12 Nov 2024,1.2747,\n"))
+	assert.Equal(t, nil, err)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.2747",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "1.2030",
+	})
+}
+
+func TestBankOfEnglandDataSource_BlankContent(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_OnlyHeader(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("DATE,XUDLUSS,XUDLERS\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_InvalidHeader(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<html><body>error</body></html>,\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_InvalidDate(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("DATE,XUDLUSS\n"+
+		"2024-11-12,1.2747\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_InvalidRate(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("DATE,XUDLUSS\n"+
+		"12 Nov 2024,null\n"))
+	assert.NotEqual(t, nil, err)
+
+	_, err = dataSource.Parse(context, []byte("DATE,XUDLUSS\n"+
+		"12 Nov 2024,0\n"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfEnglandDataSource_UnknownSeriesCode(t *testing.T) {
+	dataSource := &BankOfEnglandDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("DATE,IUDBEDR\n"+
+		"12 Nov 2024,4.75\n"))
+	assert.NotEqual(t, nil, err)
+}
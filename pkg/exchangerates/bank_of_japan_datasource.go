@@ -0,0 +1,190 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const bankOfJapanExchangeRateUrlFormat = "https://www.stat-search.boj.or.jp/api/v1/getDataCode?format=json&lang=en&db=FM08&startDate=%s&code=%s"
+const bankOfJapanExchangeRateReferenceUrl = "https://www.stat-search.boj.or.jp/index_en.html"
+const bankOfJapanDataSource = "日本銀行"
+const bankOfJapanBaseCurrency = "JPY"
+
+const bankOfJapanRequestDateFormat = "200601"
+const bankOfJapanDataUpdateDateFormat = "20060102 15:04"
+const bankOfJapanDataUpdateDateTimezone = "Asia/Tokyo"
+const bankOfJapanSuccessStatus = 200
+
+// bankOfJapanSeriesCodeCurrencies represents the series codes of the yen spot exchange rates at 17:00 in JST and their currencies,
+// the Tokyo market spot rates are only published against the US dollar and the euro
+var bankOfJapanSeriesCodeCurrencies = map[string]string{
+	"FXERD04": "USD",
+	"FXERD34": "EUR",
+}
+
+// BankOfJapanDataSource defines the structure of exchange rates data source of the Bank of Japan
+type BankOfJapanDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// BankOfJapanExchangeRateData represents the whole data from the Bank of Japan
+type BankOfJapanExchangeRateData struct {
+	Status    int                        `json:"STATUS"`
+	Message   string                     `json:"MESSAGE"`
+	ResultSet []*BankOfJapanSeriesResult `json:"RESULTSET"`
+}
+
+// BankOfJapanSeriesResult represents the time series data from the Bank of Japan
+type BankOfJapanSeriesResult struct {
+	SeriesCode string                   `json:"SERIES_CODE"`
+	Values     *BankOfJapanSeriesValues `json:"VALUES"`
+}
+
+// BankOfJapanSeriesValues represents the observation dates and values of the time series from the Bank of Japan
+type BankOfJapanSeriesValues struct {
+	SurveyDates []int64    `json:"SURVEY_DATES"`
+	Values      []*float64 `json:"VALUES"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from the Bank of Japan
+func (e *BankOfJapanExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if e.Status != bankOfJapanSuccessStatus {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] response status is %d, message is %s", e.Status, e.Message)
+		return nil
+	}
+
+	if len(e.ResultSet) < 1 {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] result set is empty")
+		return nil
+	}
+
+	timezone, err := time.LoadLocation(bankOfJapanDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", bankOfJapanDataUpdateDateTimezone)
+		return nil
+	}
+
+	latestUpdateTime := int64(0)
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.ResultSet))
+
+	for i := 0; i < len(e.ResultSet); i++ {
+		series := e.ResultSet[i]
+		currency, exists := bankOfJapanSeriesCodeCurrencies[series.SeriesCode]
+
+		if !exists || series.Values == nil {
+			continue
+		}
+
+		latestSurveyDate, latestValue := series.Values.getLatestValue()
+
+		if latestValue <= 0 {
+			log.Warnf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] there is no valid rate, series code is %s", series.SeriesCode)
+			continue
+		}
+
+		updateDateTime := utils.Int64ToString(latestSurveyDate) + " 17:00" // The spot exchange rates are observed at 17:00 in JST on every business day
+		updateTime, err := time.ParseInLocation(bankOfJapanDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.Warnf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", updateDateTime)
+			continue
+		}
+
+		finalRate := 1 / latestValue
+
+		if math.IsInf(finalRate, 0) {
+			continue
+		}
+
+		if updateTime.Unix() > latestUpdateTime {
+			latestUpdateTime = updateTime.Unix()
+		}
+
+		exchangeRates = append(exchangeRates, &models.LatestExchangeRate{
+			Currency: currency,
+			Rate:     utils.Float64ToString(finalRate),
+		})
+	}
+
+	if len(exchangeRates) < 1 {
+		log.Errorf(c, "[bank_of_japan_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    bankOfJapanDataSource,
+		ReferenceUrl:  bankOfJapanExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime,
+		BaseCurrency:  bankOfJapanBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+func (v *BankOfJapanSeriesValues) getLatestValue() (int64, float64) {
+	latestSurveyDate := int64(0)
+	latestValue := float64(0)
+
+	for i := 0; i < len(v.SurveyDates) && i < len(v.Values); i++ {
+		if v.Values[i] == nil || *v.Values[i] <= 0 {
+			continue
+		}
+
+		if v.SurveyDates[i] > latestSurveyDate {
+			latestSurveyDate = v.SurveyDates[i]
+			latestValue = *v.Values[i]
+		}
+	}
+
+	return latestSurveyDate, latestValue
+}
+
+// BuildRequests returns the Bank of Japan exchange rates http requests
+func (e *BankOfJapanDataSource) BuildRequests() ([]*http.Request, error) {
+	seriesCodes := make([]string, 0, len(bankOfJapanSeriesCodeCurrencies))
+
+	for seriesCode := range bankOfJapanSeriesCodeCurrencies {
+		seriesCodes = append(seriesCodes, seriesCode)
+	}
+
+	startDate := time.Now().AddDate(0, -1, 0).Format(bankOfJapanRequestDateFormat)
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfJapanExchangeRateUrlFormat, startDate, strings.Join(seriesCodes, ",")), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Bank of Japan data source raw response
+func (e *BankOfJapanDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	bankOfJapanData := &BankOfJapanExchangeRateData{}
+	err := json.Unmarshal(content, bankOfJapanData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_japan_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := bankOfJapanData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[bank_of_japan_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,145 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const bankOfJapanMinimumRequiredContent = "{\n" +
+	"  \"STATUS\": 200,\n" +
+	"  \"MESSAGEID\": \"M181000I\",\n" +
+	"  \"MESSAGE\": \"Successfully completed\",\n" +
+	"  \"RESULTSET\": [\n" +
+	"    {\n" +
+	"      \"SERIES_CODE\": \"FXERD04\",\n" +
+	"      \"NAME_OF_TIME_SERIES\": \"US.Dollar/Yen Spot Rate at 17:00 in JST, Tokyo Market\",\n" +
+	"      \"UNIT\": \"Yen per U.S. Dollar\",\n" +
+	"      \"FREQUENCY\": \"DAILY\",\n" +
+	"      \"VALUES\": {\n" +
+	"        \"SURVEY_DATES\": [20241111, 20241112, 20241113],\n" +
+	"        \"VALUES\": [153.5, 160, null]\n" +
+	"      }\n" +
+	"    }\n" +
+	"  ]\n" +
+	"}"
+
+const bankOfJapanMultipleCurrenciesContent = "{\n" +
+	"  \"STATUS\": 200,\n" +
+	"  \"MESSAGEID\": \"M181000I\",\n" +
+	"  \"MESSAGE\": \"Successfully completed\",\n" +
+	"  \"RESULTSET\": [\n" +
+	"    {\n" +
+	"      \"SERIES_CODE\": \"FXERD04\",\n" +
+	"      \"NAME_OF_TIME_SERIES\": \"US.Dollar/Yen Spot Rate at 17:00 in JST, Tokyo Market\",\n" +
+	"      \"UNIT\": \"Yen per U.S. Dollar\",\n" +
+	"      \"FREQUENCY\": \"DAILY\",\n" +
+	"      \"VALUES\": {\n" +
+	"        \"SURVEY_DATES\": [20241111, 20241112],\n" +
+	"        \"VALUES\": [153.5, 160]\n" +
+	"      }\n" +
+	"    },\n" +
+	"    {\n" +
+	"      \"SERIES_CODE\": \"FXERD34\",\n" +
+	"      \"NAME_OF_TIME_SERIES\": \"Euro/Yen Spot Rate at 17:00 in JST, Tokyo Market\",\n" +
+	"      \"UNIT\": \"Yen per Euro\",\n" +
+	"      \"FREQUENCY\": \"DAILY\",\n" +
+	"      \"VALUES\": {\n" +
+	"        \"SURVEY_DATES\": [20241111, 20241112],\n" +
+	"        \"VALUES\": [164.5, 125]\n" +
+	"      }\n" +
+	"    }\n" +
+	"  ]\n" +
+	"}"
+
+func TestBankOfJapanDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "JPY", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestBankOfJapanDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731398400), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestBankOfJapanDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.00625",
+	})
+}
+
+func TestBankOfJapanDataSource_MultipleCurrenciesDataExtractExchangeRates(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(bankOfJapanMultipleCurrenciesContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731398400), actualLatestExchangeRateResponse.UpdateTime)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 2)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.00625",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "EUR",
+		Rate:     "0.008",
+	})
+}
+
+func TestBankOfJapanDataSource_BlankContent(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfJapanDataSource_ErrorStatus(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"STATUS\": 400, \"MESSAGEID\": \"M181005E\", \"MESSAGE\": \"Invalid parameter\"}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfJapanDataSource_EmptyResultSet(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"STATUS\": 200, \"RESULTSET\": []}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfJapanDataSource_AllValuesAreNull(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"STATUS\": 200, \"RESULTSET\": [{\"SERIES_CODE\": \"FXERD04\", \"VALUES\": {\"SURVEY_DATES\": [20241111, 20241112], \"VALUES\": [null, null]}}]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestBankOfJapanDataSource_UnknownSeriesCode(t *testing.T) {
+	dataSource := &BankOfJapanDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"STATUS\": 200, \"RESULTSET\": [{\"SERIES_CODE\": \"FXERD01\", \"VALUES\": {\"SURVEY_DATES\": [20241112], \"VALUES\": [154.48]}}]}"))
+	assert.NotEqual(t, nil, err)
+}
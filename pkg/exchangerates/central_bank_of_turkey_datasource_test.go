@@ -0,0 +1,121 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const centralBankOfTurkeyMinimumRequiredContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<Tarih_Date Tarih=\"12.11.2024\" Date=\"11/12/2024\" Bulten_No=\"2024/213\">\n" +
+	"  <Currency CrossOrder=\"0\" Kod=\"USD\" CurrencyCode=\"USD\">\n" +
+	"    <Unit>1</Unit>\n" +
+	"    <Isim>ABD DOLARI</Isim>\n" +
+	"    <CurrencyName>US DOLLAR</CurrencyName>\n" +
+	"    <ForexBuying>32</ForexBuying>\n" +
+	"    <ForexSelling>32.0577</ForexSelling>\n" +
+	"  </Currency>\n" +
+	"  <Currency CrossOrder=\"11\" Kod=\"JPY\" CurrencyCode=\"JPY\">\n" +
+	"    <Unit>100</Unit>\n" +
+	"    <Isim>JAPON YENİ</Isim>\n" +
+	"    <CurrencyName>JAPENESE YEN</CurrencyName>\n" +
+	"    <ForexBuying>20</ForexBuying>\n" +
+	"    <ForexSelling>20.1</ForexSelling>\n" +
+	"  </Currency>\n" +
+	"  <Currency CrossOrder=\"19\" Kod=\"XDR\" CurrencyCode=\"XDR\">\n" +
+	"    <Unit>1</Unit>\n" +
+	"    <Isim>ÖZEL ÇEKME HAKKI (SDR)</Isim>\n" +
+	"    <CurrencyName>SPECIAL DRAWING RIGHT (SDR)</CurrencyName>\n" +
+	"    <ForexBuying>45.6</ForexBuying>\n" +
+	"    <ForexSelling></ForexSelling>\n" +
+	"  </Currency>\n" +
+	"</Tarih_Date>"
+
+func TestCentralBankOfTurkeyDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(centralBankOfTurkeyMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "TRY", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestCentralBankOfTurkeyDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(centralBankOfTurkeyMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731414600), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestCentralBankOfTurkeyDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(centralBankOfTurkeyMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.03125",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "5",
+	})
+}
+
+func TestCentralBankOfTurkeyDataSource_BlankContent(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCentralBankOfTurkeyDataSource_OnlyXMLHeader(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCentralBankOfTurkeyDataSource_EmptyTarihDateContent(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<Tarih_Date Tarih=\"12.11.2024\" Date=\"11/12/2024\" Bulten_No=\"2024/213\">\n"+
+		"</Tarih_Date>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCentralBankOfTurkeyDataSource_InvalidDate(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<Tarih_Date Tarih=\"12.11.2024\" Date=\"2024-11-12\" Bulten_No=\"2024/213\">\n"+
+		"  <Currency CurrencyCode=\"USD\"><Unit>1</Unit><ForexBuying>34.3</ForexBuying></Currency>\n"+
+		"</Tarih_Date>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCentralBankOfTurkeyDataSource_InvalidUnitAndRate(t *testing.T) {
+	dataSource := &CentralBankOfTurkeyDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<Tarih_Date Tarih=\"12.11.2024\" Date=\"11/12/2024\" Bulten_No=\"2024/213\">\n"+
+		"  <Currency CurrencyCode=\"USD\"><Unit>1</Unit><ForexBuying>0</ForexBuying></Currency>\n"+
+		"  <Currency CurrencyCode=\"EUR\"><Unit>0</Unit><ForexBuying>36.5</ForexBuying></Currency>\n"+
+		"  <Currency CurrencyCode=\"GBP\"><Unit>1</Unit><ForexBuying>null</ForexBuying></Currency>\n"+
+		"  <Currency CurrencyCode=\"CHF\"><Unit>null</Unit><ForexBuying>39.0</ForexBuying></Currency>\n"+
+		"</Tarih_Date>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
+}
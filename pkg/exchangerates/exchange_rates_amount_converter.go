@@ -0,0 +1,231 @@
+package exchangerates
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const exchangeRatesSnapshotDateRangeMarginSeconds = 24 * 60 * 60
+
+// netWorthSnapshotExchangeRates represents the exchange rates of one data source which are shared by building the net worth snapshots of all ledgers
+type netWorthSnapshotExchangeRates struct {
+	latestExchangeRates     *models.LatestExchangeRateResponse
+	latestExchangeRatesDate int32
+	snapshots               []*models.ExchangeRateSnapshot
+	historicalExchangeRates *models.HistoricalExchangeRates
+}
+
+// GetTransactionAmountConverter returns a converter which converts the amounts of all accounts in the specified ledger into the target currency (or the default currency of user if it is empty) at the latest or historical exchange rates, and the view-object of the applied exchange rates,
+// the historical exchange rates are only loaded for the transactions between the specified start and end unix time (zero means no limit)
+func (e *ExchangeRatesDataProviderContainer) GetTransactionAmountConverter(c core.Context, uid int64, ledgerId int64, currentConfig *settings.Config, targetCurrency string, useHistoricalExchangeRates bool, startTime int64, endTime int64) (models.TransactionAmountConverter, *models.ExchangeRatesConversionResponse, error) {
+	if e.current == nil {
+		return nil, nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	user, err := services.Users.GetUserById(c, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if targetCurrency == "" {
+		targetCurrency = user.DefaultCurrency
+	}
+
+	accounts, err := services.Accounts.GetAllAccountsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountCurrencies := make(map[int64]string, len(accounts))
+	currencies := make([]string, 0, len(accounts))
+	requiredCurrencies := make(map[string]bool, len(accounts)+2)
+	requiredCurrencies[user.DefaultCurrency] = true
+	requiredCurrencies[targetCurrency] = true
+
+	for i := 0; i < len(accounts); i++ {
+		accountCurrencies[accounts[i].AccountId] = accounts[i].Currency
+
+		if accounts[i].Type != models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			currencies = append(currencies, accounts[i].Currency)
+			requiredCurrencies[accounts[i].Currency] = true
+		}
+	}
+
+	latestExchangeRates, err := e.getLatestExchangeRatesWithUserCustomExchangeRates(c, user, currentConfig, requiredCurrencies)
+
+	if err != nil {
+		if !useHistoricalExchangeRates {
+			return nil, nil, err
+		}
+
+		log.Warnf(c, "[exchange_rates_amount_converter.GetTransactionAmountConverter] failed to get latest exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		latestExchangeRates = nil
+	}
+
+	var snapshots []*models.ExchangeRateSnapshot
+
+	if useHistoricalExchangeRates {
+		startRateDate, endRateDate := getExchangeRatesSnapshotDateRange(startTime, endTime)
+		snapshots, err = services.ExchangeRateSnapshots.GetSnapshotsInDateRange(c, e.getSnapshotUid(uid, currentConfig), currentConfig.ExchangeRatesDataSource, startRateDate, endRateDate)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	latestExchangeRatesUpdateTime := int64(0)
+
+	if latestExchangeRates != nil {
+		latestExchangeRatesUpdateTime = latestExchangeRates.UpdateTime
+	}
+
+	latestExchangeRatesDate := getExchangeRatesUpdateNumericDate(currentConfig.ExchangeRatesDataSource, latestExchangeRatesUpdateTime)
+	historicalExchangeRates := models.NewHistoricalExchangeRates(snapshots, latestExchangeRates, latestExchangeRatesDate)
+	conversionResponse := historicalExchangeRates.ToExchangeRatesConversionResponse(latestExchangeRates, currentConfig.ExchangeRatesDataSource, currencies, targetCurrency, useHistoricalExchangeRates)
+
+	return historicalExchangeRates.ToTransactionAmountConverter(accountCurrencies, targetCurrency), conversionResponse, nil
+}
+
+// GetNetWorthSnapshotAmountConverterGetter returns a getter of the converter which converts the amounts of all accounts into the default currency of user at historical exchange rates when building net worth snapshots,
+// the latest exchange rates and the exchange rate snapshots are loaded only once by the returned getter and reused for all users and ledgers
+func (e *ExchangeRatesDataProviderContainer) GetNetWorthSnapshotAmountConverterGetter(currentConfig *settings.Config) services.NetWorthSnapshotAmountConverterGetter {
+	allSharedExchangeRates := make(map[int64]*netWorthSnapshotExchangeRates)
+
+	return func(c core.Context, uid int64, ledgerId int64) (models.TransactionAmountConverter, error) {
+		if e.current == nil {
+			return nil, errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		user, err := services.Users.GetUserById(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		accounts, err := services.Accounts.GetAllAccountsByUid(c, uid, ledgerId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotUid := e.getSnapshotUid(uid, currentConfig)
+		sharedExchangeRates, exists := allSharedExchangeRates[snapshotUid]
+
+		if !exists {
+			sharedExchangeRates, err = e.loadNetWorthSnapshotExchangeRates(c, snapshotUid, currentConfig)
+
+			if err != nil {
+				return nil, err
+			}
+
+			allSharedExchangeRates[snapshotUid] = sharedExchangeRates
+		}
+
+		historicalExchangeRates := sharedExchangeRates.historicalExchangeRates
+
+		if sharedExchangeRates.latestExchangeRates != nil {
+			latestExchangeRates, err := e.withUserCustomExchangeRates(c, user, currentConfig, sharedExchangeRates.latestExchangeRates)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if latestExchangeRates != sharedExchangeRates.latestExchangeRates {
+				historicalExchangeRates = models.NewHistoricalExchangeRates(sharedExchangeRates.snapshots, latestExchangeRates, sharedExchangeRates.latestExchangeRatesDate)
+			}
+		}
+
+		accountCurrencies := make(map[int64]string, len(accounts))
+
+		for i := 0; i < len(accounts); i++ {
+			accountCurrencies[accounts[i].AccountId] = accounts[i].Currency
+		}
+
+		return historicalExchangeRates.ToTransactionAmountConverter(accountCurrencies, user.DefaultCurrency), nil
+	}
+}
+
+func (e *ExchangeRatesDataProviderContainer) loadNetWorthSnapshotExchangeRates(c core.Context, snapshotUid int64, currentConfig *settings.Config) (*netWorthSnapshotExchangeRates, error) {
+	latestExchangeRates, err := e.GetLatestExchangeRatesOfCurrencies(c, snapshotUid, currentConfig, validators.AllCurrencyNames)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates_amount_converter.loadNetWorthSnapshotExchangeRates] failed to get latest exchange rates for user \"uid:%d\", because %s", snapshotUid, err.Error())
+		latestExchangeRates = nil
+	}
+
+	snapshots, err := services.ExchangeRateSnapshots.GetSnapshotsInDateRange(c, snapshotUid, currentConfig.ExchangeRatesDataSource, 0, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	latestExchangeRatesUpdateTime := int64(0)
+
+	if latestExchangeRates != nil {
+		latestExchangeRatesUpdateTime = latestExchangeRates.UpdateTime
+	}
+
+	latestExchangeRatesDate := getExchangeRatesUpdateNumericDate(currentConfig.ExchangeRatesDataSource, latestExchangeRatesUpdateTime)
+
+	return &netWorthSnapshotExchangeRates{
+		latestExchangeRates:     latestExchangeRates,
+		latestExchangeRatesDate: latestExchangeRatesDate,
+		snapshots:               snapshots,
+		historicalExchangeRates: models.NewHistoricalExchangeRates(snapshots, latestExchangeRates, latestExchangeRatesDate),
+	}, nil
+}
+
+func (e *ExchangeRatesDataProviderContainer) getLatestExchangeRatesWithUserCustomExchangeRates(c core.Context, user *models.User, currentConfig *settings.Config, requiredCurrencies map[string]bool) (*models.LatestExchangeRateResponse, error) {
+	latestExchangeRates, err := e.GetLatestExchangeRatesOfCurrencies(c, user.Uid, currentConfig, requiredCurrencies)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return e.withUserCustomExchangeRates(c, user, currentConfig, latestExchangeRates)
+}
+
+func (e *ExchangeRatesDataProviderContainer) withUserCustomExchangeRates(c core.Context, user *models.User, currentConfig *settings.Config, latestExchangeRates *models.LatestExchangeRateResponse) (*models.LatestExchangeRateResponse, error) {
+	if currentConfig.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
+		return latestExchangeRates, nil
+	}
+
+	customExchangeRates, err := services.UserCustomExchangeRates.GetAllCustomExchangeRatesByUid(c, user.Uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(customExchangeRates) < 1 {
+		return latestExchangeRates, nil
+	}
+
+	return latestExchangeRates.WithUserCustomExchangeRates(customExchangeRates, user.DefaultCurrency, userDataSourceType), nil
+}
+
+// getExchangeRatesSnapshotDateRange returns the numeric dates (yyyyMMdd) of the exchange rates snapshots which may be used by the transactions between the specified unix time,
+// the range is extended by one day on both sides because the dates of transactions can be in any timezone
+func getExchangeRatesSnapshotDateRange(startTime int64, endTime int64) (int32, int32) {
+	startRateDate := int32(0)
+	endRateDate := int32(0)
+
+	if startTime > 0 {
+		startRateDate = utils.FormatUnixTimeToNumericYearMonthDay(startTime-exchangeRatesSnapshotDateRangeMarginSeconds, time.UTC)
+	}
+
+	if endTime > 0 {
+		endRateDate = utils.FormatUnixTimeToNumericYearMonthDay(endTime+exchangeRatesSnapshotDateRangeMarginSeconds, time.UTC)
+	}
+
+	return startRateDate, endRateDate
+}
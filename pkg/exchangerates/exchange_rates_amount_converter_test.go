@@ -0,0 +1,18 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExchangeRatesSnapshotDateRange(t *testing.T) {
+	// 2024-01-05 00:00:00 ~ 2024-01-31 23:59:59 in UTC
+	startRateDate, endRateDate := getExchangeRatesSnapshotDateRange(1704412800, 1706745599)
+	assert.Equal(t, int32(20240104), startRateDate)
+	assert.Equal(t, int32(20240201), endRateDate)
+
+	startRateDate, endRateDate = getExchangeRatesSnapshotDateRange(0, 0)
+	assert.Equal(t, int32(0), startRateDate)
+	assert.Equal(t, int32(0), endRateDate)
+}
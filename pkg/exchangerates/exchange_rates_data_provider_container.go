@@ -1,15 +1,24 @@
 package exchangerates
 
 import (
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
 )
 
-// ExchangeRatesDataProviderContainer contains the current exchange rates data provider
+// ExchangeRatesDataProviderContainer contains the current exchange rates data provider and the fallback ones
 type ExchangeRatesDataProviderContainer struct {
-	current ExchangeRatesDataProvider
+	current   ExchangeRatesDataProvider
+	fallbacks []ExchangeRatesDataProvider
 }
 
 // Initialize a exchange rates data provider container singleton instance
@@ -17,66 +26,314 @@ var (
 	Container = &ExchangeRatesDataProviderContainer{}
 )
 
-// InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
+// InitializeExchangeRatesDataSource initializes the current exchange rates data source and the fallback ones according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.DanmarksNationalbankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfGeorgiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfHungaryDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfIsraelDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfMyanmarDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NorgesBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfRomaniaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfRussiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.SwissNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfUkraineDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfUzbekistanDataSource {
-		Container.current = newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
-		Container.current = newUserCustomExchangeRatesDataProvider()
-		return nil
-	}
-
-	return errs.ErrInvalidExchangeRatesDataSource
+	current, err := createExchangeRatesDataProvider(config, config.ExchangeRatesDataSource)
+
+	if err != nil {
+		return err
+	}
+
+	fallbacks := make([]ExchangeRatesDataProvider, len(config.ExchangeRatesFallbackDataSources))
+
+	for i := 0; i < len(config.ExchangeRatesFallbackDataSources); i++ {
+		fallbacks[i], err = createExchangeRatesDataProvider(config, config.ExchangeRatesFallbackDataSources[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	Container.current = current
+	Container.fallbacks = fallbacks
+
+	return nil
 }
 
-// GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source
+// GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source,
+// and fills the default currency of user and the currencies of user accounts which are still missing from the fallback data sources in order
 func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
-	if Container.current == nil {
+	if e.current == nil {
 		return nil, errs.ErrInvalidExchangeRatesDataSource
 	}
 
-	return e.current.GetLatestExchangeRates(c, uid, currentConfig)
+	return e.GetLatestExchangeRatesOfCurrencies(c, uid, currentConfig, e.getUserRequiredCurrencies(c, uid))
+}
+
+// GetLatestExchangeRatesOfCurrencies returns the latest exchange rates data from the current exchange rates data source,
+// and fills the specified required currencies which are still missing from the fallback data sources in order
+func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRatesOfCurrencies(c core.Context, uid int64, currentConfig *settings.Config, requiredCurrencies map[string]bool) (*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if len(e.fallbacks) < 1 && len(currentConfig.ExchangeRatesDataSourceMaxAgeHours) < 1 {
+		return e.current.GetLatestExchangeRates(c, uid, currentConfig)
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.fallbacks)+1)
+	primaryExchangeRateResp, firstErr := e.getValidLatestExchangeRates(c, uid, e.current, currentConfig.ExchangeRatesDataSource, currentConfig)
+
+	if primaryExchangeRateResp != nil {
+		exchangeRateResps = append(exchangeRateResps, primaryExchangeRateResp)
+	}
+
+	missingCurrencies := getMissingCurrencies(exchangeRateResps, requiredCurrencies)
+
+	if len(missingCurrencies) > 0 && len(e.fallbacks) > 0 {
+		fallbackExchangeRateResps, fallbackErrs := e.getAllFallbackLatestExchangeRates(c, uid, currentConfig)
+
+		for i := 0; i < len(fallbackExchangeRateResps) && len(missingCurrencies) > 0; i++ {
+			if fallbackErrs[i] != nil && firstErr == nil {
+				firstErr = fallbackErrs[i]
+			}
+
+			if fallbackExchangeRateResps[i] == nil || !containsAnyCurrency(fallbackExchangeRateResps[i], missingCurrencies) {
+				continue
+			}
+
+			exchangeRateResps = append(exchangeRateResps, fallbackExchangeRateResps[i])
+			missingCurrencies = getMissingCurrencies(exchangeRateResps, requiredCurrencies)
+		}
+	}
+
+	if len(exchangeRateResps) < 1 {
+		return nil, firstErr
+	}
+
+	return mergeLatestExchangeRateResponses(exchangeRateResps), nil
+}
+
+// getAllFallbackLatestExchangeRates requests all the fallback exchange rates data sources concurrently, and returns the responses and errors in the order of the fallback data sources
+func (e *ExchangeRatesDataProviderContainer) getAllFallbackLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) ([]*models.LatestExchangeRateResponse, []error) {
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, len(e.fallbacks))
+	allErrs := make([]error, len(e.fallbacks))
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < len(e.fallbacks) && i < len(currentConfig.ExchangeRatesFallbackDataSources); i++ {
+		waitGroup.Add(1)
+
+		go func(index int) {
+			defer waitGroup.Done()
+			exchangeRateResps[index], allErrs[index] = e.getValidLatestExchangeRates(c, uid, e.fallbacks[index], currentConfig.ExchangeRatesFallbackDataSources[index], currentConfig)
+		}(i)
+	}
+
+	waitGroup.Wait()
+
+	return exchangeRateResps, allErrs
+}
+
+func (e *ExchangeRatesDataProviderContainer) getValidLatestExchangeRates(c core.Context, uid int64, provider ExchangeRatesDataProvider, dataSource string, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	exchangeRateResp, err := provider.GetLatestExchangeRates(c, uid, currentConfig)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates_data_provider_container.getValidLatestExchangeRates] failed to get latest exchange rates from \"%s\" for user \"uid:%d\", because %s", dataSource, uid, err.Error())
+		return nil, err
+	}
+
+	if maxAgeHours, exists := currentConfig.ExchangeRatesDataSourceMaxAgeHours[dataSource]; exists && maxAgeHours > 0 && time.Now().Unix()-exchangeRateResp.UpdateTime > int64(maxAgeHours)*3600 {
+		log.Warnf(c, "[exchange_rates_data_provider_container.getValidLatestExchangeRates] latest exchange rates from \"%s\" for user \"uid:%d\" are stale, update time is %d", dataSource, uid, exchangeRateResp.UpdateTime)
+		return nil, errs.ErrExchangeRatesDataIsStale
+	}
+
+	return exchangeRateResp, nil
+}
+
+// getUserRequiredCurrencies returns the default currency of user and the currencies of all user accounts, or all the supported currencies if they cannot be determined
+func (e *ExchangeRatesDataProviderContainer) getUserRequiredCurrencies(c core.Context, uid int64) map[string]bool {
+	if uid <= 0 {
+		return validators.AllCurrencyNames
+	}
+
+	user, err := services.Users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates_data_provider_container.getUserRequiredCurrencies] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return validators.AllCurrencyNames
+	}
+
+	accountCurrencies, err := services.Accounts.GetAllAccountCurrenciesByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates_data_provider_container.getUserRequiredCurrencies] failed to get account currencies for user \"uid:%d\", because %s", uid, err.Error())
+		return validators.AllCurrencyNames
+	}
+
+	requiredCurrencies := make(map[string]bool, len(accountCurrencies)+1)
+	requiredCurrencies[user.DefaultCurrency] = true
+
+	for i := 0; i < len(accountCurrencies); i++ {
+		requiredCurrencies[accountCurrencies[i]] = true
+	}
+
+	return requiredCurrencies
+}
+
+// getMissingCurrencies returns the supported currencies of the required currencies which are not in the merged result of the specified exchange rates data
+func getMissingCurrencies(exchangeRateResps []*models.LatestExchangeRateResponse, requiredCurrencies map[string]bool) map[string]bool {
+	missingCurrencies := make(map[string]bool, len(requiredCurrencies))
+
+	for currency := range requiredCurrencies {
+		if validators.AllCurrencyNames[currency] {
+			missingCurrencies[currency] = true
+		}
+	}
+
+	if len(exchangeRateResps) < 1 {
+		return missingCurrencies
+	}
+
+	mergedExchangeRateResp := mergeLatestExchangeRateResponses(exchangeRateResps)
+
+	for i := 0; i < len(mergedExchangeRateResp.ExchangeRates); i++ {
+		delete(missingCurrencies, mergedExchangeRateResp.ExchangeRates[i].Currency)
+	}
+
+	return missingCurrencies
+}
+
+func containsAnyCurrency(exchangeRateResp *models.LatestExchangeRateResponse, currencies map[string]bool) bool {
+	if currencies[exchangeRateResp.BaseCurrency] {
+		return true
+	}
+
+	for i := 0; i < len(exchangeRateResp.ExchangeRates); i++ {
+		if currencies[exchangeRateResp.ExchangeRates[i].Currency] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mergeLatestExchangeRateResponses(exchangeRateResps []*models.LatestExchangeRateResponse) *models.LatestExchangeRateResponse {
+	primaryExchangeRateResp := exchangeRateResps[0]
+	baseCurrency := primaryExchangeRateResp.BaseCurrency
+	allExchangeRatesMap := make(map[string]*models.LatestExchangeRate)
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateResp := exchangeRateResps[i]
+		baseCurrencyRate := float64(1)
+
+		if exchangeRateResp.BaseCurrency != baseCurrency {
+			baseCurrencyRate = 0
+
+			for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
+				if exchangeRateResp.ExchangeRates[j].Currency == baseCurrency {
+					baseCurrencyRate, _ = utils.StringToFloat64(exchangeRateResp.ExchangeRates[j].Rate)
+					break
+				}
+			}
+
+			// the rates of this data source cannot be converted to the base currency of the primary one
+			if baseCurrencyRate <= 0 {
+				continue
+			}
+		}
+
+		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
+			exchangeRate := exchangeRateResp.ExchangeRates[j]
+
+			if _, exists := allExchangeRatesMap[exchangeRate.Currency]; exists || exchangeRate.Currency == baseCurrency {
+				continue
+			}
+
+			rate := exchangeRate.Rate
+
+			if exchangeRateResp.BaseCurrency != baseCurrency {
+				originalRate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+				if err != nil || originalRate <= 0 {
+					continue
+				}
+
+				rate = utils.Float64ToString(originalRate / baseCurrencyRate)
+			}
+
+			allExchangeRatesMap[exchangeRate.Currency] = &models.LatestExchangeRate{
+				Currency:   exchangeRate.Currency,
+				Rate:       rate,
+				DataSource: exchangeRateResp.DataSource,
+			}
+		}
+
+		if _, exists := allExchangeRatesMap[exchangeRateResp.BaseCurrency]; !exists && exchangeRateResp.BaseCurrency != baseCurrency {
+			allExchangeRatesMap[exchangeRateResp.BaseCurrency] = &models.LatestExchangeRate{
+				Currency:   exchangeRateResp.BaseCurrency,
+				Rate:       utils.Float64ToString(1 / baseCurrencyRate),
+				DataSource: exchangeRateResp.DataSource,
+			}
+		}
+	}
+
+	allExchangeRatesMap[baseCurrency] = &models.LatestExchangeRate{
+		Currency:   baseCurrency,
+		Rate:       "1",
+		DataSource: primaryExchangeRateResp.DataSource,
+	}
+
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+
+	for _, exchangeRate := range allExchangeRatesMap {
+		allExchangeRates = append(allExchangeRates, exchangeRate)
+	}
+
+	sort.Sort(allExchangeRates)
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:    primaryExchangeRateResp.DataSource,
+		ReferenceUrl:  primaryExchangeRateResp.ReferenceUrl,
+		UpdateTime:    primaryExchangeRateResp.UpdateTime,
+		BaseCurrency:  baseCurrency,
+		ExchangeRates: allExchangeRates,
+	}
+}
+
+func createExchangeRatesDataProvider(config *settings.Config, dataSource string) (ExchangeRatesDataProvider, error) {
+	if dataSource == settings.ReserveBankOfAustraliaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &ReserveBankOfAustraliaDataSource{}), nil
+	} else if dataSource == settings.BankOfCanadaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfCanadaDataSource{}), nil
+	} else if dataSource == settings.PeoplesBankOfChinaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &PeoplesBankOfChinaDataSource{}), nil
+	} else if dataSource == settings.CzechNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CzechNationalBankDataSource{}), nil
+	} else if dataSource == settings.DanmarksNationalbankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &DanmarksNationalbankDataSource{}), nil
+	} else if dataSource == settings.EuroCentralBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &EuroCentralBankDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfGeorgiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfGeorgiaDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfHungaryDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfHungaryDataSource{}), nil
+	} else if dataSource == settings.BankOfIsraelDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfIsraelDataSource{}), nil
+	} else if dataSource == settings.BankOfJapanDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfJapanDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfMyanmarDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfMyanmarDataSource{}), nil
+	} else if dataSource == settings.NorgesBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NorgesBankDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfPolandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfPolandDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfRomaniaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfRomaniaDataSource{}), nil
+	} else if dataSource == settings.BankOfRussiaDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfRussiaDataSource{}), nil
+	} else if dataSource == settings.SwissNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &SwissNationalBankDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfTurkeyDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfTurkeyDataSource{}), nil
+	} else if dataSource == settings.NationalBankOfUkraineDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &NationalBankOfUkraineDataSource{}), nil
+	} else if dataSource == settings.BankOfEnglandDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &BankOfEnglandDataSource{}), nil
+	} else if dataSource == settings.CentralBankOfUzbekistanDataSource {
+		return newCommonHttpExchangeRatesDataProvider(config, &CentralBankOfUzbekistanDataSource{}), nil
+	} else if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return newUserCustomExchangeRatesDataProvider(), nil
+	}
+
+	return nil, errs.ErrInvalidExchangeRatesDataSource
 }
@@ -0,0 +1,315 @@
+package exchangerates
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+type mockExchangeRatesDataProvider struct {
+	response    *models.LatestExchangeRateResponse
+	err         error
+	calledCount atomic.Int32
+}
+
+func (p *mockExchangeRatesDataProvider) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	p.calledCount.Add(1)
+	return p.response, p.err
+}
+
+func TestMergeLatestExchangeRateResponses_FillMissingCurrenciesFromFallback(t *testing.T) {
+	exchangeRateResponse := mergeLatestExchangeRateResponses([]*models.LatestExchangeRateResponse{
+		{
+			DataSource:   "Primary",
+			UpdateTime:   1000,
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "EUR", Rate: "1"},
+				{Currency: "USD", Rate: "1.25"},
+			},
+		},
+		{
+			DataSource:   "Fallback",
+			UpdateTime:   2000,
+			BaseCurrency: "USD",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "1"},
+				{Currency: "EUR", Rate: "0.5"},
+				{Currency: "JPY", Rate: "150"},
+			},
+		},
+	})
+
+	assert.Equal(t, "Primary", exchangeRateResponse.DataSource)
+	assert.Equal(t, int64(1000), exchangeRateResponse.UpdateTime)
+	assert.Equal(t, "EUR", exchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 3, len(exchangeRateResponse.ExchangeRates))
+
+	assert.Equal(t, "EUR", exchangeRateResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "1", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, "Primary", exchangeRateResponse.ExchangeRates[0].DataSource)
+
+	assert.Equal(t, "JPY", exchangeRateResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "300", exchangeRateResponse.ExchangeRates[1].Rate)
+	assert.Equal(t, "Fallback", exchangeRateResponse.ExchangeRates[1].DataSource)
+
+	assert.Equal(t, "USD", exchangeRateResponse.ExchangeRates[2].Currency)
+	assert.Equal(t, "1.25", exchangeRateResponse.ExchangeRates[2].Rate)
+	assert.Equal(t, "Primary", exchangeRateResponse.ExchangeRates[2].DataSource)
+}
+
+func TestMergeLatestExchangeRateResponses_SkipFallbackWithoutBaseCurrency(t *testing.T) {
+	exchangeRateResponse := mergeLatestExchangeRateResponses([]*models.LatestExchangeRateResponse{
+		{
+			DataSource:   "Primary",
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "1.25"},
+			},
+		},
+		{
+			DataSource:   "Fallback",
+			BaseCurrency: "USD",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "JPY", Rate: "150"},
+			},
+		},
+	})
+
+	assert.Equal(t, 2, len(exchangeRateResponse.ExchangeRates))
+	assert.Equal(t, "EUR", exchangeRateResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "USD", exchangeRateResponse.ExchangeRates[1].Currency)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_PrimaryFailed(t *testing.T) {
+	container := &ExchangeRatesDataProviderContainer{
+		current: &mockExchangeRatesDataProvider{
+			err: errs.ErrFailedToRequestRemoteApi,
+		},
+		fallbacks: []ExchangeRatesDataProvider{
+			&mockExchangeRatesDataProvider{
+				response: &models.LatestExchangeRateResponse{
+					DataSource:   "Fallback",
+					UpdateTime:   time.Now().Unix(),
+					BaseCurrency: "USD",
+					ExchangeRates: models.LatestExchangeRateSlice{
+						{Currency: "EUR", Rate: "0.5"},
+					},
+				},
+			},
+		},
+	}
+
+	config := &settings.Config{
+		ExchangeRatesDataSource:          settings.EuroCentralBankDataSource,
+		ExchangeRatesFallbackDataSources: []string{settings.BankOfCanadaDataSource},
+	}
+
+	exchangeRateResponse, err := executeContainerGetLatestExchangeRates(container, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fallback", exchangeRateResponse.DataSource)
+	assert.Equal(t, "USD", exchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 2, len(exchangeRateResponse.ExchangeRates))
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_StaleData(t *testing.T) {
+	container := &ExchangeRatesDataProviderContainer{
+		current: &mockExchangeRatesDataProvider{
+			response: &models.LatestExchangeRateResponse{
+				DataSource:   "Primary",
+				UpdateTime:   time.Now().Unix() - 4*3600,
+				BaseCurrency: "EUR",
+			},
+		},
+		fallbacks: []ExchangeRatesDataProvider{
+			&mockExchangeRatesDataProvider{
+				err: errors.New("test error"),
+			},
+		},
+	}
+
+	config := &settings.Config{
+		ExchangeRatesDataSource:          settings.EuroCentralBankDataSource,
+		ExchangeRatesFallbackDataSources: []string{settings.BankOfCanadaDataSource},
+		ExchangeRatesDataSourceMaxAgeHours: map[string]uint32{
+			settings.EuroCentralBankDataSource: 3,
+		},
+	}
+
+	_, err := executeContainerGetLatestExchangeRates(container, config)
+	assert.Equal(t, errs.ErrExchangeRatesDataIsStale, err)
+
+	config.ExchangeRatesDataSourceMaxAgeHours[settings.EuroCentralBankDataSource] = 5
+
+	exchangeRateResponse, err := executeContainerGetLatestExchangeRates(container, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "Primary", exchangeRateResponse.DataSource)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_NoMissingCurrencies(t *testing.T) {
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(validators.AllCurrencyNames))
+
+	for currency := range validators.AllCurrencyNames {
+		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{Currency: currency, Rate: "2"})
+	}
+
+	fallback := &mockExchangeRatesDataProvider{
+		response: &models.LatestExchangeRateResponse{
+			DataSource:   "Fallback",
+			BaseCurrency: "USD",
+		},
+	}
+
+	container := &ExchangeRatesDataProviderContainer{
+		current: &mockExchangeRatesDataProvider{
+			response: &models.LatestExchangeRateResponse{
+				DataSource:    "Primary",
+				UpdateTime:    time.Now().Unix(),
+				BaseCurrency:  "EUR",
+				ExchangeRates: allExchangeRates,
+			},
+		},
+		fallbacks: []ExchangeRatesDataProvider{fallback},
+	}
+
+	config := &settings.Config{
+		ExchangeRatesDataSource:          settings.EuroCentralBankDataSource,
+		ExchangeRatesFallbackDataSources: []string{settings.BankOfCanadaDataSource},
+	}
+
+	exchangeRateResponse, err := executeContainerGetLatestExchangeRates(container, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "Primary", exchangeRateResponse.DataSource)
+	assert.Equal(t, len(validators.AllCurrencyNames), len(exchangeRateResponse.ExchangeRates))
+	assert.Equal(t, int32(0), fallback.calledCount.Load())
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRates_SkipFallbackWithoutMissingCurrencies(t *testing.T) {
+	fallback1 := &mockExchangeRatesDataProvider{
+		response: &models.LatestExchangeRateResponse{
+			DataSource:   "Fallback1",
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "2"},
+			},
+		},
+	}
+
+	fallback2 := &mockExchangeRatesDataProvider{
+		response: &models.LatestExchangeRateResponse{
+			DataSource:   "Fallback2",
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "USD", Rate: "3"},
+				{Currency: "JPY", Rate: "150"},
+			},
+		},
+	}
+
+	container := &ExchangeRatesDataProviderContainer{
+		current: &mockExchangeRatesDataProvider{
+			response: &models.LatestExchangeRateResponse{
+				DataSource:   "Primary",
+				UpdateTime:   time.Now().Unix(),
+				BaseCurrency: "EUR",
+				ExchangeRates: models.LatestExchangeRateSlice{
+					{Currency: "USD", Rate: "1.25"},
+				},
+			},
+		},
+		fallbacks: []ExchangeRatesDataProvider{fallback1, fallback2},
+	}
+
+	config := &settings.Config{
+		ExchangeRatesDataSource:          settings.EuroCentralBankDataSource,
+		ExchangeRatesFallbackDataSources: []string{settings.BankOfCanadaDataSource, settings.BankOfEnglandDataSource},
+	}
+
+	exchangeRateResponse, err := executeContainerGetLatestExchangeRates(container, config)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), fallback1.calledCount.Load())
+	assert.Equal(t, int32(1), fallback2.calledCount.Load())
+	assert.Equal(t, 3, len(exchangeRateResponse.ExchangeRates))
+
+	assert.Equal(t, "JPY", exchangeRateResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "150", exchangeRateResponse.ExchangeRates[1].Rate)
+	assert.Equal(t, "Fallback2", exchangeRateResponse.ExchangeRates[1].DataSource)
+
+	assert.Equal(t, "USD", exchangeRateResponse.ExchangeRates[2].Currency)
+	assert.Equal(t, "1.25", exchangeRateResponse.ExchangeRates[2].Rate)
+	assert.Equal(t, "Primary", exchangeRateResponse.ExchangeRates[2].DataSource)
+}
+
+func TestExchangeRatesDataProviderContainerGetLatestExchangeRatesOfCurrencies_RequiredCurrenciesCovered(t *testing.T) {
+	fallback := &mockExchangeRatesDataProvider{
+		response: &models.LatestExchangeRateResponse{
+			DataSource:   "Fallback",
+			BaseCurrency: "EUR",
+			ExchangeRates: models.LatestExchangeRateSlice{
+				{Currency: "JPY", Rate: "150"},
+			},
+		},
+	}
+
+	container := &ExchangeRatesDataProviderContainer{
+		current: &mockExchangeRatesDataProvider{
+			response: &models.LatestExchangeRateResponse{
+				DataSource:   "Primary",
+				UpdateTime:   time.Now().Unix(),
+				BaseCurrency: "EUR",
+				ExchangeRates: models.LatestExchangeRateSlice{
+					{Currency: "USD", Rate: "1.25"},
+				},
+			},
+		},
+		fallbacks: []ExchangeRatesDataProvider{fallback},
+	}
+
+	config := &settings.Config{
+		ExchangeRatesDataSource:          settings.EuroCentralBankDataSource,
+		ExchangeRatesFallbackDataSources: []string{settings.BankOfCanadaDataSource},
+	}
+
+	exchangeRateResponse, err := container.GetLatestExchangeRatesOfCurrencies(core.NewNullContext(), 0, config, map[string]bool{"EUR": true, "USD": true})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), fallback.calledCount.Load())
+	assert.Equal(t, "Primary", exchangeRateResponse.DataSource)
+	assert.Equal(t, 2, len(exchangeRateResponse.ExchangeRates))
+
+	exchangeRateResponse, err = container.GetLatestExchangeRatesOfCurrencies(core.NewNullContext(), 0, config, map[string]bool{"EUR": true, "JPY": true})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), fallback.calledCount.Load())
+	assert.Equal(t, 3, len(exchangeRateResponse.ExchangeRates))
+}
+
+func executeContainerGetLatestExchangeRates(container *ExchangeRatesDataProviderContainer, config *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	context := &core.WebContext{
+		Context: ginContext,
+	}
+
+	return container.GetLatestExchangeRates(context, context.GetCurrentUid(), config)
+}
+
+func TestGetExchangeRatesUpdateNumericDate(t *testing.T) {
+	// 2024-01-05 00:00:00 in Asia/Samarkand, which is 2024-01-04 in UTC
+	assert.Equal(t, int32(20240105), getExchangeRatesUpdateNumericDate(settings.CentralBankOfUzbekistanDataSource, 1704394800))
+	assert.Equal(t, int32(20240104), getExchangeRatesUpdateNumericDate(settings.NationalBankOfGeorgiaDataSource, 1704394800))
+
+	// 2024-01-05 16:00:00 in Europe/Berlin
+	assert.Equal(t, int32(20240105), getExchangeRatesUpdateNumericDate(settings.EuroCentralBankDataSource, 1704466800))
+
+	todayDate := getExchangeRatesUpdateNumericDate(settings.UserCustomExchangeRatesDataSource, 0)
+	assert.True(t, todayDate >= 20240101)
+}
@@ -0,0 +1,114 @@
+package exchangerates
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+// exchangeRatesDataSourceUpdateDateTimezones contains the timezones which the data sources publish their exchange rates in,
+// the update time of the data sources which are not listed here is parsed in UTC
+var exchangeRatesDataSourceUpdateDateTimezones = map[string]string{
+	settings.ReserveBankOfAustraliaDataSource:  reserveBankOfAustraliaDataUpdateDateTimezone,
+	settings.BankOfCanadaDataSource:            bankOfCanadaDataUpdateDateTimezone,
+	settings.PeoplesBankOfChinaDataSource:      peoplesBankOfChinaDataUpdateDateTimezone,
+	settings.CzechNationalBankDataSource:       czechNationalBankDataUpdateDateTimezone,
+	settings.DanmarksNationalbankDataSource:    danmarksNationalbankDataUpdateDateTimezone,
+	settings.EuroCentralBankDataSource:         euroCentralBankDataUpdateDateTimezone,
+	settings.CentralBankOfHungaryDataSource:    centralBankOfHungaryUpdateDateTimezone,
+	settings.BankOfJapanDataSource:             bankOfJapanDataUpdateDateTimezone,
+	settings.NorgesBankDataSource:              norgesBankUpdateDateTimezone,
+	settings.NationalBankOfPolandDataSource:    nationalBankOfPolandDataUpdateDateTimezone,
+	settings.NationalBankOfRomaniaDataSource:   nationalBankOfRomaniaUpdateDateTimezone,
+	settings.BankOfRussiaDataSource:            bankOfRussiaUpdateDateTimezone,
+	settings.CentralBankOfTurkeyDataSource:     centralBankOfTurkeyDataUpdateDateTimezone,
+	settings.BankOfEnglandDataSource:           bankOfEnglandDataUpdateDateTimezone,
+	settings.CentralBankOfUzbekistanDataSource: centralBankOfUzbekistanUpdateDateTimezone,
+}
+
+// SaveLatestExchangeRatesSnapshot saves the latest exchange rates of the current exchange rates data source as the snapshot of its update date
+func (e *ExchangeRatesDataProviderContainer) SaveLatestExchangeRatesSnapshot(c core.Context, currentConfig *settings.Config) error {
+	if e.current == nil {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if currentConfig.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource {
+		return e.saveLatestExchangeRatesSnapshot(c, 0, currentConfig)
+	}
+
+	uids, err := services.UserCustomExchangeRates.GetAllUidsOfCustomExchangeRates(c)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_snapshot.SaveLatestExchangeRatesSnapshot] failed to get all users who have user custom exchange rates, because %s", err.Error())
+		return err
+	}
+
+	for i := 0; i < len(uids); i++ {
+		err = e.saveLatestExchangeRatesSnapshot(c, uids[i], currentConfig)
+
+		if err != nil {
+			log.Warnf(c, "[exchange_rates_snapshot.SaveLatestExchangeRatesSnapshot] failed to save exchange rates snapshot for user \"uid:%d\", because %s", uids[i], err.Error())
+		}
+	}
+
+	return nil
+}
+
+// GetExchangeRatesAtDate returns the exchange rates in effect on the specified numeric date (yyyyMMdd) of the current exchange rates data source
+func (e *ExchangeRatesDataProviderContainer) GetExchangeRatesAtDate(c core.Context, uid int64, yearMonthDay int32, currentConfig *settings.Config) (*models.ExchangeRatesAtDateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	snapshots, err := services.ExchangeRateSnapshots.GetSnapshotsAtDate(c, e.getSnapshotUid(uid, currentConfig), currentConfig.ExchangeRatesDataSource, yearMonthDay)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToExchangeRatesAtDateResponse(snapshots), nil
+}
+
+func (e *ExchangeRatesDataProviderContainer) saveLatestExchangeRatesSnapshot(c core.Context, uid int64, currentConfig *settings.Config) error {
+	latestExchangeRates, err := e.GetLatestExchangeRatesOfCurrencies(c, uid, currentConfig, validators.AllCurrencyNames)
+
+	if err != nil {
+		return err
+	}
+
+	rateDate := getExchangeRatesUpdateNumericDate(currentConfig.ExchangeRatesDataSource, latestExchangeRates.UpdateTime)
+	snapshots := models.CreateExchangeRateSnapshots(uid, currentConfig.ExchangeRatesDataSource, rateDate, latestExchangeRates)
+
+	return services.ExchangeRateSnapshots.SaveSnapshots(c, uid, currentConfig.ExchangeRatesDataSource, rateDate, snapshots)
+}
+
+func (e *ExchangeRatesDataProviderContainer) getSnapshotUid(uid int64, currentConfig *settings.Config) int64 {
+	if currentConfig.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
+		return uid
+	}
+
+	return 0
+}
+
+func getExchangeRatesUpdateNumericDate(dataSource string, updateTime int64) int32 {
+	if updateTime < 1 {
+		updateTime = time.Now().Unix()
+	}
+
+	location := time.UTC
+
+	if timezoneName, exists := exchangeRatesDataSourceUpdateDateTimezones[dataSource]; exists {
+		if timezone, err := time.LoadLocation(timezoneName); err == nil {
+			location = timezone
+		}
+	}
+
+	return utils.FormatUnixTimeToNumericYearMonthDay(updateTime, location)
+}
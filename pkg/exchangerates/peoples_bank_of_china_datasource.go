@@ -0,0 +1,193 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const peoplesBankOfChinaExchangeRateUrl = "https://www.chinamoney.com.cn/r/cms/www/chinamoney/data/fx/ccpr.json"
+const peoplesBankOfChinaExchangeRateReferenceUrl = "https://www.chinamoney.com.cn/english/bmkcpr/"
+const peoplesBankOfChinaDataSource = "中国人民银行"
+const peoplesBankOfChinaBaseCurrency = "CNY"
+
+const peoplesBankOfChinaDataUpdateDateFormat = "2006-01-02 15:04"
+const peoplesBankOfChinaDataUpdateDateTimezone = "Asia/Shanghai"
+
+// PeoplesBankOfChinaDataSource defines the structure of exchange rates data source of the People's Bank of China
+type PeoplesBankOfChinaDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// PeoplesBankOfChinaExchangeRateData represents the whole data from the People's Bank of China
+type PeoplesBankOfChinaExchangeRateData struct {
+	Data    *PeoplesBankOfChinaExchangeRateDataInfo `json:"data"`
+	Records []*PeoplesBankOfChinaExchangeRate       `json:"records"`
+}
+
+// PeoplesBankOfChinaExchangeRateDataInfo represents the data info from the People's Bank of China
+type PeoplesBankOfChinaExchangeRateDataInfo struct {
+	LastDate string `json:"lastDate"`
+}
+
+// PeoplesBankOfChinaExchangeRate represents the central parity rate of one currency pair from the People's Bank of China
+type PeoplesBankOfChinaExchangeRate struct {
+	CurrencyPair string `json:"vrtEName"`
+	Price        string `json:"price"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from the People's Bank of China
+func (e *PeoplesBankOfChinaExchangeRateData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if e.Data == nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] data info does not exist")
+		return nil
+	}
+
+	if len(e.Records) < 1 {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] records is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(e.Records))
+
+	for i := 0; i < len(e.Records); i++ {
+		finalExchangeRate := e.Records[i].ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		exchangeRates = append(exchangeRates, finalExchangeRate)
+	}
+
+	timezone, err := time.LoadLocation(peoplesBankOfChinaDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", peoplesBankOfChinaDataUpdateDateTimezone)
+		return nil
+	}
+
+	updateTime, err := time.ParseInLocation(peoplesBankOfChinaDataUpdateDateFormat, e.Data.LastDate, timezone)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRateResponse] failed to parse update date, datetime is %s", e.Data.LastDate)
+		return nil
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    peoplesBankOfChinaDataSource,
+		ReferenceUrl:  peoplesBankOfChinaExchangeRateReferenceUrl,
+		UpdateTime:    updateTime.Unix(),
+		BaseCurrency:  peoplesBankOfChinaBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from the People's Bank of China
+func (e *PeoplesBankOfChinaExchangeRate) ToLatestExchangeRate(c core.Context) *models.LatestExchangeRate {
+	currencies := strings.Split(e.CurrencyPair, "/")
+
+	if len(currencies) != 2 {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] currency pair is invalid, currency pair is %s", e.CurrencyPair)
+		return nil
+	}
+
+	rate, err := utils.StringToFloat64(e.Price)
+
+	if err != nil {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] failed to parse rate, currency pair is %s, rate is %s", e.CurrencyPair, e.Price)
+		return nil
+	}
+
+	if rate <= 0 {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] rate is invalid, currency pair is %s, rate is %s", e.CurrencyPair, e.Price)
+		return nil
+	}
+
+	var currency string
+	var finalRate float64
+
+	if currencies[0] == peoplesBankOfChinaBaseCurrency {
+		// e.g. "CNY/MYR", the price is the amount of foreign currency per one yuan
+		currency = currencies[1]
+		finalRate = rate
+	} else if currencies[1] == peoplesBankOfChinaBaseCurrency {
+		// e.g. "USD/CNY" or "100JPY/CNY", the price is the amount of yuan per unit of foreign currency
+		if len(currencies[0]) < 3 {
+			log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] currency pair is invalid, currency pair is %s", e.CurrencyPair)
+			return nil
+		}
+
+		currency = currencies[0][len(currencies[0])-3:]
+		unit := float64(1)
+
+		if len(currencies[0]) > 3 {
+			unit, err = utils.StringToFloat64(currencies[0][:len(currencies[0])-3])
+
+			if err != nil || unit <= 0 {
+				log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] failed to parse unit, currency pair is %s", e.CurrencyPair)
+				return nil
+			}
+		}
+
+		finalRate = unit / rate
+	} else {
+		log.Warnf(c, "[peoples_bank_of_china_datasource.ToLatestExchangeRate] currency pair does not contain base currency, currency pair is %s", e.CurrencyPair)
+		return nil
+	}
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil
+	}
+
+	if math.IsInf(finalRate, 0) {
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: currency,
+		Rate:     utils.Float64ToString(finalRate),
+	}
+}
+
+// BuildRequests returns the People's Bank of China exchange rates http requests
+func (e *PeoplesBankOfChinaDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", peoplesBankOfChinaExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the People's Bank of China data source raw response
+func (e *PeoplesBankOfChinaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	peoplesBankOfChinaData := &PeoplesBankOfChinaExchangeRateData{}
+	err := json.Unmarshal(content, peoplesBankOfChinaData)
+
+	if err != nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.Parse] failed to parse json data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := peoplesBankOfChinaData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[peoples_bank_of_china_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,117 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const peoplesBankOfChinaMinimumRequiredContent = "{\n" +
+	"  \"head\": {\"rep_code\": \"200\"},\n" +
+	"  \"data\": {\"lastDate\": \"2024-11-12 9:15\"},\n" +
+	"  \"records\": [\n" +
+	"    {\"vrtCode\": \"USD/CNY\", \"vrtEName\": \"USD/CNY\", \"price\": \"8.0000\"},\n" +
+	"    {\"vrtCode\": \"100JPY/CNY\", \"vrtEName\": \"100JPY/CNY\", \"price\": \"5.0000\"},\n" +
+	"    {\"vrtCode\": \"CNY/MYR\", \"vrtEName\": \"CNY/MYR\", \"price\": \"0.61790\"}\n" +
+	"  ]\n" +
+	"}"
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "CNY", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731374100), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestPeoplesBankOfChinaDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(peoplesBankOfChinaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 3)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.125",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "20",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "MYR",
+		Rate:     "0.6179",
+	})
+}
+
+func TestPeoplesBankOfChinaDataSource_BlankContent(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPeoplesBankOfChinaDataSource_EmptyJsonObject(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPeoplesBankOfChinaDataSource_EmptyRecords(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"data\": {\"lastDate\": \"2024-11-12 9:15\"}, \"records\": []}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPeoplesBankOfChinaDataSource_InvalidUpdateDate(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("{\"data\": {\"lastDate\": \"2024/11/12\"}, \"records\": [{\"vrtEName\": \"USD/CNY\", \"price\": \"7.1927\"}]}"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPeoplesBankOfChinaDataSource_InvalidCurrencyPair(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"data\": {\"lastDate\": \"2024-11-12 9:15\"}, \"records\": ["+
+		"{\"vrtEName\": \"USD/EUR\", \"price\": \"0.9\"},"+
+		"{\"vrtEName\": \"USDCNY\", \"price\": \"7.1927\"},"+
+		"{\"vrtEName\": \"XJPY/CNY\", \"price\": \"4.6715\"},"+
+		"{\"vrtEName\": \"CNY/XXX\", \"price\": \"1\"}"+
+		"]}"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
+}
+
+func TestPeoplesBankOfChinaDataSource_InvalidRate(t *testing.T) {
+	dataSource := &PeoplesBankOfChinaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("{\"data\": {\"lastDate\": \"2024-11-12 9:15\"}, \"records\": ["+
+		"{\"vrtEName\": \"USD/CNY\", \"price\": \"null\"},"+
+		"{\"vrtEName\": \"EUR/CNY\", \"price\": \"0\"}"+
+		"]}"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
+}
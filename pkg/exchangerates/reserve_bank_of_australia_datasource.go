@@ -0,0 +1,197 @@
+package exchangerates
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const reserveBankOfAustraliaExchangeRateUrl = "https://www.rba.gov.au/rss/rss-cb-exchange-rates.xml"
+const reserveBankOfAustraliaExchangeRateReferenceUrl = "https://www.rba.gov.au/statistics/frequency/exchange-rates.html"
+const reserveBankOfAustraliaDataSource = "Reserve Bank of Australia"
+const reserveBankOfAustraliaBaseCurrency = "AUD"
+
+const reserveBankOfAustraliaDataUpdateDateFormat = "2006-01-02 15:04"
+const reserveBankOfAustraliaDataUpdateDateTimezone = "Australia/Sydney"
+
+// ReserveBankOfAustraliaDataSource defines the structure of exchange rates data source of the Reserve Bank of Australia
+type ReserveBankOfAustraliaDataSource struct {
+	HttpExchangeRatesDataSource
+}
+
+// ReserveBankOfAustraliaData represents the whole data from the Reserve Bank of Australia
+type ReserveBankOfAustraliaData struct {
+	XMLName xml.Name                         `xml:"RDF"`
+	Items   []*ReserveBankOfAustraliaRdfItem `xml:"item"`
+}
+
+// ReserveBankOfAustraliaRdfItem represents the rdf item from the Reserve Bank of Australia
+type ReserveBankOfAustraliaRdfItem struct {
+	Statistics *ReserveBankOfAustraliaItemStatistics `xml:"statistics"`
+}
+
+// ReserveBankOfAustraliaItemStatistics represents the item statistics from the Reserve Bank of Australia
+type ReserveBankOfAustraliaItemStatistics struct {
+	ExchangeRate *ReserveBankOfAustraliaExchangeRate `xml:"exchangeRate"`
+}
+
+// ReserveBankOfAustraliaExchangeRate represents the exchange rate from the Reserve Bank of Australia
+type ReserveBankOfAustraliaExchangeRate struct {
+	Value             string                                               `xml:"value"`
+	BaseCurrency      string                                               `xml:"baseCurrency"`
+	TargetCurrency    string                                               `xml:"targetCurrency"`
+	ObservationPeriod *ReserveBankOfAustraliaExchangeRateObservationPeriod `xml:"observationPeriod"`
+}
+
+// ReserveBankOfAustraliaExchangeRateObservationPeriod represents the exchange rate period data from the Reserve Bank of Australia
+type ReserveBankOfAustraliaExchangeRateObservationPeriod struct {
+	Period string `xml:"period"`
+}
+
+// ToLatestExchangeRateResponse returns a view-object according to original data from the Reserve Bank of Australia
+func (e *ReserveBankOfAustraliaData) ToLatestExchangeRateResponse(c core.Context) *models.LatestExchangeRateResponse {
+	if len(e.Items) < 1 {
+		log.Errorf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRateResponse] rdf items is empty")
+		return nil
+	}
+
+	timezone, err := time.LoadLocation(reserveBankOfAustraliaDataUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRateResponse] failed to get timezone, timezone name is %s", reserveBankOfAustraliaDataUpdateDateTimezone)
+		return nil
+	}
+
+	latestUpdateTime := int64(0)
+	latestCurrencyExchangeRateTime := make(map[string]int64)
+	latestExchangeRates := make(map[string]*models.LatestExchangeRate)
+
+	for i := 0; i < len(e.Items); i++ {
+		item := e.Items[i]
+
+		if item.Statistics == nil || item.Statistics.ExchangeRate == nil || item.Statistics.ExchangeRate.ObservationPeriod == nil {
+			continue
+		}
+
+		if item.Statistics.ExchangeRate.BaseCurrency != reserveBankOfAustraliaBaseCurrency {
+			continue
+		}
+
+		if _, exists := validators.AllCurrencyNames[item.Statistics.ExchangeRate.TargetCurrency]; !exists {
+			continue
+		}
+
+		updateDateTime := item.Statistics.ExchangeRate.ObservationPeriod.Period + " 16:00" // The exchange rates are observed at 4.00pm Eastern Australian time on every business day
+		updateTime, err := time.ParseInLocation(reserveBankOfAustraliaDataUpdateDateFormat, updateDateTime, timezone)
+
+		if err != nil {
+			log.Warnf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRateResponse] failed to parse exchange rate period date, period is %s", item.Statistics.ExchangeRate.ObservationPeriod.Period)
+			continue
+		}
+
+		currency := item.Statistics.ExchangeRate.TargetCurrency
+		latestTime, exists := latestCurrencyExchangeRateTime[currency]
+
+		if exists && updateTime.Unix() <= latestTime {
+			continue
+		}
+
+		finalExchangeRate := item.Statistics.ExchangeRate.ToLatestExchangeRate(c)
+
+		if finalExchangeRate == nil {
+			continue
+		}
+
+		latestCurrencyExchangeRateTime[currency] = updateTime.Unix()
+		latestExchangeRates[currency] = finalExchangeRate
+
+		if updateTime.Unix() > latestUpdateTime {
+			latestUpdateTime = updateTime.Unix()
+		}
+	}
+
+	if len(latestExchangeRates) < 1 {
+		log.Errorf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRateResponse] exchange rates is empty")
+		return nil
+	}
+
+	exchangeRates := make(models.LatestExchangeRateSlice, 0, len(latestExchangeRates))
+
+	for _, exchangeRate := range latestExchangeRates {
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	latestExchangeRateResp := &models.LatestExchangeRateResponse{
+		DataSource:    reserveBankOfAustraliaDataSource,
+		ReferenceUrl:  reserveBankOfAustraliaExchangeRateReferenceUrl,
+		UpdateTime:    latestUpdateTime,
+		BaseCurrency:  reserveBankOfAustraliaBaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+
+	return latestExchangeRateResp
+}
+
+// ToLatestExchangeRate returns a data pair according to original data from the Reserve Bank of Australia
+func (e *ReserveBankOfAustraliaExchangeRate) ToLatestExchangeRate(c core.Context) *models.LatestExchangeRate {
+	rate, err := utils.StringToFloat64(e.Value)
+
+	if err != nil {
+		log.Warnf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRate] failed to parse rate, currency is %s, rate is %s", e.TargetCurrency, e.Value)
+		return nil
+	}
+
+	if rate <= 0 {
+		log.Warnf(c, "[reserve_bank_of_australia_datasource.ToLatestExchangeRate] rate is invalid, currency is %s, rate is %s", e.TargetCurrency, e.Value)
+		return nil
+	}
+
+	return &models.LatestExchangeRate{
+		Currency: e.TargetCurrency,
+		Rate:     e.Value,
+	}
+}
+
+// BuildRequests returns the Reserve Bank of Australia exchange rates http requests
+func (e *ReserveBankOfAustraliaDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", reserveBankOfAustraliaExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// Parse returns the common response entity according to the Reserve Bank of Australia data source raw response
+func (e *ReserveBankOfAustraliaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	reserveBankOfAustraliaData := &ReserveBankOfAustraliaData{}
+	err := xmlDecoder.Decode(reserveBankOfAustraliaData)
+
+	if err != nil {
+		log.Errorf(c, "[reserve_bank_of_australia_datasource.Parse] failed to parse xml data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	latestExchangeRateResponse := reserveBankOfAustraliaData.ToLatestExchangeRateResponse(c)
+
+	if latestExchangeRateResponse == nil {
+		log.Errorf(c, "[reserve_bank_of_australia_datasource.Parse] failed to parse latest exchange rate data, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return latestExchangeRateResponse, nil
+}
@@ -0,0 +1,173 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const reserveBankOfAustraliaMinimumRequiredContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\">\n" +
+	"  <item rdf:about=\"https://www.rba.gov.au/statistics/frequency/exchange-rates.html#USD\">\n" +
+	"    <cb:statistics rdf:parseType=\"Resource\">\n" +
+	"      <cb:exchangeRate rdf:parseType=\"Resource\">\n" +
+	"        <cb:value frequency=\"business\" decimals=\"4\">0.6553</cb:value>\n" +
+	"        <cb:baseCurrency>AUD</cb:baseCurrency>\n" +
+	"        <cb:targetCurrency>USD</cb:targetCurrency>\n" +
+	"        <cb:observationPeriod rdf:parseType=\"Resource\">\n" +
+	"          <cb:period>2024-11-12</cb:period>\n" +
+	"        </cb:observationPeriod>\n" +
+	"      </cb:exchangeRate>\n" +
+	"    </cb:statistics>\n" +
+	"  </item>\n" +
+	"  <item rdf:about=\"https://www.rba.gov.au/statistics/frequency/exchange-rates.html#JPY\">\n" +
+	"    <cb:statistics rdf:parseType=\"Resource\">\n" +
+	"      <cb:exchangeRate rdf:parseType=\"Resource\">\n" +
+	"        <cb:value frequency=\"business\" decimals=\"2\">100.86</cb:value>\n" +
+	"        <cb:baseCurrency>AUD</cb:baseCurrency>\n" +
+	"        <cb:targetCurrency>JPY</cb:targetCurrency>\n" +
+	"        <cb:observationPeriod rdf:parseType=\"Resource\">\n" +
+	"          <cb:period>2024-11-12</cb:period>\n" +
+	"        </cb:observationPeriod>\n" +
+	"      </cb:exchangeRate>\n" +
+	"    </cb:statistics>\n" +
+	"  </item>\n" +
+	"</rdf:RDF>"
+
+func TestReserveBankOfAustraliaDataSource_StandardDataExtractBaseCurrency(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfAustraliaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "AUD", actualLatestExchangeRateResponse.BaseCurrency)
+}
+
+func TestReserveBankOfAustraliaDataSource_StandardDataExtractUpdateTime(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfAustraliaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1731387600), actualLatestExchangeRateResponse.UpdateTime)
+}
+
+func TestReserveBankOfAustraliaDataSource_StandardDataExtractExchangeRates(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte(reserveBankOfAustraliaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.6553",
+	})
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "JPY",
+		Rate:     "100.86",
+	})
+}
+
+func TestReserveBankOfAustraliaDataSource_MultipleDateExchanges(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestExchangeRateResponse, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\">\n"+
+		"  <item>\n"+
+		"    <cb:statistics rdf:parseType=\"Resource\">\n"+
+		"      <cb:exchangeRate rdf:parseType=\"Resource\">\n"+
+		"        <cb:value>0.6553</cb:value>\n"+
+		"        <cb:baseCurrency>AUD</cb:baseCurrency>\n"+
+		"        <cb:targetCurrency>USD</cb:targetCurrency>\n"+
+		"        <cb:observationPeriod rdf:parseType=\"Resource\">\n"+
+		"          <cb:period>2024-11-12</cb:period>\n"+
+		"        </cb:observationPeriod>\n"+
+		"      </cb:exchangeRate>\n"+
+		"    </cb:statistics>\n"+
+		"  </item>\n"+
+		"  <item>\n"+
+		"    <cb:statistics rdf:parseType=\"Resource\">\n"+
+		"      <cb:exchangeRate rdf:parseType=\"Resource\">\n"+
+		"        <cb:value>0.6571</cb:value>\n"+
+		"        <cb:baseCurrency>AUD</cb:baseCurrency>\n"+
+		"        <cb:targetCurrency>USD</cb:targetCurrency>\n"+
+		"        <cb:observationPeriod rdf:parseType=\"Resource\">\n"+
+		"          <cb:period>2024-11-11</cb:period>\n"+
+		"        </cb:observationPeriod>\n"+
+		"      </cb:exchangeRate>\n"+
+		"    </cb:statistics>\n"+
+		"  </item>\n"+
+		"</rdf:RDF>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 1)
+	assert.Contains(t, actualLatestExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.6553",
+	})
+}
+
+func TestReserveBankOfAustraliaDataSource_BlankContent(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReserveBankOfAustraliaDataSource_EmptyRdfContent(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\">\n"+
+		"</rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReserveBankOfAustraliaDataSource_InvalidCurrency(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\">\n"+
+		"  <item>\n"+
+		"    <cb:statistics rdf:parseType=\"Resource\">\n"+
+		"      <cb:exchangeRate rdf:parseType=\"Resource\">\n"+
+		"        <cb:value>60.9</cb:value>\n"+
+		"        <cb:baseCurrency>AUD</cb:baseCurrency>\n"+
+		"        <cb:targetCurrency>TWI</cb:targetCurrency>\n"+
+		"        <cb:observationPeriod rdf:parseType=\"Resource\">\n"+
+		"          <cb:period>2024-11-12</cb:period>\n"+
+		"        </cb:observationPeriod>\n"+
+		"      </cb:exchangeRate>\n"+
+		"    </cb:statistics>\n"+
+		"  </item>\n"+
+		"</rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReserveBankOfAustraliaDataSource_InvalidRate(t *testing.T) {
+	dataSource := &ReserveBankOfAustraliaDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns=\"http://purl.org/rss/1.0/\" xmlns:cb=\"http://www.cbwiki.net/wiki/index.php/Specification_1.2/\">\n"+
+		"  <item>\n"+
+		"    <cb:statistics rdf:parseType=\"Resource\">\n"+
+		"      <cb:exchangeRate rdf:parseType=\"Resource\">\n"+
+		"        <cb:value>0</cb:value>\n"+
+		"        <cb:baseCurrency>AUD</cb:baseCurrency>\n"+
+		"        <cb:targetCurrency>USD</cb:targetCurrency>\n"+
+		"        <cb:observationPeriod rdf:parseType=\"Resource\">\n"+
+		"          <cb:period>2024-11-12</cb:period>\n"+
+		"        </cb:observationPeriod>\n"+
+		"      </cb:exchangeRate>\n"+
+		"    </cb:statistics>\n"+
+		"  </item>\n"+
+		"</rdf:RDF>"))
+	assert.NotEqual(t, nil, err)
+}
@@ -22,6 +22,8 @@ type GlobalTextItems struct {
 type DefaultTypes struct {
 	DecimalSeparator    core.DecimalSeparator
 	DigitGroupingSymbol core.DigitGroupingSymbol
+	LongDateFormat      core.LongDateFormat
+	LongTimeFormat      core.LongTimeFormat
 }
 
 // DataConverterTextItems represents text items need to be translated in data converter
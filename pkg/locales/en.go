@@ -11,6 +11,8 @@ var en = &LocaleTextItems{
 	DefaultTypes: &DefaultTypes{
 		DecimalSeparator:    core.DECIMAL_SEPARATOR_DOT,
 		DigitGroupingSymbol: core.DIGIT_GROUPING_SYMBOL_COMMA,
+		LongDateFormat:      core.LONG_DATE_FORMAT_M_D_YYYY,
+		LongTimeFormat:      core.LONG_TIME_FORMAT_HH_MM_SS_A,
 	},
 	DataConverterTextItems: &DataConverterTextItems{
 		Alipay:       "Alipay",
@@ -11,6 +11,8 @@ var fr = &LocaleTextItems{
 	DefaultTypes: &DefaultTypes{
 		DecimalSeparator:    core.DECIMAL_SEPARATOR_COMMA,
 		DigitGroupingSymbol: core.DIGIT_GROUPING_SYMBOL_SPACE,
+		LongDateFormat:      core.LONG_DATE_FORMAT_D_M_YYYY,
+		LongTimeFormat:      core.LONG_TIME_FORMAT_HH_MM_SS,
 	},
 	DataConverterTextItems: &DataConverterTextItems{
 		Alipay:       "Alipay",
@@ -11,6 +11,8 @@ var ja = &LocaleTextItems{
 	DefaultTypes: &DefaultTypes{
 		DecimalSeparator:    core.DECIMAL_SEPARATOR_DOT,
 		DigitGroupingSymbol: core.DIGIT_GROUPING_SYMBOL_COMMA,
+		LongDateFormat:      core.LONG_DATE_FORMAT_YYYY_M_D,
+		LongTimeFormat:      core.LONG_TIME_FORMAT_HH_MM_SS,
 	},
 	DataConverterTextItems: &DataConverterTextItems{
 		Alipay:       "Alipay",
@@ -63,6 +63,11 @@ func (h *mcpAddTransactionToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAddTransactionResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpAddTransactionToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_ADD_TRANSACTION
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var addTransactionRequest MCPAddTransactionRequest
@@ -85,8 +90,9 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		return nil, nil, errs.ErrTransactionHasTooManyTags
 	}
 
-	uid := user.Uid
-	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get account error, because %s", err.Error())
@@ -115,7 +121,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		destinationAccountId = destinationAccount.AccountId
 	}
 
-	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
 
 	if err != nil {
 		log.Warnf(c, "[add_transaction.Handle] get transaction category error, because %s", err.Error())
@@ -153,7 +159,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	var tagIds []int64
 
 	if len(addTransactionRequest.Tags) > 0 {
-		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid, ledgerId)
 
 		if err != nil {
 			log.Warnf(c, "[add_transaction.Handle] get transaction tag ids error, because %s", err.Error())
@@ -172,7 +178,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		}
 	}
 
-	transaction, err := h.createNewTransactionModel(uid, &addTransactionRequest, transactionCategory.CategoryId, sourceAccount.AccountId, destinationAccountId, c.ClientIP())
+	transaction, err := h.createNewTransactionModel(uid, ledgerId, &addTransactionRequest, transactionCategory.CategoryId, sourceAccount.AccountId, destinationAccountId, user.Uid, c.ClientIP())
 
 	if err != nil {
 		return nil, nil, err
@@ -185,7 +191,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	if !addTransactionRequest.DryRun {
-		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil)
+		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil, nil)
 
 		if err != nil {
 			log.Errorf(c, "[add_transaction.Handle] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -200,7 +206,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 			accountIds = append(accountIds, destinationAccountId)
 		}
 
-		newAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, accountIds)
+		newAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, ledgerId, accountIds)
 
 		if err != nil {
 			log.Warnf(c, "[add_transaction.Handle] failed to get latest accounts info after transaction created, because %s", err.Error())
@@ -238,7 +244,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 }
 
-func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addTransactionRequest *MCPAddTransactionRequest, categoryId int64, sourceAccountId int64, destinationAccountId int64, clientIp string) (*models.Transaction, error) {
+func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, ledgerId int64, addTransactionRequest *MCPAddTransactionRequest, categoryId int64, sourceAccountId int64, destinationAccountId int64, createdByUid int64, clientIp string) (*models.Transaction, error) {
 	var transactionDbType models.TransactionDbType
 
 	if addTransactionRequest.Type == transactionTypeExpense {
@@ -263,6 +269,7 @@ func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addT
 
 	transaction := &models.Transaction{
 		Uid:               uid,
+		LedgerId:          ledgerId,
 		Type:              transactionDbType,
 		CategoryId:        categoryId,
 		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
@@ -272,6 +279,7 @@ func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addT
 		HideAmount:        false,
 		Comment:           addTransactionRequest.Comment,
 		CreatedIp:         clientIp,
+		CreatedByUid:      createdByUid,
 	}
 
 	if addTransactionRequest.Type == transactionTypeTransfer {
@@ -32,6 +32,9 @@ type MCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPRe
 	// OutputType returns the output type for the MCP tool response
 	OutputType() reflect.Type
 
+	// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+	RequiredLedgerPermission() core.LedgerPermission
+
 	// Handle processes the MCP call tool request and returns the response
 	Handle(*core.WebContext, *MCPCallToolRequest, *models.User, *settings.Config, MCPAvailableServices) (any, []*T, error)
 }
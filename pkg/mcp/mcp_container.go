@@ -6,6 +6,7 @@ import (
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
@@ -72,6 +73,7 @@ func InitializeMCPHandlers(config *settings.Config) error {
 
 	registerMCPTextContentToolHandler(container, MCPAddTransactionToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryTransactionsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryTransactionStatisticsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsBalanceToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionCategoriesToolHandler)
@@ -112,6 +114,11 @@ func registerMCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent
 }
 
 func handleTool[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPResourceLink | MCPEmbeddedResource](ctx *core.WebContext, handler MCPToolHandler[T], currentConfig *settings.Config, services MCPAvailableServices, callToolReq *MCPCallToolRequest, user *models.User) (any, error) {
+	if !ctx.GetCurrentLedgerRole().HasPermission(handler.RequiredLedgerPermission()) {
+		log.Warnf(ctx, "[mcp_container.handleTool] user \"uid:%d\" has no permission to call tool \"%s\" in ledger \"id:%d\"", user.Uid, callToolReq.Name, ctx.GetCurrentLedgerId())
+		return nil, errs.ErrNoPermissionToOperateLedger
+	}
+
 	structuredResponse, result, err := handler.Handle(ctx, callToolReq, user, currentConfig, services)
 
 	if err != nil {
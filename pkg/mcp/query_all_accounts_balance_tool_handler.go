@@ -57,10 +57,16 @@ func (h *mcpQueryAllAccountsBalanceToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryAllAccountsBalanceResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryAllAccountsBalanceToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllAccountsBalanceToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
-	uid := user.Uid
-	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[query_all_accounts_balance_tool_handler.Handle] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
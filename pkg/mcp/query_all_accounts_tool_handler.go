@@ -47,10 +47,16 @@ func (h *mcpQueryAllAccountsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryAllAccountsResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryAllAccountsToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllAccountsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
-	uid := user.Uid
-	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[query_all_accounts.Handle] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
@@ -41,10 +41,16 @@ func (h *mcpQueryAllTransactionCategoriesToolHandler) OutputType() reflect.Type
 	return reflect.TypeOf(&MCPQueryAllTransactionCategoriesResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryAllTransactionCategoriesToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionCategoriesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
-	uid := user.Uid
-	categories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	categories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[query_all_transaction_categories.Handle] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
@@ -39,10 +39,16 @@ func (h *mcpQueryAllTransactionTagsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAllQueryTransactionTagsResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryAllTransactionTagsToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionTagsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
-	uid := user.Uid
-	tags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	tags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid, ledgerId)
 
 	if err != nil {
 		log.Errorf(c, "[query_all_transaction_tags.Handle] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
@@ -56,6 +56,11 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryExchangeRatesResponse{})
 }
 
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryLatestExchangeRatesToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryLatestExchangeRatesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var exchangeRatesRequest MCPQueryExchangeRatesRequest
@@ -68,7 +73,10 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) Handle(c *core.WebContext, call
 		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
 	}
 
-	exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, user.Uid, currentConfig)
+	requiredCurrencies := h.getQueryCurrencies(exchangeRatesRequest.Currencies)
+	requiredCurrencies[user.DefaultCurrency] = true
+
+	exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRatesOfCurrencies(c, user.Uid, currentConfig, requiredCurrencies)
 
 	if err != nil {
 		return nil, nil, err
@@ -84,15 +92,7 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) Handle(c *core.WebContext, call
 }
 
 func (h *mcpQueryLatestExchangeRatesToolHandler) createNewMCPQueryExchangeRatesResponse(currencies string, exchangeRatesResp *models.LatestExchangeRateResponse) (any, []*MCPTextContent, error) {
-	queryCurrencies := make(map[string]bool)
-
-	for _, currency := range strings.Split(currencies, ",") {
-		currency = strings.TrimSpace(currency)
-
-		if currency != "" {
-			queryCurrencies[currency] = true
-		}
-	}
+	queryCurrencies := h.getQueryCurrencies(currencies)
 
 	response := &MCPQueryExchangeRatesResponse{
 		BaseCurrency: exchangeRatesResp.BaseCurrency,
@@ -121,3 +121,17 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) createNewMCPQueryExchangeRatesR
 		NewMCPTextContent(string(content)),
 	}, nil
 }
+
+func (h *mcpQueryLatestExchangeRatesToolHandler) getQueryCurrencies(currencies string) map[string]bool {
+	queryCurrencies := make(map[string]bool)
+
+	for _, currency := range strings.Split(currencies, ",") {
+		currency = strings.TrimSpace(currency)
+
+		if currency != "" {
+			queryCurrencies[currency] = true
+		}
+	}
+
+	return queryCurrencies
+}
@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+// MCPQueryTransactionStatisticsRequest represents all parameters of the query transaction statistics request
+type MCPQueryTransactionStatisticsRequest struct {
+	StartTime                  string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time for the query in RFC 3339 format (e.g. 2023-01-01T00:00:00Z)"`
+	EndTime                    string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time for the query in RFC 3339 format (e.g. 2023-01-31T23:59:59Z)"`
+	Currency                   string `json:"currency,omitempty" jsonschema_description:"Currency code which all amounts are converted into (e.g. USD) (optional, default is the default currency of the user)"`
+	UseHistoricalExchangeRates bool   `json:"use_historical_exchange_rates,omitempty" jsonschema_description:"Whether to convert the amount of each transaction at the exchange rates of its date instead of the latest exchange rates (optional)"`
+}
+
+// MCPQueryTransactionStatisticsResponse represents the response structure for querying transaction statistics
+type MCPQueryTransactionStatisticsResponse struct {
+	Currency      string                          `json:"currency" jsonschema_description:"Currency code of all amounts (e.g. USD)"`
+	TotalIncome   string                          `json:"total_income" jsonschema_description:"Total income amount in the specified time range"`
+	TotalExpense  string                          `json:"total_expense" jsonschema_description:"Total expense amount in the specified time range"`
+	Categories    []*MCPCategoryStatisticInfo     `json:"categories" jsonschema_description:"Total amounts of each transaction category, sorted by amount in descending order"`
+	ExchangeRates *MCPExchangeRatesConversionInfo `json:"exchange_rates" jsonschema_description:"Exchange rates applied when converting the amounts"`
+}
+
+// MCPCategoryStatisticInfo defines the structure of the total amount of a transaction category
+type MCPCategoryStatisticInfo struct {
+	SecondaryCategoryName string `json:"category_name" jsonschema_description:"Secondary category name"`
+	Type                  string `json:"type" jsonschema:"enum=income,enum=expense" jsonschema_description:"Transaction type (income, expense)"`
+	Amount                string `json:"amount" jsonschema_description:"Total amount of the category"`
+}
+
+// MCPExchangeRatesConversionInfo defines the structure of the exchange rates applied when converting the amounts
+type MCPExchangeRatesConversionInfo struct {
+	DataSource              string                        `json:"data_source" jsonschema_description:"Exchange rates data source"`
+	UpdateTime              string                        `json:"update_time,omitempty" jsonschema_description:"Last update time of the latest exchange rates in RFC 3339 format (e.g. '2023-01-01T12:00:00Z')"`
+	UseHistoricalRates      bool                          `json:"use_historical_rates" jsonschema_description:"Whether the amount of each transaction is converted at the exchange rates of its date"`
+	Rates                   []*MCPAppliedExchangeRateInfo `json:"rates" jsonschema_description:"Latest exchange rates from the currency of each account to the target currency"`
+	UnconvertibleCurrencies []string                      `json:"unconvertible_currencies,omitempty" jsonschema_description:"Currencies which have no exchange rate, the amounts in these currencies are not converted"`
+}
+
+// MCPAppliedExchangeRateInfo defines the structure of the exchange rate from a currency to the target currency
+type MCPAppliedExchangeRateInfo struct {
+	Currency   string `json:"currency" jsonschema_description:"Currency code (e.g. EUR)"`
+	Rate       string `json:"rate_to_target" jsonschema_description:"The amount of the target currency that can be obtained for 1 unit of this currency"`
+	IsUserRate bool   `json:"is_user_custom_rate,omitempty" jsonschema_description:"Whether this rate is a user custom exchange rate"`
+}
+
+type mcpQueryTransactionStatisticsToolHandler struct{}
+
+var MCPQueryTransactionStatisticsToolHandler = &mcpQueryTransactionStatisticsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Name() string {
+	return "query_transaction_statistics"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Description() string {
+	return "Query total income and expense amounts of each transaction category in specified time range, all amounts are converted into one currency."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryTransactionStatisticsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryTransactionStatisticsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsResponse{})
+}
+
+// RequiredLedgerPermission returns the ledger permission required to call the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) RequiredLedgerPermission() core.LedgerPermission {
+	return core.LEDGER_PERMISSION_READ
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryTransactionStatisticsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryStatisticsRequest MCPQueryTransactionStatisticsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryStatisticsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if queryStatisticsRequest.Currency != "" {
+		if _, exists := validators.AllCurrencyNames[queryStatisticsRequest.Currency]; !exists {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	}
+
+	startTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.StartTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	endTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := c.GetCurrentLedgerOwnerUid()
+	ledgerId := c.GetCurrentLedgerId()
+	amountConverter, exchangeRatesConversion, err := exchangerates.Container.GetTransactionAmountConverter(c, uid, ledgerId, currentConfig, queryStatisticsRequest.Currency, queryStatisticsRequest.UseHistoricalExchangeRates, startTime.Unix(), endTime.Unix())
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_statistics_tool_handler.Handle] failed to get exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	totalAmounts, err := services.GetTransactionService().GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, ledgerId, startTime.Unix(), endTime.Unix(), nil, false, "", startTime.Location(), false, false, amountConverter)
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_statistics_tool_handler.Handle] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, ledgerId, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_statistics_tool_handler.Handle] failed to get transaction categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	structuredResponse, response, err := h.createNewMCPQueryTransactionStatisticsResponse(totalAmounts, services.GetTransactionCategoryService().GetCategoryMapByList(allCategories), exchangeRatesConversion)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return structuredResponse, response, nil
+}
+
+func (h *mcpQueryTransactionStatisticsToolHandler) createNewMCPQueryTransactionStatisticsResponse(totalAmounts []*models.Transaction, categoriesMap map[int64]*models.TransactionCategory, exchangeRatesConversion *models.ExchangeRatesConversionResponse) (any, []*MCPTextContent, error) {
+	totalIncomeAmount, totalExpenseAmount := models.GetTotalIncomeAndExpenseAmounts(totalAmounts)
+	categoryTotalAmounts := make(map[int64]int64)
+	categoryTypes := make(map[int64]string)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmountItem := totalAmounts[i]
+
+		if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			categoryTypes[totalAmountItem.CategoryId] = transactionTypeIncome
+		} else if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			categoryTypes[totalAmountItem.CategoryId] = transactionTypeExpense
+		} else {
+			continue
+		}
+
+		categoryTotalAmounts[totalAmountItem.CategoryId] += totalAmountItem.Amount
+	}
+
+	categoryIds := make([]int64, 0, len(categoryTotalAmounts))
+
+	for categoryId := range categoryTotalAmounts {
+		categoryIds = append(categoryIds, categoryId)
+	}
+
+	sort.Slice(categoryIds, func(i, j int) bool {
+		if categoryTotalAmounts[categoryIds[i]] != categoryTotalAmounts[categoryIds[j]] {
+			return categoryTotalAmounts[categoryIds[i]] > categoryTotalAmounts[categoryIds[j]]
+		}
+
+		return categoryIds[i] < categoryIds[j]
+	})
+
+	response := MCPQueryTransactionStatisticsResponse{
+		Currency:     exchangeRatesConversion.Currency,
+		TotalIncome:  utils.FormatAmount(totalIncomeAmount),
+		TotalExpense: utils.FormatAmount(totalExpenseAmount),
+		Categories:   make([]*MCPCategoryStatisticInfo, 0, len(categoryIds)),
+		ExchangeRates: &MCPExchangeRatesConversionInfo{
+			DataSource:              exchangeRatesConversion.DataSource,
+			UseHistoricalRates:      exchangeRatesConversion.UseHistoricalRates,
+			Rates:                   make([]*MCPAppliedExchangeRateInfo, 0, len(exchangeRatesConversion.ExchangeRates)),
+			UnconvertibleCurrencies: exchangeRatesConversion.UnconvertibleCurrencies,
+		},
+	}
+
+	if exchangeRatesConversion.UpdateTime > 0 {
+		response.ExchangeRates.UpdateTime = utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(exchangeRatesConversion.UpdateTime, time.UTC)
+	}
+
+	for i := 0; i < len(exchangeRatesConversion.ExchangeRates); i++ {
+		exchangeRate := exchangeRatesConversion.ExchangeRates[i]
+		response.ExchangeRates.Rates = append(response.ExchangeRates.Rates, &MCPAppliedExchangeRateInfo{
+			Currency:   exchangeRate.Currency,
+			Rate:       exchangeRate.Rate,
+			IsUserRate: exchangeRate.DataSource == settings.UserCustomExchangeRatesDataSource,
+		})
+	}
+
+	for i := 0; i < len(categoryIds); i++ {
+		categoryInfo := &MCPCategoryStatisticInfo{
+			Type:   categoryTypes[categoryIds[i]],
+			Amount: utils.FormatAmount(categoryTotalAmounts[categoryIds[i]]),
+		}
+
+		if category, exists := categoriesMap[categoryIds[i]]; exists && category != nil {
+			categoryInfo.SecondaryCategoryName = category.Name
+		}
+
+		response.Categories = append(response.Categories, categoryInfo)
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
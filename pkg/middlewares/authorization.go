@@ -120,6 +120,15 @@ func JWTMCPAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
 
 		c.SetTokenClaims(claims)
 		c.SetTokenContext(tokenContext)
+
+		// the permission of each mcp tool is checked when the tool is called
+		err = setCurrentLedgerAccess(c, 0, false)
+
+		if err != nil {
+			utils.PrintJsonErrorResult(c, err)
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -175,6 +184,21 @@ func jwtAuthorization(config *settings.Config, source TokenSourceType) core.Midd
 
 		c.SetTokenClaims(claims)
 		c.SetTokenContext(tokenContext)
+
+		permission, isLedgerDataRoute, err := getRequiredLedgerPermission(c)
+
+		if err != nil {
+			utils.PrintJsonErrorResult(c, err)
+			return
+		}
+
+		err = setCurrentLedgerAccess(c, permission, isLedgerDataRoute)
+
+		if err != nil {
+			utils.PrintJsonErrorResult(c, err)
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,221 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// nonLedgerDataRoutePrefixes represents the route prefixes of api which do not operate on the data of any ledger
+var nonLedgerDataRoutePrefixes = []string{
+	"/avatar/",
+	"/proxy/",
+	"/_AMapService/",
+	"/api/v1/tokens/",
+	"/api/v1/users/",
+	"/api/v1/ledgers/",
+	"/api/v1/exchange_rates/",
+	"/api/v1/security_prices/",
+	"/api/v1/systems/",
+}
+
+// ledgerRoutePermissions represents the required ledger permissions of all the api which operate on the data of current ledger
+var ledgerRoutePermissions = map[string]core.LedgerPermission{
+	"GET /api/v1/data/statistics.json":                             core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/data/clear/all.json":                             core.LEDGER_PERMISSION_MANAGE,
+	"POST /api/v1/data/clear/transactions.json":                    core.LEDGER_PERMISSION_MANAGE,
+	"POST /api/v1/data/clear/transactions/by_account.json":         core.LEDGER_PERMISSION_MANAGE,
+	"GET /api/v1/data/export.csv":                                  core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.tsv":                                  core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.ofx":                                  core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.qif":                                  core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.beancount":                            core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.ledger":                               core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.gnucash":                              core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/export.xlsx":                                 core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/data/backup.zip":                                  core.LEDGER_PERMISSION_MANAGE,
+	"POST /api/v1/data/restore.json":                               core.LEDGER_PERMISSION_MANAGE,
+	"GET /api/v1/accounts/list.json":                               core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/accounts/get.json":                                core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"POST /api/v1/accounts/add.json":                               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/modify.json":                            core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/hide.json":                              core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/move.json":                              core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/delete.json":                            core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/sub_account/delete.json":                core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/accounts/trash/list.json":                         core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/accounts/trash/restore.json":                     core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/accounts/trash/purge.json":                       core.LEDGER_PERMISSION_MANAGE,
+	"GET /api/v1/transactions/count.json":                          core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/list.json":                           core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/list/by_month.json":                  core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/list/all.json":                       core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/reconciliation_statements.json":      core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transactions/reconciliation/set_status.json":     core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/reconciliation/reconcile.json":      core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transactions/statistics.json":                     core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/statistics/trends.json":              core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/statistics/asset_trends.json":        core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/statistics/net_worth_trends.json":    core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/cash_flow_forecast.json":             core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/amounts.json":                        core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/get.json":                            core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transactions/history.json":                        core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transactions/add.json":                           core.LEDGER_PERMISSION_ADD_TRANSACTION,
+	"POST /api/v1/transactions/modify.json":                        core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/move/all.json":                      core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/delete.json":                        core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transactions/trash/list.json":                     core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transactions/trash/restore.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/trash/purge.json":                   core.LEDGER_PERMISSION_MANAGE,
+	"POST /api/v1/transactions/parse_custom_file.json":             core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/parse_import.json":                  core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transactions/import.json":                        core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transactions/import/process.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/pictures/upload.json":                core.LEDGER_PERMISSION_ADD_TRANSACTION,
+	"POST /api/v1/transaction/pictures/remove_unused.json":         core.LEDGER_PERMISSION_ADD_TRANSACTION,
+	"GET /pictures/:fileName":                                      core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transaction/categories/list.json":                 core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/transaction/categories/get.json":                  core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"POST /api/v1/transaction/categories/add.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/add_batch.json":           core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/modify.json":              core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/hide.json":                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/move.json":                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/delete.json":              core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transaction/categories/trash/list.json":           core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transaction/categories/trash/restore.json":       core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/categories/trash/purge.json":         core.LEDGER_PERMISSION_MANAGE,
+	"GET /api/v1/transaction/tags/groups/list.json":                core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/transaction/tags/groups/get.json":                 core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"POST /api/v1/transaction/tags/groups/add.json":                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/groups/modify.json":             core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/groups/move.json":               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/groups/delete.json":             core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transaction/tags/list.json":                       core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/transaction/tags/get.json":                        core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"POST /api/v1/transaction/tags/add.json":                       core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/add_batch.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/modify.json":                    core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/hide.json":                      core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/move.json":                      core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/delete.json":                    core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transaction/tags/trash/list.json":                 core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transaction/tags/trash/restore.json":             core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/tags/trash/purge.json":               core.LEDGER_PERMISSION_MANAGE,
+	"GET /api/v1/transaction/templates/list.json":                  core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/transaction/templates/get.json":                   core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/transaction/templates/scheduled_preview.json":     core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transaction/templates/add.json":                  core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/modify.json":               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/hide.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/move.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/delete.json":               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/occurrences/skip.json":     core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/occurrences/postpone.json": core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/templates/occurrences/restore.json":  core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/transaction/rules/list.json":                      core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/transaction/rules/get.json":                       core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/transaction/rules/add.json":                      core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/rules/modify.json":                   core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/rules/move.json":                     core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/rules/delete.json":                   core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/transaction/rules/apply.json":                    core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/payees/list.json":                                 core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"GET /api/v1/payees/get.json":                                  core.LEDGER_PERMISSION_READ_BASIC_DATA,
+	"POST /api/v1/payees/add.json":                                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/payees/add_batch.json":                           core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/payees/modify.json":                              core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/payees/hide.json":                                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/payees/move.json":                                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/payees/delete.json":                              core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/amortization_schedules/list.json":                 core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/amortization_schedules/get.json":                  core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/amortization_schedules/add.json":                 core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/amortization_schedules/delete.json":              core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/investments/transactions/list.json":               core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/investments/transactions/add.json":               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/investments/transactions/delete.json":            core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/investments/holdings/list.json":                   core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/insights/explorers/list.json":                     core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/insights/explorers/get.json":                      core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/insights/explorers/add.json":                     core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/insights/explorers/modify.json":                  core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/insights/explorers/hide.json":                    core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/insights/explorers/move.json":                    core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/insights/explorers/delete.json":                  core.LEDGER_PERMISSION_WRITE,
+	"GET /api/v1/budgets/list.json":                                core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/budgets/get.json":                                 core.LEDGER_PERMISSION_READ,
+	"GET /api/v1/budgets/usages.json":                              core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/budgets/add.json":                                core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/budgets/modify.json":                             core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/budgets/hide.json":                               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/budgets/move.json":                               core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/budgets/delete.json":                             core.LEDGER_PERMISSION_WRITE,
+	"POST /api/v1/llm/transactions/recognize_receipt_image.json":   core.LEDGER_PERMISSION_ADD_TRANSACTION,
+	"POST /api/v1/llm/transactions/recognize_receipt_images.json":  core.LEDGER_PERMISSION_ADD_TRANSACTION,
+	"POST /api/v1/llm/assistant/chat.json":                         core.LEDGER_PERMISSION_READ,
+	"POST /api/v1/llm/assistant/chat/stream.json":                  core.LEDGER_PERMISSION_READ,
+}
+
+// getRequiredLedgerPermission returns the ledger permission which current request requires, returns false if current request does not operate on ledger data,
+// returns error if the route of current request is unknown, so that the new route would be denied until its required ledger permission is defined
+func getRequiredLedgerPermission(c *core.WebContext) (core.LedgerPermission, bool, *errs.Error) {
+	routePath := c.FullPath()
+	method := c.Request.Method
+
+	if permission, exists := ledgerRoutePermissions[method+" "+routePath]; exists {
+		return permission, true, nil
+	}
+
+	for i := 0; i < len(nonLedgerDataRoutePrefixes); i++ {
+		if strings.HasPrefix(routePath, nonLedgerDataRoutePrefixes[i]) {
+			return 0, false, nil
+		}
+	}
+
+	log.Warnf(c, "[ledger_permission.getRequiredLedgerPermission] the required ledger permission of route \"%s %s\" is not defined", method, routePath)
+	return 0, false, errs.ErrNoPermissionToOperateLedger
+}
+
+// setCurrentLedgerAccess verifies the ledger id in request header and whether current user has the required permission, then saves the ledger access to context
+func setCurrentLedgerAccess(c *core.WebContext, permission core.LedgerPermission, checkPermission bool) *errs.Error {
+	ledgerId := models.DefaultLedgerId
+	ledgerIdValue := c.GetLedgerIdFromHeader()
+
+	if ledgerIdValue != "" {
+		var err error
+		ledgerId, err = utils.StringToInt64(ledgerIdValue)
+
+		if err != nil || ledgerId < 0 {
+			log.Warnf(c, "[ledger_permission.setCurrentLedgerAccess] ledger id \"%s\" is invalid", ledgerIdValue)
+			return errs.ErrLedgerIdInvalid
+		}
+	}
+
+	uid := c.GetCurrentUid()
+	var access *models.LedgerAccess
+	var err error
+
+	if checkPermission {
+		access, err = services.LedgerMembers.CheckLedgerPermission(c, uid, ledgerId, permission)
+	} else {
+		access, err = services.LedgerMembers.GetLedgerAccess(c, uid, ledgerId)
+	}
+
+	if err != nil {
+		log.Warnf(c, "[ledger_permission.setCurrentLedgerAccess] failed to get access of ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerId, uid, err.Error())
+		return errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	c.SetCurrentLedgerId(access.LedgerId)
+	c.SetCurrentLedgerOwnerUid(access.OwnerUid)
+	c.SetCurrentLedgerRole(access.Role)
+
+	return nil
+}
@@ -69,13 +69,14 @@ var defaultCreditCardAccountStatementDate = 0
 // Account represents account data stored in database
 type Account struct {
 	AccountId       int64           `xorm:"PK"`
-	Uid             int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
-	Deleted         bool            `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	Uid             int64           `xorm:"INDEX(IDX_account_uid_ledger_id_deleted_parent_account_id_order) NOT NULL"`
+	LedgerId        int64           `xorm:"INDEX(IDX_account_uid_ledger_id_deleted_parent_account_id_order) NOT NULL DEFAULT 0"`
+	Deleted         bool            `xorm:"INDEX(IDX_account_uid_ledger_id_deleted_parent_account_id_order) NOT NULL"`
 	Category        AccountCategory `xorm:"NOT NULL"`
 	Type            AccountType     `xorm:"NOT NULL"`
-	ParentAccountId int64           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	ParentAccountId int64           `xorm:"INDEX(IDX_account_uid_ledger_id_deleted_parent_account_id_order) NOT NULL"`
 	Name            string          `xorm:"VARCHAR(64) NOT NULL"`
-	DisplayOrder    int32           `xorm:"INDEX(IDX_account_uid_deleted_parent_account_id_order) NOT NULL"`
+	DisplayOrder    int32           `xorm:"INDEX(IDX_account_uid_ledger_id_deleted_parent_account_id_order) NOT NULL"`
 	Icon            int64           `xorm:"NOT NULL"`
 	Color           string          `xorm:"VARCHAR(6) NOT NULL"`
 	Currency        string          `xorm:"VARCHAR(3) NOT NULL"`
@@ -158,6 +159,16 @@ type AccountDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// AccountRestoreRequest represents all parameters of deleted account restoring request
+type AccountRestoreRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AccountPurgeRequest represents all parameters of deleted account purging request
+type AccountPurgeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
 // AccountInfoResponse represents a view-object of account
 type AccountInfoResponse struct {
 	Id                      int64                    `json:"id,string"`
@@ -178,6 +189,12 @@ type AccountInfoResponse struct {
 	SubAccounts             AccountInfoResponseSlice `json:"subAccounts,omitempty"`
 }
 
+// DeletedAccountInfoResponse represents a view-object of deleted account in trash bin
+type DeletedAccountInfoResponse struct {
+	*AccountInfoResponse
+	DeletedTime int64 `json:"deletedTime"`
+}
+
 // ToAccountInfoResponse returns a view-object according to database model
 func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	var creditCardStatementDate *int
@@ -209,6 +226,14 @@ func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 	}
 }
 
+// ToDeletedAccountInfoResponse returns a view-object of deleted account according to database model
+func (a *Account) ToDeletedAccountInfoResponse() *DeletedAccountInfoResponse {
+	return &DeletedAccountInfoResponse{
+		AccountInfoResponse: a.ToAccountInfoResponse(),
+		DeletedTime:         a.DeletedUnixTime,
+	}
+}
+
 // FromDB fills the fields from the data stored in database
 func (a *AccountExtend) FromDB(data []byte) error {
 	return json.Unmarshal(data, a)
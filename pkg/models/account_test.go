@@ -49,3 +49,21 @@ func TestAccountInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(5), accountRespSlice[4].Id)
 	assert.Equal(t, int64(3), accountRespSlice[5].Id)
 }
+
+func TestAccountToDeletedAccountInfoResponse(t *testing.T) {
+	account := &Account{
+		AccountId:       1001,
+		Name:            "Savings",
+		Currency:        "USD",
+		Balance:         10000,
+		Deleted:         true,
+		DeletedUnixTime: 1710000000,
+	}
+
+	accountResp := account.ToDeletedAccountInfoResponse()
+
+	assert.Equal(t, int64(1001), accountResp.Id)
+	assert.Equal(t, "Savings", accountResp.Name)
+	assert.Equal(t, int64(10000), accountResp.Balance)
+	assert.Equal(t, int64(1710000000), accountResp.DeletedTime)
+}
@@ -0,0 +1,373 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumInstallmentCountOfAmortizationSchedule represents the maximum installment count of one amortization schedule
+const MaximumInstallmentCountOfAmortizationSchedule = 600
+
+// MaximumPaymentDayOfAmortizationSchedule represents the maximum payment day in month of amortization schedule
+const MaximumPaymentDayOfAmortizationSchedule = 28
+
+// MaximumAnnualInterestRateOfAmortizationSchedule represents the maximum annual interest rate (in percent) of amortization schedule
+const MaximumAnnualInterestRateOfAmortizationSchedule = 100
+
+// AmortizationMethod represents amortization method
+type AmortizationMethod byte
+
+// Amortization methods
+const (
+	AMORTIZATION_METHOD_EQUAL_PAYMENT   AmortizationMethod = 1
+	AMORTIZATION_METHOD_EQUAL_PRINCIPAL AmortizationMethod = 2
+)
+
+// String returns a textual representation of the amortization method enum
+func (m AmortizationMethod) String() string {
+	switch m {
+	case AMORTIZATION_METHOD_EQUAL_PAYMENT:
+		return "Equal Payment"
+	case AMORTIZATION_METHOD_EQUAL_PRINCIPAL:
+		return "Equal Principal"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(m))
+	}
+}
+
+// IsValid returns whether the amortization method is valid
+func (m AmortizationMethod) IsValid() bool {
+	return m == AMORTIZATION_METHOD_EQUAL_PAYMENT || m == AMORTIZATION_METHOD_EQUAL_PRINCIPAL
+}
+
+// AmortizationSchedule represents amortization schedule of a debt account stored in database
+type AmortizationSchedule struct {
+	ScheduleId            int64              `xorm:"PK"`
+	Uid                   int64              `xorm:"INDEX(IDX_amortization_schedule_uid_ledger_id_deleted_account_id) NOT NULL"`
+	LedgerId              int64              `xorm:"INDEX(IDX_amortization_schedule_uid_ledger_id_deleted_account_id) NOT NULL DEFAULT 0"`
+	Deleted               bool               `xorm:"INDEX(IDX_amortization_schedule_uid_ledger_id_deleted_account_id) NOT NULL"`
+	AccountId             int64              `xorm:"INDEX(IDX_amortization_schedule_uid_ledger_id_deleted_account_id) NOT NULL"`
+	Name                  string             `xorm:"VARCHAR(64) NOT NULL"`
+	Method                AmortizationMethod `xorm:"NOT NULL"`
+	Principal             int64              `xorm:"NOT NULL"`
+	AnnualInterestRate    string             `xorm:"VARCHAR(20) NOT NULL"`
+	InstallmentCount      int32              `xorm:"NOT NULL"`
+	FirstPaymentYearMonth int32              `xorm:"NOT NULL"`
+	PaymentDay            int32              `xorm:"NOT NULL"`
+	TimezoneUtcOffset     int16              `xorm:"NOT NULL"`
+	PaymentAccountId      int64              `xorm:"NOT NULL"`
+	PrincipalCategoryId   int64              `xorm:"NOT NULL"`
+	InterestCategoryId    int64              `xorm:"NOT NULL"`
+	PrincipalTemplateId   int64              `xorm:"NOT NULL"`
+	InterestTemplateId    int64              `xorm:"NOT NULL"`
+	Comment               string             `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime       int64
+	UpdatedUnixTime       int64
+	DeletedUnixTime       int64
+}
+
+// AmortizationInstallment represents the principal and interest split of one installment of amortization schedule
+type AmortizationInstallment struct {
+	InstallmentNumber  int32
+	YearMonth          int32
+	Payment            int64
+	Principal          int64
+	Interest           int64
+	RemainingPrincipal int64
+}
+
+// AmortizationScheduleListRequest represents all parameters of amortization schedule listing request
+type AmortizationScheduleListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"min=0"`
+}
+
+// AmortizationScheduleGetRequest represents all parameters of amortization schedule getting request
+type AmortizationScheduleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AmortizationScheduleCreateRequest represents all parameters of amortization schedule creation request
+type AmortizationScheduleCreateRequest struct {
+	Name                string             `json:"name" binding:"required,notBlank,max=48"`
+	AccountId           int64              `json:"accountId,string" binding:"required,min=1"`
+	Method              AmortizationMethod `json:"method" binding:"required"`
+	Principal           int64              `json:"principal" binding:"min=1,max=99999999999"`
+	AnnualInterestRate  string             `json:"annualInterestRate" binding:"required"`
+	InstallmentCount    int32              `json:"installmentCount" binding:"min=1,max=600"`
+	FirstPaymentDate    string             `json:"firstPaymentDate" binding:"required"`
+	UtcOffset           int16              `json:"utcOffset" binding:"min=-720,max=840"`
+	PaymentAccountId    int64              `json:"paymentAccountId,string" binding:"required,min=1"`
+	PrincipalCategoryId int64              `json:"principalCategoryId,string" binding:"required,min=1"`
+	InterestCategoryId  int64              `json:"interestCategoryId,string" binding:"min=0"`
+	Comment             string             `json:"comment" binding:"max=255"`
+}
+
+// AmortizationScheduleDeleteRequest represents all parameters of amortization schedule deleting request
+type AmortizationScheduleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AmortizationScheduleInfoResponse represents a view-object of amortization schedule
+type AmortizationScheduleInfoResponse struct {
+	Id                   int64                              `json:"id,string"`
+	Name                 string                             `json:"name"`
+	AccountId            int64                              `json:"accountId,string"`
+	Method               AmortizationMethod                 `json:"method"`
+	Principal            int64                              `json:"principal"`
+	AnnualInterestRate   string                             `json:"annualInterestRate"`
+	InstallmentCount     int32                              `json:"installmentCount"`
+	FirstPaymentDate     string                             `json:"firstPaymentDate"`
+	UtcOffset            int16                              `json:"utcOffset"`
+	PaymentAccountId     int64                              `json:"paymentAccountId,string"`
+	PrincipalCategoryId  int64                              `json:"principalCategoryId,string"`
+	InterestCategoryId   int64                              `json:"interestCategoryId,string"`
+	PrincipalTemplateId  int64                              `json:"principalTemplateId,string"`
+	InterestTemplateId   int64                              `json:"interestTemplateId,string"`
+	Comment              string                             `json:"comment"`
+	PaidInstallmentCount int32                              `json:"paidInstallmentCount"`
+	RemainingPrincipal   int64                              `json:"remainingPrincipal"`
+	TotalInterestPaid    int64                              `json:"totalInterestPaid"`
+	TotalInterest        int64                              `json:"totalInterest"`
+	Installments         []*AmortizationInstallmentResponse `json:"installments,omitempty"`
+	CreatedUnixTime      int64                              `json:"createdTime"`
+	UpdatedUnixTime      int64                              `json:"updatedTime"`
+}
+
+// AmortizationInstallmentResponse represents a view-object of amortization installment
+type AmortizationInstallmentResponse struct {
+	InstallmentNumber  int32  `json:"installmentNumber"`
+	PaymentDate        string `json:"paymentDate"`
+	Payment            int64  `json:"payment"`
+	Principal          int64  `json:"principal"`
+	Interest           int64  `json:"interest"`
+	RemainingPrincipal int64  `json:"remainingPrincipal"`
+	Paid               bool   `json:"paid"`
+}
+
+// GetAnnualInterestRate returns the annual interest rate (in percent) of the amortization schedule
+func (s *AmortizationSchedule) GetAnnualInterestRate() (float64, error) {
+	rate, err := strconv.ParseFloat(s.AnnualInterestRate, 64)
+
+	if err != nil || math.IsNaN(rate) || math.IsInf(rate, 0) || rate < 0 || rate > MaximumAnnualInterestRateOfAmortizationSchedule {
+		return 0, errs.ErrAmortizationAnnualInterestRateInvalid
+	}
+
+	return rate, nil
+}
+
+// GetInstallments returns all installments of the amortization schedule
+func (s *AmortizationSchedule) GetInstallments() ([]*AmortizationInstallment, error) {
+	if !s.Method.IsValid() {
+		return nil, errs.ErrAmortizationMethodInvalid
+	}
+
+	if s.InstallmentCount < 1 || s.InstallmentCount > MaximumInstallmentCountOfAmortizationSchedule {
+		return nil, errs.ErrAmortizationInstallmentCountInvalid
+	}
+
+	annualInterestRate, err := s.GetAnnualInterestRate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthlyInterestRate := annualInterestRate / 100 / 12
+	equalPayment := int64(0)
+
+	if s.Method == AMORTIZATION_METHOD_EQUAL_PAYMENT {
+		if monthlyInterestRate > 0 {
+			compoundRate := math.Pow(1+monthlyInterestRate, float64(s.InstallmentCount))
+			equalPayment = int64(math.Round(float64(s.Principal) * monthlyInterestRate * compoundRate / (compoundRate - 1)))
+		} else {
+			equalPayment = int64(math.Round(float64(s.Principal) / float64(s.InstallmentCount)))
+		}
+	}
+
+	installments := make([]*AmortizationInstallment, s.InstallmentCount)
+	remainingPrincipal := s.Principal
+
+	for i := int32(0); i < s.InstallmentCount; i++ {
+		interest := int64(math.Round(float64(remainingPrincipal) * monthlyInterestRate))
+		principal := int64(0)
+
+		if s.Method == AMORTIZATION_METHOD_EQUAL_PAYMENT {
+			principal = equalPayment - interest
+		} else {
+			principal = s.Principal / int64(s.InstallmentCount)
+		}
+
+		if principal < 0 {
+			principal = 0
+		}
+
+		if i == s.InstallmentCount-1 || principal > remainingPrincipal {
+			principal = remainingPrincipal
+		}
+
+		remainingPrincipal -= principal
+
+		installments[i] = &AmortizationInstallment{
+			InstallmentNumber:  i + 1,
+			YearMonth:          utils.AddMonthsToNumericYearMonth(s.FirstPaymentYearMonth, i),
+			Payment:            principal + interest,
+			Principal:          principal,
+			Interest:           interest,
+			RemainingPrincipal: remainingPrincipal,
+		}
+	}
+
+	return installments, nil
+}
+
+// GetInstallmentNumber returns the installment number which should be paid in the specified year and month, returns 0 if no installment should be paid
+func (s *AmortizationSchedule) GetInstallmentNumber(year int, month time.Month) int32 {
+	firstYear := s.FirstPaymentYearMonth / 100
+	firstMonth := s.FirstPaymentYearMonth % 100
+	installmentNumber := (int32(year)-firstYear)*12 + (int32(month) - firstMonth) + 1
+
+	if installmentNumber < 1 || installmentNumber > s.InstallmentCount {
+		return 0
+	}
+
+	return installmentNumber
+}
+
+// GetInstallmentAmount returns the amount which should be paid by the principal or interest template in the specified year and month, returns 0 if no installment should be paid
+func (s *AmortizationSchedule) GetInstallmentAmount(templateId int64, year int, month time.Month) (int64, error) {
+	installmentNumber := s.GetInstallmentNumber(year, month)
+
+	if installmentNumber < 1 {
+		return 0, nil
+	}
+
+	installments, err := s.GetInstallments()
+
+	if err != nil {
+		return 0, err
+	}
+
+	installment := installments[installmentNumber-1]
+
+	if templateId == s.PrincipalTemplateId {
+		return installment.Principal, nil
+	} else if templateId == s.InterestTemplateId {
+		return installment.Interest, nil
+	}
+
+	return 0, errs.ErrTransactionTemplateNotFound
+}
+
+// GetPaidInstallmentCount returns the count of installments whose payment date is not later than the specified unix time
+func (s *AmortizationSchedule) GetPaidInstallmentCount(currentUnixTime int64) int32 {
+	timezone := time.FixedZone("Schedule Timezone", int(s.TimezoneUtcOffset)*60)
+	currentYearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(currentUnixTime, timezone)
+	paidCount := int32(0)
+
+	for i := int32(0); i < s.InstallmentCount; i++ {
+		paymentYearMonthDay := utils.AddMonthsToNumericYearMonth(s.FirstPaymentYearMonth, i)*100 + s.PaymentDay
+
+		if paymentYearMonthDay > currentYearMonthDay {
+			break
+		}
+
+		paidCount++
+	}
+
+	return paidCount
+}
+
+// GetFirstPaymentDate returns the textual first payment date of the amortization schedule
+func (s *AmortizationSchedule) GetFirstPaymentDate() string {
+	return formatNumericYearMonthDay(s.FirstPaymentYearMonth, s.PaymentDay)
+}
+
+// GetLastPaymentDate returns the textual last payment date of the amortization schedule
+func (s *AmortizationSchedule) GetLastPaymentDate() string {
+	return formatNumericYearMonthDay(utils.AddMonthsToNumericYearMonth(s.FirstPaymentYearMonth, s.InstallmentCount-1), s.PaymentDay)
+}
+
+// ToAmortizationScheduleInfoResponse returns a view-object according to database model
+func (s *AmortizationSchedule) ToAmortizationScheduleInfoResponse(installments []*AmortizationInstallment, paidInstallmentCount int32, includeInstallments bool) *AmortizationScheduleInfoResponse {
+	response := &AmortizationScheduleInfoResponse{
+		Id:                   s.ScheduleId,
+		Name:                 s.Name,
+		AccountId:            s.AccountId,
+		Method:               s.Method,
+		Principal:            s.Principal,
+		AnnualInterestRate:   s.AnnualInterestRate,
+		InstallmentCount:     s.InstallmentCount,
+		FirstPaymentDate:     s.GetFirstPaymentDate(),
+		UtcOffset:            s.TimezoneUtcOffset,
+		PaymentAccountId:     s.PaymentAccountId,
+		PrincipalCategoryId:  s.PrincipalCategoryId,
+		InterestCategoryId:   s.InterestCategoryId,
+		PrincipalTemplateId:  s.PrincipalTemplateId,
+		InterestTemplateId:   s.InterestTemplateId,
+		Comment:              s.Comment,
+		PaidInstallmentCount: paidInstallmentCount,
+		RemainingPrincipal:   s.Principal,
+		CreatedUnixTime:      s.CreatedUnixTime,
+		UpdatedUnixTime:      s.UpdatedUnixTime,
+	}
+
+	if includeInstallments {
+		response.Installments = make([]*AmortizationInstallmentResponse, len(installments))
+	}
+
+	for i := 0; i < len(installments); i++ {
+		installment := installments[i]
+		paid := installment.InstallmentNumber <= paidInstallmentCount
+
+		response.TotalInterest += installment.Interest
+
+		if paid {
+			response.TotalInterestPaid += installment.Interest
+			response.RemainingPrincipal = installment.RemainingPrincipal
+		}
+
+		if includeInstallments {
+			response.Installments[i] = &AmortizationInstallmentResponse{
+				InstallmentNumber:  installment.InstallmentNumber,
+				PaymentDate:        formatNumericYearMonthDay(installment.YearMonth, s.PaymentDay),
+				Payment:            installment.Payment,
+				Principal:          installment.Principal,
+				Interest:           installment.Interest,
+				RemainingPrincipal: installment.RemainingPrincipal,
+				Paid:               paid,
+			}
+		}
+	}
+
+	return response
+}
+
+func formatNumericYearMonthDay(yearMonth int32, day int32) string {
+	return fmt.Sprintf("%s-%02d", formatNumericYearMonth(yearMonth), day)
+}
+
+// AmortizationScheduleInfoResponseSlice represents the slice data structure of AmortizationScheduleInfoResponse
+type AmortizationScheduleInfoResponseSlice []*AmortizationScheduleInfoResponse
+
+// Len returns the count of items
+func (s AmortizationScheduleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s AmortizationScheduleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s AmortizationScheduleInfoResponseSlice) Less(i, j int) bool {
+	if s[i].FirstPaymentDate != s[j].FirstPaymentDate {
+		return s[i].FirstPaymentDate < s[j].FirstPaymentDate
+	}
+
+	return s[i].Id < s[j].Id
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestAmortizationScheduleGetInstallments_EqualPayment(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		Method:                AMORTIZATION_METHOD_EQUAL_PAYMENT,
+		Principal:             1200000,
+		AnnualInterestRate:    "12",
+		InstallmentCount:      12,
+		FirstPaymentYearMonth: 202411,
+		PaymentDay:            15,
+	}
+
+	installments, err := schedule.GetInstallments()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(installments))
+
+	assert.Equal(t, int32(1), installments[0].InstallmentNumber)
+	assert.Equal(t, int32(202411), installments[0].YearMonth)
+	assert.Equal(t, int64(106619), installments[0].Payment)
+	assert.Equal(t, int64(12000), installments[0].Interest)
+	assert.Equal(t, int64(94619), installments[0].Principal)
+	assert.Equal(t, int64(1105381), installments[0].RemainingPrincipal)
+
+	assert.Equal(t, int32(12), installments[11].InstallmentNumber)
+	assert.Equal(t, int32(202510), installments[11].YearMonth)
+	assert.Equal(t, int64(0), installments[11].RemainingPrincipal)
+
+	totalPrincipal := int64(0)
+
+	for i := 0; i < len(installments); i++ {
+		totalPrincipal += installments[i].Principal
+		assert.Equal(t, installments[i].Principal+installments[i].Interest, installments[i].Payment)
+	}
+
+	assert.Equal(t, int64(1200000), totalPrincipal)
+}
+
+func TestAmortizationScheduleGetInstallments_EqualPrincipal(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		Method:                AMORTIZATION_METHOD_EQUAL_PRINCIPAL,
+		Principal:             1200000,
+		AnnualInterestRate:    "12",
+		InstallmentCount:      12,
+		FirstPaymentYearMonth: 202401,
+		PaymentDay:            1,
+	}
+
+	installments, err := schedule.GetInstallments()
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(installments))
+
+	totalInterest := int64(0)
+
+	for i := 0; i < len(installments); i++ {
+		assert.Equal(t, int64(100000), installments[i].Principal)
+		assert.Equal(t, int64(12000-1000*i), installments[i].Interest)
+		totalInterest += installments[i].Interest
+	}
+
+	assert.Equal(t, int64(78000), totalInterest)
+	assert.Equal(t, int64(0), installments[11].RemainingPrincipal)
+}
+
+func TestAmortizationScheduleGetInstallments_ZeroInterestRate(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		Method:                AMORTIZATION_METHOD_EQUAL_PAYMENT,
+		Principal:             100000,
+		AnnualInterestRate:    "0",
+		InstallmentCount:      3,
+		FirstPaymentYearMonth: 202412,
+		PaymentDay:            20,
+	}
+
+	installments, err := schedule.GetInstallments()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(installments))
+	assert.Equal(t, int64(33333), installments[0].Principal)
+	assert.Equal(t, int64(33333), installments[1].Principal)
+	assert.Equal(t, int64(33334), installments[2].Principal)
+	assert.Equal(t, int64(0), installments[2].Interest)
+	assert.Equal(t, int32(202502), installments[2].YearMonth)
+}
+
+func TestAmortizationScheduleGetInstallments_InvalidParameters(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		Method:             AMORTIZATION_METHOD_EQUAL_PAYMENT,
+		Principal:          100000,
+		AnnualInterestRate: "abc",
+		InstallmentCount:   3,
+	}
+
+	_, err := schedule.GetInstallments()
+	assert.EqualError(t, err, errs.ErrAmortizationAnnualInterestRateInvalid.Message)
+
+	schedule.AnnualInterestRate = "-1"
+	_, err = schedule.GetInstallments()
+	assert.EqualError(t, err, errs.ErrAmortizationAnnualInterestRateInvalid.Message)
+
+	schedule.AnnualInterestRate = "3.5"
+	schedule.Method = 0
+	_, err = schedule.GetInstallments()
+	assert.EqualError(t, err, errs.ErrAmortizationMethodInvalid.Message)
+
+	schedule.Method = AMORTIZATION_METHOD_EQUAL_PRINCIPAL
+	schedule.InstallmentCount = 0
+	_, err = schedule.GetInstallments()
+	assert.EqualError(t, err, errs.ErrAmortizationInstallmentCountInvalid.Message)
+}
+
+func TestAmortizationScheduleGetInstallmentNumber(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		InstallmentCount:      12,
+		FirstPaymentYearMonth: 202411,
+	}
+
+	assert.Equal(t, int32(0), schedule.GetInstallmentNumber(2024, time.October))
+	assert.Equal(t, int32(1), schedule.GetInstallmentNumber(2024, time.November))
+	assert.Equal(t, int32(3), schedule.GetInstallmentNumber(2025, time.January))
+	assert.Equal(t, int32(12), schedule.GetInstallmentNumber(2025, time.October))
+	assert.Equal(t, int32(0), schedule.GetInstallmentNumber(2025, time.November))
+}
+
+func TestAmortizationScheduleGetPaidInstallmentCount(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		InstallmentCount:      12,
+		FirstPaymentYearMonth: 202411,
+		PaymentDay:            15,
+		TimezoneUtcOffset:     480,
+	}
+
+	assert.Equal(t, int32(0), schedule.GetPaidInstallmentCount(time.Date(2024, 11, 14, 23, 59, 59, 0, time.FixedZone("", 480*60)).Unix()))
+	assert.Equal(t, int32(1), schedule.GetPaidInstallmentCount(time.Date(2024, 11, 15, 0, 0, 0, 0, time.FixedZone("", 480*60)).Unix()))
+	assert.Equal(t, int32(2), schedule.GetPaidInstallmentCount(time.Date(2025, 1, 14, 0, 0, 0, 0, time.FixedZone("", 480*60)).Unix()))
+	assert.Equal(t, int32(12), schedule.GetPaidInstallmentCount(time.Date(2030, 1, 1, 0, 0, 0, 0, time.FixedZone("", 480*60)).Unix()))
+}
+
+func TestAmortizationScheduleToAmortizationScheduleInfoResponse(t *testing.T) {
+	schedule := &AmortizationSchedule{
+		ScheduleId:            1,
+		Method:                AMORTIZATION_METHOD_EQUAL_PRINCIPAL,
+		Principal:             1200000,
+		AnnualInterestRate:    "12",
+		InstallmentCount:      12,
+		FirstPaymentYearMonth: 202401,
+		PaymentDay:            5,
+	}
+
+	installments, err := schedule.GetInstallments()
+	assert.Nil(t, err)
+
+	response := schedule.ToAmortizationScheduleInfoResponse(installments, 3, true)
+	assert.Equal(t, "2024-01-05", response.FirstPaymentDate)
+	assert.Equal(t, int32(3), response.PaidInstallmentCount)
+	assert.Equal(t, int64(900000), response.RemainingPrincipal)
+	assert.Equal(t, int64(33000), response.TotalInterestPaid)
+	assert.Equal(t, int64(78000), response.TotalInterest)
+	assert.Equal(t, 12, len(response.Installments))
+	assert.Equal(t, "2024-03-05", response.Installments[2].PaymentDate)
+	assert.True(t, response.Installments[2].Paid)
+	assert.False(t, response.Installments[3].Paid)
+
+	response = schedule.ToAmortizationScheduleInfoResponse(installments, 0, false)
+	assert.Equal(t, int64(1200000), response.RemainingPrincipal)
+	assert.Equal(t, int64(0), response.TotalInterestPaid)
+	assert.Nil(t, response.Installments)
+}
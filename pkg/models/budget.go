@@ -0,0 +1,252 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetPeriodType represents budget period type
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_MONTHLY     BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_FISCAL_YEAR BudgetPeriodType = 2
+)
+
+// String returns a textual representation of the budget period type enum
+func (t BudgetPeriodType) String() string {
+	switch t {
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		return "Monthly"
+	case BUDGET_PERIOD_TYPE_FISCAL_YEAR:
+		return "Fiscal Year"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// IsValid returns whether the budget period type is valid
+func (t BudgetPeriodType) IsValid() bool {
+	return t == BUDGET_PERIOD_TYPE_MONTHLY || t == BUDGET_PERIOD_TYPE_FISCAL_YEAR
+}
+
+// Budget represents budget data stored in database
+type Budget struct {
+	BudgetId        int64            `xorm:"PK"`
+	Uid             int64            `xorm:"INDEX(IDX_budget_uid_ledger_id_deleted_order) NOT NULL"`
+	LedgerId        int64            `xorm:"INDEX(IDX_budget_uid_ledger_id_deleted_order) NOT NULL DEFAULT 0"`
+	Deleted         bool             `xorm:"INDEX(IDX_budget_uid_ledger_id_deleted_order) NOT NULL"`
+	Name            string           `xorm:"VARCHAR(64) NOT NULL"`
+	PeriodType      BudgetPeriodType `xorm:"NOT NULL"`
+	CategoryId      int64            `xorm:"NOT NULL"`
+	AccountId       int64            `xorm:"NOT NULL"`
+	Currency        string           `xorm:"VARCHAR(3) NOT NULL"`
+	Amount          int64            `xorm:"NOT NULL"`
+	Rollover        bool             `xorm:"NOT NULL"`
+	StartYearMonth  int32            `xorm:"NOT NULL"`
+	DisplayOrder    int32            `xorm:"INDEX(IDX_budget_uid_ledger_id_deleted_order) NOT NULL"`
+	Hidden          bool             `xorm:"NOT NULL"`
+	Comment         string           `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BudgetUsage represents the planned, rollover and actual amounts of a budget in one period
+type BudgetUsage struct {
+	BudgetId             int64
+	PeriodStartYearMonth int32
+	PeriodEndYearMonth   int32
+	PlannedAmount        int64
+	RolloverAmount       int64
+	ActualAmount         int64
+}
+
+// BudgetListRequest represents all parameters of budget listing request
+type BudgetListRequest struct {
+	VisibleOnly bool `form:"visible_only"`
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	Name            string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType      BudgetPeriodType `json:"periodType" binding:"required"`
+	CategoryId      int64            `json:"categoryId,string" binding:"min=0"`
+	AccountId       int64            `json:"accountId,string" binding:"min=0"`
+	Currency        string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount          int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Rollover        bool             `json:"rollover"`
+	StartYearMonth  string           `json:"startYearMonth" binding:"required"`
+	Comment         string           `json:"comment" binding:"max=255"`
+	ClientSessionId string           `json:"clientSessionId"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id             int64            `json:"id,string" binding:"required,min=1"`
+	Name           string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType     BudgetPeriodType `json:"periodType" binding:"required"`
+	CategoryId     int64            `json:"categoryId,string" binding:"min=0"`
+	AccountId      int64            `json:"accountId,string" binding:"min=0"`
+	Currency       string           `json:"currency" binding:"required,len=3,validCurrency"`
+	Amount         int64            `json:"amount" binding:"min=1,max=99999999999"`
+	Rollover       bool             `json:"rollover"`
+	StartYearMonth string           `json:"startYearMonth" binding:"required"`
+	Comment        string           `json:"comment" binding:"max=255"`
+}
+
+// BudgetHideRequest represents all parameters of budget hiding request
+type BudgetHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// BudgetMoveRequest represents all parameters of budget moving request
+type BudgetMoveRequest struct {
+	NewDisplayOrders []*BudgetNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// BudgetNewDisplayOrderRequest represents a data pair of id and display order
+type BudgetNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetUsageListRequest represents all parameters of budget usage listing request
+type BudgetUsageListRequest struct {
+	YearMonth              string `form:"year_month" binding:"required"`
+	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id             int64            `json:"id,string"`
+	Name           string           `json:"name"`
+	PeriodType     BudgetPeriodType `json:"periodType"`
+	CategoryId     int64            `json:"categoryId,string"`
+	AccountId      int64            `json:"accountId,string"`
+	Currency       string           `json:"currency"`
+	Amount         int64            `json:"amount"`
+	Rollover       bool             `json:"rollover"`
+	StartYearMonth string           `json:"startYearMonth"`
+	DisplayOrder   int32            `json:"displayOrder"`
+	Hidden         bool             `json:"hidden"`
+	Comment        string           `json:"comment"`
+}
+
+// BudgetUsageResponse represents a view-object of budget usage in one period
+type BudgetUsageResponse struct {
+	BudgetId             int64  `json:"budgetId,string"`
+	PeriodStartYearMonth string `json:"periodStartYearMonth"`
+	PeriodEndYearMonth   string `json:"periodEndYearMonth"`
+	PlannedAmount        int64  `json:"plannedAmount"`
+	RolloverAmount       int64  `json:"rolloverAmount"`
+	AvailableAmount      int64  `json:"availableAmount"`
+	ActualAmount         int64  `json:"actualAmount"`
+	RemainingAmount      int64  `json:"remainingAmount"`
+}
+
+// GetPeriodStartYearMonth returns the numeric start year and month of the budget period which contains the specified year and month
+func (b *Budget) GetPeriodStartYearMonth(yearMonth int32, fiscalYearStart core.FiscalYearStart) int32 {
+	if b.PeriodType != BUDGET_PERIOD_TYPE_FISCAL_YEAR {
+		return yearMonth
+	}
+
+	fiscalYearStartMonth, _, err := fiscalYearStart.GetMonthDay()
+
+	if err != nil {
+		fiscalYearStartMonth = 1
+	}
+
+	year := yearMonth / 100
+	month := yearMonth % 100
+
+	if month < int32(fiscalYearStartMonth) {
+		year--
+	}
+
+	return year*100 + int32(fiscalYearStartMonth)
+}
+
+// GetPeriodEndYearMonth returns the numeric end year and month of the budget period which starts at the specified year and month
+func (b *Budget) GetPeriodEndYearMonth(periodStartYearMonth int32) int32 {
+	return utils.AddMonthsToNumericYearMonth(periodStartYearMonth, b.GetPeriodMonthCount()-1)
+}
+
+// GetPeriodMonthCount returns the count of months in one budget period
+func (b *Budget) GetPeriodMonthCount() int32 {
+	if b.PeriodType == BUDGET_PERIOD_TYPE_FISCAL_YEAR {
+		return 12
+	}
+
+	return 1
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:             b.BudgetId,
+		Name:           b.Name,
+		PeriodType:     b.PeriodType,
+		CategoryId:     b.CategoryId,
+		AccountId:      b.AccountId,
+		Currency:       b.Currency,
+		Amount:         b.Amount,
+		Rollover:       b.Rollover,
+		StartYearMonth: formatNumericYearMonth(b.StartYearMonth),
+		DisplayOrder:   b.DisplayOrder,
+		Hidden:         b.Hidden,
+		Comment:        b.Comment,
+	}
+}
+
+// ToBudgetUsageResponse returns a view-object according to budget usage
+func (u *BudgetUsage) ToBudgetUsageResponse() *BudgetUsageResponse {
+	availableAmount := u.PlannedAmount + u.RolloverAmount
+
+	return &BudgetUsageResponse{
+		BudgetId:             u.BudgetId,
+		PeriodStartYearMonth: formatNumericYearMonth(u.PeriodStartYearMonth),
+		PeriodEndYearMonth:   formatNumericYearMonth(u.PeriodEndYearMonth),
+		PlannedAmount:        u.PlannedAmount,
+		RolloverAmount:       u.RolloverAmount,
+		AvailableAmount:      availableAmount,
+		ActualAmount:         u.ActualAmount,
+		RemainingAmount:      availableAmount - u.ActualAmount,
+	}
+}
+
+func formatNumericYearMonth(yearMonth int32) string {
+	return fmt.Sprintf("%04d-%02d", yearMonth/100, yearMonth%100)
+}
+
+// BudgetInfoResponseSlice represents the slice data structure of BudgetInfoResponse
+type BudgetInfoResponseSlice []*BudgetInfoResponse
+
+// Len returns the count of items
+func (s BudgetInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestBudgetGetPeriodStartYearMonth_Monthly(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_MONTHLY,
+	}
+
+	assert.Equal(t, int32(202403), budget.GetPeriodStartYearMonth(202403, core.FISCAL_YEAR_START_DEFAULT))
+	assert.Equal(t, int32(202403), budget.GetPeriodEndYearMonth(202403))
+}
+
+func TestBudgetGetPeriodStartYearMonth_FiscalYear(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_FISCAL_YEAR,
+	}
+
+	assert.Equal(t, int32(202401), budget.GetPeriodStartYearMonth(202403, core.FISCAL_YEAR_START_DEFAULT))
+	assert.Equal(t, int32(202412), budget.GetPeriodEndYearMonth(202401))
+
+	fiscalYearStart, _ := core.NewFiscalYearStart(4, 6)
+	assert.Equal(t, int32(202304), budget.GetPeriodStartYearMonth(202403, fiscalYearStart))
+	assert.Equal(t, int32(202404), budget.GetPeriodStartYearMonth(202404, fiscalYearStart))
+	assert.Equal(t, int32(202404), budget.GetPeriodStartYearMonth(202503, fiscalYearStart))
+	assert.Equal(t, int32(202503), budget.GetPeriodEndYearMonth(202404))
+}
+
+func TestBudgetUsageToBudgetUsageResponse(t *testing.T) {
+	usage := &BudgetUsage{
+		BudgetId:             1,
+		PeriodStartYearMonth: 202404,
+		PeriodEndYearMonth:   202503,
+		PlannedAmount:        10000,
+		RolloverAmount:       2500,
+		ActualAmount:         11000,
+	}
+
+	actualResponse := usage.ToBudgetUsageResponse()
+
+	assert.Equal(t, "2024-04", actualResponse.PeriodStartYearMonth)
+	assert.Equal(t, "2025-03", actualResponse.PeriodEndYearMonth)
+	assert.Equal(t, int64(12500), actualResponse.AvailableAmount)
+	assert.Equal(t, int64(1500), actualResponse.RemainingAmount)
+}
+
+func TestBudgetInfoResponseSliceLess(t *testing.T) {
+	var budgetRespSlice BudgetInfoResponseSlice
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(budgetRespSlice)
+
+	assert.Equal(t, int64(2), budgetRespSlice[0].Id)
+	assert.Equal(t, int64(3), budgetRespSlice[1].Id)
+	assert.Equal(t, int64(1), budgetRespSlice[2].Id)
+}
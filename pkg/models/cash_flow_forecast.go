@@ -0,0 +1,203 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionCashFlowForecastRequest represents all parameters of transaction cash flow forecast request
+type TransactionCashFlowForecastRequest struct {
+	Months                   int32 `form:"months" binding:"required,min=3,max=12"`
+	IncludeHistoricalAverage bool  `form:"include_historical_average"`
+	HistoricalMonths         int32 `form:"historical_months" binding:"omitempty,min=1,max=24"`
+}
+
+// TransactionCashFlowForecastResponse represents the projected daily balances of all accounts
+type TransactionCashFlowForecastResponse struct {
+	StartDate          string                                                  `json:"startDate"`
+	EndDate            string                                                  `json:"endDate"`
+	Accounts           []*TransactionCashFlowForecastAccountResponse           `json:"accounts"`
+	HistoricalAverages []*TransactionCashFlowForecastHistoricalAverageResponse `json:"historicalAverages,omitempty"`
+}
+
+// TransactionCashFlowForecastAccountResponse represents the projected daily balances of one account
+type TransactionCashFlowForecastAccountResponse struct {
+	AccountId            int64                                       `json:"accountId,string"`
+	Currency             string                                      `json:"currency"`
+	CurrentBalance       int64                                       `json:"currentBalance"`
+	Items                []*TransactionCashFlowForecastDailyResponse `json:"items"`
+	NegativeBalanceDates []string                                    `json:"negativeBalanceDates"`
+}
+
+// TransactionCashFlowForecastDailyResponse represents the projected balance of one account at the end of one day
+type TransactionCashFlowForecastDailyResponse struct {
+	Date                    string `json:"date"`
+	ScheduledAmount         int64  `json:"scheduledAmount"`
+	HistoricalAverageAmount int64  `json:"historicalAverageAmount"`
+	Balance                 int64  `json:"balance"`
+}
+
+// TransactionCashFlowForecastHistoricalAverageResponse represents the average monthly amount of one category in one account in the past months
+type TransactionCashFlowForecastHistoricalAverageResponse struct {
+	AccountId     int64           `json:"accountId,string"`
+	CategoryId    int64           `json:"categoryId,string"`
+	Type          TransactionType `json:"type"`
+	MonthlyAmount int64           `json:"monthlyAmount"`
+}
+
+// CashFlowForecast represents the projected daily amount changes of all accounts in the forecast days
+type CashFlowForecast struct {
+	startTime                time.Time
+	days                     int
+	scheduledAmounts         map[int64][]int64
+	historicalAverageAmounts map[int64][]int64
+	historicalAverages       []*TransactionCashFlowForecastHistoricalAverageResponse
+}
+
+// NewCashFlowForecast returns a new cash flow forecast which starts from the day of the start time and ends at the day before the end time
+func NewCashFlowForecast(startTime time.Time, endTime time.Time) *CashFlowForecast {
+	startTime = time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
+	days := 0
+
+	for date := startTime; date.Before(endTime); date = date.AddDate(0, 0, 1) {
+		days++
+	}
+
+	return &CashFlowForecast{
+		startTime:                startTime,
+		days:                     days,
+		scheduledAmounts:         make(map[int64][]int64),
+		historicalAverageAmounts: make(map[int64][]int64),
+		historicalAverages:       make([]*TransactionCashFlowForecastHistoricalAverageResponse, 0),
+	}
+}
+
+// GetDays returns the count of forecast days
+func (f *CashFlowForecast) GetDays() int {
+	return f.days
+}
+
+// GetStartUnixTime returns the unix time of the beginning of the first forecast day
+func (f *CashFlowForecast) GetStartUnixTime() int64 {
+	return f.startTime.Unix()
+}
+
+// GetEndUnixTime returns the unix time of the end of the last forecast day
+func (f *CashFlowForecast) GetEndUnixTime() int64 {
+	return f.startTime.AddDate(0, 0, f.days).Unix() - 1
+}
+
+// AddScheduledAmount adds the amount of one scheduled transaction at the specified unix time to the account, the amount is ignored if it is out of the forecast days
+func (f *CashFlowForecast) AddScheduledAmount(accountId int64, unixTime int64, amount int64) {
+	dayIndex := f.getDayIndex(unixTime)
+
+	if dayIndex < 0 || dayIndex >= f.days {
+		return
+	}
+
+	amounts, exists := f.scheduledAmounts[accountId]
+
+	if !exists {
+		amounts = make([]int64, f.days)
+		f.scheduledAmounts[accountId] = amounts
+	}
+
+	amounts[dayIndex] += amount
+}
+
+// AddHistoricalAverageAmount spreads the total amount of one category in the past months evenly across all forecast days of the account
+func (f *CashFlowForecast) AddHistoricalAverageAmount(accountId int64, categoryId int64, transactionType TransactionType, totalAmount int64, historicalMonths int32, historicalDays int) {
+	if totalAmount == 0 || historicalMonths < 1 || historicalDays < 1 {
+		return
+	}
+
+	amounts, exists := f.historicalAverageAmounts[accountId]
+
+	if !exists {
+		amounts = make([]int64, f.days)
+		f.historicalAverageAmounts[accountId] = amounts
+	}
+
+	sign := int64(1)
+
+	if transactionType == TRANSACTION_TYPE_EXPENSE {
+		sign = -1
+	}
+
+	for i := 0; i < f.days; i++ {
+		amounts[i] += sign * (totalAmount*int64(i+1)/int64(historicalDays) - totalAmount*int64(i)/int64(historicalDays))
+	}
+
+	f.historicalAverages = append(f.historicalAverages, &TransactionCashFlowForecastHistoricalAverageResponse{
+		AccountId:     accountId,
+		CategoryId:    categoryId,
+		Type:          transactionType,
+		MonthlyAmount: totalAmount / int64(historicalMonths),
+	})
+}
+
+// ToTransactionCashFlowForecastResponse returns a view-object of the projected daily balances of the accounts, which start from the current balances
+func (f *CashFlowForecast) ToTransactionCashFlowForecastResponse(accounts []*Account) *TransactionCashFlowForecastResponse {
+	response := &TransactionCashFlowForecastResponse{
+		StartDate: utils.FormatUnixTimeToLongDate(f.startTime.Unix(), f.startTime.Location()),
+		EndDate:   utils.FormatUnixTimeToLongDate(f.startTime.AddDate(0, 0, f.days-1).Unix(), f.startTime.Location()),
+		Accounts:  make([]*TransactionCashFlowForecastAccountResponse, 0, len(accounts)),
+	}
+
+	if len(f.historicalAverages) > 0 {
+		response.HistoricalAverages = f.historicalAverages
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		scheduledAmounts := f.scheduledAmounts[account.AccountId]
+		historicalAverageAmounts := f.historicalAverageAmounts[account.AccountId]
+
+		accountResponse := &TransactionCashFlowForecastAccountResponse{
+			AccountId:            account.AccountId,
+			Currency:             account.Currency,
+			CurrentBalance:       account.Balance,
+			Items:                make([]*TransactionCashFlowForecastDailyResponse, f.days),
+			NegativeBalanceDates: make([]string, 0),
+		}
+
+		balance := account.Balance
+
+		for j := 0; j < f.days; j++ {
+			dailyResponse := &TransactionCashFlowForecastDailyResponse{
+				Date: utils.FormatUnixTimeToLongDate(f.startTime.AddDate(0, 0, j).Unix(), f.startTime.Location()),
+			}
+
+			if scheduledAmounts != nil {
+				dailyResponse.ScheduledAmount = scheduledAmounts[j]
+			}
+
+			if historicalAverageAmounts != nil {
+				dailyResponse.HistoricalAverageAmount = historicalAverageAmounts[j]
+			}
+
+			previousBalance := balance
+			balance += dailyResponse.ScheduledAmount + dailyResponse.HistoricalAverageAmount
+			dailyResponse.Balance = balance
+
+			if previousBalance >= 0 && balance < 0 {
+				accountResponse.NegativeBalanceDates = append(accountResponse.NegativeBalanceDates, dailyResponse.Date)
+			}
+
+			accountResponse.Items[j] = dailyResponse
+		}
+
+		response.Accounts = append(response.Accounts, accountResponse)
+	}
+
+	return response
+}
+
+func (f *CashFlowForecast) getDayIndex(unixTime int64) int {
+	date := time.Unix(unixTime, 0).In(f.startTime.Location())
+	startDate := time.Date(f.startTime.Year(), f.startTime.Month(), f.startTime.Day(), 0, 0, 0, 0, time.UTC)
+	currentDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(currentDate.Sub(startDate).Hours() / 24)
+}
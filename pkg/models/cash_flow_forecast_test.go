@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCashFlowForecast(t *testing.T) {
+	timezone := time.FixedZone("Client Timezone", 480*60)
+	startTime := time.Date(2024, 1, 31, 10, 0, 0, 0, timezone)
+	forecast := NewCashFlowForecast(startTime, time.Date(2024, 4, 30, 0, 0, 0, 0, timezone))
+
+	assert.Equal(t, 90, forecast.GetDays())
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, timezone).Unix(), forecast.GetStartUnixTime())
+	assert.Equal(t, time.Date(2024, 4, 30, 0, 0, 0, 0, timezone).Unix()-1, forecast.GetEndUnixTime())
+}
+
+func TestCashFlowForecastToTransactionCashFlowForecastResponse(t *testing.T) {
+	timezone := time.FixedZone("Client Timezone", 480*60)
+	forecast := NewCashFlowForecast(time.Date(2024, 3, 1, 0, 0, 0, 0, timezone), time.Date(2024, 3, 6, 0, 0, 0, 0, timezone))
+
+	forecast.AddScheduledAmount(1, time.Date(2024, 3, 2, 0, 0, 0, 0, timezone).Unix(), -1500)
+	forecast.AddScheduledAmount(1, time.Date(2024, 3, 4, 23, 59, 59, 0, timezone).Unix(), 2000)
+	forecast.AddScheduledAmount(1, time.Date(2024, 3, 5, 0, 0, 0, 0, timezone).Unix(), -2000)
+	forecast.AddScheduledAmount(1, time.Date(2024, 2, 29, 23, 59, 59, 0, timezone).Unix(), 100000)
+	forecast.AddScheduledAmount(1, time.Date(2024, 3, 6, 0, 0, 0, 0, timezone).Unix(), 100000)
+	forecast.AddHistoricalAverageAmount(2, 10, TRANSACTION_TYPE_EXPENSE, 1000, 1, 3)
+
+	accounts := []*Account{
+		{AccountId: 1, Currency: "USD", Balance: 1000},
+		{AccountId: 2, Currency: "EUR", Balance: 0},
+	}
+
+	actualValue := forecast.ToTransactionCashFlowForecastResponse(accounts)
+
+	assert.Equal(t, "2024-03-01", actualValue.StartDate)
+	assert.Equal(t, "2024-03-05", actualValue.EndDate)
+	assert.Equal(t, 2, len(actualValue.Accounts))
+
+	account1 := actualValue.Accounts[0]
+	assert.Equal(t, int64(1000), account1.CurrentBalance)
+	assert.Equal(t, 5, len(account1.Items))
+	assert.Equal(t, "2024-03-01", account1.Items[0].Date)
+	assert.Equal(t, int64(1000), account1.Items[0].Balance)
+	assert.Equal(t, int64(-1500), account1.Items[1].ScheduledAmount)
+	assert.Equal(t, int64(-500), account1.Items[1].Balance)
+	assert.Equal(t, int64(-500), account1.Items[2].Balance)
+	assert.Equal(t, int64(1500), account1.Items[3].Balance)
+	assert.Equal(t, int64(-500), account1.Items[4].Balance)
+	assert.Equal(t, []string{"2024-03-02", "2024-03-05"}, account1.NegativeBalanceDates)
+
+	account2 := actualValue.Accounts[1]
+	assert.Equal(t, int64(-333), account2.Items[0].HistoricalAverageAmount)
+	assert.Equal(t, int64(-333), account2.Items[1].HistoricalAverageAmount)
+	assert.Equal(t, int64(-334), account2.Items[2].HistoricalAverageAmount)
+	assert.Equal(t, int64(-1000), account2.Items[2].Balance)
+	assert.Equal(t, int64(-1666), account2.Items[4].Balance)
+	assert.Equal(t, []string{"2024-03-01"}, account2.NegativeBalanceDates)
+
+	assert.Equal(t, 1, len(actualValue.HistoricalAverages))
+	assert.Equal(t, int64(10), actualValue.HistoricalAverages[0].CategoryId)
+	assert.Equal(t, int64(1000), actualValue.HistoricalAverages[0].MonthlyAmount)
+}
@@ -19,18 +19,20 @@ type DataStatisticsResponse struct {
 	TotalTransactionCount          int64 `json:"totalTransactionCount,string"`
 	TotalTransactionPictureCount   int64 `json:"totalTransactionPictureCount,string"`
 	TotalInsightsExplorerCount     int64 `json:"totalInsightsExplorerCount,string"`
+	TotalBudgetCount               int64 `json:"totalBudgetCount,string"`
 	TotalTransactionTemplateCount  int64 `json:"totalTransactionTemplateCount,string"`
 	TotalScheduledTransactionCount int64 `json:"totalScheduledTransactionCount,string"`
 }
 
 // ExportTransactionDataRequest represents export transaction request
 type ExportTransactionDataRequest struct {
-	Type         TransactionType `form:"type" binding:"min=0,max=4"`
-	CategoryIds  string          `form:"category_ids"`
-	AccountIds   string          `form:"account_ids"`
-	TagFilter    string          `form:"tag_filter" binding:"validTagFilter"`
-	AmountFilter string          `form:"amount_filter" binding:"validAmountFilter"`
-	Keyword      string          `form:"keyword"`
-	MaxTime      int64           `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
-	MinTime      int64           `form:"min_time" binding:"min=0"` // Unix timestamp in seconds
+	Type                       TransactionType `form:"type" binding:"min=0,max=4"`
+	CategoryIds                string          `form:"category_ids"`
+	AccountIds                 string          `form:"account_ids"`
+	TagFilter                  string          `form:"tag_filter" binding:"validTagFilter"`
+	AmountFilter               string          `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword                    string          `form:"keyword"`
+	MaxTime                    int64           `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
+	MinTime                    int64           `form:"min_time" binding:"min=0"` // Unix timestamp in seconds
+	UseHistoricalExchangeRates bool            `form:"use_historical_exchange_rates"`
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -46,8 +47,9 @@ type LatestExchangeRateResponse struct {
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
 type LatestExchangeRate struct {
-	Currency string `json:"currency"`
-	Rate     string `json:"rate"`
+	Currency   string `json:"currency"`
+	Rate       string `json:"rate"`
+	DataSource string `json:"dataSource,omitempty"`
 }
 
 // ToLatestExchangeRate returns a data pair of currency and exchange rate according to database model
@@ -97,6 +99,100 @@ func CreateUserCustomExchangeRate(uid int64, currency string, exchangeRate strin
 	}, nil
 }
 
+// WithUserCustomExchangeRates returns a copy of the latest exchange rates based on the default currency, in which the rates of the currencies that have user custom exchange rates are overridden and marked with the specified data source
+func (r *LatestExchangeRateResponse) WithUserCustomExchangeRates(customExchangeRates []*UserCustomExchangeRate, defaultCurrency string, customDataSource string) *LatestExchangeRateResponse {
+	defaultCurrencyCustomRate := int64(0)
+
+	for i := 0; i < len(customExchangeRates); i++ {
+		if customExchangeRates[i].Currency == defaultCurrency {
+			defaultCurrencyCustomRate = customExchangeRates[i].Rate
+			break
+		}
+	}
+
+	if defaultCurrencyCustomRate <= 0 {
+		return r
+	}
+
+	defaultCurrencyRate := float64(0)
+
+	if r.BaseCurrency == defaultCurrency {
+		defaultCurrencyRate = 1
+	} else {
+		for i := 0; i < len(r.ExchangeRates); i++ {
+			if r.ExchangeRates[i].Currency != defaultCurrency {
+				continue
+			}
+
+			rate, err := utils.StringToFloat64(r.ExchangeRates[i].Rate)
+
+			if err == nil && rate > 0 {
+				defaultCurrencyRate = rate
+			}
+
+			break
+		}
+	}
+
+	if defaultCurrencyRate <= 0 {
+		return r
+	}
+
+	exchangeRatesMap := make(map[string]*LatestExchangeRate, len(r.ExchangeRates)+1)
+	exchangeRatesMap[r.BaseCurrency] = &LatestExchangeRate{
+		Currency: r.BaseCurrency,
+		Rate:     utils.Float64ToString(1 / defaultCurrencyRate),
+	}
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		exchangeRate := r.ExchangeRates[i]
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		exchangeRatesMap[exchangeRate.Currency] = &LatestExchangeRate{
+			Currency:   exchangeRate.Currency,
+			Rate:       utils.Float64ToString(rate / defaultCurrencyRate),
+			DataSource: exchangeRate.DataSource,
+		}
+	}
+
+	for i := 0; i < len(customExchangeRates); i++ {
+		customExchangeRate := customExchangeRates[i]
+
+		if customExchangeRate.Currency == defaultCurrency || customExchangeRate.Rate <= 0 {
+			continue
+		}
+
+		exchangeRate := customExchangeRate.ToLatestExchangeRate(defaultCurrencyCustomRate)
+		exchangeRate.DataSource = customDataSource
+		exchangeRatesMap[customExchangeRate.Currency] = exchangeRate
+	}
+
+	exchangeRatesMap[defaultCurrency] = &LatestExchangeRate{
+		Currency: defaultCurrency,
+		Rate:     "1",
+	}
+
+	exchangeRates := make(LatestExchangeRateSlice, 0, len(exchangeRatesMap))
+
+	for _, exchangeRate := range exchangeRatesMap {
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+
+	sort.Sort(exchangeRates)
+
+	return &LatestExchangeRateResponse{
+		DataSource:    r.DataSource,
+		ReferenceUrl:  r.ReferenceUrl,
+		UpdateTime:    r.UpdateTime,
+		BaseCurrency:  defaultCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
 // LatestExchangeRateSlice represents the slice data structure of LatestExchangeRate
 type LatestExchangeRateSlice []*LatestExchangeRate
 
@@ -0,0 +1,324 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ExchangeRateSnapshot represents the exchange rate of one currency in one day stored in database
+type ExchangeRateSnapshot struct {
+	Uid             int64  `xorm:"PK NOT NULL"`
+	DataSource      string `xorm:"PK VARCHAR(32) NOT NULL"`
+	RateDate        int32  `xorm:"PK NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(3) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	UpdateUnixTime  int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+}
+
+// ExchangeRatesAtDateRequest represents all parameters of exchange rates at date request
+type ExchangeRatesAtDateRequest struct {
+	Date string `form:"date" binding:"required"`
+}
+
+// ExchangeRatesAtDateResponse returns a view-object which contains the exchange rates in effect on the specified date
+type ExchangeRatesAtDateResponse struct {
+	DataSource    string                  `json:"dataSource"`
+	Date          string                  `json:"date"`
+	UpdateTime    int64                   `json:"updateTime"`
+	BaseCurrency  string                  `json:"baseCurrency"`
+	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+}
+
+// ExchangeRatesConversionResponse represents a view-object of the exchange rates applied when converting amounts into one currency
+type ExchangeRatesConversionResponse struct {
+	Currency                 string                   `json:"currency"`
+	DataSource               string                   `json:"dataSource"`
+	UpdateTime               int64                    `json:"updateTime"`
+	UseHistoricalRates       bool                     `json:"useHistoricalRates"`
+	HistoricalRatesStartDate string                   `json:"historicalRatesStartDate,omitempty"`
+	HistoricalRatesEndDate   string                   `json:"historicalRatesEndDate,omitempty"`
+	ExchangeRates            AppliedExchangeRateSlice `json:"exchangeRates"`
+	UnconvertibleCurrencies  []string                 `json:"unconvertibleCurrencies,omitempty"`
+}
+
+// AppliedExchangeRate represents the amount of the target currency per one unit of the specified currency
+type AppliedExchangeRate struct {
+	Currency   string `json:"currency"`
+	Rate       string `json:"rate"`
+	DataSource string `json:"dataSource,omitempty"`
+}
+
+// TransactionAmountConverter converts the amount of the specified account on the specified numeric date (yyyyMMdd) to another currency
+type TransactionAmountConverter func(accountId int64, yearMonthDay int32, amount int64) int64
+
+// HistoricalExchangeRates provides the exchange rates in effect on the specified date according to the daily snapshots
+type HistoricalExchangeRates struct {
+	dates           []int32
+	dailyRates      map[int32]*exchangeRatesOfDay
+	latestRates     *exchangeRatesOfDay
+	latestRatesDate int32
+}
+
+type exchangeRatesOfDay struct {
+	rates map[string]float64
+}
+
+// CreateExchangeRateSnapshots returns the exchange rate snapshot database models of the specified data source and date according to the latest exchange rates
+func CreateExchangeRateSnapshots(uid int64, dataSource string, rateDate int32, latestExchangeRates *LatestExchangeRateResponse) []*ExchangeRateSnapshot {
+	snapshots := make([]*ExchangeRateSnapshot, 0, len(latestExchangeRates.ExchangeRates)+1)
+	hasBaseCurrency := false
+
+	for i := 0; i < len(latestExchangeRates.ExchangeRates); i++ {
+		exchangeRate := latestExchangeRates.ExchangeRates[i]
+
+		if exchangeRate.Currency == latestExchangeRates.BaseCurrency {
+			hasBaseCurrency = true
+		}
+
+		snapshots = append(snapshots, &ExchangeRateSnapshot{
+			Uid:            uid,
+			DataSource:     dataSource,
+			RateDate:       rateDate,
+			Currency:       exchangeRate.Currency,
+			BaseCurrency:   latestExchangeRates.BaseCurrency,
+			Rate:           exchangeRate.Rate,
+			UpdateUnixTime: latestExchangeRates.UpdateTime,
+		})
+	}
+
+	if !hasBaseCurrency {
+		snapshots = append(snapshots, &ExchangeRateSnapshot{
+			Uid:            uid,
+			DataSource:     dataSource,
+			RateDate:       rateDate,
+			Currency:       latestExchangeRates.BaseCurrency,
+			BaseCurrency:   latestExchangeRates.BaseCurrency,
+			Rate:           "1",
+			UpdateUnixTime: latestExchangeRates.UpdateTime,
+		})
+	}
+
+	return snapshots
+}
+
+// ToExchangeRatesAtDateResponse returns a view-object according to the exchange rate snapshots of one day
+func ToExchangeRatesAtDateResponse(snapshots []*ExchangeRateSnapshot) *ExchangeRatesAtDateResponse {
+	if len(snapshots) < 1 {
+		return nil
+	}
+
+	response := &ExchangeRatesAtDateResponse{
+		DataSource:    snapshots[0].DataSource,
+		Date:          formatNumericYearMonthDay(snapshots[0].RateDate/100, snapshots[0].RateDate%100),
+		UpdateTime:    snapshots[0].UpdateUnixTime,
+		BaseCurrency:  snapshots[0].BaseCurrency,
+		ExchangeRates: make(LatestExchangeRateSlice, len(snapshots)),
+	}
+
+	for i := 0; i < len(snapshots); i++ {
+		response.ExchangeRates[i] = &LatestExchangeRate{
+			Currency: snapshots[i].Currency,
+			Rate:     snapshots[i].Rate,
+		}
+	}
+
+	sort.Sort(response.ExchangeRates)
+
+	return response
+}
+
+// NewHistoricalExchangeRates returns the historical exchange rates according to the daily snapshots, and uses the latest exchange rates for the dates after the last snapshot
+func NewHistoricalExchangeRates(snapshots []*ExchangeRateSnapshot, latestExchangeRates *LatestExchangeRateResponse, latestExchangeRatesDate int32) *HistoricalExchangeRates {
+	historicalExchangeRates := &HistoricalExchangeRates{
+		dates:           make([]int32, 0),
+		dailyRates:      make(map[int32]*exchangeRatesOfDay),
+		latestRatesDate: latestExchangeRatesDate,
+	}
+
+	for i := 0; i < len(snapshots); i++ {
+		snapshot := snapshots[i]
+		ratesOfDay, exists := historicalExchangeRates.dailyRates[snapshot.RateDate]
+
+		if !exists {
+			ratesOfDay = &exchangeRatesOfDay{
+				rates: make(map[string]float64),
+			}
+
+			historicalExchangeRates.dailyRates[snapshot.RateDate] = ratesOfDay
+			historicalExchangeRates.dates = append(historicalExchangeRates.dates, snapshot.RateDate)
+		}
+
+		rate, err := utils.StringToFloat64(snapshot.Rate)
+
+		if err == nil && rate > 0 {
+			ratesOfDay.rates[snapshot.Currency] = rate
+		}
+	}
+
+	sort.Slice(historicalExchangeRates.dates, func(i, j int) bool {
+		return historicalExchangeRates.dates[i] < historicalExchangeRates.dates[j]
+	})
+
+	if latestExchangeRates != nil {
+		historicalExchangeRates.latestRates = &exchangeRatesOfDay{
+			rates: make(map[string]float64, len(latestExchangeRates.ExchangeRates)),
+		}
+
+		for i := 0; i < len(latestExchangeRates.ExchangeRates); i++ {
+			rate, err := utils.StringToFloat64(latestExchangeRates.ExchangeRates[i].Rate)
+
+			if err == nil && rate > 0 {
+				historicalExchangeRates.latestRates.rates[latestExchangeRates.ExchangeRates[i].Currency] = rate
+			}
+		}
+
+		historicalExchangeRates.latestRates.rates[latestExchangeRates.BaseCurrency] = 1
+	}
+
+	return historicalExchangeRates
+}
+
+// ConvertAmount returns the amount converted from one currency to another at the rates in effect on the specified numeric date (yyyyMMdd), and whether the conversion succeeded
+func (h *HistoricalExchangeRates) ConvertAmount(amount int64, fromCurrency string, toCurrency string, yearMonthDay int32) (int64, bool) {
+	if fromCurrency == toCurrency {
+		return amount, true
+	}
+
+	ratesOfDay := h.getExchangeRatesOfDay(yearMonthDay)
+
+	if ratesOfDay == nil {
+		return amount, false
+	}
+
+	fromRate, fromExists := ratesOfDay.rates[fromCurrency]
+	toRate, toExists := ratesOfDay.rates[toCurrency]
+
+	if !fromExists || !toExists {
+		return amount, false
+	}
+
+	return int64(math.Round(float64(amount) / fromRate * toRate)), true
+}
+
+// ToTransactionAmountConverter returns a converter which converts the amount of each account to the target currency
+func (h *HistoricalExchangeRates) ToTransactionAmountConverter(accountCurrencies map[int64]string, targetCurrency string) TransactionAmountConverter {
+	return func(accountId int64, yearMonthDay int32, amount int64) int64 {
+		currency, exists := accountCurrencies[accountId]
+
+		if !exists {
+			return amount
+		}
+
+		convertedAmount, _ := h.ConvertAmount(amount, currency, targetCurrency, yearMonthDay)
+		return convertedAmount
+	}
+}
+
+// ToExchangeRatesConversionResponse returns a view-object of the exchange rates from the specified currencies to the target currency, the rates are the latest ones (or the ones of the last snapshot if the latest exchange rates are unavailable)
+func (h *HistoricalExchangeRates) ToExchangeRatesConversionResponse(latestExchangeRates *LatestExchangeRateResponse, dataSource string, currencies []string, targetCurrency string, useHistoricalRates bool) *ExchangeRatesConversionResponse {
+	response := &ExchangeRatesConversionResponse{
+		Currency:           targetCurrency,
+		DataSource:         dataSource,
+		UseHistoricalRates: useHistoricalRates,
+		ExchangeRates:      make(AppliedExchangeRateSlice, 0, len(currencies)),
+	}
+
+	rateDataSources := make(map[string]string)
+
+	if latestExchangeRates != nil {
+		response.DataSource = latestExchangeRates.DataSource
+		response.UpdateTime = latestExchangeRates.UpdateTime
+
+		for i := 0; i < len(latestExchangeRates.ExchangeRates); i++ {
+			rateDataSources[latestExchangeRates.ExchangeRates[i].Currency] = latestExchangeRates.ExchangeRates[i].DataSource
+		}
+	}
+
+	if useHistoricalRates && len(h.dates) > 0 {
+		firstDate := h.dates[0]
+		lastDate := h.dates[len(h.dates)-1]
+		response.HistoricalRatesStartDate = formatNumericYearMonthDay(firstDate/100, firstDate%100)
+		response.HistoricalRatesEndDate = formatNumericYearMonthDay(lastDate/100, lastDate%100)
+	}
+
+	ratesOfDay := h.getExchangeRatesOfDay(h.latestRatesDate)
+	addedCurrencies := make(map[string]bool, len(currencies))
+
+	for i := 0; i < len(currencies); i++ {
+		currency := currencies[i]
+
+		if currency == targetCurrency || addedCurrencies[currency] {
+			continue
+		}
+
+		addedCurrencies[currency] = true
+
+		if ratesOfDay == nil {
+			response.UnconvertibleCurrencies = append(response.UnconvertibleCurrencies, currency)
+			continue
+		}
+
+		fromRate, fromExists := ratesOfDay.rates[currency]
+		toRate, toExists := ratesOfDay.rates[targetCurrency]
+
+		if !fromExists || !toExists {
+			response.UnconvertibleCurrencies = append(response.UnconvertibleCurrencies, currency)
+			continue
+		}
+
+		response.ExchangeRates = append(response.ExchangeRates, &AppliedExchangeRate{
+			Currency:   currency,
+			Rate:       utils.Float64ToString(toRate / fromRate),
+			DataSource: rateDataSources[currency],
+		})
+	}
+
+	sort.Sort(response.ExchangeRates)
+	sort.Strings(response.UnconvertibleCurrencies)
+
+	return response
+}
+
+func (h *HistoricalExchangeRates) getExchangeRatesOfDay(yearMonthDay int32) *exchangeRatesOfDay {
+	if h.latestRates != nil && (len(h.dates) < 1 || yearMonthDay >= h.latestRatesDate) {
+		return h.latestRates
+	}
+
+	if len(h.dates) < 1 {
+		return nil
+	}
+
+	index := sort.Search(len(h.dates), func(i int) bool {
+		return h.dates[i] > yearMonthDay
+	})
+
+	// use the earliest snapshot for the dates before the first snapshot
+	if index < 1 {
+		return h.dailyRates[h.dates[0]]
+	}
+
+	return h.dailyRates[h.dates[index-1]]
+}
+
+// AppliedExchangeRateSlice represents the slice data structure of AppliedExchangeRate
+type AppliedExchangeRateSlice []*AppliedExchangeRate
+
+// Len returns the count of items
+func (s AppliedExchangeRateSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s AppliedExchangeRateSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s AppliedExchangeRateSlice) Less(i, j int) bool {
+	return strings.Compare(s[i].Currency, s[j].Currency) < 0
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExchangeRateSnapshots_AddBaseCurrency(t *testing.T) {
+	snapshots := CreateExchangeRateSnapshots(0, "euro_central_bank", 20240105, &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		UpdateTime:   1704412800,
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.1"},
+			{Currency: "CNY", Rate: "7.8"},
+		},
+	})
+
+	assert.Equal(t, 3, len(snapshots))
+	assert.Equal(t, "USD", snapshots[0].Currency)
+	assert.Equal(t, "1.1", snapshots[0].Rate)
+	assert.Equal(t, "EUR", snapshots[0].BaseCurrency)
+	assert.Equal(t, int32(20240105), snapshots[0].RateDate)
+	assert.Equal(t, "euro_central_bank", snapshots[0].DataSource)
+	assert.Equal(t, int64(1704412800), snapshots[0].UpdateUnixTime)
+	assert.Equal(t, "EUR", snapshots[2].Currency)
+	assert.Equal(t, "1", snapshots[2].Rate)
+}
+
+func TestToExchangeRatesAtDateResponse(t *testing.T) {
+	response := ToExchangeRatesAtDateResponse([]*ExchangeRateSnapshot{
+		{DataSource: "euro_central_bank", RateDate: 20240105, Currency: "USD", BaseCurrency: "EUR", Rate: "1.1", UpdateUnixTime: 1704412800},
+		{DataSource: "euro_central_bank", RateDate: 20240105, Currency: "CNY", BaseCurrency: "EUR", Rate: "7.8", UpdateUnixTime: 1704412800},
+	})
+
+	assert.Equal(t, "euro_central_bank", response.DataSource)
+	assert.Equal(t, "2024-01-05", response.Date)
+	assert.Equal(t, "EUR", response.BaseCurrency)
+	assert.Equal(t, 2, len(response.ExchangeRates))
+	assert.Equal(t, "CNY", response.ExchangeRates[0].Currency)
+	assert.Equal(t, "USD", response.ExchangeRates[1].Currency)
+
+	assert.Nil(t, ToExchangeRatesAtDateResponse(nil))
+}
+
+func TestHistoricalExchangeRatesConvertAmount(t *testing.T) {
+	historicalExchangeRates := NewHistoricalExchangeRates([]*ExchangeRateSnapshot{
+		{RateDate: 20240101, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240101, Currency: "USD", Rate: "1.1"},
+		{RateDate: 20240201, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240201, Currency: "USD", Rate: "1.25"},
+	}, &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.5"},
+		},
+	}, 20240301)
+
+	amount, success := historicalExchangeRates.ConvertAmount(1100, "USD", "EUR", 20240115)
+	assert.True(t, success)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, success = historicalExchangeRates.ConvertAmount(1000, "EUR", "USD", 20240201)
+	assert.True(t, success)
+	assert.Equal(t, int64(1250), amount)
+
+	amount, success = historicalExchangeRates.ConvertAmount(1000, "EUR", "USD", 20240315)
+	assert.True(t, success)
+	assert.Equal(t, int64(1500), amount)
+
+	amount, success = historicalExchangeRates.ConvertAmount(1000, "EUR", "USD", 20231231)
+	assert.True(t, success)
+	assert.Equal(t, int64(1100), amount)
+
+	amount, success = historicalExchangeRates.ConvertAmount(1000, "EUR", "JPY", 20240115)
+	assert.False(t, success)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, success = historicalExchangeRates.ConvertAmount(1000, "JPY", "JPY", 20240115)
+	assert.True(t, success)
+	assert.Equal(t, int64(1000), amount)
+}
+
+func TestHistoricalExchangeRatesConvertAmount_NoExchangeRates(t *testing.T) {
+	historicalExchangeRates := NewHistoricalExchangeRates(nil, nil, 20240301)
+
+	amount, success := historicalExchangeRates.ConvertAmount(1000, "EUR", "USD", 20240115)
+	assert.False(t, success)
+	assert.Equal(t, int64(1000), amount)
+}
+
+func TestHistoricalExchangeRatesToTransactionAmountConverter(t *testing.T) {
+	historicalExchangeRates := NewHistoricalExchangeRates([]*ExchangeRateSnapshot{
+		{RateDate: 20240101, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240101, Currency: "USD", Rate: "1.1"},
+	}, nil, 20240301)
+
+	converter := historicalExchangeRates.ToTransactionAmountConverter(map[int64]string{
+		1: "USD",
+		2: "EUR",
+	}, "EUR")
+
+	assert.Equal(t, int64(1000), converter(1, 20240115, 1100))
+	assert.Equal(t, int64(1100), converter(2, 20240115, 1100))
+	assert.Equal(t, int64(1100), converter(3, 20240115, 1100))
+}
+
+func TestHistoricalExchangeRatesToExchangeRatesConversionResponse_LatestExchangeRates(t *testing.T) {
+	latestExchangeRates := &LatestExchangeRateResponse{
+		DataSource:   "euro_central_bank",
+		UpdateTime:   1704412800,
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+			{Currency: "CNY", Rate: "8", DataSource: "user_custom"},
+		},
+	}
+	historicalExchangeRates := NewHistoricalExchangeRates(nil, latestExchangeRates, 20240301)
+
+	response := historicalExchangeRates.ToExchangeRatesConversionResponse(latestExchangeRates, "euro_central_bank", []string{"USD", "CNY", "EUR", "CNY", "JPY"}, "EUR", false)
+
+	assert.Equal(t, "EUR", response.Currency)
+	assert.Equal(t, "euro_central_bank", response.DataSource)
+	assert.Equal(t, int64(1704412800), response.UpdateTime)
+	assert.False(t, response.UseHistoricalRates)
+	assert.Equal(t, "", response.HistoricalRatesStartDate)
+	assert.Equal(t, 2, len(response.ExchangeRates))
+	assert.Equal(t, "CNY", response.ExchangeRates[0].Currency)
+	assert.Equal(t, "0.125", response.ExchangeRates[0].Rate)
+	assert.Equal(t, "user_custom", response.ExchangeRates[0].DataSource)
+	assert.Equal(t, "USD", response.ExchangeRates[1].Currency)
+	assert.Equal(t, "0.8", response.ExchangeRates[1].Rate)
+	assert.Equal(t, "", response.ExchangeRates[1].DataSource)
+	assert.Equal(t, []string{"JPY"}, response.UnconvertibleCurrencies)
+}
+
+func TestHistoricalExchangeRatesToExchangeRatesConversionResponse_HistoricalExchangeRatesWithoutLatest(t *testing.T) {
+	historicalExchangeRates := NewHistoricalExchangeRates([]*ExchangeRateSnapshot{
+		{RateDate: 20240101, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240101, Currency: "USD", Rate: "1.1"},
+		{RateDate: 20240201, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240201, Currency: "USD", Rate: "1.25"},
+	}, nil, 20240301)
+
+	response := historicalExchangeRates.ToExchangeRatesConversionResponse(nil, "euro_central_bank", []string{"EUR"}, "USD", true)
+
+	assert.Equal(t, "USD", response.Currency)
+	assert.Equal(t, "euro_central_bank", response.DataSource)
+	assert.Equal(t, int64(0), response.UpdateTime)
+	assert.True(t, response.UseHistoricalRates)
+	assert.Equal(t, "2024-01-01", response.HistoricalRatesStartDate)
+	assert.Equal(t, "2024-02-01", response.HistoricalRatesEndDate)
+	assert.Equal(t, 1, len(response.ExchangeRates))
+	assert.Equal(t, "EUR", response.ExchangeRates[0].Currency)
+	assert.Equal(t, "1.25", response.ExchangeRates[0].Rate)
+	assert.Nil(t, response.UnconvertibleCurrencies)
+}
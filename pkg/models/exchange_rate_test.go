@@ -25,3 +25,51 @@ func TestLatestExchangeRateSliceLess(t *testing.T) {
 	assert.Equal(t, "EUR", latestExchangeRateSlice[1].Currency)
 	assert.Equal(t, "USD", latestExchangeRateSlice[2].Currency)
 }
+
+func TestLatestExchangeRateResponseWithUserCustomExchangeRates(t *testing.T) {
+	latestExchangeRates := &LatestExchangeRateResponse{
+		DataSource:   "euro_central_bank",
+		UpdateTime:   1704412800,
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+			{Currency: "CNY", Rate: "8"},
+			{Currency: "JPY", Rate: "160"},
+		},
+	}
+
+	actualExchangeRates := latestExchangeRates.WithUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "USD", Rate: UserCustomExchangeRateFactorInDatabase},
+		{Currency: "CNY", Rate: 7 * UserCustomExchangeRateFactorInDatabase},
+	}, "USD", "user_custom")
+
+	assert.Equal(t, "euro_central_bank", actualExchangeRates.DataSource)
+	assert.Equal(t, int64(1704412800), actualExchangeRates.UpdateTime)
+	assert.Equal(t, "USD", actualExchangeRates.BaseCurrency)
+	assert.Equal(t, LatestExchangeRateSlice{
+		{Currency: "CNY", Rate: "7", DataSource: "user_custom"},
+		{Currency: "EUR", Rate: "0.8"},
+		{Currency: "JPY", Rate: "128"},
+		{Currency: "USD", Rate: "1"},
+	}, actualExchangeRates.ExchangeRates)
+}
+
+func TestLatestExchangeRateResponseWithUserCustomExchangeRates_NoDefaultCurrencyRate(t *testing.T) {
+	latestExchangeRates := &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.25"},
+		},
+	}
+
+	actualExchangeRates := latestExchangeRates.WithUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "CNY", Rate: 7 * UserCustomExchangeRateFactorInDatabase},
+	}, "USD", "user_custom")
+	assert.Equal(t, latestExchangeRates, actualExchangeRates)
+
+	actualExchangeRates = latestExchangeRates.WithUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "GBP", Rate: UserCustomExchangeRateFactorInDatabase},
+		{Currency: "CNY", Rate: 9 * UserCustomExchangeRateFactorInDatabase},
+	}, "GBP", "user_custom")
+	assert.Equal(t, latestExchangeRates, actualExchangeRates)
+}
@@ -5,10 +5,11 @@ import "encoding/json"
 // InsightsExplorer represents a saved insights explorer configuration
 type InsightsExplorer struct {
 	ExplorerId      int64  `xorm:"PK"`
-	Uid             int64  `xorm:"INDEX(IDX_insights_explorer_uid_deleted_order) NOT NULL"`
-	Deleted         bool   `xorm:"INDEX(IDX_insights_explorer_uid_deleted_order) NOT NULL"`
+	Uid             int64  `xorm:"INDEX(IDX_insights_explorer_uid_ledger_id_deleted_order) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_insights_explorer_uid_ledger_id_deleted_order) NOT NULL DEFAULT 0"`
+	Deleted         bool   `xorm:"INDEX(IDX_insights_explorer_uid_ledger_id_deleted_order) NOT NULL"`
 	Name            string `xorm:"VARCHAR(64) NOT NULL"`
-	DisplayOrder    int32  `xorm:"INDEX(IDX_insights_explorer_uid_deleted_order) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_insights_explorer_uid_ledger_id_deleted_order) NOT NULL"`
 	Data            string `xorm:"MEDIUMBLOB"`
 	Hidden          bool   `xorm:"NOT NULL"`
 	CreatedUnixTime int64
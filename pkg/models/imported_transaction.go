@@ -12,6 +12,7 @@ type ImportTransaction struct {
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	OriginalCounterpartyName           string
 }
 
 // ImportTransactionRequest represents all parameters of the imported transaction data
@@ -52,7 +53,11 @@ type ImportTransactionResponse struct {
 	TagIds                             []string                        `json:"tagIds"`
 	OriginalTagNames                   []string                        `json:"originalTagNames"`
 	Comment                            string                          `json:"comment"`
+	HideAmount                         bool                            `json:"hideAmount"`
+	PayeeId                            int64                           `json:"payeeId,string,omitempty"`
+	OriginalCounterpartyName           string                          `json:"originalCounterpartyName,omitempty"`
 	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+	ReconciliationStatus               TransactionReconciliationStatus `json:"reconciliationStatus"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -95,7 +100,11 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		TagIds:                             t.TagIds,
 		OriginalTagNames:                   t.OriginalTagNames,
 		Comment:                            t.Comment,
+		HideAmount:                         t.HideAmount,
+		PayeeId:                            t.PayeeId,
+		OriginalCounterpartyName:           t.OriginalCounterpartyName,
 		GeoLocation:                        geoLocation,
+		ReconciliationStatus:               t.ReconciliationStatus,
 	}
 }
 
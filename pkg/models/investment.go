@@ -0,0 +1,437 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentQuantityFactorInDatabase represents the factor of security quantity stored in database
+const InvestmentQuantityFactorInDatabase = int64(100000000)
+
+const investmentQuantityDecimalPlaces = 8
+
+// InvestmentTransactionType represents the subtype of transaction in investment account
+type InvestmentTransactionType byte
+
+// Investment transaction types
+const (
+	INVESTMENT_TRANSACTION_TYPE_BUY      InvestmentTransactionType = 1
+	INVESTMENT_TRANSACTION_TYPE_SELL     InvestmentTransactionType = 2
+	INVESTMENT_TRANSACTION_TYPE_DIVIDEND InvestmentTransactionType = 3
+)
+
+// String returns a textual representation of the investment transaction type enum
+func (t InvestmentTransactionType) String() string {
+	switch t {
+	case INVESTMENT_TRANSACTION_TYPE_BUY:
+		return "Buy"
+	case INVESTMENT_TRANSACTION_TYPE_SELL:
+		return "Sell"
+	case INVESTMENT_TRANSACTION_TYPE_DIVIDEND:
+		return "Dividend"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// IsValid returns whether the investment transaction type is valid
+func (t InvestmentTransactionType) IsValid() bool {
+	return t == INVESTMENT_TRANSACTION_TYPE_BUY || t == INVESTMENT_TRANSACTION_TYPE_SELL || t == INVESTMENT_TRANSACTION_TYPE_DIVIDEND
+}
+
+// InvestmentTransaction represents buy, sell or dividend transaction of a security in investment account stored in database
+type InvestmentTransaction struct {
+	InvestmentTransactionId int64                     `xorm:"PK"`
+	Uid                     int64                     `xorm:"INDEX(IDX_investment_transaction_uid_ledger_id_deleted_account_id_time) NOT NULL"`
+	LedgerId                int64                     `xorm:"INDEX(IDX_investment_transaction_uid_ledger_id_deleted_account_id_time) NOT NULL DEFAULT 0"`
+	Deleted                 bool                      `xorm:"INDEX(IDX_investment_transaction_uid_ledger_id_deleted_account_id_time) NOT NULL"`
+	AccountId               int64                     `xorm:"INDEX(IDX_investment_transaction_uid_ledger_id_deleted_account_id_time) NOT NULL"`
+	TransactionTime         int64                     `xorm:"INDEX(IDX_investment_transaction_uid_ledger_id_deleted_account_id_time) NOT NULL"`
+	TimezoneUtcOffset       int16                     `xorm:"NOT NULL"`
+	Type                    InvestmentTransactionType `xorm:"NOT NULL"`
+	Symbol                  string                    `xorm:"VARCHAR(32) NOT NULL"`
+	Quantity                int64                     `xorm:"NOT NULL"`
+	Amount                  int64                     `xorm:"NOT NULL"`
+	Fee                     int64                     `xorm:"NOT NULL"`
+	TransactionId           int64                     `xorm:"NOT NULL"`
+	Comment                 string                    `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime         int64
+	UpdatedUnixTime         int64
+	DeletedUnixTime         int64
+}
+
+// InvestmentLot represents the remaining quantity and cost basis of one buy transaction
+type InvestmentLot struct {
+	InvestmentTransactionId int64
+	AcquiredUnixTime        int64
+	Quantity                int64
+	CostBasis               int64
+}
+
+// InvestmentHolding represents the holding of a security in investment account
+type InvestmentHolding struct {
+	AccountId      int64
+	Symbol         string
+	Quantity       int64
+	CostBasis      int64
+	RealizedGain   int64
+	DividendAmount int64
+	Lots           []*InvestmentLot
+}
+
+// InvestmentAccountValuation represents the market value and unrealized gain of all holdings in investment account
+type InvestmentAccountValuation struct {
+	MarketValue    int64
+	UnrealizedGain int64
+}
+
+// InvestmentHoldingCalculator calculates the holdings of securities with first-in-first-out cost basis lots
+type InvestmentHoldingCalculator struct {
+	holdings map[string]*InvestmentHolding
+}
+
+// InvestmentTransactionListRequest represents all parameters of investment transaction listing request
+type InvestmentTransactionListRequest struct {
+	AccountId int64  `form:"account_id,string" binding:"min=0"`
+	Symbol    string `form:"symbol" binding:"max=32"`
+}
+
+// InvestmentTransactionCreateRequest represents all parameters of investment transaction creation request
+type InvestmentTransactionCreateRequest struct {
+	AccountId  int64                     `json:"accountId,string" binding:"required,min=1"`
+	Type       InvestmentTransactionType `json:"type" binding:"required"`
+	Symbol     string                    `json:"symbol" binding:"required,notBlank,max=32"`
+	Time       int64                     `json:"time" binding:"required,min=1"`
+	UtcOffset  int16                     `json:"utcOffset" binding:"min=-720,max=840"`
+	Quantity   string                    `json:"quantity"`
+	Amount     int64                     `json:"amount" binding:"min=0,max=99999999999"`
+	Fee        int64                     `json:"fee" binding:"min=0,max=99999999999"`
+	CategoryId int64                     `json:"categoryId,string" binding:"min=0"`
+	Comment    string                    `json:"comment" binding:"max=255"`
+}
+
+// InvestmentTransactionDeleteRequest represents all parameters of investment transaction deleting request
+type InvestmentTransactionDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentHoldingListRequest represents all parameters of investment holding listing request
+type InvestmentHoldingListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"min=0"`
+}
+
+// InvestmentTransactionInfoResponse represents a view-object of investment transaction
+type InvestmentTransactionInfoResponse struct {
+	Id            int64                     `json:"id,string"`
+	AccountId     int64                     `json:"accountId,string"`
+	Type          InvestmentTransactionType `json:"type"`
+	Symbol        string                    `json:"symbol"`
+	Time          int64                     `json:"time"`
+	UtcOffset     int16                     `json:"utcOffset"`
+	Quantity      string                    `json:"quantity"`
+	Amount        int64                     `json:"amount"`
+	Fee           int64                     `json:"fee"`
+	TransactionId int64                     `json:"transactionId,string,omitempty"`
+	Comment       string                    `json:"comment"`
+}
+
+// InvestmentHoldingInfoResponse represents a view-object of investment holding
+type InvestmentHoldingInfoResponse struct {
+	AccountId      int64                        `json:"accountId,string"`
+	Symbol         string                       `json:"symbol"`
+	Quantity       string                       `json:"quantity"`
+	CostBasis      int64                        `json:"costBasis"`
+	RealizedGain   int64                        `json:"realizedGain"`
+	DividendAmount int64                        `json:"dividendAmount"`
+	HasPrice       bool                         `json:"hasPrice"`
+	Price          int64                        `json:"price"`
+	MarketValue    int64                        `json:"marketValue"`
+	UnrealizedGain int64                        `json:"unrealizedGain"`
+	Lots           []*InvestmentLotInfoResponse `json:"lots"`
+}
+
+// InvestmentLotInfoResponse represents a view-object of investment cost basis lot
+type InvestmentLotInfoResponse struct {
+	InvestmentTransactionId int64  `json:"investmentTransactionId,string"`
+	AcquiredTime            int64  `json:"acquiredTime"`
+	Quantity                string `json:"quantity"`
+	CostBasis               int64  `json:"costBasis"`
+}
+
+// NewInvestmentHoldingCalculator returns a new investment holding calculator
+func NewInvestmentHoldingCalculator() *InvestmentHoldingCalculator {
+	return &InvestmentHoldingCalculator{
+		holdings: make(map[string]*InvestmentHolding),
+	}
+}
+
+// AddTransaction applies the investment transaction to the holdings, the transactions must be added in ascending order of transaction time
+func (c *InvestmentHoldingCalculator) AddTransaction(transaction *InvestmentTransaction) error {
+	holdingKey := fmt.Sprintf("%d_%s", transaction.AccountId, transaction.Symbol)
+	holding, exists := c.holdings[holdingKey]
+
+	if !exists {
+		holding = &InvestmentHolding{
+			AccountId: transaction.AccountId,
+			Symbol:    transaction.Symbol,
+			Lots:      make([]*InvestmentLot, 0),
+		}
+		c.holdings[holdingKey] = holding
+	}
+
+	if transaction.Type == INVESTMENT_TRANSACTION_TYPE_BUY {
+		costBasis := transaction.Amount + transaction.Fee
+
+		holding.Quantity += transaction.Quantity
+		holding.CostBasis += costBasis
+		holding.Lots = append(holding.Lots, &InvestmentLot{
+			InvestmentTransactionId: transaction.InvestmentTransactionId,
+			AcquiredUnixTime:        transaction.TransactionTime,
+			Quantity:                transaction.Quantity,
+			CostBasis:               costBasis,
+		})
+	} else if transaction.Type == INVESTMENT_TRANSACTION_TYPE_SELL {
+		if transaction.Quantity > holding.Quantity {
+			return errs.ErrInvestmentSellQuantityExceedsHolding
+		}
+
+		remainingQuantity := transaction.Quantity
+		soldCostBasis := int64(0)
+
+		for remainingQuantity > 0 && len(holding.Lots) > 0 {
+			lot := holding.Lots[0]
+
+			if lot.Quantity <= remainingQuantity {
+				remainingQuantity -= lot.Quantity
+				soldCostBasis += lot.CostBasis
+				holding.Lots = holding.Lots[1:]
+				continue
+			}
+
+			lotSoldCostBasis := multiplyAndDivide(lot.CostBasis, remainingQuantity, lot.Quantity)
+			lot.Quantity -= remainingQuantity
+			lot.CostBasis -= lotSoldCostBasis
+			soldCostBasis += lotSoldCostBasis
+			remainingQuantity = 0
+		}
+
+		holding.Quantity -= transaction.Quantity
+		holding.CostBasis -= soldCostBasis
+		holding.RealizedGain += transaction.Amount - transaction.Fee - soldCostBasis
+	} else if transaction.Type == INVESTMENT_TRANSACTION_TYPE_DIVIDEND {
+		holding.DividendAmount += transaction.Amount - transaction.Fee
+	} else {
+		return errs.ErrInvestmentTransactionTypeInvalid
+	}
+
+	return nil
+}
+
+// GetHoldings returns all holdings ordered by account id and symbol
+func (c *InvestmentHoldingCalculator) GetHoldings() []*InvestmentHolding {
+	holdings := make([]*InvestmentHolding, 0, len(c.holdings))
+
+	for _, holding := range c.holdings {
+		holdings = append(holdings, holding)
+	}
+
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].AccountId != holdings[j].AccountId {
+			return holdings[i].AccountId < holdings[j].AccountId
+		}
+
+		return holdings[i].Symbol < holdings[j].Symbol
+	})
+
+	return holdings
+}
+
+// CalculateInvestmentHoldings returns the holdings of all the specified investment transactions which are in ascending order of transaction time
+func CalculateInvestmentHoldings(transactions []*InvestmentTransaction) ([]*InvestmentHolding, error) {
+	calculator := NewInvestmentHoldingCalculator()
+
+	for i := 0; i < len(transactions); i++ {
+		err := calculator.AddTransaction(transactions[i])
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return calculator.GetHoldings(), nil
+}
+
+// GetAccountValuations returns the valuation of each investment account on the specified numeric date (yyyyMMdd), the holding without price is valued at its cost basis
+func (c *InvestmentHoldingCalculator) GetAccountValuations(priceLookup *SecurityPriceLookup, date int32) map[int64]*InvestmentAccountValuation {
+	valuations := make(map[int64]*InvestmentAccountValuation)
+
+	for _, holding := range c.holdings {
+		if holding.Quantity <= 0 {
+			continue
+		}
+
+		valuation, exists := valuations[holding.AccountId]
+
+		if !exists {
+			valuation = &InvestmentAccountValuation{}
+			valuations[holding.AccountId] = valuation
+		}
+
+		marketValue := holding.CostBasis
+
+		if price, hasPrice := priceLookup.GetPrice(holding.Symbol, date); hasPrice {
+			marketValue = holding.GetMarketValue(price)
+		}
+
+		valuation.MarketValue += marketValue
+		valuation.UnrealizedGain += marketValue - holding.CostBasis
+	}
+
+	return valuations
+}
+
+// GetMarketValue returns the market value of the holding according to the specified price per unit
+func (h *InvestmentHolding) GetMarketValue(price int64) int64 {
+	return multiplyAndDivide(h.Quantity, price, InvestmentQuantityFactorInDatabase)
+}
+
+// ToInvestmentHoldingInfoResponse returns a view-object according to the holding and the price per unit
+func (h *InvestmentHolding) ToInvestmentHoldingInfoResponse(price int64, hasPrice bool) *InvestmentHoldingInfoResponse {
+	response := &InvestmentHoldingInfoResponse{
+		AccountId:      h.AccountId,
+		Symbol:         h.Symbol,
+		Quantity:       FormatInvestmentQuantity(h.Quantity),
+		CostBasis:      h.CostBasis,
+		RealizedGain:   h.RealizedGain,
+		DividendAmount: h.DividendAmount,
+		HasPrice:       hasPrice,
+		MarketValue:    h.CostBasis,
+		Lots:           make([]*InvestmentLotInfoResponse, len(h.Lots)),
+	}
+
+	if hasPrice {
+		response.Price = price
+		response.MarketValue = h.GetMarketValue(price)
+		response.UnrealizedGain = response.MarketValue - h.CostBasis
+	}
+
+	for i := 0; i < len(h.Lots); i++ {
+		response.Lots[i] = &InvestmentLotInfoResponse{
+			InvestmentTransactionId: h.Lots[i].InvestmentTransactionId,
+			AcquiredTime:            h.Lots[i].AcquiredUnixTime,
+			Quantity:                FormatInvestmentQuantity(h.Lots[i].Quantity),
+			CostBasis:               h.Lots[i].CostBasis,
+		}
+	}
+
+	return response
+}
+
+// ToInvestmentTransactionInfoResponse returns a view-object according to database model
+func (t *InvestmentTransaction) ToInvestmentTransactionInfoResponse() *InvestmentTransactionInfoResponse {
+	return &InvestmentTransactionInfoResponse{
+		Id:            t.InvestmentTransactionId,
+		AccountId:     t.AccountId,
+		Type:          t.Type,
+		Symbol:        t.Symbol,
+		Time:          t.TransactionTime,
+		UtcOffset:     t.TimezoneUtcOffset,
+		Quantity:      FormatInvestmentQuantity(t.Quantity),
+		Amount:        t.Amount,
+		Fee:           t.Fee,
+		TransactionId: t.TransactionId,
+		Comment:       t.Comment,
+	}
+}
+
+// ParseInvestmentQuantity parses a textual representation of security quantity
+func ParseInvestmentQuantity(quantity string) (int64, error) {
+	quantity = strings.TrimSpace(quantity)
+
+	if len(quantity) < 1 {
+		return 0, nil
+	}
+
+	items := strings.Split(quantity, ".")
+
+	if len(items) > 2 || (len(items) == 2 && len(items[1]) > investmentQuantityDecimalPlaces) {
+		return 0, errs.ErrInvestmentQuantityInvalid
+	}
+
+	integer := int64(0)
+	decimals := int64(0)
+
+	if len(items[0]) > 0 {
+		value, err := utils.StringToInt64(items[0])
+
+		if err != nil || value < 0 || value > 9999999999 || strings.HasPrefix(items[0], "+") {
+			return 0, errs.ErrInvestmentQuantityInvalid
+		}
+
+		integer = value
+	}
+
+	if len(items) == 2 && len(items[1]) > 0 {
+		value, err := utils.StringToInt64(items[1] + strings.Repeat("0", investmentQuantityDecimalPlaces-len(items[1])))
+
+		if err != nil || value < 0 || strings.HasPrefix(items[1], "+") || strings.HasPrefix(items[1], "-") {
+			return 0, errs.ErrInvestmentQuantityInvalid
+		}
+
+		decimals = value
+	}
+
+	return integer*InvestmentQuantityFactorInDatabase + decimals, nil
+}
+
+// FormatInvestmentQuantity returns a textual representation of security quantity
+func FormatInvestmentQuantity(quantity int64) string {
+	integer := quantity / InvestmentQuantityFactorInDatabase
+	decimals := quantity % InvestmentQuantityFactorInDatabase
+
+	if decimals == 0 {
+		return utils.Int64ToString(integer)
+	}
+
+	decimalsText := strings.TrimRight(fmt.Sprintf("%08d", decimals), "0")
+
+	return utils.Int64ToString(integer) + "." + decimalsText
+}
+
+func multiplyAndDivide(value int64, multiplier int64, divisor int64) int64 {
+	if divisor == 0 {
+		return 0
+	}
+
+	result := new(big.Int).Mul(big.NewInt(value), big.NewInt(multiplier))
+	result.Quo(result, big.NewInt(divisor))
+
+	return result.Int64()
+}
+
+// InvestmentTransactionInfoResponseSlice represents the slice data structure of InvestmentTransactionInfoResponse
+type InvestmentTransactionInfoResponseSlice []*InvestmentTransactionInfoResponse
+
+// Len returns the count of items
+func (s InvestmentTransactionInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s InvestmentTransactionInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s InvestmentTransactionInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Time != s[j].Time {
+		return s[i].Time > s[j].Time
+	}
+
+	return s[i].Id > s[j].Id
+}
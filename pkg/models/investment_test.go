@@ -0,0 +1,123 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestInvestmentHoldingCalculatorAddTransaction_FirstInFirstOut(t *testing.T) {
+	holdings, err := CalculateInvestmentHoldings([]*InvestmentTransaction{
+		{InvestmentTransactionId: 1, AccountId: 100, TransactionTime: 1000, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "AAPL", Quantity: 10 * InvestmentQuantityFactorInDatabase, Amount: 100000, Fee: 500},
+		{InvestmentTransactionId: 2, AccountId: 100, TransactionTime: 2000, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "AAPL", Quantity: 10 * InvestmentQuantityFactorInDatabase, Amount: 120000, Fee: 0},
+		{InvestmentTransactionId: 3, AccountId: 100, TransactionTime: 3000, Type: INVESTMENT_TRANSACTION_TYPE_SELL, Symbol: "AAPL", Quantity: 15 * InvestmentQuantityFactorInDatabase, Amount: 180000, Fee: 1000},
+		{InvestmentTransactionId: 4, AccountId: 100, TransactionTime: 4000, Type: INVESTMENT_TRANSACTION_TYPE_DIVIDEND, Symbol: "AAPL", Amount: 2000, Fee: 100},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(holdings))
+
+	holding := holdings[0]
+	assert.Equal(t, int64(100), holding.AccountId)
+	assert.Equal(t, "AAPL", holding.Symbol)
+	assert.Equal(t, 5*InvestmentQuantityFactorInDatabase, holding.Quantity)
+	assert.Equal(t, int64(60000), holding.CostBasis)
+	assert.Equal(t, int64(180000-1000-100500-60000), holding.RealizedGain)
+	assert.Equal(t, int64(1900), holding.DividendAmount)
+
+	assert.Equal(t, 1, len(holding.Lots))
+	assert.Equal(t, int64(2), holding.Lots[0].InvestmentTransactionId)
+	assert.Equal(t, 5*InvestmentQuantityFactorInDatabase, holding.Lots[0].Quantity)
+	assert.Equal(t, int64(60000), holding.Lots[0].CostBasis)
+}
+
+func TestInvestmentHoldingCalculatorAddTransaction_SellQuantityExceedsHolding(t *testing.T) {
+	_, err := CalculateInvestmentHoldings([]*InvestmentTransaction{
+		{InvestmentTransactionId: 1, AccountId: 100, TransactionTime: 1000, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "AAPL", Quantity: 10 * InvestmentQuantityFactorInDatabase, Amount: 100000},
+		{InvestmentTransactionId: 2, AccountId: 100, TransactionTime: 2000, Type: INVESTMENT_TRANSACTION_TYPE_SELL, Symbol: "AAPL", Quantity: 11 * InvestmentQuantityFactorInDatabase, Amount: 110000},
+	})
+	assert.Equal(t, errs.ErrInvestmentSellQuantityExceedsHolding, err)
+
+	_, err = CalculateInvestmentHoldings([]*InvestmentTransaction{
+		{InvestmentTransactionId: 1, AccountId: 100, TransactionTime: 1000, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "AAPL", Quantity: 10 * InvestmentQuantityFactorInDatabase, Amount: 100000},
+		{InvestmentTransactionId: 2, AccountId: 200, TransactionTime: 2000, Type: INVESTMENT_TRANSACTION_TYPE_SELL, Symbol: "AAPL", Quantity: 1 * InvestmentQuantityFactorInDatabase, Amount: 10000},
+	})
+	assert.Equal(t, errs.ErrInvestmentSellQuantityExceedsHolding, err)
+}
+
+func TestInvestmentHoldingCalculatorGetAccountValuations(t *testing.T) {
+	calculator := NewInvestmentHoldingCalculator()
+	assert.Nil(t, calculator.AddTransaction(&InvestmentTransaction{InvestmentTransactionId: 1, AccountId: 100, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "AAPL", Quantity: 10 * InvestmentQuantityFactorInDatabase, Amount: 100000}))
+	assert.Nil(t, calculator.AddTransaction(&InvestmentTransaction{InvestmentTransactionId: 2, AccountId: 100, Type: INVESTMENT_TRANSACTION_TYPE_BUY, Symbol: "MSFT", Quantity: InvestmentQuantityFactorInDatabase / 2, Amount: 20000}))
+
+	priceLookup := NewSecurityPriceLookup([]*SecurityPrice{
+		{Symbol: "AAPL", PriceDate: 20240102, Price: 12000},
+		{Symbol: "AAPL", PriceDate: 20240101, Price: 11000},
+	})
+
+	valuations := calculator.GetAccountValuations(priceLookup, 20231231)
+	assert.Equal(t, int64(120000), valuations[100].MarketValue)
+	assert.Equal(t, int64(0), valuations[100].UnrealizedGain)
+
+	valuations = calculator.GetAccountValuations(priceLookup, 20240101)
+	assert.Equal(t, int64(110000+20000), valuations[100].MarketValue)
+	assert.Equal(t, int64(10000), valuations[100].UnrealizedGain)
+
+	valuations = calculator.GetAccountValuations(priceLookup, 20240315)
+	assert.Equal(t, int64(120000+20000), valuations[100].MarketValue)
+	assert.Equal(t, int64(20000), valuations[100].UnrealizedGain)
+}
+
+func TestInvestmentHoldingToInvestmentHoldingInfoResponse(t *testing.T) {
+	holding := &InvestmentHolding{
+		AccountId: 100,
+		Symbol:    "AAPL",
+		Quantity:  250000000,
+		CostBasis: 30000,
+		Lots:      []*InvestmentLot{{InvestmentTransactionId: 1, AcquiredUnixTime: 1000, Quantity: 250000000, CostBasis: 30000}},
+	}
+
+	response := holding.ToInvestmentHoldingInfoResponse(10000, true)
+	assert.Equal(t, "2.5", response.Quantity)
+	assert.Equal(t, int64(25000), response.MarketValue)
+	assert.Equal(t, int64(-5000), response.UnrealizedGain)
+	assert.Equal(t, "2.5", response.Lots[0].Quantity)
+
+	response = holding.ToInvestmentHoldingInfoResponse(0, false)
+	assert.False(t, response.HasPrice)
+	assert.Equal(t, int64(30000), response.MarketValue)
+	assert.Equal(t, int64(0), response.UnrealizedGain)
+}
+
+func TestParseInvestmentQuantity(t *testing.T) {
+	quantity, err := ParseInvestmentQuantity("12.345")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1234500000), quantity)
+
+	quantity, err = ParseInvestmentQuantity("0.00000001")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), quantity)
+
+	quantity, err = ParseInvestmentQuantity("7")
+	assert.Nil(t, err)
+	assert.Equal(t, 7*InvestmentQuantityFactorInDatabase, quantity)
+
+	_, err = ParseInvestmentQuantity("1.000000001")
+	assert.Equal(t, errs.ErrInvestmentQuantityInvalid, err)
+
+	_, err = ParseInvestmentQuantity("-1")
+	assert.Equal(t, errs.ErrInvestmentQuantityInvalid, err)
+
+	_, err = ParseInvestmentQuantity("1.-5")
+	assert.Equal(t, errs.ErrInvestmentQuantityInvalid, err)
+
+	_, err = ParseInvestmentQuantity("abc")
+	assert.Equal(t, errs.ErrInvestmentQuantityInvalid, err)
+}
+
+func TestFormatInvestmentQuantity(t *testing.T) {
+	assert.Equal(t, "12.345", FormatInvestmentQuantity(1234500000))
+	assert.Equal(t, "0.00000001", FormatInvestmentQuantity(1))
+	assert.Equal(t, "7", FormatInvestmentQuantity(7*InvestmentQuantityFactorInDatabase))
+}
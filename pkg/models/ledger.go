@@ -0,0 +1,108 @@
+package models
+
+// DefaultLedgerId represents the ledger id of the default ledger which every user owns implicitly
+const DefaultLedgerId int64 = 0
+
+// Ledger represents ledger data stored in database
+type Ledger struct {
+	LedgerId                  int64  `xorm:"PK"`
+	Uid                       int64  `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	Deleted                   bool   `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	Name                      string `xorm:"VARCHAR(64) NOT NULL"`
+	DisplayOrder              int32  `xorm:"INDEX(IDX_ledger_uid_deleted_order) NOT NULL"`
+	Hidden                    bool   `xorm:"NOT NULL"`
+	Comment                   string `xorm:"VARCHAR(255) NOT NULL"`
+	TransactionLockOverridden bool
+	TransactionLockTime       int64 `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnixTime           int64
+	UpdatedUnixTime           int64
+	DeletedUnixTime           int64
+}
+
+// LedgerListRequest represents all parameters of ledger listing request
+type LedgerListRequest struct {
+	VisibleOnly bool `form:"visible_only"`
+}
+
+// LedgerGetRequest represents all parameters of ledger getting request
+type LedgerGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// LedgerCreateRequest represents all parameters of ledger creation request
+type LedgerCreateRequest struct {
+	Name            string `json:"name" binding:"required,notBlank,max=64"`
+	Comment         string `json:"comment" binding:"max=255"`
+	ClientSessionId string `json:"clientSessionId"`
+}
+
+// LedgerModifyRequest represents all parameters of ledger modification request
+type LedgerModifyRequest struct {
+	Id      int64  `json:"id,string" binding:"required,min=1"`
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// LedgerHideRequest represents all parameters of ledger hiding request
+type LedgerHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// LedgerMoveRequest represents all parameters of ledger moving request
+type LedgerMoveRequest struct {
+	NewDisplayOrders []*LedgerNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// LedgerNewDisplayOrderRequest represents a data pair of id and display order
+type LedgerNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// LedgerDeleteRequest represents all parameters of ledger deleting request
+type LedgerDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerInfoResponse represents a view-object of ledger
+type LedgerInfoResponse struct {
+	Id                        int64  `json:"id,string"`
+	Name                      string `json:"name"`
+	DisplayOrder              int32  `json:"displayOrder"`
+	Hidden                    bool   `json:"hidden"`
+	Comment                   string `json:"comment"`
+	TransactionLockOverridden bool   `json:"transactionLockOverridden"`
+	TransactionLockTime       int64  `json:"transactionLockTime"`
+}
+
+// ToLedgerInfoResponse returns a view-object according to database model
+func (l *Ledger) ToLedgerInfoResponse() *LedgerInfoResponse {
+	return &LedgerInfoResponse{
+		Id:                        l.LedgerId,
+		Name:                      l.Name,
+		DisplayOrder:              l.DisplayOrder,
+		Hidden:                    l.Hidden,
+		Comment:                   l.Comment,
+		TransactionLockOverridden: l.TransactionLockOverridden,
+		TransactionLockTime:       l.TransactionLockTime,
+	}
+}
+
+// LedgerInfoResponseSlice represents the slice data structure of LedgerInfoResponse
+type LedgerInfoResponseSlice []*LedgerInfoResponse
+
+// Len returns the count of items
+func (s LedgerInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LedgerInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LedgerInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
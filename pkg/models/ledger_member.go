@@ -0,0 +1,110 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/core"
+
+// LedgerMember represents a user who the ledger is shared with, stored in database
+type LedgerMember struct {
+	MemberId        int64                 `xorm:"PK"`
+	Uid             int64                 `xorm:"INDEX(IDX_ledger_member_uid_deleted_ledger_id) NOT NULL"`
+	Deleted         bool                  `xorm:"INDEX(IDX_ledger_member_uid_deleted_ledger_id) INDEX(IDX_ledger_member_member_uid_deleted) NOT NULL"`
+	LedgerId        int64                 `xorm:"INDEX(IDX_ledger_member_uid_deleted_ledger_id) NOT NULL"`
+	MemberUid       int64                 `xorm:"INDEX(IDX_ledger_member_member_uid_deleted) NOT NULL"`
+	Role            core.LedgerMemberRole `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// LedgerAccess represents the access of a user to a ledger
+type LedgerAccess struct {
+	OwnerUid int64
+	LedgerId int64
+	Role     core.LedgerMemberRole
+}
+
+// LedgerMemberListRequest represents all parameters of ledger member listing request
+type LedgerMemberListRequest struct {
+	LedgerId int64 `form:"ledger_id,string" binding:"required,min=1"`
+}
+
+// LedgerMemberAddRequest represents all parameters of ledger member adding request
+type LedgerMemberAddRequest struct {
+	LedgerId int64                 `json:"ledgerId,string" binding:"required,min=1"`
+	Username string                `json:"username" binding:"required,notBlank,max=32,validUsername"`
+	Role     core.LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberModifyRequest represents all parameters of ledger member modification request
+type LedgerMemberModifyRequest struct {
+	Id   int64                 `json:"id,string" binding:"required,min=1"`
+	Role core.LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberDeleteRequest represents all parameters of ledger member deleting request
+type LedgerMemberDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// SharedLedgerLeaveRequest represents all parameters of shared ledger leaving request
+type SharedLedgerLeaveRequest struct {
+	LedgerId int64 `json:"ledgerId,string" binding:"required,min=1"`
+}
+
+// LedgerMemberInfoResponse represents a view-object of ledger member
+type LedgerMemberInfoResponse struct {
+	Id        int64                 `json:"id,string"`
+	LedgerId  int64                 `json:"ledgerId,string"`
+	MemberUid int64                 `json:"memberUid,string"`
+	Username  string                `json:"username"`
+	Nickname  string                `json:"nickname"`
+	Role      core.LedgerMemberRole `json:"role"`
+}
+
+// SharedLedgerInfoResponse represents a view-object of the ledger which is shared with current user
+type SharedLedgerInfoResponse struct {
+	Id            int64                 `json:"id,string"`
+	Name          string                `json:"name"`
+	Comment       string                `json:"comment"`
+	OwnerUid      int64                 `json:"ownerUid,string"`
+	OwnerUsername string                `json:"ownerUsername"`
+	OwnerNickname string                `json:"ownerNickname"`
+	Role          core.LedgerMemberRole `json:"role"`
+}
+
+// ToLedgerMemberInfoResponse returns a view-object according to database model
+func (m *LedgerMember) ToLedgerMemberInfoResponse(member *User) *LedgerMemberInfoResponse {
+	resp := &LedgerMemberInfoResponse{
+		Id:        m.MemberId,
+		LedgerId:  m.LedgerId,
+		MemberUid: m.MemberUid,
+		Role:      m.Role,
+	}
+
+	if member != nil {
+		resp.Username = member.Username
+		resp.Nickname = member.Nickname
+	}
+
+	return resp
+}
+
+// ToSharedLedgerInfoResponse returns a view-object according to database model
+func (m *LedgerMember) ToSharedLedgerInfoResponse(ledger *Ledger, owner *User) *SharedLedgerInfoResponse {
+	resp := &SharedLedgerInfoResponse{
+		Id:       m.LedgerId,
+		OwnerUid: m.Uid,
+		Role:     m.Role,
+	}
+
+	if ledger != nil {
+		resp.Name = ledger.Name
+		resp.Comment = ledger.Comment
+	}
+
+	if owner != nil {
+		resp.OwnerUsername = owner.Username
+		resp.OwnerNickname = owner.Nickname
+	}
+
+	return resp
+}
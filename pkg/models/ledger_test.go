@@ -0,0 +1,53 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerInfoResponseSliceLess(t *testing.T) {
+	var ledgerRespSlice LedgerInfoResponseSlice
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	ledgerRespSlice = append(ledgerRespSlice, &LedgerInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(ledgerRespSlice)
+
+	assert.Equal(t, int64(2), ledgerRespSlice[0].Id)
+	assert.Equal(t, int64(3), ledgerRespSlice[1].Id)
+	assert.Equal(t, int64(1), ledgerRespSlice[2].Id)
+}
+
+func TestLedgerToLedgerInfoResponse(t *testing.T) {
+	ledger := &Ledger{
+		LedgerId:                  1001,
+		Uid:                       1,
+		Name:                      "Household",
+		DisplayOrder:              2,
+		Hidden:                    true,
+		Comment:                   "Shared expenses",
+		TransactionLockOverridden: true,
+		TransactionLockTime:       1704067200,
+	}
+
+	ledgerResp := ledger.ToLedgerInfoResponse()
+
+	assert.Equal(t, int64(1001), ledgerResp.Id)
+	assert.Equal(t, "Household", ledgerResp.Name)
+	assert.Equal(t, int32(2), ledgerResp.DisplayOrder)
+	assert.Equal(t, true, ledgerResp.Hidden)
+	assert.Equal(t, "Shared expenses", ledgerResp.Comment)
+	assert.Equal(t, true, ledgerResp.TransactionLockOverridden)
+	assert.Equal(t, int64(1704067200), ledgerResp.TransactionLockTime)
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// NetWorthSnapshot represents the closing balance of one account at the end of one month stored in database
+type NetWorthSnapshot struct {
+	Uid                             int64  `xorm:"PK NOT NULL"`
+	LedgerId                        int64  `xorm:"PK NOT NULL"`
+	YearMonth                       int32  `xorm:"PK NOT NULL"`
+	AccountId                       int64  `xorm:"PK NOT NULL"`
+	Currency                        string `xorm:"VARCHAR(3) NOT NULL"`
+	ClosingBalance                  int64  `xorm:"NOT NULL"`
+	DefaultCurrency                 string `xorm:"VARCHAR(3) NOT NULL"`
+	ClosingBalanceInDefaultCurrency int64  `xorm:"NOT NULL"`
+	CreatedUnixTime                 int64
+}
+
+// NetWorthSnapshotState represents the progress of the net worth snapshots of one ledger stored in database
+type NetWorthSnapshotState struct {
+	Uid             int64  `xorm:"PK NOT NULL"`
+	LedgerId        int64  `xorm:"PK NOT NULL"`
+	LastYearMonth   int32  `xorm:"NOT NULL"`
+	DefaultCurrency string `xorm:"VARCHAR(3) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// TransactionStatisticNetWorthTrendsRequest represents all parameters of transaction statistic net worth trends request
+type TransactionStatisticNetWorthTrendsRequest struct {
+	YearMonthRangeRequest
+}
+
+// TransactionStatisticNetWorthTrendsResponse represents the monthly net worth, total assets and total liabilities in the default currency
+type TransactionStatisticNetWorthTrendsResponse struct {
+	Currency      string                                              `json:"currency"`
+	Items         TransactionStatisticNetWorthTrendsResponseItemSlice `json:"items"`
+	ExchangeRates *ExchangeRatesConversionResponse                    `json:"exchangeRates,omitempty"`
+}
+
+// TransactionStatisticNetWorthTrendsResponseItem represents the net worth of one month
+type TransactionStatisticNetWorthTrendsResponseItem struct {
+	Year             int32                                                 `json:"year"`
+	Month            int32                                                 `json:"month"`
+	TotalAssets      int64                                                 `json:"totalAssets"`
+	TotalLiabilities int64                                                 `json:"totalLiabilities"`
+	NetWorth         int64                                                 `json:"netWorth"`
+	Items            []*TransactionStatisticNetWorthTrendsResponseDataItem `json:"items"`
+}
+
+// TransactionStatisticNetWorthTrendsResponseDataItem represents the closing balance of one account at the end of one month
+type TransactionStatisticNetWorthTrendsResponseDataItem struct {
+	AccountId                       int64  `json:"accountId,string"`
+	Currency                        string `json:"currency"`
+	ClosingBalance                  int64  `json:"closingBalance"`
+	ClosingBalanceInDefaultCurrency int64  `json:"closingBalanceInDefaultCurrency"`
+}
+
+// GetNetWorthSnapshotYearMonth returns the numeric year and month (yyyyMM) of the transaction time in the timezone of the transaction
+func GetNetWorthSnapshotYearMonth(transactionTime int64, timezoneUtcOffset int16) int32 {
+	timezone := time.FixedZone("Transaction Timezone", int(timezoneUtcOffset)*60)
+	return utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transactionTime), timezone)
+}
+
+// GetNetWorthSnapshotLastYearMonth returns the numeric year and month (yyyyMM) of the last month which has ended at the specified unix time in server timezone
+func GetNetWorthSnapshotLastYearMonth(unixTime int64) int32 {
+	return utils.AddMonthsToNumericYearMonth(utils.FormatUnixTimeToNumericYearMonth(unixTime, time.Local), -1)
+}
+
+// BuildNetWorthSnapshots returns the month-end closing balance snapshots of all accounts from the start year month to the end year month according to all transactions of the ledger, the balances which are zero are omitted
+func BuildNetWorthSnapshots(uid int64, ledgerId int64, transactions []*Transaction, accounts map[int64]*Account, startYearMonth int32, endYearMonth int32, defaultCurrency string, amountConverter TransactionAmountConverter) ([]*NetWorthSnapshot, error) {
+	accountMonthlyChanges := make(map[int64]map[int32]int64)
+	accountFirstYearMonths := make(map[int64]int32)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		amount := int64(0)
+
+		if transaction.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			amount = transaction.RelatedAccountAmount
+		} else if transaction.Type == TRANSACTION_DB_TYPE_INCOME || transaction.Type == TRANSACTION_DB_TYPE_TRANSFER_IN {
+			amount = transaction.Amount
+		} else if transaction.Type == TRANSACTION_DB_TYPE_EXPENSE || transaction.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			amount = -transaction.Amount
+		} else {
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		if _, exists := accounts[transaction.AccountId]; !exists {
+			continue
+		}
+
+		yearMonth := GetNetWorthSnapshotYearMonth(transaction.TransactionTime, transaction.TimezoneUtcOffset)
+
+		if yearMonth > endYearMonth {
+			continue
+		}
+
+		monthlyChanges, exists := accountMonthlyChanges[transaction.AccountId]
+
+		if !exists {
+			monthlyChanges = make(map[int32]int64)
+			accountMonthlyChanges[transaction.AccountId] = monthlyChanges
+		}
+
+		monthlyChanges[yearMonth] += amount
+
+		if firstYearMonth, exists := accountFirstYearMonths[transaction.AccountId]; !exists || yearMonth < firstYearMonth {
+			accountFirstYearMonths[transaction.AccountId] = yearMonth
+		}
+	}
+
+	snapshots := make([]*NetWorthSnapshot, 0)
+
+	for accountId, monthlyChanges := range accountMonthlyChanges {
+		account := accounts[accountId]
+		closingBalance := int64(0)
+
+		for yearMonth := accountFirstYearMonths[accountId]; yearMonth <= endYearMonth; yearMonth = utils.AddMonthsToNumericYearMonth(yearMonth, 1) {
+			closingBalance += monthlyChanges[yearMonth]
+
+			if yearMonth < startYearMonth || closingBalance == 0 {
+				continue
+			}
+
+			lastDay := int32(utils.GetMaxDayOfMonth(int(yearMonth/100), time.Month(yearMonth%100)))
+			closingBalanceInDefaultCurrency := closingBalance
+
+			if amountConverter != nil {
+				closingBalanceInDefaultCurrency = amountConverter(accountId, yearMonth*100+lastDay, closingBalance)
+			}
+
+			snapshots = append(snapshots, &NetWorthSnapshot{
+				Uid:                             uid,
+				LedgerId:                        ledgerId,
+				YearMonth:                       yearMonth,
+				AccountId:                       accountId,
+				Currency:                        account.Currency,
+				ClosingBalance:                  closingBalance,
+				DefaultCurrency:                 defaultCurrency,
+				ClosingBalanceInDefaultCurrency: closingBalanceInDefaultCurrency,
+			})
+		}
+	}
+
+	return snapshots, nil
+}
+
+// ToTransactionStatisticNetWorthTrendsResponseItems returns the view-objects of monthly net worth according to the snapshots, the total liabilities are the absolute value of the sum of all liability account balances
+func ToTransactionStatisticNetWorthTrendsResponseItems(snapshots []*NetWorthSnapshot, accounts map[int64]*Account) TransactionStatisticNetWorthTrendsResponseItemSlice {
+	monthlyItems := make(map[int32]*TransactionStatisticNetWorthTrendsResponseItem)
+
+	for i := 0; i < len(snapshots); i++ {
+		snapshot := snapshots[i]
+		account, exists := accounts[snapshot.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		item, exists := monthlyItems[snapshot.YearMonth]
+
+		if !exists {
+			item = &TransactionStatisticNetWorthTrendsResponseItem{
+				Year:  snapshot.YearMonth / 100,
+				Month: snapshot.YearMonth % 100,
+				Items: make([]*TransactionStatisticNetWorthTrendsResponseDataItem, 0),
+			}
+			monthlyItems[snapshot.YearMonth] = item
+		}
+
+		item.Items = append(item.Items, &TransactionStatisticNetWorthTrendsResponseDataItem{
+			AccountId:                       snapshot.AccountId,
+			Currency:                        snapshot.Currency,
+			ClosingBalance:                  snapshot.ClosingBalance,
+			ClosingBalanceInDefaultCurrency: snapshot.ClosingBalanceInDefaultCurrency,
+		})
+
+		if account.Category.IsAsset() {
+			item.TotalAssets += snapshot.ClosingBalanceInDefaultCurrency
+		} else if account.Category.IsLiability() {
+			item.TotalLiabilities -= snapshot.ClosingBalanceInDefaultCurrency
+		}
+
+		item.NetWorth += snapshot.ClosingBalanceInDefaultCurrency
+	}
+
+	items := make(TransactionStatisticNetWorthTrendsResponseItemSlice, 0, len(monthlyItems))
+
+	for _, item := range monthlyItems {
+		items = append(items, item)
+	}
+
+	sort.Sort(items)
+
+	return items
+}
+
+// TransactionStatisticNetWorthTrendsResponseItemSlice represents the slice data structure of TransactionStatisticNetWorthTrendsResponseItem
+type TransactionStatisticNetWorthTrendsResponseItemSlice []*TransactionStatisticNetWorthTrendsResponseItem
+
+// Len returns the count of items
+func (s TransactionStatisticNetWorthTrendsResponseItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionStatisticNetWorthTrendsResponseItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionStatisticNetWorthTrendsResponseItemSlice) Less(i, j int) bool {
+	if s[i].Year != s[j].Year {
+		return s[i].Year < s[j].Year
+	}
+
+	return s[i].Month < s[j].Month
+}
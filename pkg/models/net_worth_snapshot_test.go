@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNetWorthSnapshotYearMonth(t *testing.T) {
+	// 2024-01-31 23:30:00 UTC
+	transactionTime := int64(1706743800) * 1000
+
+	assert.Equal(t, int32(202401), GetNetWorthSnapshotYearMonth(transactionTime, 0))
+	assert.Equal(t, int32(202402), GetNetWorthSnapshotYearMonth(transactionTime, 60))
+	assert.Equal(t, int32(202401), GetNetWorthSnapshotYearMonth(transactionTime, -300))
+}
+
+func TestBuildNetWorthSnapshots(t *testing.T) {
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD", Category: ACCOUNT_CATEGORY_CASH},
+		2: {AccountId: 2, Currency: "EUR", Category: ACCOUNT_CATEGORY_CREDIT_CARD},
+	}
+
+	transactions := []*Transaction{
+		// 2024-03-10 00:00:00 UTC
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1, TransactionTime: int64(1710028800)*1000 + 1, Amount: 200},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 2, TransactionTime: int64(1710028800) * 1000, Amount: 180},
+		// 2024-01-31 23:30:00 UTC, 2024-02-01 in UTC+1
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: int64(1706743800) * 1000, TimezoneUtcOffset: 60, Amount: 300},
+		// 2024-01-15 00:00:00 UTC
+		{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, TransactionTime: int64(1705276800) * 1000, RelatedAccountAmount: 1000},
+		{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 3, TransactionTime: int64(1705276800)*1000 + 1, Amount: 500},
+	}
+
+	amountConverter := func(accountId int64, yearMonthDay int32, amount int64) int64 {
+		if accountId == 2 {
+			return amount * 11 / 10
+		}
+
+		return amount
+	}
+
+	snapshots, err := BuildNetWorthSnapshots(1, 0, transactions, accounts, 202402, 202403, "USD", amountConverter)
+	assert.Nil(t, err)
+
+	balances := make(map[int64]map[int32]*NetWorthSnapshot)
+
+	for i := 0; i < len(snapshots); i++ {
+		if _, exists := balances[snapshots[i].AccountId]; !exists {
+			balances[snapshots[i].AccountId] = make(map[int32]*NetWorthSnapshot)
+		}
+
+		balances[snapshots[i].AccountId][snapshots[i].YearMonth] = snapshots[i]
+	}
+
+	assert.Equal(t, 3, len(snapshots))
+	assert.Equal(t, int64(700), balances[1][202402].ClosingBalance)
+	assert.Equal(t, int64(900), balances[1][202403].ClosingBalance)
+	assert.Equal(t, "USD", balances[1][202403].Currency)
+	assert.Equal(t, int64(-180), balances[2][202403].ClosingBalance)
+	assert.Equal(t, int64(-198), balances[2][202403].ClosingBalanceInDefaultCurrency)
+	assert.Equal(t, "EUR", balances[2][202403].Currency)
+	assert.Equal(t, "USD", balances[2][202403].DefaultCurrency)
+
+	_, exists := balances[1][202401]
+	assert.False(t, exists)
+	_, exists = balances[3]
+	assert.False(t, exists)
+}
+
+func TestBuildNetWorthSnapshots_InvalidTransactionType(t *testing.T) {
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD"},
+	}
+
+	transactions := []*Transaction{
+		{Type: 0, AccountId: 1, TransactionTime: int64(1705276800) * 1000, Amount: 100},
+	}
+
+	_, err := BuildNetWorthSnapshots(1, 0, transactions, accounts, 0, 202401, "USD", nil)
+	assert.NotNil(t, err)
+}
+
+func TestToTransactionStatisticNetWorthTrendsResponseItems(t *testing.T) {
+	accounts := map[int64]*Account{
+		1: {AccountId: 1, Category: ACCOUNT_CATEGORY_CASH},
+		2: {AccountId: 2, Category: ACCOUNT_CATEGORY_CREDIT_CARD},
+	}
+
+	items := ToTransactionStatisticNetWorthTrendsResponseItems([]*NetWorthSnapshot{
+		{YearMonth: 202403, AccountId: 1, ClosingBalance: 900, ClosingBalanceInDefaultCurrency: 900},
+		{YearMonth: 202403, AccountId: 2, ClosingBalance: -180, ClosingBalanceInDefaultCurrency: -198},
+		{YearMonth: 202402, AccountId: 1, ClosingBalance: 700, ClosingBalanceInDefaultCurrency: 700},
+		{YearMonth: 202402, AccountId: 3, ClosingBalance: 500, ClosingBalanceInDefaultCurrency: 500},
+	}, accounts)
+
+	assert.Equal(t, 2, len(items))
+
+	assert.Equal(t, int32(2024), items[0].Year)
+	assert.Equal(t, int32(2), items[0].Month)
+	assert.Equal(t, int64(700), items[0].TotalAssets)
+	assert.Equal(t, int64(0), items[0].TotalLiabilities)
+	assert.Equal(t, int64(700), items[0].NetWorth)
+	assert.Equal(t, 1, len(items[0].Items))
+
+	assert.Equal(t, int32(3), items[1].Month)
+	assert.Equal(t, int64(900), items[1].TotalAssets)
+	assert.Equal(t, int64(198), items[1].TotalLiabilities)
+	assert.Equal(t, int64(702), items[1].NetWorth)
+	assert.Equal(t, 2, len(items[1].Items))
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumAliasesCountOfPayee represents the maximum count of aliases of one payee
+const MaximumAliasesCountOfPayee = 20
+
+// Payee represents payee (merchant or counterparty) data stored in database
+type Payee struct {
+	PayeeId           int64        `xorm:"PK"`
+	Uid               int64        `xorm:"INDEX(IDX_payee_uid_ledger_id_deleted_order) NOT NULL"`
+	LedgerId          int64        `xorm:"INDEX(IDX_payee_uid_ledger_id_deleted_order) NOT NULL DEFAULT 0"`
+	Deleted           bool         `xorm:"INDEX(IDX_payee_uid_ledger_id_deleted_order) NOT NULL"`
+	Name              string       `xorm:"VARCHAR(64) NOT NULL"`
+	Aliases           PayeeAliases `xorm:"BLOB"`
+	DefaultCategoryId int64        `xorm:"NOT NULL DEFAULT 0"`
+	DefaultTagIds     string       `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	DisplayOrder      int32        `xorm:"INDEX(IDX_payee_uid_ledger_id_deleted_order) NOT NULL"`
+	Hidden            bool         `xorm:"NOT NULL"`
+	CreatedUnixTime   int64
+	UpdatedUnixTime   int64
+	DeletedUnixTime   int64
+}
+
+// PayeeAliases represents all the alias names of a payee stored in database
+type PayeeAliases []string
+
+// PayeeGetRequest represents all parameters of payee getting request
+type PayeeGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// PayeeCreateRequest represents all parameters of payee creation request
+type PayeeCreateRequest struct {
+	Name              string   `json:"name" binding:"required,notBlank,max=64"`
+	Aliases           []string `json:"aliases" binding:"omitempty,dive,notBlank,max=64"`
+	DefaultCategoryId int64    `json:"defaultCategoryId,string" binding:"min=0"`
+	DefaultTagIds     []string `json:"defaultTagIds"`
+}
+
+// PayeeCreateBatchRequest represents all parameters of payee batch creation request
+type PayeeCreateBatchRequest struct {
+	Payees     []*PayeeCreateRequest `json:"payees" binding:"required"`
+	SkipExists bool                  `json:"skipExists"`
+}
+
+// PayeeModifyRequest represents all parameters of payee modification request
+type PayeeModifyRequest struct {
+	Id                int64    `json:"id,string" binding:"required,min=1"`
+	Name              string   `json:"name" binding:"required,notBlank,max=64"`
+	Aliases           []string `json:"aliases" binding:"omitempty,dive,notBlank,max=64"`
+	DefaultCategoryId int64    `json:"defaultCategoryId,string" binding:"min=0"`
+	DefaultTagIds     []string `json:"defaultTagIds"`
+}
+
+// PayeeHideRequest represents all parameters of payee hiding request
+type PayeeHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// PayeeMoveRequest represents all parameters of payee moving request
+type PayeeMoveRequest struct {
+	NewDisplayOrders []*PayeeNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// PayeeNewDisplayOrderRequest represents a data pair of id and display order
+type PayeeNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// PayeeDeleteRequest represents all parameters of payee deleting request
+type PayeeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// PayeeInfoResponse represents a view-object of payee
+type PayeeInfoResponse struct {
+	Id                int64    `json:"id,string"`
+	Name              string   `json:"name"`
+	Aliases           []string `json:"aliases"`
+	DefaultCategoryId int64    `json:"defaultCategoryId,string"`
+	DefaultTagIds     []string `json:"defaultTagIds"`
+	DisplayOrder      int32    `json:"displayOrder"`
+	Hidden            bool     `json:"hidden"`
+}
+
+// FromDB fills the fields from the data stored in database
+func (a *PayeeAliases) FromDB(data []byte) error {
+	return json.Unmarshal(data, a)
+}
+
+// ToDB returns the actual stored data in database
+func (a *PayeeAliases) ToDB() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// FillFromOtherPayee fills all the fields in this current payee from other payee
+func (p *Payee) FillFromOtherPayee(payee *Payee) {
+	p.PayeeId = payee.PayeeId
+	p.Uid = payee.Uid
+	p.LedgerId = payee.LedgerId
+	p.Deleted = payee.Deleted
+	p.Name = payee.Name
+	p.Aliases = payee.Aliases
+	p.DefaultCategoryId = payee.DefaultCategoryId
+	p.DefaultTagIds = payee.DefaultTagIds
+	p.DisplayOrder = payee.DisplayOrder
+	p.Hidden = payee.Hidden
+	p.CreatedUnixTime = payee.CreatedUnixTime
+	p.UpdatedUnixTime = payee.UpdatedUnixTime
+	p.DeletedUnixTime = payee.DeletedUnixTime
+}
+
+// GetDefaultTagIds returns all default tag ids of the payee
+func (p *Payee) GetDefaultTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if p.DefaultTagIds != "" {
+		tagIds = strings.Split(p.DefaultTagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// IsNameOrAliasMatch returns whether the specified counterparty name equals to the name or any alias of the payee (case-insensitive)
+func (p *Payee) IsNameOrAliasMatch(counterpartyName string) bool {
+	counterpartyName = strings.TrimSpace(counterpartyName)
+
+	if counterpartyName == "" {
+		return false
+	}
+
+	if strings.EqualFold(strings.TrimSpace(p.Name), counterpartyName) {
+		return true
+	}
+
+	for i := 0; i < len(p.Aliases); i++ {
+		if strings.EqualFold(strings.TrimSpace(p.Aliases[i]), counterpartyName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToPayeeInfoResponse returns a view-object according to database model
+func (p *Payee) ToPayeeInfoResponse() *PayeeInfoResponse {
+	aliases := make([]string, len(p.Aliases))
+	copy(aliases, p.Aliases)
+
+	return &PayeeInfoResponse{
+		Id:                p.PayeeId,
+		Name:              p.Name,
+		Aliases:           aliases,
+		DefaultCategoryId: p.DefaultCategoryId,
+		DefaultTagIds:     utils.Int64ArrayToStringArray(p.GetDefaultTagIds()),
+		DisplayOrder:      p.DisplayOrder,
+		Hidden:            p.Hidden,
+	}
+}
+
+// PayeeInfoResponseSlice represents the slice data structure of PayeeInfoResponse
+type PayeeInfoResponseSlice []*PayeeInfoResponse
+
+// Len returns the count of items
+func (s PayeeInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s PayeeInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s PayeeInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayeeIsNameOrAliasMatch(t *testing.T) {
+	payee := &Payee{
+		Name:    "Coffee Shop",
+		Aliases: PayeeAliases{"COFFEE SHOP LTD", " Cafe "},
+	}
+
+	assert.True(t, payee.IsNameOrAliasMatch("Coffee Shop"))
+	assert.True(t, payee.IsNameOrAliasMatch(" coffee shop "))
+	assert.True(t, payee.IsNameOrAliasMatch("coffee shop ltd"))
+	assert.True(t, payee.IsNameOrAliasMatch("Cafe"))
+	assert.False(t, payee.IsNameOrAliasMatch("Coffee"))
+	assert.False(t, payee.IsNameOrAliasMatch(""))
+	assert.False(t, payee.IsNameOrAliasMatch("  "))
+}
+
+func TestPayeeGetDefaultTagIds(t *testing.T) {
+	payee := &Payee{
+		DefaultTagIds: "",
+	}
+	assert.Equal(t, 0, len(payee.GetDefaultTagIds()))
+
+	payee.DefaultTagIds = "123,456"
+	assert.Equal(t, []int64{123, 456}, payee.GetDefaultTagIds())
+}
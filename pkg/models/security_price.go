@@ -0,0 +1,161 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// SecurityPrice represents user custom security price data of one day stored in database
+type SecurityPrice struct {
+	Uid             int64  `xorm:"PK NOT NULL"`
+	DeletedUnixTime int64  `xorm:"PK NOT NULL"`
+	Symbol          string `xorm:"PK VARCHAR(32) NOT NULL"`
+	PriceDate       int32  `xorm:"PK NOT NULL"`
+	Price           int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// SecurityPriceListRequest represents all parameters of security price listing request
+type SecurityPriceListRequest struct {
+	Symbol string `form:"symbol" binding:"required,notBlank,max=32"`
+}
+
+// SecurityPriceUpdateRequest represents all parameters of user custom security price updating request
+type SecurityPriceUpdateRequest struct {
+	Symbol string `json:"symbol" binding:"required,notBlank,max=32"`
+	Date   string `json:"date" binding:"required"`
+	Price  int64  `json:"price" binding:"min=0,max=99999999999"`
+}
+
+// SecurityPriceDeleteRequest represents all parameters of user custom security price deleting request
+type SecurityPriceDeleteRequest struct {
+	Symbol string `json:"symbol" binding:"required,notBlank,max=32"`
+	Date   string `json:"date" binding:"required"`
+}
+
+// SecurityPriceInfoResponse represents a view-object of security price
+type SecurityPriceInfoResponse struct {
+	Symbol     string `json:"symbol"`
+	Date       string `json:"date"`
+	Price      int64  `json:"price"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// LatestSecurityPriceResponse returns a view-object which contains latest security prices
+type LatestSecurityPriceResponse struct {
+	DataSource     string                 `json:"dataSource"`
+	UpdateTime     int64                  `json:"updateTime"`
+	SecurityPrices []*LatestSecurityPrice `json:"securityPrices"`
+}
+
+// LatestSecurityPrice represents the latest price of a security
+type LatestSecurityPrice struct {
+	Symbol string `json:"symbol"`
+	Date   string `json:"date"`
+	Price  int64  `json:"price"`
+}
+
+// GetPriceMap returns a map of security symbol and latest price
+func (r *LatestSecurityPriceResponse) GetPriceMap() map[string]int64 {
+	priceMap := make(map[string]int64, len(r.SecurityPrices))
+
+	for i := 0; i < len(r.SecurityPrices); i++ {
+		priceMap[r.SecurityPrices[i].Symbol] = r.SecurityPrices[i].Price
+	}
+
+	return priceMap
+}
+
+// ToSecurityPriceInfoResponse returns a view-object according to database model
+func (p *SecurityPrice) ToSecurityPriceInfoResponse() *SecurityPriceInfoResponse {
+	return &SecurityPriceInfoResponse{
+		Symbol:     p.Symbol,
+		Date:       formatNumericYearMonthDay(p.PriceDate/100, p.PriceDate%100),
+		Price:      p.Price,
+		UpdateTime: p.UpdatedUnixTime,
+	}
+}
+
+// ToLatestSecurityPrice returns the latest price of a security according to database model
+func (p *SecurityPrice) ToLatestSecurityPrice() *LatestSecurityPrice {
+	return &LatestSecurityPrice{
+		Symbol: p.Symbol,
+		Date:   formatNumericYearMonthDay(p.PriceDate/100, p.PriceDate%100),
+		Price:  p.Price,
+	}
+}
+
+// NormalizeSecuritySymbol returns the normalized security symbol
+func NormalizeSecuritySymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// ParseSecurityPriceDate returns the numeric date (yyyyMMdd) of the textual price date
+func ParseSecurityPriceDate(date string) (int32, error) {
+	priceTime, err := utils.ParseFromLongDateFirstTime(date, 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(priceTime.Year())*10000 + int32(priceTime.Month())*100 + int32(priceTime.Day()), nil
+}
+
+// SecurityPriceLookup provides the price of a security on the specified date according to the historical prices
+type SecurityPriceLookup struct {
+	prices map[string][]*SecurityPrice
+}
+
+// NewSecurityPriceLookup returns a new security price lookup according to the historical prices
+func NewSecurityPriceLookup(securityPrices []*SecurityPrice) *SecurityPriceLookup {
+	prices := make(map[string][]*SecurityPrice)
+
+	for i := 0; i < len(securityPrices); i++ {
+		prices[securityPrices[i].Symbol] = append(prices[securityPrices[i].Symbol], securityPrices[i])
+	}
+
+	for _, symbolPrices := range prices {
+		sort.Slice(symbolPrices, func(i, j int) bool {
+			return symbolPrices[i].PriceDate < symbolPrices[j].PriceDate
+		})
+	}
+
+	return &SecurityPriceLookup{
+		prices: prices,
+	}
+}
+
+// GetPrice returns the latest price of the security on or before the specified numeric date (yyyyMMdd), and whether the price exists
+func (l *SecurityPriceLookup) GetPrice(symbol string, date int32) (int64, bool) {
+	symbolPrices := l.prices[symbol]
+	index := sort.Search(len(symbolPrices), func(i int) bool {
+		return symbolPrices[i].PriceDate > date
+	})
+
+	if index < 1 {
+		return 0, false
+	}
+
+	return symbolPrices[index-1].Price, true
+}
+
+// SecurityPriceInfoResponseSlice represents the slice data structure of SecurityPriceInfoResponse
+type SecurityPriceInfoResponseSlice []*SecurityPriceInfoResponse
+
+// Len returns the count of items
+func (s SecurityPriceInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s SecurityPriceInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s SecurityPriceInfoResponseSlice) Less(i, j int) bool {
+	return s[i].Date > s[j].Date
+}
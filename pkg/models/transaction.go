@@ -11,6 +11,7 @@ import (
 
 const MaximumTagsCountOfTransaction = 10
 const MaximumPicturesCountOfTransaction = 10
+const MaximumSplitsCountOfTransaction = 20
 
 // TransactionType represents transaction type
 type TransactionType byte
@@ -105,6 +106,30 @@ func (t TransactionDbType) ToTransactionRelatedAccountType() (TransactionRelated
 	}
 }
 
+// TransactionReconciliationStatus represents the reconciliation status of transaction
+type TransactionReconciliationStatus byte
+
+// Transaction reconciliation statuses
+const (
+	TRANSACTION_RECONCILIATION_STATUS_PENDING    TransactionReconciliationStatus = 0
+	TRANSACTION_RECONCILIATION_STATUS_CLEARED    TransactionReconciliationStatus = 1
+	TRANSACTION_RECONCILIATION_STATUS_RECONCILED TransactionReconciliationStatus = 2
+)
+
+// String returns a textual representation of the transaction reconciliation status enum
+func (s TransactionReconciliationStatus) String() string {
+	switch s {
+	case TRANSACTION_RECONCILIATION_STATUS_PENDING:
+		return "Pending"
+	case TRANSACTION_RECONCILIATION_STATUS_CLEARED:
+		return "Cleared"
+	case TRANSACTION_RECONCILIATION_STATUS_RECONCILED:
+		return "Reconciled"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(s))
+	}
+}
+
 // TransactionTagFilterValue represents transaction tag filter value for no tag
 const TransactionNoTagFilterValue = "none"
 
@@ -122,12 +147,14 @@ const (
 // Transaction represents transaction data stored in database
 type Transaction struct {
 	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_ledger_id_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	LedgerId             int64             `xorm:"INDEX(IDX_transaction_uid_ledger_id_deleted_time) NOT NULL DEFAULT 0"`
+	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_ledger_id_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
 	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
 	CategoryId           int64             `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
 	AccountId            int64             `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
-	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
+	PayeeId              int64             `xorm:"NOT NULL DEFAULT 0"`
+	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_ledger_id_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
 	TimezoneUtcOffset    int16             `xorm:"NOT NULL"`
 	Amount               int64             `xorm:"NOT NULL"`
 	RelatedId            int64             `xorm:"NOT NULL"`
@@ -138,7 +165,9 @@ type Transaction struct {
 	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	CreatedByUid         int64
 	ScheduledCreated     bool
+	ReconciliationStatus TransactionReconciliationStatus `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
 	DeletedUnixTime      int64
@@ -159,37 +188,42 @@ type TransactionGeoLocationRequest struct {
 
 // TransactionCreateRequest represents all parameters of transaction creation request
 type TransactionCreateRequest struct {
-	Type                 TransactionType                `json:"type" binding:"required"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
-	ClientSessionId      string                         `json:"clientSessionId"`
+	Type                 TransactionType                  `json:"type" binding:"required"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	PayeeId              int64                            `json:"payeeId,string" binding:"min=0"`
+	HideAmount           bool                             `json:"hideAmount"`
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	Splits               []*TransactionSplitCreateRequest `json:"splits" binding:"omitempty,dive"`
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
+	ReconciliationStatus TransactionReconciliationStatus  `json:"reconciliationStatus" binding:"min=0,max=1"`
+	ClientSessionId      string                           `json:"clientSessionId"`
 }
 
 // TransactionModifyRequest represents all parameters of transaction modification request
 type TransactionModifyRequest struct {
-	Id                   int64                          `json:"id,string" binding:"required,min=1"`
-	CategoryId           int64                          `json:"categoryId,string"`
-	Time                 int64                          `json:"time" binding:"required,min=1"`
-	UtcOffset            int16                          `json:"utcOffset" binding:"min=-720,max=840"`
-	SourceAccountId      int64                          `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId int64                          `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount         int64                          `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
-	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
-	HideAmount           bool                           `json:"hideAmount"`
-	TagIds               []string                       `json:"tagIds"`
-	PictureIds           []string                       `json:"pictureIds"`
-	Comment              string                         `json:"comment" binding:"max=255"`
-	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Id                   int64                            `json:"id,string" binding:"required,min=1"`
+	CategoryId           int64                            `json:"categoryId,string"`
+	Time                 int64                            `json:"time" binding:"required,min=1"`
+	UtcOffset            int16                            `json:"utcOffset" binding:"min=-720,max=840"`
+	SourceAccountId      int64                            `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId int64                            `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount         int64                            `json:"sourceAmount" binding:"min=-99999999999,max=99999999999"`
+	DestinationAmount    int64                            `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
+	PayeeId              int64                            `json:"payeeId,string" binding:"min=0"`
+	HideAmount           bool                             `json:"hideAmount"`
+	TagIds               []string                         `json:"tagIds"`
+	PictureIds           []string                         `json:"pictureIds"`
+	Splits               []*TransactionSplitCreateRequest `json:"splits" binding:"omitempty,dive"`
+	Comment              string                           `json:"comment" binding:"max=255"`
+	GeoLocation          *TransactionGeoLocationRequest   `json:"geoLocation" binding:"omitempty"`
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
@@ -278,27 +312,50 @@ type TransactionReconciliationStatementRequest struct {
 	EndTime   int64 `form:"end_time"`
 }
 
+// TransactionReconciliationStatusSetRequest represents all parameters of transaction reconciliation status setting request
+type TransactionReconciliationStatusSetRequest struct {
+	AccountId int64                           `json:"accountId,string" binding:"required,min=1"`
+	Ids       []string                        `json:"ids" binding:"required,min=1"`
+	Status    TransactionReconciliationStatus `json:"status" binding:"min=0,max=1"`
+	Unlock    bool                            `json:"unlock"`
+}
+
+// TransactionReconcileRequest represents all parameters of transaction reconciling request
+type TransactionReconcileRequest struct {
+	AccountId     int64 `json:"accountId,string" binding:"required,min=1"`
+	StatementTime int64 `json:"statementTime" binding:"required,min=1"`
+	EndingBalance int64 `json:"endingBalance" binding:"min=-99999999999,max=99999999999"`
+}
+
 // TransactionStatisticRequest represents all parameters of transaction statistic request
 type TransactionStatisticRequest struct {
-	StartTime              int64  `form:"start_time" binding:"min=0"`
-	EndTime                int64  `form:"end_time" binding:"min=0"`
-	TagFilter              string `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string `form:"keyword"`
-	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	StartTime                  int64  `form:"start_time" binding:"min=0"`
+	EndTime                    int64  `form:"end_time" binding:"min=0"`
+	TagFilter                  string `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string `form:"keyword"`
+	UseTransactionTimezone     bool   `form:"use_transaction_timezone"`
+	GroupByPayee               bool   `form:"group_by_payee"`
+	ConvertAmounts             bool   `form:"convert_amounts"`
+	TargetCurrency             string `form:"target_currency" binding:"omitempty,len=3,validCurrency"`
+	UseHistoricalExchangeRates bool   `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
 type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
-	TagFilter              string `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string `form:"keyword"`
-	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	TagFilter                  string `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string `form:"keyword"`
+	UseTransactionTimezone     bool   `form:"use_transaction_timezone"`
+	ConvertAmounts             bool   `form:"convert_amounts"`
+	TargetCurrency             string `form:"target_currency" binding:"omitempty,len=3,validCurrency"`
+	UseHistoricalExchangeRates bool   `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticAssetTrendsRequest represents all parameters of transaction statistic asset trends request
 type TransactionStatisticAssetTrendsRequest struct {
-	StartTime int64 `form:"start_time"`
-	EndTime   int64 `form:"end_time"`
+	StartTime                  int64 `form:"start_time"`
+	EndTime                    int64 `form:"end_time"`
+	UseHistoricalExchangeRates bool  `form:"use_historical_exchange_rates"`
 }
 
 // TransactionAmountsRequest represents all parameters of transaction amounts request
@@ -336,6 +393,22 @@ type TransactionDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTrashListRequest represents all parameters of deleted transaction listing request
+type TransactionTrashListRequest struct {
+	Page  int32 `form:"page" binding:"min=0"`
+	Count int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// TransactionRestoreRequest represents all parameters of deleted transaction restoring request
+type TransactionRestoreRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionPurgeRequest represents all parameters of deleted transaction purging request
+type TransactionPurgeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
 // YearMonthRangeRequest represents all parameters of a request with year and month range
 type YearMonthRangeRequest struct {
 	StartYearMonth string `form:"start_year_month"`
@@ -363,15 +436,25 @@ type TransactionInfoResponse struct {
 	DestinationAccount   *AccountInfoResponse                     `json:"destinationAccount,omitempty"`
 	SourceAmount         int64                                    `json:"sourceAmount"`
 	DestinationAmount    int64                                    `json:"destinationAmount,omitempty"`
+	PayeeId              int64                                    `json:"payeeId,string,omitempty"`
 	HideAmount           bool                                     `json:"hideAmount"`
 	TagIds               []string                                 `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Comment              string                                   `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	ReconciliationStatus TransactionReconciliationStatus          `json:"reconciliationStatus"`
+	CreatedByUid         int64                                    `json:"createdByUid,string,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
+// DeletedTransactionInfoResponse represents a view-object of deleted transaction in trash bin
+type DeletedTransactionInfoResponse struct {
+	*TransactionInfoResponse
+	DeletedTime int64 `json:"deletedTime"`
+}
+
 // TransactionCountResponse represents transaction count response
 type TransactionCountResponse struct {
 	TotalCount int64 `json:"totalCount"`
@@ -406,11 +489,20 @@ type TransactionReconciliationStatementResponse struct {
 	ClosingBalance int64                                             `json:"closingBalance"`
 }
 
+// TransactionReconcileResponse represents the result of reconciling transactions
+type TransactionReconcileResponse struct {
+	ReconciledCount int64 `json:"reconciledCount"`
+	ClearedBalance  int64 `json:"clearedBalance"`
+}
+
 // TransactionStatisticResponse represents transaction statistic response
 type TransactionStatisticResponse struct {
-	StartTime int64                               `json:"startTime"`
-	EndTime   int64                               `json:"endTime"`
-	Items     []*TransactionStatisticResponseItem `json:"items"`
+	StartTime          int64                               `json:"startTime"`
+	EndTime            int64                               `json:"endTime"`
+	Items              []*TransactionStatisticResponseItem `json:"items"`
+	TotalIncomeAmount  *int64                              `json:"totalIncomeAmount,omitempty"`
+	TotalExpenseAmount *int64                              `json:"totalExpenseAmount,omitempty"`
+	ExchangeRates      *ExchangeRatesConversionResponse    `json:"exchangeRates,omitempty"`
 }
 
 // TransactionStatisticResponseItem represents total amount item for a response
@@ -419,14 +511,19 @@ type TransactionStatisticResponseItem struct {
 	AccountId          int64                         `json:"accountId,string"`
 	RelatedAccountId   int64                         `json:"relatedAccountId,string,omitempty"`
 	RelatedAccountType TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
+	PayeeId            int64                         `json:"payeeId,string,omitempty"`
 	TotalAmount        int64                         `json:"amount"`
+	Currency           string                        `json:"currency,omitempty"`
 }
 
 // TransactionStatisticTrendsResponseItem represents the data within each statistic interval
 type TransactionStatisticTrendsResponseItem struct {
-	Year  int32                               `json:"year"`
-	Month int32                               `json:"month"`
-	Items []*TransactionStatisticResponseItem `json:"items"`
+	Year               int32                               `json:"year"`
+	Month              int32                               `json:"month"`
+	Items              []*TransactionStatisticResponseItem `json:"items"`
+	TotalIncomeAmount  *int64                              `json:"totalIncomeAmount,omitempty"`
+	TotalExpenseAmount *int64                              `json:"totalExpenseAmount,omitempty"`
+	ExchangeRates      *ExchangeRatesConversionResponse    `json:"exchangeRates,omitempty"`
 }
 
 // TransactionStatisticAssetTrendsResponseItem represents the data within each statistic interval
@@ -439,9 +536,12 @@ type TransactionStatisticAssetTrendsResponseItem struct {
 
 // TransactionStatisticAssetTrendsResponseDataItem represents an asset trends data item
 type TransactionStatisticAssetTrendsResponseDataItem struct {
-	AccountId             int64 `json:"accountId,string"`
-	AccountOpeningBalance int64 `json:"accountOpeningBalance"`
-	AccountClosingBalance int64 `json:"accountClosingBalance"`
+	AccountId             int64  `json:"accountId,string"`
+	AccountOpeningBalance int64  `json:"accountOpeningBalance"`
+	AccountClosingBalance int64  `json:"accountClosingBalance"`
+	MarketValue           int64  `json:"marketValue,omitempty"`
+	UnrealizedGain        int64  `json:"unrealizedGain,omitempty"`
+	Currency              string `json:"currency,omitempty"`
 }
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
@@ -521,6 +621,10 @@ func (t *Transaction) IsEditable(currentUser *User, clientTimezone *time.Locatio
 		return false
 	}
 
+	if t.ReconciliationStatus == TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return false
+	}
+
 	if t.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT {
 		if relatedAccount == nil || relatedAccount.Hidden {
 			return false
@@ -530,6 +634,23 @@ func (t *Transaction) IsEditable(currentUser *User, clientTimezone *time.Locatio
 	return true
 }
 
+// GetAccountBalanceChangedAmount returns the amount which this transaction changes the balance of its account
+func (t *Transaction) GetAccountBalanceChangedAmount() (int64, error) {
+	if t.Type == TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return t.RelatedAccountAmount, nil
+	} else if t.Type == TRANSACTION_DB_TYPE_INCOME {
+		return t.Amount, nil
+	} else if t.Type == TRANSACTION_DB_TYPE_EXPENSE {
+		return -t.Amount, nil
+	} else if t.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return -t.Amount, nil
+	} else if t.Type == TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return t.Amount, nil
+	} else {
+		return 0, errs.ErrTransactionTypeInvalid
+	}
+}
+
 // ToTransactionInfoResponse returns a view-object according to database model
 func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *TransactionInfoResponse {
 	transactionType, err := t.Type.ToTransactionType()
@@ -575,14 +696,25 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		DestinationAccountId: destinationAccountId,
 		SourceAmount:         sourceAmount,
 		DestinationAmount:    destinationAmount,
+		PayeeId:              t.PayeeId,
 		HideAmount:           t.HideAmount,
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
+		ReconciliationStatus: t.ReconciliationStatus,
+		CreatedByUid:         t.CreatedByUid,
 		Editable:             editable,
 	}
 }
 
+// ToDeletedTransactionInfoResponse returns a view-object of deleted transaction according to database model
+func (t *Transaction) ToDeletedTransactionInfoResponse(tagIds []int64, editable bool) *DeletedTransactionInfoResponse {
+	return &DeletedTransactionInfoResponse{
+		TransactionInfoResponse: t.ToTransactionInfoResponse(tagIds, editable),
+		DeletedTime:             t.DeletedUnixTime,
+	}
+}
+
 // GetTransactionAmountsRequestItems returns request items by query parameters
 func (t *TransactionAmountsRequest) GetTransactionAmountsRequestItems() ([]*TransactionAmountsRequestItem, error) {
 	items := strings.Split(t.Query, "|")
@@ -643,6 +775,32 @@ func (t *YearMonthRangeRequest) GetNumericYearMonthRange() (int32, int32, int32,
 	return startYear, startMonth, endYear, endMonth, nil
 }
 
+// IsAmountConversionRequired returns whether the statistic amounts should be converted into one currency
+func (t *TransactionStatisticRequest) IsAmountConversionRequired() bool {
+	return t.ConvertAmounts || t.TargetCurrency != "" || t.UseHistoricalExchangeRates
+}
+
+// IsAmountConversionRequired returns whether the statistic amounts should be converted into one currency
+func (t *TransactionStatisticTrendsRequest) IsAmountConversionRequired() bool {
+	return t.ConvertAmounts || t.TargetCurrency != "" || t.UseHistoricalExchangeRates
+}
+
+// GetTotalIncomeAndExpenseAmounts returns the total income and expense amounts of the statistic total amounts items, the items should be converted into one currency
+func GetTotalIncomeAndExpenseAmounts(totalAmounts []*Transaction) (int64, int64) {
+	totalIncomeAmount := int64(0)
+	totalExpenseAmount := int64(0)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		if totalAmounts[i].Type == TRANSACTION_DB_TYPE_INCOME {
+			totalIncomeAmount += totalAmounts[i].Amount
+		} else if totalAmounts[i].Type == TRANSACTION_DB_TYPE_EXPENSE {
+			totalExpenseAmount += totalAmounts[i].Amount
+		}
+	}
+
+	return totalIncomeAmount, totalExpenseAmount
+}
+
 // TransactionInfoResponseSlice represents the slice data structure of TransactionInfoResponse
 type TransactionInfoResponseSlice []*TransactionInfoResponse
 
@@ -16,12 +16,13 @@ const (
 // TransactionCategory represents transaction category data stored in database
 type TransactionCategory struct {
 	CategoryId       int64                   `xorm:"PK"`
-	Uid              int64                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
-	Deleted          bool                    `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
-	Type             TransactionCategoryType `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
-	ParentCategoryId int64                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
+	Uid              int64                   `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL"`
+	LedgerId         int64                   `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL DEFAULT 0"`
+	Deleted          bool                    `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL"`
+	Type             TransactionCategoryType `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL"`
+	ParentCategoryId int64                   `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL"`
 	Name             string                  `xorm:"VARCHAR(64) NOT NULL"`
-	DisplayOrder     int32                   `xorm:"INDEX(IDX_category_uid_deleted_type_parent_category_id_order) NOT NULL"`
+	DisplayOrder     int32                   `xorm:"INDEX(IDX_category_uid_ledger_id_deleted_type_parent_category_id_order) NOT NULL"`
 	Icon             int64                   `xorm:"NOT NULL"`
 	Color            string                  `xorm:"VARCHAR(6) NOT NULL"`
 	Hidden           bool                    `xorm:"NOT NULL"`
@@ -101,6 +102,16 @@ type TransactionCategoryDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionCategoryRestoreRequest represents all parameters of deleted transaction category restoring request
+type TransactionCategoryRestoreRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionCategoryPurgeRequest represents all parameters of deleted transaction category purging request
+type TransactionCategoryPurgeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
 // TransactionCategoryInfoResponse represents a view-object of transaction category
 type TransactionCategoryInfoResponse struct {
 	Id            int64                                `json:"id,string"`
@@ -115,6 +126,12 @@ type TransactionCategoryInfoResponse struct {
 	SubCategories TransactionCategoryInfoResponseSlice `json:"subCategories,omitempty"`
 }
 
+// DeletedTransactionCategoryInfoResponse represents a view-object of deleted transaction category in trash bin
+type DeletedTransactionCategoryInfoResponse struct {
+	*TransactionCategoryInfoResponse
+	DeletedTime int64 `json:"deletedTime"`
+}
+
 // ToTransactionCategoryInfoResponse returns a view-object according to database model
 func (c *TransactionCategory) ToTransactionCategoryInfoResponse() *TransactionCategoryInfoResponse {
 	return &TransactionCategoryInfoResponse{
@@ -130,6 +147,14 @@ func (c *TransactionCategory) ToTransactionCategoryInfoResponse() *TransactionCa
 	}
 }
 
+// ToDeletedTransactionCategoryInfoResponse returns a view-object of deleted transaction category according to database model
+func (c *TransactionCategory) ToDeletedTransactionCategoryInfoResponse() *DeletedTransactionCategoryInfoResponse {
+	return &DeletedTransactionCategoryInfoResponse{
+		TransactionCategoryInfoResponse: c.ToTransactionCategoryInfoResponse(),
+		DeletedTime:                     c.DeletedUnixTime,
+	}
+}
+
 // TransactionCategoryInfoResponseSlice represents the slice data structure of TransactionCategoryInfoResponse
 type TransactionCategoryInfoResponseSlice []*TransactionCategoryInfoResponse
 
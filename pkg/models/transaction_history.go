@@ -0,0 +1,205 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionHistoryAction represents the action which changes transaction
+type TransactionHistoryAction byte
+
+// Transaction history actions
+const (
+	TRANSACTION_HISTORY_ACTION_MODIFY TransactionHistoryAction = 1
+	TRANSACTION_HISTORY_ACTION_DELETE TransactionHistoryAction = 2
+	TRANSACTION_HISTORY_ACTION_MOVE   TransactionHistoryAction = 3
+)
+
+// String returns a textual representation of the transaction history action enum
+func (a TransactionHistoryAction) String() string {
+	switch a {
+	case TRANSACTION_HISTORY_ACTION_MODIFY:
+		return "Modify"
+	case TRANSACTION_HISTORY_ACTION_DELETE:
+		return "Delete"
+	case TRANSACTION_HISTORY_ACTION_MOVE:
+		return "Move"
+	default:
+		return "Unknown"
+	}
+}
+
+// Field names of transaction history change
+const (
+	TransactionHistoryFieldType                 = "type"
+	TransactionHistoryFieldCategoryId           = "category_id"
+	TransactionHistoryFieldAccountId            = "account_id"
+	TransactionHistoryFieldPayeeId              = "payee_id"
+	TransactionHistoryFieldAmount               = "amount"
+	TransactionHistoryFieldRelatedAccountId     = "related_account_id"
+	TransactionHistoryFieldRelatedAccountAmount = "related_account_amount"
+	TransactionHistoryFieldTransactionTime      = "transaction_time"
+	TransactionHistoryFieldTimezoneUtcOffset    = "timezone_utc_offset"
+	TransactionHistoryFieldHideAmount           = "hide_amount"
+	TransactionHistoryFieldComment              = "comment"
+	TransactionHistoryFieldGeoLongitude         = "geo_longitude"
+	TransactionHistoryFieldGeoLatitude          = "geo_latitude"
+	TransactionHistoryFieldTagIds               = "tag_ids"
+)
+
+// TransactionHistory represents a change log of transaction stored in database
+type TransactionHistory struct {
+	HistoryId       int64                           `xorm:"PK"`
+	Uid             int64                           `xorm:"INDEX(IDX_transaction_history_uid_transaction_id_time) NOT NULL"`
+	LedgerId        int64                           `xorm:"NOT NULL DEFAULT 0"`
+	TransactionId   int64                           `xorm:"INDEX(IDX_transaction_history_uid_transaction_id_time) NOT NULL"`
+	Action          TransactionHistoryAction        `xorm:"TINYINT NOT NULL"`
+	Changes         *TransactionHistoryFieldChanges `xorm:"BLOB"`
+	OperatorUid     int64                           `xorm:"NOT NULL"`
+	OperatorTokenId string                          `xorm:"VARCHAR(64)"`
+	OperatorIp      string                          `xorm:"VARCHAR(39)"`
+	CreatedUnixTime int64                           `xorm:"INDEX(IDX_transaction_history_uid_transaction_id_time)"`
+}
+
+// TransactionHistoryFieldChange represents the old and new value of a changed transaction field
+type TransactionHistoryFieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// TransactionHistoryFieldChanges represents all changed fields of a transaction history stored in database
+type TransactionHistoryFieldChanges []*TransactionHistoryFieldChange
+
+// TransactionHistoryOperator represents the user and the client which change transaction
+type TransactionHistoryOperator struct {
+	Uid      int64
+	TokenId  string
+	ClientIp string
+}
+
+// TransactionHistoryListRequest represents all parameters of transaction history listing request
+type TransactionHistoryListRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionHistoryInfoResponse represents a view-object of transaction history
+type TransactionHistoryInfoResponse struct {
+	Id              int64                          `json:"id,string"`
+	TransactionId   int64                          `json:"transactionId,string"`
+	Action          TransactionHistoryAction       `json:"action"`
+	Changes         TransactionHistoryFieldChanges `json:"changes"`
+	OperatorUid     int64                          `json:"operatorUid,string"`
+	OperatorTokenId string                         `json:"operatorTokenId,omitempty"`
+	OperatorIp      string                         `json:"operatorIp,omitempty"`
+	Time            int64                          `json:"time"`
+}
+
+// FromDB fills the fields from the data stored in database
+func (c *TransactionHistoryFieldChanges) FromDB(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// ToDB returns the actual stored data in database
+func (c *TransactionHistoryFieldChanges) ToDB() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// ToTransactionHistoryInfoResponse returns a view-object according to database model
+func (h *TransactionHistory) ToTransactionHistoryInfoResponse() *TransactionHistoryInfoResponse {
+	changes := TransactionHistoryFieldChanges{}
+
+	if h.Changes != nil {
+		changes = *h.Changes
+	}
+
+	return &TransactionHistoryInfoResponse{
+		Id:              h.HistoryId,
+		TransactionId:   h.TransactionId,
+		Action:          h.Action,
+		Changes:         changes,
+		OperatorUid:     h.OperatorUid,
+		OperatorTokenId: h.OperatorTokenId,
+		OperatorIp:      h.OperatorIp,
+		Time:            h.CreatedUnixTime,
+	}
+}
+
+// GetTransactionHistoryFieldChanges returns the changed fields between the old transaction and the new transaction, all the fields of old transaction are returned if new transaction is nil
+func GetTransactionHistoryFieldChanges(oldTransaction *Transaction, newTransaction *Transaction) TransactionHistoryFieldChanges {
+	oldValues := getTransactionHistoryFieldValues(oldTransaction)
+	newValues := getTransactionHistoryFieldValues(newTransaction)
+	changes := make(TransactionHistoryFieldChanges, 0, len(oldValues))
+
+	for i := 0; i < len(oldValues); i++ {
+		newValue := ""
+
+		if newValues != nil {
+			newValue = newValues[i][1]
+		}
+
+		if newValues != nil && oldValues[i][1] == newValue {
+			continue
+		}
+
+		changes = append(changes, &TransactionHistoryFieldChange{
+			Field:    oldValues[i][0],
+			OldValue: oldValues[i][1],
+			NewValue: newValue,
+		})
+	}
+
+	return changes
+}
+
+// GetTransactionHistoryTagIdsFieldChange returns the change of tag ids, returns nil if tag ids are not changed
+func GetTransactionHistoryTagIdsFieldChange(oldTagIds []int64, newTagIds []int64) *TransactionHistoryFieldChange {
+	oldValue := getTransactionHistoryTagIdsValue(oldTagIds)
+	newValue := getTransactionHistoryTagIdsValue(newTagIds)
+
+	if oldValue == newValue {
+		return nil
+	}
+
+	return &TransactionHistoryFieldChange{
+		Field:    TransactionHistoryFieldTagIds,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+}
+
+func getTransactionHistoryFieldValues(transaction *Transaction) [][2]string {
+	if transaction == nil {
+		return nil
+	}
+
+	return [][2]string{
+		{TransactionHistoryFieldType, strconv.Itoa(int(transaction.Type))},
+		{TransactionHistoryFieldCategoryId, utils.Int64ToString(transaction.CategoryId)},
+		{TransactionHistoryFieldAccountId, utils.Int64ToString(transaction.AccountId)},
+		{TransactionHistoryFieldAmount, utils.Int64ToString(transaction.Amount)},
+		{TransactionHistoryFieldRelatedAccountId, utils.Int64ToString(transaction.RelatedAccountId)},
+		{TransactionHistoryFieldRelatedAccountAmount, utils.Int64ToString(transaction.RelatedAccountAmount)},
+		{TransactionHistoryFieldPayeeId, utils.Int64ToString(transaction.PayeeId)},
+		{TransactionHistoryFieldTransactionTime, utils.Int64ToString(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))},
+		{TransactionHistoryFieldTimezoneUtcOffset, strconv.Itoa(int(transaction.TimezoneUtcOffset))},
+		{TransactionHistoryFieldHideAmount, strconv.FormatBool(transaction.HideAmount)},
+		{TransactionHistoryFieldComment, transaction.Comment},
+		{TransactionHistoryFieldGeoLongitude, strconv.FormatFloat(transaction.GeoLongitude, 'f', -1, 64)},
+		{TransactionHistoryFieldGeoLatitude, strconv.FormatFloat(transaction.GeoLatitude, 'f', -1, 64)},
+	}
+}
+
+func getTransactionHistoryTagIdsValue(tagIds []int64) string {
+	sortedTagIds := make([]int64, len(tagIds))
+	copy(sortedTagIds, tagIds)
+	sort.Slice(sortedTagIds, func(i, j int) bool {
+		return sortedTagIds[i] < sortedTagIds[j]
+	})
+
+	return strings.Join(utils.Int64ArrayToStringArray(sortedTagIds), ",")
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionHistoryFieldChanges_ChangedFields(t *testing.T) {
+	oldTransaction := &Transaction{
+		Type:            TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      1001,
+		AccountId:       2001,
+		Amount:          1234,
+		TransactionTime: 1700000000000,
+		Comment:         "Lunch",
+	}
+	newTransaction := &Transaction{
+		Type:            TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      1001,
+		AccountId:       2002,
+		Amount:          2345,
+		TransactionTime: 1700000000001,
+		Comment:         "Lunch",
+	}
+
+	changes := GetTransactionHistoryFieldChanges(oldTransaction, newTransaction)
+
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, TransactionHistoryFieldAccountId, changes[0].Field)
+	assert.Equal(t, "2001", changes[0].OldValue)
+	assert.Equal(t, "2002", changes[0].NewValue)
+	assert.Equal(t, TransactionHistoryFieldAmount, changes[1].Field)
+	assert.Equal(t, "1234", changes[1].OldValue)
+	assert.Equal(t, "2345", changes[1].NewValue)
+}
+
+func TestGetTransactionHistoryFieldChanges_NoChanges(t *testing.T) {
+	transaction := &Transaction{
+		Type:       TRANSACTION_DB_TYPE_INCOME,
+		CategoryId: 1001,
+		AccountId:  2001,
+		Amount:     1234,
+	}
+
+	changes := GetTransactionHistoryFieldChanges(transaction, transaction)
+	assert.Equal(t, 0, len(changes))
+}
+
+func TestGetTransactionHistoryFieldChanges_DeletedTransaction(t *testing.T) {
+	oldTransaction := &Transaction{
+		Type:         TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:   1001,
+		AccountId:    2001,
+		Amount:       1234,
+		HideAmount:   true,
+		Comment:      "Lunch",
+		GeoLatitude:  31.2,
+		GeoLongitude: 121.5,
+	}
+
+	changes := GetTransactionHistoryFieldChanges(oldTransaction, nil)
+	changeMap := make(map[string]*TransactionHistoryFieldChange, len(changes))
+
+	for i := 0; i < len(changes); i++ {
+		assert.Equal(t, "", changes[i].NewValue)
+		changeMap[changes[i].Field] = changes[i]
+	}
+
+	assert.Equal(t, 13, len(changes))
+	assert.Equal(t, "1234", changeMap[TransactionHistoryFieldAmount].OldValue)
+	assert.Equal(t, "true", changeMap[TransactionHistoryFieldHideAmount].OldValue)
+	assert.Equal(t, "Lunch", changeMap[TransactionHistoryFieldComment].OldValue)
+	assert.Equal(t, "31.2", changeMap[TransactionHistoryFieldGeoLatitude].OldValue)
+	assert.Equal(t, "121.5", changeMap[TransactionHistoryFieldGeoLongitude].OldValue)
+}
+
+func TestGetTransactionHistoryTagIdsFieldChange(t *testing.T) {
+	change := GetTransactionHistoryTagIdsFieldChange([]int64{3, 1}, []int64{1, 2})
+	assert.NotNil(t, change)
+	assert.Equal(t, TransactionHistoryFieldTagIds, change.Field)
+	assert.Equal(t, "1,3", change.OldValue)
+	assert.Equal(t, "1,2", change.NewValue)
+
+	change = GetTransactionHistoryTagIdsFieldChange([]int64{2, 1}, []int64{1, 2})
+	assert.Nil(t, change)
+
+	change = GetTransactionHistoryTagIdsFieldChange(nil, nil)
+	assert.Nil(t, change)
+}
+
+func TestTransactionHistoryToTransactionHistoryInfoResponse(t *testing.T) {
+	changes := TransactionHistoryFieldChanges{
+		&TransactionHistoryFieldChange{
+			Field:    TransactionHistoryFieldComment,
+			OldValue: "Lunch",
+			NewValue: "Dinner",
+		},
+	}
+	history := &TransactionHistory{
+		HistoryId:       1001,
+		TransactionId:   2001,
+		Action:          TRANSACTION_HISTORY_ACTION_MODIFY,
+		Changes:         &changes,
+		OperatorUid:     3001,
+		OperatorTokenId: "3001:1700000000:4001",
+		OperatorIp:      "127.0.0.1",
+		CreatedUnixTime: 1700000000,
+	}
+
+	historyResp := history.ToTransactionHistoryInfoResponse()
+
+	assert.Equal(t, int64(1001), historyResp.Id)
+	assert.Equal(t, int64(2001), historyResp.TransactionId)
+	assert.Equal(t, TRANSACTION_HISTORY_ACTION_MODIFY, historyResp.Action)
+	assert.Equal(t, 1, len(historyResp.Changes))
+	assert.Equal(t, "Dinner", historyResp.Changes[0].NewValue)
+	assert.Equal(t, int64(3001), historyResp.OperatorUid)
+	assert.Equal(t, "3001:1700000000:4001", historyResp.OperatorTokenId)
+	assert.Equal(t, "127.0.0.1", historyResp.OperatorIp)
+	assert.Equal(t, int64(1700000000), historyResp.Time)
+
+	history.Changes = nil
+	historyResp = history.ToTransactionHistoryInfoResponse()
+	assert.NotNil(t, historyResp.Changes)
+	assert.Equal(t, 0, len(historyResp.Changes))
+}
@@ -0,0 +1,278 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRule represents transaction rule data stored in database
+type TransactionRule struct {
+	RuleId               int64           `xorm:"PK"`
+	Uid                  int64           `xorm:"INDEX(IDX_transaction_rule_uid_ledger_id_deleted_order) NOT NULL"`
+	LedgerId             int64           `xorm:"INDEX(IDX_transaction_rule_uid_ledger_id_deleted_order) NOT NULL DEFAULT 0"`
+	Deleted              bool            `xorm:"INDEX(IDX_transaction_rule_uid_ledger_id_deleted_order) NOT NULL"`
+	Name                 string          `xorm:"VARCHAR(64) NOT NULL"`
+	TransactionType      TransactionType `xorm:"NOT NULL"`
+	CommentKeyword       string          `xorm:"VARCHAR(255) NOT NULL"`
+	CommentRegex         string          `xorm:"VARCHAR(255) NOT NULL"`
+	MinAmount            *int64
+	MaxAmount            *int64
+	AccountId            int64  `xorm:"NOT NULL"`
+	CounterpartyKeyword  string `xorm:"VARCHAR(255) NOT NULL"`
+	OriginalCategoryName string `xorm:"VARCHAR(255) NOT NULL"`
+	SetCategoryId        int64  `xorm:"NOT NULL"`
+	AddTagIds            string `xorm:"VARCHAR(255) NOT NULL"`
+	RewriteComment       string `xorm:"VARCHAR(255) NOT NULL"`
+	SetHideAmount        bool   `xorm:"NOT NULL"`
+	StopProcessing       bool   `xorm:"NOT NULL"`
+	DisplayOrder         int32  `xorm:"INDEX(IDX_transaction_rule_uid_ledger_id_deleted_order) NOT NULL"`
+	Disabled             bool   `xorm:"NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// TransactionRuleTarget represents the transaction data which transaction rules match and change
+type TransactionRuleTarget struct {
+	Type                 TransactionType
+	AccountId            int64
+	RelatedAccountId     int64
+	Amount               int64
+	Counterparty         string
+	OriginalCategoryName string
+	HasSplits            bool
+	CategoryId           int64
+	CategorySkipped      bool
+	TagIds               []int64
+	Comment              string
+	HideAmount           bool
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	Name                 string          `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType      TransactionType `json:"transactionType" binding:"min=0"`
+	CommentKeyword       string          `json:"commentKeyword" binding:"max=255"`
+	CommentRegex         string          `json:"commentRegex" binding:"max=255"`
+	MinAmount            *int64          `json:"minAmount" binding:"omitempty,min=-99999999999,max=99999999999"`
+	MaxAmount            *int64          `json:"maxAmount" binding:"omitempty,min=-99999999999,max=99999999999"`
+	AccountId            int64           `json:"accountId,string" binding:"min=0"`
+	CounterpartyKeyword  string          `json:"counterpartyKeyword" binding:"max=255"`
+	OriginalCategoryName string          `json:"originalCategoryName" binding:"max=255"`
+	SetCategoryId        int64           `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds            []string        `json:"addTagIds"`
+	RewriteComment       string          `json:"rewriteComment" binding:"max=255"`
+	SetHideAmount        bool            `json:"setHideAmount"`
+	StopProcessing       bool            `json:"stopProcessing"`
+	Disabled             bool            `json:"disabled"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id                   int64           `json:"id,string" binding:"required,min=1"`
+	Name                 string          `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType      TransactionType `json:"transactionType" binding:"min=0"`
+	CommentKeyword       string          `json:"commentKeyword" binding:"max=255"`
+	CommentRegex         string          `json:"commentRegex" binding:"max=255"`
+	MinAmount            *int64          `json:"minAmount" binding:"omitempty,min=-99999999999,max=99999999999"`
+	MaxAmount            *int64          `json:"maxAmount" binding:"omitempty,min=-99999999999,max=99999999999"`
+	AccountId            int64           `json:"accountId,string" binding:"min=0"`
+	CounterpartyKeyword  string          `json:"counterpartyKeyword" binding:"max=255"`
+	OriginalCategoryName string          `json:"originalCategoryName" binding:"max=255"`
+	SetCategoryId        int64           `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds            []string        `json:"addTagIds"`
+	RewriteComment       string          `json:"rewriteComment" binding:"max=255"`
+	SetHideAmount        bool            `json:"setHideAmount"`
+	StopProcessing       bool            `json:"stopProcessing"`
+	Disabled             bool            `json:"disabled"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleApplyRequest represents all parameters of applying transaction rules to existed transactions request
+type TransactionRuleApplyRequest struct {
+	RuleIds   []string `json:"ruleIds"`
+	StartTime int64    `json:"startTime" binding:"min=0"`
+	EndTime   int64    `json:"endTime" binding:"min=0"`
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id                   int64           `json:"id,string"`
+	Name                 string          `json:"name"`
+	TransactionType      TransactionType `json:"transactionType"`
+	CommentKeyword       string          `json:"commentKeyword"`
+	CommentRegex         string          `json:"commentRegex"`
+	MinAmount            *int64          `json:"minAmount,omitempty"`
+	MaxAmount            *int64          `json:"maxAmount,omitempty"`
+	AccountId            int64           `json:"accountId,string"`
+	CounterpartyKeyword  string          `json:"counterpartyKeyword"`
+	OriginalCategoryName string          `json:"originalCategoryName"`
+	SetCategoryId        int64           `json:"setCategoryId,string"`
+	AddTagIds            []string        `json:"addTagIds"`
+	RewriteComment       string          `json:"rewriteComment"`
+	SetHideAmount        bool            `json:"setHideAmount"`
+	StopProcessing       bool            `json:"stopProcessing"`
+	DisplayOrder         int32           `json:"displayOrder"`
+	Disabled             bool            `json:"disabled"`
+}
+
+// TransactionRuleApplyResponse represents a view-object of the result of applying transaction rules to existed transactions
+type TransactionRuleApplyResponse struct {
+	MatchedCount         int `json:"matchedCount"`
+	UpdatedCount         int `json:"updatedCount"`
+	FailedCount          int `json:"failedCount"`
+	CategorySkippedCount int `json:"categorySkippedCount"`
+}
+
+// NewTransactionRuleTarget returns a transaction rule target according to the transaction model and its additional data
+func NewTransactionRuleTarget(transaction *Transaction, tagIds []int64, counterparty string, originalCategoryName string, hasSplits bool) *TransactionRuleTarget {
+	transactionType, _ := transaction.Type.ToTransactionType()
+	targetTagIds := make([]int64, len(tagIds))
+	copy(targetTagIds, tagIds)
+
+	return &TransactionRuleTarget{
+		Type:                 transactionType,
+		AccountId:            transaction.AccountId,
+		RelatedAccountId:     transaction.RelatedAccountId,
+		Amount:               transaction.Amount,
+		Counterparty:         counterparty,
+		OriginalCategoryName: originalCategoryName,
+		HasSplits:            hasSplits,
+		CategoryId:           transaction.CategoryId,
+		TagIds:               targetTagIds,
+		Comment:              transaction.Comment,
+		HideAmount:           transaction.HideAmount,
+	}
+}
+
+// IsTransactionTypeValid returns whether the transaction type condition of the transaction rule is valid
+func (r *TransactionRule) IsTransactionTypeValid() bool {
+	return r.TransactionType == 0 || r.TransactionType == TRANSACTION_TYPE_INCOME || r.TransactionType == TRANSACTION_TYPE_EXPENSE || r.TransactionType == TRANSACTION_TYPE_TRANSFER
+}
+
+// HasConditions returns whether the transaction rule has any condition except transaction type
+func (r *TransactionRule) HasConditions() bool {
+	return r.CommentKeyword != "" || r.CommentRegex != "" || r.MinAmount != nil || r.MaxAmount != nil || r.AccountId != 0 || r.CounterpartyKeyword != "" || r.OriginalCategoryName != ""
+}
+
+// HasActions returns whether the transaction rule has any action
+func (r *TransactionRule) HasActions() bool {
+	return r.SetCategoryId != 0 || r.AddTagIds != "" || r.RewriteComment != "" || r.SetHideAmount
+}
+
+// GetAddTagIds returns all tag ids which the transaction rule adds to transaction
+func (r *TransactionRule) GetAddTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if r.AddTagIds != "" {
+		tagIds = strings.Split(r.AddTagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// IsMatch returns whether the specified transaction matches all conditions of the transaction rule, the compiled comment regular expression is required if the rule has comment regular expression condition
+func (r *TransactionRule) IsMatch(target *TransactionRuleTarget, commentRegex *regexp.Regexp) bool {
+	if target.Type == TRANSACTION_TYPE_MODIFY_BALANCE {
+		return false
+	}
+
+	if r.TransactionType != 0 && r.TransactionType != target.Type {
+		return false
+	}
+
+	if r.CommentKeyword != "" && !strings.Contains(strings.ToLower(target.Comment), strings.ToLower(r.CommentKeyword)) {
+		return false
+	}
+
+	if r.CommentRegex != "" && (commentRegex == nil || !commentRegex.MatchString(target.Comment)) {
+		return false
+	}
+
+	if r.MinAmount != nil && target.Amount < *r.MinAmount {
+		return false
+	}
+
+	if r.MaxAmount != nil && target.Amount > *r.MaxAmount {
+		return false
+	}
+
+	if r.AccountId != 0 && r.AccountId != target.AccountId && (target.Type != TRANSACTION_TYPE_TRANSFER || r.AccountId != target.RelatedAccountId) {
+		return false
+	}
+
+	if r.CounterpartyKeyword != "" && !strings.Contains(strings.ToLower(target.Counterparty), strings.ToLower(r.CounterpartyKeyword)) {
+		return false
+	}
+
+	if r.OriginalCategoryName != "" && !strings.EqualFold(strings.TrimSpace(target.OriginalCategoryName), strings.TrimSpace(r.OriginalCategoryName)) {
+		return false
+	}
+
+	return true
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	return &TransactionRuleInfoResponse{
+		Id:                   r.RuleId,
+		Name:                 r.Name,
+		TransactionType:      r.TransactionType,
+		CommentKeyword:       r.CommentKeyword,
+		CommentRegex:         r.CommentRegex,
+		MinAmount:            r.MinAmount,
+		MaxAmount:            r.MaxAmount,
+		AccountId:            r.AccountId,
+		CounterpartyKeyword:  r.CounterpartyKeyword,
+		OriginalCategoryName: r.OriginalCategoryName,
+		SetCategoryId:        r.SetCategoryId,
+		AddTagIds:            utils.Int64ArrayToStringArray(r.GetAddTagIds()),
+		RewriteComment:       r.RewriteComment,
+		SetHideAmount:        r.SetHideAmount,
+		StopProcessing:       r.StopProcessing,
+		DisplayOrder:         r.DisplayOrder,
+		Disabled:             r.Disabled,
+	}
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRuleHasConditionsAndActions(t *testing.T) {
+	rule := &TransactionRule{}
+	assert.False(t, rule.HasConditions())
+	assert.False(t, rule.HasActions())
+
+	minAmount := int64(100)
+	rule.MinAmount = &minAmount
+	rule.SetHideAmount = true
+	assert.True(t, rule.HasConditions())
+	assert.True(t, rule.HasActions())
+}
+
+func TestTransactionRuleGetAddTagIds(t *testing.T) {
+	rule := &TransactionRule{}
+	assert.Equal(t, []int64{}, rule.GetAddTagIds())
+
+	rule.AddTagIds = "1001,1002"
+	assert.Equal(t, []int64{1001, 1002}, rule.GetAddTagIds())
+}
+
+func TestTransactionRuleIsMatch_CommentKeyword(t *testing.T) {
+	rule := &TransactionRule{
+		TransactionType: TRANSACTION_TYPE_EXPENSE,
+		CommentKeyword:  "coffee",
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Comment: "Morning Coffee"}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Comment: "Lunch"}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_INCOME, Comment: "Coffee"}, nil))
+}
+
+func TestTransactionRuleIsMatch_CommentRegex(t *testing.T) {
+	rule := &TransactionRule{
+		CommentRegex: "^UBER\\s+\\*TRIP",
+	}
+	commentRegex := regexp.MustCompile(rule.CommentRegex)
+
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Comment: "UBER *TRIP 1234"}, commentRegex))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Comment: "UBER EATS"}, commentRegex))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Comment: "UBER *TRIP 1234"}, nil))
+}
+
+func TestTransactionRuleIsMatch_AmountRange(t *testing.T) {
+	minAmount := int64(1000)
+	maxAmount := int64(5000)
+	rule := &TransactionRule{
+		MinAmount: &minAmount,
+		MaxAmount: &maxAmount,
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Amount: 1000}, nil))
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Amount: 5000}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Amount: 999}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Amount: 5001}, nil))
+}
+
+func TestTransactionRuleIsMatch_Account(t *testing.T) {
+	rule := &TransactionRule{
+		AccountId: 2001,
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, AccountId: 2001}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, AccountId: 2002, RelatedAccountId: 2001}, nil))
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_TRANSFER, AccountId: 2002, RelatedAccountId: 2001}, nil))
+}
+
+func TestTransactionRuleIsMatch_CounterpartyAndOriginalCategoryName(t *testing.T) {
+	rule := &TransactionRule{
+		CounterpartyKeyword:  "starbucks",
+		OriginalCategoryName: "Food & Drink",
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Counterparty: "Starbucks Store #12", OriginalCategoryName: " food & drink "}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Counterparty: "Starbucks Store #12", OriginalCategoryName: "Food"}, nil))
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_EXPENSE, Counterparty: "", OriginalCategoryName: "Food & Drink"}, nil))
+}
+
+func TestTransactionRuleIsMatch_ModifyBalanceTransaction(t *testing.T) {
+	rule := &TransactionRule{
+		AccountId: 2001,
+	}
+
+	assert.False(t, rule.IsMatch(&TransactionRuleTarget{Type: TRANSACTION_TYPE_MODIFY_BALANCE, AccountId: 2001}, nil))
+}
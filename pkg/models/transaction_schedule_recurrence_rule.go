@@ -0,0 +1,544 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maxTransactionScheduleRecurrenceRuleInterval = 1000
+const maxTransactionScheduleRecurrenceRuleCount = 100000
+
+// TransactionScheduleRecurrenceFrequency represents the frequency of the recurrence rule of scheduled transaction template
+type TransactionScheduleRecurrenceFrequency string
+
+// Recurrence rule frequencies
+const (
+	TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_DAILY   TransactionScheduleRecurrenceFrequency = "DAILY"
+	TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY  TransactionScheduleRecurrenceFrequency = "WEEKLY"
+	TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY TransactionScheduleRecurrenceFrequency = "MONTHLY"
+	TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_YEARLY  TransactionScheduleRecurrenceFrequency = "YEARLY"
+)
+
+var transactionScheduleRecurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// TransactionScheduleRecurrenceWeekday represents a weekday in the BYDAY part of recurrence rule, the ordinal is zero if every weekday in the period matches
+type TransactionScheduleRecurrenceWeekday struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// TransactionScheduleRecurrenceRule represents a recurrence rule (RFC 5545 RRULE) of scheduled transaction template, only the date based parts are supported
+type TransactionScheduleRecurrenceRule struct {
+	Frequency     TransactionScheduleRecurrenceFrequency
+	Interval      int
+	Count         int
+	UntilDate     int32
+	ByDay         []TransactionScheduleRecurrenceWeekday
+	ByMonthDay    []int
+	ByMonth       []int
+	BySetPos      []int
+	WeekStart     time.Weekday
+	StartDate     int32
+	ExcludedDates map[int32]bool
+}
+
+// ParseTransactionScheduleRecurrenceRule returns the recurrence rule according to the textual RRULE, which can be either a single RRULE value or the DTSTART, RRULE and EXDATE lines
+func ParseTransactionScheduleRecurrenceRule(content string) (*TransactionScheduleRecurrenceRule, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	rule := &TransactionScheduleRecurrenceRule{
+		Interval:      1,
+		WeekStart:     time.Monday,
+		ExcludedDates: make(map[int32]bool),
+	}
+	hasRule := false
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		name, value := parseTransactionScheduleRecurrenceRuleLine(line)
+
+		if name == "DTSTART" {
+			startDate, err := parseTransactionScheduleRecurrenceRuleDate(value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			rule.StartDate = startDate
+		} else if name == "EXDATE" {
+			excludedDates := strings.Split(value, ",")
+
+			for j := 0; j < len(excludedDates); j++ {
+				excludedDate, err := parseTransactionScheduleRecurrenceRuleDate(excludedDates[j])
+
+				if err != nil {
+					return nil, err
+				}
+
+				rule.ExcludedDates[excludedDate] = true
+			}
+		} else if name == "RRULE" && !hasRule {
+			err := rule.parseRuleParts(value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			hasRule = true
+		} else {
+			return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+	}
+
+	if !hasRule {
+		return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	return rule, nil
+}
+
+// GetOccurrenceDates returns the dates (yyyyMMdd) of all occurrences between the from date and the to date, the default start date is used if the rule has no DTSTART, the max count is unlimited if it is zero
+func (r *TransactionScheduleRecurrenceRule) GetOccurrenceDates(defaultStartDate int32, fromDate int32, toDate int32, maxCount int) []int32 {
+	startDate := r.StartDate
+
+	if startDate == 0 {
+		startDate = defaultStartDate
+	}
+
+	start := getTransactionScheduleRecurrenceRuleDateTime(startDate)
+	periodStart := start
+
+	if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY {
+		periodStart = start.AddDate(0, 0, -((int(start.Weekday()) - int(r.WeekStart) + 7) % 7))
+	} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY {
+		periodStart = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_YEARLY {
+		periodStart = time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	occurrenceDates := make([]int32, 0)
+	occurrenceCount := 0
+
+	for periodIndex := 0; getTransactionScheduleRecurrenceRuleDate(periodStart) <= toDate; periodIndex++ {
+		candidates := r.applyBySetPos(r.getPeriodCandidates(periodStart, start))
+
+		for i := 0; i < len(candidates); i++ {
+			candidateDate := getTransactionScheduleRecurrenceRuleDate(candidates[i])
+
+			if candidateDate < startDate {
+				continue
+			}
+
+			if (r.UntilDate > 0 && candidateDate > r.UntilDate) || candidateDate > toDate {
+				return occurrenceDates
+			}
+
+			occurrenceCount++
+
+			if r.Count > 0 && occurrenceCount > r.Count {
+				return occurrenceDates
+			}
+
+			if candidateDate < fromDate || r.ExcludedDates[candidateDate] {
+				continue
+			}
+
+			occurrenceDates = append(occurrenceDates, candidateDate)
+
+			if maxCount > 0 && len(occurrenceDates) >= maxCount {
+				return occurrenceDates
+			}
+		}
+
+		if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_DAILY {
+			periodStart = start.AddDate(0, 0, (periodIndex+1)*r.Interval)
+		} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY {
+			periodStart = periodStart.AddDate(0, 0, 7*r.Interval)
+		} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY {
+			periodStart = periodStart.AddDate(0, r.Interval, 0)
+		} else {
+			periodStart = periodStart.AddDate(r.Interval, 0, 0)
+		}
+	}
+
+	return occurrenceDates
+}
+
+func (r *TransactionScheduleRecurrenceRule) parseRuleParts(value string) error {
+	parts := strings.Split(value, ";")
+	existedNames := make(map[string]bool, len(parts))
+
+	for i := 0; i < len(parts); i++ {
+		part := strings.TrimSpace(parts[i])
+
+		if part == "" {
+			continue
+		}
+
+		nameAndValue := strings.SplitN(part, "=", 2)
+
+		if len(nameAndValue) != 2 || nameAndValue[1] == "" {
+			return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		name := strings.ToUpper(strings.TrimSpace(nameAndValue[0]))
+		partValue := strings.ToUpper(strings.TrimSpace(nameAndValue[1]))
+
+		if existedNames[name] {
+			return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		existedNames[name] = true
+
+		var err error
+
+		switch name {
+		case "FREQ":
+			r.Frequency = TransactionScheduleRecurrenceFrequency(partValue)
+
+			if r.Frequency != TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_DAILY &&
+				r.Frequency != TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY &&
+				r.Frequency != TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY &&
+				r.Frequency != TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_YEARLY {
+				return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+			}
+		case "INTERVAL":
+			r.Interval, err = parseTransactionScheduleRecurrenceRuleNumber(partValue, 1, maxTransactionScheduleRecurrenceRuleInterval)
+		case "COUNT":
+			r.Count, err = parseTransactionScheduleRecurrenceRuleNumber(partValue, 1, maxTransactionScheduleRecurrenceRuleCount)
+		case "UNTIL":
+			r.UntilDate, err = parseTransactionScheduleRecurrenceRuleDate(partValue)
+		case "BYDAY":
+			r.ByDay, err = parseTransactionScheduleRecurrenceRuleWeekdays(partValue)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseTransactionScheduleRecurrenceRuleNumbers(partValue, 31)
+		case "BYMONTH":
+			r.ByMonth, err = parseTransactionScheduleRecurrenceRuleNumbers(partValue, 12)
+
+			for j := 0; err == nil && j < len(r.ByMonth); j++ {
+				if r.ByMonth[j] < 0 {
+					err = errs.ErrScheduledTransactionRecurrenceRuleInvalid
+				}
+			}
+
+			sort.Ints(r.ByMonth)
+		case "BYSETPOS":
+			r.BySetPos, err = parseTransactionScheduleRecurrenceRuleNumbers(partValue, 366)
+		case "WKST":
+			weekday, exists := transactionScheduleRecurrenceWeekdays[partValue]
+
+			if !exists {
+				return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+			}
+
+			r.WeekStart = weekday
+		default:
+			return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Frequency == "" || (r.Count > 0 && r.UntilDate > 0) {
+		return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	if len(r.BySetPos) > 0 && len(r.ByDay) < 1 && len(r.ByMonthDay) < 1 && len(r.ByMonth) < 1 {
+		return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY && len(r.ByMonthDay) > 0 {
+		return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_DAILY || r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY {
+		for i := 0; i < len(r.ByDay); i++ {
+			if r.ByDay[i].Ordinal != 0 {
+				return errs.ErrScheduledTransactionRecurrenceRuleInvalid
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *TransactionScheduleRecurrenceRule) getPeriodCandidates(periodStart time.Time, start time.Time) []time.Time {
+	candidates := make([]time.Time, 0)
+
+	if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_DAILY {
+		if r.isMonthMatched(periodStart) && r.isMonthDayMatched(periodStart) && r.isWeekdayMatched(periodStart, periodStart, periodStart) {
+			candidates = append(candidates, periodStart)
+		}
+	} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY {
+		for i := 0; i < 7; i++ {
+			date := periodStart.AddDate(0, 0, i)
+
+			if len(r.ByDay) < 1 && date.Weekday() != start.Weekday() {
+				continue
+			}
+
+			if r.isMonthMatched(date) && r.isWeekdayMatched(date, date, date) {
+				candidates = append(candidates, date)
+			}
+		}
+	} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY {
+		if r.isMonthMatched(periodStart) {
+			candidates = r.getMonthCandidates(periodStart, start, periodStart, periodStart.AddDate(0, 1, -1))
+		}
+	} else if r.Frequency == TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_YEARLY {
+		yearEnd := periodStart.AddDate(1, 0, -1)
+
+		if len(r.ByMonth) < 1 && len(r.ByMonthDay) < 1 && len(r.ByDay) < 1 {
+			monthStart := time.Date(periodStart.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+			candidates = r.getMonthCandidates(monthStart, start, monthStart, monthStart.AddDate(0, 1, -1))
+		} else if len(r.ByMonth) < 1 && len(r.ByMonthDay) < 1 {
+			for date := periodStart; !date.After(yearEnd); date = date.AddDate(0, 0, 1) {
+				if r.isWeekdayMatched(date, periodStart, yearEnd) {
+					candidates = append(candidates, date)
+				}
+			}
+		} else {
+			for month := 1; month <= 12; month++ {
+				monthStart := time.Date(periodStart.Year(), time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+				if !r.isMonthMatched(monthStart) {
+					continue
+				}
+
+				if len(r.ByMonth) > 0 {
+					candidates = append(candidates, r.getMonthCandidates(monthStart, start, monthStart, monthStart.AddDate(0, 1, -1))...)
+				} else {
+					candidates = append(candidates, r.getMonthCandidates(monthStart, start, periodStart, yearEnd)...)
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+func (r *TransactionScheduleRecurrenceRule) getMonthCandidates(monthStart time.Time, start time.Time, scopeStart time.Time, scopeEnd time.Time) []time.Time {
+	candidates := make([]time.Time, 0)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	for date := monthStart; !date.After(monthEnd); date = date.AddDate(0, 0, 1) {
+		if len(r.ByMonthDay) < 1 && len(r.ByDay) < 1 {
+			if date.Day() == start.Day() {
+				candidates = append(candidates, date)
+			}
+
+			continue
+		}
+
+		if r.isMonthDayMatched(date) && r.isWeekdayMatched(date, scopeStart, scopeEnd) {
+			candidates = append(candidates, date)
+		}
+	}
+
+	return candidates
+}
+
+func (r *TransactionScheduleRecurrenceRule) applyBySetPos(candidates []time.Time) []time.Time {
+	if len(r.BySetPos) < 1 || len(candidates) < 1 {
+		return candidates
+	}
+
+	selectedIndexes := make(map[int]bool, len(r.BySetPos))
+
+	for i := 0; i < len(r.BySetPos); i++ {
+		index := r.BySetPos[i] - 1
+
+		if r.BySetPos[i] < 0 {
+			index = len(candidates) + r.BySetPos[i]
+		}
+
+		if index >= 0 && index < len(candidates) {
+			selectedIndexes[index] = true
+		}
+	}
+
+	selectedCandidates := make([]time.Time, 0, len(selectedIndexes))
+
+	for i := 0; i < len(candidates); i++ {
+		if selectedIndexes[i] {
+			selectedCandidates = append(selectedCandidates, candidates[i])
+		}
+	}
+
+	return selectedCandidates
+}
+
+func (r *TransactionScheduleRecurrenceRule) isMonthMatched(date time.Time) bool {
+	if len(r.ByMonth) < 1 {
+		return true
+	}
+
+	for i := 0; i < len(r.ByMonth); i++ {
+		if int(date.Month()) == r.ByMonth[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *TransactionScheduleRecurrenceRule) isMonthDayMatched(date time.Time) bool {
+	if len(r.ByMonthDay) < 1 {
+		return true
+	}
+
+	maxDayOfMonth := utils.GetMaxDayOfMonth(date.Year(), date.Month())
+
+	for i := 0; i < len(r.ByMonthDay); i++ {
+		if date.Day() == r.ByMonthDay[i] || (r.ByMonthDay[i] < 0 && date.Day() == maxDayOfMonth+r.ByMonthDay[i]+1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *TransactionScheduleRecurrenceRule) isWeekdayMatched(date time.Time, scopeStart time.Time, scopeEnd time.Time) bool {
+	if len(r.ByDay) < 1 {
+		return true
+	}
+
+	ordinal := int(date.Sub(scopeStart).Hours()/24)/7 + 1
+	reverseOrdinal := -(int(scopeEnd.Sub(date).Hours()/24)/7 + 1)
+
+	for i := 0; i < len(r.ByDay); i++ {
+		if r.ByDay[i].Weekday != date.Weekday() {
+			continue
+		}
+
+		if r.ByDay[i].Ordinal == 0 || r.ByDay[i].Ordinal == ordinal || r.ByDay[i].Ordinal == reverseOrdinal {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseTransactionScheduleRecurrenceRuleLine(line string) (string, string) {
+	colonIndex := strings.Index(line, ":")
+	equalIndex := strings.Index(line, "=")
+
+	if colonIndex < 0 || (equalIndex >= 0 && equalIndex < colonIndex && !strings.Contains(line[:colonIndex], ";")) {
+		return "RRULE", line
+	}
+
+	name := strings.ToUpper(line[:colonIndex])
+
+	if semicolonIndex := strings.Index(name, ";"); semicolonIndex >= 0 {
+		name = name[:semicolonIndex]
+	}
+
+	return strings.TrimSpace(name), strings.TrimSpace(line[colonIndex+1:])
+}
+
+func parseTransactionScheduleRecurrenceRuleDate(value string) (int32, error) {
+	value = strings.TrimSpace(value)
+
+	if len(value) < 8 {
+		return 0, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+
+	if err != nil {
+		return 0, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	return getTransactionScheduleRecurrenceRuleDate(date), nil
+}
+
+func parseTransactionScheduleRecurrenceRuleNumber(value string, minValue int, maxValue int) (int, error) {
+	number, err := utils.StringToInt(value)
+
+	if err != nil || number < minValue || number > maxValue {
+		return 0, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+	}
+
+	return number, nil
+}
+
+func parseTransactionScheduleRecurrenceRuleNumbers(value string, maxAbsValue int) ([]int, error) {
+	items := strings.Split(value, ",")
+	numbers := make([]int, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		number, err := parseTransactionScheduleRecurrenceRuleNumber(strings.TrimPrefix(items[i], "+"), -maxAbsValue, maxAbsValue)
+
+		if err != nil || number == 0 {
+			return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+func parseTransactionScheduleRecurrenceRuleWeekdays(value string) ([]TransactionScheduleRecurrenceWeekday, error) {
+	items := strings.Split(value, ",")
+	weekdays := make([]TransactionScheduleRecurrenceWeekday, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+
+		if len(item) < 2 {
+			return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		weekday, exists := transactionScheduleRecurrenceWeekdays[item[len(item)-2:]]
+
+		if !exists {
+			return nil, errs.ErrScheduledTransactionRecurrenceRuleInvalid
+		}
+
+		ordinal := 0
+
+		if len(item) > 2 {
+			number, err := parseTransactionScheduleRecurrenceRuleNumbers(item[:len(item)-2], 53)
+
+			if err != nil {
+				return nil, err
+			}
+
+			ordinal = number[0]
+		}
+
+		weekdays = append(weekdays, TransactionScheduleRecurrenceWeekday{
+			Ordinal: ordinal,
+			Weekday: weekday,
+		})
+	}
+
+	return weekdays, nil
+}
+
+func getTransactionScheduleRecurrenceRuleDate(date time.Time) int32 {
+	return int32(date.Year()*10000 + int(date.Month())*100 + date.Day())
+}
+
+func getTransactionScheduleRecurrenceRuleDateTime(date int32) time.Time {
+	return time.Date(int(date/10000), time.Month((date%10000)/100), int(date%100), 0, 0, 0, 0, time.UTC)
+}
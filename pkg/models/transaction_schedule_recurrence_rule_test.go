@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestParseTransactionScheduleRecurrenceRule(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("DTSTART;VALUE=DATE:20240105\nRRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU,-1FR;COUNT=5;WKST=SU\nEXDATE:20240312,20240405T000000Z")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_MONTHLY, rule.Frequency)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, 5, rule.Count)
+	assert.Equal(t, time.Sunday, rule.WeekStart)
+	assert.Equal(t, int32(20240105), rule.StartDate)
+	assert.Equal(t, []TransactionScheduleRecurrenceWeekday{{Ordinal: 2, Weekday: time.Tuesday}, {Ordinal: -1, Weekday: time.Friday}}, rule.ByDay)
+	assert.True(t, rule.ExcludedDates[20240312])
+	assert.True(t, rule.ExcludedDates[20240405])
+
+	rule, err = ParseTransactionScheduleRecurrenceRule("FREQ=WEEKLY;UNTIL=20241231T235959Z")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SCHEDULE_RECURRENCE_FREQUENCY_WEEKLY, rule.Frequency)
+	assert.Equal(t, 1, rule.Interval)
+	assert.Equal(t, int32(20241231), rule.UntilDate)
+}
+
+func TestParseTransactionScheduleRecurrenceRule_InvalidRule(t *testing.T) {
+	invalidRules := []string{
+		"",
+		"EXDATE:20240101",
+		"RRULE:INTERVAL=2",
+		"RRULE:FREQ=HOURLY",
+		"RRULE:FREQ=DAILY;BYHOUR=10",
+		"RRULE:FREQ=DAILY;INTERVAL=0",
+		"RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		"RRULE:FREQ=DAILY;FREQ=WEEKLY",
+		"RRULE:FREQ=WEEKLY;BYDAY=1MO",
+		"RRULE:FREQ=WEEKLY;BYMONTHDAY=1",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=0",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=32",
+		"RRULE:FREQ=MONTHLY;BYDAY=XX",
+		"RRULE:FREQ=YEARLY;BYMONTH=13",
+		"RRULE:FREQ=MONTHLY;BYSETPOS=1",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=1\nEXDATE:2024",
+		"DTSTART:20240230\nRRULE:FREQ=MONTHLY",
+	}
+
+	for i := 0; i < len(invalidRules); i++ {
+		_, err := ParseTransactionScheduleRecurrenceRule(invalidRules[i])
+		assert.Equal(t, errs.ErrScheduledTransactionRecurrenceRuleInvalid, err, invalidRules[i])
+	}
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_EveryTwoWeeks(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR")
+	assert.Nil(t, err)
+
+	// 2024-01-03 is Wednesday
+	actualValue := rule.GetOccurrenceDates(20240103, 20240101, 20240131, 0)
+	assert.Equal(t, []int32{20240105, 20240115, 20240119, 20240129}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_LastDayOfMonth(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("RRULE:FREQ=MONTHLY;BYMONTHDAY=-1")
+	assert.Nil(t, err)
+
+	actualValue := rule.GetOccurrenceDates(20240115, 20240101, 20240531, 0)
+	assert.Equal(t, []int32{20240131, 20240229, 20240331, 20240430, 20240531}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_NthWeekdayOfMonth(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("RRULE:FREQ=MONTHLY;BYDAY=2TU,-1FR")
+	assert.Nil(t, err)
+
+	actualValue := rule.GetOccurrenceDates(20240101, 20240101, 20240229, 0)
+	assert.Equal(t, []int32{20240109, 20240126, 20240213, 20240223}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_FirstBusinessDayAfterDay(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYMONTHDAY=26,27,28,29,30,31;BYSETPOS=1")
+	assert.Nil(t, err)
+
+	// 2024-05-26 is Sunday, 2024-10-26 is Saturday
+	actualValue := rule.GetOccurrenceDates(20240101, 20240501, 20241031, 0)
+	assert.Equal(t, []int32{20240527, 20240626, 20240726, 20240826, 20240926, 20241028}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_ExcludedDatesAndCount(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("DTSTART:20240101\nRRULE:FREQ=DAILY;INTERVAL=3;COUNT=5\nEXDATE:20240107")
+	assert.Nil(t, err)
+
+	actualValue := rule.GetOccurrenceDates(20230101, 20240104, 20241231, 0)
+	assert.Equal(t, []int32{20240104, 20240110, 20240113}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_YearlyWithUntilAndMaxCount(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("RRULE:FREQ=YEARLY;BYMONTH=2,8;BYMONTHDAY=-1;UNTIL=20260301")
+	assert.Nil(t, err)
+
+	actualValue := rule.GetOccurrenceDates(20240101, 20240101, 20301231, 0)
+	assert.Equal(t, []int32{20240229, 20240831, 20250228, 20250831, 20260228}, actualValue)
+
+	actualValue = rule.GetOccurrenceDates(20240101, 20240301, 20301231, 2)
+	assert.Equal(t, []int32{20240831, 20250228}, actualValue)
+}
+
+func TestTransactionScheduleRecurrenceRuleGetOccurrenceDates_YearlyWithWeekdayInYear(t *testing.T) {
+	rule, err := ParseTransactionScheduleRecurrenceRule("RRULE:FREQ=YEARLY;BYDAY=-1MO")
+	assert.Nil(t, err)
+
+	actualValue := rule.GetOccurrenceDates(20240101, 20240101, 20251231, 0)
+	assert.Equal(t, []int32{20241230, 20251229}, actualValue)
+}
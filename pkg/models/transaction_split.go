@@ -0,0 +1,41 @@
+package models
+
+// TransactionSplit represents a category line of split transaction stored in database
+type TransactionSplit struct {
+	SplitId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_transaction_time) INDEX(IDX_transaction_split_uid_deleted_category_id) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_transaction_time) INDEX(IDX_transaction_split_uid_deleted_category_id) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	TransactionTime int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_time) NOT NULL"`
+	CategoryId      int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_category_id) NOT NULL"`
+	Amount          int64  `xorm:"NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionSplitCreateRequest represents all parameters of a category line in transaction creation or modification request
+type TransactionSplitCreateRequest struct {
+	CategoryId int64  `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64  `json:"amount" binding:"min=-99999999999,max=99999999999"`
+	Comment    string `json:"comment" binding:"max=255"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split
+type TransactionSplitInfoResponse struct {
+	CategoryId int64                            `json:"categoryId,string"`
+	Category   *TransactionCategoryInfoResponse `json:"category,omitempty"`
+	Amount     int64                            `json:"amount"`
+	Comment    string                           `json:"comment"`
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		Comment:    s.Comment,
+	}
+}
@@ -3,11 +3,12 @@ package models
 // TransactionTag represents transaction tag data stored in database
 type TransactionTag struct {
 	TagId           int64  `xorm:"PK"`
-	Uid             int64  `xorm:"INDEX(IDX_tag_uid_deleted_group_order) NOT NULL"`
-	Deleted         bool   `xorm:"INDEX(IDX_tag_uid_deleted_group_order) NOT NULL"`
-	TagGroupId      int64  `xorm:"INDEX(IDX_tag_uid_deleted_group_order) NOT NULL DEFAULT 0"`
+	Uid             int64  `xorm:"INDEX(IDX_tag_uid_ledger_id_deleted_group_order) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_tag_uid_ledger_id_deleted_group_order) NOT NULL DEFAULT 0"`
+	Deleted         bool   `xorm:"INDEX(IDX_tag_uid_ledger_id_deleted_group_order) NOT NULL"`
+	TagGroupId      int64  `xorm:"INDEX(IDX_tag_uid_ledger_id_deleted_group_order) NOT NULL DEFAULT 0"`
 	Name            string `xorm:"VARCHAR(64) NOT NULL"`
-	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_uid_deleted_group_order) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_uid_ledger_id_deleted_group_order) NOT NULL"`
 	Hidden          bool   `xorm:"NOT NULL"`
 	CreatedUnixTime int64
 	UpdatedUnixTime int64
@@ -61,6 +62,16 @@ type TransactionTagDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTagRestoreRequest represents all parameters of deleted transaction tag restoring request
+type TransactionTagRestoreRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionTagPurgeRequest represents all parameters of deleted transaction tag purging request
+type TransactionTagPurgeRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
 // TransactionTagInfoResponse represents a view-object of transaction tag
 type TransactionTagInfoResponse struct {
 	Id           int64  `json:"id,string"`
@@ -70,6 +81,12 @@ type TransactionTagInfoResponse struct {
 	Hidden       bool   `json:"hidden"`
 }
 
+// DeletedTransactionTagInfoResponse represents a view-object of deleted transaction tag in trash bin
+type DeletedTransactionTagInfoResponse struct {
+	*TransactionTagInfoResponse
+	DeletedTime int64 `json:"deletedTime"`
+}
+
 // FillFromOtherTag fills all the fields in this current tag from other transaction tag
 func (t *TransactionTag) FillFromOtherTag(tag *TransactionTag) {
 	t.TagId = tag.TagId
@@ -95,6 +112,14 @@ func (t *TransactionTag) ToTransactionTagInfoResponse() *TransactionTagInfoRespo
 	}
 }
 
+// ToDeletedTransactionTagInfoResponse returns a view-object of deleted transaction tag according to database model
+func (t *TransactionTag) ToDeletedTransactionTagInfoResponse() *DeletedTransactionTagInfoResponse {
+	return &DeletedTransactionTagInfoResponse{
+		TransactionTagInfoResponse: t.ToTransactionTagInfoResponse(),
+		DeletedTime:                t.DeletedUnixTime,
+	}
+}
+
 // TransactionTagInfoResponseSlice represents the slice data structure of TransactionTagInfoResponse
 type TransactionTagInfoResponseSlice []*TransactionTagInfoResponse
 
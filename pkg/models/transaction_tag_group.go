@@ -3,10 +3,11 @@ package models
 // TransactionTagGroup represents transaction tag group data stored in database
 type TransactionTagGroup struct {
 	TagGroupId      int64  `xorm:"PK"`
-	Uid             int64  `xorm:"INDEX(IDX_tag_group_uid_deleted_order) NOT NULL"`
-	Deleted         bool   `xorm:"INDEX(IDX_tag_group_uid_deleted_order) NOT NULL"`
+	Uid             int64  `xorm:"INDEX(IDX_tag_group_uid_ledger_id_deleted_order) NOT NULL"`
+	LedgerId        int64  `xorm:"INDEX(IDX_tag_group_uid_ledger_id_deleted_order) NOT NULL DEFAULT 0"`
+	Deleted         bool   `xorm:"INDEX(IDX_tag_group_uid_ledger_id_deleted_order) NOT NULL"`
 	Name            string `xorm:"VARCHAR(64) NOT NULL"`
-	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_group_uid_deleted_order) NOT NULL"`
+	DisplayOrder    int32  `xorm:"INDEX(IDX_tag_group_uid_ledger_id_deleted_order) NOT NULL"`
 	CreatedUnixTime int64
 	UpdatedUnixTime int64
 	DeletedUnixTime int64
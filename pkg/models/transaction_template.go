@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
@@ -26,35 +27,41 @@ const (
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY  TransactionScheduleFrequencyType = 2
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY    TransactionScheduleFrequencyType = 3
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY   TransactionScheduleFrequencyType = 4
+	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE    TransactionScheduleFrequencyType = 5
 )
 
+const maxScheduledTransactionPreviewYears = 10
+
 // TransactionTemplate represents transaction template stored in database
 type TransactionTemplate struct {
-	TemplateId                 int64                            `xorm:"PK"`
-	Uid                        int64                            `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
-	Deleted                    bool                             `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
-	TemplateType               TransactionTemplateType          `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
-	Name                       string                           `xorm:"VARCHAR(64) NOT NULL"`
-	Type                       TransactionType                  `xorm:"NOT NULL"`
-	CategoryId                 int64                            `xorm:"NOT NULL"`
-	AccountId                  int64                            `xorm:"NOT NULL"`
-	ScheduledFrequencyType     TransactionScheduleFrequencyType `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
-	ScheduledFrequency         string                           `xorm:"VARCHAR(100)"`
-	ScheduledStartTime         *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
-	ScheduledEndTime           *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
-	ScheduledAt                int16                            `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
-	ScheduledTimezoneUtcOffset int16
-	TagIds                     string `xorm:"VARCHAR(255) NOT NULL"`
-	Amount                     int64  `xorm:"NOT NULL"`
-	RelatedAccountId           int64  `xorm:"NOT NULL"`
-	RelatedAccountAmount       int64  `xorm:"NOT NULL"`
-	HideAmount                 bool   `xorm:"NOT NULL"`
-	Comment                    string `xorm:"VARCHAR(255) NOT NULL"`
-	DisplayOrder               int32  `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
-	Hidden                     bool   `xorm:"NOT NULL"`
-	CreatedUnixTime            int64
-	UpdatedUnixTime            int64
-	DeletedUnixTime            int64
+	TemplateId                  int64                            `xorm:"PK"`
+	Uid                         int64                            `xorm:"INDEX(IDX_transaction_template_uid_ledger_id_deleted_template_type_order) NOT NULL"`
+	LedgerId                    int64                            `xorm:"INDEX(IDX_transaction_template_uid_ledger_id_deleted_template_type_order) NOT NULL DEFAULT 0"`
+	Deleted                     bool                             `xorm:"INDEX(IDX_transaction_template_uid_ledger_id_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
+	TemplateType                TransactionTemplateType          `xorm:"INDEX(IDX_transaction_template_uid_ledger_id_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time) NOT NULL"`
+	Name                        string                           `xorm:"VARCHAR(64) NOT NULL"`
+	Type                        TransactionType                  `xorm:"NOT NULL"`
+	CategoryId                  int64                            `xorm:"NOT NULL"`
+	AccountId                   int64                            `xorm:"NOT NULL"`
+	ScheduledFrequencyType      TransactionScheduleFrequencyType `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
+	ScheduledFrequency          string                           `xorm:"VARCHAR(1000)"`
+	ScheduledStartTime          *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
+	ScheduledEndTime            *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
+	ScheduledAt                 int16                            `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
+	ScheduledTimezoneUtcOffset  int16
+	ScheduledLastOccurrenceTime int64  `xorm:"NOT NULL DEFAULT 0"`
+	TagIds                      string `xorm:"VARCHAR(255) NOT NULL"`
+	Amount                      int64  `xorm:"NOT NULL"`
+	RelatedAccountId            int64  `xorm:"NOT NULL"`
+	RelatedAccountAmount        int64  `xorm:"NOT NULL"`
+	HideAmount                  bool   `xorm:"NOT NULL"`
+	Comment                     string `xorm:"VARCHAR(255) NOT NULL"`
+	AmortizationScheduleId      int64  `xorm:"NOT NULL DEFAULT 0"`
+	DisplayOrder                int32  `xorm:"INDEX(IDX_transaction_template_uid_ledger_id_deleted_template_type_order) NOT NULL"`
+	Hidden                      bool   `xorm:"NOT NULL"`
+	CreatedUnixTime             int64
+	UpdatedUnixTime             int64
+	DeletedUnixTime             int64
 }
 
 // TransactionTemplateListRequest represents all parameters of transaction template list request
@@ -81,7 +88,7 @@ type TransactionTemplateCreateRequest struct {
 	TagIds                     []string                          `json:"tagIds"`
 	Comment                    string                            `json:"comment" binding:"max=255"`
 	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
-	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
+	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty,max=1000"`
 	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
 	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
@@ -108,7 +115,7 @@ type TransactionTemplateModifyRequest struct {
 	TagIds                     []string                          `json:"tagIds"`
 	Comment                    string                            `json:"comment" binding:"max=255"`
 	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
-	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
+	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty,max=1000"`
 	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
 	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
@@ -136,6 +143,25 @@ type TransactionTemplateDeleteRequest struct {
 	Id int64 `json:"id,string" binding:"required,min=1"`
 }
 
+// TransactionTemplateScheduledPreviewRequest represents all parameters of scheduled transaction occurrences preview request
+type TransactionTemplateScheduledPreviewRequest struct {
+	Id                         int64                            `form:"id,string" binding:"omitempty,min=1"`
+	ScheduledFrequencyType     TransactionScheduleFrequencyType `form:"scheduled_frequency_type"`
+	ScheduledFrequency         string                           `form:"scheduled_frequency" binding:"max=1000"`
+	ScheduledStartDate         string                           `form:"scheduled_start_date"`
+	ScheduledEndDate           string                           `form:"scheduled_end_date"`
+	ScheduledTimezoneUtcOffset int16                            `form:"utc_offset" binding:"min=-720,max=840"`
+	Count                      int                              `form:"count" binding:"required,min=1,max=100"`
+}
+
+// TransactionTemplateScheduledOccurrenceResponse represents a view-object of one transaction which would be created by scheduled transaction template
+type TransactionTemplateScheduledOccurrenceResponse struct {
+	Date         string  `json:"date"`
+	Time         int64   `json:"time"`
+	Skipped      bool    `json:"skipped,omitempty"`
+	OriginalDate *string `json:"originalDate,omitempty"`
+}
+
 type TransactionTemplateInfoResponse struct {
 	*TransactionInfoResponse
 	TemplateType           TransactionTemplateType           `json:"templateType"`
@@ -145,6 +171,7 @@ type TransactionTemplateInfoResponse struct {
 	ScheduledStartDate     *string                           `json:"scheduledStartDate" binding:"omitempty"`
 	ScheduledEndDate       *string                           `json:"scheduledEndDate" binding:"omitempty"`
 	ScheduledAt            *int16                            `json:"scheduledAt,omitempty"`
+	AmortizationScheduleId int64                             `json:"amortizationScheduleId,string,omitempty"`
 	DisplayOrder           int32                             `json:"displayOrder"`
 	Hidden                 bool                              `json:"hidden"`
 }
@@ -162,6 +189,117 @@ func (t *TransactionTemplate) GetTagIds() []int64 {
 	return result
 }
 
+// GetScheduledTransactionTimes returns the unix times of all transactions which would be created by the scheduled transaction template between the start and end unix time
+func (t *TransactionTemplate) GetScheduledTransactionTimes(startUnixTime int64, endUnixTime int64) ([]int64, error) {
+	return t.getScheduledTransactionTimes(startUnixTime, endUnixTime, 0)
+}
+
+// GetNextScheduledTransactionTimes returns the unix times of the next transactions which would be created by the scheduled transaction template since the start unix time
+func (t *TransactionTemplate) GetNextScheduledTransactionTimes(startUnixTime int64, count int) ([]int64, error) {
+	endUnixTime := time.Unix(startUnixTime, 0).AddDate(maxScheduledTransactionPreviewYears, 0, 0).Unix()
+	return t.getScheduledTransactionTimes(startUnixTime, endUnixTime, count)
+}
+
+func (t *TransactionTemplate) getScheduledTransactionTimes(startUnixTime int64, endUnixTime int64, maxCount int) ([]int64, error) {
+	if t.TemplateType != TRANSACTION_TEMPLATE_TYPE_SCHEDULE || t.ScheduledFrequency == "" {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	if t.ScheduledStartTime != nil && *t.ScheduledStartTime > startUnixTime {
+		startUnixTime = *t.ScheduledStartTime
+	}
+
+	if t.ScheduledEndTime != nil && *t.ScheduledEndTime < endUnixTime {
+		endUnixTime = *t.ScheduledEndTime
+	}
+
+	templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+	startTime := time.Unix(startUnixTime, 0).In(templateTimeZone)
+	firstTransactionTime := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, templateTimeZone)
+
+	if firstTransactionTime.Unix() < startUnixTime {
+		firstTransactionTime = firstTransactionTime.AddDate(0, 0, 1)
+	}
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+		return t.getRecurrenceRuleTransactionTimes(firstTransactionTime, endUnixTime, maxCount, templateTimeZone)
+	}
+
+	if t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(t.ScheduledFrequency, ","))
+
+	if err != nil {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	frequencyValueSet := utils.ToSet(frequencyValues)
+	transactionUnixTimes := make([]int64, 0)
+
+	for transactionTime := firstTransactionTime; transactionTime.Unix() <= endUnixTime; transactionTime = transactionTime.AddDate(0, 0, 1) {
+		if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
+			continue
+		} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY {
+			maxDayInMonth := int64(utils.GetMaxDayOfMonth(transactionTime.Year(), transactionTime.Month()))
+			day := int64(transactionTime.Day())
+
+			if !frequencyValueSet[day] && !frequencyValueSet[day-maxDayInMonth-1] {
+				continue
+			}
+		} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY && !frequencyValueSet[int64(transactionTime.Month())*100+int64(transactionTime.Day())] {
+			continue
+		}
+
+		transactionUnixTimes = append(transactionUnixTimes, transactionTime.Unix())
+
+		if maxCount > 0 && len(transactionUnixTimes) >= maxCount {
+			break
+		}
+	}
+
+	return transactionUnixTimes, nil
+}
+
+func (t *TransactionTemplate) getRecurrenceRuleTransactionTimes(firstTransactionTime time.Time, endUnixTime int64, maxCount int, templateTimeZone *time.Location) ([]int64, error) {
+	rule, err := ParseTransactionScheduleRecurrenceRule(t.ScheduledFrequency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the recurrence starts from the scheduled start date if the rule does not have DTSTART
+	defaultStartUnixTime := t.CreatedUnixTime
+
+	if t.ScheduledStartTime != nil {
+		defaultStartUnixTime = *t.ScheduledStartTime
+	} else if defaultStartUnixTime <= 0 {
+		defaultStartUnixTime = firstTransactionTime.Unix()
+	}
+
+	defaultStartDate := utils.FormatUnixTimeToNumericYearMonthDay(defaultStartUnixTime, templateTimeZone)
+	fromDate := utils.FormatUnixTimeToNumericYearMonthDay(firstTransactionTime.Unix(), templateTimeZone)
+	toDate := utils.FormatUnixTimeToNumericYearMonthDay(endUnixTime, templateTimeZone)
+
+	if fromDate > toDate {
+		return make([]int64, 0), nil
+	}
+
+	occurrenceDates := rule.GetOccurrenceDates(defaultStartDate, fromDate, toDate, maxCount)
+	transactionUnixTimes := make([]int64, len(occurrenceDates))
+
+	for i := 0; i < len(occurrenceDates); i++ {
+		occurrenceDate := occurrenceDates[i]
+		transactionUnixTimes[i] = time.Date(int(occurrenceDate/10000), time.Month((occurrenceDate%10000)/100), int(occurrenceDate%100), 0, 0, 0, 0, templateTimeZone).Unix()
+	}
+
+	return transactionUnixTimes, nil
+}
+
 // ToTransactionTemplateInfoResponse returns a view-object according to database model
 func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset int16) *TransactionTemplateInfoResponse {
 	utcOffset := serverUtcOffset
@@ -182,6 +320,7 @@ func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset
 		response.ScheduledFrequencyType = &t.ScheduledFrequencyType
 		response.ScheduledFrequency = &t.ScheduledFrequency
 		response.ScheduledAt = &t.ScheduledAt
+		response.AmortizationScheduleId = t.AmortizationScheduleId
 
 		templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
 
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionTemplateOccurrenceOverrideType represents the override type of one occurrence of scheduled transaction template
+type TransactionTemplateOccurrenceOverrideType byte
+
+// Transaction template occurrence override types
+const (
+	TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_SKIP     TransactionTemplateOccurrenceOverrideType = 1
+	TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE TransactionTemplateOccurrenceOverrideType = 2
+)
+
+// TransactionTemplateOccurrenceOverride represents the skipped or postponed occurrence of scheduled transaction template stored in database
+type TransactionTemplateOccurrenceOverride struct {
+	Uid             int64                                     `xorm:"PK NOT NULL"`
+	TemplateId      int64                                     `xorm:"PK NOT NULL"`
+	OccurrenceTime  int64                                     `xorm:"PK NOT NULL"`
+	OverrideType    TransactionTemplateOccurrenceOverrideType `xorm:"NOT NULL"`
+	PostponedTime   int64                                     `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnixTime int64
+}
+
+// TransactionTemplateOccurrenceSkipRequest represents all parameters of scheduled transaction occurrence skipping request
+type TransactionTemplateOccurrenceSkipRequest struct {
+	Id   int64  `json:"id,string" binding:"required,min=1"`
+	Date string `json:"date" binding:"required"`
+}
+
+// TransactionTemplateOccurrencePostponeRequest represents all parameters of scheduled transaction occurrence postponing request
+type TransactionTemplateOccurrencePostponeRequest struct {
+	Id            int64  `json:"id,string" binding:"required,min=1"`
+	Date          string `json:"date" binding:"required"`
+	PostponedDate string `json:"postponedDate" binding:"required"`
+}
+
+// TransactionTemplateOccurrenceRestoreRequest represents all parameters of scheduled transaction occurrence restoring request
+type TransactionTemplateOccurrenceRestoreRequest struct {
+	Id   int64  `json:"id,string" binding:"required,min=1"`
+	Date string `json:"date" binding:"required"`
+}
+
+// ApplyTransactionTemplateOccurrenceOverrides returns the unix times of the transactions which would be created actually, the skipped and postponed occurrences are removed and the postponed occurrences whose new time is between the start and end unix time are added
+func ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes []int64, overrides []*TransactionTemplateOccurrenceOverride, startUnixTime int64, endUnixTime int64) []int64 {
+	if len(overrides) < 1 {
+		return transactionUnixTimes
+	}
+
+	overrideMap := make(map[int64]*TransactionTemplateOccurrenceOverride, len(overrides))
+
+	for i := 0; i < len(overrides); i++ {
+		overrideMap[overrides[i].OccurrenceTime] = overrides[i]
+	}
+
+	result := make([]int64, 0, len(transactionUnixTimes))
+
+	for i := 0; i < len(transactionUnixTimes); i++ {
+		if _, exists := overrideMap[transactionUnixTimes[i]]; exists {
+			continue
+		}
+
+		result = append(result, transactionUnixTimes[i])
+	}
+
+	for i := 0; i < len(overrides); i++ {
+		override := overrides[i]
+
+		if override.OverrideType == TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE && override.PostponedTime >= startUnixTime && override.PostponedTime <= endUnixTime {
+			result = append(result, override.PostponedTime)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+// GetNextScheduledOccurrences returns the view-objects of the next occurrences of the scheduled transaction template since the start unix time, the skipped occurrences are marked and the postponed occurrences are moved to the new date
+func (t *TransactionTemplate) GetNextScheduledOccurrences(startUnixTime int64, count int, overrides []*TransactionTemplateOccurrenceOverride) ([]*TransactionTemplateScheduledOccurrenceResponse, error) {
+	// get more occurrences since the postponed occurrences would be moved to other dates
+	maxCount := count + len(overrides)
+	transactionUnixTimes, err := t.GetNextScheduledTransactionTimes(startUnixTime, maxCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+	overrideMap := make(map[int64]*TransactionTemplateOccurrenceOverride, len(overrides))
+	occurrences := make([]*TransactionTemplateScheduledOccurrenceResponse, 0, len(transactionUnixTimes))
+
+	for i := 0; i < len(overrides); i++ {
+		overrideMap[overrides[i].OccurrenceTime] = overrides[i]
+	}
+
+	for i := 0; i < len(transactionUnixTimes); i++ {
+		override := overrideMap[transactionUnixTimes[i]]
+
+		if override != nil && override.OverrideType == TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE {
+			continue
+		}
+
+		occurrences = append(occurrences, &TransactionTemplateScheduledOccurrenceResponse{
+			Date:    utils.FormatUnixTimeToLongDate(transactionUnixTimes[i], templateTimeZone),
+			Time:    transactionUnixTimes[i],
+			Skipped: override != nil && override.OverrideType == TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_SKIP,
+		})
+	}
+
+	lastUnixTime := int64(0)
+
+	if len(transactionUnixTimes) > 0 {
+		lastUnixTime = transactionUnixTimes[len(transactionUnixTimes)-1]
+	}
+
+	for i := 0; i < len(overrides); i++ {
+		override := overrides[i]
+
+		if override.OverrideType != TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE || override.PostponedTime < startUnixTime {
+			continue
+		}
+
+		// the postponed occurrences later than the last occurrence may be not the next ones
+		if len(transactionUnixTimes) >= maxCount && override.PostponedTime > lastUnixTime {
+			continue
+		}
+
+		originalDate := utils.FormatUnixTimeToLongDate(override.OccurrenceTime, templateTimeZone)
+
+		occurrences = append(occurrences, &TransactionTemplateScheduledOccurrenceResponse{
+			Date:         utils.FormatUnixTimeToLongDate(override.PostponedTime, templateTimeZone),
+			Time:         override.PostponedTime,
+			OriginalDate: &originalDate,
+		})
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Time < occurrences[j].Time
+	})
+
+	if len(occurrences) > count {
+		occurrences = occurrences[:count]
+	}
+
+	return occurrences, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTransactionTemplateOccurrenceOverrides(t *testing.T) {
+	// 2024-01-01, 2024-02-01, 2024-03-01 and 2024-04-01
+	transactionUnixTimes := []int64{1704067200, 1706745600, 1709251200, 1711929600}
+	overrides := []*TransactionTemplateOccurrenceOverride{
+		// postpone 2024-02-01 to 2024-02-05
+		{OccurrenceTime: 1706745600, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE, PostponedTime: 1707091200},
+		// skip 2024-03-01
+		{OccurrenceTime: 1709251200, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_SKIP},
+		// postpone 2023-12-01 to 2024-04-01
+		{OccurrenceTime: 1701388800, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE, PostponedTime: 1711929600},
+	}
+
+	actualValue := ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes, overrides, 1704067200, 1711929600)
+	assert.Equal(t, []int64{1704067200, 1707091200, 1711929600, 1711929600}, actualValue)
+
+	// 2024-01-01 to 2024-02-04
+	actualValue = ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes[:2], overrides, 1704067200, 1707004800)
+	assert.Equal(t, []int64{1704067200}, actualValue)
+
+	actualValue = ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes, nil, 1704067200, 1711929600)
+	assert.Equal(t, transactionUnixTimes, actualValue)
+}
+
+func TestTransactionTemplateGetNextScheduledOccurrences(t *testing.T) {
+	template := &TransactionTemplate{
+		TemplateType:           TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "1",
+	}
+
+	overrides := []*TransactionTemplateOccurrenceOverride{
+		// postpone 2023-12-01 to 2024-01-10
+		{OccurrenceTime: 1701388800, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE, PostponedTime: 1704844800},
+		// postpone 2024-02-01 to 2024-03-20
+		{OccurrenceTime: 1706745600, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_POSTPONE, PostponedTime: 1710892800},
+		// skip 2024-03-01
+		{OccurrenceTime: 1709251200, OverrideType: TRANSACTION_TEMPLATE_OCCURRENCE_OVERRIDE_TYPE_SKIP},
+	}
+
+	// 2024-01-01
+	actualValue, err := template.GetNextScheduledOccurrences(1704067200, 4, overrides)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(actualValue))
+
+	assert.Equal(t, "2024-01-01", actualValue[0].Date)
+	assert.Equal(t, int64(1704067200), actualValue[0].Time)
+	assert.False(t, actualValue[0].Skipped)
+	assert.Nil(t, actualValue[0].OriginalDate)
+
+	assert.Equal(t, "2024-01-10", actualValue[1].Date)
+	assert.Equal(t, "2023-12-01", *actualValue[1].OriginalDate)
+
+	assert.Equal(t, "2024-03-01", actualValue[2].Date)
+	assert.True(t, actualValue[2].Skipped)
+
+	assert.Equal(t, "2024-03-20", actualValue[3].Date)
+	assert.Equal(t, "2024-02-01", *actualValue[3].OriginalDate)
+
+	actualValue, err = template.GetNextScheduledOccurrences(1704067200, 2, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actualValue))
+	assert.Equal(t, "2024-01-01", actualValue[0].Date)
+	assert.Equal(t, "2024-02-01", actualValue[1].Date)
+}
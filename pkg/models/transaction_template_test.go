@@ -3,8 +3,11 @@ package models
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 func TestTransactionTemplateGetTagIds(t *testing.T) {
@@ -43,3 +46,106 @@ func TestTransactionTemplateInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(3), transactionTemplateRespSlice[1].Id)
 	assert.Equal(t, int64(1), transactionTemplateRespSlice[2].Id)
 }
+
+func TestTransactionTemplateGetScheduledTransactionTimes_Weekly(t *testing.T) {
+	template := &TransactionTemplate{
+		TemplateType:           TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+		ScheduledFrequency:     "1,5",
+	}
+
+	// 2024-01-01 00:00:00 UTC (Monday) to 2024-01-14 23:59:59 UTC
+	actualValue, err := template.GetScheduledTransactionTimes(1704067200, 1705276799)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1704067200, 1704412800, 1704672000, 1705017600}, actualValue)
+}
+
+func TestTransactionTemplateGetScheduledTransactionTimes_MonthlyWithLastDay(t *testing.T) {
+	template := &TransactionTemplate{
+		TemplateType:               TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:         "-1,15",
+		ScheduledTimezoneUtcOffset: 480,
+	}
+
+	// 2024-02-01 00:00:00 UTC+8 to 2024-03-31 23:59:59 UTC+8
+	actualValue, err := template.GetScheduledTransactionTimes(1706716800, 1711900799)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(actualValue))
+
+	timezone := time.FixedZone("Template Timezone", 480*60)
+	assert.Equal(t, "2024-02-15", utils.FormatUnixTimeToLongDate(actualValue[0], timezone))
+	assert.Equal(t, "2024-02-29", utils.FormatUnixTimeToLongDate(actualValue[1], timezone))
+	assert.Equal(t, "2024-03-15", utils.FormatUnixTimeToLongDate(actualValue[2], timezone))
+	assert.Equal(t, "2024-03-31", utils.FormatUnixTimeToLongDate(actualValue[3], timezone))
+}
+
+func TestTransactionTemplateGetScheduledTransactionTimes_DailyWithStartAndEndTime(t *testing.T) {
+	startTime := int64(1704240000) // 2024-01-03 00:00:00 UTC
+	endTime := int64(1704412800)   // 2024-01-05 00:00:00 UTC
+	template := &TransactionTemplate{
+		TemplateType:           TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY,
+		ScheduledFrequency:     "0",
+		ScheduledStartTime:     &startTime,
+		ScheduledEndTime:       &endTime,
+	}
+
+	actualValue, err := template.GetScheduledTransactionTimes(1704067200, 1705276799)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1704240000, 1704326400, 1704412800}, actualValue)
+}
+
+func TestTransactionTemplateGetScheduledTransactionTimes_Yearly(t *testing.T) {
+	template := &TransactionTemplate{
+		TemplateType:           TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY,
+		ScheduledFrequency:     "102",
+	}
+
+	// 2024-01-01 00:00:00 UTC to 2025-12-31 23:59:59 UTC
+	actualValue, err := template.GetScheduledTransactionTimes(1704067200, 1767225599)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1704153600, 1735776000}, actualValue)
+}
+
+func TestTransactionTemplateGetScheduledTransactionTimes_InvalidFrequency(t *testing.T) {
+	template := &TransactionTemplate{
+		TemplateType:           TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED,
+	}
+
+	_, err := template.GetScheduledTransactionTimes(1704067200, 1705276799)
+	assert.NotNil(t, err)
+
+	template.ScheduledFrequencyType = TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY
+	template.ScheduledFrequency = "a"
+
+	_, err = template.GetScheduledTransactionTimes(1704067200, 1705276799)
+	assert.NotNil(t, err)
+}
+
+func TestTransactionTemplateGetScheduledTransactionTimes_RecurrenceRule(t *testing.T) {
+	startTime := int64(1704067200) // 2024-01-01 00:00:00 UTC
+	template := &TransactionTemplate{
+		TemplateType:               TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+		ScheduledFrequency:         "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO\nEXDATE:20240129",
+		ScheduledStartTime:         &startTime,
+		ScheduledTimezoneUtcOffset: 0,
+	}
+
+	// 2024-01-10 00:00:00 UTC to 2024-03-01 00:00:00 UTC
+	actualValue, err := template.GetScheduledTransactionTimes(1704844800, 1709251200)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1705276800, 1707696000, 1708905600}, actualValue)
+
+	// next 2 transactions since 2024-01-15 00:00:00 UTC
+	actualValue, err = template.GetNextScheduledTransactionTimes(1705276800, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1705276800, 1705276800 + 28*86400}, actualValue)
+
+	template.ScheduledFrequency = "RRULE:FREQ=SECONDLY"
+	_, err = template.GetScheduledTransactionTimes(1704844800, 1709251200)
+	assert.NotNil(t, err)
+}
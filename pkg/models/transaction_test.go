@@ -3,6 +3,7 @@ package models
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -332,3 +333,88 @@ func TestTransactionAmountsResponseItemAmountInfoSliceLess(t *testing.T) {
 	assert.Equal(t, "EUR", amountInfoSlice[1].Currency)
 	assert.Equal(t, "USD", amountInfoSlice[2].Currency)
 }
+
+func TestTransactionToDeletedTransactionInfoResponse(t *testing.T) {
+	transaction := &Transaction{
+		TransactionId:   1001,
+		Type:            TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      2001,
+		AccountId:       3001,
+		Amount:          1234,
+		TransactionTime: 1700000000000,
+		Deleted:         true,
+		DeletedUnixTime: 1710000000,
+	}
+
+	transactionResp := transaction.ToDeletedTransactionInfoResponse([]int64{4001, 4002}, true)
+
+	assert.Equal(t, int64(1001), transactionResp.Id)
+	assert.Equal(t, TRANSACTION_TYPE_EXPENSE, transactionResp.Type)
+	assert.Equal(t, int64(3001), transactionResp.SourceAccountId)
+	assert.Equal(t, int64(1234), transactionResp.SourceAmount)
+	assert.Equal(t, []string{"4001", "4002"}, transactionResp.TagIds)
+	assert.Equal(t, int64(1710000000), transactionResp.DeletedTime)
+}
+
+func TestGetTotalIncomeAndExpenseAmounts(t *testing.T) {
+	totalIncomeAmount, totalExpenseAmount := GetTotalIncomeAndExpenseAmounts([]*Transaction{
+		{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 1000},
+		{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 250},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 300},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Amount: 400},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, Amount: 400},
+	})
+
+	assert.Equal(t, int64(1250), totalIncomeAmount)
+	assert.Equal(t, int64(300), totalExpenseAmount)
+}
+
+func TestTransactionGetAccountBalanceChangedAmount(t *testing.T) {
+	transactions := []*Transaction{
+		{Type: TRANSACTION_DB_TYPE_MODIFY_BALANCE, Amount: 1000, RelatedAccountAmount: 1000},
+		{Type: TRANSACTION_DB_TYPE_INCOME, Amount: 250},
+		{Type: TRANSACTION_DB_TYPE_EXPENSE, Amount: 300},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, Amount: 400, RelatedAccountAmount: 50},
+		{Type: TRANSACTION_DB_TYPE_TRANSFER_IN, Amount: 50, RelatedAccountAmount: 400},
+	}
+	expectedValues := []int64{1000, 250, -300, -400, 50}
+
+	for i := 0; i < len(transactions); i++ {
+		actualValue, err := transactions[i].GetAccountBalanceChangedAmount()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedValues[i], actualValue)
+	}
+
+	_, err := (&Transaction{Type: 6}).GetAccountBalanceChangedAmount()
+	assert.Equal(t, errs.ErrTransactionTypeInvalid, err)
+}
+
+func TestTransactionIsEditable_ReconciledTransaction(t *testing.T) {
+	user := &User{TransactionEditScope: TRANSACTION_EDIT_SCOPE_ALL}
+	account := &Account{AccountId: 1}
+	transaction := &Transaction{
+		Type:                 TRANSACTION_DB_TYPE_EXPENSE,
+		AccountId:            1,
+		TransactionTime:      1700000000000,
+		ReconciliationStatus: TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+
+	assert.True(t, transaction.IsEditable(user, time.UTC, account, nil))
+
+	transaction.ReconciliationStatus = TRANSACTION_RECONCILIATION_STATUS_RECONCILED
+	assert.False(t, transaction.IsEditable(user, time.UTC, account, nil))
+}
+
+func TestTransactionToTransactionInfoResponse_ReconciliationStatus(t *testing.T) {
+	transaction := &Transaction{
+		TransactionId:        1001,
+		Type:                 TRANSACTION_DB_TYPE_INCOME,
+		AccountId:            3001,
+		Amount:               1234,
+		TransactionTime:      1700000000000,
+		ReconciliationStatus: TRANSACTION_RECONCILIATION_STATUS_CLEARED,
+	}
+
+	transactionResp := transaction.ToTransactionInfoResponse(nil, true)
+	assert.Equal(t, TRANSACTION_RECONCILIATION_STATUS_CLEARED, transactionResp.ReconciliationStatus)
+}
@@ -91,6 +91,7 @@ type User struct {
 	CustomAvatarType      string `xorm:"VARCHAR(10)"`
 	DefaultAccountId      int64
 	TransactionEditScope  TransactionEditScope       `xorm:"TINYINT NOT NULL"`
+	TransactionLockTime   int64                      `xorm:"NOT NULL DEFAULT 0"`
 	Language              string                     `xorm:"VARCHAR(10)"`
 	DefaultCurrency       string                     `xorm:"VARCHAR(3) NOT NULL"`
 	FirstDayOfWeek        core.WeekDay               `xorm:"TINYINT NOT NULL"`
@@ -129,6 +130,7 @@ type UserBasicInfo struct {
 	AvatarProvider        string                     `json:"avatarProvider,omitempty"`
 	DefaultAccountId      int64                      `json:"defaultAccountId,string"`
 	TransactionEditScope  TransactionEditScope       `json:"transactionEditScope"`
+	TransactionLockTime   int64                      `json:"transactionLockTime"`
 	Language              string                     `json:"language"`
 	DefaultCurrency       string                     `json:"defaultCurrency"`
 	FirstDayOfWeek        core.WeekDay               `json:"firstDayOfWeek"`
@@ -216,6 +218,15 @@ type UserProfileUpdateRequest struct {
 	IncomeAmountColor     *AmountColorType            `json:"incomeAmountColor" binding:"omitempty,min=0,max=4"`
 }
 
+// UserTransactionLockTimeUpdateRequest represents all parameters of transaction lock date updating request
+type UserTransactionLockTimeUpdateRequest struct {
+	LedgerId   int64  `json:"ledgerId,string" binding:"min=0"`
+	Overridden bool   `json:"overridden"`
+	LockTime   int64  `json:"lockTime" binding:"min=0"`
+	Password   string `json:"password" binding:"omitempty,min=6,max=128"`
+	Passcode   string `json:"passcode" binding:"omitempty,notBlank,len=6"`
+}
+
 // UserProfileUpdateResponse represents the data returns to frontend after updating profile
 type UserProfileUpdateResponse struct {
 	User     *UserBasicInfo `json:"user"`
@@ -286,6 +297,7 @@ func (u *User) ToUserBasicInfo(avatarProvider core.UserAvatarProviderType, avata
 		AvatarProvider:        string(avatarProvider),
 		DefaultAccountId:      u.DefaultAccountId,
 		TransactionEditScope:  u.TransactionEditScope,
+		TransactionLockTime:   u.TransactionLockTime,
 		Language:              u.Language,
 		DefaultCurrency:       u.DefaultCurrency,
 		FirstDayOfWeek:        u.FirstDayOfWeek,
@@ -309,6 +321,29 @@ func (u *User) ToUserBasicInfo(avatarProvider core.UserAvatarProviderType, avata
 	}
 }
 
+// GetTransactionLockTime returns the unix time before which the transactions in the specified ledger cannot be changed, the ledger can override the lock time of user
+func (u *User) GetTransactionLockTime(ledger *Ledger) int64 {
+	if ledger != nil && ledger.TransactionLockOverridden {
+		return ledger.TransactionLockTime
+	}
+
+	return u.TransactionLockTime
+}
+
+// IsTransactionTimeLocked returns whether the transaction with specified transaction time is locked by the lock unix time
+func IsTransactionTimeLocked(transactionTime int64, lockUnixTime int64) bool {
+	return lockUnixTime > 0 && utils.GetUnixTimeFromTransactionTime(transactionTime) < lockUnixTime
+}
+
+// GetMinUnlockedTransactionTime returns the minimum transaction time which is not locked by the lock unix time
+func GetMinUnlockedTransactionTime(lockUnixTime int64) int64 {
+	if lockUnixTime <= 0 {
+		return 0
+	}
+
+	return utils.GetMinTransactionTimeFromUnixTime(lockUnixTime)
+}
+
 // ToUserProfileResponse returns a user profile view-object according to database model
 func (u *User) ToUserProfileResponse(basicInfo *UserBasicInfo) *UserProfileResponse {
 	return &UserProfileResponse{
@@ -0,0 +1,124 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/core"
+
+// UserDataBackupVersion represents the current version of user data backup format
+const UserDataBackupVersion = 1
+
+// UserDataBackup represents all the data of a user stored in backup archive
+type UserDataBackup struct {
+	Version                     int                                      `json:"version"`
+	CreatedUnixTime             int64                                    `json:"createdUnixTime"`
+	Uid                         int64                                    `json:"uid,string"`
+	Settings                    *UserDataBackupSettings                  `json:"settings"`
+	Ledgers                     []*Ledger                                `json:"ledgers"`
+	Accounts                    []*Account                               `json:"accounts"`
+	Categories                  []*TransactionCategory                   `json:"categories"`
+	TagGroups                   []*TransactionTagGroup                   `json:"tagGroups"`
+	Tags                        []*TransactionTag                        `json:"tags"`
+	Payees                      []*Payee                                 `json:"payees"`
+	Transactions                []*Transaction                           `json:"transactions"`
+	TagIndexes                  []*TransactionTagIndex                   `json:"tagIndexes"`
+	Splits                      []*TransactionSplit                      `json:"splits"`
+	PictureInfos                []*TransactionPictureInfo                `json:"pictureInfos"`
+	Histories                   []*TransactionHistory                    `json:"histories"`
+	Templates                   []*TransactionTemplate                   `json:"templates"`
+	TemplateOccurrenceOverrides []*TransactionTemplateOccurrenceOverride `json:"templateOccurrenceOverrides"`
+	AmortizationSchedules       []*AmortizationSchedule                  `json:"amortizationSchedules"`
+	InvestmentTransactions      []*InvestmentTransaction                 `json:"investmentTransactions"`
+	SecurityPrices              []*SecurityPrice                         `json:"securityPrices"`
+	Budgets                     []*Budget                                `json:"budgets"`
+	Rules                       []*TransactionRule                       `json:"rules"`
+	InsightsExplorers           []*InsightsExplorer                      `json:"insightsExplorers"`
+	CustomExchangeRates         []*UserCustomExchangeRate                `json:"customExchangeRates"`
+	ExchangeRateSnapshots       []*ExchangeRateSnapshot                  `json:"exchangeRateSnapshots"`
+	NetWorthSnapshots           []*NetWorthSnapshot                      `json:"netWorthSnapshots"`
+	NetWorthSnapshotStates      []*NetWorthSnapshotState                 `json:"netWorthSnapshotStates"`
+	ApplicationCloudSettings    *UserApplicationCloudSetting             `json:"applicationCloudSettings"`
+}
+
+// UserDataBackupSettings represents the user preferences stored in backup archive
+type UserDataBackupSettings struct {
+	Nickname              string                     `json:"nickname"`
+	CustomAvatarType      string                     `json:"customAvatarType"`
+	DefaultAccountId      int64                      `json:"defaultAccountId,string"`
+	TransactionEditScope  TransactionEditScope       `json:"transactionEditScope"`
+	TransactionLockTime   int64                      `json:"transactionLockTime"`
+	Language              string                     `json:"language"`
+	DefaultCurrency       string                     `json:"defaultCurrency"`
+	FirstDayOfWeek        core.WeekDay               `json:"firstDayOfWeek"`
+	FiscalYearStart       core.FiscalYearStart       `json:"fiscalYearStart"`
+	CalendarDisplayType   core.CalendarDisplayType   `json:"calendarDisplayType"`
+	DateDisplayType       core.DateDisplayType       `json:"dateDisplayType"`
+	LongDateFormat        core.LongDateFormat        `json:"longDateFormat"`
+	ShortDateFormat       core.ShortDateFormat       `json:"shortDateFormat"`
+	LongTimeFormat        core.LongTimeFormat        `json:"longTimeFormat"`
+	ShortTimeFormat       core.ShortTimeFormat       `json:"shortTimeFormat"`
+	FiscalYearFormat      core.FiscalYearFormat      `json:"fiscalYearFormat"`
+	CurrencyDisplayType   core.CurrencyDisplayType   `json:"currencyDisplayType"`
+	NumeralSystem         core.NumeralSystem         `json:"numeralSystem"`
+	DecimalSeparator      core.DecimalSeparator      `json:"decimalSeparator"`
+	DigitGroupingSymbol   core.DigitGroupingSymbol   `json:"digitGroupingSymbol"`
+	DigitGrouping         core.DigitGroupingType     `json:"digitGrouping"`
+	CoordinateDisplayType core.CoordinateDisplayType `json:"coordinateDisplayType"`
+	ExpenseAmountColor    AmountColorType            `json:"expenseAmountColor"`
+	IncomeAmountColor     AmountColorType            `json:"incomeAmountColor"`
+}
+
+// ToUserDataBackupSettings returns the user preferences stored in backup archive according to database model
+func (u *User) ToUserDataBackupSettings() *UserDataBackupSettings {
+	return &UserDataBackupSettings{
+		Nickname:              u.Nickname,
+		CustomAvatarType:      u.CustomAvatarType,
+		DefaultAccountId:      u.DefaultAccountId,
+		TransactionEditScope:  u.TransactionEditScope,
+		TransactionLockTime:   u.TransactionLockTime,
+		Language:              u.Language,
+		DefaultCurrency:       u.DefaultCurrency,
+		FirstDayOfWeek:        u.FirstDayOfWeek,
+		FiscalYearStart:       u.FiscalYearStart,
+		CalendarDisplayType:   u.CalendarDisplayType,
+		DateDisplayType:       u.DateDisplayType,
+		LongDateFormat:        u.LongDateFormat,
+		ShortDateFormat:       u.ShortDateFormat,
+		LongTimeFormat:        u.LongTimeFormat,
+		ShortTimeFormat:       u.ShortTimeFormat,
+		FiscalYearFormat:      u.FiscalYearFormat,
+		CurrencyDisplayType:   u.CurrencyDisplayType,
+		NumeralSystem:         u.NumeralSystem,
+		DecimalSeparator:      u.DecimalSeparator,
+		DigitGroupingSymbol:   u.DigitGroupingSymbol,
+		DigitGrouping:         u.DigitGrouping,
+		CoordinateDisplayType: u.CoordinateDisplayType,
+		ExpenseAmountColor:    u.ExpenseAmountColor,
+		IncomeAmountColor:     u.IncomeAmountColor,
+	}
+}
+
+// ApplyToUser fills the user preferences stored in backup archive to the specified user model
+func (s *UserDataBackupSettings) ApplyToUser(user *User) {
+	user.Nickname = s.Nickname
+	user.CustomAvatarType = s.CustomAvatarType
+	user.DefaultAccountId = s.DefaultAccountId
+	user.TransactionEditScope = s.TransactionEditScope
+	user.TransactionLockTime = s.TransactionLockTime
+	user.Language = s.Language
+	user.DefaultCurrency = s.DefaultCurrency
+	user.FirstDayOfWeek = s.FirstDayOfWeek
+	user.FiscalYearStart = s.FiscalYearStart
+	user.CalendarDisplayType = s.CalendarDisplayType
+	user.DateDisplayType = s.DateDisplayType
+	user.LongDateFormat = s.LongDateFormat
+	user.ShortDateFormat = s.ShortDateFormat
+	user.LongTimeFormat = s.LongTimeFormat
+	user.ShortTimeFormat = s.ShortTimeFormat
+	user.FiscalYearFormat = s.FiscalYearFormat
+	user.CurrencyDisplayType = s.CurrencyDisplayType
+	user.NumeralSystem = s.NumeralSystem
+	user.DecimalSeparator = s.DecimalSeparator
+	user.DigitGroupingSymbol = s.DigitGroupingSymbol
+	user.DigitGrouping = s.DigitGrouping
+	user.CoordinateDisplayType = s.CoordinateDisplayType
+	user.ExpenseAmountColor = s.ExpenseAmountColor
+	user.IncomeAmountColor = s.IncomeAmountColor
+}
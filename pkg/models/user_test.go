@@ -112,3 +112,27 @@ func TestUserCanEditTransactionByTransactionTime_ScopeIsThisYearOrLater(t *testi
 	assert.Equal(t, true, user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(thisYearLastDatetime.Unix()), timezone))
 	assert.Equal(t, false, user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(lastYearLastDatetime.Unix()), timezone))
 }
+
+func TestUserGetTransactionLockTime(t *testing.T) {
+	user := &User{TransactionLockTime: 1704067200}
+
+	assert.Equal(t, int64(1704067200), user.GetTransactionLockTime(nil))
+	assert.Equal(t, int64(1704067200), user.GetTransactionLockTime(&Ledger{TransactionLockTime: 1706745600}))
+	assert.Equal(t, int64(1706745600), user.GetTransactionLockTime(&Ledger{TransactionLockOverridden: true, TransactionLockTime: 1706745600}))
+	assert.Equal(t, int64(0), user.GetTransactionLockTime(&Ledger{TransactionLockOverridden: true}))
+}
+
+func TestIsTransactionTimeLocked(t *testing.T) {
+	assert.False(t, IsTransactionTimeLocked(utils.GetMinTransactionTimeFromUnixTime(1704067199), 0))
+	assert.True(t, IsTransactionTimeLocked(utils.GetMinTransactionTimeFromUnixTime(1704067199), 1704067200))
+	assert.True(t, IsTransactionTimeLocked(utils.GetMaxTransactionTimeFromUnixTime(1704067199), 1704067200))
+	assert.False(t, IsTransactionTimeLocked(utils.GetMinTransactionTimeFromUnixTime(1704067200), 1704067200))
+}
+
+func TestGetMinUnlockedTransactionTime(t *testing.T) {
+	assert.Equal(t, int64(0), GetMinUnlockedTransactionTime(0))
+
+	minUnlockedTransactionTime := GetMinUnlockedTransactionTime(1704067200)
+	assert.False(t, IsTransactionTimeLocked(minUnlockedTransactionTime, 1704067200))
+	assert.True(t, IsTransactionTimeLocked(minUnlockedTransactionTime-1, 1704067200))
+}
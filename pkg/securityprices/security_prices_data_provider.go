@@ -0,0 +1,16 @@
+package securityprices
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// SecurityPricesDataProvider defines the structure of security prices data provider
+type SecurityPricesDataProvider interface {
+	// GetLatestSecurityPrices returns the latest price of each security
+	GetLatestSecurityPrices(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error)
+
+	// GetHistoricalSecurityPrices returns all historical prices of the specified securities in ascending order of symbol and price date
+	GetHistoricalSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) ([]*models.SecurityPrice, error)
+}
@@ -0,0 +1,46 @@
+package securityprices
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// SecurityPricesDataProviderContainer contains the current security prices data provider
+type SecurityPricesDataProviderContainer struct {
+	current SecurityPricesDataProvider
+}
+
+// Initialize a security prices data provider container singleton instance
+var (
+	Container = &SecurityPricesDataProviderContainer{}
+)
+
+// InitializeSecurityPricesDataSource initializes the current security prices data source according to the config
+func InitializeSecurityPricesDataSource(config *settings.Config) error {
+	if config.SecurityPricesDataSource == settings.UserCustomSecurityPricesDataSource {
+		Container.current = newUserCustomSecurityPricesDataProvider()
+		return nil
+	}
+
+	return errs.ErrInvalidSecurityPricesDataSource
+}
+
+// GetLatestSecurityPrices returns the latest security prices data from the current security prices data source
+func (e *SecurityPricesDataProviderContainer) GetLatestSecurityPrices(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error) {
+	if Container.current == nil {
+		return nil, errs.ErrInvalidSecurityPricesDataSource
+	}
+
+	return e.current.GetLatestSecurityPrices(c, uid, currentConfig)
+}
+
+// GetHistoricalSecurityPrices returns the historical security prices data from the current security prices data source
+func (e *SecurityPricesDataProviderContainer) GetHistoricalSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) ([]*models.SecurityPrice, error) {
+	if Container.current == nil {
+		return nil, errs.ErrInvalidSecurityPricesDataSource
+	}
+
+	return e.current.GetHistoricalSecurityPrices(c, uid, symbols, currentConfig)
+}
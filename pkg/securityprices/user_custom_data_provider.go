@@ -0,0 +1,85 @@
+package securityprices
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const userDataSourceType = "user_custom"
+
+// UserCustomSecurityPricesDataProvider defines the structure of user custom security prices data provider
+type UserCustomSecurityPricesDataProvider struct {
+	SecurityPricesDataProvider
+	securityPrices *services.SecurityPriceService
+}
+
+// GetLatestSecurityPrices returns the latest security prices which are maintained by user
+func (e *UserCustomSecurityPricesDataProvider) GetLatestSecurityPrices(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error) {
+	securityPrices, err := e.securityPrices.GetLatestPricesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_data_provider.GetLatestSecurityPrices] failed to get user custom security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	latestPrices := make([]*models.LatestSecurityPrice, len(securityPrices))
+	latestUpdateTime := int64(0)
+
+	for i := 0; i < len(securityPrices); i++ {
+		if securityPrices[i].UpdatedUnixTime > latestUpdateTime {
+			latestUpdateTime = securityPrices[i].UpdatedUnixTime
+		}
+
+		latestPrices[i] = securityPrices[i].ToLatestSecurityPrice()
+	}
+
+	if latestUpdateTime < 1 {
+		latestUpdateTime = time.Now().Unix()
+	}
+
+	finalSecurityPriceResponse := &models.LatestSecurityPriceResponse{
+		DataSource:     userDataSourceType,
+		UpdateTime:     latestUpdateTime,
+		SecurityPrices: latestPrices,
+	}
+
+	return finalSecurityPriceResponse, nil
+}
+
+// GetHistoricalSecurityPrices returns all historical security prices of the specified securities which are maintained by user
+func (e *UserCustomSecurityPricesDataProvider) GetHistoricalSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) ([]*models.SecurityPrice, error) {
+	securityPrices, err := e.securityPrices.GetAllPricesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_data_provider.GetHistoricalSecurityPrices] failed to get user custom security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	symbolSet := make(map[string]bool, len(symbols))
+
+	for i := 0; i < len(symbols); i++ {
+		symbolSet[symbols[i]] = true
+	}
+
+	historicalPrices := make([]*models.SecurityPrice, 0, len(securityPrices))
+
+	for i := 0; i < len(securityPrices); i++ {
+		if symbolSet[securityPrices[i].Symbol] {
+			historicalPrices = append(historicalPrices, securityPrices[i])
+		}
+	}
+
+	return historicalPrices, nil
+}
+
+func newUserCustomSecurityPricesDataProvider() *UserCustomSecurityPricesDataProvider {
+	return &UserCustomSecurityPricesDataProvider{
+		securityPrices: services.SecurityPrices,
+	}
+}
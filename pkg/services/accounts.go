@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"xorm.io/builder"
 	"xorm.io/xorm"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -35,30 +36,52 @@ var (
 )
 
 // GetTotalAccountCountByUid returns total account count of user
-func (s *AccountService) GetTotalAccountCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *AccountService) GetTotalAccountCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.Account{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.Account{})
 
 	return count, err
 }
 
 // GetAllAccountsByUid returns all account models of user
-func (s *AccountService) GetAllAccountsByUid(c core.Context, uid int64) ([]*models.Account, error) {
+func (s *AccountService) GetAllAccountsByUid(c core.Context, uid int64, ledgerId int64) ([]*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var accounts []*models.Account
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
 
 	return accounts, err
 }
 
+// GetAllAccountCurrenciesByUid returns all distinct currencies of the accounts in all ledgers of user
+func (s *AccountService) GetAllAccountCurrenciesByUid(c core.Context, uid int64) ([]string, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var accounts []*models.Account
+	err := s.UserDataDB(uid).NewSession(c).Distinct("currency").Where("uid=? AND deleted=?", uid, false).Find(&accounts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]string, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		currencies[i] = accounts[i].Currency
+	}
+
+	return currencies, nil
+}
+
 // GetAccountByAccountId returns account model according to account id
-func (s *AccountService) GetAccountByAccountId(c core.Context, uid int64, accountId int64) (*models.Account, error) {
+func (s *AccountService) GetAccountByAccountId(c core.Context, uid int64, ledgerId int64, accountId int64) (*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -68,7 +91,7 @@ func (s *AccountService) GetAccountByAccountId(c core.Context, uid int64, accoun
 	}
 
 	account := &models.Account{}
-	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Get(account)
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND account_id=?", uid, ledgerId, false, accountId).Get(account)
 
 	if err != nil {
 		return nil, err
@@ -80,7 +103,7 @@ func (s *AccountService) GetAccountByAccountId(c core.Context, uid int64, accoun
 }
 
 // GetAccountAndSubAccountsByAccountId returns account model and sub-account models according to account id
-func (s *AccountService) GetAccountAndSubAccountsByAccountId(c core.Context, uid int64, accountId int64) ([]*models.Account, error) {
+func (s *AccountService) GetAccountAndSubAccountsByAccountId(c core.Context, uid int64, ledgerId int64, accountId int64) ([]*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -90,13 +113,13 @@ func (s *AccountService) GetAccountAndSubAccountsByAccountId(c core.Context, uid
 	}
 
 	var accounts []*models.Account
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND (account_id=? OR parent_account_id=?)", uid, false, accountId, accountId).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND (account_id=? OR parent_account_id=?)", uid, ledgerId, false, accountId, accountId).OrderBy("parent_account_id asc, display_order asc").Find(&accounts)
 
 	return accounts, err
 }
 
 // GetSubAccountsByAccountId returns sub-account models according to account id
-func (s *AccountService) GetSubAccountsByAccountId(c core.Context, uid int64, accountId int64) ([]*models.Account, error) {
+func (s *AccountService) GetSubAccountsByAccountId(c core.Context, uid int64, ledgerId int64, accountId int64) ([]*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -106,13 +129,13 @@ func (s *AccountService) GetSubAccountsByAccountId(c core.Context, uid int64, ac
 	}
 
 	var accounts []*models.Account
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND parent_account_id=?", uid, false, accountId).OrderBy("display_order asc").Find(&accounts)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=?", uid, ledgerId, false, accountId).OrderBy("display_order asc").Find(&accounts)
 
 	return accounts, err
 }
 
 // GetSubAccountsByAccountIds returns sub-account models according to account ids
-func (s *AccountService) GetSubAccountsByAccountIds(c core.Context, uid int64, accountIds []int64) ([]*models.Account, error) {
+func (s *AccountService) GetSubAccountsByAccountIds(c core.Context, uid int64, ledgerId int64, accountIds []int64) ([]*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -121,9 +144,10 @@ func (s *AccountService) GetSubAccountsByAccountIds(c core.Context, uid int64, a
 		return nil, errs.ErrAccountIdInvalid
 	}
 
-	condition := "uid=? AND deleted=?"
-	conditionParams := make([]any, 0, len(accountIds)+2)
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := make([]any, 0, len(accountIds)+3)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 
 	var accountIdConditions strings.Builder
@@ -154,7 +178,7 @@ func (s *AccountService) GetSubAccountsByAccountIds(c core.Context, uid int64, a
 }
 
 // GetAccountsByAccountIds returns account models according to account ids
-func (s *AccountService) GetAccountsByAccountIds(c core.Context, uid int64, accountIds []int64) (map[int64]*models.Account, error) {
+func (s *AccountService) GetAccountsByAccountIds(c core.Context, uid int64, ledgerId int64, accountIds []int64) (map[int64]*models.Account, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -164,7 +188,7 @@ func (s *AccountService) GetAccountsByAccountIds(c core.Context, uid int64, acco
 	}
 
 	var accounts []*models.Account
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("account_id", accountIds).Find(&accounts)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("account_id", accountIds).Find(&accounts)
 
 	if err != nil {
 		return nil, err
@@ -175,13 +199,13 @@ func (s *AccountService) GetAccountsByAccountIds(c core.Context, uid int64, acco
 }
 
 // GetMaxDisplayOrder returns the max display order according to account category
-func (s *AccountService) GetMaxDisplayOrder(c core.Context, uid int64, category models.AccountCategory) (int32, error) {
+func (s *AccountService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64, category models.AccountCategory) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	account := &models.Account{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "parent_account_id", "display_order").Where("uid=? AND deleted=? AND parent_account_id=? AND category=?", uid, false, models.LevelOneAccountParentId, category).OrderBy("display_order desc").Limit(1).Get(account)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "parent_account_id", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=? AND category=?", uid, ledgerId, false, models.LevelOneAccountParentId, category).OrderBy("display_order desc").Limit(1).Get(account)
 
 	if err != nil {
 		return 0, err
@@ -195,7 +219,7 @@ func (s *AccountService) GetMaxDisplayOrder(c core.Context, uid int64, category
 }
 
 // GetMaxSubAccountDisplayOrder returns the max display order of sub-account according to account category and parent account id
-func (s *AccountService) GetMaxSubAccountDisplayOrder(c core.Context, uid int64, category models.AccountCategory, parentAccountId int64) (int32, error) {
+func (s *AccountService) GetMaxSubAccountDisplayOrder(c core.Context, uid int64, ledgerId int64, category models.AccountCategory, parentAccountId int64) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -205,7 +229,7 @@ func (s *AccountService) GetMaxSubAccountDisplayOrder(c core.Context, uid int64,
 	}
 
 	account := &models.Account{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "parent_account_id", "display_order").Where("uid=? AND deleted=? AND parent_account_id=? AND category=?", uid, false, parentAccountId, category).OrderBy("display_order desc").Limit(1).Get(account)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "parent_account_id", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=? AND category=?", uid, ledgerId, false, parentAccountId, category).OrderBy("display_order desc").Limit(1).Get(account)
 
 	if err != nil {
 		return 0, err
@@ -224,6 +248,12 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, mainAccount.Uid, mainAccount.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	needAccountUuidCount := uint16(len(childrenAccounts) + 1)
 	accountUuids := s.GenerateUuids(uuid.UUID_TYPE_ACCOUNT, needAccountUuidCount)
 
@@ -245,6 +275,7 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 			childAccount.AccountId = accountUuids[i+1]
 			childAccount.ParentAccountId = mainAccount.AccountId
 			childAccount.Uid = mainAccount.Uid
+			childAccount.LedgerId = mainAccount.LedgerId
 			childAccount.Type = models.ACCOUNT_TYPE_SINGLE_ACCOUNT
 
 			allAccounts[i+1] = childrenAccounts[i]
@@ -281,6 +312,7 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 			newTransaction := &models.Transaction{
 				TransactionId:        transactionId,
 				Uid:                  allAccounts[i].Uid,
+				LedgerId:             allAccounts[i].LedgerId,
 				Deleted:              false,
 				Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
 				TransactionTime:      transactionTime,
@@ -364,6 +396,13 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 			}
 		}
 
+		err := NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, mainAccount.Uid, allInitTransactions, now)
+
+		if err != nil {
+			log.Errorf(c, "[accounts.CreateAccounts] failed to invalidate net worth snapshots, because %s", err.Error())
+			return err
+		}
+
 		return nil
 	})
 }
@@ -374,6 +413,12 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, mainAccount.Uid, mainAccount.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	needAccountUuidCount := uint16(len(addSubAccounts))
 	newAccountUuids := s.GenerateUuids(uuid.UUID_TYPE_ACCOUNT, needAccountUuidCount)
 
@@ -397,6 +442,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 			childAccount.AccountId = newAccountUuids[i]
 			childAccount.ParentAccountId = mainAccount.AccountId
 			childAccount.Uid = mainAccount.Uid
+			childAccount.LedgerId = mainAccount.LedgerId
 			childAccount.Type = models.ACCOUNT_TYPE_SINGLE_ACCOUNT
 			childAccount.Deleted = false
 			childAccount.CreatedUnixTime = now
@@ -422,6 +468,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 				newTransaction := &models.Transaction{
 					TransactionId:        transactionId,
 					Uid:                  childAccount.Uid,
+					LedgerId:             childAccount.LedgerId,
 					Deleted:              false,
 					Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
 					TransactionTime:      transactionTime,
@@ -445,7 +492,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 		// update accounts
 		for i := 0; i < len(updateAccounts); i++ {
 			account := updateAccounts[i]
-			updatedRows, err := sess.ID(account.AccountId).Cols("name", "display_order", "category", "icon", "color", "comment", "extend", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", account.Uid, false).Update(account)
+			updatedRows, err := sess.ID(account.AccountId).Cols("name", "display_order", "category", "icon", "color", "comment", "extend", "hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", account.Uid, account.LedgerId, false).Update(account)
 
 			if err != nil {
 				return err
@@ -520,9 +567,18 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 			}
 		}
 
+		if len(addInitTransactions) > 0 {
+			err := NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, mainAccount.Uid, addInitTransactions, now)
+
+			if err != nil {
+				log.Errorf(c, "[accounts.ModifyAccounts] failed to invalidate net worth snapshots, because %s", err.Error())
+				return err
+			}
+		}
+
 		// remove sub accounts
 		if len(removeSubAccountIds) > 0 {
-			subAccountsCount, err := sess.Where("uid=? AND deleted=? AND parent_account_id=?", mainAccount.Uid, false, mainAccount.AccountId).Count(&models.Account{})
+			subAccountsCount, err := sess.Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=?", mainAccount.Uid, mainAccount.LedgerId, false, mainAccount.AccountId).Count(&models.Account{})
 
 			if subAccountsCount <= int64(len(removeSubAccountIds)) {
 				return errs.ErrAccountHaveNoSubAccount
@@ -557,7 +613,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 				DeletedUnixTime: now,
 			}
 
-			deletedRows, err := sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", mainAccount.Uid, false).In("account_id", removeSubAccountIds).Update(deleteAccountUpdateModel)
+			deletedRows, err := sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", mainAccount.Uid, mainAccount.LedgerId, false).In("account_id", removeSubAccountIds).Update(deleteAccountUpdateModel)
 
 			if err != nil {
 				return err
@@ -585,6 +641,12 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 					log.Errorf(c, "[accounts.ModifyAccounts] it should delete %d transactions, but have deleted %d actually", len(transactionIds), deletedTransactionRows)
 					return errs.ErrDatabaseOperationFailed
 				}
+
+				err = NetWorthSnapshots.invalidateSnapshots(sess, mainAccount.Uid, mainAccount.LedgerId, 0, now)
+
+				if err != nil {
+					return err
+				}
 			}
 		}
 
@@ -593,11 +655,17 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 }
 
 // HideAccount updates hidden field of given accounts
-func (s *AccountService) HideAccount(c core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *AccountService) HideAccount(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Account{
@@ -606,7 +674,7 @@ func (s *AccountService) HideAccount(c core.Context, uid int64, ids []int64, hid
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", ids).Update(updateModel)
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("account_id", ids).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -619,11 +687,17 @@ func (s *AccountService) HideAccount(c core.Context, uid int64, ids []int64, hid
 }
 
 // ModifyAccountDisplayOrders updates display order of given accounts
-func (s *AccountService) ModifyAccountDisplayOrders(c core.Context, uid int64, accounts []*models.Account) error {
+func (s *AccountService) ModifyAccountDisplayOrders(c core.Context, uid int64, ledgerId int64, accounts []*models.Account) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(accounts); i++ {
 		accounts[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -631,7 +705,7 @@ func (s *AccountService) ModifyAccountDisplayOrders(c core.Context, uid int64, a
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(accounts); i++ {
 			account := accounts[i]
-			updatedRows, err := sess.ID(account.AccountId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
+			updatedRows, err := sess.ID(account.AccountId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(account)
 
 			if err != nil {
 				return err
@@ -645,11 +719,17 @@ func (s *AccountService) ModifyAccountDisplayOrders(c core.Context, uid int64, a
 }
 
 // DeleteAccount deletes an existed account from database
-func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int64) error {
+func (s *AccountService) DeleteAccount(c core.Context, uid int64, ledgerId int64, accountId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Account{
@@ -660,7 +740,7 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int6
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		var accountAndSubAccounts []*models.Account
-		err := sess.Where("uid=? AND deleted=? AND ((account_id=? AND parent_account_id=?) OR parent_account_id=?)", uid, false, accountId, models.LevelOneAccountParentId, accountId).Find(&accountAndSubAccounts)
+		err := sess.Where("uid=? AND ledger_id=? AND deleted=? AND ((account_id=? AND parent_account_id=?) OR parent_account_id=?)", uid, ledgerId, false, accountId, models.LevelOneAccountParentId, accountId).Find(&accountAndSubAccounts)
 
 		if err != nil {
 			return err
@@ -756,6 +836,12 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int6
 				log.Errorf(c, "[accounts.DeleteAccount] it should delete %d transactions, but have deleted %d actually", len(transactionIds), deletedTransactionRows)
 				return errs.ErrDatabaseOperationFailed
 			}
+
+			err = NetWorthSnapshots.invalidateSnapshots(sess, uid, ledgerId, 0, now)
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return err
@@ -763,11 +849,17 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int6
 }
 
 // DeleteSubAccount deletes an existed sub-account from database
-func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, accountId int64) error {
+func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, ledgerId int64, accountId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Account{
@@ -778,7 +870,7 @@ func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, accountId i
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		account := &models.Account{}
-		has, err := sess.Cols("account_id", "uid", "deleted", "parent_account_id").Where("uid=? AND deleted=? AND account_id=? AND parent_account_id<>?", uid, false, accountId, models.LevelOneAccountParentId).Limit(1).Get(account)
+		has, err := sess.Cols("account_id", "uid", "ledger_id", "deleted", "parent_account_id").Where("uid=? AND ledger_id=? AND deleted=? AND account_id=? AND parent_account_id<>?", uid, ledgerId, false, accountId, models.LevelOneAccountParentId).Limit(1).Get(account)
 
 		if err != nil {
 			return err
@@ -845,12 +937,215 @@ func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, accountId i
 				log.Errorf(c, "[accounts.DeleteSubAccount] it should delete %d transactions, but have deleted %d actually", len(transactionIds), deletedTransactionRows)
 				return errs.ErrDatabaseOperationFailed
 			}
+
+			err = NetWorthSnapshots.invalidateSnapshots(sess, uid, ledgerId, 0, now)
+
+			if err != nil {
+				return err
+			}
 		}
 
 		return err
 	})
 }
 
+// GetAllDeletedAccountsByUid returns all deleted account models in the trash bin of user
+func (s *AccountService) GetAllDeletedAccountsByUid(c core.Context, uid int64, ledgerId int64) ([]*models.Account, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var accounts []*models.Account
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).OrderBy("deleted_unix_time desc, parent_account_id asc, display_order asc").Find(&accounts)
+
+	return accounts, err
+}
+
+// RestoreAccount restores a deleted account and the sub-accounts deleted along with it from the trash bin, and re-applies their opening balances
+func (s *AccountService) RestoreAccount(c core.Context, uid int64, ledgerId int64, accountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Account{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	transactionUpdateModel := &models.Transaction{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(accountId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		accountIds := []int64{account.AccountId}
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			exists, err := sess.Cols("account_id", "uid", "ledger_id", "deleted").Where("account_id=? AND uid=? AND ledger_id=? AND deleted=?", account.ParentAccountId, uid, ledgerId, false).Exist(&models.Account{})
+
+			if err != nil {
+				return err
+			} else if !exists {
+				return errs.ErrParentAccountNotFound
+			}
+		} else {
+			var subAccounts []*models.Account
+			err = sess.Cols("account_id").Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=? AND deleted_unix_time=?", uid, ledgerId, true, account.AccountId, account.DeletedUnixTime).Find(&subAccounts)
+
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < len(subAccounts); i++ {
+				accountIds = append(accountIds, subAccounts[i].AccountId)
+			}
+		}
+
+		restoredRows, err := sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).In("account_id", accountIds).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < 1 {
+			return errs.ErrAccountNotFound
+		}
+
+		// Restore the balance modification transactions which were deleted along with the accounts
+		var balanceModificationTransactions []*models.Transaction
+		err = sess.Where("uid=? AND deleted=? AND type=? AND deleted_unix_time=?", uid, true, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, account.DeletedUnixTime).In("account_id", accountIds).Find(&balanceModificationTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(balanceModificationTransactions); i++ {
+			transaction := balanceModificationTransactions[i]
+			restoredRows, err = sess.ID(transaction.TransactionId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).Update(transactionUpdateModel)
+
+			if err != nil {
+				return err
+			} else if restoredRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+
+			if transaction.RelatedAccountAmount != 0 {
+				accountUpdateModel := &models.Account{
+					UpdatedUnixTime: now,
+				}
+
+				updatedRows, err := sess.ID(transaction.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", transaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[accounts.RestoreAccount] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+		}
+
+		if len(balanceModificationTransactions) > 0 {
+			err = NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, uid, balanceModificationTransactions, now)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PurgeAccount permanently deletes a deleted account, its deleted sub-accounts and their deleted transactions from the trash bin
+func (s *AccountService) PurgeAccount(c core.Context, uid int64, ledgerId int64, accountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		account := &models.Account{}
+		has, err := sess.ID(accountId).Cols("account_id", "parent_account_id").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+
+		accountIds := []int64{account.AccountId}
+
+		if account.ParentAccountId == models.LevelOneAccountParentId {
+			var subAccounts []*models.Account
+			err = sess.Cols("account_id").Where("uid=? AND ledger_id=? AND deleted=? AND parent_account_id=?", uid, ledgerId, true, account.AccountId).Find(&subAccounts)
+
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < len(subAccounts); i++ {
+				accountIds = append(accountIds, subAccounts[i].AccountId)
+			}
+		}
+
+		var transactions []*models.Transaction
+		err = sess.Cols("transaction_id").Where("uid=? AND deleted=?", uid, true).And(builder.In("account_id", accountIds).Or(builder.In("related_account_id", accountIds))).Find(&transactions)
+
+		if err != nil {
+			return err
+		}
+
+		if len(transactions) > 0 {
+			transactionIds := make([]int64, len(transactions))
+
+			for i := 0; i < len(transactions); i++ {
+				transactionIds[i] = transactions[i].TransactionId
+			}
+
+			err = purgeDeletedTransactionsByIds(sess, uid, transactionIds)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.Where("uid=? AND deleted=?", uid, true).In("account_id", accountIds).Delete(&models.Account{})
+
+		return err
+	})
+}
+
 // GetAccountMapByList returns an account map by a list
 func (s *AccountService) GetAccountMapByList(accounts []*models.Account) map[int64]*models.Account {
 	accountMap := make(map[int64]*models.Account)
@@ -894,7 +1189,7 @@ func (s *AccountService) GetAccountNames(accounts []*models.Account) []string {
 }
 
 // GetAccountOrSubAccountIds returns a list of account ids or sub-account ids according to given account ids
-func (s *AccountService) GetAccountOrSubAccountIds(c core.Context, accountIds string, uid int64) ([]int64, error) {
+func (s *AccountService) GetAccountOrSubAccountIds(c core.Context, accountIds string, uid int64, ledgerId int64) ([]int64, error) {
 	if accountIds == "" || accountIds == "0" {
 		return nil, nil
 	}
@@ -908,7 +1203,7 @@ func (s *AccountService) GetAccountOrSubAccountIds(c core.Context, accountIds st
 	var allAccountIds []int64
 
 	if len(requestAccountIds) > 0 {
-		allSubAccounts, err := s.GetSubAccountsByAccountIds(c, uid, requestAccountIds)
+		allSubAccounts, err := s.GetSubAccountsByAccountIds(c, uid, ledgerId, requestAccountIds)
 
 		if err != nil {
 			return nil, err
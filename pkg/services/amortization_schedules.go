@@ -0,0 +1,385 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const amortizationPrincipalTemplateNameSuffix = " - Principal"
+const amortizationInterestTemplateNameSuffix = " - Interest"
+
+// AmortizationScheduleService represents amortization schedule service
+type AmortizationScheduleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an amortization schedule service singleton instance
+var (
+	AmortizationSchedules = &AmortizationScheduleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllSchedulesByUid returns all amortization schedule models of user, or the ones of the specified debt account if account id is not zero
+func (s *AmortizationScheduleService) GetAllSchedulesByUid(c core.Context, uid int64, ledgerId int64, accountId int64) ([]*models.AmortizationSchedule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := []any{uid, ledgerId, false}
+
+	if accountId > 0 {
+		condition = condition + " AND account_id=?"
+		conditionParams = append(conditionParams, accountId)
+	}
+
+	var schedules []*models.AmortizationSchedule
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).Find(&schedules)
+
+	return schedules, err
+}
+
+// GetScheduleByScheduleId returns an amortization schedule model according to schedule id
+func (s *AmortizationScheduleService) GetScheduleByScheduleId(c core.Context, uid int64, ledgerId int64, scheduleId int64) (*models.AmortizationSchedule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if scheduleId <= 0 {
+		return nil, errs.ErrAmortizationScheduleIdInvalid
+	}
+
+	schedule := &models.AmortizationSchedule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(scheduleId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(schedule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrAmortizationScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+// CreateSchedule saves a new amortization schedule model and its scheduled transaction templates to database
+func (s *AmortizationScheduleService) CreateSchedule(c core.Context, schedule *models.AmortizationSchedule) error {
+	if schedule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, schedule.Uid, schedule.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	installments, err := schedule.GetInstallments()
+
+	if err != nil {
+		return err
+	}
+
+	startTime, err := utils.ParseFromLongDateFirstTime(schedule.GetFirstPaymentDate(), schedule.TimezoneUtcOffset)
+
+	if err != nil {
+		return errs.ErrAmortizationFirstPaymentDateInvalid
+	}
+
+	endTime, err := utils.ParseFromLongDateLastTime(schedule.GetLastPaymentDate(), schedule.TimezoneUtcOffset)
+
+	if err != nil {
+		return errs.ErrAmortizationFirstPaymentDateInvalid
+	}
+
+	// the amortization schedule shares the uuid type with the scheduled templates it generates
+	needUuidCount := uint16(2)
+
+	if schedule.InterestCategoryId > 0 {
+		needUuidCount++
+	}
+
+	uuids := s.GenerateUuids(uuid.UUID_TYPE_TEMPLATE, needUuidCount)
+
+	if len(uuids) < int(needUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
+	schedule.ScheduleId = uuids[0]
+	templateUuids := uuids[1:]
+
+	now := time.Now().Unix()
+	startUnixTime := startTime.Unix()
+	endUnixTime := endTime.Unix()
+
+	principalTemplate := s.createNewScheduledTemplate(schedule, templateUuids[0], schedule.Name+amortizationPrincipalTemplateNameSuffix, models.TRANSACTION_TYPE_TRANSFER, schedule.PrincipalCategoryId, installments[0].Principal, startUnixTime, endUnixTime, now)
+	principalTemplate.RelatedAccountId = schedule.AccountId
+	principalTemplate.RelatedAccountAmount = installments[0].Principal
+	schedule.PrincipalTemplateId = principalTemplate.TemplateId
+
+	var interestTemplate *models.TransactionTemplate
+
+	if schedule.InterestCategoryId > 0 {
+		interestTemplate = s.createNewScheduledTemplate(schedule, templateUuids[1], schedule.Name+amortizationInterestTemplateNameSuffix, models.TRANSACTION_TYPE_EXPENSE, schedule.InterestCategoryId, installments[0].Interest, startUnixTime, endUnixTime, now)
+		schedule.InterestTemplateId = interestTemplate.TemplateId
+	}
+
+	schedule.Deleted = false
+	schedule.CreatedUnixTime = now
+	schedule.UpdatedUnixTime = now
+
+	return s.UserDataDB(schedule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isScheduleValid(sess, schedule)
+
+		if err != nil {
+			return err
+		}
+
+		maxOrderTemplate := &models.TransactionTemplate{}
+		has, err := sess.Cols("uid", "ledger_id", "deleted", "template_type", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND template_type=?", schedule.Uid, schedule.LedgerId, false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE).OrderBy("display_order desc").Limit(1).Get(maxOrderTemplate)
+
+		if err != nil {
+			return err
+		}
+
+		if has {
+			principalTemplate.DisplayOrder = maxOrderTemplate.DisplayOrder + 1
+		} else {
+			principalTemplate.DisplayOrder = 1
+		}
+
+		_, err = sess.Insert(principalTemplate)
+
+		if err != nil {
+			return err
+		}
+
+		if interestTemplate != nil {
+			interestTemplate.DisplayOrder = principalTemplate.DisplayOrder + 1
+			_, err = sess.Insert(interestTemplate)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.Insert(schedule)
+		return err
+	})
+}
+
+// DeleteSchedule deletes an existed amortization schedule and its scheduled transaction templates from database
+func (s *AmortizationScheduleService) DeleteSchedule(c core.Context, uid int64, ledgerId int64, scheduleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.AmortizationSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	templateUpdateModel := &models.TransactionTemplate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(scheduleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrAmortizationScheduleNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=? AND amortization_schedule_id=?", uid, ledgerId, false, scheduleId).Update(templateUpdateModel)
+
+		return err
+	})
+}
+
+// DeleteAllSchedules deletes all existed amortization schedules and their scheduled transaction templates from database
+func (s *AmortizationScheduleService) DeleteAllSchedules(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.AmortizationSchedule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	templateUpdateModel := &models.TransactionTemplate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=? AND amortization_schedule_id<>?", uid, ledgerId, false, 0).Update(templateUpdateModel)
+
+		return err
+	})
+}
+
+func (s *AmortizationScheduleService) createNewScheduledTemplate(schedule *models.AmortizationSchedule, templateId int64, name string, transactionType models.TransactionType, categoryId int64, amount int64, startUnixTime int64, endUnixTime int64, now int64) *models.TransactionTemplate {
+	return &models.TransactionTemplate{
+		TemplateId:                  templateId,
+		Uid:                         schedule.Uid,
+		LedgerId:                    schedule.LedgerId,
+		Deleted:                     false,
+		TemplateType:                models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		Name:                        name,
+		Type:                        transactionType,
+		CategoryId:                  categoryId,
+		AccountId:                   schedule.PaymentAccountId,
+		ScheduledFrequencyType:      models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:          utils.IntToString(int(schedule.PaymentDay)),
+		ScheduledStartTime:          &startUnixTime,
+		ScheduledEndTime:            &endUnixTime,
+		ScheduledAt:                 s.getUTCScheduledAt(schedule.TimezoneUtcOffset),
+		ScheduledTimezoneUtcOffset:  schedule.TimezoneUtcOffset,
+		ScheduledLastOccurrenceTime: now,
+		Amount:                      amount,
+		Comment:                     schedule.Comment,
+		AmortizationScheduleId:      schedule.ScheduleId,
+		CreatedUnixTime:             now,
+		UpdatedUnixTime:             now,
+	}
+}
+
+func (s *AmortizationScheduleService) getUTCScheduledAt(utcOffset int16) int16 {
+	scheduleTimeZone := time.FixedZone("Schedule Timezone", int(utcOffset)*60)
+	scheduledTime := time.Date(2020, 1, 1, 0, 0, 0, 0, scheduleTimeZone)
+	scheduledTimeInUTC := scheduledTime.In(time.UTC)
+
+	return int16(scheduledTimeInUTC.Hour()*60 + scheduledTimeInUTC.Minute())
+}
+
+func (s *AmortizationScheduleService) isScheduleValid(sess *xorm.Session, schedule *models.AmortizationSchedule) error {
+	// check debt account is valid
+	debtAccount := &models.Account{}
+	has, err := sess.ID(schedule.AccountId).Where("uid=? AND ledger_id=? AND deleted=?", schedule.Uid, schedule.LedgerId, false).Get(debtAccount)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrAccountNotFound
+	}
+
+	if debtAccount.Hidden {
+		return errs.ErrCannotUseHiddenAccount
+	}
+
+	if debtAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return errs.ErrCannotAddTransactionToParentAccount
+	}
+
+	if !debtAccount.Category.IsLiability() {
+		return errs.ErrAmortizationScheduleAccountInvalid
+	}
+
+	// check payment account is valid
+	if schedule.PaymentAccountId == schedule.AccountId {
+		return errs.ErrAmortizationPaymentAccountInvalid
+	}
+
+	paymentAccount := &models.Account{}
+	has, err = sess.ID(schedule.PaymentAccountId).Where("uid=? AND ledger_id=? AND deleted=?", schedule.Uid, schedule.LedgerId, false).Get(paymentAccount)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrSourceAccountNotFound
+	}
+
+	if paymentAccount.Hidden {
+		return errs.ErrCannotUseHiddenAccount
+	}
+
+	if paymentAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return errs.ErrCannotAddTransactionToParentAccount
+	}
+
+	if paymentAccount.Currency != debtAccount.Currency {
+		return errs.ErrAmortizationPaymentAccountCurrencyNotMatch
+	}
+
+	// check categories are valid
+	err = s.isCategoryValid(sess, schedule.Uid, schedule.LedgerId, schedule.PrincipalCategoryId, models.CATEGORY_TYPE_TRANSFER)
+
+	if err != nil {
+		return err
+	}
+
+	if schedule.InterestCategoryId > 0 {
+		err = s.isCategoryValid(sess, schedule.Uid, schedule.LedgerId, schedule.InterestCategoryId, models.CATEGORY_TYPE_EXPENSE)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *AmortizationScheduleService) isCategoryValid(sess *xorm.Session, uid int64, ledgerId int64, categoryId int64, categoryType models.TransactionCategoryType) error {
+	category := &models.TransactionCategory{}
+	has, err := sess.ID(categoryId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(category)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if category.Hidden {
+		return errs.ErrCannotUseHiddenTransactionCategory
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return errs.ErrCannotUsePrimaryCategoryForTransaction
+	}
+
+	if category.Type != categoryType {
+		return errs.ErrTransactionCategoryTypeInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// BudgetService represents budget service
+type BudgetService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a budget service singleton instance
+var (
+	Budgets = &BudgetService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetTotalBudgetCountByUid returns total budget count of user
+func (s *BudgetService) GetTotalBudgetCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.Budget{})
+
+	return count, err
+}
+
+// GetAllBudgetsByUid returns all budget models of user
+func (s *BudgetService) GetAllBudgetsByUid(c core.Context, uid int64, ledgerId int64, visibleOnly bool) ([]*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := []any{uid, ledgerId, false}
+
+	if visibleOnly {
+		condition = condition + " AND hidden=?"
+		conditionParams = append(conditionParams, false)
+	}
+
+	var budgets []*models.Budget
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("display_order asc").Find(&budgets)
+
+	return budgets, err
+}
+
+// GetBudgetByBudgetId returns a budget model according to budget id
+func (s *BudgetService) GetBudgetByBudgetId(c core.Context, uid int64, ledgerId int64, budgetId int64) (*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if budgetId <= 0 {
+		return nil, errs.ErrBudgetIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(budgetId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(budget)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *BudgetService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("display_order desc").Limit(1).Get(budget)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return budget.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateBudget saves a new budget model to database
+func (s *BudgetService) CreateBudget(c core.Context, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, budget.Uid, budget.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	budget.BudgetId = s.GenerateUuid(uuid.UUID_TYPE_BUDGET)
+
+	if budget.BudgetId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	budget.Deleted = false
+	budget.CreatedUnixTime = time.Now().Unix()
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(budget)
+		return err
+	})
+}
+
+// ModifyBudget saves an existed budget model to database
+func (s *BudgetService) ModifyBudget(c core.Context, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, budget.Uid, budget.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(budget.BudgetId).Cols("name", "period_type", "category_id", "account_id", "currency", "amount", "rollover", "start_year_month", "comment", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", budget.Uid, budget.LedgerId, false).Update(budget)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// HideBudget updates hidden field of given budget ids
+func (s *BudgetService) HideBudget(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("budget_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyBudgetDisplayOrders updates display order of given budgets
+func (s *BudgetService) ModifyBudgetDisplayOrders(c core.Context, uid int64, ledgerId int64, budgets []*models.Budget) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(budgets); i++ {
+		budgets[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(budgets); i++ {
+			budget := budgets[i]
+			updatedRows, err := sess.ID(budget.BudgetId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(budget)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrBudgetNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteBudget deletes an existed budget from database
+func (s *BudgetService) DeleteBudget(c core.Context, uid int64, ledgerId int64, budgetId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(budgetId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllBudgets deletes all existed budgets from database
+func (s *BudgetService) DeleteAllBudgets(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetBudgetUsagesYearMonthRange returns the numeric start and end year month of monthly amounts which are required for calculating budget usages of the specified year and month
+func (s *BudgetService) GetBudgetUsagesYearMonthRange(budgets []*models.Budget, yearMonth int32, fiscalYearStart core.FiscalYearStart) (int32, int32) {
+	var minYearMonth, maxYearMonth int32
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		periodStartYearMonth := budget.GetPeriodStartYearMonth(yearMonth, fiscalYearStart)
+		periodEndYearMonth := budget.GetPeriodEndYearMonth(periodStartYearMonth)
+		startYearMonth := periodStartYearMonth
+
+		if budget.Rollover {
+			firstPeriodStartYearMonth := budget.GetPeriodStartYearMonth(budget.StartYearMonth, fiscalYearStart)
+
+			if firstPeriodStartYearMonth < startYearMonth {
+				startYearMonth = firstPeriodStartYearMonth
+			}
+		}
+
+		if minYearMonth == 0 || startYearMonth < minYearMonth {
+			minYearMonth = startYearMonth
+		}
+
+		if maxYearMonth == 0 || periodEndYearMonth > maxYearMonth {
+			maxYearMonth = periodEndYearMonth
+		}
+	}
+
+	return minYearMonth, maxYearMonth
+}
+
+// GetBudgetUsages returns the planned, rollover and actual amounts of budgets in the period which contains the specified year and month,
+// the monthly total amounts of each budget currency should be the amounts of all accounts converted into that currency
+func (s *BudgetService) GetBudgetUsages(budgets []*models.Budget, yearMonth int32, fiscalYearStart core.FiscalYearStart, currencyMonthlyTotalAmounts map[string]map[int32][]*models.Transaction, categoryMap map[int64]*models.TransactionCategory, accountMap map[int64]*models.Account) []*models.BudgetUsage {
+	budgetUsages := make([]*models.BudgetUsage, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		monthlyTotalAmounts := currencyMonthlyTotalAmounts[budget.Currency]
+		periodStartYearMonth := budget.GetPeriodStartYearMonth(yearMonth, fiscalYearStart)
+		rolloverAmount := int64(0)
+
+		if budget.Rollover {
+			for previousPeriodStartYearMonth := budget.GetPeriodStartYearMonth(budget.StartYearMonth, fiscalYearStart); previousPeriodStartYearMonth < periodStartYearMonth; previousPeriodStartYearMonth = utils.AddMonthsToNumericYearMonth(previousPeriodStartYearMonth, budget.GetPeriodMonthCount()) {
+				previousActualAmount := s.getBudgetActualAmount(budget, previousPeriodStartYearMonth, monthlyTotalAmounts, categoryMap, accountMap)
+				rolloverAmount = budget.Amount + rolloverAmount - previousActualAmount
+
+				if rolloverAmount < 0 {
+					rolloverAmount = 0
+				}
+			}
+		}
+
+		budgetUsages[i] = &models.BudgetUsage{
+			BudgetId:             budget.BudgetId,
+			PeriodStartYearMonth: periodStartYearMonth,
+			PeriodEndYearMonth:   budget.GetPeriodEndYearMonth(periodStartYearMonth),
+			PlannedAmount:        budget.Amount,
+			RolloverAmount:       rolloverAmount,
+			ActualAmount:         s.getBudgetActualAmount(budget, periodStartYearMonth, monthlyTotalAmounts, categoryMap, accountMap),
+		}
+	}
+
+	return budgetUsages
+}
+
+func (s *BudgetService) getBudgetActualAmount(budget *models.Budget, periodStartYearMonth int32, monthlyTotalAmounts map[int32][]*models.Transaction, categoryMap map[int64]*models.TransactionCategory, accountMap map[int64]*models.Account) int64 {
+	actualAmount := int64(0)
+	periodEndYearMonth := budget.GetPeriodEndYearMonth(periodStartYearMonth)
+
+	for yearMonth := periodStartYearMonth; yearMonth <= periodEndYearMonth; yearMonth = utils.AddMonthsToNumericYearMonth(yearMonth, 1) {
+		totalAmounts := monthlyTotalAmounts[yearMonth]
+
+		for i := 0; i < len(totalAmounts); i++ {
+			if s.isTransactionAmountMatchBudget(budget, totalAmounts[i], categoryMap, accountMap) {
+				actualAmount += totalAmounts[i].Amount
+			}
+		}
+	}
+
+	return actualAmount
+}
+
+func (s *BudgetService) isTransactionAmountMatchBudget(budget *models.Budget, totalAmount *models.Transaction, categoryMap map[int64]*models.TransactionCategory, accountMap map[int64]*models.Account) bool {
+	if totalAmount.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return false
+	}
+
+	account, exists := accountMap[totalAmount.AccountId]
+
+	if !exists {
+		return false
+	}
+
+	if budget.AccountId > 0 && account.AccountId != budget.AccountId && account.ParentAccountId != budget.AccountId {
+		return false
+	}
+
+	if budget.CategoryId > 0 && totalAmount.CategoryId != budget.CategoryId {
+		category, exists := categoryMap[totalAmount.CategoryId]
+
+		if !exists || category.ParentCategoryId != budget.CategoryId {
+			return false
+		}
+	}
+
+	return true
+}
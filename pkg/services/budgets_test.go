@@ -0,0 +1,155 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+var (
+	testBudgetCategoryMap = map[int64]*models.TransactionCategory{
+		100: {CategoryId: 100, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		101: {CategoryId: 101, ParentCategoryId: 100},
+		102: {CategoryId: 102, ParentCategoryId: 100},
+		200: {CategoryId: 200, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		201: {CategoryId: 201, ParentCategoryId: 200},
+	}
+	testBudgetAccountMap = map[int64]*models.Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "USD"},
+		3: {AccountId: 3, Currency: "EUR"},
+	}
+)
+
+func TestGetBudgetUsages_ParentCategory(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, CategoryId: 100, Currency: "USD", Amount: 10000, StartYearMonth: 202401},
+	}
+	monthlyTotalAmounts := map[int32][]*models.Transaction{
+		202403: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 1000},
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 102, AccountId: 2, Amount: 2000},
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 201, AccountId: 1, Amount: 4000},
+			{Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 101, AccountId: 1, Amount: 16000},
+		},
+	}
+
+	actualUsages := Budgets.GetBudgetUsages(budgets, 202403, core.FISCAL_YEAR_START_DEFAULT, map[string]map[int32][]*models.Transaction{"USD": monthlyTotalAmounts}, testBudgetCategoryMap, testBudgetAccountMap)
+
+	assert.Equal(t, 1, len(actualUsages))
+	assert.Equal(t, int32(202403), actualUsages[0].PeriodStartYearMonth)
+	assert.Equal(t, int32(202403), actualUsages[0].PeriodEndYearMonth)
+	assert.Equal(t, int64(10000), actualUsages[0].PlannedAmount)
+	assert.Equal(t, int64(0), actualUsages[0].RolloverAmount)
+	assert.Equal(t, int64(3000), actualUsages[0].ActualAmount)
+}
+
+func TestGetBudgetUsages_Account(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, AccountId: 2, Currency: "USD", Amount: 10000, StartYearMonth: 202401},
+	}
+	monthlyTotalAmounts := map[int32][]*models.Transaction{
+		202403: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 1000},
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 102, AccountId: 2, Amount: 2000},
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 201, AccountId: 2, Amount: 4000},
+		},
+	}
+
+	actualUsages := Budgets.GetBudgetUsages(budgets, 202403, core.FISCAL_YEAR_START_DEFAULT, map[string]map[int32][]*models.Transaction{"USD": monthlyTotalAmounts}, testBudgetCategoryMap, testBudgetAccountMap)
+
+	assert.Equal(t, 1, len(actualUsages))
+	assert.Equal(t, int64(6000), actualUsages[0].ActualAmount)
+}
+
+func TestGetBudgetUsages_AccountsInOtherCurrency(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, CategoryId: 100, Currency: "USD", Amount: 10000, StartYearMonth: 202401},
+		{BudgetId: 2, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, CategoryId: 100, Currency: "EUR", Amount: 10000, StartYearMonth: 202401},
+	}
+	currencyMonthlyTotalAmounts := map[string]map[int32][]*models.Transaction{
+		"USD": {
+			202403: {
+				{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 1000},
+				{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 3, Amount: 4000},
+			},
+		},
+		"EUR": {
+			202403: {
+				{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 500},
+				{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 3, Amount: 2000},
+			},
+		},
+	}
+
+	actualUsages := Budgets.GetBudgetUsages(budgets, 202403, core.FISCAL_YEAR_START_DEFAULT, currencyMonthlyTotalAmounts, testBudgetCategoryMap, testBudgetAccountMap)
+
+	assert.Equal(t, 2, len(actualUsages))
+	assert.Equal(t, int64(5000), actualUsages[0].ActualAmount)
+	assert.Equal(t, int64(2500), actualUsages[1].ActualAmount)
+}
+
+func TestGetBudgetUsages_Rollover(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, CategoryId: 100, Currency: "USD", Amount: 10000, Rollover: true, StartYearMonth: 202401},
+	}
+	monthlyTotalAmounts := map[int32][]*models.Transaction{
+		202401: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 6000},
+		},
+		202402: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 20000},
+		},
+		202403: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 7000},
+		},
+	}
+
+	actualUsages := Budgets.GetBudgetUsages(budgets, 202402, core.FISCAL_YEAR_START_DEFAULT, map[string]map[int32][]*models.Transaction{"USD": monthlyTotalAmounts}, testBudgetCategoryMap, testBudgetAccountMap)
+	assert.Equal(t, int64(4000), actualUsages[0].RolloverAmount)
+	assert.Equal(t, int64(20000), actualUsages[0].ActualAmount)
+
+	actualUsages = Budgets.GetBudgetUsages(budgets, 202404, core.FISCAL_YEAR_START_DEFAULT, map[string]map[int32][]*models.Transaction{"USD": monthlyTotalAmounts}, testBudgetCategoryMap, testBudgetAccountMap)
+	assert.Equal(t, int64(3000), actualUsages[0].RolloverAmount)
+	assert.Equal(t, int64(0), actualUsages[0].ActualAmount)
+}
+
+func TestGetBudgetUsages_FiscalYear(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_FISCAL_YEAR, Currency: "USD", Amount: 100000, StartYearMonth: 202401},
+	}
+	monthlyTotalAmounts := map[int32][]*models.Transaction{
+		202403: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 101, AccountId: 1, Amount: 1000},
+		},
+		202404: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 201, AccountId: 2, Amount: 2000},
+		},
+		202503: {
+			{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 102, AccountId: 1, Amount: 4000},
+		},
+	}
+	fiscalYearStart, _ := core.NewFiscalYearStart(4, 1)
+
+	actualUsages := Budgets.GetBudgetUsages(budgets, 202408, fiscalYearStart, map[string]map[int32][]*models.Transaction{"USD": monthlyTotalAmounts}, testBudgetCategoryMap, testBudgetAccountMap)
+
+	assert.Equal(t, int32(202404), actualUsages[0].PeriodStartYearMonth)
+	assert.Equal(t, int32(202503), actualUsages[0].PeriodEndYearMonth)
+	assert.Equal(t, int64(6000), actualUsages[0].ActualAmount)
+}
+
+func TestGetBudgetUsagesYearMonthRange(t *testing.T) {
+	budgets := []*models.Budget{
+		{BudgetId: 1, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, StartYearMonth: 202301},
+		{BudgetId: 2, PeriodType: models.BUDGET_PERIOD_TYPE_MONTHLY, Rollover: true, StartYearMonth: 202311},
+		{BudgetId: 3, PeriodType: models.BUDGET_PERIOD_TYPE_FISCAL_YEAR, StartYearMonth: 202401},
+	}
+
+	actualStartYearMonth, actualEndYearMonth := Budgets.GetBudgetUsagesYearMonthRange(budgets, 202403, core.FISCAL_YEAR_START_DEFAULT)
+
+	assert.Equal(t, int32(202311), actualStartYearMonth)
+	assert.Equal(t, int32(202412), actualEndYearMonth)
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const pageCountForCashFlowForecastHistoricalTransactions = 1000
+const defaultCashFlowForecastHistoricalMonths = 3
+
+// CashFlowForecastService represents cash flow forecast service
+type CashFlowForecastService struct {
+	ServiceUsingDB
+}
+
+// Initialize a cash flow forecast service singleton instance
+var (
+	CashFlowForecasts = &CashFlowForecastService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+type cashFlowForecastHistoricalAverageKey struct {
+	accountId       int64
+	categoryId      int64
+	transactionType models.TransactionType
+}
+
+// GetCashFlowForecast returns the projected daily balances of all accounts from the next day of the current time for the specified months, which expands all scheduled transaction templates and optionally adds the average historical amounts of each category
+func (s *CashFlowForecastService) GetCashFlowForecast(c core.Context, uid int64, ledgerId int64, currentUnixTime int64, months int32, includeHistoricalAverage bool, historicalMonths int32, clientTimezone *time.Location) (*models.TransactionCashFlowForecastResponse, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	currentTime := time.Unix(currentUnixTime, 0).In(clientTimezone)
+	todayFirstTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, clientTimezone)
+	startTime := todayFirstTime.AddDate(0, 0, 1)
+	forecast := models.NewCashFlowForecast(startTime, startTime.AddDate(0, int(months), 0))
+
+	allAccounts, err := Accounts.GetAllAccountsByUid(c, uid, ledgerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*models.Account, 0, len(allAccounts))
+
+	for i := 0; i < len(allAccounts); i++ {
+		if allAccounts[i].Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		accounts = append(accounts, allAccounts[i])
+	}
+
+	err = s.addScheduledTransactionAmounts(c, uid, ledgerId, forecast)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if includeHistoricalAverage {
+		if historicalMonths < 1 {
+			historicalMonths = defaultCashFlowForecastHistoricalMonths
+		}
+
+		err = s.addHistoricalAverageAmounts(c, uid, ledgerId, todayFirstTime.AddDate(0, -int(historicalMonths), 0), todayFirstTime, historicalMonths, forecast)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return forecast.ToTransactionCashFlowForecastResponse(accounts), nil
+}
+
+func (s *CashFlowForecastService) addScheduledTransactionAmounts(c core.Context, uid int64, ledgerId int64, forecast *models.CashFlowForecast) error {
+	templates, err := TransactionTemplates.GetAllTemplatesByUid(c, uid, ledgerId, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+	if err != nil {
+		return err
+	}
+
+	schedules, err := AmortizationSchedules.GetAllSchedulesByUid(c, uid, ledgerId, 0)
+
+	if err != nil {
+		return err
+	}
+
+	scheduleMap := make(map[int64]*models.AmortizationSchedule, len(schedules))
+
+	for i := 0; i < len(schedules); i++ {
+		scheduleMap[schedules[i].ScheduleId] = schedules[i]
+	}
+
+	for i := 0; i < len(templates); i++ {
+		template := templates[i]
+
+		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+			continue
+		}
+
+		if template.Type != models.TRANSACTION_TYPE_EXPENSE && template.Type != models.TRANSACTION_TYPE_INCOME && template.Type != models.TRANSACTION_TYPE_TRANSFER {
+			log.Warnf(c, "[cash_flow_forecasts.addScheduledTransactionAmounts] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
+			continue
+		}
+
+		transactionUnixTimes, err := template.GetScheduledTransactionTimes(forecast.GetStartUnixTime(), forecast.GetEndUnixTime())
+
+		if err != nil {
+			log.Warnf(c, "[cash_flow_forecasts.addScheduledTransactionAmounts] transaction template \"id:%d\" has invalid scheduled transaction frequency, because %s", template.TemplateId, err.Error())
+			continue
+		}
+
+		overrides, err := TransactionTemplates.GetAllOccurrenceOverridesByTemplateId(c, uid, template.TemplateId)
+
+		if err != nil {
+			return err
+		}
+
+		transactionUnixTimes = models.ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes, overrides, forecast.GetStartUnixTime(), forecast.GetEndUnixTime())
+
+		var schedule *models.AmortizationSchedule
+
+		if template.AmortizationScheduleId > 0 {
+			schedule = scheduleMap[template.AmortizationScheduleId]
+
+			if schedule == nil {
+				log.Warnf(c, "[cash_flow_forecasts.addScheduledTransactionAmounts] transaction template \"id:%d\" cannot find amortization schedule \"id:%d\"", template.TemplateId, template.AmortizationScheduleId)
+				continue
+			}
+		}
+
+		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
+
+		for j := 0; j < len(transactionUnixTimes); j++ {
+			amount := template.Amount
+			relatedAccountAmount := template.RelatedAccountAmount
+
+			if schedule != nil {
+				transactionTime := time.Unix(transactionUnixTimes[j], 0).In(templateTimeZone)
+				amount, err = schedule.GetInstallmentAmount(template.TemplateId, transactionTime.Year(), transactionTime.Month())
+
+				if err != nil {
+					log.Warnf(c, "[cash_flow_forecasts.addScheduledTransactionAmounts] transaction template \"id:%d\" cannot get amount of amortization schedule \"id:%d\", because %s", template.TemplateId, template.AmortizationScheduleId, err.Error())
+					break
+				}
+
+				relatedAccountAmount = amount
+			}
+
+			if template.Type == models.TRANSACTION_TYPE_EXPENSE {
+				forecast.AddScheduledAmount(template.AccountId, transactionUnixTimes[j], -amount)
+			} else if template.Type == models.TRANSACTION_TYPE_INCOME {
+				forecast.AddScheduledAmount(template.AccountId, transactionUnixTimes[j], amount)
+			} else if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+				forecast.AddScheduledAmount(template.AccountId, transactionUnixTimes[j], -amount)
+				forecast.AddScheduledAmount(template.RelatedAccountId, transactionUnixTimes[j], relatedAccountAmount)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *CashFlowForecastService) addHistoricalAverageAmounts(c core.Context, uid int64, ledgerId int64, historicalStartTime time.Time, historicalEndTime time.Time, historicalMonths int32, forecast *models.CashFlowForecast) error {
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(historicalStartTime.Unix())
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(historicalEndTime.Unix() - 1)
+
+	transactions, err := Transactions.GetAllSpecifiedTransactions(c, uid, ledgerId, maxTransactionTime, minTransactionTime, 0, nil, nil, nil, false, "", "", pageCountForCashFlowForecastHistoricalTransactions, true)
+
+	if err != nil {
+		return err
+	}
+
+	allTransactionSplits, err := Transactions.getTransactionSplitsInTimeRange(c, uid, minTransactionTime, maxTransactionTime)
+
+	if err != nil {
+		return err
+	}
+
+	transactions = Transactions.expandTransactionSplits(transactions, allTransactionSplits)
+
+	totalAmounts := make(map[cashFlowForecastHistoricalAverageKey]int64)
+	keys := make([]cashFlowForecastHistoricalAverageKey, 0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		// the transactions created by scheduled transaction templates have been projected by the templates
+		if transaction.ScheduledCreated {
+			continue
+		}
+
+		var transactionType models.TransactionType
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionType = models.TRANSACTION_TYPE_INCOME
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			transactionType = models.TRANSACTION_TYPE_EXPENSE
+		} else {
+			continue
+		}
+
+		key := cashFlowForecastHistoricalAverageKey{
+			accountId:       transaction.AccountId,
+			categoryId:      transaction.CategoryId,
+			transactionType: transactionType,
+		}
+
+		if _, exists := totalAmounts[key]; !exists {
+			keys = append(keys, key)
+		}
+
+		totalAmounts[key] += transaction.Amount
+	}
+
+	historicalDays := int(historicalEndTime.Sub(historicalStartTime).Hours()/24 + 0.5)
+
+	for i := 0; i < len(keys); i++ {
+		key := keys[i]
+		forecast.AddHistoricalAverageAmount(key.accountId, key.categoryId, key.transactionType, totalAmounts[key], historicalMonths, historicalDays)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ExchangeRateSnapshotService represents daily exchange rate snapshot service
+type ExchangeRateSnapshotService struct {
+	ServiceUsingDB
+}
+
+// Initialize a daily exchange rate snapshot service singleton instance
+var (
+	ExchangeRateSnapshots = &ExchangeRateSnapshotService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetSnapshotsInDateRange returns the exchange rate snapshot models of the specified data source which are in effect between the specified numeric dates (yyyyMMdd) in ascending order of rate date,
+// the snapshots include the last one before the start date (or the first one if there is no snapshot before the start date), zero date means no limit, uid is zero if the data source is not user custom
+func (s *ExchangeRateSnapshotService) GetSnapshotsInDateRange(c core.Context, uid int64, dataSource string, startRateDate int32, endRateDate int32) ([]*models.ExchangeRateSnapshot, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND data_source=?"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, dataSource)
+
+	if startRateDate > 0 {
+		firstSnapshot := &models.ExchangeRateSnapshot{}
+		has, err := s.UserDataDB(uid).NewSession(c).Cols("rate_date").Where("uid=? AND data_source=? AND rate_date<=?", uid, dataSource, startRateDate).OrderBy("rate_date desc").Limit(1).Get(firstSnapshot)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !has {
+			has, err = s.UserDataDB(uid).NewSession(c).Cols("rate_date").Where("uid=? AND data_source=? AND rate_date>?", uid, dataSource, startRateDate).OrderBy("rate_date asc").Limit(1).Get(firstSnapshot)
+
+			if err != nil {
+				return nil, err
+			} else if !has {
+				return make([]*models.ExchangeRateSnapshot, 0), nil
+			}
+		}
+
+		startRateDate = firstSnapshot.RateDate
+		condition = condition + " AND rate_date>=?"
+		conditionParams = append(conditionParams, startRateDate)
+	}
+
+	if endRateDate > 0 {
+		if endRateDate < startRateDate {
+			endRateDate = startRateDate
+		}
+
+		condition = condition + " AND rate_date<=?"
+		conditionParams = append(conditionParams, endRateDate)
+	}
+
+	var snapshots []*models.ExchangeRateSnapshot
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("rate_date asc").Find(&snapshots)
+
+	return snapshots, err
+}
+
+// GetSnapshotsAtDate returns the exchange rate snapshot models which are in effect on the specified numeric date (yyyyMMdd), uid is zero if the data source is not user custom
+func (s *ExchangeRateSnapshotService) GetSnapshotsAtDate(c core.Context, uid int64, dataSource string, rateDate int32) ([]*models.ExchangeRateSnapshot, error) {
+	if uid < 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	latestSnapshot := &models.ExchangeRateSnapshot{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("rate_date").Where("uid=? AND data_source=? AND rate_date<=?", uid, dataSource, rateDate).OrderBy("rate_date desc").Limit(1).Get(latestSnapshot)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExchangeRateSnapshotNotFound
+	}
+
+	var snapshots []*models.ExchangeRateSnapshot
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND data_source=? AND rate_date=?", uid, dataSource, latestSnapshot.RateDate).Find(&snapshots)
+
+	return snapshots, err
+}
+
+// SaveSnapshots saves the exchange rate snapshot models of one day to database, and replaces the existed ones of the same day
+func (s *ExchangeRateSnapshotService) SaveSnapshots(c core.Context, uid int64, dataSource string, rateDate int32, snapshots []*models.ExchangeRateSnapshot) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	for i := 0; i < len(snapshots); i++ {
+		snapshots[i].CreatedUnixTime = now
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=? AND data_source=? AND rate_date=?", uid, dataSource, rateDate).Delete(&models.ExchangeRateSnapshot{})
+
+		if err != nil {
+			return err
+		}
+
+		if len(snapshots) < 1 {
+			return nil
+		}
+
+		_, err = sess.Insert(snapshots)
+		return err
+	})
+}
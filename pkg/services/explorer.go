@@ -31,30 +31,30 @@ var (
 )
 
 // GetTotalInsightsExplorersCountByUid returns total insights explorers count of user
-func (s *InsightsExplorerService) GetTotalInsightsExplorersCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *InsightsExplorerService) GetTotalInsightsExplorersCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.InsightsExplorer{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.InsightsExplorer{})
 
 	return count, err
 }
 
 // GetAllInsightsExplorerNamesByUid returns all insights explorer models of user without data
-func (s *InsightsExplorerService) GetAllInsightsExplorerNamesByUid(c core.Context, uid int64) ([]*models.InsightsExplorer, error) {
+func (s *InsightsExplorerService) GetAllInsightsExplorerNamesByUid(c core.Context, uid int64, ledgerId int64) ([]*models.InsightsExplorer, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var explorers []*models.InsightsExplorer
-	err := s.UserDataDB(uid).NewSession(c).Select("explorer_id, uid, name, display_order, hidden").Where("uid=? AND deleted=?", uid, false).Find(&explorers)
+	err := s.UserDataDB(uid).NewSession(c).Select("explorer_id, uid, name, display_order, hidden").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&explorers)
 
 	return explorers, err
 }
 
 // GetInsightsExplorerByExplorerId returns a insights explorer model according to insights explorer id
-func (s *InsightsExplorerService) GetInsightsExplorerByExplorerId(c core.Context, uid int64, explorerId int64) (*models.InsightsExplorer, error) {
+func (s *InsightsExplorerService) GetInsightsExplorerByExplorerId(c core.Context, uid int64, ledgerId int64, explorerId int64) (*models.InsightsExplorer, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -64,7 +64,7 @@ func (s *InsightsExplorerService) GetInsightsExplorerByExplorerId(c core.Context
 	}
 
 	explorer := &models.InsightsExplorer{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(explorerId).Where("uid=? AND deleted=?", uid, false).Get(explorer)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(explorerId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(explorer)
 
 	if err != nil {
 		return nil, err
@@ -76,13 +76,13 @@ func (s *InsightsExplorerService) GetInsightsExplorerByExplorerId(c core.Context
 }
 
 // GetMaxDisplayOrder returns the max display order
-func (s *InsightsExplorerService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+func (s *InsightsExplorerService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	explorer := &models.InsightsExplorer{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(explorer)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("display_order desc").Limit(1).Get(explorer)
 
 	if err != nil {
 		return 0, err
@@ -101,6 +101,12 @@ func (s *InsightsExplorerService) CreateInsightsExplorer(c core.Context, explore
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, explorer.Uid, explorer.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	explorer.ExplorerId = s.GenerateUuid(uuid.UUID_TYPE_EXPLORER)
 
 	if explorer.ExplorerId < 1 {
@@ -123,10 +129,16 @@ func (s *InsightsExplorerService) ModifyInsightsExplorer(c core.Context, explore
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, explorer.Uid, explorer.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	explorer.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(explorer.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(explorer.ExplorerId).Cols("name", "data", "updated_unix_time").Where("uid=? AND deleted=?", explorer.Uid, false).Update(explorer)
+		updatedRows, err := sess.ID(explorer.ExplorerId).Cols("name", "data", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", explorer.Uid, explorer.LedgerId, false).Update(explorer)
 
 		if err != nil {
 			return err
@@ -139,11 +151,17 @@ func (s *InsightsExplorerService) ModifyInsightsExplorer(c core.Context, explore
 }
 
 // HideInsightsExplorer updates hidden field of given insights explorer ids
-func (s *InsightsExplorerService) HideInsightsExplorer(c core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *InsightsExplorerService) HideInsightsExplorer(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.InsightsExplorer{
@@ -152,7 +170,7 @@ func (s *InsightsExplorerService) HideInsightsExplorer(c core.Context, uid int64
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("explorer_id", ids).Update(updateModel)
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("explorer_id", ids).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -165,11 +183,17 @@ func (s *InsightsExplorerService) HideInsightsExplorer(c core.Context, uid int64
 }
 
 // ModifyInsightsExplorerDisplayOrders updates display order of given insights explorers
-func (s *InsightsExplorerService) ModifyInsightsExplorerDisplayOrders(c core.Context, uid int64, explorers []*models.InsightsExplorer) error {
+func (s *InsightsExplorerService) ModifyInsightsExplorerDisplayOrders(c core.Context, uid int64, ledgerId int64, explorers []*models.InsightsExplorer) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(explorers); i++ {
 		explorers[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -177,7 +201,7 @@ func (s *InsightsExplorerService) ModifyInsightsExplorerDisplayOrders(c core.Con
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(explorers); i++ {
 			explorer := explorers[i]
-			updatedRows, err := sess.ID(explorer.ExplorerId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(explorer)
+			updatedRows, err := sess.ID(explorer.ExplorerId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(explorer)
 
 			if err != nil {
 				return err
@@ -191,11 +215,17 @@ func (s *InsightsExplorerService) ModifyInsightsExplorerDisplayOrders(c core.Con
 }
 
 // DeleteInsightsExplorer deletes an existed insights explorer from database
-func (s *InsightsExplorerService) DeleteInsightsExplorer(c core.Context, uid int64, explorerId int64) error {
+func (s *InsightsExplorerService) DeleteInsightsExplorer(c core.Context, uid int64, ledgerId int64, explorerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.InsightsExplorer{
@@ -204,7 +234,7 @@ func (s *InsightsExplorerService) DeleteInsightsExplorer(c core.Context, uid int
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		deletedRows, err := sess.ID(explorerId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		deletedRows, err := sess.ID(explorerId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -217,11 +247,17 @@ func (s *InsightsExplorerService) DeleteInsightsExplorer(c core.Context, uid int
 }
 
 // DeleteAllInsightsExplorers deletes all existed insights explorers from database
-func (s *InsightsExplorerService) DeleteAllInsightsExplorers(c core.Context, uid int64) error {
+func (s *InsightsExplorerService) DeleteAllInsightsExplorers(c core.Context, uid int64, ledgerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.InsightsExplorer{
@@ -230,7 +266,7 @@ func (s *InsightsExplorerService) DeleteAllInsightsExplorers(c core.Context, uid
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
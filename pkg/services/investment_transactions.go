@@ -0,0 +1,265 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// InvestmentTransactionService represents investment transaction service
+type InvestmentTransactionService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an investment transaction service singleton instance
+var (
+	InvestmentTransactions = &InvestmentTransactionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllTransactionsByUid returns all investment transaction models of user in ascending order of transaction time, or the ones of the specified account or symbol if they are not empty
+func (s *InvestmentTransactionService) GetAllTransactionsByUid(c core.Context, uid int64, ledgerId int64, accountId int64, symbol string) ([]*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := []any{uid, ledgerId, false}
+
+	if accountId > 0 {
+		condition = condition + " AND account_id=?"
+		conditionParams = append(conditionParams, accountId)
+	}
+
+	if symbol != "" {
+		condition = condition + " AND symbol=?"
+		conditionParams = append(conditionParams, symbol)
+	}
+
+	var transactions []*models.InvestmentTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&transactions)
+
+	return transactions, err
+}
+
+// GetTransactionByTransactionId returns an investment transaction model according to investment transaction id
+func (s *InvestmentTransactionService) GetTransactionByTransactionId(c core.Context, uid int64, ledgerId int64, transactionId int64) (*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrInvestmentTransactionIdInvalid
+	}
+
+	transaction := &models.InvestmentTransaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrInvestmentTransactionNotFound
+	}
+
+	return transaction, nil
+}
+
+// CreateTransaction saves a new investment transaction model to database, and saves the cash transaction of it in the same database transaction if the cash transaction is not nil
+func (s *InvestmentTransactionService) CreateTransaction(c core.Context, transaction *models.InvestmentTransaction, cashTransaction *models.Transaction) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, transaction.Uid, transaction.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	if cashTransaction != nil && (cashTransaction.Uid != transaction.Uid || cashTransaction.LedgerId != transaction.LedgerId || cashTransaction.AccountId != transaction.AccountId) {
+		return errs.ErrInvestmentAccountInvalid
+	}
+
+	transaction.InvestmentTransactionId = s.GenerateUuid(uuid.UUID_TYPE_TRANSACTION)
+
+	if transaction.InvestmentTransactionId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	transaction.Deleted = false
+	transaction.CreatedUnixTime = time.Now().Unix()
+	transaction.UpdatedUnixTime = time.Now().Unix()
+
+	if cashTransaction != nil {
+		return Transactions.createTransaction(c, cashTransaction, nil, nil, nil, nil, transaction)
+	}
+
+	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.doCreateTransaction(sess, transaction)
+	})
+}
+
+// DeleteTransaction deletes an existed investment transaction from database and returns the deleted one
+func (s *InvestmentTransactionService) DeleteTransaction(c core.Context, uid int64, ledgerId int64, transactionId int64) (*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	transaction := &models.InvestmentTransaction{}
+
+	updateModel := &models.InvestmentTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(transaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentTransactionNotFound
+		}
+
+		if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
+			var remainingTransactions []*models.InvestmentTransaction
+			err = sess.Where("uid=? AND ledger_id=? AND deleted=? AND account_id=? AND symbol=? AND investment_transaction_id<>?", uid, ledgerId, false, transaction.AccountId, transaction.Symbol, transactionId).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&remainingTransactions)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = models.CalculateInvestmentHoldings(remainingTransactions)
+
+			if err == errs.ErrInvestmentSellQuantityExceedsHolding {
+				return errs.ErrInvestmentTransactionCannotBeDeleted
+			} else if err != nil {
+				return err
+			}
+		}
+
+		deletedRows, err := sess.ID(transactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentTransactionNotFound
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// DeleteAllTransactions deletes all existed investment transactions from database
+func (s *InvestmentTransactionService) DeleteAllTransactions(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	updateModel := &models.InvestmentTransaction{
+		Deleted:         true,
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+		return err
+	})
+}
+
+func (s *InvestmentTransactionService) isAccountValid(sess *xorm.Session, transaction *models.InvestmentTransaction) error {
+	account := &models.Account{}
+	has, err := sess.ID(transaction.AccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(account)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrAccountNotFound
+	}
+
+	if account.Hidden {
+		return errs.ErrCannotUseHiddenAccount
+	}
+
+	if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+		return errs.ErrCannotAddTransactionToParentAccount
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_INVESTMENT {
+		return errs.ErrInvestmentAccountInvalid
+	}
+
+	return nil
+}
+
+func (s *InvestmentTransactionService) doCreateTransaction(sess *xorm.Session, transaction *models.InvestmentTransaction) error {
+	err := s.isAccountValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	var existedTransactions []*models.InvestmentTransaction
+	err = sess.Where("uid=? AND ledger_id=? AND deleted=? AND account_id=? AND symbol=?", transaction.Uid, transaction.LedgerId, false, transaction.AccountId, transaction.Symbol).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&existedTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	allTransactions := make([]*models.InvestmentTransaction, 0, len(existedTransactions)+1)
+	inserted := false
+
+	for i := 0; i < len(existedTransactions); i++ {
+		if !inserted && existedTransactions[i].TransactionTime > transaction.TransactionTime {
+			allTransactions = append(allTransactions, transaction)
+			inserted = true
+		}
+
+		allTransactions = append(allTransactions, existedTransactions[i])
+	}
+
+	if !inserted {
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	_, err = models.CalculateInvestmentHoldings(allTransactions)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Insert(transaction)
+	return err
+}
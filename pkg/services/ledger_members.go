@@ -0,0 +1,337 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// LedgerMemberService represents ledger member service
+type LedgerMemberService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a ledger member service singleton instance
+var (
+	LedgerMembers = &LedgerMemberService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllMembersByLedgerId returns all member models of the specified ledger owned by user
+func (s *LedgerMemberService) GetAllMembersByLedgerId(c core.Context, uid int64, ledgerId int64) ([]*models.LedgerMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND ledger_id=?", uid, false, ledgerId).OrderBy("created_unix_time asc").Find(&members)
+
+	return members, err
+}
+
+// GetMemberByMemberId returns a ledger member model according to member id
+func (s *LedgerMemberService) GetMemberByMemberId(c core.Context, uid int64, memberId int64) (*models.LedgerMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if memberId <= 0 {
+		return nil, errs.ErrLedgerMemberIdInvalid
+	}
+
+	member := &models.LedgerMember{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(memberId).Where("uid=? AND deleted=?", uid, false).Get(member)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerMemberNotFound
+	}
+
+	return member, nil
+}
+
+// GetAllSharedLedgerMembersByMemberUid returns all member models of the ledgers which are shared with user
+func (s *LedgerMemberService) GetAllSharedLedgerMembersByMemberUid(c core.Context, memberUid int64) ([]*models.LedgerMember, error) {
+	if memberUid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var allMembers []*models.LedgerMember
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var members []*models.LedgerMember
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("member_uid=? AND deleted=?", memberUid, false).OrderBy("created_unix_time asc").Find(&members)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allMembers = append(allMembers, members...)
+	}
+
+	return allMembers, nil
+}
+
+// GetSharedLedgerMemberByLedgerId returns the member model of the specified ledger which is shared with user
+func (s *LedgerMemberService) GetSharedLedgerMemberByLedgerId(c core.Context, memberUid int64, ledgerId int64) (*models.LedgerMember, error) {
+	if memberUid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		member := &models.LedgerMember{}
+		has, err := s.UserDataDBByIndex(i).NewSession(c).Where("member_uid=? AND deleted=? AND ledger_id=?", memberUid, false, ledgerId).Get(member)
+
+		if err != nil {
+			return nil, err
+		} else if has {
+			return member, nil
+		}
+	}
+
+	return nil, errs.ErrLedgerNotFound
+}
+
+// GetLedgerAccess returns the access of user to the specified ledger, the ledger can be owned by user or shared with user
+func (s *LedgerMemberService) GetLedgerAccess(c core.Context, uid int64, ledgerId int64) (*models.LedgerAccess, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId < 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	if ledgerId == models.DefaultLedgerId {
+		return &models.LedgerAccess{
+			OwnerUid: uid,
+			LedgerId: ledgerId,
+			Role:     core.LEDGER_MEMBER_ROLE_OWNER,
+		}, nil
+	}
+
+	exists, err := s.UserDataDB(uid).NewSession(c).Cols("ledger_id", "uid", "deleted").Where("ledger_id=? AND uid=? AND deleted=?", ledgerId, uid, false).Exist(&models.Ledger{})
+
+	if err != nil {
+		return nil, err
+	} else if exists {
+		return &models.LedgerAccess{
+			OwnerUid: uid,
+			LedgerId: ledgerId,
+			Role:     core.LEDGER_MEMBER_ROLE_OWNER,
+		}, nil
+	}
+
+	member, err := s.GetSharedLedgerMemberByLedgerId(c, uid, ledgerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err = s.UserDataDB(member.Uid).NewSession(c).Cols("ledger_id", "uid", "deleted").Where("ledger_id=? AND uid=? AND deleted=?", ledgerId, member.Uid, false).Exist(&models.Ledger{})
+
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return &models.LedgerAccess{
+		OwnerUid: member.Uid,
+		LedgerId: ledgerId,
+		Role:     member.Role,
+	}, nil
+}
+
+// CheckLedgerPermission returns the access of user to the specified ledger if user has the specified permission in this ledger
+func (s *LedgerMemberService) CheckLedgerPermission(c core.Context, uid int64, ledgerId int64, permission core.LedgerPermission) (*models.LedgerAccess, error) {
+	access, err := s.GetLedgerAccess(c, uid, ledgerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !access.Role.HasPermission(permission) {
+		return nil, errs.ErrNoPermissionToOperateLedger
+	}
+
+	return access, nil
+}
+
+// CreateMember saves a new ledger member model to database
+func (s *LedgerMemberService) CreateMember(c core.Context, member *models.LedgerMember) error {
+	if member.Uid <= 0 || member.MemberUid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if member.LedgerId == models.DefaultLedgerId {
+		return errs.ErrDefaultLedgerCannotBeShared
+	} else if member.LedgerId < 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	if member.Uid == member.MemberUid {
+		return errs.ErrCannotShareLedgerWithYourself
+	}
+
+	if !member.Role.IsShareable() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	member.MemberId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER)
+
+	if member.MemberId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	member.Deleted = false
+	member.CreatedUnixTime = time.Now().Unix()
+	member.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(member.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("ledger_id", "uid", "deleted").Where("ledger_id=? AND uid=? AND deleted=?", member.LedgerId, member.Uid, false).Exist(&models.Ledger{})
+
+		if err != nil {
+			return err
+		} else if !exists {
+			return errs.ErrLedgerNotFound
+		}
+
+		exists, err = sess.Cols("uid", "deleted", "ledger_id", "member_uid").Where("uid=? AND deleted=? AND ledger_id=? AND member_uid=?", member.Uid, false, member.LedgerId, member.MemberUid).Exist(&models.LedgerMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerMemberAlreadyExists
+		}
+
+		_, err = sess.Insert(member)
+		return err
+	})
+}
+
+// ModifyMemberRole updates the role of an existed ledger member
+func (s *LedgerMemberService) ModifyMemberRole(c core.Context, uid int64, memberId int64, role core.LedgerMemberRole) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	if !role.IsShareable() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	updateModel := &models.LedgerMember{
+		Role:            role,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(memberId).Cols("role", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerMemberNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteMember deletes an existed ledger member from database
+func (s *LedgerMemberService) DeleteMember(c core.Context, uid int64, memberId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.LedgerMember{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(memberId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerMemberNotFound
+		}
+
+		return err
+	})
+}
+
+// LeaveSharedLedger deletes the membership of user in the specified ledger which is shared with user
+func (s *LedgerMemberService) LeaveSharedLedger(c core.Context, memberUid int64, ledgerId int64) error {
+	member, err := s.GetSharedLedgerMemberByLedgerId(c, memberUid, ledgerId)
+
+	if err != nil {
+		return err
+	}
+
+	return s.DeleteMember(c, member.Uid, member.MemberId)
+}
+
+// checkLedgerPermission returns error if the data of the specified user and ledger is operated by another user who does not have the specified permission in this ledger,
+// the operations which are not triggered by web requests (e.g. cron jobs) or operate on the data of current user are not restricted by the ledger member roles
+func checkLedgerPermission(c core.Context, uid int64, ledgerId int64, permission core.LedgerPermission) error {
+	webContext, ok := c.(*core.WebContext)
+
+	if !ok || webContext.GetCurrentUid() == uid {
+		return nil
+	}
+
+	if webContext.GetCurrentLedgerId() != ledgerId {
+		return errs.ErrNoPermissionToOperateLedger
+	}
+
+	return checkLedgerOwnerPermission(c, uid, permission)
+}
+
+// checkLedgerOwnerPermission returns error if the data of the specified user which does not belong to any ledger is operated by another user who does not have the specified permission in current ledger
+func checkLedgerOwnerPermission(c core.Context, uid int64, permission core.LedgerPermission) error {
+	webContext, ok := c.(*core.WebContext)
+
+	if !ok || webContext.GetCurrentUid() == uid {
+		return nil
+	}
+
+	if webContext.GetCurrentLedgerOwnerUid() != uid || !webContext.GetCurrentLedgerRole().HasPermission(permission) {
+		return errs.ErrNoPermissionToOperateLedger
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func createTestLedgerMemberWebContext(currentUid int64, ledgerId int64, ownerUid int64, role core.LedgerMemberRole) *core.WebContext {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	context := core.WrapWebContext(ginContext)
+	context.SetTokenClaims(&core.UserTokenClaims{Uid: currentUid})
+	context.SetCurrentLedgerId(ledgerId)
+	context.SetCurrentLedgerOwnerUid(ownerUid)
+	context.SetCurrentLedgerRole(role)
+
+	return context
+}
+
+func TestCheckLedgerPermission_NotWebContext(t *testing.T) {
+	assert.Nil(t, checkLedgerPermission(core.NewNullContext(), 1, 3, core.LEDGER_PERMISSION_MANAGE))
+	assert.Nil(t, checkLedgerOwnerPermission(core.NewNullContext(), 1, core.LEDGER_PERMISSION_MANAGE))
+}
+
+func TestCheckLedgerPermission_DataOfCurrentUser(t *testing.T) {
+	context := createTestLedgerMemberWebContext(1, 0, 1, core.LEDGER_MEMBER_ROLE_OWNER)
+
+	assert.Nil(t, checkLedgerPermission(context, 1, 3, core.LEDGER_PERMISSION_MANAGE))
+	assert.Nil(t, checkLedgerOwnerPermission(context, 1, core.LEDGER_PERMISSION_MANAGE))
+}
+
+func TestCheckLedgerPermission_LedgerMember(t *testing.T) {
+	context := createTestLedgerMemberWebContext(2, 3, 1, core.LEDGER_MEMBER_ROLE_TRANSACTION_CREATOR)
+
+	assert.Nil(t, checkLedgerPermission(context, 1, 3, core.LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.EqualError(t, checkLedgerPermission(context, 1, 3, core.LEDGER_PERMISSION_WRITE), errs.ErrNoPermissionToOperateLedger.Message)
+	assert.Nil(t, checkLedgerOwnerPermission(context, 1, core.LEDGER_PERMISSION_ADD_TRANSACTION))
+	assert.EqualError(t, checkLedgerOwnerPermission(context, 1, core.LEDGER_PERMISSION_WRITE), errs.ErrNoPermissionToOperateLedger.Message)
+
+	context = createTestLedgerMemberWebContext(2, 3, 1, core.LEDGER_MEMBER_ROLE_EDITOR)
+
+	assert.Nil(t, checkLedgerPermission(context, 1, 3, core.LEDGER_PERMISSION_WRITE))
+	assert.EqualError(t, checkLedgerPermission(context, 1, 3, core.LEDGER_PERMISSION_MANAGE), errs.ErrNoPermissionToOperateLedger.Message)
+}
+
+func TestCheckLedgerPermission_OtherLedgerOrUser(t *testing.T) {
+	context := createTestLedgerMemberWebContext(2, 3, 1, core.LEDGER_MEMBER_ROLE_EDITOR)
+
+	assert.EqualError(t, checkLedgerPermission(context, 1, 4, core.LEDGER_PERMISSION_WRITE), errs.ErrNoPermissionToOperateLedger.Message)
+	assert.EqualError(t, checkLedgerPermission(context, 5, 3, core.LEDGER_PERMISSION_WRITE), errs.ErrNoPermissionToOperateLedger.Message)
+	assert.EqualError(t, checkLedgerOwnerPermission(context, 5, core.LEDGER_PERMISSION_WRITE), errs.ErrNoPermissionToOperateLedger.Message)
+}
+
+func TestCheckLedgerPermission_WithoutLedgerAccess(t *testing.T) {
+	ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+	context := core.WrapWebContext(ginContext)
+	context.SetTokenClaims(&core.UserTokenClaims{Uid: 2})
+
+	assert.EqualError(t, checkLedgerPermission(context, 1, 0, core.LEDGER_PERMISSION_READ_BASIC_DATA), errs.ErrNoPermissionToOperateLedger.Message)
+	assert.EqualError(t, checkLedgerOwnerPermission(context, 1, core.LEDGER_PERMISSION_READ_BASIC_DATA), errs.ErrNoPermissionToOperateLedger.Message)
+}
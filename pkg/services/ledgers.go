@@ -0,0 +1,319 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// LedgerService represents ledger service
+type LedgerService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a ledger service singleton instance
+var (
+	Ledgers = &LedgerService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetTotalLedgerCountByUid returns total ledger count of user
+func (s *LedgerService) GetTotalLedgerCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.Ledger{})
+
+	return count, err
+}
+
+// GetAllLedgersByUid returns all ledger models of user
+func (s *LedgerService) GetAllLedgersByUid(c core.Context, uid int64, visibleOnly bool) ([]*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=?"
+	conditionParams := []any{uid, false}
+
+	if visibleOnly {
+		condition = condition + " AND hidden=?"
+		conditionParams = append(conditionParams, false)
+	}
+
+	var ledgers []*models.Ledger
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("display_order asc").Find(&ledgers)
+
+	return ledgers, err
+}
+
+// GetLedgerByLedgerId returns a ledger model according to ledger id
+func (s *LedgerService) GetLedgerByLedgerId(c core.Context, uid int64, ledgerId int64) (*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return ledger, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *LedgerService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(ledger)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return ledger.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateLedger saves a new ledger model to database
+func (s *LedgerService) CreateLedger(c core.Context, ledger *models.Ledger) error {
+	if ledger.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	ledger.LedgerId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER)
+
+	if ledger.LedgerId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	ledger.Deleted = false
+	ledger.CreatedUnixTime = time.Now().Unix()
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(ledger.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(ledger)
+		return err
+	})
+}
+
+// ModifyLedger saves an existed ledger model to database
+func (s *LedgerService) ModifyLedger(c core.Context, ledger *models.Ledger) error {
+	if ledger.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, ledger.Uid, ledger.LedgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(ledger.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(ledger.LedgerId).Cols("name", "comment", "updated_unix_time").Where("uid=? AND deleted=?", ledger.Uid, false).Update(ledger)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return err
+	})
+}
+
+// UpdateLedgerTransactionLockTime updates whether the ledger overrides the transaction lock date of user and the transaction lock date of the ledger
+func (s *LedgerService) UpdateLedgerTransactionLockTime(c core.Context, uid int64, ledgerId int64, overridden bool, lockTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	if !overridden {
+		lockTime = 0
+	}
+
+	updateModel := &models.Ledger{
+		TransactionLockOverridden: overridden,
+		TransactionLockTime:       lockTime,
+		UpdatedUnixTime:           time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(ledgerId).Cols("transaction_lock_overridden", "transaction_lock_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return nil
+	})
+}
+
+// HideLedger updates hidden field of given ledger ids
+func (s *LedgerService) HideLedger(c core.Context, uid int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Ledger{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("ledger_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyLedgerDisplayOrders updates display order of given ledgers
+func (s *LedgerService) ModifyLedgerDisplayOrders(c core.Context, uid int64, ledgers []*models.Ledger) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(ledgers); i++ {
+		ledgers[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(ledgers); i++ {
+			ledger := ledgers[i]
+			updatedRows, err := sess.ID(ledger.LedgerId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(ledger)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrLedgerNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteLedger deletes an existed ledger from database, the ledger must not contain any data
+func (s *LedgerService) DeleteLedger(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Ledger{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	ledgerDataBeans := []any{
+		&models.Account{},
+		&models.TransactionCategory{},
+		&models.TransactionTag{},
+		&models.TransactionTagGroup{},
+		&models.TransactionTemplate{},
+		&models.InsightsExplorer{},
+		&models.Budget{},
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(ledgerDataBeans); i++ {
+			exists, err := sess.Cols("uid", "ledger_id", "deleted").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Limit(1).Exist(ledgerDataBeans[i])
+
+			if err != nil {
+				return err
+			} else if exists {
+				return errs.ErrLedgerInUseCannotBeDeleted
+			}
+		}
+
+		deletedRows, err := sess.ID(ledgerId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		memberUpdateModel := &models.LedgerMember{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(memberUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=? AND ledger_id=?", uid, ledgerId).Delete(&models.NetWorthSnapshot{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=? AND ledger_id=?", uid, ledgerId).Delete(&models.NetWorthSnapshotState{})
+
+		return err
+	})
+}
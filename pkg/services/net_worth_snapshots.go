@@ -0,0 +1,308 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const pageCountForBuildNetWorthSnapshots = 1000
+const maxNetWorthSnapshotsCountPerInsert = 500
+
+// NetWorthSnapshotAmountConverterGetter returns a converter which converts the amounts of all accounts in the specified ledger into the default currency of user
+type NetWorthSnapshotAmountConverterGetter func(c core.Context, uid int64, ledgerId int64) (models.TransactionAmountConverter, error)
+
+// NetWorthSnapshotService represents monthly net worth snapshot service
+type NetWorthSnapshotService struct {
+	ServiceUsingDB
+}
+
+// Initialize a monthly net worth snapshot service singleton instance
+var (
+	NetWorthSnapshots = &NetWorthSnapshotService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetSnapshotsByYearMonthRange returns the net worth snapshot models of the ledger between the start and end numeric year month (yyyyMM), zero means no limit
+func (s *NetWorthSnapshotService) GetSnapshotsByYearMonthRange(c core.Context, uid int64, ledgerId int64, startYearMonth int32, endYearMonth int32) ([]*models.NetWorthSnapshot, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND ledger_id=?"
+	conditionParams := []any{uid, ledgerId}
+
+	if startYearMonth > 0 {
+		condition = condition + " AND year_month>=?"
+		conditionParams = append(conditionParams, startYearMonth)
+	}
+
+	if endYearMonth > 0 {
+		condition = condition + " AND year_month<=?"
+		conditionParams = append(conditionParams, endYearMonth)
+	}
+
+	var snapshots []*models.NetWorthSnapshot
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("year_month asc").Find(&snapshots)
+
+	return snapshots, err
+}
+
+// UpdateSnapshots builds the missing net worth snapshots of the ledger up to the end numeric year month (yyyyMM), all snapshots are rebuilt if the default currency of user is changed
+func (s *NetWorthSnapshotService) UpdateSnapshots(c core.Context, uid int64, ledgerId int64, endYearMonth int32, getAmountConverter NetWorthSnapshotAmountConverterGetter) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	user, err := Users.GetUserById(c, uid)
+
+	if err != nil {
+		return err
+	}
+
+	buildStartUnixTime := time.Now().Unix()
+	state := &models.NetWorthSnapshotState{}
+	stateExists, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=?", uid, ledgerId).Get(state)
+
+	if err != nil {
+		return err
+	}
+
+	startYearMonth := int32(0)
+
+	if stateExists && state.DefaultCurrency == user.DefaultCurrency {
+		if state.LastYearMonth >= endYearMonth {
+			return nil
+		}
+
+		startYearMonth = utils.AddMonthsToNumericYearMonth(state.LastYearMonth, 1)
+	}
+
+	amountConverter, err := getAmountConverter(c, uid, ledgerId)
+
+	if err != nil {
+		return err
+	}
+
+	var accounts []*models.Account
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=?", uid, ledgerId).Find(&accounts)
+
+	if err != nil {
+		return err
+	}
+
+	accountMap := make(map[int64]*models.Account, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountMap[accounts[i].AccountId] = accounts[i]
+	}
+
+	transactions, err := s.getAllTransactionAmounts(c, uid, ledgerId)
+
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := models.BuildNetWorthSnapshots(uid, ledgerId, transactions, accountMap, startYearMonth, endYearMonth, user.DefaultCurrency, amountConverter)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	for i := 0; i < len(snapshots); i++ {
+		snapshots[i].CreatedUnixTime = now
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		currentState := &models.NetWorthSnapshotState{}
+		has, err := sess.Where("uid=? AND ledger_id=?", uid, ledgerId).Get(currentState)
+
+		if err != nil {
+			return err
+		}
+
+		if has && currentState.UpdatedUnixTime >= buildStartUnixTime {
+			log.Warnf(c, "[net_worth_snapshots.UpdateSnapshots] net worth snapshots of ledger \"id:%d\" for user \"uid:%d\" are changed during building, the new snapshots would be built next time", ledgerId, uid)
+			return nil
+		}
+
+		_, err = sess.Where("uid=? AND ledger_id=? AND year_month>=?", uid, ledgerId, startYearMonth).Delete(&models.NetWorthSnapshot{})
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(snapshots); i += maxNetWorthSnapshotsCountPerInsert {
+			endIndex := i + maxNetWorthSnapshotsCountPerInsert
+
+			if endIndex > len(snapshots) {
+				endIndex = len(snapshots)
+			}
+
+			_, err = sess.Insert(snapshots[i:endIndex])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		newState := &models.NetWorthSnapshotState{
+			Uid:             uid,
+			LedgerId:        ledgerId,
+			LastYearMonth:   endYearMonth,
+			DefaultCurrency: user.DefaultCurrency,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		}
+
+		if !has {
+			_, err = sess.Insert(newState)
+		} else {
+			_, err = sess.Cols("last_year_month", "default_currency", "updated_unix_time").Where("uid=? AND ledger_id=?", uid, ledgerId).Update(newState)
+		}
+
+		return err
+	})
+}
+
+// UpdateAllSnapshots builds the missing net worth snapshots up to the end numeric year month (yyyyMM) of all ledgers which have net worth snapshots
+func (s *NetWorthSnapshotService) UpdateAllSnapshots(c core.Context, endYearMonth int32, getAmountConverter NetWorthSnapshotAmountConverterGetter) error {
+	var allStates []*models.NetWorthSnapshotState
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var states []*models.NetWorthSnapshotState
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("last_year_month<?", endYearMonth).Find(&states)
+
+		if err != nil {
+			return err
+		}
+
+		allStates = append(allStates, states...)
+	}
+
+	if len(allStates) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[net_worth_snapshots.UpdateAllSnapshots] should update net worth snapshots of %d ledgers now", len(allStates))
+
+	successCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allStates); i++ {
+		state := allStates[i]
+		err := s.UpdateSnapshots(c, state.Uid, state.LedgerId, endYearMonth, getAmountConverter)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[net_worth_snapshots.UpdateAllSnapshots] failed to update net worth snapshots of ledger \"id:%d\" for user \"uid:%d\", because %s", state.LedgerId, state.Uid, err.Error())
+			continue
+		}
+
+		successCount++
+	}
+
+	log.Infof(c, "[net_worth_snapshots.UpdateAllSnapshots] %d ledgers updated successfully, %d failed", successCount, failedCount)
+
+	return nil
+}
+
+func (s *NetWorthSnapshotService) getAllTransactionAmounts(c core.Context, uid int64, ledgerId int64) ([]*models.Transaction, error) {
+	var allTransactions []*models.Transaction
+	maxTransactionTime := int64(-1)
+
+	for {
+		var transactions []*models.Transaction
+		condition := "uid=? AND ledger_id=? AND deleted=?"
+		conditionParams := []any{uid, ledgerId, false}
+
+		if maxTransactionTime >= 0 {
+			condition = condition + " AND transaction_time<=?"
+			conditionParams = append(conditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, account_id, transaction_time, timezone_utc_offset, amount, related_account_amount").Where(condition, conditionParams...).Limit(pageCountForBuildNetWorthSnapshots, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForBuildNetWorthSnapshots {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return allTransactions, nil
+}
+
+// invalidateSnapshots removes the net worth snapshots of the ledger since the specified numeric year month (yyyyMM) in the session, so that they would be rebuilt later
+func (s *NetWorthSnapshotService) invalidateSnapshots(sess *xorm.Session, uid int64, ledgerId int64, yearMonth int32, now int64) error {
+	_, err := sess.Where("uid=? AND ledger_id=? AND year_month>=?", uid, ledgerId, yearMonth).Delete(&models.NetWorthSnapshot{})
+
+	if err != nil {
+		return err
+	}
+
+	stateUpdateModel := &models.NetWorthSnapshotState{
+		LastYearMonth:   0,
+		UpdatedUnixTime: now,
+	}
+
+	if yearMonth > 0 {
+		stateUpdateModel.LastYearMonth = utils.AddMonthsToNumericYearMonth(yearMonth, -1)
+	}
+
+	_, err = sess.Cols("last_year_month", "updated_unix_time").Where("uid=? AND ledger_id=? AND last_year_month>=?", uid, ledgerId, yearMonth).Update(stateUpdateModel)
+
+	if err != nil {
+		return err
+	}
+
+	// mark the snapshots which are being built as outdated
+	_, err = sess.Cols("updated_unix_time").Where("uid=? AND ledger_id=? AND last_year_month<?", uid, ledgerId, yearMonth).Update(stateUpdateModel)
+
+	return err
+}
+
+// invalidateSnapshotsByTransactions removes the net worth snapshots of the ledgers since the earliest month of the specified transactions in each ledger in the session
+func (s *NetWorthSnapshotService) invalidateSnapshotsByTransactions(sess *xorm.Session, uid int64, transactions []*models.Transaction, now int64) error {
+	ledgerMinYearMonths := make(map[int64]int32)
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i] == nil {
+			continue
+		}
+
+		yearMonth := models.GetNetWorthSnapshotYearMonth(transactions[i].TransactionTime, transactions[i].TimezoneUtcOffset)
+
+		if minYearMonth, exists := ledgerMinYearMonths[transactions[i].LedgerId]; !exists || yearMonth < minYearMonth {
+			ledgerMinYearMonths[transactions[i].LedgerId] = yearMonth
+		}
+	}
+
+	for ledgerId, minYearMonth := range ledgerMinYearMonths {
+		err := s.invalidateSnapshots(sess, uid, ledgerId, minYearMonth, now)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// PayeeService represents payee service
+type PayeeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a payee service singleton instance
+var (
+	Payees = &PayeeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllPayeesByUid returns all payee models of user
+func (s *PayeeService) GetAllPayeesByUid(c core.Context, uid int64, ledgerId int64) ([]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var payees []*models.Payee
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&payees)
+
+	return payees, err
+}
+
+// GetPayeeByPayeeId returns a payee model according to payee id
+func (s *PayeeService) GetPayeeByPayeeId(c core.Context, uid int64, ledgerId int64, payeeId int64) (*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if payeeId <= 0 {
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	payee := &models.Payee{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(payeeId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(payee)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrPayeeNotFound
+	}
+
+	return payee, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *PayeeService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	payee := &models.Payee{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("display_order desc").Limit(1).Get(payee)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return payee.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreatePayee saves a new payee model to database
+func (s *PayeeService) CreatePayee(c core.Context, payee *models.Payee) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, payee.Uid, payee.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.ExistsPayeeName(c, payee.Uid, payee.LedgerId, payee.Name)
+
+	if err != nil {
+		return err
+	} else if exists {
+		return errs.ErrPayeeNameAlreadyExists
+	}
+
+	payee.PayeeId = s.GenerateUuid(uuid.UUID_TYPE_PAYEE)
+
+	if payee.PayeeId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	payee.Deleted = false
+	payee.CreatedUnixTime = time.Now().Unix()
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(payee)
+		return err
+	})
+}
+
+// CreatePayees saves a few payee models to database
+func (s *PayeeService) CreatePayees(c core.Context, uid int64, ledgerId int64, payees []*models.Payee, skipExists bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	allPayeeNames := make([]string, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		allPayeeNames[i] = payees[i].Name
+	}
+
+	var existPayees []*models.Payee
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("name", allPayeeNames).Find(&existPayees)
+
+	if err != nil {
+		return err
+	} else if !skipExists && len(existPayees) > 0 {
+		return errs.ErrPayeeNameAlreadyExists
+	}
+
+	existsNamePayeeMap := make(map[string]*models.Payee, len(existPayees))
+
+	for i := 0; i < len(existPayees); i++ {
+		payee := existPayees[i]
+		existsNamePayeeMap[payee.Name] = payee
+	}
+
+	newPayees := make([]*models.Payee, 0, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+		existsPayee, exists := existsNamePayeeMap[payee.Name]
+
+		if exists {
+			payee.FillFromOtherPayee(existsPayee)
+			continue
+		}
+
+		newPayees = append(newPayees, payee)
+		existsNamePayeeMap[payee.Name] = payee
+	}
+
+	payeeUuids := s.GenerateUuids(uuid.UUID_TYPE_PAYEE, uint16(len(newPayees)))
+
+	if len(payeeUuids) < len(newPayees) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(newPayees); i++ {
+		payee := newPayees[i]
+		payee.PayeeId = payeeUuids[i]
+		payee.Uid = uid
+		payee.LedgerId = ledgerId
+		payee.Deleted = false
+		payee.CreatedUnixTime = time.Now().Unix()
+		payee.UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(newPayees); i++ {
+			payee := newPayees[i]
+			_, err := sess.Insert(payee)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ModifyPayee saves an existed payee model to database
+func (s *PayeeService) ModifyPayee(c core.Context, payee *models.Payee, payeeNameChanged bool) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, payee.Uid, payee.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	if payeeNameChanged {
+		exists, err := s.ExistsPayeeName(c, payee.Uid, payee.LedgerId, payee.Name)
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeNameAlreadyExists
+		}
+	}
+
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(payee.PayeeId).Cols("name", "aliases", "default_category_id", "default_tag_ids", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", payee.Uid, payee.LedgerId, false).Update(payee)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// HidePayee updates hidden field of given payees
+func (s *PayeeService) HidePayee(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("payee_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyPayeeDisplayOrders updates display order of given payees
+func (s *PayeeService) ModifyPayeeDisplayOrders(c core.Context, uid int64, ledgerId int64, payees []*models.Payee) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(payees); i++ {
+		payees[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(payees); i++ {
+			payee := payees[i]
+			updatedRows, err := sess.ID(payee.PayeeId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(payee)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrPayeeNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeletePayee deletes an existed payee from database
+func (s *PayeeService) DeletePayee(c core.Context, uid int64, ledgerId int64, payeeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "ledger_id", "deleted", "payee_id").Where("uid=? AND ledger_id=? AND deleted=? AND payee_id=?", uid, ledgerId, false, payeeId).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(payeeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllPayees deletes all existed payees from database
+func (s *PayeeService) DeleteAllPayees(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "ledger_id", "deleted", "payee_id").Where("uid=? AND ledger_id=? AND deleted=? AND payee_id<>?", uid, ledgerId, false, 0).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeInUseCannotBeDeleted
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ExistsPayeeName returns whether the given payee name exists
+func (s *PayeeService) ExistsPayeeName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
+	if name == "" {
+		return false, errs.ErrPayeeNameIsEmpty
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND ledger_id=? AND deleted=? AND name=?", uid, ledgerId, false, name).Exist(&models.Payee{})
+}
+
+// GetPayeeMapByList returns a payee map by a list
+func (s *PayeeService) GetPayeeMapByList(payees []*models.Payee) map[int64]*models.Payee {
+	payeeMap := make(map[int64]*models.Payee)
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+		payeeMap[payee.PayeeId] = payee
+	}
+
+	return payeeMap
+}
+
+// GetPayeeByCounterpartyName returns the first visible payee whose name or alias matches the given counterparty name, or nil if no payee matches
+func (s *PayeeService) GetPayeeByCounterpartyName(payees []*models.Payee, counterpartyName string) *models.Payee {
+	if counterpartyName == "" {
+		return nil
+	}
+
+	var aliasMatchedPayee *models.Payee
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+
+		if payee.Hidden {
+			continue
+		}
+
+		if payee.Name == counterpartyName {
+			return payee
+		}
+
+		if aliasMatchedPayee == nil && payee.IsNameOrAliasMatch(counterpartyName) {
+			aliasMatchedPayee = payee
+		}
+	}
+
+	return aliasMatchedPayee
+}
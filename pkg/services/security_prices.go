@@ -0,0 +1,150 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// SecurityPriceService represents user custom security price data service
+type SecurityPriceService struct {
+	ServiceUsingDB
+}
+
+// Initialize a user custom security price data service singleton instance
+var (
+	SecurityPrices = &SecurityPriceService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetAllPricesByUid returns all security price data models of user in ascending order of symbol and price date
+func (s *SecurityPriceService) GetAllPricesByUid(c core.Context, uid int64) ([]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var securityPrices []*models.SecurityPrice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted_unix_time=?", uid, 0).OrderBy("symbol asc, price_date asc").Find(&securityPrices)
+
+	return securityPrices, err
+}
+
+// GetAllPricesBySymbol returns all security price data models of the specified symbol of user
+func (s *SecurityPriceService) GetAllPricesBySymbol(c core.Context, uid int64, symbol string) ([]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var securityPrices []*models.SecurityPrice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted_unix_time=? AND symbol=?", uid, 0, symbol).OrderBy("price_date desc").Find(&securityPrices)
+
+	return securityPrices, err
+}
+
+// GetLatestPricesByUid returns the latest security price data model of each symbol of user
+func (s *SecurityPriceService) GetLatestPricesByUid(c core.Context, uid int64) ([]*models.SecurityPrice, error) {
+	securityPrices, err := s.GetAllPricesByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	latestPrices := make([]*models.SecurityPrice, 0, len(securityPrices))
+
+	for i := 0; i < len(securityPrices); i++ {
+		if i+1 < len(securityPrices) && securityPrices[i+1].Symbol == securityPrices[i].Symbol {
+			continue
+		}
+
+		latestPrices = append(latestPrices, securityPrices[i])
+	}
+
+	return latestPrices, nil
+}
+
+// UpdatePrice updates user security price data model of the specified date to database
+func (s *SecurityPriceService) UpdatePrice(c core.Context, uid int64, symbol string, priceDate int32, price int64) (*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	newSecurityPrice := &models.SecurityPrice{
+		Uid:             uid,
+		DeletedUnixTime: 0,
+		Symbol:          symbol,
+		PriceDate:       priceDate,
+		Price:           price,
+		CreatedUnixTime: now,
+		UpdatedUnixTime: now,
+	}
+
+	updateOldPriceModel := &models.SecurityPrice{
+		DeletedUnixTime: now,
+	}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=? AND symbol=? AND price_date=?", uid, 0, symbol, priceDate).Update(updateOldPriceModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(newSecurityPrice)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newSecurityPrice, nil
+}
+
+// DeletePrice deletes an existed user security price data of the specified date from database
+func (s *SecurityPriceService) DeletePrice(c core.Context, uid int64, symbol string, priceDate int32) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.SecurityPrice{
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=? AND symbol=? AND price_date=?", uid, 0, symbol, priceDate).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrSecurityPriceNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllPrices deletes all existed user security price data from database
+func (s *SecurityPriceService) DeleteAllPrices(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.SecurityPrice{
+		DeletedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=?", uid, 0).Update(updateModel)
+		return err
+	})
+}
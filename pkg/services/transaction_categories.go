@@ -33,25 +33,26 @@ var (
 )
 
 // GetTotalCategoryCountByUid returns total category count of user
-func (s *TransactionCategoryService) GetTotalCategoryCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *TransactionCategoryService) GetTotalCategoryCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.TransactionCategory{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.TransactionCategory{})
 
 	return count, err
 }
 
 // GetAllCategoriesByUid returns all transaction category models of user
-func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid int64, categoryType models.TransactionCategoryType, parentCategoryId int64) ([]*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid int64, ledgerId int64, categoryType models.TransactionCategoryType, parentCategoryId int64) ([]*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
-	condition := "uid=? AND deleted=?"
+	condition := "uid=? AND ledger_id=? AND deleted=?"
 	conditionParams := make([]any, 0, 8)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 
 	if categoryType > 0 {
@@ -71,7 +72,7 @@ func (s *TransactionCategoryService) GetAllCategoriesByUid(c core.Context, uid i
 }
 
 // GetSubCategoriesByCategoryIds returns sub-category models according to category ids
-func (s *TransactionCategoryService) GetSubCategoriesByCategoryIds(c core.Context, uid int64, categoryIds []int64) ([]*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) GetSubCategoriesByCategoryIds(c core.Context, uid int64, ledgerId int64, categoryIds []int64) ([]*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -80,9 +81,10 @@ func (s *TransactionCategoryService) GetSubCategoriesByCategoryIds(c core.Contex
 		return nil, errs.ErrTransactionCategoryIdInvalid
 	}
 
-	condition := "uid=? AND deleted=?"
-	conditionParams := make([]any, 0, len(categoryIds)+2)
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := make([]any, 0, len(categoryIds)+3)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 
 	var categoryIdConditions strings.Builder
@@ -113,7 +115,7 @@ func (s *TransactionCategoryService) GetSubCategoriesByCategoryIds(c core.Contex
 }
 
 // GetCategoryByCategoryId returns a transaction category model according to transaction category id
-func (s *TransactionCategoryService) GetCategoryByCategoryId(c core.Context, uid int64, categoryId int64) (*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) GetCategoryByCategoryId(c core.Context, uid int64, ledgerId int64, categoryId int64) (*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -123,7 +125,7 @@ func (s *TransactionCategoryService) GetCategoryByCategoryId(c core.Context, uid
 	}
 
 	category := &models.TransactionCategory{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(categoryId).Where("uid=? AND deleted=?", uid, false).Get(category)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(categoryId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(category)
 
 	if err != nil {
 		return nil, err
@@ -135,7 +137,7 @@ func (s *TransactionCategoryService) GetCategoryByCategoryId(c core.Context, uid
 }
 
 // GetCategoriesByCategoryIds returns transaction category models according to transaction category ids
-func (s *TransactionCategoryService) GetCategoriesByCategoryIds(c core.Context, uid int64, categoryIds []int64) (map[int64]*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) GetCategoriesByCategoryIds(c core.Context, uid int64, ledgerId int64, categoryIds []int64) (map[int64]*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -145,7 +147,7 @@ func (s *TransactionCategoryService) GetCategoriesByCategoryIds(c core.Context,
 	}
 
 	var categories []*models.TransactionCategory
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("category_id", categoryIds).Find(&categories)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("category_id", categoryIds).Find(&categories)
 
 	if err != nil {
 		return nil, err
@@ -156,13 +158,13 @@ func (s *TransactionCategoryService) GetCategoriesByCategoryIds(c core.Context,
 }
 
 // GetMaxDisplayOrder returns the max display order according to transaction category type
-func (s *TransactionCategoryService) GetMaxDisplayOrder(c core.Context, uid int64, categoryType models.TransactionCategoryType) (int32, error) {
+func (s *TransactionCategoryService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64, categoryType models.TransactionCategoryType) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	category := &models.TransactionCategory{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "parent_category_id", "display_order").Where("uid=? AND deleted=? AND type=? AND parent_category_id=?", uid, false, categoryType, models.LevelOneTransactionCategoryParentId).OrderBy("display_order desc").Limit(1).Get(category)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "parent_category_id", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND type=? AND parent_category_id=?", uid, ledgerId, false, categoryType, models.LevelOneTransactionCategoryParentId).OrderBy("display_order desc").Limit(1).Get(category)
 
 	if err != nil {
 		return 0, err
@@ -176,7 +178,7 @@ func (s *TransactionCategoryService) GetMaxDisplayOrder(c core.Context, uid int6
 }
 
 // GetMaxSubCategoryDisplayOrder returns the max display order of sub transaction category according to transaction category type and parent transaction category id
-func (s *TransactionCategoryService) GetMaxSubCategoryDisplayOrder(c core.Context, uid int64, categoryType models.TransactionCategoryType, parentCategoryId int64) (int32, error) {
+func (s *TransactionCategoryService) GetMaxSubCategoryDisplayOrder(c core.Context, uid int64, ledgerId int64, categoryType models.TransactionCategoryType, parentCategoryId int64) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -186,7 +188,7 @@ func (s *TransactionCategoryService) GetMaxSubCategoryDisplayOrder(c core.Contex
 	}
 
 	category := &models.TransactionCategory{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "parent_category_id", "display_order").Where("uid=? AND deleted=? AND type=? AND parent_category_id=?", uid, false, categoryType, parentCategoryId).OrderBy("display_order desc").Limit(1).Get(category)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "parent_category_id", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND type=? AND parent_category_id=?", uid, ledgerId, false, categoryType, parentCategoryId).OrderBy("display_order desc").Limit(1).Get(category)
 
 	if err != nil {
 		return 0, err
@@ -205,6 +207,12 @@ func (s *TransactionCategoryService) CreateCategory(c core.Context, category *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, category.Uid, category.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	category.CategoryId = s.GenerateUuid(uuid.UUID_TYPE_CATEGORY)
 
 	if category.CategoryId < 1 {
@@ -222,11 +230,17 @@ func (s *TransactionCategoryService) CreateCategory(c core.Context, category *mo
 }
 
 // CreateCategories saves a few transaction category models to database
-func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64, categories map[*models.TransactionCategory][]*models.TransactionCategory) ([]*models.TransactionCategory, error) {
+func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64, ledgerId int64, categories map[*models.TransactionCategory][]*models.TransactionCategory) ([]*models.TransactionCategory, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var allCategories []*models.TransactionCategory
 	primaryCategories := categories[nil]
 
@@ -240,6 +254,7 @@ func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64,
 	for i := 0; i < len(primaryCategories); i++ {
 		primaryCategory := primaryCategories[i]
 		primaryCategory.CategoryId = primaryCategoryUuids[i]
+		primaryCategory.LedgerId = ledgerId
 		primaryCategory.Deleted = false
 		primaryCategory.CreatedUnixTime = time.Now().Unix()
 		primaryCategory.UpdatedUnixTime = time.Now().Unix()
@@ -259,6 +274,7 @@ func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64,
 			secondaryCategory := secondaryCategories[j]
 			secondaryCategory.CategoryId = secondaryCategoryUuids[j]
 			secondaryCategory.ParentCategoryId = primaryCategory.CategoryId
+			secondaryCategory.LedgerId = ledgerId
 			secondaryCategory.Deleted = false
 			secondaryCategory.CreatedUnixTime = time.Now().Unix()
 			secondaryCategory.UpdatedUnixTime = time.Now().Unix()
@@ -267,7 +283,7 @@ func (s *TransactionCategoryService) CreateCategories(c core.Context, uid int64,
 		}
 	}
 
-	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(allCategories); i++ {
 			category := allCategories[i]
 			_, err := sess.Insert(category)
@@ -293,10 +309,16 @@ func (s *TransactionCategoryService) ModifyCategory(c core.Context, category *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, category.Uid, category.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	category.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(category.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(category.CategoryId).Cols("parent_category_id", "name", "display_order", "icon", "color", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", category.Uid, false).Update(category)
+		updatedRows, err := sess.ID(category.CategoryId).Cols("parent_category_id", "name", "display_order", "icon", "color", "comment", "hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", category.Uid, category.LedgerId, false).Update(category)
 
 		if err != nil {
 			return err
@@ -309,11 +331,17 @@ func (s *TransactionCategoryService) ModifyCategory(c core.Context, category *mo
 }
 
 // HideCategory updates hidden field of given transaction categories
-func (s *TransactionCategoryService) HideCategory(c core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *TransactionCategoryService) HideCategory(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionCategory{
@@ -322,7 +350,7 @@ func (s *TransactionCategoryService) HideCategory(c core.Context, uid int64, ids
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("category_id", ids).Update(updateModel)
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("category_id", ids).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -335,11 +363,17 @@ func (s *TransactionCategoryService) HideCategory(c core.Context, uid int64, ids
 }
 
 // ModifyCategoryDisplayOrders updates display order of given transaction categories
-func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c core.Context, uid int64, categories []*models.TransactionCategory) error {
+func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c core.Context, uid int64, ledgerId int64, categories []*models.TransactionCategory) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(categories); i++ {
 		categories[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -347,7 +381,7 @@ func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c core.Context,
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(categories); i++ {
 			category := categories[i]
-			updatedRows, err := sess.ID(category.CategoryId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(category)
+			updatedRows, err := sess.ID(category.CategoryId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(category)
 
 			if err != nil {
 				return err
@@ -361,11 +395,17 @@ func (s *TransactionCategoryService) ModifyCategoryDisplayOrders(c core.Context,
 }
 
 // DeleteCategory deletes an existed transaction category from database
-func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, categoryId int64) error {
+func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, ledgerId int64, categoryId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionCategory{
@@ -375,7 +415,7 @@ func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, c
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		var categoryAndSubCategories []*models.TransactionCategory
-		err := sess.Where("uid=? AND deleted=? AND (category_id=? OR parent_category_id=?)", uid, false, categoryId, categoryId).Find(&categoryAndSubCategories)
+		err := sess.Where("uid=? AND ledger_id=? AND deleted=? AND (category_id=? OR parent_category_id=?)", uid, ledgerId, false, categoryId, categoryId).Find(&categoryAndSubCategories)
 
 		if err != nil {
 			return err
@@ -397,6 +437,14 @@ func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, c
 			return errs.ErrTransactionCategoryInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "deleted", "category_id").Where("uid=? AND deleted=?", uid, false).In("category_id", categoryAndSubCategoryIds).Limit(1).Exist(&models.TransactionSplit{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionCategoryInUseCannotBeDeleted
+		}
+
 		exists, err = sess.Cols("uid", "deleted", "category_id", "template_type", "scheduled_frequency_type", "scheduled_end_time").Where("uid=? AND deleted=? AND (template_type=? OR (template_type=? AND scheduled_frequency_type<>? AND (scheduled_end_time IS NULL OR scheduled_end_time>=?)))", uid, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, now).In("category_id", categoryAndSubCategoryIds).Limit(1).Exist(&models.TransactionTemplate{})
 
 		if err != nil {
@@ -418,11 +466,17 @@ func (s *TransactionCategoryService) DeleteCategory(c core.Context, uid int64, c
 }
 
 // DeleteAllCategories deletes all existed transaction categories from database
-func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int64) error {
+func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int64, ledgerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionCategory{
@@ -431,7 +485,7 @@ func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		exists, err := sess.Cols("uid", "deleted", "category_id").Where("uid=? AND deleted=? AND category_id<>?", uid, false, 0).Limit(1).Exist(&models.Transaction{})
+		exists, err := sess.Cols("uid", "ledger_id", "deleted", "category_id").Where("uid=? AND ledger_id=? AND deleted=? AND category_id<>?", uid, ledgerId, false, 0).Limit(1).Exist(&models.Transaction{})
 
 		if err != nil {
 			return err
@@ -439,16 +493,135 @@ func (s *TransactionCategoryService) DeleteAllCategories(c core.Context, uid int
 			return errs.ErrTransactionCategoryInUseCannotBeDeleted
 		}
 
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetAllDeletedCategoriesByUid returns all deleted transaction category models in the trash bin of user
+func (s *TransactionCategoryService) GetAllDeletedCategoriesByUid(c core.Context, uid int64, ledgerId int64) ([]*models.TransactionCategory, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var categories []*models.TransactionCategory
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).OrderBy("deleted_unix_time desc, type asc, parent_category_id asc, display_order asc").Find(&categories)
+
+	return categories, err
+}
+
+// RestoreCategory restores a deleted transaction category and the sub-categories deleted along with it from the trash bin
+func (s *TransactionCategoryService) RestoreCategory(c core.Context, uid int64, ledgerId int64, categoryId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if categoryId <= 0 {
+		return errs.ErrTransactionCategoryIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	updateModel := &models.TransactionCategory{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(categoryId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		categoryIds := []int64{category.CategoryId}
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			exists, err := sess.Cols("category_id", "uid", "ledger_id", "deleted").Where("category_id=? AND uid=? AND ledger_id=? AND deleted=?", category.ParentCategoryId, uid, ledgerId, false).Exist(&models.TransactionCategory{})
+
+			if err != nil {
+				return err
+			} else if !exists {
+				return errs.ErrParentTransactionCategoryNotFound
+			}
+		} else {
+			var subCategories []*models.TransactionCategory
+			err = sess.Cols("category_id").Where("uid=? AND ledger_id=? AND deleted=? AND parent_category_id=? AND deleted_unix_time=?", uid, ledgerId, true, category.CategoryId, category.DeletedUnixTime).Find(&subCategories)
+
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < len(subCategories); i++ {
+				categoryIds = append(categoryIds, subCategories[i].CategoryId)
+			}
+		}
+
+		restoredRows, err := sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).In("category_id", categoryIds).Update(updateModel)
 
 		if err != nil {
 			return err
+		} else if restoredRows < 1 {
+			return errs.ErrTransactionCategoryNotFound
 		}
 
 		return nil
 	})
 }
 
+// PurgeCategory permanently deletes a deleted transaction category and its deleted sub-categories from the trash bin
+func (s *TransactionCategoryService) PurgeCategory(c core.Context, uid int64, ledgerId int64, categoryId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if categoryId <= 0 {
+		return errs.ErrTransactionCategoryIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		category := &models.TransactionCategory{}
+		has, err := sess.ID(categoryId).Cols("category_id", "parent_category_id").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(category)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionCategoryNotFound
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			_, err = sess.Where("uid=? AND ledger_id=? AND deleted=? AND parent_category_id=?", uid, ledgerId, true, category.CategoryId).Delete(&models.TransactionCategory{})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.ID(category.CategoryId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Delete(&models.TransactionCategory{})
+
+		return err
+	})
+}
+
 // GetCategoryMapByList returns a transaction category map by a list
 func (s *TransactionCategoryService) GetCategoryMapByList(categories []*models.TransactionCategory) map[int64]*models.TransactionCategory {
 	categoryMap := make(map[int64]*models.TransactionCategory)
@@ -534,7 +707,7 @@ func (s *TransactionCategoryService) GetCategoryNames(categories []*models.Trans
 }
 
 // GetCategoryOrSubCategoryIds returns all category ids and sub-category ids according to given category ids
-func (s *TransactionCategoryService) GetCategoryOrSubCategoryIds(c core.Context, categoryIds string, uid int64) ([]int64, error) {
+func (s *TransactionCategoryService) GetCategoryOrSubCategoryIds(c core.Context, categoryIds string, uid int64, ledgerId int64) ([]int64, error) {
 	if categoryIds == "" || categoryIds == "0" {
 		return nil, nil
 	}
@@ -548,7 +721,7 @@ func (s *TransactionCategoryService) GetCategoryOrSubCategoryIds(c core.Context,
 	var allCategoryIds []int64
 
 	if len(requestCategoryIds) > 0 {
-		allSubCategories, err := s.GetSubCategoriesByCategoryIds(c, uid, requestCategoryIds)
+		allSubCategories, err := s.GetSubCategoriesByCategoryIds(c, uid, ledgerId, requestCategoryIds)
 
 		if err != nil {
 			return nil, err
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// TransactionHistoryService represents transaction history service
+type TransactionHistoryService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction history service singleton instance
+var (
+	TransactionHistories = &TransactionHistoryService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetHistoriesByTransactionId returns all history models of the specified transaction, the latest one is the first
+func (s *TransactionHistoryService) GetHistoriesByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionHistory, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var histories []*models.TransactionHistory
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND transaction_id=?", uid, transactionId).OrderBy("created_unix_time desc, history_id desc").Find(&histories)
+
+	return histories, err
+}
@@ -203,6 +203,12 @@ func (s *TransactionPictureService) UploadPicture(c core.Context, pictureInfo *m
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, pictureInfo.Uid, pictureInfo.LedgerId, core.LEDGER_PERMISSION_ADD_TRANSACTION)
+
+	if err != nil {
+		return err
+	}
+
 	defer pictureFile.Close()
 
 	pictureInfo.PictureId = s.GenerateUuid(uuid.UUID_TYPE_USER)
@@ -216,7 +222,7 @@ func (s *TransactionPictureService) UploadPicture(c core.Context, pictureInfo *m
 	pictureInfo.CreatedUnixTime = time.Now().Unix()
 	pictureInfo.UpdatedUnixTime = time.Now().Unix()
 
-	err := s.SaveTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureFile, pictureInfo.PictureExtension)
+	err = s.SaveTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureFile, pictureInfo.PictureExtension)
 
 	if err != nil {
 		return err
@@ -228,8 +234,8 @@ func (s *TransactionPictureService) UploadPicture(c core.Context, pictureInfo *m
 	})
 }
 
-// RemoveUnusedTransactionPicture removes the unused transaction picture of specified user
-func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Context, uid int64, pictureId int64) error {
+// RemoveUnusedTransactionPicture removes the unused transaction picture of specified user and ledger
+func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Context, uid int64, ledgerId int64, pictureId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -238,6 +244,12 @@ func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Contex
 		return errs.ErrTransactionPictureIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_ADD_TRANSACTION)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionPictureInfo{
@@ -246,7 +258,7 @@ func (s *TransactionPictureService) RemoveUnusedTransactionPicture(c core.Contex
 	}
 
 	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
-		deletedRows, err := sess.ID(pictureId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, models.TransactionPictureNewPictureTransactionId).Update(updateModel)
+		deletedRows, err := sess.ID(pictureId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=? AND transaction_id=?", uid, ledgerId, false, models.TransactionPictureNewPictureTransactionId).Update(updateModel)
 
 		if err != nil {
 			return err
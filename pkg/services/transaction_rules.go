@@ -0,0 +1,470 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionRuleService represents transaction rule service
+type TransactionRuleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction rule service singleton instance
+var (
+	TransactionRules = &TransactionRuleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllRulesByUid returns all transaction rule models of user
+func (s *TransactionRuleService) GetAllRulesByUid(c core.Context, uid int64, ledgerId int64, enabledOnly bool) ([]*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND ledger_id=? AND deleted=?"
+	conditionParams := []any{uid, ledgerId, false}
+
+	if enabledOnly {
+		condition = condition + " AND disabled=?"
+		conditionParams = append(conditionParams, false)
+	}
+
+	var rules []*models.TransactionRule
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("display_order asc").Find(&rules)
+
+	return rules, err
+}
+
+// GetRulesByRuleIds returns transaction rule models according to transaction rule ids
+func (s *TransactionRuleService) GetRulesByRuleIds(c core.Context, uid int64, ledgerId int64, ruleIds []int64) ([]*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ruleIds == nil {
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	var rules []*models.TransactionRule
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("rule_id", ruleIds).OrderBy("display_order asc").Find(&rules)
+
+	if err != nil {
+		return nil, err
+	} else if len(rules) < len(utils.ToUniqueInt64Slice(ruleIds)) {
+		return nil, errs.ErrTransactionRuleNotFound
+	}
+
+	return rules, err
+}
+
+// GetRuleByRuleId returns a transaction rule model according to transaction rule id
+func (s *TransactionRuleService) GetRuleByRuleId(c core.Context, uid int64, ledgerId int64, ruleId int64) (*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ruleId <= 0 {
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ruleId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(rule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionRuleNotFound
+	}
+
+	return rule, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *TransactionRuleService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("display_order desc").Limit(1).Get(rule)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return rule.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateRule saves a new transaction rule model to database
+func (s *TransactionRuleService) CreateRule(c core.Context, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, rule.Uid, rule.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	rule.RuleId = s.GenerateUuid(uuid.UUID_TYPE_RULE)
+
+	if rule.RuleId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	rule.Deleted = false
+	rule.CreatedUnixTime = time.Now().Unix()
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(rule)
+		return err
+	})
+}
+
+// ModifyRule saves an existed transaction rule model to database
+func (s *TransactionRuleService) ModifyRule(c core.Context, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, rule.Uid, rule.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(rule.RuleId).Cols("name", "transaction_type", "comment_keyword", "comment_regex", "min_amount", "max_amount", "account_id", "counterparty_keyword", "original_category_name", "set_category_id", "add_tag_ids", "rewrite_comment", "set_hide_amount", "stop_processing", "disabled", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", rule.Uid, rule.LedgerId, false).Update(rule)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyRuleDisplayOrders updates display order of given transaction rules
+func (s *TransactionRuleService) ModifyRuleDisplayOrders(c core.Context, uid int64, ledgerId int64, rules []*models.TransactionRule) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rules[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(rules); i++ {
+			rule := rules[i]
+			updatedRows, err := sess.ID(rule.RuleId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(rule)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionRuleNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRule deletes an existed transaction rule from database
+func (s *TransactionRuleService) DeleteRule(c core.Context, uid int64, ledgerId int64, ruleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(ruleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllRules deletes all existed transaction rules from database
+func (s *TransactionRuleService) DeleteAllRules(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ApplyRulesToTargets applies the specified transaction rules (or all enabled transaction rules if rule ids is empty) of user to the given transaction data, the name of current category is used as original category name if it is not set, and returns the count of matched transaction data
+func (s *TransactionRuleService) ApplyRulesToTargets(c core.Context, uid int64, ledgerId int64, ruleIds []int64, targets []*models.TransactionRuleTarget) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if len(targets) < 1 {
+		return 0, nil
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return 0, err
+	}
+
+	rules, categoryMap, tagMap, err := s.GetRulesAndItemsForApplying(c, uid, ledgerId, ruleIds)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rules) < 1 {
+		return 0, nil
+	}
+
+	s.FillOriginalCategoryNames(categoryMap, targets)
+
+	return s.ApplyRules(rules, categoryMap, tagMap, targets), nil
+}
+
+// GetRulesAndItemsForApplying returns the specified transaction rules (or all enabled transaction rules if rule ids is empty) of user, and the category and tag maps which are needed when applying these rules
+func (s *TransactionRuleService) GetRulesAndItemsForApplying(c core.Context, uid int64, ledgerId int64, ruleIds []int64) ([]*models.TransactionRule, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, error) {
+	if uid <= 0 {
+		return nil, nil, nil, errs.ErrUserIdInvalid
+	}
+
+	var rules []*models.TransactionRule
+	var err error
+
+	if len(ruleIds) > 0 {
+		rules, err = s.GetRulesByRuleIds(c, uid, ledgerId, ruleIds)
+	} else {
+		rules, err = s.GetAllRulesByUid(c, uid, ledgerId, true)
+	}
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(rules) < 1 {
+		return rules, nil, nil, nil
+	}
+
+	var categories []*models.TransactionCategory
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&categories)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var tags []*models.TransactionTag
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&tags)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, len(categories))
+
+	for i := 0; i < len(categories); i++ {
+		categoryMap[categories[i].CategoryId] = categories[i]
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, len(tags))
+
+	for i := 0; i < len(tags); i++ {
+		tagMap[tags[i].TagId] = tags[i]
+	}
+
+	return rules, categoryMap, tagMap, nil
+}
+
+// FillOriginalCategoryNames sets the name of current category as original category name of the given transaction data if it is not set
+func (s *TransactionRuleService) FillOriginalCategoryNames(categoryMap map[int64]*models.TransactionCategory, targets []*models.TransactionRuleTarget) {
+	for i := 0; i < len(targets); i++ {
+		target := targets[i]
+
+		if target.OriginalCategoryName != "" {
+			continue
+		}
+
+		if category, exists := categoryMap[target.CategoryId]; exists {
+			target.OriginalCategoryName = category.Name
+		}
+	}
+}
+
+// ApplyRules applies the given transaction rules in order to the given transaction data, and returns the count of matched transaction data
+func (s *TransactionRuleService) ApplyRules(rules []*models.TransactionRule, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, targets []*models.TransactionRuleTarget) int {
+	commentRegexes := make(map[int64]*regexp.Regexp, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+
+		if rule.CommentRegex == "" {
+			continue
+		}
+
+		commentRegex, err := regexp.Compile(rule.CommentRegex)
+
+		if err == nil {
+			commentRegexes[rule.RuleId] = commentRegex
+		}
+	}
+
+	matchedCount := 0
+
+	for i := 0; i < len(targets); i++ {
+		target := targets[i]
+		matched := false
+
+		for j := 0; j < len(rules); j++ {
+			rule := rules[j]
+			commentRegex := commentRegexes[rule.RuleId]
+
+			if !rule.IsMatch(target, commentRegex) {
+				continue
+			}
+
+			matched = true
+			s.applyRuleActions(rule, commentRegex, categoryMap, tagMap, target)
+
+			if rule.StopProcessing {
+				break
+			}
+		}
+
+		if matched {
+			matchedCount++
+		}
+	}
+
+	return matchedCount
+}
+
+func (s *TransactionRuleService) applyRuleActions(rule *models.TransactionRule, commentRegex *regexp.Regexp, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, target *models.TransactionRuleTarget) {
+	if rule.SetCategoryId != 0 && target.HasSplits {
+		// the category of transaction with splits is determined by its splits, so it cannot be changed by rule
+		target.CategorySkipped = true
+	} else if rule.SetCategoryId != 0 {
+		category, exists := categoryMap[rule.SetCategoryId]
+
+		if exists && s.isCategoryAvailable(category, categoryMap, target.Type) {
+			target.CategoryId = category.CategoryId
+		}
+	}
+
+	addTagIds := rule.GetAddTagIds()
+	currentTagIds := utils.ToSet(target.TagIds)
+
+	for i := 0; i < len(addTagIds) && len(target.TagIds) < models.MaximumTagsCountOfTransaction; i++ {
+		tag, exists := tagMap[addTagIds[i]]
+
+		if !exists || tag.Hidden || currentTagIds[tag.TagId] {
+			continue
+		}
+
+		target.TagIds = append(target.TagIds, tag.TagId)
+		currentTagIds[tag.TagId] = true
+	}
+
+	if rule.RewriteComment != "" {
+		if commentRegex != nil {
+			target.Comment = commentRegex.ReplaceAllString(target.Comment, rule.RewriteComment)
+		} else {
+			target.Comment = rule.RewriteComment
+		}
+
+		target.Comment = strings.TrimSpace(target.Comment)
+	}
+
+	if rule.SetHideAmount {
+		target.HideAmount = true
+	}
+}
+
+func (s *TransactionRuleService) isCategoryAvailable(category *models.TransactionCategory, categoryMap map[int64]*models.TransactionCategory, transactionType models.TransactionType) bool {
+	if category.Hidden || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return false
+	}
+
+	parentCategory, exists := categoryMap[category.ParentCategoryId]
+
+	if !exists || parentCategory.Hidden {
+		return false
+	}
+
+	return (transactionType == models.TRANSACTION_TYPE_INCOME && category.Type == models.CATEGORY_TYPE_INCOME) ||
+		(transactionType == models.TRANSACTION_TYPE_EXPENSE && category.Type == models.CATEGORY_TYPE_EXPENSE) ||
+		(transactionType == models.TRANSACTION_TYPE_TRANSFER && category.Type == models.CATEGORY_TYPE_TRANSFER)
+}
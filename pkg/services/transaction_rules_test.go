@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func getTransactionRuleTestCategoryMap() map[int64]*models.TransactionCategory {
+	return map[int64]*models.TransactionCategory{
+		1001: {CategoryId: 1001, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		1002: {CategoryId: 1002, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1001},
+		1003: {CategoryId: 1003, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1001, Hidden: true},
+		2001: {CategoryId: 2001, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		2002: {CategoryId: 2002, Type: models.CATEGORY_TYPE_INCOME, ParentCategoryId: 2001},
+	}
+}
+
+func getTransactionRuleTestTagMap() map[int64]*models.TransactionTag {
+	return map[int64]*models.TransactionTag{
+		3001: {TagId: 3001, Name: "Tag1"},
+		3002: {TagId: 3002, Name: "Tag2"},
+		3003: {TagId: 3003, Name: "Tag3", Hidden: true},
+	}
+}
+
+func TestApplyRules_SetCategory(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentKeyword: "salary", SetCategoryId: 2002},
+		{RuleId: 2, CommentKeyword: "coffee", SetCategoryId: 1002},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 1009, Comment: "Coffee"},
+		{Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 1009, Comment: "Salary refund"},
+		{Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 1009, Comment: "Coffee", HasSplits: true},
+		{Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 1009, Comment: "Lunch"},
+	}
+
+	matchedCount := TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, 3, matchedCount)
+	assert.Equal(t, int64(1002), targets[0].CategoryId)
+	assert.Equal(t, int64(1009), targets[1].CategoryId)
+	assert.Equal(t, int64(1009), targets[2].CategoryId)
+	assert.Equal(t, int64(1009), targets[3].CategoryId)
+	assert.False(t, targets[0].CategorySkipped)
+	assert.True(t, targets[2].CategorySkipped)
+}
+
+func TestFillOriginalCategoryNames(t *testing.T) {
+	categoryMap := map[int64]*models.TransactionCategory{
+		1002: {CategoryId: 1002, Name: "Coffee"},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{CategoryId: 1002},
+		{CategoryId: 1002, OriginalCategoryName: "Drinks"},
+		{CategoryId: 1009},
+	}
+
+	TransactionRules.FillOriginalCategoryNames(categoryMap, targets)
+
+	assert.Equal(t, "Coffee", targets[0].OriginalCategoryName)
+	assert.Equal(t, "Drinks", targets[1].OriginalCategoryName)
+	assert.Equal(t, "", targets[2].OriginalCategoryName)
+}
+
+func TestApplyRules_SetHiddenCategory(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentKeyword: "coffee", SetCategoryId: 1003},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, CategoryId: 1009, Comment: "Coffee"},
+	}
+
+	TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, int64(1009), targets[0].CategoryId)
+}
+
+func TestApplyRules_AddTags(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentKeyword: "coffee", AddTagIds: "3001,3002,3003,3004"},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, Comment: "Coffee", TagIds: []int64{3002}},
+	}
+
+	TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, []int64{3002, 3001}, targets[0].TagIds)
+}
+
+func TestApplyRules_AddTagsExceedMaximumCount(t *testing.T) {
+	tagIds := make([]int64, models.MaximumTagsCountOfTransaction)
+
+	for i := 0; i < len(tagIds); i++ {
+		tagIds[i] = int64(4000 + i)
+	}
+
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentKeyword: "coffee", AddTagIds: "3001"},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, Comment: "Coffee", TagIds: tagIds},
+	}
+
+	TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, models.MaximumTagsCountOfTransaction, len(targets[0].TagIds))
+	assert.NotContains(t, targets[0].TagIds, int64(3001))
+}
+
+func TestApplyRules_RewriteComment(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentRegex: "^POS\\s+(\\w+)\\s+\\d+$", RewriteComment: "$1"},
+		{RuleId: 2, CommentKeyword: "atm", RewriteComment: "Cash Withdrawal"},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, Comment: "POS Starbucks 123456"},
+		{Type: models.TRANSACTION_TYPE_EXPENSE, Comment: "ATM 0001"},
+	}
+
+	TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, "Starbucks", targets[0].Comment)
+	assert.Equal(t, "Cash Withdrawal", targets[1].Comment)
+}
+
+func TestApplyRules_StopProcessing(t *testing.T) {
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CommentKeyword: "coffee", SetHideAmount: true, StopProcessing: true},
+		{RuleId: 2, CommentKeyword: "coffee", AddTagIds: "3001"},
+	}
+	targets := []*models.TransactionRuleTarget{
+		{Type: models.TRANSACTION_TYPE_EXPENSE, Comment: "Coffee"},
+	}
+
+	matchedCount := TransactionRules.ApplyRules(rules, getTransactionRuleTestCategoryMap(), getTransactionRuleTestTagMap(), targets)
+
+	assert.Equal(t, 1, matchedCount)
+	assert.True(t, targets[0].HideAmount)
+	assert.Equal(t, 0, len(targets[0].TagIds))
+}
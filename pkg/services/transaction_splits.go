@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// TransactionSplitService represents transaction split service
+type TransactionSplitService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction split service singleton instance
+var (
+	TransactionSplits = &TransactionSplitService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetAllSplitsOfAllTransactions returns all transaction splits of user grouped by transaction id
+func (s *TransactionSplitService) GetAllSplitsOfAllTransactions(c core.Context, uid int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_id asc, display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroupedTransactionSplits(splits), nil
+}
+
+// GetAllSplitsOfTransactions returns transaction splits of given transactions grouped by transaction id
+func (s *TransactionSplitService) GetAllSplitsOfTransactions(c core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(transactionIds) < 1 {
+		return make(map[int64][]*models.TransactionSplit), nil
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).OrderBy("transaction_id asc, display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroupedTransactionSplits(splits), nil
+}
+
+// GetGroupedTransactionSplits returns a map of transaction id and its splits
+func (s *TransactionSplitService) GetGroupedTransactionSplits(splits []*models.TransactionSplit) map[int64][]*models.TransactionSplit {
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	return allTransactionSplits
+}
+
+// GetTransactionSplitCategoryIds returns all category ids used by given transaction splits
+func (s *TransactionSplitService) GetTransactionSplitCategoryIds(allTransactionSplits map[int64][]*models.TransactionSplit) []int64 {
+	categoryIds := make([]int64, 0, len(allTransactionSplits)*2)
+
+	for _, splits := range allTransactionSplits {
+		for i := 0; i < len(splits); i++ {
+			categoryIds = append(categoryIds, splits[i].CategoryId)
+		}
+	}
+
+	return categoryIds
+}
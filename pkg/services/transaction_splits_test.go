@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetGroupedTransactionSplits(t *testing.T) {
+	splits := []*models.TransactionSplit{
+		{SplitId: 1, TransactionId: 1001, CategoryId: 1, DisplayOrder: 1},
+		{SplitId: 2, TransactionId: 1001, CategoryId: 2, DisplayOrder: 2},
+		{SplitId: 3, TransactionId: 1002, CategoryId: 3, DisplayOrder: 1},
+	}
+	actualSplits := TransactionSplits.GetGroupedTransactionSplits(splits)
+
+	assert.Equal(t, 2, len(actualSplits))
+	assert.Equal(t, 2, len(actualSplits[1001]))
+	assert.Equal(t, int64(1), actualSplits[1001][0].SplitId)
+	assert.Equal(t, int64(2), actualSplits[1001][1].SplitId)
+	assert.Equal(t, 1, len(actualSplits[1002]))
+	assert.Equal(t, int64(3), actualSplits[1002][0].SplitId)
+}
+
+func TestGetTransactionSplitCategoryIds(t *testing.T) {
+	allTransactionSplits := map[int64][]*models.TransactionSplit{
+		1001: {
+			{TransactionId: 1001, CategoryId: 1},
+			{TransactionId: 1001, CategoryId: 2},
+		},
+	}
+	actualCategoryIds := TransactionSplits.GetTransactionSplitCategoryIds(allTransactionSplits)
+
+	assert.ElementsMatch(t, []int64{1, 2}, actualCategoryIds)
+}
+
+func TestExpandTransactionSplits_NoSplits(t *testing.T) {
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, AccountId: 1, Amount: 100},
+	}
+	actualTransactions := Transactions.expandTransactionSplits(transactions, nil)
+
+	assert.Equal(t, 1, len(actualTransactions))
+	assert.Equal(t, transactions[0], actualTransactions[0])
+}
+
+func TestExpandTransactionSplits_SplitTransaction(t *testing.T) {
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, AccountId: 1, TransactionTime: 1000, TimezoneUtcOffset: 480, Amount: 300},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 5, AccountId: 2, Amount: 50},
+	}
+	allTransactionSplits := map[int64][]*models.TransactionSplit{
+		1001: {
+			{TransactionId: 1001, CategoryId: 1, Amount: 100},
+			{TransactionId: 1001, CategoryId: 2, Amount: 200},
+		},
+	}
+	actualTransactions := Transactions.expandTransactionSplits(transactions, allTransactionSplits)
+
+	assert.Equal(t, 3, len(actualTransactions))
+
+	assert.Equal(t, int64(1001), actualTransactions[0].TransactionId)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, actualTransactions[0].Type)
+	assert.Equal(t, int64(1), actualTransactions[0].CategoryId)
+	assert.Equal(t, int64(1), actualTransactions[0].AccountId)
+	assert.Equal(t, int64(1000), actualTransactions[0].TransactionTime)
+	assert.Equal(t, int16(480), actualTransactions[0].TimezoneUtcOffset)
+	assert.Equal(t, int64(100), actualTransactions[0].Amount)
+
+	assert.Equal(t, int64(1001), actualTransactions[1].TransactionId)
+	assert.Equal(t, int64(2), actualTransactions[1].CategoryId)
+	assert.Equal(t, int64(200), actualTransactions[1].Amount)
+
+	assert.Equal(t, int64(1002), actualTransactions[2].TransactionId)
+	assert.Equal(t, int64(5), actualTransactions[2].CategoryId)
+	assert.Equal(t, int64(50), actualTransactions[2].Amount)
+}
+
+func TestExpandTransactionSplits_IgnoreSplitsOfTransferTransaction(t *testing.T) {
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 1, AccountId: 1, Amount: 300},
+	}
+	allTransactionSplits := map[int64][]*models.TransactionSplit{
+		1001: {
+			{TransactionId: 1001, CategoryId: 1, Amount: 100},
+			{TransactionId: 1001, CategoryId: 2, Amount: 200},
+		},
+	}
+	actualTransactions := Transactions.expandTransactionSplits(transactions, allTransactionSplits)
+
+	assert.Equal(t, 1, len(actualTransactions))
+	assert.Equal(t, int64(300), actualTransactions[0].Amount)
+}
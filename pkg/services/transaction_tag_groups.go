@@ -31,19 +31,19 @@ var (
 )
 
 // GetAllTagGroupsByUid returns all transaction tag group models of user
-func (s *TransactionTagGroupService) GetAllTagGroupsByUid(c core.Context, uid int64) ([]*models.TransactionTagGroup, error) {
+func (s *TransactionTagGroupService) GetAllTagGroupsByUid(c core.Context, uid int64, ledgerId int64) ([]*models.TransactionTagGroup, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var tagGroups []*models.TransactionTagGroup
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&tagGroups)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&tagGroups)
 
 	return tagGroups, err
 }
 
 // GetTagGroupByTagGroupId returns a transaction tag group model according to transaction tag group id
-func (s *TransactionTagGroupService) GetTagGroupByTagGroupId(c core.Context, uid int64, tagGroupId int64) (*models.TransactionTagGroup, error) {
+func (s *TransactionTagGroupService) GetTagGroupByTagGroupId(c core.Context, uid int64, ledgerId int64, tagGroupId int64) (*models.TransactionTagGroup, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -53,7 +53,7 @@ func (s *TransactionTagGroupService) GetTagGroupByTagGroupId(c core.Context, uid
 	}
 
 	tagGroup := &models.TransactionTagGroup{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(tagGroupId).Where("uid=? AND deleted=?", uid, false).Get(tagGroup)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(tagGroupId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(tagGroup)
 
 	if err != nil {
 		return nil, err
@@ -65,13 +65,13 @@ func (s *TransactionTagGroupService) GetTagGroupByTagGroupId(c core.Context, uid
 }
 
 // GetMaxDisplayOrder returns the max display order
-func (s *TransactionTagGroupService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+func (s *TransactionTagGroupService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	tagGroup := &models.TransactionTagGroup{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(tagGroup)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).OrderBy("display_order desc").Limit(1).Get(tagGroup)
 
 	if err != nil {
 		return 0, err
@@ -90,6 +90,12 @@ func (s *TransactionTagGroupService) CreateTagGroup(c core.Context, tagGroup *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, tagGroup.Uid, tagGroup.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	tagGroup.TagGroupId = s.GenerateUuid(uuid.UUID_TYPE_TAG_GROUP)
 
 	if tagGroup.TagGroupId < 1 {
@@ -112,10 +118,16 @@ func (s *TransactionTagGroupService) ModifyTagGroup(c core.Context, tagGroup *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, tagGroup.Uid, tagGroup.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	tagGroup.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(tagGroup.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("name", "updated_unix_time").Where("uid=? AND deleted=?", tagGroup.Uid, false).Update(tagGroup)
+		updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("name", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", tagGroup.Uid, tagGroup.LedgerId, false).Update(tagGroup)
 
 		if err != nil {
 			return err
@@ -128,11 +140,17 @@ func (s *TransactionTagGroupService) ModifyTagGroup(c core.Context, tagGroup *mo
 }
 
 // ModifyTagGroupDisplayOrders updates display order of given transaction tag groups
-func (s *TransactionTagGroupService) ModifyTagGroupDisplayOrders(c core.Context, uid int64, tagGroups []*models.TransactionTagGroup) error {
+func (s *TransactionTagGroupService) ModifyTagGroupDisplayOrders(c core.Context, uid int64, ledgerId int64, tagGroups []*models.TransactionTagGroup) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(tagGroups); i++ {
 		tagGroups[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -140,7 +158,7 @@ func (s *TransactionTagGroupService) ModifyTagGroupDisplayOrders(c core.Context,
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(tagGroups); i++ {
 			tagGroup := tagGroups[i]
-			updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tagGroup)
+			updatedRows, err := sess.ID(tagGroup.TagGroupId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(tagGroup)
 
 			if err != nil {
 				return err
@@ -154,11 +172,17 @@ func (s *TransactionTagGroupService) ModifyTagGroupDisplayOrders(c core.Context,
 }
 
 // DeleteTagGroup deletes an existed transaction tag group from database
-func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, tagGroupId int64) error {
+func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, ledgerId int64, tagGroupId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTagGroup{
@@ -167,7 +191,7 @@ func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, t
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		exists, err := sess.Cols("uid", "deleted").Where("uid=? AND deleted=? AND tag_group_id=?", uid, false, tagGroupId).Limit(1).Exist(&models.TransactionTag{})
+		exists, err := sess.Cols("uid", "ledger_id", "deleted").Where("uid=? AND ledger_id=? AND deleted=? AND tag_group_id=?", uid, ledgerId, false, tagGroupId).Limit(1).Exist(&models.TransactionTag{})
 
 		if err != nil {
 			return err
@@ -175,7 +199,7 @@ func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, t
 			return errs.ErrTransactionTagGroupInUseCannotBeDeleted
 		}
 
-		deletedRows, err := sess.ID(tagGroupId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		deletedRows, err := sess.ID(tagGroupId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -188,11 +212,17 @@ func (s *TransactionTagGroupService) DeleteTagGroup(c core.Context, uid int64, t
 }
 
 // DeleteAllTagGroups deletes all existed transaction tag groups from database
-func (s *TransactionTagGroupService) DeleteAllTagGroups(c core.Context, uid int64) error {
+func (s *TransactionTagGroupService) DeleteAllTagGroups(c core.Context, uid int64, ledgerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTagGroup{
@@ -201,7 +231,7 @@ func (s *TransactionTagGroupService) DeleteAllTagGroups(c core.Context, uid int6
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		exists, err := sess.Cols("uid", "deleted").Where("uid=? AND deleted=? AND tag_group_id>?", uid, false, 0).Limit(1).Exist(&models.TransactionTag{})
+		exists, err := sess.Cols("uid", "ledger_id", "deleted").Where("uid=? AND ledger_id=? AND deleted=? AND tag_group_id>?", uid, ledgerId, false, 0).Limit(1).Exist(&models.TransactionTag{})
 
 		if err != nil {
 			return err
@@ -209,7 +239,7 @@ func (s *TransactionTagGroupService) DeleteAllTagGroups(c core.Context, uid int6
 			return errs.ErrTransactionTagGroupInUseCannotBeDeleted
 		}
 
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -35,30 +35,30 @@ var (
 )
 
 // GetTotalTagCountByUid returns total tag count of user
-func (s *TransactionTagService) GetTotalTagCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *TransactionTagService) GetTotalTagCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.TransactionTag{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.TransactionTag{})
 
 	return count, err
 }
 
 // GetAllTagsByUid returns all transaction tag models of user
-func (s *TransactionTagService) GetAllTagsByUid(c core.Context, uid int64) ([]*models.TransactionTag, error) {
+func (s *TransactionTagService) GetAllTagsByUid(c core.Context, uid int64, ledgerId int64) ([]*models.TransactionTag, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var tags []*models.TransactionTag
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&tags)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&tags)
 
 	return tags, err
 }
 
 // GetTagByTagId returns a transaction tag model according to transaction tag id
-func (s *TransactionTagService) GetTagByTagId(c core.Context, uid int64, tagId int64) (*models.TransactionTag, error) {
+func (s *TransactionTagService) GetTagByTagId(c core.Context, uid int64, ledgerId int64, tagId int64) (*models.TransactionTag, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -68,7 +68,7 @@ func (s *TransactionTagService) GetTagByTagId(c core.Context, uid int64, tagId i
 	}
 
 	tag := &models.TransactionTag{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(tagId).Where("uid=? AND deleted=?", uid, false).Get(tag)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(tagId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(tag)
 
 	if err != nil {
 		return nil, err
@@ -80,7 +80,7 @@ func (s *TransactionTagService) GetTagByTagId(c core.Context, uid int64, tagId i
 }
 
 // GetTagsByTagIds returns transaction tag models according to transaction tag ids
-func (s *TransactionTagService) GetTagsByTagIds(c core.Context, uid int64, tagIds []int64) (map[int64]*models.TransactionTag, error) {
+func (s *TransactionTagService) GetTagsByTagIds(c core.Context, uid int64, ledgerId int64, tagIds []int64) (map[int64]*models.TransactionTag, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -90,7 +90,7 @@ func (s *TransactionTagService) GetTagsByTagIds(c core.Context, uid int64, tagId
 	}
 
 	var tags []*models.TransactionTag
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("tag_id", tagIds).Find(&tags)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("tag_id", tagIds).Find(&tags)
 
 	if err != nil {
 		return nil, err
@@ -101,13 +101,13 @@ func (s *TransactionTagService) GetTagsByTagIds(c core.Context, uid int64, tagId
 }
 
 // GetMaxDisplayOrder returns the max display order
-func (s *TransactionTagService) GetMaxDisplayOrder(c core.Context, uid int64, tagGroupId int64) (int32, error) {
+func (s *TransactionTagService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64, tagGroupId int64) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	tag := &models.TransactionTag{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=? AND tag_group_id=?", uid, false, tagGroupId).OrderBy("display_order desc").Limit(1).Get(tag)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND tag_group_id=?", uid, ledgerId, false, tagGroupId).OrderBy("display_order desc").Limit(1).Get(tag)
 
 	if err != nil {
 		return 0, err
@@ -193,13 +193,53 @@ func (s *TransactionTagService) GetAllTagIdsOfTransactions(c core.Context, uid i
 	return allTransactionTagIds, err
 }
 
+// GetAllTagIdsOfDeletedTransactions returns the tag ids which deleted transactions had before they were deleted
+func (s *TransactionTagService) GetAllTagIdsOfDeletedTransactions(c core.Context, uid int64, transactions []*models.Transaction) (map[int64][]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	transactionIds := make([]int64, len(transactions))
+	transactionDeletedTimes := make(map[int64]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = transactions[i].TransactionId
+		transactionDeletedTimes[transactions[i].TransactionId] = transactions[i].DeletedUnixTime
+	}
+
+	var allTagIndexes []*models.TransactionTagIndex
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, true).In("transaction_id", transactionIds).OrderBy("transaction_id asc, tag_index_id asc").Find(&allTagIndexes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tagIndexes := make([]*models.TransactionTagIndex, 0, len(allTagIndexes))
+
+	for i := 0; i < len(allTagIndexes); i++ {
+		tagIndex := allTagIndexes[i]
+
+		if tagIndex.DeletedUnixTime == transactionDeletedTimes[tagIndex.TransactionId] {
+			tagIndexes = append(tagIndexes, tagIndex)
+		}
+	}
+
+	return s.GetGroupedTransactionTagIds(tagIndexes), nil
+}
+
 // CreateTag saves a new transaction tag model to database
 func (s *TransactionTagService) CreateTag(c core.Context, tag *models.TransactionTag) error {
 	if tag.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
+	err := checkLedgerPermission(c, tag.Uid, tag.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	exists, err := s.ExistsTagName(c, tag.Uid, tag.LedgerId, tag.Name)
 
 	if err != nil {
 		return err
@@ -224,11 +264,17 @@ func (s *TransactionTagService) CreateTag(c core.Context, tag *models.Transactio
 }
 
 // CreateTags saves a few transaction tag models to database
-func (s *TransactionTagService) CreateTags(c core.Context, uid int64, tags []*models.TransactionTag, skipExists bool) error {
+func (s *TransactionTagService) CreateTags(c core.Context, uid int64, ledgerId int64, tags []*models.TransactionTag, skipExists bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	allTagNames := make([]string, len(tags))
 
 	for i := 0; i < len(tags); i++ {
@@ -236,7 +282,7 @@ func (s *TransactionTagService) CreateTags(c core.Context, uid int64, tags []*mo
 	}
 
 	var existTags []*models.TransactionTag
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("name", allTagNames).Find(&existTags)
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("name", allTagNames).Find(&existTags)
 
 	if err != nil {
 		return err
@@ -274,6 +320,7 @@ func (s *TransactionTagService) CreateTags(c core.Context, uid int64, tags []*mo
 	for i := 0; i < len(newTags); i++ {
 		tag := newTags[i]
 		tag.TagId = tagUuids[i]
+		tag.LedgerId = ledgerId
 		tag.Deleted = false
 		tag.CreatedUnixTime = time.Now().Unix()
 		tag.UpdatedUnixTime = time.Now().Unix()
@@ -299,8 +346,14 @@ func (s *TransactionTagService) ModifyTag(c core.Context, tag *models.Transactio
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, tag.Uid, tag.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	if tagNameChanged {
-		exists, err := s.ExistsTagName(c, tag.Uid, tag.Name)
+		exists, err := s.ExistsTagName(c, tag.Uid, tag.LedgerId, tag.Name)
 
 		if err != nil {
 			return err
@@ -312,7 +365,7 @@ func (s *TransactionTagService) ModifyTag(c core.Context, tag *models.Transactio
 	tag.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(tag.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(tag.TagId).Cols("name", "tag_group_id", "display_order", "updated_unix_time").Where("uid=? AND deleted=?", tag.Uid, false).Update(tag)
+		updatedRows, err := sess.ID(tag.TagId).Cols("name", "tag_group_id", "display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", tag.Uid, tag.LedgerId, false).Update(tag)
 
 		if err != nil {
 			return err
@@ -325,11 +378,17 @@ func (s *TransactionTagService) ModifyTag(c core.Context, tag *models.Transactio
 }
 
 // HideTag updates hidden field of given transaction tags
-func (s *TransactionTagService) HideTag(c core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *TransactionTagService) HideTag(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTag{
@@ -338,7 +397,7 @@ func (s *TransactionTagService) HideTag(c core.Context, uid int64, ids []int64,
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("tag_id", ids).Update(updateModel)
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("tag_id", ids).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -351,11 +410,17 @@ func (s *TransactionTagService) HideTag(c core.Context, uid int64, ids []int64,
 }
 
 // ModifyTagDisplayOrders updates display order of given transaction tags
-func (s *TransactionTagService) ModifyTagDisplayOrders(c core.Context, uid int64, tags []*models.TransactionTag) error {
+func (s *TransactionTagService) ModifyTagDisplayOrders(c core.Context, uid int64, ledgerId int64, tags []*models.TransactionTag) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(tags); i++ {
 		tags[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -363,7 +428,7 @@ func (s *TransactionTagService) ModifyTagDisplayOrders(c core.Context, uid int64
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(tags); i++ {
 			tag := tags[i]
-			updatedRows, err := sess.ID(tag.TagId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tag)
+			updatedRows, err := sess.ID(tag.TagId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(tag)
 
 			if err != nil {
 				return err
@@ -377,11 +442,17 @@ func (s *TransactionTagService) ModifyTagDisplayOrders(c core.Context, uid int64
 }
 
 // DeleteTag deletes an existed transaction tag from database
-func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64) error {
+func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, ledgerId int64, tagId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTag{
@@ -399,7 +470,7 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 		}
 
 		var relatedTransactionTemplatesByTag []*models.TransactionTemplate
-		err = sess.Cols("uid", "deleted", "tag_ids", "template_type", "scheduled_frequency_type", "scheduled_end_time").Where("uid=? AND deleted=? AND (template_type=? OR (template_type=? AND scheduled_frequency_type<>? AND (scheduled_end_time IS NULL OR scheduled_end_time>=?))) AND tag_ids LIKE ?", uid, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, now, "%%"+utils.Int64ToString(tagId)+"%%").Find(&relatedTransactionTemplatesByTag)
+		err = sess.Cols("uid", "deleted", "tag_ids", "template_type", "scheduled_frequency_type", "scheduled_end_time").Where("uid=? AND ledger_id=? AND deleted=? AND (template_type=? OR (template_type=? AND scheduled_frequency_type<>? AND (scheduled_end_time IS NULL OR scheduled_end_time>=?))) AND tag_ids LIKE ?", uid, ledgerId, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED, now, "%%"+utils.Int64ToString(tagId)+"%%").Find(&relatedTransactionTemplatesByTag)
 
 		if err != nil {
 			return err
@@ -420,7 +491,7 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 			}
 		}
 
-		deletedRows, err := sess.ID(tagId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		deletedRows, err := sess.ID(tagId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -433,11 +504,17 @@ func (s *TransactionTagService) DeleteTag(c core.Context, uid int64, tagId int64
 }
 
 // DeleteAllTags deletes all existed transaction tags from database
-func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
+func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64, ledgerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTag{
@@ -446,7 +523,22 @@ func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		exists, err := sess.Cols("uid", "deleted").Where("uid=? AND deleted=?", uid, false).Limit(1).Exist(&models.TransactionTagIndex{})
+		var tags []*models.TransactionTag
+		err := sess.Cols("tag_id").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&tags)
+
+		if err != nil {
+			return err
+		} else if len(tags) < 1 {
+			return nil
+		}
+
+		tagIds := make([]int64, len(tags))
+
+		for i := 0; i < len(tags); i++ {
+			tagIds[i] = tags[i].TagId
+		}
+
+		exists, err := sess.Cols("uid", "deleted", "tag_id").Where("uid=? AND deleted=?", uid, false).In("tag_id", tagIds).Limit(1).Exist(&models.TransactionTagIndex{})
 
 		if err != nil {
 			return err
@@ -454,7 +546,7 @@ func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 			return errs.ErrTransactionTagInUseCannotBeDeleted
 		}
 
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -464,13 +556,108 @@ func (s *TransactionTagService) DeleteAllTags(c core.Context, uid int64) error {
 	})
 }
 
+// GetAllDeletedTagsByUid returns all deleted transaction tag models in the trash bin of user
+func (s *TransactionTagService) GetAllDeletedTagsByUid(c core.Context, uid int64, ledgerId int64) ([]*models.TransactionTag, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var tags []*models.TransactionTag
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).OrderBy("deleted_unix_time desc, display_order asc").Find(&tags)
+
+	return tags, err
+}
+
+// RestoreTag restores a deleted transaction tag from the trash bin
+func (s *TransactionTagService) RestoreTag(c core.Context, uid int64, ledgerId int64, tagId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if tagId <= 0 {
+		return errs.ErrTransactionTagIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	updateModel := &models.TransactionTag{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		tag := &models.TransactionTag{}
+		has, err := sess.ID(tagId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(tag)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		exists, err := sess.Cols("uid", "ledger_id", "deleted", "name").Where("uid=? AND ledger_id=? AND deleted=? AND name=?", uid, ledgerId, false, tag.Name).Exist(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrTransactionTagNameAlreadyExists
+		}
+
+		restoredRows, err := sess.ID(tagId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < 1 {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		return nil
+	})
+}
+
+// PurgeTag permanently deletes a deleted transaction tag and its deleted tag indexes from the trash bin
+func (s *TransactionTagService) PurgeTag(c core.Context, uid int64, ledgerId int64, tagId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if tagId <= 0 {
+		return errs.ErrTransactionTagIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(tagId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Delete(&models.TransactionTag{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionTagNotFound
+		}
+
+		_, err = sess.Where("uid=? AND deleted=? AND tag_id=?", uid, true, tagId).Delete(&models.TransactionTagIndex{})
+
+		return err
+	})
+}
+
 // ExistsTagName returns whether the given tag name exists
-func (s *TransactionTagService) ExistsTagName(c core.Context, uid int64, name string) (bool, error) {
+func (s *TransactionTagService) ExistsTagName(c core.Context, uid int64, ledgerId int64, name string) (bool, error) {
 	if name == "" {
 		return false, errs.ErrTransactionTagNameIsEmpty
 	}
 
-	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND deleted=? AND name=?", uid, false, name).Exist(&models.TransactionTag{})
+	return s.UserDataDB(uid).NewSession(c).Cols("name").Where("uid=? AND ledger_id=? AND deleted=? AND name=?", uid, ledgerId, false, name).Exist(&models.TransactionTag{})
 }
 
 // ModifyTagIndexTransactionTime updates transaction time of given transaction tag indexes
@@ -479,6 +666,12 @@ func (s *TransactionTagService) ModifyTagIndexTransactionTime(c core.Context, ui
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerOwnerPermission(c, uid, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(tagIndexes); i++ {
 		tagIndexes[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -31,41 +31,41 @@ var (
 )
 
 // GetTotalNormalTemplateCountByUid returns total normal template count of user
-func (s *TransactionTemplateService) GetTotalNormalTemplateCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *TransactionTemplateService) GetTotalNormalTemplateCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_type=?", uid, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL).Count(&models.TransactionTemplate{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND template_type=?", uid, ledgerId, false, models.TRANSACTION_TEMPLATE_TYPE_NORMAL).Count(&models.TransactionTemplate{})
 
 	return count, err
 }
 
 // GetTotalScheduledTemplateCountByUid returns total scheduled transaction count of user
-func (s *TransactionTemplateService) GetTotalScheduledTemplateCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *TransactionTemplateService) GetTotalScheduledTemplateCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_type=?", uid, false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE).Count(&models.TransactionTemplate{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND template_type=?", uid, ledgerId, false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE).Count(&models.TransactionTemplate{})
 
 	return count, err
 }
 
 // GetAllTemplatesByUid returns all transaction template models of user
-func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, uid int64, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
+func (s *TransactionTemplateService) GetAllTemplatesByUid(c core.Context, uid int64, ledgerId int64, templateType models.TransactionTemplateType) ([]*models.TransactionTemplate, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
 	var templates []*models.TransactionTemplate
-	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_type=?", uid, false, templateType).Find(&templates)
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND template_type=?", uid, ledgerId, false, templateType).Find(&templates)
 
 	return templates, err
 }
 
 // GetTemplateByTemplateId returns a transaction template model according to transaction template id
-func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid int64, templateId int64) (*models.TransactionTemplate, error) {
+func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid int64, ledgerId int64, templateId int64) (*models.TransactionTemplate, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -75,7 +75,7 @@ func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid
 	}
 
 	template := &models.TransactionTemplate{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(templateId).Where("uid=? AND deleted=?", uid, false).Get(template)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(templateId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(template)
 
 	if err != nil {
 		return nil, err
@@ -87,13 +87,13 @@ func (s *TransactionTemplateService) GetTemplateByTemplateId(c core.Context, uid
 }
 
 // GetMaxDisplayOrder returns the max display order
-func (s *TransactionTemplateService) GetMaxDisplayOrder(c core.Context, uid int64, templateType models.TransactionTemplateType) (int32, error) {
+func (s *TransactionTemplateService) GetMaxDisplayOrder(c core.Context, uid int64, ledgerId int64, templateType models.TransactionTemplateType) (int32, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
 	template := &models.TransactionTemplate{}
-	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=? AND template_type=?", uid, false, templateType).OrderBy("display_order desc").Limit(1).Get(template)
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "ledger_id", "deleted", "display_order").Where("uid=? AND ledger_id=? AND deleted=? AND template_type=?", uid, ledgerId, false, templateType).OrderBy("display_order desc").Limit(1).Get(template)
 
 	if err != nil {
 		return 0, err
@@ -112,6 +112,12 @@ func (s *TransactionTemplateService) CreateTemplate(c core.Context, template *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, template.Uid, template.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	template.TemplateId = s.GenerateUuid(uuid.UUID_TYPE_TEMPLATE)
 
 	if template.TemplateId < 1 {
@@ -122,6 +128,11 @@ func (s *TransactionTemplateService) CreateTemplate(c core.Context, template *mo
 	template.CreatedUnixTime = time.Now().Unix()
 	template.UpdatedUnixTime = time.Now().Unix()
 
+	// the occurrences earlier than the creation time would not be back-filled
+	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		template.ScheduledLastOccurrenceTime = template.CreatedUnixTime
+	}
+
 	return s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		err := s.isTemplateValid(sess, template)
 
@@ -140,6 +151,12 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, template.Uid, template.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	template.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
@@ -149,7 +166,7 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 			return err
 		}
 
-		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
+		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).Update(template)
 
 		if err != nil {
 			return err
@@ -162,11 +179,17 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 }
 
 // HideTemplate updates hidden field of given transaction templates
-func (s *TransactionTemplateService) HideTemplate(c core.Context, uid int64, ids []int64, hidden bool) error {
+func (s *TransactionTemplateService) HideTemplate(c core.Context, uid int64, ledgerId int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTemplate{
@@ -175,7 +198,7 @@ func (s *TransactionTemplateService) HideTemplate(c core.Context, uid int64, ids
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("template_id", ids).Update(updateModel)
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("template_id", ids).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -188,11 +211,17 @@ func (s *TransactionTemplateService) HideTemplate(c core.Context, uid int64, ids
 }
 
 // ModifyTemplateDisplayOrders updates display order of given transaction templates
-func (s *TransactionTemplateService) ModifyTemplateDisplayOrders(c core.Context, uid int64, templates []*models.TransactionTemplate) error {
+func (s *TransactionTemplateService) ModifyTemplateDisplayOrders(c core.Context, uid int64, ledgerId int64, templates []*models.TransactionTemplate) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	for i := 0; i < len(templates); i++ {
 		templates[i].UpdatedUnixTime = time.Now().Unix()
 	}
@@ -200,7 +229,7 @@ func (s *TransactionTemplateService) ModifyTemplateDisplayOrders(c core.Context,
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(templates); i++ {
 			template := templates[i]
-			updatedRows, err := sess.ID(template.TemplateId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(template)
+			updatedRows, err := sess.ID(template.TemplateId).Cols("display_order", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(template)
 
 			if err != nil {
 				return err
@@ -214,11 +243,17 @@ func (s *TransactionTemplateService) ModifyTemplateDisplayOrders(c core.Context,
 }
 
 // DeleteTemplate deletes an existed transaction template from database
-func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, templateId int64) error {
+func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, ledgerId int64, templateId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTemplate{
@@ -227,7 +262,7 @@ func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, t
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		deletedRows, err := sess.ID(templateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		deletedRows, err := sess.ID(templateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
@@ -235,16 +270,24 @@ func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, t
 			return errs.ErrTransactionTemplateNotFound
 		}
 
+		_, err = sess.Where("uid=? AND template_id=?", uid, templateId).Delete(&models.TransactionTemplateOccurrenceOverride{})
+
 		return err
 	})
 }
 
 // DeleteAllTemplates deletes all existed transaction templates from database
-func (s *TransactionTemplateService) DeleteAllTemplates(c core.Context, uid int64) error {
+func (s *TransactionTemplateService) DeleteAllTemplates(c core.Context, uid int64, ledgerId int64) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.TransactionTemplate{
@@ -253,21 +296,111 @@ func (s *TransactionTemplateService) DeleteAllTemplates(c core.Context, uid int6
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+		var templateIds []int64
+		err := sess.Table("transaction_template").Cols("template_id").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Find(&templateIds)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(updateModel)
 
 		if err != nil {
 			return err
 		}
 
+		if len(templateIds) > 0 {
+			_, err = sess.Where("uid=?", uid).In("template_id", templateIds).Delete(&models.TransactionTemplateOccurrenceOverride{})
+
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
+// GetAllOccurrenceOverridesByTemplateId returns all skipped or postponed occurrences of the scheduled transaction template
+func (s *TransactionTemplateService) GetAllOccurrenceOverridesByTemplateId(c core.Context, uid int64, templateId int64) ([]*models.TransactionTemplateOccurrenceOverride, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if templateId <= 0 {
+		return nil, errs.ErrTransactionTemplateIdInvalid
+	}
+
+	var overrides []*models.TransactionTemplateOccurrenceOverride
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND template_id=?", uid, templateId).OrderBy("occurrence_time asc").Find(&overrides)
+
+	return overrides, err
+}
+
+// SaveOccurrenceOverride skips or postpones one occurrence of the scheduled transaction template, the existed override of the same occurrence would be replaced
+func (s *TransactionTemplateService) SaveOccurrenceOverride(c core.Context, override *models.TransactionTemplateOccurrenceOverride) error {
+	if override.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerOwnerPermission(c, override.Uid, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	if override.TemplateId <= 0 {
+		return errs.ErrTransactionTemplateIdInvalid
+	}
+
+	override.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(override.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=? AND template_id=? AND occurrence_time=?", override.Uid, override.TemplateId, override.OccurrenceTime).Delete(&models.TransactionTemplateOccurrenceOverride{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(override)
+		return err
+	})
+}
+
+// DeleteOccurrenceOverride restores one skipped or postponed occurrence of the scheduled transaction template
+func (s *TransactionTemplateService) DeleteOccurrenceOverride(c core.Context, uid int64, templateId int64, occurrenceTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if templateId <= 0 {
+		return errs.ErrTransactionTemplateIdInvalid
+	}
+
+	err := checkLedgerOwnerPermission(c, uid, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND template_id=? AND occurrence_time=?", uid, templateId, occurrenceTime).Delete(&models.TransactionTemplateOccurrenceOverride{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrScheduledTransactionOccurrenceNotFound
+		}
+
+		return err
+	})
+}
+
 func (s *TransactionTemplateService) isTemplateValid(sess *xorm.Session, template *models.TransactionTemplate) error {
 	// check accounts are valid
 	sourceAccount := &models.Account{}
 	destinationAccount := &models.Account{}
-	has, err := sess.ID(template.AccountId).Where("uid=? AND deleted=?", template.Uid, false).Get(sourceAccount)
+	has, err := sess.ID(template.AccountId).Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).Get(sourceAccount)
 
 	if err != nil {
 		return err
@@ -283,7 +416,7 @@ func (s *TransactionTemplateService) isTemplateValid(sess *xorm.Session, templat
 		if template.RelatedAccountId <= 0 {
 			return errs.ErrAccountIdInvalid
 		} else {
-			has, err = sess.ID(template.RelatedAccountId).Where("uid=? AND deleted=?", template.Uid, false).Get(destinationAccount)
+			has, err = sess.ID(template.RelatedAccountId).Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).Get(destinationAccount)
 
 			if err != nil {
 				return err
@@ -303,7 +436,7 @@ func (s *TransactionTemplateService) isTemplateValid(sess *xorm.Session, templat
 
 	// check category is valid
 	category := &models.TransactionCategory{}
-	has, err = sess.ID(template.CategoryId).Where("uid=? AND deleted=?", template.Uid, false).Get(category)
+	has, err = sess.ID(template.CategoryId).Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).Get(category)
 
 	if err != nil {
 		return err
@@ -328,7 +461,7 @@ func (s *TransactionTemplateService) isTemplateValid(sess *xorm.Session, templat
 	// check tags are valid
 	tagIds := template.GetTagIds()
 	var tags []*models.TransactionTag
-	err = sess.Where("uid=? AND deleted=?", template.Uid, false).In("tag_id", tagIds).Find(&tags)
+	err = sess.Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).In("tag_id", tagIds).Find(&tags)
 
 	if err != nil {
 		return err
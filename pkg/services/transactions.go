@@ -39,23 +39,23 @@ var (
 )
 
 // GetTotalTransactionCountByUid returns total transaction count of user
-func (s *TransactionService) GetTotalTransactionCountByUid(c core.Context, uid int64) (int64, error) {
+func (s *TransactionService) GetTotalTransactionCountByUid(c core.Context, uid int64, ledgerId int64) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
 
-	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.Transaction{})
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Count(&models.Transaction{})
 
 	return count, err
 }
 
 // GetAllTransactions returns all transactions
-func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, ledgerId int64, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
 	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetAllTransactionsByMaxTime(c, uid, maxTransactionTime, pageCount, noDuplicated)
+		transactions, err := s.GetAllTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, pageCount, noDuplicated)
 
 		if err != nil {
 			return nil, err
@@ -75,12 +75,12 @@ func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, pageC
 }
 
 // GetAllTransactionsByMaxTime returns all transactions before given time
-func (s *TransactionService) GetAllTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, count int32, noDuplicated bool) ([]*models.Transaction, error) {
-	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, count, false, noDuplicated)
+func (s *TransactionService) GetAllTransactionsByMaxTime(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, count int32, noDuplicated bool) ([]*models.Transaction, error) {
+	return s.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, count, false, noDuplicated)
 }
 
 // GetAllSpecifiedTransactions returns all transactions that match given conditions
-func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -88,7 +88,7 @@ func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, amountFilter, keyword, 1, pageCount, false, noDuplicated)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagFilters, noTags, amountFilter, keyword, 1, pageCount, false, noDuplicated)
 
 		if err != nil {
 			return nil, err
@@ -108,7 +108,7 @@ func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int
 }
 
 // GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime returns account statement within time range
-func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c core.Context, uid int64, pageCount int32, maxTransactionTime int64, minTransactionTime int64, accountId int64, accountCategory models.AccountCategory) ([]*models.TransactionWithAccountBalance, int64, int64, int64, int64, error) {
+func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c core.Context, uid int64, ledgerId int64, pageCount int32, maxTransactionTime int64, minTransactionTime int64, accountId int64, accountCategory models.AccountCategory) ([]*models.TransactionWithAccountBalance, int64, int64, int64, int64, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -116,7 +116,7 @@ func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByM
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, "", "", 1, pageCount, false, true)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, "", "", 1, pageCount, false, true)
 
 		if err != nil {
 			return nil, 0, 0, 0, 0, err
@@ -198,7 +198,7 @@ func (s *TransactionService) GetAllTransactionsInOneAccountWithAccountBalanceByM
 }
 
 // GetAllAccountsDailyOpeningAndClosingBalance returns daily opening and closing balance of all accounts within time range
-func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, clientTimezone *time.Location) (map[int32][]*models.TransactionWithAccountBalance, error) {
+func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, clientTimezone *time.Location) (map[int32][]*models.TransactionWithAccountBalance, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -206,7 +206,7 @@ func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, pageCountForLoadTransactionAmounts, false, false)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, ledgerId, maxTransactionTime, 0, 0, nil, nil, nil, false, "", "", 1, pageCountForLoadTransactionAmounts, false, false)
 
 		if err != nil {
 			return nil, err
@@ -323,7 +323,7 @@ func (s *TransactionService) GetAllAccountsDailyOpeningAndClosingBalance(c core.
 }
 
 // GetTransactionsByMaxTime returns transactions before given time
-func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -357,7 +357,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 		actualCount++
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, noDuplicated)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, noDuplicated)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
@@ -367,7 +367,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 }
 
 // GetTransactionsInMonthByPage returns all transactions in given year and month
-func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, ledgerId int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -391,7 +391,7 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 
 	var transactions []*models.Transaction
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
@@ -413,7 +413,7 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 }
 
 // GetTransactionByTransactionId returns a transaction model according to transaction id
-func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid int64, transactionId int64) (*models.Transaction, error) {
+func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid int64, ledgerId int64, transactionId int64) (*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -423,7 +423,7 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 	}
 
 	transaction := &models.Transaction{}
-	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(transaction)
 
 	if err != nil {
 		return nil, err
@@ -435,12 +435,12 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 }
 
 // GetAllTransactionCount returns total count of transactions
-func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64) (int64, error) {
-	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, "", "")
+func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64, ledgerId int64) (int64, error) {
+	return s.GetTransactionCount(c, uid, ledgerId, 0, 0, 0, nil, nil, nil, false, "", "")
 }
 
 // GetTransactionCount returns count of transactions
-func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) (int64, error) {
+func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, noTags bool, amountFilter string, keyword string) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -456,7 +456,7 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 		}
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, ledgerId, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagFilters, amountFilter, keyword, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
@@ -464,7 +464,19 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 }
 
 // CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splits []*models.TransactionSplit) error {
+	err := checkLedgerPermission(c, transaction.Uid, transaction.LedgerId, core.LEDGER_PERMISSION_ADD_TRANSACTION)
+
+	if err != nil {
+		return err
+	}
+
+	return s.createTransaction(c, transaction, tagIds, pictureIds, splits, nil, nil)
+}
+
+// createTransaction saves a new transaction to database, the transaction lock time is read in the database transaction if the specified lock time is nil,
+// and the specified investment transaction is saved with the new transaction as its cash transaction in the same database transaction if it is not nil
+func (s *TransactionService) createTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splits []*models.TransactionSplit, lockTime *int64, investmentTransaction *models.InvestmentTransaction) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -498,6 +510,13 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		return errs.ErrSystemIsBusy
 	}
 
+	needSplitUuidCount := uint16(len(splits))
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, needSplitUuidCount)
+
+	if len(splitUuids) < int(needSplitUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
 	transaction.TransactionId = transactionUuids[0]
 
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -523,6 +542,12 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		}
 	}
 
+	if len(splits) > 0 {
+		transaction.CategoryId = splits[0].CategoryId
+	}
+
+	transactionSplits := s.buildTransactionSplits(transaction, splits, splitUuids, now)
+
 	pictureUpdateModel := &models.TransactionPictureInfo{
 		TransactionId:   transaction.TransactionId,
 		UpdatedUnixTime: now,
@@ -531,7 +556,45 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	userDataDb := s.UserDataDB(transaction.Uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		// Check whether transaction time is locked
+		if lockTime == nil {
+			currentLockTime, err := s.getTransactionLockTime(c, sess, transaction.Uid, transaction.LedgerId)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.createTransaction] failed to get transaction lock time, because %s", err.Error())
+				return err
+			}
+
+			lockTime = &currentLockTime
+		}
+
+		if models.IsTransactionTimeLocked(transaction.TransactionTime, *lockTime) {
+			return errs.ErrCannotCreateTransactionBeforeLockTime
+		}
+
+		if investmentTransaction != nil {
+			investmentTransaction.TransactionId = transaction.TransactionId
+			err := InvestmentTransactions.doCreateTransaction(sess, investmentTransaction)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionSplits, tagIds, pictureIds, pictureUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		err = NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, transaction.Uid, []*models.Transaction{transaction}, now)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.createTransaction] failed to invalidate net worth snapshots, because %s", err.Error())
+			return err
+		}
+
+		return nil
 	})
 }
 
@@ -551,8 +614,14 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 			return errs.ErrUserIdInvalid
 		}
 
+		err := checkLedgerPermission(c, uid, transaction.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+		if err != nil {
+			return err
+		}
+
 		// Check whether account id is valid
-		err := s.isAccountIdValid(transaction)
+		err = s.isAccountIdValid(transaction)
 
 		if err != nil {
 			return err
@@ -639,11 +708,33 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 	userDataDb := s.UserDataDB(uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		ledgerLockTimes := make(map[int64]int64)
+
 		for i := 0; i < len(transactions); i++ {
 			transaction := transactions[i]
+
+			// Check whether transaction time is locked
+			lockTime, exists := ledgerLockTimes[transaction.LedgerId]
+
+			if !exists {
+				var err error
+				lockTime, err = s.getTransactionLockTime(c, sess, uid, transaction.LedgerId)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.BatchCreateTransactions] failed to get transaction lock time, because %s", err.Error())
+					return err
+				}
+
+				ledgerLockTimes[transaction.LedgerId] = lockTime
+			}
+
+			if models.IsTransactionTimeLocked(transaction.TransactionTime, lockTime) {
+				return errs.ErrCannotCreateTransactionBeforeLockTime
+			}
+
 			transactionTagIndexes := allTransactionTagIndexes[transaction.TransactionId]
 			transactionTagIds := allTransactionTagIds[transaction.TransactionId]
-			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil)
+			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, nil, transactionTagIds, nil, nil)
 
 			currentProcess = float64(i) / float64(len(transactions)) * 100
 
@@ -659,12 +750,19 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 			}
 		}
 
+		err := NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, uid, transactions, now)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.BatchCreateTransactions] failed to invalidate net worth snapshots, because %s", err.Error())
+			return err
+		}
+
 		return nil
 	})
 }
 
-// CreateScheduledTransactions saves all scheduled transactions that should be created now
-func (s *TransactionService) CreateScheduledTransactions(c core.Context, currentUnixTime int64, interval time.Duration) error {
+// CreateScheduledTransactions saves all scheduled transactions that should be created now, the missed transactions in the last max catch-up days are also created if max catch-up days is greater than zero
+func (s *TransactionService) CreateScheduledTransactions(c core.Context, currentUnixTime int64, interval time.Duration, maxCatchUpDays uint32) error {
 	var allTemplates []*models.TransactionTemplate
 	intervalMinute := int(interval / time.Minute)
 	currentTime := time.Unix(currentUnixTime, 0)
@@ -685,16 +783,16 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		var templates []*models.TransactionTemplate
 		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=?"+
 			" AND template_type=?"+
-			" AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?)"+
+			" AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?)"+
 			" AND (scheduled_start_time IS NULL OR scheduled_start_time<=?)"+
 			" AND (scheduled_end_time IS NULL OR scheduled_end_time>=?)"+
 			" AND scheduled_at>=?"+
 			" AND scheduled_at<?",
 			false,
 			models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
-			models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY,
-			startTime.Unix(),
+			models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
 			startTime.Unix(),
+			startTime.Unix()-int64(maxCatchUpDays)*24*60*60,
 			minScheduledAt,
 			maxScheduledAt).Find(&templates)
 
@@ -718,127 +816,285 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 	for i := 0; i < len(allTemplates); i++ {
 		template := allTemplates[i]
 
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+		if !s.isScheduledTransactionTemplateValid(c, template) {
 			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" disabled scheduled transaction frequency", template.TemplateId)
 			continue
 		}
 
-		if (template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY) ||
-			template.ScheduledFrequency == "" {
-			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency", template.TemplateId)
-			continue
+		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
+		minTransactionUnixTime := transactionUnixTime
+
+		if maxCatchUpDays > 0 && template.ScheduledLastOccurrenceTime > 0 {
+			minTransactionUnixTime = transactionUnixTime - int64(maxCatchUpDays)*24*60*60
 		}
 
-		frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(template.ScheduledFrequency, ","))
+		createdCount, failed := s.createScheduledTransactionsByTemplate(c, template, minTransactionUnixTime, transactionUnixTime)
+		successCount += createdCount
 
-		if err != nil {
+		if failed {
+			failedCount++
+		} else if createdCount < 1 {
 			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency, because %s", template.TemplateId, err.Error())
-			continue
 		}
+	}
 
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY {
-			maxDayInMonth := utils.GetMaxDayOfMonth(currentTime.Year(), currentTime.Month())
+	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully, %d templates does not need to create transactions and %d templates failed to create transactions", successCount, skipCount, failedCount)
 
-			for i := 0; i < len(frequencyValues); i++ {
-				if frequencyValues[i] < 0 {
-					frequencyValues[i] = int64(maxDayInMonth) + frequencyValues[i] + 1
-				}
-			}
+	return nil
+}
+
+// CatchUpScheduledTransactions saves all scheduled transactions that were missed in the last max catch-up days and should have been created before now
+func (s *TransactionService) CatchUpScheduledTransactions(c core.Context, currentUnixTime int64, maxCatchUpDays uint32) error {
+	var allTemplates []*models.TransactionTemplate
+	minTransactionUnixTime := currentUnixTime - int64(maxCatchUpDays)*24*60*60
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var templates []*models.TransactionTemplate
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=?"+
+			" AND template_type=?"+
+			" AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?)"+
+			" AND (scheduled_start_time IS NULL OR scheduled_start_time<=?)"+
+			" AND (scheduled_end_time IS NULL OR scheduled_end_time>=?)"+
+			" AND scheduled_last_occurrence_time>?"+
+			" AND scheduled_last_occurrence_time<?",
+			false,
+			models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+			models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+			currentUnixTime,
+			minTransactionUnixTime,
+			0,
+			currentUnixTime).Find(&templates)
+
+		if err != nil {
+			return err
 		}
 
-		frequencyValueSet := utils.ToSet(frequencyValues)
-		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
-		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
-		transactionTime := time.Unix(transactionUnixTime, 0).In(templateTimeZone)
+		allTemplates = append(allTemplates, templates...)
+	}
 
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, startTimeInUTC.Weekday())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && !frequencyValueSet[int64(transactionTime.Day())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d of month", template.TemplateId, startTimeInUTC.Day())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY && !frequencyValueSet[int64(transactionTime.Month())*100+int64(transactionTime.Day())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d-%d of year", template.TemplateId, startTimeInUTC.Month(), startTimeInUTC.Day())
+	if len(allTemplates) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[transactions.CatchUpScheduledTransactions] should check %d scheduled transaction templates for missed transactions since %d", len(allTemplates), minTransactionUnixTime)
+
+	successCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allTemplates); i++ {
+		template := allTemplates[i]
+
+		if !s.isScheduledTransactionTemplateValid(c, template) {
 			continue
 		}
 
-		if template.ScheduledStartTime != nil && *template.ScheduledStartTime > transactionUnixTime {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, now is earlier than the start time %d", template.TemplateId, *template.ScheduledStartTime)
-			continue
+		createdCount, failed := s.createScheduledTransactionsByTemplate(c, template, minTransactionUnixTime, currentUnixTime)
+		successCount += createdCount
+
+		if failed {
+			failedCount++
 		}
+	}
 
-		if template.ScheduledEndTime != nil && *template.ScheduledEndTime < transactionUnixTime {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, now is later than the end time %d", template.TemplateId, *template.ScheduledEndTime)
+	log.Infof(c, "[transactions.CatchUpScheduledTransactions] %d missed transactions has been created successfully and %d templates failed to create missed transactions", successCount, failedCount)
+
+	return nil
+}
+
+func (s *TransactionService) isScheduledTransactionTemplateValid(c core.Context, template *models.TransactionTemplate) bool {
+	if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+		log.Warnf(c, "[transactions.isScheduledTransactionTemplateValid] transaction template \"id:%d\" disabled scheduled transaction frequency", template.TemplateId)
+		return false
+	}
+
+	if (template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
+		template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY &&
+		template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY &&
+		template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY &&
+		template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE) ||
+		template.ScheduledFrequency == "" {
+		log.Warnf(c, "[transactions.isScheduledTransactionTemplateValid] transaction template \"id:%d\" has invalid scheduled transaction frequency", template.TemplateId)
+		return false
+	}
+
+	if template.Type != models.TRANSACTION_TYPE_EXPENSE && template.Type != models.TRANSACTION_TYPE_INCOME && template.Type != models.TRANSACTION_TYPE_TRANSFER {
+		log.Warnf(c, "[transactions.isScheduledTransactionTemplateValid] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
+		return false
+	}
+
+	return true
+}
+
+// createScheduledTransactionsByTemplate creates the transactions of all occurrences which are later than the last created occurrence and between the min and max transaction unix time, and returns the count of created transactions and whether any transaction failed to create
+func (s *TransactionService) createScheduledTransactionsByTemplate(c core.Context, template *models.TransactionTemplate, minTransactionUnixTime int64, maxTransactionUnixTime int64) (int, bool) {
+	templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
+
+	if template.ScheduledLastOccurrenceTime >= minTransactionUnixTime {
+		minTransactionUnixTime = template.ScheduledLastOccurrenceTime + 1
+	}
+
+	if minTransactionUnixTime > maxTransactionUnixTime {
+		log.Infof(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" does not need to create transaction, the transaction of %s has been created", template.TemplateId, utils.FormatUnixTimeToLongDate(template.ScheduledLastOccurrenceTime, templateTimeZone))
+		return 0, false
+	}
+
+	transactionUnixTimes, err := template.GetScheduledTransactionTimes(minTransactionUnixTime, maxTransactionUnixTime)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" has invalid scheduled transaction frequency, because %s", template.TemplateId, err.Error())
+		return 0, false
+	}
+
+	overrides, err := TransactionTemplates.GetAllOccurrenceOverridesByTemplateId(c, template.Uid, template.TemplateId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" failed to get skipped or postponed occurrences, because %s", template.TemplateId, err.Error())
+		return 0, true
+	}
+
+	transactionUnixTimes = models.ApplyTransactionTemplateOccurrenceOverrides(transactionUnixTimes, overrides, minTransactionUnixTime, maxTransactionUnixTime)
+
+	if len(transactionUnixTimes) < 1 {
+		log.Infof(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" does not need to create transaction from %s to %s", template.TemplateId, utils.FormatUnixTimeToLongDate(minTransactionUnixTime, templateTimeZone), utils.FormatUnixTimeToLongDate(maxTransactionUnixTime, templateTimeZone))
+		return 0, false
+	}
+
+	lockTime, err := s.getTransactionLockTime(c, s.UserDataDB(template.Uid).NewSession(c), template.Uid, template.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" failed to get transaction lock time, because %s", template.TemplateId, err.Error())
+		return 0, true
+	}
+
+	createdCount := 0
+
+	for i := 0; i < len(transactionUnixTimes); i++ {
+		transactionUnixTime := transactionUnixTimes[i]
+		transactionDate := utils.FormatUnixTimeToLongDate(transactionUnixTime, templateTimeZone)
+
+		// record the occurrence before creating transaction, so that the same occurrence would not be created by other process concurrently
+		updatedRows, err := s.UserDataDB(template.Uid).NewSession(c).ID(template.TemplateId).Cols("scheduled_last_occurrence_time").Where("uid=? AND deleted=? AND scheduled_last_occurrence_time<?", template.Uid, false, transactionUnixTime).Update(&models.TransactionTemplate{
+			ScheduledLastOccurrenceTime: transactionUnixTime,
+		})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" failed to update last occurrence time to %s, because %s", template.TemplateId, transactionDate, err.Error())
+			return createdCount, true
+		} else if updatedRows < 1 {
+			log.Infof(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" does not need to create transaction, the transaction of %s has been created", template.TemplateId, transactionDate)
 			continue
 		}
 
-		var transactionDbType models.TransactionDbType
+		template.ScheduledLastOccurrenceTime = transactionUnixTime
+		transaction, err := s.createScheduledTransaction(c, template, time.Unix(transactionUnixTime, 0).In(templateTimeZone), lockTime)
 
-		if template.Type == models.TRANSACTION_TYPE_EXPENSE {
-			transactionDbType = models.TRANSACTION_DB_TYPE_EXPENSE
-		} else if template.Type == models.TRANSACTION_TYPE_INCOME {
-			transactionDbType = models.TRANSACTION_DB_TYPE_INCOME
-		} else if template.Type == models.TRANSACTION_TYPE_TRANSFER {
-			transactionDbType = models.TRANSACTION_DB_TYPE_TRANSFER_OUT
-		} else {
-			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
+		if err != nil {
+			log.Errorf(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" failed to create new trasaction of %s, because %s", template.TemplateId, transactionDate, err.Error())
+			return createdCount, true
+		} else if transaction == nil {
 			continue
 		}
 
-		transaction := &models.Transaction{
-			Uid:               template.Uid,
-			Type:              transactionDbType,
-			CategoryId:        template.CategoryId,
-			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
-			TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
-			AccountId:         template.AccountId,
-			Amount:            template.Amount,
-			HideAmount:        template.HideAmount,
-			Comment:           template.Comment,
-			CreatedIp:         c.ClientIP(),
-			ScheduledCreated:  true,
+		createdCount++
+		log.Infof(c, "[transactions.createScheduledTransactionsByTemplate] transaction template \"id:%d\" has created a new trasaction \"id:%d\" of %s", template.TemplateId, transaction.TransactionId, transactionDate)
+	}
+
+	return createdCount, false
+}
+
+func (s *TransactionService) createScheduledTransaction(c core.Context, template *models.TransactionTemplate, transactionTime time.Time, lockTime int64) (*models.Transaction, error) {
+	var err error
+	amount := template.Amount
+	relatedAccountAmount := template.RelatedAccountAmount
+
+	if template.AmortizationScheduleId > 0 {
+		amount, err = s.getAmortizationInstallmentAmount(c, template, transactionTime)
+
+		if err != nil {
+			return nil, err
 		}
 
-		if template.Type == models.TRANSACTION_TYPE_TRANSFER {
-			transaction.RelatedAccountId = template.RelatedAccountId
-			transaction.RelatedAccountAmount = template.RelatedAccountAmount
+		if amount <= 0 {
+			log.Infof(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" does not need to create transaction, amortization schedule \"id:%d\" has nothing to pay in this installment", template.TemplateId, template.AmortizationScheduleId)
+			return nil, nil
 		}
 
-		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+		relatedAccountAmount = amount
+	}
 
-		if err == nil {
-			successCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has created a new trasaction \"id:%d\"", template.TemplateId, transaction.TransactionId)
-		} else {
-			failedCount++
-			log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to create new trasaction, because %s", template.TemplateId, err.Error())
-		}
+	var transactionDbType models.TransactionDbType
+
+	if template.Type == models.TRANSACTION_TYPE_EXPENSE {
+		transactionDbType = models.TRANSACTION_DB_TYPE_EXPENSE
+	} else if template.Type == models.TRANSACTION_TYPE_INCOME {
+		transactionDbType = models.TRANSACTION_DB_TYPE_INCOME
+	} else if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+		transactionDbType = models.TRANSACTION_DB_TYPE_TRANSFER_OUT
+	} else {
+		return nil, errs.ErrTransactionTypeInvalid
 	}
 
-	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully, %d templates does not need to create transactions and %d transactions failed to create", successCount, skipCount, failedCount)
+	transaction := &models.Transaction{
+		Uid:               template.Uid,
+		LedgerId:          template.LedgerId,
+		Type:              transactionDbType,
+		CategoryId:        template.CategoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
+		TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
+		AccountId:         template.AccountId,
+		Amount:            amount,
+		HideAmount:        template.HideAmount,
+		Comment:           template.Comment,
+		CreatedIp:         c.ClientIP(),
+		CreatedByUid:      template.Uid,
+		ScheduledCreated:  true,
+	}
 
-	return nil
+	if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+		transaction.RelatedAccountId = template.RelatedAccountId
+		transaction.RelatedAccountAmount = relatedAccountAmount
+	}
+
+	if models.IsTransactionTimeLocked(transaction.TransactionTime, lockTime) {
+		log.Infof(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" does not need to create transaction, the transaction time is before the transaction lock date", template.TemplateId)
+		return nil, nil
+	}
+
+	tagIds := template.GetTagIds()
+	err = s.createTransaction(c, transaction, tagIds, nil, nil, &lockTime, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func (s *TransactionService) getAmortizationInstallmentAmount(c core.Context, template *models.TransactionTemplate, transactionTime time.Time) (int64, error) {
+	schedule := &models.AmortizationSchedule{}
+	has, err := s.UserDataDB(template.Uid).NewSession(c).ID(template.AmortizationScheduleId).Where("uid=? AND ledger_id=? AND deleted=?", template.Uid, template.LedgerId, false).Get(schedule)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrAmortizationScheduleNotFound
+	}
+
+	return schedule.GetInstallmentAmount(template.TemplateId, transactionTime.Year(), transactionTime.Month())
 }
 
 // ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, splits []*models.TransactionSplit, operator *models.TransactionHistoryOperator) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, transaction.Uid, transaction.LedgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	needTagIndexUuidCount := uint16(len(addTagIds))
 	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
 
@@ -846,6 +1102,13 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return errs.ErrSystemIsBusy
 	}
 
+	needSplitUuidCount := uint16(len(splits))
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, needSplitUuidCount)
+
+	if len(splitUuids) < int(needSplitUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
 	updateCols := make([]string, 0, 16)
 
 	now := time.Now().Unix()
@@ -871,10 +1134,16 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
-	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+	if len(splits) > 0 {
+		transaction.CategoryId = splits[0].CategoryId
+	}
+
+	transactionSplits := s.buildTransactionSplits(transaction, splits, splitUuids, now)
+
+	err = s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
+		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(oldTransaction)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction, because %s", err.Error())
@@ -883,6 +1152,26 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrTransactionNotFound
 		}
 
+		reconciled, err := s.isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get reconciliation status of transaction, because %s", err.Error())
+			return err
+		} else if reconciled {
+			return errs.ErrCannotModifyReconciledTransaction
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, transaction.Uid, transaction.LedgerId)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get transaction lock time, because %s", err.Error())
+			return err
+		}
+
+		if models.IsTransactionTimeLocked(oldTransaction.TransactionTime, lockTime) || models.IsTransactionTimeLocked(transaction.TransactionTime, lockTime) {
+			return errs.ErrCannotModifyTransactionBeforeLockTime
+		}
+
 		transaction.Type = oldTransaction.Type
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
@@ -945,6 +1234,17 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "category_id")
 		}
 
+		if transaction.PayeeId != oldTransaction.PayeeId {
+			// Get and verify payee
+			err = s.isPayeeValid(sess, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			updateCols = append(updateCols, "payee_id")
+		}
+
 		modifyTransactionTime := false
 
 		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
@@ -1016,6 +1316,13 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "geo_latitude")
 		}
 
+		// Get and verify splits
+		err = s.isSplitsValid(sess, transaction, transactionSplits)
+
+		if err != nil {
+			return err
+		}
+
 		// Get and verify tags
 		err = s.isTagsValid(sess, transaction, transactionTagIndexes, addTagIds)
 
@@ -1119,6 +1426,33 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction splits
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME || oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			splitUpdateModel := &models.TransactionSplit{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction splits, because %s", err.Error())
+				return err
+			}
+
+			for i := 0; i < len(transactionSplits); i++ {
+				transactionSplit := transactionSplits[i]
+				transactionSplit.TransactionTime = transaction.TransactionTime
+
+				_, err := sess.Insert(transactionSplit)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction split, because %s", err.Error())
+					return err
+				}
+			}
+		}
+
 		// Update transaction picture
 		if len(removePictureIds) > 0 {
 			pictureUpdateModel := &models.TransactionPictureInfo{
@@ -1312,33 +1646,286 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrTransactionTypeInvalid
 		}
 
-		return nil
-	})
+		// Insert transaction history
+		newTransaction := &models.Transaction{}
+		has, err = sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(newTransaction)
 
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get modified transaction, because %s", err.Error())
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
 
-	return nil
-}
+		changes := models.GetTransactionHistoryFieldChanges(oldTransaction, newTransaction)
+		tagIdsChange := models.GetTransactionHistoryTagIdsFieldChange(removeTagIds, addTagIds)
 
-func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context, uid int64, fromAccountId int64, toAccountId int64) error {
-	if uid <= 0 {
-		return errs.ErrUserIdInvalid
+		if tagIdsChange != nil {
+			changes = append(changes, tagIdsChange)
+		}
+
+		if len(changes) > 0 {
+			history := s.createTransactionHistoryModel(oldTransaction, models.TRANSACTION_HISTORY_ACTION_MODIFY, changes, operator, now)
+			err = s.insertTransactionHistories(sess, []*models.TransactionHistory{history})
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to add transaction history, because %s", err.Error())
+				return err
+			}
+		}
+
+		// Invalidate net worth snapshots
+		err = NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, transaction.Uid, []*models.Transaction{oldTransaction, newTransaction}, now)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to invalidate net worth snapshots, because %s", err.Error())
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ModifyTransactionsByRuleTargets updates the category, comment, hide amount and tags of the given transactions to the values of the transaction rule targets at the same index,
+// the transactions which cannot be modified (e.g. reconciled or locked) are skipped, and returns the count of updated transactions
+func (s *TransactionService) ModifyTransactionsByRuleTargets(c core.Context, uid int64, ledgerId int64, transactions []*models.Transaction, targets []*models.TransactionRuleTarget, allTransactionTagIds map[int64][]int64, operator *models.TransactionHistoryOperator) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if len(transactions) != len(targets) {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	if len(transactions) < 1 {
+		return 0, nil
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return 0, err
+	}
+
+	allAddTagIds := make([][]int64, len(transactions))
+	needTagIndexUuidCount := 0
+
+	for i := 0; i < len(transactions); i++ {
+		allAddTagIds[i] = utils.ToUniqueInt64Slice(utils.Int64SliceMinus(targets[i].TagIds, allTransactionTagIds[transactions[i].TransactionId]))
+		needTagIndexUuidCount += len(allAddTagIds[i])
+	}
+
+	if needTagIndexUuidCount > math.MaxUint16 {
+		return 0, errs.ErrSystemIsBusy
+	}
+
+	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, uint16(needTagIndexUuidCount))
+
+	if len(tagIndexUuids) < needTagIndexUuidCount {
+		return 0, errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+	updatedCount := 0
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedCount = 0
+		tagIndexUuidIndex := 0
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to get transaction lock time, because %s", err.Error())
+			return err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			target := targets[i]
+			addTagIds := allAddTagIds[i]
+
+			oldTransaction := &models.Transaction{}
+			has, err := sess.ID(transactions[i].TransactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(oldTransaction)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to get current transaction, because %s", err.Error())
+				return err
+			} else if !has {
+				continue
+			}
+
+			reconciled, err := s.isTransactionReconciled(sess, oldTransaction)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to get reconciliation status of transaction, because %s", err.Error())
+				return err
+			} else if reconciled || models.IsTransactionTimeLocked(oldTransaction.TransactionTime, lockTime) {
+				continue
+			}
+
+			newTransaction := *oldTransaction
+			newTransaction.Comment = target.Comment
+			newTransaction.HideAmount = target.HideAmount
+			newTransaction.UpdatedUnixTime = now
+			updateCols := []string{"updated_unix_time"}
+
+			// the category of transaction with splits is determined by its splits, so it would not be changed here
+			if target.CategoryId != oldTransaction.CategoryId && !target.HasSplits {
+				newTransaction.CategoryId = target.CategoryId
+				err = s.isCategoryValid(sess, &newTransaction)
+
+				if err != nil && errs.IsCustomError(err) {
+					continue
+				} else if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to get category, because %s", err.Error())
+					return err
+				}
+
+				updateCols = append(updateCols, "category_id")
+			}
+
+			if newTransaction.Comment != oldTransaction.Comment {
+				updateCols = append(updateCols, "comment")
+			}
+
+			if newTransaction.HideAmount != oldTransaction.HideAmount {
+				updateCols = append(updateCols, "hide_amount")
+			}
+
+			transactionTagIndexes := make([]*models.TransactionTagIndex, len(addTagIds))
+
+			for j := 0; j < len(addTagIds); j++ {
+				transactionTagIndexes[j] = &models.TransactionTagIndex{
+					TagIndexId:      tagIndexUuids[tagIndexUuidIndex+j],
+					Uid:             uid,
+					Deleted:         false,
+					TagId:           addTagIds[j],
+					TransactionId:   oldTransaction.TransactionId,
+					TransactionTime: oldTransaction.TransactionTime,
+					CreatedUnixTime: now,
+					UpdatedUnixTime: now,
+				}
+			}
+
+			tagIndexUuidIndex += len(addTagIds)
+			err = s.isTagsValid(sess, &newTransaction, transactionTagIndexes, addTagIds)
+
+			if err != nil && errs.IsCustomError(err) {
+				continue
+			} else if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to get tags, because %s", err.Error())
+				return err
+			}
+
+			if len(updateCols) < 2 && len(transactionTagIndexes) < 1 {
+				continue
+			}
+
+			// Update transaction row
+			_, err = sess.ID(oldTransaction.TransactionId).Cols(updateCols...).Where("uid=? AND deleted=?", uid, false).Update(&newTransaction)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to update transaction, because %s", err.Error())
+				return err
+			}
+
+			if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				relatedTransaction := &models.Transaction{
+					CategoryId:      newTransaction.CategoryId,
+					Comment:         newTransaction.Comment,
+					HideAmount:      newTransaction.HideAmount,
+					UpdatedUnixTime: now,
+				}
+
+				_, err = sess.ID(oldTransaction.RelatedId).Cols(updateCols...).Where("uid=? AND deleted=?", uid, false).Update(relatedTransaction)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to update related transaction, because %s", err.Error())
+					return err
+				}
+			}
+
+			// Insert transaction tag index
+			for j := 0; j < len(transactionTagIndexes); j++ {
+				_, err = sess.Insert(transactionTagIndexes[j])
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to add new transaction tag index, because %s", err.Error())
+					return err
+				}
+			}
+
+			// Insert transaction history
+			oldTagIds := allTransactionTagIds[oldTransaction.TransactionId]
+			newTagIds := make([]int64, 0, len(oldTagIds)+len(addTagIds))
+			newTagIds = append(newTagIds, oldTagIds...)
+			newTagIds = append(newTagIds, addTagIds...)
+
+			changes := models.GetTransactionHistoryFieldChanges(oldTransaction, &newTransaction)
+			tagIdsChange := models.GetTransactionHistoryTagIdsFieldChange(oldTagIds, newTagIds)
+
+			if tagIdsChange != nil {
+				changes = append(changes, tagIdsChange)
+			}
+
+			if len(changes) > 0 {
+				history := s.createTransactionHistoryModel(oldTransaction, models.TRANSACTION_HISTORY_ACTION_MODIFY, changes, operator, now)
+				err = s.insertTransactionHistories(sess, []*models.TransactionHistory{history})
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransactionsByRuleTargets] failed to add transaction history, because %s", err.Error())
+					return err
+				}
+			}
+
+			updatedCount++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return updatedCount, nil
+}
+
+func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context, uid int64, ledgerId int64, fromAccountId int64, toAccountId int64, operator *models.TransactionHistoryOperator) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
 	}
 
 	if fromAccountId <= 0 || toAccountId <= 0 {
 		return errs.ErrAccountIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	if fromAccountId == toAccountId {
 		return errs.ErrCannotMoveTransactionToSameAccount
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to get transaction lock time, because %s", err.Error())
+			return err
+		}
+
 		// get and verify from and to account
 		fromAccount := &models.Account{}
-		has, err := sess.ID(fromAccountId).Where("uid=? AND deleted=?", uid, false).Get(fromAccount)
+		has, err := sess.ID(fromAccountId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(fromAccount)
 
 		if err != nil {
 			return err
@@ -1347,7 +1934,7 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 		}
 
 		toAccount := &models.Account{}
-		has, err = sess.ID(toAccountId).Where("uid=? AND deleted=?", uid, false).Get(toAccount)
+		has, err = sess.ID(toAccountId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(toAccount)
 
 		if err != nil {
 			return err
@@ -1367,6 +1954,34 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 			return errs.ErrCannotMoveTransactionBetweenAccountsWithDifferentCurrencies
 		}
 
+		// the reconciled transactions in from account and the balance modification transaction in to account cannot be changed
+		reconciledCount, err := sess.Where("uid=? AND deleted=? AND reconciliation_status=? AND (account_id=? OR (type=? AND account_id=?))", uid, false, models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED, fromAccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, toAccountId).Count(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if reconciledCount > 0 {
+			return errs.ErrCannotMoveReconciledTransaction
+		}
+
+		// the transactions before the transaction lock date in from account and the balance modification transaction in to account cannot be changed
+		if lockTime > 0 {
+			lockedCount, err := sess.Where("uid=? AND deleted=? AND transaction_time<? AND (account_id=? OR (type=? AND account_id=?))", uid, false, utils.GetMinTransactionTimeFromUnixTime(lockTime), fromAccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, toAccountId).Count(&models.Transaction{})
+
+			if err != nil {
+				return err
+			} else if lockedCount > 0 {
+				return errs.ErrCannotMoveTransactionBeforeLockTime
+			}
+		}
+
+		// get all transactions which will be changed for transaction history
+		var oldTransactions []*models.Transaction
+		err = sess.Where("uid=? AND deleted=? AND type<>? AND (account_id=? OR related_account_id=? OR (type=? AND account_id=?))", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN, fromAccountId, fromAccountId, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, toAccountId).Find(&oldTransactions)
+
+		if err != nil {
+			return err
+		}
+
 		// combine balance modification transaction
 		var balanceModificationTransactions []*models.Transaction
 		err = sess.Where("uid=? AND deleted=? AND type=? AND (account_id=? OR account_id=?)", uid, false, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, fromAccountId, toAccountId).Find(&balanceModificationTransactions)
@@ -1531,16 +2146,67 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 			}
 		}
 
+		// insert transaction histories
+		if len(oldTransactions) > 0 {
+			var newTransactions []*models.Transaction
+			err = sess.Where("uid=?", uid).In("transaction_id", s.GetTransactionIds(oldTransactions)).Find(&newTransactions)
+
+			if err != nil {
+				return err
+			}
+
+			newTransactionMap := s.GetTransactionMapByList(newTransactions)
+			histories := make([]*models.TransactionHistory, 0, len(oldTransactions))
+			now := time.Now().Unix()
+
+			for i := 0; i < len(oldTransactions); i++ {
+				oldTransaction := oldTransactions[i]
+				newTransaction, exists := newTransactionMap[oldTransaction.TransactionId]
+
+				if !exists {
+					continue
+				}
+
+				if newTransaction.Deleted {
+					changes := models.GetTransactionHistoryFieldChanges(oldTransaction, nil)
+					histories = append(histories, s.createTransactionHistoryModel(oldTransaction, models.TRANSACTION_HISTORY_ACTION_DELETE, changes, operator, now))
+				} else if changes := models.GetTransactionHistoryFieldChanges(oldTransaction, newTransaction); len(changes) > 0 {
+					histories = append(histories, s.createTransactionHistoryModel(oldTransaction, models.TRANSACTION_HISTORY_ACTION_MOVE, changes, operator, now))
+				}
+			}
+
+			err = s.insertTransactionHistories(sess, histories)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to add transaction histories, because %s", err.Error())
+				return err
+			}
+		}
+
+		// Invalidate all net worth snapshots of the ledger, because the balances of both accounts are changed since their first transactions
+		err = NetWorthSnapshots.invalidateSnapshots(sess, uid, ledgerId, 0, time.Now().Unix())
+
+		if err != nil {
+			log.Errorf(c, "[transactions.MoveAllTransactionsBetweenAccounts] failed to invalidate net worth snapshots, because %s", err.Error())
+			return err
+		}
+
 		return nil
 	})
 }
 
 // DeleteTransaction deletes an existed transaction from database
-func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transactionId int64) error {
+func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, ledgerId int64, transactionId int64, operator *models.TransactionHistoryOperator) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().Unix()
 
 	updateModel := &models.Transaction{
@@ -1553,6 +2219,11 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	pictureUpdateModel := &models.TransactionPictureInfo{
 		Deleted:         true,
 		DeletedUnixTime: now,
@@ -1561,7 +2232,7 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
-		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
+		has, err := sess.ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Get(oldTransaction)
 
 		if err != nil {
 			return err
@@ -1569,6 +2240,24 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return errs.ErrTransactionNotFound
 		}
 
+		reconciled, err := s.isTransactionReconciled(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		} else if reconciled {
+			return errs.ErrCannotDeleteReconciledTransaction
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		if models.IsTransactionTimeLocked(oldTransaction.TransactionTime, lockTime) {
+			return errs.ErrCannotDeleteTransactionBeforeLockTime
+		}
+
 		// Get and verify source and destination account
 		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
 
@@ -1603,6 +2292,35 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			}
 		}
 
+		// Insert transaction history
+		var tagIndexes []*models.TransactionTagIndex
+		err = sess.Cols("tag_id").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Find(&tagIndexes)
+
+		if err != nil {
+			return err
+		}
+
+		changes := models.GetTransactionHistoryFieldChanges(oldTransaction, nil)
+		tagIdsChange := models.GetTransactionHistoryTagIdsFieldChange(s.getTagIdsFromTagIndexes(tagIndexes), nil)
+
+		if tagIdsChange != nil {
+			changes = append(changes, tagIdsChange)
+		}
+
+		history := s.createTransactionHistoryModel(oldTransaction, models.TRANSACTION_HISTORY_ACTION_DELETE, changes, operator, now)
+		err = s.insertTransactionHistories(sess, []*models.TransactionHistory{history})
+
+		if err != nil {
+			return err
+		}
+
+		// Invalidate net worth snapshots
+		err = NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, uid, []*models.Transaction{oldTransaction}, now)
+
+		if err != nil {
+			return err
+		}
+
 		// Update transaction tag index
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(tagIndexUpdateModel)
 
@@ -1610,6 +2328,13 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return err
 		}
 
+		// Update transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update transaction picture
 		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(pictureUpdateModel)
 
@@ -1686,59 +2411,621 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 	})
 }
 
-// DeleteAllTransactions deletes all existed transactions from database
-func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, deleteAccount bool) error {
+// SetTransactionsReconciliationStatus sets the reconciliation status of the specified transactions in one account, the reconciled transactions would be changed only if unlock is true
+func (s *TransactionService) SetTransactionsReconciliationStatus(c core.Context, uid int64, ledgerId int64, accountId int64, transactionIds []int64, status models.TransactionReconciliationStatus, unlock bool) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	now := time.Now().Unix()
+	if accountId <= 0 {
+		return errs.ErrAccountIdInvalid
+	}
 
-	updateModel := &models.Transaction{
-		Deleted:         true,
-		DeletedUnixTime: now,
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
 	}
 
-	tagIndexUpdateModel := &models.TransactionTagIndex{
-		Deleted:         true,
-		DeletedUnixTime: now,
+	if status != models.TRANSACTION_RECONCILIATION_STATUS_PENDING && status != models.TRANSACTION_RECONCILIATION_STATUS_CLEARED {
+		return errs.ErrTransactionReconciliationStatusInvalid
 	}
 
-	pictureUpdateModel := &models.TransactionPictureInfo{
-		Deleted:         true,
-		DeletedUnixTime: now,
-	}
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	if len(transactionIds) < 1 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	updateModel := &models.Transaction{
+		ReconciliationStatus: status,
+		UpdatedUnixTime:      time.Now().Unix(),
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var transactions []*models.Transaction
+		err := sess.Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("transaction_id", transactionIds).Find(&transactions)
+
+		if err != nil {
+			return err
+		} else if len(transactions) != len(transactionIds) {
+			return errs.ErrTransactionNotFound
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		if isAnyTransactionTimeLocked(transactions, lockTime) {
+			return errs.ErrCannotModifyTransactionBeforeLockTime
+		}
+
+		// the transfer transaction is identified by the transfer out transaction, so use the transfer in transaction if the account is the destination account
+		accountTransactionIds := make([]int64, len(transactions))
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+
+			if transaction.AccountId == accountId {
+				accountTransactionIds[i] = transaction.TransactionId
+			} else if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && transaction.RelatedAccountId == accountId {
+				accountTransactionIds[i] = transaction.RelatedId
+			} else {
+				return errs.ErrTransactionNotFound
+			}
+		}
+
+		var accountTransactions []*models.Transaction
+		err = sess.Cols("transaction_id", "reconciliation_status").Where("uid=? AND ledger_id=? AND deleted=? AND account_id=?", uid, ledgerId, false, accountId).In("transaction_id", accountTransactionIds).Find(&accountTransactions)
+
+		if err != nil {
+			return err
+		} else if len(accountTransactions) != len(accountTransactionIds) {
+			return errs.ErrTransactionNotFound
+		}
+
+		for i := 0; i < len(accountTransactions); i++ {
+			if accountTransactions[i].ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED && !unlock {
+				return errs.ErrReconciledTransactionNotUnlocked
+			}
+		}
+
+		_, err = sess.Cols("reconciliation_status", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=? AND account_id=?", uid, ledgerId, false, accountId).In("transaction_id", accountTransactionIds).Update(updateModel)
+
+		return err
+	})
+}
+
+// ReconcileTransactions marks all cleared transactions in one account not later than the statement time as reconciled if the balance of all cleared and reconciled transactions equals to the statement ending balance, and returns the count of reconciled transactions and the cleared balance
+func (s *TransactionService) ReconcileTransactions(c core.Context, uid int64, ledgerId int64, accountId int64, maxTransactionTime int64, endingBalance int64) (int64, int64, error) {
+	if uid <= 0 {
+		return 0, 0, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return 0, 0, errs.ErrAccountIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	updateModel := &models.Transaction{
+		ReconciliationStatus: models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED,
+		UpdatedUnixTime:      time.Now().Unix(),
+	}
+
+	reconciledCount := int64(0)
+	clearedBalance := int64(0)
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var transactions []*models.Transaction
+		err := sess.Cols("transaction_id", "type", "amount", "related_account_amount").Where("uid=? AND ledger_id=? AND deleted=? AND account_id=? AND transaction_time<=? AND reconciliation_status>=?", uid, ledgerId, false, accountId, maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED).Find(&transactions)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			amount, err := transactions[i].GetAccountBalanceChangedAmount()
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ReconcileTransactions] transaction type (%d) is invalid (id:%d)", transactions[i].Type, transactions[i].TransactionId)
+				return err
+			}
+
+			clearedBalance += amount
+		}
+
+		if clearedBalance != endingBalance {
+			log.Warnf(c, "[transactions.ReconcileTransactions] cleared balance %d of account \"id:%d\" does not match ending balance %d for user \"uid:%d\"", clearedBalance, accountId, endingBalance, uid)
+			return errs.ErrReconciliationEndingBalanceNotMatch
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		// the cleared transactions before transaction lock date are counted in the cleared balance, but they are kept unchanged
+		reconciledCount, err = sess.Cols("reconciliation_status", "updated_unix_time").Where("uid=? AND ledger_id=? AND deleted=? AND account_id=? AND transaction_time>=? AND transaction_time<=? AND reconciliation_status=?", uid, ledgerId, false, accountId, models.GetMinUnlockedTransactionTime(lockTime), maxTransactionTime, models.TRANSACTION_RECONCILIATION_STATUS_CLEARED).Update(updateModel)
+
+		return err
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return reconciledCount, clearedBalance, nil
+}
+
+// GetDeletedTransactionsByPage returns deleted transaction models in the trash bin of user
+func (s *TransactionService) GetDeletedTransactionsByPage(c core.Context, uid int64, ledgerId int64, page int32, count int32) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if page < 0 {
+		return nil, errs.ErrPageIndexInvalid
+	} else if page == 0 {
+		page = 1
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	var transactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND ledger_id=? AND deleted=? AND type<>?", uid, ledgerId, true, models.TRANSACTION_DB_TYPE_TRANSFER_IN).OrderBy("deleted_unix_time desc, transaction_time desc").Limit(int(count), int(count*(page-1))).Find(&transactions)
+
+	return transactions, err
+}
+
+// GetDeletedTransactionByTransactionId returns a deleted transaction model in the trash bin according to transaction id
+func (s *TransactionService) GetDeletedTransactionByTransactionId(c core.Context, uid int64, ledgerId int64, transactionId int64) (*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	transaction := &models.Transaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionNotFound
+	}
+
+	return transaction, nil
+}
+
+// RestoreTransaction restores a deleted transaction from the trash bin, re-applies its account balance changes and rebuilds its tag indexes
+func (s *TransactionService) RestoreTransaction(c core.Context, uid int64, ledgerId int64, transactionId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_WRITE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Transaction{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		Deleted:         false,
+		DeletedUnixTime: 0,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// Get and verify deleted transaction
+		oldTransaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(oldTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		if models.IsTransactionTimeLocked(oldTransaction.TransactionTime, lockTime) {
+			return errs.ErrCannotRestoreTransactionBeforeLockTime
+		}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			return errs.ErrTransactionTypeInvalid
+		}
+
+		// Get and verify source and destination account
+		sourceAccount, destinationAccount, err := s.getAccountModels(sess, oldTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+			return errs.ErrCannotAddTransactionToHiddenAccount
+		}
+
+		if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+			return errs.ErrCannotAddTransactionToParentAccount
+		}
+
+		// Verify category
+		if oldTransaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			exists, err := sess.Cols("category_id", "uid", "ledger_id", "deleted").Where("category_id=? AND uid=? AND ledger_id=? AND deleted=?", oldTransaction.CategoryId, uid, ledgerId, false).Exist(&models.TransactionCategory{})
+
+			if err != nil {
+				return err
+			} else if !exists {
+				return errs.ErrTransactionCategoryNotFound
+			}
+		} else {
+			otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
+
+			if err != nil {
+				return err
+			} else if otherTransactionExists {
+				return errs.ErrBalanceModificationTransactionCannotAddWhenNotEmpty
+			}
+		}
+
+		// Update transaction row to not deleted
+		restoredRows, err := sess.ID(oldTransaction.TransactionId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if restoredRows < 1 {
+			return errs.ErrTransactionNotFound
+		}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			restoredRows, err = sess.ID(oldTransaction.RelatedId).Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=?", uid, true).Update(updateModel)
+
+			if err != nil {
+				return err
+			} else if restoredRows < 1 {
+				return errs.ErrTransactionNotFound
+			}
+		}
+
+		// Rebuild transaction tag index
+		var oldTagIndexes []*models.TransactionTagIndex
+		err = sess.Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, oldTransaction.DeletedUnixTime).Find(&oldTagIndexes)
+
+		if err != nil {
+			return err
+		}
+
+		if len(oldTagIndexes) > 0 {
+			oldTagIds := make([]int64, len(oldTagIndexes))
+
+			for i := 0; i < len(oldTagIndexes); i++ {
+				oldTagIds[i] = oldTagIndexes[i].TagId
+			}
+
+			var tags []*models.TransactionTag
+			err = sess.Cols("tag_id", "uid", "ledger_id", "deleted").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).In("tag_id", utils.ToUniqueInt64Slice(oldTagIds)).Find(&tags)
+
+			if err != nil {
+				return err
+			}
+
+			if len(tags) > 0 {
+				tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, uint16(len(tags)))
+
+				if len(tagIndexUuids) < len(tags) {
+					return errs.ErrSystemIsBusy
+				}
+
+				for i := 0; i < len(tags); i++ {
+					tagIndex := &models.TransactionTagIndex{
+						TagIndexId:      tagIndexUuids[i],
+						Uid:             uid,
+						Deleted:         false,
+						TagId:           tags[i].TagId,
+						TransactionId:   oldTransaction.TransactionId,
+						TransactionTime: oldTransaction.TransactionTime,
+						CreatedUnixTime: now,
+						UpdatedUnixTime: now,
+					}
+
+					_, err = sess.Insert(tagIndex)
+
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			_, err = sess.Where("uid=? AND deleted=? AND transaction_id=?", uid, true, oldTransaction.TransactionId).Delete(&models.TransactionTagIndex{})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Update transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, oldTransaction.DeletedUnixTime).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Update transaction picture
+		_, err = sess.Cols("deleted", "deleted_unix_time", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=? AND deleted_unix_time=?", uid, true, oldTransaction.TransactionId, oldTransaction.DeletedUnixTime).Update(pictureUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		// Invalidate net worth snapshots
+		err = NetWorthSnapshots.invalidateSnapshotsByTransactions(sess, uid, []*models.Transaction{oldTransaction}, now)
+
+		if err != nil {
+			return err
+		}
+
+		// Update account table
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			if oldTransaction.RelatedAccountAmount != 0 {
+				sourceAccount.UpdatedUnixTime = time.Now().Unix()
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = time.Now().Unix()
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = time.Now().Unix()
+				updatedRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedRows < 1 {
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+		} else if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if oldTransaction.Amount != 0 {
+				sourceAccount.UpdatedUnixTime = time.Now().Unix()
+				updatedSourceRows, err := sess.ID(sourceAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance-(%d)", oldTransaction.Amount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", sourceAccount.Uid, false).Update(sourceAccount)
+
+				if err != nil {
+					return err
+				} else if updatedSourceRows < 1 {
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+
+			if oldTransaction.RelatedAccountAmount != 0 {
+				destinationAccount.UpdatedUnixTime = time.Now().Unix()
+				updatedDestinationRows, err := sess.ID(destinationAccount.AccountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", oldTransaction.RelatedAccountAmount)).Cols("updated_unix_time").Where("uid=? AND deleted=?", destinationAccount.Uid, false).Update(destinationAccount)
+
+				if err != nil {
+					return err
+				} else if updatedDestinationRows < 1 {
+					log.Errorf(c, "[transactions.RestoreTransaction] failed to update related account balance")
+					return errs.ErrDatabaseOperationFailed
+				}
+			}
+		}
+
+		return err
+	})
+}
+
+// PurgeTransaction permanently deletes a deleted transaction and its related data from the trash bin
+func (s *TransactionService) PurgeTransaction(c core.Context, uid int64, ledgerId int64, transactionId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return errs.ErrTransactionIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldTransaction := &models.Transaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, true).Get(oldTransaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionNotFound
+		}
+
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		if models.IsTransactionTimeLocked(oldTransaction.TransactionTime, lockTime) {
+			return errs.ErrCannotDeleteTransactionBeforeLockTime
+		}
+
+		transactionIds := []int64{oldTransaction.TransactionId}
+
+		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			transactionIds = append(transactionIds, oldTransaction.RelatedId)
+		}
+
+		return purgeDeletedTransactionsByIds(sess, uid, transactionIds)
+	})
+}
+
+// DeleteAllTransactions deletes all existed transactions from database
+func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, ledgerId int64, deleteAccount bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	tagIndexUpdateModel := &models.TransactionTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	accountUpdateModel := &models.Account{
+		Balance:         0,
+		Deleted:         deleteAccount,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		lockTime, err := s.getTransactionLockTime(c, sess, uid, ledgerId)
+
+		if err != nil {
+			return err
+		}
+
+		if lockTime > 0 {
+			lockedTransactionCount, err := sess.Where("uid=? AND ledger_id=? AND deleted=? AND transaction_time<?", uid, ledgerId, false, models.GetMinUnlockedTransactionTime(lockTime)).Count(&models.Transaction{})
+
+			if err != nil {
+				return err
+			} else if lockedTransactionCount > 0 {
+				return errs.ErrCannotDeleteTransactionBeforeLockTime
+			}
+		}
+
+		for {
+			var transactions []*models.Transaction
+			err := sess.Cols("transaction_id").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Limit(pageCountForLoadTransactionAmounts, 0).Find(&transactions)
+
+			if err != nil {
+				return err
+			}
+
+			if len(transactions) < 1 {
+				break
+			}
+
+			transactionIds := s.GetTransactionIds(transactions)
+
+			// Update all transactions to deleted
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Update(updateModel)
+
+			if err != nil {
+				return err
+			}
+
+			// Update all transaction tag index to deleted
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Update(tagIndexUpdateModel)
+
+			if err != nil {
+				return err
+			}
 
-	accountUpdateModel := &models.Account{
-		Balance:         0,
-		Deleted:         deleteAccount,
-		DeletedUnixTime: now,
-	}
+			// Update all transaction splits to deleted
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Update(splitUpdateModel)
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
-		// Update all transactions to deleted
-		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+			if err != nil {
+				return err
+			}
 
-		if err != nil {
-			return err
-		}
+			// Update all transaction pictures to deleted
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Update(pictureUpdateModel)
 
-		// Update all transaction tag index to deleted
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(tagIndexUpdateModel)
+			if err != nil {
+				return err
+			}
 
-		if err != nil {
-			return err
+			if len(transactions) < pageCountForLoadTransactionAmounts {
+				break
+			}
 		}
 
-		// Update all transaction pictures to deleted
-		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(pictureUpdateModel)
+		// Update all accounts to deleted or set amount to zero
+		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND ledger_id=? AND deleted=?", uid, ledgerId, false).Update(accountUpdateModel)
 
 		if err != nil {
 			return err
 		}
 
-		// Update all accounts to deleted or set amount to zero
-		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
+		// Invalidate all net worth snapshots of the ledger
+		err = NetWorthSnapshots.invalidateSnapshots(sess, uid, ledgerId, 0, now)
 
 		if err != nil {
 			return err
@@ -1749,7 +3036,7 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 }
 
 // DeleteAllTransactionsOfAccount deletes all existed transactions of specific account from database
-func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid int64, accountId int64, pageCount int32) error {
+func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid int64, ledgerId int64, accountId int64, pageCount int32, operator *models.TransactionHistoryOperator) error {
 	if uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -1758,7 +3045,13 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		return errs.ErrAccountIdInvalid
 	}
 
-	transactions, err := s.GetAllSpecifiedTransactions(c, uid, 0, 0, 0, nil, []int64{accountId}, nil, false, "", "", pageCount, true)
+	err := checkLedgerPermission(c, uid, ledgerId, core.LEDGER_PERMISSION_MANAGE)
+
+	if err != nil {
+		return err
+	}
+
+	transactions, err := s.GetAllSpecifiedTransactions(c, uid, ledgerId, 0, 0, 0, nil, []int64{accountId}, nil, false, "", "", pageCount, true)
 
 	if err != nil {
 		return err
@@ -1772,9 +3065,9 @@ func (s *TransactionService) DeleteAllTransactionsOfAccount(c core.Context, uid
 		transaction := transactions[i]
 
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			err = s.DeleteTransaction(c, uid, transaction.RelatedId)
+			err = s.DeleteTransaction(c, uid, ledgerId, transaction.RelatedId, operator)
 		} else {
-			err = s.DeleteTransaction(c, uid, transaction.TransactionId)
+			err = s.DeleteTransaction(c, uid, ledgerId, transaction.TransactionId, operator)
 		}
 
 		if err != nil {
@@ -1803,6 +3096,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 	relatedTransaction := &models.Transaction{
 		TransactionId:        originalTransaction.RelatedId,
 		Uid:                  originalTransaction.Uid,
+		LedgerId:             originalTransaction.LedgerId,
 		Deleted:              originalTransaction.Deleted,
 		Type:                 relatedType,
 		CategoryId:           originalTransaction.CategoryId,
@@ -1813,10 +3107,13 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		RelatedId:            originalTransaction.TransactionId,
 		RelatedAccountId:     originalTransaction.AccountId,
 		RelatedAccountAmount: originalTransaction.Amount,
+		PayeeId:              originalTransaction.PayeeId,
 		Comment:              originalTransaction.Comment,
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
 		CreatedIp:            originalTransaction.CreatedIp,
+		CreatedByUid:         originalTransaction.CreatedByUid,
+		ReconciliationStatus: originalTransaction.ReconciliationStatus,
 		CreatedUnixTime:      originalTransaction.CreatedUnixTime,
 		UpdatedUnixTime:      originalTransaction.UpdatedUnixTime,
 		DeletedUnixTime:      originalTransaction.DeletedUnixTime,
@@ -1826,7 +3123,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 }
 
 // GetAccountsTotalIncomeAndExpense returns the every accounts total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, excludeAccountIds []int64, excludeCategoryIds []int64, clientTimezone *time.Location, useTransactionTimezone bool) (map[int64]int64, map[int64]int64, error) {
+func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, uid int64, ledgerId int64, startUnixTime int64, endUnixTime int64, excludeAccountIds []int64, excludeCategoryIds []int64, clientTimezone *time.Location, useTransactionTimezone bool) (map[int64]int64, map[int64]int64, error) {
 	if uid <= 0 {
 		return nil, nil, errs.ErrUserIdInvalid
 	}
@@ -1840,9 +3137,10 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 	startTransactionTime := utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
 	endTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
 
-	condition := "uid=? AND deleted=? AND (type=? OR type=?)"
-	conditionParams := make([]any, 0, 4+len(excludeAccountIds)+len(excludeCategoryIds))
+	condition := "uid=? AND ledger_id=? AND deleted=? AND (type=? OR type=?)"
+	conditionParams := make([]any, 0, 5+len(excludeAccountIds))
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
@@ -1864,23 +3162,6 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 		conditionParams = append(conditionParams, accountIdConditionParams...)
 	}
 
-	if len(excludeCategoryIds) > 0 {
-		var categoryIdsCondition strings.Builder
-		categoryIdConditionParams := make([]any, 0, len(excludeCategoryIds))
-
-		for i := 0; i < len(excludeCategoryIds); i++ {
-			if i > 0 {
-				categoryIdsCondition.WriteString(",")
-			}
-
-			categoryIdsCondition.WriteString("?")
-			categoryIdConditionParams = append(categoryIdConditionParams, excludeCategoryIds[i])
-		}
-
-		condition = condition + " AND category_id NOT IN (" + categoryIdsCondition.String() + ")"
-		conditionParams = append(conditionParams, categoryIdConditionParams...)
-	}
-
 	condition = condition + " AND transaction_time>=? AND transaction_time<=?"
 
 	minTransactionTime := startTransactionTime
@@ -1895,7 +3176,7 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 		finalConditionParams = append(finalConditionParams, minTransactionTime)
 		finalConditionParams = append(finalConditionParams, maxTransactionTime)
 
-		err := s.UserDataDB(uid).NewSession(c).Select("type, account_id, transaction_time, timezone_utc_offset, amount").Where(condition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+		err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(condition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
 
 		if err != nil {
 			return nil, nil, err
@@ -1911,6 +3192,14 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactionSplits, err := s.getTransactionSplitsInTimeRange(c, uid, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allTransactions = s.expandTransactionSplits(allTransactions, allTransactionSplits)
+	excludeCategoryIdsMap := utils.ToSet(excludeCategoryIds)
 	incomeAmounts := make(map[int64]int64)
 	expenseAmounts := make(map[int64]int64)
 
@@ -1918,6 +3207,10 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 		transaction := allTransactions[i]
 		timeZone := clientTimezone
 
+		if _, excluded := excludeCategoryIdsMap[transaction.CategoryId]; excluded {
+			continue
+		}
+
 		if useTransactionTimezone {
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
@@ -1949,8 +3242,8 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 	return incomeAmounts, expenseAmounts, nil
 }
 
-// GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) ([]*models.Transaction, error) {
+// GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories (and payees if groupByPayee is true) total inflows and outflows amount by specific date range, and the amounts are converted by the amount converter if it is not nil
+func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, ledgerId int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool, groupByPayee bool, amountConverter models.TransactionAmountConverter) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1969,9 +3262,10 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 		endTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
 	}
 
-	condition := "uid=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
-	conditionParams := make([]any, 0, 6)
+	condition := "uid=? AND ledger_id=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
+	conditionParams := make([]any, 0, 7)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
@@ -2004,7 +3298,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, payee_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -2023,6 +3317,13 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactionSplits, err := s.getTransactionSplitsInTimeRange(c, uid, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactions = s.expandTransactionSplits(allTransactions, allTransactionSplits)
 	transactionTotalAmountsMap := make(map[string]*models.Transaction)
 
 	for i := 0; i < len(allTransactions); i++ {
@@ -2033,7 +3334,8 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		localDateTime := utils.FormatUnixTimeToNumericLocalDateTime(transactionUnixTime, timeZone)
 
 		if (startLocalDateTime > 0 && localDateTime < startLocalDateTime) || (endLocalDateTime > 0 && localDateTime > endLocalDateTime) {
 			continue
@@ -2045,6 +3347,13 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			groupKey = fmt.Sprintf("%d_%d_%d_%d", transaction.CategoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
 		}
 
+		payeeId := int64(0)
+
+		if groupByPayee {
+			payeeId = transaction.PayeeId
+			groupKey = fmt.Sprintf("%s_%d", groupKey, payeeId)
+		}
+
 		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
 
 		if !exists {
@@ -2053,13 +3362,18 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 				CategoryId:       transaction.CategoryId,
 				AccountId:        transaction.AccountId,
 				RelatedAccountId: transaction.RelatedAccountId,
+				PayeeId:          payeeId,
 				Amount:           0,
 			}
 
 			transactionTotalAmountsMap[groupKey] = totalAmounts
 		}
 
-		totalAmounts.Amount += transaction.Amount
+		if amountConverter != nil {
+			totalAmounts.Amount += amountConverter(transaction.AccountId, utils.FormatUnixTimeToNumericYearMonthDay(transactionUnixTime, timeZone), transaction.Amount)
+		} else {
+			totalAmounts.Amount += transaction.Amount
+		}
 	}
 
 	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
@@ -2071,8 +3385,8 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 	return transactionTotalAmounts, nil
 }
 
-// GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+// GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range, and the amounts are converted by the amount converter if it is not nil
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, ledgerId int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, clientTimezone *time.Location, useTransactionTimezone bool, amountConverter models.TransactionAmountConverter) (map[int32][]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -2096,9 +3410,10 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		}
 	}
 
-	condition := "uid=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
-	conditionParams := make([]any, 0, 6)
+	condition := "uid=? AND ledger_id=? AND deleted=? AND (type=? OR type=? OR type=? OR type=?)"
+	conditionParams := make([]any, 0, 7)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
 	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
@@ -2131,7 +3446,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -2150,6 +3465,13 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactionSplits, err := s.getTransactionSplitsInTimeRange(c, uid, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactions = s.expandTransactionSplits(allTransactions, allTransactionSplits)
 	startYearMonth := startYear*100 + startMonth
 	endYearMonth := endYear*100 + endMonth
 	transactionsMonthlyAmountsMap := make(map[string]*models.Transaction)
@@ -2163,7 +3485,8 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			timeZone = time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
 		}
 
-		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(transactionUnixTime, timeZone)
 
 		if (startYearMonth > 0 && yearMonth < startYearMonth) || (endYearMonth > 0 && yearMonth > endYearMonth) {
 			continue
@@ -2189,7 +3512,11 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 		}
 
-		transactionAmounts.Amount += transaction.Amount
+		if amountConverter != nil {
+			transactionAmounts.Amount += amountConverter(transaction.AccountId, utils.FormatUnixTimeToNumericYearMonthDay(transactionUnixTime, timeZone), transaction.Amount)
+		} else {
+			transactionAmounts.Amount += transaction.Amount
+		}
 	}
 
 	for groupKey, transaction := range transactionsMonthlyAmountsMap {
@@ -2231,7 +3558,7 @@ func (s *TransactionService) GetTransactionIds(transactions []*models.Transactio
 	return transactionIds
 }
 
-func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
+func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, transactionSplits []*models.TransactionSplit, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
 	// Get and verify source and destination account
 	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
@@ -2264,6 +3591,20 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify payee
+	err = s.isPayeeValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify splits
+	err = s.isSplitsValid(sess, transaction, transactionSplits)
+
+	if err != nil {
+		return err
+	}
+
 	// Get and verify tags
 	err = s.isTagsValid(sess, transaction, transactionTagIndexes, tagIds)
 
@@ -2402,6 +3743,21 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
+	// Insert transaction splits
+	if len(transactionSplits) > 0 {
+		for i := 0; i < len(transactionSplits); i++ {
+			transactionSplit := transactionSplits[i]
+			transactionSplit.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(transactionSplit)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction split, because %s", err.Error())
+				return err
+			}
+		}
+	}
+
 	// Update transaction picture
 	if len(pictureIds) > 0 {
 		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
@@ -2485,10 +3841,179 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 	return err
 }
 
-func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+func (s *TransactionService) createTransactionHistoryModel(transaction *models.Transaction, action models.TransactionHistoryAction, changes models.TransactionHistoryFieldChanges, operator *models.TransactionHistoryOperator, now int64) *models.TransactionHistory {
+	history := &models.TransactionHistory{
+		Uid:             transaction.Uid,
+		LedgerId:        transaction.LedgerId,
+		TransactionId:   transaction.TransactionId,
+		Action:          action,
+		Changes:         &changes,
+		OperatorUid:     transaction.Uid,
+		CreatedUnixTime: now,
+	}
+
+	if operator != nil {
+		history.OperatorUid = operator.Uid
+		history.OperatorTokenId = operator.TokenId
+		history.OperatorIp = operator.ClientIp
+	}
+
+	return history
+}
+
+func (s *TransactionService) insertTransactionHistories(sess *xorm.Session, histories []*models.TransactionHistory) error {
+	for i := 0; i < len(histories); i += math.MaxUint16 {
+		batchHistories := histories[i:min(i+math.MaxUint16, len(histories))]
+		historyUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, uint16(len(batchHistories)))
+
+		if len(historyUuids) < len(batchHistories) {
+			return errs.ErrSystemIsBusy
+		}
+
+		for j := 0; j < len(batchHistories); j++ {
+			batchHistories[j].HistoryId = historyUuids[j]
+
+			_, err := sess.Insert(batchHistories[j])
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) getTagIdsFromTagIndexes(tagIndexes []*models.TransactionTagIndex) []int64 {
+	tagIds := make([]int64, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tagIds[i] = tagIndexes[i].TagId
+	}
+
+	return tagIds
+}
+
+// purgeDeletedTransactionsByIds keeps the deleted picture infos, so that the picture files can be removed along with them when they expire in trash bin
+func purgeDeletedTransactionsByIds(sess *xorm.Session, uid int64, transactionIds []int64) error {
+	_, err := sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", transactionIds).Delete(&models.Transaction{})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", transactionIds).Delete(&models.TransactionTagIndex{})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Where("uid=? AND deleted=?", uid, true).In("transaction_id", transactionIds).Delete(&models.TransactionSplit{})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sess.Where("uid=?", uid).In("transaction_id", transactionIds).Delete(&models.TransactionHistory{})
+
+	return err
+}
+
+func (s *TransactionService) getTransactionSplitsInTimeRange(c core.Context, uid int64, minTransactionTime int64, maxTransactionTime int64) (map[int64][]*models.TransactionSplit, error) {
 	condition := "uid=? AND deleted=?"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+
+	if minTransactionTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, minTransactionTime)
+	}
+
+	if maxTransactionTime > 0 {
+		condition = condition + " AND transaction_time<=?"
+		conditionParams = append(conditionParams, maxTransactionTime)
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("transaction_id asc, display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	return allTransactionSplits, nil
+}
+
+func (s *TransactionService) expandTransactionSplits(transactions []*models.Transaction, allTransactionSplits map[int64][]*models.TransactionSplit) []*models.Transaction {
+	if len(allTransactionSplits) < 1 {
+		return transactions
+	}
+
+	expandedTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		splits, exists := allTransactionSplits[transaction.TransactionId]
+
+		if !exists || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE) {
+			expandedTransactions = append(expandedTransactions, transaction)
+			continue
+		}
+
+		for j := 0; j < len(splits); j++ {
+			expandedTransactions = append(expandedTransactions, &models.Transaction{
+				TransactionId:     transaction.TransactionId,
+				Uid:               transaction.Uid,
+				Type:              transaction.Type,
+				CategoryId:        splits[j].CategoryId,
+				AccountId:         transaction.AccountId,
+				PayeeId:           transaction.PayeeId,
+				TransactionTime:   transaction.TransactionTime,
+				TimezoneUtcOffset: transaction.TimezoneUtcOffset,
+				Amount:            splits[j].Amount,
+				HideAmount:        transaction.HideAmount,
+				Comment:           transaction.Comment,
+			})
+		}
+	}
+
+	return expandedTransactions
+}
+
+func (s *TransactionService) buildTransactionSplits(transaction *models.Transaction, splits []*models.TransactionSplit, splitUuids []int64, now int64) []*models.TransactionSplit {
+	transactionSplits := make([]*models.TransactionSplit, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		transactionSplits[i] = &models.TransactionSplit{
+			SplitId:         splitUuids[i],
+			Uid:             transaction.Uid,
+			Deleted:         false,
+			TransactionId:   transaction.TransactionId,
+			CategoryId:      splits[i].CategoryId,
+			Amount:          splits[i].Amount,
+			Comment:         splits[i].Comment,
+			DisplayOrder:    int32(i + 1),
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		}
+	}
+
+	return transactionSplits
+}
+
+func (s *TransactionService) buildTransactionQueryCondition(uid int64, ledgerId int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagFilters []*models.TransactionTagFilter, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+	condition := "uid=? AND ledger_id=? AND deleted=?"
 	conditionParams := make([]any, 0, 16)
 	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, ledgerId)
 	conditionParams = append(conditionParams, false)
 
 	if maxTransactionTime > 0 {
@@ -2554,6 +4079,7 @@ func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransa
 
 	if len(categoryIds) > 0 {
 		var conditions strings.Builder
+		categoryIdConditionParams := make([]any, 0, len(categoryIds))
 
 		for i := 0; i < len(categoryIds); i++ {
 			if i > 0 {
@@ -2561,14 +4087,15 @@ func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransa
 			}
 
 			conditions.WriteString("?")
-			conditionParams = append(conditionParams, categoryIds[i])
+			categoryIdConditionParams = append(categoryIdConditionParams, categoryIds[i])
 		}
 
-		if conditions.Len() > 1 {
-			condition = condition + " AND category_id IN (" + conditions.String() + ")"
-		} else {
-			condition = condition + " AND category_id = " + conditions.String()
-		}
+		// split transactions match if any of their splits uses the specified categories
+		condition = condition + " AND (category_id IN (" + conditions.String() + ") OR transaction_id IN (SELECT transaction_id FROM transaction_split WHERE uid=? AND deleted=? AND category_id IN (" + conditions.String() + ")))"
+		conditionParams = append(conditionParams, categoryIdConditionParams...)
+		conditionParams = append(conditionParams, uid)
+		conditionParams = append(conditionParams, false)
+		conditionParams = append(conditionParams, categoryIdConditionParams...)
 	}
 
 	if len(accountIds) > 0 {
@@ -2720,7 +4247,7 @@ func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *m
 	sourceAccount = &models.Account{}
 	destinationAccount = &models.Account{}
 
-	has, err := sess.ID(transaction.AccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(sourceAccount)
+	has, err := sess.ID(transaction.AccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(sourceAccount)
 
 	if err != nil {
 		return nil, nil, err
@@ -2745,7 +4272,7 @@ func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *m
 		if transaction.RelatedAccountId <= 0 {
 			return nil, nil, errs.ErrAccountIdInvalid
 		} else {
-			has, err = sess.ID(transaction.RelatedAccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(destinationAccount)
+			has, err = sess.ID(transaction.RelatedAccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(destinationAccount)
 
 			if err != nil {
 				return nil, nil, err
@@ -2758,7 +4285,7 @@ func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *m
 	// check whether the parent accounts are valid
 	if sourceAccount.ParentAccountId > 0 && destinationAccount != nil && sourceAccount.ParentAccountId != destinationAccount.ParentAccountId && destinationAccount.ParentAccountId > 0 {
 		var accounts []*models.Account
-		err := sess.Where("uid=? AND deleted=? and (account_id=? or account_id=?)", transaction.Uid, false, sourceAccount.ParentAccountId, destinationAccount.ParentAccountId).Find(&accounts)
+		err := sess.Where("uid=? AND ledger_id=? AND deleted=? and (account_id=? or account_id=?)", transaction.Uid, transaction.LedgerId, false, sourceAccount.ParentAccountId, destinationAccount.ParentAccountId).Find(&accounts)
 
 		if err != nil {
 			return nil, nil, err
@@ -2777,7 +4304,7 @@ func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *m
 		}
 	} else if sourceAccount.ParentAccountId > 0 && (destinationAccount == nil || sourceAccount.ParentAccountId == destinationAccount.ParentAccountId || destinationAccount.ParentAccountId == 0) {
 		sourceParentAccount := &models.Account{}
-		has, err = sess.ID(sourceAccount.ParentAccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(sourceParentAccount)
+		has, err = sess.ID(sourceAccount.ParentAccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(sourceParentAccount)
 
 		if err != nil {
 			return nil, nil, err
@@ -2790,7 +4317,7 @@ func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *m
 		}
 	} else if sourceAccount.ParentAccountId == 0 && destinationAccount != nil && destinationAccount.ParentAccountId > 0 {
 		destinationParentAccount := &models.Account{}
-		has, err = sess.ID(destinationAccount.ParentAccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(destinationParentAccount)
+		has, err = sess.ID(destinationAccount.ParentAccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(destinationParentAccount)
 
 		if err != nil {
 			return nil, nil, err
@@ -2813,7 +4340,7 @@ func (s *TransactionService) getOldAccountModels(sess *xorm.Session, transaction
 	if transaction.AccountId == oldTransaction.AccountId {
 		oldSourceAccount = sourceAccount
 	} else {
-		has, err := sess.ID(oldTransaction.AccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldSourceAccount)
+		has, err := sess.ID(oldTransaction.AccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(oldSourceAccount)
 
 		if err != nil {
 			return nil, nil, err
@@ -2825,7 +4352,7 @@ func (s *TransactionService) getOldAccountModels(sess *xorm.Session, transaction
 	if transaction.RelatedAccountId == oldTransaction.RelatedAccountId {
 		oldDestinationAccount = destinationAccount
 	} else {
-		has, err := sess.ID(oldTransaction.RelatedAccountId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldDestinationAccount)
+		has, err := sess.ID(oldTransaction.RelatedAccountId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(oldDestinationAccount)
 
 		if err != nil {
 			return nil, nil, err
@@ -2836,6 +4363,66 @@ func (s *TransactionService) getOldAccountModels(sess *xorm.Session, transaction
 	return oldSourceAccount, oldDestinationAccount, nil
 }
 
+// getTransactionLockTime returns the transaction lock time of the specified ledger, the ledger is read in the specified session of user data database,
+// and the user is read from user database which cannot join the same database transaction
+func (s *TransactionService) getTransactionLockTime(c core.Context, sess *xorm.Session, uid int64, ledgerId int64) (int64, error) {
+	user := &models.User{}
+	has, err := s.UserDB().NewSession(c).ID(uid).Cols("transaction_lock_time").Where("deleted=?", false).Get(user)
+
+	if err != nil {
+		return 0, err
+	} else if !has {
+		return 0, errs.ErrUserNotFound
+	}
+
+	var ledger *models.Ledger
+
+	if ledgerId > 0 {
+		ledger = &models.Ledger{}
+		has, err = sess.ID(ledgerId).Cols("transaction_lock_overridden", "transaction_lock_time").Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+		if err != nil {
+			return 0, err
+		} else if !has {
+			return 0, errs.ErrLedgerNotFound
+		}
+	}
+
+	return user.GetTransactionLockTime(ledger), nil
+}
+
+// isAnyTransactionTimeLocked returns whether the transaction time of any specified transaction is locked by the lock unix time
+func isAnyTransactionTimeLocked(transactions []*models.Transaction, lockTime int64) bool {
+	for i := 0; i < len(transactions); i++ {
+		if models.IsTransactionTimeLocked(transactions[i].TransactionTime, lockTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *TransactionService) isTransactionReconciled(sess *xorm.Session, transaction *models.Transaction) (bool, error) {
+	if transaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED {
+		return true, nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return false, nil
+	}
+
+	relatedTransaction := &models.Transaction{}
+	has, err := sess.ID(transaction.RelatedId).Cols("reconciliation_status").Where("uid=? AND deleted=?", transaction.Uid, false).Get(relatedTransaction)
+
+	if err != nil {
+		return false, err
+	} else if !has {
+		return false, nil
+	}
+
+	return relatedTransaction.ReconciliationStatus == models.TRANSACTION_RECONCILIATION_STATUS_RECONCILED, nil
+}
+
 func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []string {
 	relatedUpdateCols := make([]string, len(updateCols))
 
@@ -2856,6 +4443,26 @@ func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []stri
 	return relatedUpdateCols
 }
 
+func (s *TransactionService) isPayeeValid(sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.PayeeId == 0 {
+		return nil
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return errs.ErrPayeeIdInvalid
+	}
+
+	exists, err := sess.Cols("payee_id").Where("uid=? AND ledger_id=? AND deleted=? AND payee_id=?", transaction.Uid, transaction.LedgerId, false, transaction.PayeeId).Exist(&models.Payee{})
+
+	if err != nil {
+		return err
+	} else if !exists {
+		return errs.ErrPayeeNotFound
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.CategoryId != 0 {
@@ -2863,7 +4470,7 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 		}
 	} else {
 		category := &models.TransactionCategory{}
-		has, err := sess.ID(transaction.CategoryId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(category)
+		has, err := sess.ID(transaction.CategoryId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(category)
 
 		if err != nil {
 			return err
@@ -2886,7 +4493,7 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 		}
 
 		parentCategory := &models.TransactionCategory{}
-		has, err = sess.ID(category.ParentCategoryId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(parentCategory)
+		has, err = sess.ID(category.ParentCategoryId).Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).Get(parentCategory)
 
 		if err != nil {
 			return err
@@ -2905,7 +4512,7 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
-		err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", tagIds).Find(&tags)
+		err := sess.Where("uid=? AND ledger_id=? AND deleted=?", transaction.Uid, transaction.LedgerId, false).In("tag_id", tagIds).Find(&tags)
 
 		if err != nil {
 			return err
@@ -2931,6 +4538,49 @@ func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models
 	return nil
 }
 
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction, transactionSplits []*models.TransactionSplit) error {
+	if len(transactionSplits) < 1 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionTypeCannotBeSplit
+	}
+
+	if len(transactionSplits) < 2 {
+		return errs.ErrTransactionSplitsTooFew
+	}
+
+	if len(transactionSplits) > models.MaximumSplitsCountOfTransaction {
+		return errs.ErrTransactionHasTooManySplits
+	}
+
+	totalAmount := int64(0)
+
+	for i := 0; i < len(transactionSplits); i++ {
+		totalAmount += transactionSplits[i].Amount
+	}
+
+	if totalAmount != transaction.Amount {
+		return errs.ErrTransactionSplitsAmountNotEqual
+	}
+
+	for i := 0; i < len(transactionSplits); i++ {
+		err := s.isCategoryValid(sess, &models.Transaction{
+			Uid:        transaction.Uid,
+			LedgerId:   transaction.LedgerId,
+			Type:       transaction.Type,
+			CategoryId: transactionSplits[i].CategoryId,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isPicturesValid(sess *xorm.Session, transaction *models.Transaction, pictureIds []int64) error {
 	if len(pictureIds) > 0 {
 		var pictureInfos []*models.TransactionPictureInfo
@@ -2947,6 +4597,11 @@ func (s *TransactionService) isPicturesValid(sess *xorm.Session, transaction *mo
 				return errs.ErrTransactionPictureIdInvalid
 			}
 
+			// the new picture can only be used by the transaction in the ledger which the picture is uploaded to
+			if pictureInfos[i].TransactionId == models.TransactionPictureNewPictureTransactionId && pictureInfos[i].LedgerId != transaction.LedgerId {
+				return errs.ErrTransactionPictureIdInvalid
+			}
+
 			pictureInfoMap[pictureInfos[i].PictureId] = pictureInfos[i]
 		}
 
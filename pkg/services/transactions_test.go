@@ -0,0 +1,24 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestIsAnyTransactionTimeLocked(t *testing.T) {
+	transactions := []*models.Transaction{
+		{TransactionId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200)},
+		{TransactionId: 2, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067199) + 1},
+	}
+
+	assert.False(t, isAnyTransactionTimeLocked(transactions, 0))
+	assert.False(t, isAnyTransactionTimeLocked(transactions, 1704067199))
+	assert.True(t, isAnyTransactionTimeLocked(transactions, 1704067200))
+	assert.True(t, isAnyTransactionTimeLocked(transactions[1:], 1704067200))
+	assert.False(t, isAnyTransactionTimeLocked(transactions[:1], 1704067200))
+	assert.False(t, isAnyTransactionTimeLocked(nil, 1704067200))
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+)
+
+// TrashService represents trash bin service
+type TrashService struct {
+	ServiceUsingDB
+	ServiceUsingStorage
+}
+
+// Initialize a trash bin service singleton instance
+var (
+	Trash = &TrashService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// PurgeAllExpiredDeletedData permanently deletes all the deleted data in trash bin which was deleted before the specified time
+func (s *TrashService) PurgeAllExpiredDeletedData(c core.Context, maxDeletedUnixTime int64) error {
+	var errors []error
+	totalCount := int64(0)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var pictureInfos []*models.TransactionPictureInfo
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND deleted_unix_time<?", true, maxDeletedUnixTime).Find(&pictureInfos)
+
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+
+		for j := 0; j < len(pictureInfos); j++ {
+			pictureInfo := pictureInfos[j]
+			err = s.DeleteTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+			if err != nil {
+				log.Warnf(c, "[trash.PurgeAllExpiredDeletedData] failed to delete transaction picture file \"id:%d\" of user \"uid:%d\", because %s", pictureInfo.PictureId, pictureInfo.Uid, err.Error())
+			}
+		}
+
+		err = s.UserDataDBByIndex(i).DoTransaction(c, func(sess *xorm.Session) error {
+			for {
+				var transactions []*models.Transaction
+				err := sess.Cols("transaction_id").Where("deleted=? AND deleted_unix_time<?", true, maxDeletedUnixTime).Limit(pageCountForLoadTransactionAmounts, 0).Find(&transactions)
+
+				if err != nil {
+					return err
+				}
+
+				if len(transactions) < 1 {
+					break
+				}
+
+				transactionIds := make([]int64, len(transactions))
+
+				for j := 0; j < len(transactions); j++ {
+					transactionIds[j] = transactions[j].TransactionId
+				}
+
+				_, err = sess.In("transaction_id", transactionIds).Delete(&models.TransactionHistory{})
+
+				if err != nil {
+					return err
+				}
+
+				count, err := sess.Where("deleted=?", true).In("transaction_id", transactionIds).Delete(&models.Transaction{})
+
+				if err != nil {
+					return err
+				}
+
+				totalCount += count
+			}
+
+			beans := []any{
+				&models.TransactionTagIndex{},
+				&models.TransactionSplit{},
+				&models.TransactionPictureInfo{},
+				&models.TransactionTag{},
+				&models.TransactionCategory{},
+				&models.Account{},
+				&models.Payee{},
+				&models.AmortizationSchedule{},
+				&models.InvestmentTransaction{},
+			}
+
+			for j := 0; j < len(beans); j++ {
+				count, err := sess.Where("deleted=? AND deleted_unix_time<?", true, maxDeletedUnixTime).Delete(beans[j])
+
+				if err != nil {
+					return err
+				}
+
+				totalCount += count
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	if totalCount > 0 {
+		log.Infof(c, "[trash.PurgeAllExpiredDeletedData] %d expired deleted rows have been purged", totalCount)
+	} else if len(errors) == 0 {
+		log.Infof(c, "[trash.PurgeAllExpiredDeletedData] no expired deleted rows have been purged")
+	}
+
+	return errs.NewMultiErrorOrNil(errors...)
+}
@@ -151,3 +151,21 @@ func (s *UserCustomExchangeRatesService) DeleteAllCustomExchangeRates(c core.Con
 		return nil
 	})
 }
+
+// GetAllUidsOfCustomExchangeRates returns the uids of all users who have user custom exchange rate data
+func (s *UserCustomExchangeRatesService) GetAllUidsOfCustomExchangeRates(c core.Context) ([]int64, error) {
+	var allUids []int64
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var uids []int64
+		err := s.UserDataDBByIndex(i).NewSession(c).Table("user_custom_exchange_rate").Where("deleted_unix_time=?", 0).Distinct("uid").Find(&uids)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allUids = append(allUids, uids...)
+	}
+
+	return allUids, nil
+}
@@ -0,0 +1,1349 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const userDataBackupDocumentFileName = "backup.json"
+const userDataBackupAvatarFileNamePrefix = "avatar."
+const userDataBackupPicturesDirectory = "pictures/"
+const maxUuidCountPerGeneration = 65535
+
+// userDataBackupInsightsExplorerConditionFieldsWithIds represents the condition fields of insights explorer whose values are ids
+var userDataBackupInsightsExplorerConditionFieldsWithIds = map[string]bool{
+	"transactionCategory": true,
+	"sourceAccount":       true,
+	"destinationAccount":  true,
+	"transactionTag":      true,
+}
+
+// userDataBackupCloudSettingKeysWithIds represents the application cloud setting keys whose values are maps keyed by ids
+var userDataBackupCloudSettingKeysWithIds = map[string]bool{
+	"overviewAccountFilterInHomePage":             true,
+	"overviewTransactionCategoryFilterInHomePage": true,
+	"totalAmountExcludeAccountIds":                true,
+	"statistics.defaultAccountFilter":             true,
+	"statistics.defaultTransactionCategoryFilter": true,
+}
+
+// UserDataBackupService represents user data backup service
+type UserDataBackupService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	ServiceUsingStorage
+}
+
+// userDataBackupIdMapper represents the mapping from the ids in backup archive to the new generated ids
+type userDataBackupIdMapper struct {
+	oldUid int64
+	newUid int64
+	newIds map[int64]int64
+}
+
+// Initialize a user data backup service singleton instance
+var (
+	UserDataBackups = &UserDataBackupService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// CreateBackupArchive returns the zip archive which contains all the data, the avatar and the transaction pictures of specified user,
+// the ai assistant embeddings, external authentications, ledger members, tokens and two-factor authorization settings are not included
+func (s *UserDataBackupService) CreateBackupArchive(c core.Context, user *models.User) ([]byte, error) {
+	backup, err := s.GetUserDataBackup(c, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	if user.CustomAvatarType != "" {
+		avatarData, err := s.readObject(s.ReadAvatar(c, user.Uid, user.CustomAvatarType))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if avatarData != nil {
+			err = s.writeZipEntry(zipWriter, userDataBackupAvatarFileNamePrefix+user.CustomAvatarType, avatarData)
+
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			backup.Settings.CustomAvatarType = ""
+		}
+	}
+
+	pictureInfos := make([]*models.TransactionPictureInfo, 0, len(backup.PictureInfos))
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		pictureInfo := backup.PictureInfos[i]
+		pictureData, err := s.readObject(s.ReadTransactionPicture(c, pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension))
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the picture info whose object does not exist in storage is useless, so it would not be backed up
+		if pictureData == nil {
+			continue
+		}
+
+		err = s.writeZipEntry(zipWriter, s.getPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension), pictureData)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pictureInfos = append(pictureInfos, pictureInfo)
+	}
+
+	backup.PictureInfos = pictureInfos
+	document, err := json.Marshal(backup)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.writeZipEntry(zipWriter, userDataBackupDocumentFileName, document)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = zipWriter.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// RestoreBackupArchive restores all the data, the avatar and the transaction pictures in the zip archive to specified user which does not have any data,
+// all the ids in the archive are regenerated, so the archive can be restored to a user of another instance
+func (s *UserDataBackupService) RestoreBackupArchive(c core.Context, user *models.User, data []byte) error {
+	if user.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return errs.ErrUserDataBackupInvalid
+	}
+
+	allFiles := make(map[string]*zip.File, len(zipReader.File))
+
+	for i := 0; i < len(zipReader.File); i++ {
+		allFiles[zipReader.File[i].Name] = zipReader.File[i]
+	}
+
+	documentFile, exists := allFiles[userDataBackupDocumentFileName]
+
+	if !exists {
+		return errs.ErrUserDataBackupInvalid
+	}
+
+	document, err := s.readZipEntry(documentFile)
+
+	if err != nil {
+		return errs.ErrUserDataBackupInvalid
+	}
+
+	backup := &models.UserDataBackup{}
+	err = json.Unmarshal(document, backup)
+
+	if err != nil || backup.Version < 1 || backup.Settings == nil {
+		return errs.ErrUserDataBackupInvalid
+	}
+
+	if backup.Version > models.UserDataBackupVersion {
+		return errs.ErrUserDataBackupVersionNotSupported
+	}
+
+	// the extensions are used in the storage object keys, so only the supported image extensions are allowed
+	if backup.Settings.CustomAvatarType != "" && utils.GetImageContentType(backup.Settings.CustomAvatarType) == "" {
+		log.Warnf(c, "[user_data_backups.RestoreBackupArchive] the avatar extension \"%s\" in backup is not supported for user \"uid:%d\"", backup.Settings.CustomAvatarType, user.Uid)
+		return errs.ErrUserDataBackupInvalid
+	}
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		if backup.PictureInfos[i] == nil || utils.GetImageContentType(backup.PictureInfos[i].PictureExtension) == "" {
+			log.Warnf(c, "[user_data_backups.RestoreBackupArchive] the transaction picture extension in backup is not supported for user \"uid:%d\"", user.Uid)
+			return errs.ErrUserDataBackupInvalid
+		}
+	}
+
+	var avatarData []byte
+
+	if backup.Settings.CustomAvatarType != "" {
+		if avatarFile, exists := allFiles[userDataBackupAvatarFileNamePrefix+backup.Settings.CustomAvatarType]; exists {
+			avatarData, err = s.readZipEntry(avatarFile)
+
+			if err != nil {
+				return errs.ErrUserDataBackupInvalid
+			}
+		} else {
+			backup.Settings.CustomAvatarType = ""
+		}
+	}
+
+	allPictureData := make(map[int64][]byte, len(backup.PictureInfos))
+	pictureInfos := make([]*models.TransactionPictureInfo, 0, len(backup.PictureInfos))
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		pictureInfo := backup.PictureInfos[i]
+		pictureFile, exists := allFiles[s.getPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension)]
+
+		if !exists {
+			continue
+		}
+
+		pictureData, err := s.readZipEntry(pictureFile)
+
+		if err != nil {
+			return errs.ErrUserDataBackupInvalid
+		}
+
+		allPictureData[pictureInfo.PictureId] = pictureData
+		pictureInfos = append(pictureInfos, pictureInfo)
+	}
+
+	backup.PictureInfos = pictureInfos
+	oldPictureIds := make([]int64, len(backup.PictureInfos))
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		oldPictureIds[i] = backup.PictureInfos[i].PictureId
+	}
+
+	idMapper, err := s.createIdMapper(backup, user.Uid)
+
+	if err != nil {
+		return err
+	}
+
+	err = idMapper.remapUserDataBackup(backup)
+
+	if err != nil {
+		return err
+	}
+
+	savedPictureInfos := make([]*models.TransactionPictureInfo, 0, len(backup.PictureInfos))
+
+	err = s.UserDataDB(user.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		hasData, err := s.hasUserData(sess, user.Uid)
+
+		if err != nil {
+			return err
+		} else if hasData {
+			return errs.ErrUserDataRestoreTargetNotEmpty
+		}
+
+		err = s.deleteReplaceableUserData(sess, user.Uid, backup)
+
+		if err != nil {
+			return err
+		}
+
+		err = s.insertUserDataBackup(sess, backup)
+
+		if err != nil {
+			return err
+		}
+
+		// save the pictures before committing, so that the restoring would be rolled back if any picture fails to save
+		for i := 0; i < len(backup.PictureInfos); i++ {
+			pictureInfo := backup.PictureInfos[i]
+			err = s.SaveTransactionPicture(c, user.Uid, pictureInfo.PictureId, storage.NewByteSliceObject(allPictureData[oldPictureIds[i]]), pictureInfo.PictureExtension)
+
+			if err != nil {
+				log.Errorf(c, "[user_data_backups.RestoreBackupArchive] failed to save transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureInfo.PictureId, user.Uid, err.Error())
+				return err
+			}
+
+			savedPictureInfos = append(savedPictureInfos, pictureInfo)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		for i := 0; i < len(savedPictureInfos); i++ {
+			pictureInfo := savedPictureInfos[i]
+			deleteErr := s.DeleteTransactionPicture(c, user.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+			if deleteErr != nil {
+				log.Warnf(c, "[user_data_backups.RestoreBackupArchive] failed to delete transaction picture \"id:%d\" for user \"uid:%d\" after restoring failed, because %s", pictureInfo.PictureId, user.Uid, deleteErr.Error())
+			}
+		}
+
+		return err
+	}
+
+	// save the avatar after the user data is restored, so that the current avatar would not be overwritten if restoring fails
+	if avatarData != nil {
+		err = s.SaveAvatar(c, user.Uid, storage.NewByteSliceObject(avatarData), backup.Settings.CustomAvatarType)
+
+		if err != nil {
+			log.Warnf(c, "[user_data_backups.RestoreBackupArchive] failed to save avatar for user \"uid:%d\", because %s", user.Uid, err.Error())
+			backup.Settings.CustomAvatarType = user.CustomAvatarType
+		}
+	}
+
+	backup.Settings.ApplyToUser(user)
+	user.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.ID(user.Uid).Cols("nickname", "custom_avatar_type", "default_account_id", "transaction_edit_scope", "transaction_lock_time", "language", "default_currency", "first_day_of_week", "fiscal_year_start", "calendar_display_type", "date_display_type", "long_date_format", "short_date_format", "long_time_format", "short_time_format", "fiscal_year_format", "currency_display_type", "numeral_system", "decimal_separator", "digit_grouping_symbol", "digit_grouping", "coordinate_display_type", "expense_amount_color", "income_amount_color", "updated_unix_time").Where("deleted=?", false).Update(user)
+		return err
+	})
+}
+
+// GetUserDataBackup returns all the data of specified user which would be stored in backup archive
+func (s *UserDataBackupService) GetUserDataBackup(c core.Context, user *models.User) (*models.UserDataBackup, error) {
+	uid := user.Uid
+
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	backup := &models.UserDataBackup{
+		Version:         models.UserDataBackupVersion,
+		CreatedUnixTime: time.Now().Unix(),
+		Uid:             uid,
+		Settings:        user.ToUserDataBackupSettings(),
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Ledgers)
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&backup.Accounts)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("parent_category_id asc, display_order asc").Find(&backup.Categories)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.TagGroups)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("tag_group_id asc, display_order asc").Find(&backup.Tags)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Payees)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&backup.Transactions)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.TagIndexes)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_id asc, display_order asc").Find(&backup.Splits)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=? AND transaction_id<>?", uid, false, 0).Find(&backup.PictureInfos)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=?", uid).OrderBy("created_unix_time asc").Find(&backup.Histories)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Templates)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=?", uid).Find(&backup.TemplateOccurrenceOverrides)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.AmortizationSchedules)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&backup.InvestmentTransactions)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Find(&backup.SecurityPrices)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Budgets)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Rules)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.InsightsExplorers)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Find(&backup.CustomExchangeRates)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=?", uid).Find(&backup.ExchangeRateSnapshots)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=?", uid).Find(&backup.NetWorthSnapshots)
+	}
+
+	if err == nil {
+		err = sess.Where("uid=?", uid).Find(&backup.NetWorthSnapshotStates)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cloudSetting := &models.UserApplicationCloudSetting{}
+	has, err := sess.Where("uid=?", uid).Get(cloudSetting)
+
+	if err != nil {
+		return nil, err
+	} else if has {
+		backup.ApplicationCloudSettings = cloudSetting
+	}
+
+	s.removeOrphanedData(backup)
+
+	return backup, nil
+}
+
+// removeOrphanedData removes the rows which reference to the deleted parent rows, because they cannot be restored
+func (s *UserDataBackupService) removeOrphanedData(backup *models.UserDataBackup) {
+	existedIds := make(map[int64]bool)
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		existedIds[backup.Accounts[i].AccountId] = true
+	}
+
+	for i := 0; i < len(backup.Tags); i++ {
+		existedIds[backup.Tags[i].TagId] = true
+	}
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		existedIds[backup.Transactions[i].TransactionId] = true
+	}
+
+	for i := 0; i < len(backup.Templates); i++ {
+		existedIds[backup.Templates[i].TemplateId] = true
+	}
+
+	tagIndexes := make([]*models.TransactionTagIndex, 0, len(backup.TagIndexes))
+
+	for i := 0; i < len(backup.TagIndexes); i++ {
+		if existedIds[backup.TagIndexes[i].TagId] && existedIds[backup.TagIndexes[i].TransactionId] {
+			tagIndexes = append(tagIndexes, backup.TagIndexes[i])
+		}
+	}
+
+	splits := make([]*models.TransactionSplit, 0, len(backup.Splits))
+
+	for i := 0; i < len(backup.Splits); i++ {
+		if existedIds[backup.Splits[i].TransactionId] {
+			splits = append(splits, backup.Splits[i])
+		}
+	}
+
+	pictureInfos := make([]*models.TransactionPictureInfo, 0, len(backup.PictureInfos))
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		if existedIds[backup.PictureInfos[i].TransactionId] {
+			pictureInfos = append(pictureInfos, backup.PictureInfos[i])
+		}
+	}
+
+	histories := make([]*models.TransactionHistory, 0, len(backup.Histories))
+
+	for i := 0; i < len(backup.Histories); i++ {
+		if existedIds[backup.Histories[i].TransactionId] {
+			histories = append(histories, backup.Histories[i])
+		}
+	}
+
+	templateOccurrenceOverrides := make([]*models.TransactionTemplateOccurrenceOverride, 0, len(backup.TemplateOccurrenceOverrides))
+
+	for i := 0; i < len(backup.TemplateOccurrenceOverrides); i++ {
+		if existedIds[backup.TemplateOccurrenceOverrides[i].TemplateId] {
+			templateOccurrenceOverrides = append(templateOccurrenceOverrides, backup.TemplateOccurrenceOverrides[i])
+		}
+	}
+
+	netWorthSnapshots := make([]*models.NetWorthSnapshot, 0, len(backup.NetWorthSnapshots))
+
+	for i := 0; i < len(backup.NetWorthSnapshots); i++ {
+		if existedIds[backup.NetWorthSnapshots[i].AccountId] {
+			netWorthSnapshots = append(netWorthSnapshots, backup.NetWorthSnapshots[i])
+		}
+	}
+
+	backup.TagIndexes = tagIndexes
+	backup.Splits = splits
+	backup.PictureInfos = pictureInfos
+	backup.Histories = histories
+	backup.TemplateOccurrenceOverrides = templateOccurrenceOverrides
+	backup.NetWorthSnapshots = netWorthSnapshots
+}
+
+func (s *UserDataBackupService) hasUserData(sess *xorm.Session, uid int64) (bool, error) {
+	tablesWithDeletedFlag := []any{
+		&models.Ledger{},
+		&models.Account{},
+		&models.TransactionCategory{},
+		&models.TransactionTagGroup{},
+		&models.TransactionTag{},
+		&models.Payee{},
+		&models.Transaction{},
+		&models.TransactionTagIndex{},
+		&models.TransactionSplit{},
+		&models.TransactionPictureInfo{},
+		&models.TransactionTemplate{},
+		&models.AmortizationSchedule{},
+		&models.InvestmentTransaction{},
+		&models.Budget{},
+		&models.TransactionRule{},
+		&models.InsightsExplorer{},
+	}
+
+	for i := 0; i < len(tablesWithDeletedFlag); i++ {
+		exists, err := sess.Where("uid=? AND deleted=?", uid, false).Exist(tablesWithDeletedFlag[i])
+
+		if err != nil {
+			return false, err
+		} else if exists {
+			return true, nil
+		}
+	}
+
+	tablesWithDeletedUnixTime := []any{
+		&models.SecurityPrice{},
+		&models.UserCustomExchangeRate{},
+	}
+
+	for i := 0; i < len(tablesWithDeletedUnixTime); i++ {
+		exists, err := sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Exist(tablesWithDeletedUnixTime[i])
+
+		if err != nil {
+			return false, err
+		} else if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// deleteReplaceableUserData deletes the existed rows of the user which would be replaced by the rows in backup,
+// the net worth snapshots are always deleted because they are calculated from the transactions which do not exist now
+func (s *UserDataBackupService) deleteReplaceableUserData(sess *xorm.Session, uid int64, backup *models.UserDataBackup) error {
+	tables := []any{
+		&models.NetWorthSnapshot{},
+		&models.NetWorthSnapshotState{},
+	}
+
+	if len(backup.ExchangeRateSnapshots) > 0 {
+		tables = append(tables, &models.ExchangeRateSnapshot{})
+	}
+
+	if backup.ApplicationCloudSettings != nil {
+		tables = append(tables, &models.UserApplicationCloudSetting{})
+	}
+
+	for i := 0; i < len(tables); i++ {
+		_, err := sess.Where("uid=?", uid).Delete(tables[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserDataBackupService) createIdMapper(backup *models.UserDataBackup, uid int64) (*userDataBackupIdMapper, error) {
+	idMapper := &userDataBackupIdMapper{
+		oldUid: backup.Uid,
+		newUid: uid,
+		newIds: make(map[int64]int64),
+	}
+
+	allOldIds := map[uuid.UuidType][]int64{}
+
+	for i := 0; i < len(backup.Ledgers); i++ {
+		allOldIds[uuid.UUID_TYPE_LEDGER] = append(allOldIds[uuid.UUID_TYPE_LEDGER], backup.Ledgers[i].LedgerId)
+	}
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		allOldIds[uuid.UUID_TYPE_ACCOUNT] = append(allOldIds[uuid.UUID_TYPE_ACCOUNT], backup.Accounts[i].AccountId)
+	}
+
+	for i := 0; i < len(backup.Categories); i++ {
+		allOldIds[uuid.UUID_TYPE_CATEGORY] = append(allOldIds[uuid.UUID_TYPE_CATEGORY], backup.Categories[i].CategoryId)
+	}
+
+	for i := 0; i < len(backup.TagGroups); i++ {
+		allOldIds[uuid.UUID_TYPE_TAG_GROUP] = append(allOldIds[uuid.UUID_TYPE_TAG_GROUP], backup.TagGroups[i].TagGroupId)
+	}
+
+	for i := 0; i < len(backup.Tags); i++ {
+		allOldIds[uuid.UUID_TYPE_TAG] = append(allOldIds[uuid.UUID_TYPE_TAG], backup.Tags[i].TagId)
+	}
+
+	for i := 0; i < len(backup.Payees); i++ {
+		allOldIds[uuid.UUID_TYPE_PAYEE] = append(allOldIds[uuid.UUID_TYPE_PAYEE], backup.Payees[i].PayeeId)
+	}
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		allOldIds[uuid.UUID_TYPE_TRANSACTION] = append(allOldIds[uuid.UUID_TYPE_TRANSACTION], backup.Transactions[i].TransactionId)
+	}
+
+	for i := 0; i < len(backup.TagIndexes); i++ {
+		allOldIds[uuid.UUID_TYPE_TAG_INDEX] = append(allOldIds[uuid.UUID_TYPE_TAG_INDEX], backup.TagIndexes[i].TagIndexId)
+	}
+
+	for i := 0; i < len(backup.Splits); i++ {
+		allOldIds[uuid.UUID_TYPE_TRANSACTION] = append(allOldIds[uuid.UUID_TYPE_TRANSACTION], backup.Splits[i].SplitId)
+	}
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		allOldIds[uuid.UUID_TYPE_USER] = append(allOldIds[uuid.UUID_TYPE_USER], backup.PictureInfos[i].PictureId)
+	}
+
+	for i := 0; i < len(backup.Histories); i++ {
+		allOldIds[uuid.UUID_TYPE_TRANSACTION] = append(allOldIds[uuid.UUID_TYPE_TRANSACTION], backup.Histories[i].HistoryId)
+	}
+
+	for i := 0; i < len(backup.Templates); i++ {
+		allOldIds[uuid.UUID_TYPE_TEMPLATE] = append(allOldIds[uuid.UUID_TYPE_TEMPLATE], backup.Templates[i].TemplateId)
+	}
+
+	for i := 0; i < len(backup.AmortizationSchedules); i++ {
+		allOldIds[uuid.UUID_TYPE_TEMPLATE] = append(allOldIds[uuid.UUID_TYPE_TEMPLATE], backup.AmortizationSchedules[i].ScheduleId)
+	}
+
+	for i := 0; i < len(backup.InvestmentTransactions); i++ {
+		allOldIds[uuid.UUID_TYPE_TRANSACTION] = append(allOldIds[uuid.UUID_TYPE_TRANSACTION], backup.InvestmentTransactions[i].InvestmentTransactionId)
+	}
+
+	for i := 0; i < len(backup.Budgets); i++ {
+		allOldIds[uuid.UUID_TYPE_BUDGET] = append(allOldIds[uuid.UUID_TYPE_BUDGET], backup.Budgets[i].BudgetId)
+	}
+
+	for i := 0; i < len(backup.Rules); i++ {
+		allOldIds[uuid.UUID_TYPE_RULE] = append(allOldIds[uuid.UUID_TYPE_RULE], backup.Rules[i].RuleId)
+	}
+
+	for i := 0; i < len(backup.InsightsExplorers); i++ {
+		allOldIds[uuid.UUID_TYPE_EXPLORER] = append(allOldIds[uuid.UUID_TYPE_EXPLORER], backup.InsightsExplorers[i].ExplorerId)
+	}
+
+	for uuidType, oldIds := range allOldIds {
+		newIds, err := s.generateUuids(uuidType, len(oldIds))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(oldIds); i++ {
+			if oldIds[i] <= 0 {
+				return nil, errs.ErrUserDataBackupInvalid
+			}
+
+			if _, exists := idMapper.newIds[oldIds[i]]; exists {
+				return nil, errs.ErrUserDataBackupInvalid
+			}
+
+			idMapper.newIds[oldIds[i]] = newIds[i]
+		}
+	}
+
+	return idMapper, nil
+}
+
+func (s *UserDataBackupService) generateUuids(uuidType uuid.UuidType, count int) ([]int64, error) {
+	allUuids := make([]int64, 0, count)
+
+	for len(allUuids) < count {
+		needUuidCount := count - len(allUuids)
+
+		if needUuidCount > maxUuidCountPerGeneration {
+			needUuidCount = maxUuidCountPerGeneration
+		}
+
+		uuids := s.GenerateUuids(uuidType, uint16(needUuidCount))
+
+		if len(uuids) < needUuidCount {
+			return nil, errs.ErrSystemIsBusy
+		}
+
+		allUuids = append(allUuids, uuids...)
+	}
+
+	return allUuids, nil
+}
+
+func (s *UserDataBackupService) insertUserDataBackup(sess *xorm.Session, backup *models.UserDataBackup) error {
+	allRows := make([]any, 0)
+
+	for i := 0; i < len(backup.Ledgers); i++ {
+		allRows = append(allRows, backup.Ledgers[i])
+	}
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		allRows = append(allRows, backup.Accounts[i])
+	}
+
+	for i := 0; i < len(backup.Categories); i++ {
+		allRows = append(allRows, backup.Categories[i])
+	}
+
+	for i := 0; i < len(backup.TagGroups); i++ {
+		allRows = append(allRows, backup.TagGroups[i])
+	}
+
+	for i := 0; i < len(backup.Tags); i++ {
+		allRows = append(allRows, backup.Tags[i])
+	}
+
+	for i := 0; i < len(backup.Payees); i++ {
+		allRows = append(allRows, backup.Payees[i])
+	}
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		allRows = append(allRows, backup.Transactions[i])
+	}
+
+	for i := 0; i < len(backup.TagIndexes); i++ {
+		allRows = append(allRows, backup.TagIndexes[i])
+	}
+
+	for i := 0; i < len(backup.Splits); i++ {
+		allRows = append(allRows, backup.Splits[i])
+	}
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		allRows = append(allRows, backup.PictureInfos[i])
+	}
+
+	for i := 0; i < len(backup.Histories); i++ {
+		allRows = append(allRows, backup.Histories[i])
+	}
+
+	for i := 0; i < len(backup.Templates); i++ {
+		allRows = append(allRows, backup.Templates[i])
+	}
+
+	for i := 0; i < len(backup.TemplateOccurrenceOverrides); i++ {
+		allRows = append(allRows, backup.TemplateOccurrenceOverrides[i])
+	}
+
+	for i := 0; i < len(backup.AmortizationSchedules); i++ {
+		allRows = append(allRows, backup.AmortizationSchedules[i])
+	}
+
+	for i := 0; i < len(backup.InvestmentTransactions); i++ {
+		allRows = append(allRows, backup.InvestmentTransactions[i])
+	}
+
+	for i := 0; i < len(backup.SecurityPrices); i++ {
+		allRows = append(allRows, backup.SecurityPrices[i])
+	}
+
+	for i := 0; i < len(backup.Budgets); i++ {
+		allRows = append(allRows, backup.Budgets[i])
+	}
+
+	for i := 0; i < len(backup.Rules); i++ {
+		allRows = append(allRows, backup.Rules[i])
+	}
+
+	for i := 0; i < len(backup.InsightsExplorers); i++ {
+		allRows = append(allRows, backup.InsightsExplorers[i])
+	}
+
+	for i := 0; i < len(backup.CustomExchangeRates); i++ {
+		allRows = append(allRows, backup.CustomExchangeRates[i])
+	}
+
+	for i := 0; i < len(backup.ExchangeRateSnapshots); i++ {
+		allRows = append(allRows, backup.ExchangeRateSnapshots[i])
+	}
+
+	for i := 0; i < len(backup.NetWorthSnapshots); i++ {
+		allRows = append(allRows, backup.NetWorthSnapshots[i])
+	}
+
+	for i := 0; i < len(backup.NetWorthSnapshotStates); i++ {
+		allRows = append(allRows, backup.NetWorthSnapshotStates[i])
+	}
+
+	if backup.ApplicationCloudSettings != nil {
+		allRows = append(allRows, backup.ApplicationCloudSettings)
+	}
+
+	for i := 0; i < len(allRows); i++ {
+		_, err := sess.Insert(allRows[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *UserDataBackupService) readObject(object storage.ObjectInStorage, err error) ([]byte, error) {
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer object.Close()
+
+	return io.ReadAll(object)
+}
+
+func (s *UserDataBackupService) writeZipEntry(zipWriter *zip.Writer, fileName string, data []byte) error {
+	writer, err := zipWriter.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(data)
+
+	return err
+}
+
+func (s *UserDataBackupService) readZipEntry(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func (s *UserDataBackupService) getPictureFileName(pictureId int64, fileExtension string) string {
+	return path.Join(userDataBackupPicturesDirectory, fmt.Sprintf("%d.%s", pictureId, fileExtension))
+}
+
+// remapUserDataBackup replaces all the ids and the uid in the backup data with the new generated ids and the new uid
+func (m *userDataBackupIdMapper) remapUserDataBackup(backup *models.UserDataBackup) error {
+	var err error
+	backup.Uid = m.newUid
+	backup.Settings.DefaultAccountId = m.getNewIdOrZero(backup.Settings.DefaultAccountId)
+
+	for i := 0; i < len(backup.Ledgers); i++ {
+		ledger := backup.Ledgers[i]
+		ledger.Uid = m.newUid
+
+		if ledger.LedgerId, err = m.getNewId(ledger.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		account := backup.Accounts[i]
+		account.Uid = m.newUid
+
+		if account.AccountId, err = m.getNewId(account.AccountId); err != nil {
+			return err
+		} else if account.LedgerId, err = m.getNewId(account.LedgerId); err != nil {
+			return err
+		} else if account.ParentAccountId, err = m.getNewId(account.ParentAccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Categories); i++ {
+		category := backup.Categories[i]
+		category.Uid = m.newUid
+
+		if category.CategoryId, err = m.getNewId(category.CategoryId); err != nil {
+			return err
+		} else if category.LedgerId, err = m.getNewId(category.LedgerId); err != nil {
+			return err
+		} else if category.ParentCategoryId, err = m.getNewId(category.ParentCategoryId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TagGroups); i++ {
+		tagGroup := backup.TagGroups[i]
+		tagGroup.Uid = m.newUid
+
+		if tagGroup.TagGroupId, err = m.getNewId(tagGroup.TagGroupId); err != nil {
+			return err
+		} else if tagGroup.LedgerId, err = m.getNewId(tagGroup.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Tags); i++ {
+		tag := backup.Tags[i]
+		tag.Uid = m.newUid
+		tag.TagGroupId = m.getNewIdOrZero(tag.TagGroupId)
+
+		if tag.TagId, err = m.getNewId(tag.TagId); err != nil {
+			return err
+		} else if tag.LedgerId, err = m.getNewId(tag.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Payees); i++ {
+		payee := backup.Payees[i]
+		payee.Uid = m.newUid
+		payee.DefaultCategoryId = m.getNewIdOrZero(payee.DefaultCategoryId)
+		payee.DefaultTagIds = m.getNewIdsText(payee.DefaultTagIds)
+
+		if payee.PayeeId, err = m.getNewId(payee.PayeeId); err != nil {
+			return err
+		} else if payee.LedgerId, err = m.getNewId(payee.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		transaction := backup.Transactions[i]
+		transaction.Uid = m.newUid
+		transaction.CreatedByUid = m.getNewUid(transaction.CreatedByUid)
+		transaction.PayeeId = m.getNewIdOrZero(transaction.PayeeId)
+
+		if transaction.TransactionId, err = m.getNewId(transaction.TransactionId); err != nil {
+			return err
+		} else if transaction.LedgerId, err = m.getNewId(transaction.LedgerId); err != nil {
+			return err
+		} else if transaction.CategoryId, err = m.getNewId(transaction.CategoryId); err != nil {
+			return err
+		} else if transaction.AccountId, err = m.getNewId(transaction.AccountId); err != nil {
+			return err
+		} else if transaction.RelatedId, err = m.getNewId(transaction.RelatedId); err != nil {
+			return err
+		} else if transaction.RelatedAccountId, err = m.getNewId(transaction.RelatedAccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TagIndexes); i++ {
+		tagIndex := backup.TagIndexes[i]
+		tagIndex.Uid = m.newUid
+
+		if tagIndex.TagIndexId, err = m.getNewId(tagIndex.TagIndexId); err != nil {
+			return err
+		} else if tagIndex.TagId, err = m.getNewId(tagIndex.TagId); err != nil {
+			return err
+		} else if tagIndex.TransactionId, err = m.getNewId(tagIndex.TransactionId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Splits); i++ {
+		split := backup.Splits[i]
+		split.Uid = m.newUid
+
+		if split.SplitId, err = m.getNewId(split.SplitId); err != nil {
+			return err
+		} else if split.TransactionId, err = m.getNewId(split.TransactionId); err != nil {
+			return err
+		} else if split.CategoryId, err = m.getNewId(split.CategoryId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.PictureInfos); i++ {
+		pictureInfo := backup.PictureInfos[i]
+		pictureInfo.Uid = m.newUid
+
+		if pictureInfo.PictureId, err = m.getNewId(pictureInfo.PictureId); err != nil {
+			return err
+		} else if pictureInfo.LedgerId, err = m.getNewId(pictureInfo.LedgerId); err != nil {
+			return err
+		} else if pictureInfo.TransactionId, err = m.getNewId(pictureInfo.TransactionId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Histories); i++ {
+		history := backup.Histories[i]
+		history.Uid = m.newUid
+		history.OperatorUid = m.getNewUid(history.OperatorUid)
+		history.OperatorTokenId = ""
+
+		if history.HistoryId, err = m.getNewId(history.HistoryId); err != nil {
+			return err
+		} else if history.LedgerId, err = m.getNewId(history.LedgerId); err != nil {
+			return err
+		} else if history.TransactionId, err = m.getNewId(history.TransactionId); err != nil {
+			return err
+		}
+
+		if history.Changes != nil {
+			for j := 0; j < len(*history.Changes); j++ {
+				change := (*history.Changes)[j]
+
+				if change.Field == models.TransactionHistoryFieldCategoryId || change.Field == models.TransactionHistoryFieldAccountId || change.Field == models.TransactionHistoryFieldPayeeId || change.Field == models.TransactionHistoryFieldRelatedAccountId {
+					change.OldValue = m.getNewIdText(change.OldValue)
+					change.NewValue = m.getNewIdText(change.NewValue)
+				} else if change.Field == models.TransactionHistoryFieldTagIds {
+					change.OldValue = m.getNewIdsText(change.OldValue)
+					change.NewValue = m.getNewIdsText(change.NewValue)
+				}
+			}
+		}
+	}
+
+	for i := 0; i < len(backup.Templates); i++ {
+		template := backup.Templates[i]
+		template.Uid = m.newUid
+		template.TagIds = m.getNewIdsText(template.TagIds)
+		template.AmortizationScheduleId = m.getNewIdOrZero(template.AmortizationScheduleId)
+
+		if template.TemplateId, err = m.getNewId(template.TemplateId); err != nil {
+			return err
+		} else if template.LedgerId, err = m.getNewId(template.LedgerId); err != nil {
+			return err
+		} else if template.CategoryId, err = m.getNewId(template.CategoryId); err != nil {
+			return err
+		} else if template.AccountId, err = m.getNewId(template.AccountId); err != nil {
+			return err
+		} else if template.RelatedAccountId, err = m.getNewId(template.RelatedAccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TemplateOccurrenceOverrides); i++ {
+		templateOccurrenceOverride := backup.TemplateOccurrenceOverrides[i]
+		templateOccurrenceOverride.Uid = m.newUid
+
+		if templateOccurrenceOverride.TemplateId, err = m.getNewId(templateOccurrenceOverride.TemplateId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.AmortizationSchedules); i++ {
+		schedule := backup.AmortizationSchedules[i]
+		schedule.Uid = m.newUid
+		schedule.PrincipalTemplateId = m.getNewIdOrZero(schedule.PrincipalTemplateId)
+		schedule.InterestTemplateId = m.getNewIdOrZero(schedule.InterestTemplateId)
+
+		if schedule.ScheduleId, err = m.getNewId(schedule.ScheduleId); err != nil {
+			return err
+		} else if schedule.LedgerId, err = m.getNewId(schedule.LedgerId); err != nil {
+			return err
+		} else if schedule.AccountId, err = m.getNewId(schedule.AccountId); err != nil {
+			return err
+		} else if schedule.PaymentAccountId, err = m.getNewId(schedule.PaymentAccountId); err != nil {
+			return err
+		} else if schedule.PrincipalCategoryId, err = m.getNewId(schedule.PrincipalCategoryId); err != nil {
+			return err
+		} else if schedule.InterestCategoryId, err = m.getNewId(schedule.InterestCategoryId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.InvestmentTransactions); i++ {
+		investmentTransaction := backup.InvestmentTransactions[i]
+		investmentTransaction.Uid = m.newUid
+		investmentTransaction.TransactionId = m.getNewIdOrZero(investmentTransaction.TransactionId)
+
+		if investmentTransaction.InvestmentTransactionId, err = m.getNewId(investmentTransaction.InvestmentTransactionId); err != nil {
+			return err
+		} else if investmentTransaction.LedgerId, err = m.getNewId(investmentTransaction.LedgerId); err != nil {
+			return err
+		} else if investmentTransaction.AccountId, err = m.getNewId(investmentTransaction.AccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.SecurityPrices); i++ {
+		backup.SecurityPrices[i].Uid = m.newUid
+	}
+
+	for i := 0; i < len(backup.Budgets); i++ {
+		budget := backup.Budgets[i]
+		budget.Uid = m.newUid
+		budget.CategoryId = m.getNewIdOrZero(budget.CategoryId)
+		budget.AccountId = m.getNewIdOrZero(budget.AccountId)
+
+		if budget.BudgetId, err = m.getNewId(budget.BudgetId); err != nil {
+			return err
+		} else if budget.LedgerId, err = m.getNewId(budget.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.Rules); i++ {
+		rule := backup.Rules[i]
+		rule.Uid = m.newUid
+		rule.AccountId = m.getNewIdOrZero(rule.AccountId)
+		rule.SetCategoryId = m.getNewIdOrZero(rule.SetCategoryId)
+		rule.AddTagIds = m.getNewIdsText(rule.AddTagIds)
+
+		if rule.RuleId, err = m.getNewId(rule.RuleId); err != nil {
+			return err
+		} else if rule.LedgerId, err = m.getNewId(rule.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.InsightsExplorers); i++ {
+		explorer := backup.InsightsExplorers[i]
+		explorer.Uid = m.newUid
+		explorer.Data = m.getNewInsightsExplorerData(explorer.Data)
+
+		if explorer.ExplorerId, err = m.getNewId(explorer.ExplorerId); err != nil {
+			return err
+		} else if explorer.LedgerId, err = m.getNewId(explorer.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.CustomExchangeRates); i++ {
+		backup.CustomExchangeRates[i].Uid = m.newUid
+	}
+
+	for i := 0; i < len(backup.ExchangeRateSnapshots); i++ {
+		backup.ExchangeRateSnapshots[i].Uid = m.newUid
+	}
+
+	for i := 0; i < len(backup.NetWorthSnapshots); i++ {
+		snapshot := backup.NetWorthSnapshots[i]
+		snapshot.Uid = m.newUid
+
+		if snapshot.LedgerId, err = m.getNewId(snapshot.LedgerId); err != nil {
+			return err
+		} else if snapshot.AccountId, err = m.getNewId(snapshot.AccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.NetWorthSnapshotStates); i++ {
+		state := backup.NetWorthSnapshotStates[i]
+		state.Uid = m.newUid
+
+		if state.LedgerId, err = m.getNewId(state.LedgerId); err != nil {
+			return err
+		}
+	}
+
+	if backup.ApplicationCloudSettings != nil {
+		backup.ApplicationCloudSettings.Uid = m.newUid
+
+		for i := 0; i < len(backup.ApplicationCloudSettings.Settings); i++ {
+			if userDataBackupCloudSettingKeysWithIds[backup.ApplicationCloudSettings.Settings[i].SettingKey] {
+				backup.ApplicationCloudSettings.Settings[i].SettingValue = m.getNewIdBooleanMapText(backup.ApplicationCloudSettings.Settings[i].SettingValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getNewId returns the new id of the specified id in backup archive, or returns error if the specified id does not exist in backup archive
+func (m *userDataBackupIdMapper) getNewId(oldId int64) (int64, error) {
+	if oldId == 0 {
+		return 0, nil
+	}
+
+	newId, exists := m.newIds[oldId]
+
+	if !exists {
+		return 0, errs.ErrUserDataBackupInvalid
+	}
+
+	return newId, nil
+}
+
+// getNewIdOrZero returns the new id of the specified id in backup archive, or returns zero if the specified id does not exist in backup archive
+func (m *userDataBackupIdMapper) getNewIdOrZero(oldId int64) int64 {
+	newId, err := m.getNewId(oldId)
+
+	if err != nil {
+		return 0
+	}
+
+	return newId
+}
+
+// getNewUid returns the new uid if the specified uid is the uid of backup archive, or returns zero if the specified uid is another user in the original instance
+func (m *userDataBackupIdMapper) getNewUid(oldUid int64) int64 {
+	if oldUid == m.oldUid {
+		return m.newUid
+	}
+
+	return 0
+}
+
+// getNewIdsText returns the new ids text of the specified comma-separated ids text in backup archive, the ids which do not exist in backup archive would be removed
+func (m *userDataBackupIdMapper) getNewIdsText(oldIdsText string) string {
+	if oldIdsText == "" {
+		return ""
+	}
+
+	oldIds := strings.Split(oldIdsText, ",")
+	newIds := make([]string, 0, len(oldIds))
+
+	for i := 0; i < len(oldIds); i++ {
+		oldId, err := utils.StringToInt64(oldIds[i])
+
+		if err != nil {
+			continue
+		}
+
+		if newId := m.getNewIdOrZero(oldId); newId != 0 {
+			newIds = append(newIds, utils.Int64ToString(newId))
+		}
+	}
+
+	return strings.Join(newIds, ",")
+}
+
+// getNewIdText returns the new id text of the specified id text in backup archive, or returns zero text if the specified id does not exist in backup archive
+func (m *userDataBackupIdMapper) getNewIdText(oldIdText string) string {
+	oldId, err := utils.StringToInt64(oldIdText)
+
+	if err != nil {
+		return oldIdText
+	}
+
+	return utils.Int64ToString(m.getNewIdOrZero(oldId))
+}
+
+// getNewIdBooleanMapText returns the new json text of the specified json object text which is keyed by the ids in backup archive, the ids which do not exist in backup archive would be removed
+func (m *userDataBackupIdMapper) getNewIdBooleanMapText(oldMapText string) string {
+	oldMap := make(map[string]bool)
+
+	if err := json.Unmarshal([]byte(oldMapText), &oldMap); err != nil {
+		return oldMapText
+	}
+
+	newMap := make(map[string]bool, len(oldMap))
+
+	for oldIdText, value := range oldMap {
+		oldId, err := utils.StringToInt64(oldIdText)
+
+		if err != nil {
+			continue
+		}
+
+		if newId := m.getNewIdOrZero(oldId); newId != 0 {
+			newMap[utils.Int64ToString(newId)] = value
+		}
+	}
+
+	newMapText, err := json.Marshal(newMap)
+
+	if err != nil {
+		return oldMapText
+	}
+
+	return string(newMapText)
+}
+
+// getNewInsightsExplorerData returns the new insights explorer data which the ids in the condition values are replaced with the new ids, the ids which do not exist in backup archive would be removed
+func (m *userDataBackupIdMapper) getNewInsightsExplorerData(oldData string) string {
+	decoder := json.NewDecoder(strings.NewReader(oldData))
+	decoder.UseNumber()
+
+	data := make(map[string]any)
+
+	if err := decoder.Decode(&data); err != nil {
+		return oldData
+	}
+
+	queries, _ := data["queries"].([]any)
+
+	for i := 0; i < len(queries); i++ {
+		query, _ := queries[i].(map[string]any)
+		conditions, _ := query["conditions"].([]any)
+
+		for j := 0; j < len(conditions); j++ {
+			conditionWithRelation, _ := conditions[j].(map[string]any)
+			condition, _ := conditionWithRelation["condition"].(map[string]any)
+			field, _ := condition["field"].(string)
+			oldValues, isArray := condition["value"].([]any)
+
+			if !userDataBackupInsightsExplorerConditionFieldsWithIds[field] || !isArray {
+				continue
+			}
+
+			newValues := make([]any, 0, len(oldValues))
+
+			for k := 0; k < len(oldValues); k++ {
+				oldIdText, _ := oldValues[k].(string)
+				oldId, err := utils.StringToInt64(oldIdText)
+
+				if err != nil {
+					continue
+				}
+
+				if newId := m.getNewIdOrZero(oldId); newId != 0 {
+					newValues = append(newValues, utils.Int64ToString(newId))
+				}
+			}
+
+			condition["value"] = newValues
+		}
+	}
+
+	newData, err := json.Marshal(data)
+
+	if err != nil {
+		return oldData
+	}
+
+	return string(newData)
+}
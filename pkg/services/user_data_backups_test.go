@@ -0,0 +1,214 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func createTestUserDataBackupArchive(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	for fileName, content := range files {
+		writer, err := zipWriter.Create(fileName)
+		assert.Nil(t, err)
+
+		_, err = writer.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
+
+func createTestUserDataBackupIdMapper() *userDataBackupIdMapper {
+	return &userDataBackupIdMapper{
+		oldUid: 1,
+		newUid: 2,
+		newIds: map[int64]int64{
+			1001: 2001,
+			1002: 2002,
+			1003: 2003,
+		},
+	}
+}
+
+func TestUserDataBackupIdMapperGetNewId(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	newId, err := idMapper.getNewId(0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), newId)
+
+	newId, err = idMapper.getNewId(1001)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2001), newId)
+
+	_, err = idMapper.getNewId(1004)
+	assert.EqualError(t, err, errs.ErrUserDataBackupInvalid.Message)
+
+	assert.Equal(t, int64(2002), idMapper.getNewIdOrZero(1002))
+	assert.Equal(t, int64(0), idMapper.getNewIdOrZero(1004))
+}
+
+func TestUserDataBackupIdMapperGetNewUid(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	assert.Equal(t, int64(2), idMapper.getNewUid(1))
+	assert.Equal(t, int64(0), idMapper.getNewUid(3))
+}
+
+func TestUserDataBackupIdMapperGetNewIdsText(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	assert.Equal(t, "", idMapper.getNewIdsText(""))
+	assert.Equal(t, "2001,2003", idMapper.getNewIdsText("1001,1003"))
+	assert.Equal(t, "2002", idMapper.getNewIdsText("1004,1002,abc"))
+}
+
+func TestUserDataBackupIdMapperGetNewIdText(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	assert.Equal(t, "0", idMapper.getNewIdText("0"))
+	assert.Equal(t, "2003", idMapper.getNewIdText("1003"))
+	assert.Equal(t, "0", idMapper.getNewIdText("1004"))
+	assert.Equal(t, "abc", idMapper.getNewIdText("abc"))
+}
+
+func TestUserDataBackupIdMapperGetNewIdBooleanMapText(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	assert.Equal(t, "{\"2001\":true,\"2002\":false}", idMapper.getNewIdBooleanMapText("{\"1001\":true,\"1002\":false,\"1004\":true}"))
+	assert.Equal(t, "{}", idMapper.getNewIdBooleanMapText("{}"))
+	assert.Equal(t, "1001", idMapper.getNewIdBooleanMapText("1001"))
+}
+
+func TestUserDataBackupIdMapperGetNewInsightsExplorerData(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+
+	oldData := "{\"countPerPage\":1001,\"datatableQuerySource\":\"1002\",\"queries\":[{\"conditions\":[" +
+		"{\"condition\":{\"field\":\"sourceAccount\",\"operator\":\"in\",\"value\":[\"1001\",\"1004\"]},\"relation\":\"first\"}," +
+		"{\"condition\":{\"field\":\"sourceAmount\",\"operator\":\"gt\",\"value\":[1002]},\"relation\":\"and\"}," +
+		"{\"condition\":{\"field\":\"description\",\"operator\":\"contains\",\"value\":\"1003\"},\"relation\":\"and\"}," +
+		"{\"condition\":{\"field\":\"transactionTag\",\"operator\":\"hasAny\",\"value\":[\"1003\"]},\"relation\":\"or\"}" +
+		"],\"id\":\"1002\",\"name\":\"Query 1001\"}]}"
+	expectedData := "{\"countPerPage\":1001,\"datatableQuerySource\":\"1002\",\"queries\":[{\"conditions\":[" +
+		"{\"condition\":{\"field\":\"sourceAccount\",\"operator\":\"in\",\"value\":[\"2001\"]},\"relation\":\"first\"}," +
+		"{\"condition\":{\"field\":\"sourceAmount\",\"operator\":\"gt\",\"value\":[1002]},\"relation\":\"and\"}," +
+		"{\"condition\":{\"field\":\"description\",\"operator\":\"contains\",\"value\":\"1003\"},\"relation\":\"and\"}," +
+		"{\"condition\":{\"field\":\"transactionTag\",\"operator\":\"hasAny\",\"value\":[\"2003\"]},\"relation\":\"or\"}" +
+		"],\"id\":\"1002\",\"name\":\"Query 1001\"}]}"
+
+	assert.Equal(t, expectedData, idMapper.getNewInsightsExplorerData(oldData))
+	assert.Equal(t, "invalid 1001", idMapper.getNewInsightsExplorerData("invalid 1001"))
+}
+
+func TestUserDataBackupIdMapperRemapUserDataBackup(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+	backup := &models.UserDataBackup{
+		Uid:      1,
+		Settings: &models.UserDataBackupSettings{DefaultAccountId: 1001},
+		Accounts: []*models.Account{
+			{AccountId: 1001, Uid: 1},
+			{AccountId: 1002, Uid: 1, ParentAccountId: 1001},
+		},
+		Tags: []*models.TransactionTag{
+			{TagId: 1003, Uid: 1, TagGroupId: 1005},
+		},
+		ApplicationCloudSettings: &models.UserApplicationCloudSetting{
+			Uid: 1,
+			Settings: models.ApplicationCloudSettingSlice{
+				{SettingKey: "totalAmountExcludeAccountIds", SettingValue: "{\"1002\":true}"},
+				{SettingKey: "lastSelectedFileTypeInImportTransactionDialog", SettingValue: "1001"},
+			},
+		},
+	}
+
+	err := idMapper.remapUserDataBackup(backup)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), backup.Uid)
+	assert.Equal(t, int64(2001), backup.Settings.DefaultAccountId)
+	assert.Equal(t, int64(2001), backup.Accounts[0].AccountId)
+	assert.Equal(t, int64(2), backup.Accounts[0].Uid)
+	assert.Equal(t, int64(2002), backup.Accounts[1].AccountId)
+	assert.Equal(t, int64(2001), backup.Accounts[1].ParentAccountId)
+	assert.Equal(t, int64(2003), backup.Tags[0].TagId)
+	assert.Equal(t, int64(0), backup.Tags[0].TagGroupId)
+	assert.Equal(t, int64(2), backup.ApplicationCloudSettings.Uid)
+	assert.Equal(t, "{\"2002\":true}", backup.ApplicationCloudSettings.Settings[0].SettingValue)
+	assert.Equal(t, "1001", backup.ApplicationCloudSettings.Settings[1].SettingValue)
+}
+
+func TestUserDataBackupIdMapperRemapUserDataBackup_TransactionHistories(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+	changes := models.TransactionHistoryFieldChanges{
+		{Field: models.TransactionHistoryFieldCategoryId, OldValue: "1002", NewValue: "1004"},
+		{Field: models.TransactionHistoryFieldTagIds, OldValue: "1001,1003", NewValue: "1003,1004"},
+		{Field: models.TransactionHistoryFieldAmount, OldValue: "1001", NewValue: "1002"},
+		{Field: models.TransactionHistoryFieldComment, OldValue: "paid 1003", NewValue: "1001"},
+	}
+	backup := &models.UserDataBackup{
+		Uid:      1,
+		Settings: &models.UserDataBackupSettings{},
+		Histories: []*models.TransactionHistory{
+			{HistoryId: 1001, Uid: 1, OperatorUid: 3, TransactionId: 1002, Changes: &changes},
+		},
+	}
+
+	err := idMapper.remapUserDataBackup(backup)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2001), backup.Histories[0].HistoryId)
+	assert.Equal(t, int64(0), backup.Histories[0].OperatorUid)
+	assert.Equal(t, int64(2002), backup.Histories[0].TransactionId)
+
+	assert.Equal(t, "2002", changes[0].OldValue)
+	assert.Equal(t, "0", changes[0].NewValue)
+	assert.Equal(t, "2001,2003", changes[1].OldValue)
+	assert.Equal(t, "2003", changes[1].NewValue)
+	assert.Equal(t, "1001", changes[2].OldValue)
+	assert.Equal(t, "1002", changes[2].NewValue)
+	assert.Equal(t, "paid 1003", changes[3].OldValue)
+	assert.Equal(t, "1001", changes[3].NewValue)
+}
+
+func TestUserDataBackupIdMapperRemapUserDataBackup_InvalidReference(t *testing.T) {
+	idMapper := createTestUserDataBackupIdMapper()
+	backup := &models.UserDataBackup{
+		Uid:      1,
+		Settings: &models.UserDataBackupSettings{},
+		Transactions: []*models.Transaction{
+			{TransactionId: 1001, Uid: 1, CategoryId: 1002, AccountId: 1004},
+		},
+	}
+
+	err := idMapper.remapUserDataBackup(backup)
+	assert.EqualError(t, err, errs.ErrUserDataBackupInvalid.Message)
+}
+
+func TestUserDataBackupServiceRestoreBackupArchive_InvalidPictureExtension(t *testing.T) {
+	data := createTestUserDataBackupArchive(t, map[string]string{
+		"backup.json":                     "{\"version\":1,\"uid\":\"1\",\"settings\":{},\"pictureInfos\":[{\"pictureId\":1001,\"uid\":1,\"pictureExtension\":\"png/../../../../x\"}]}",
+		"pictures/1001.png/../../../../x": "data",
+	})
+
+	err := UserDataBackups.RestoreBackupArchive(core.NewNullContext(), &models.User{Uid: 2}, data)
+	assert.EqualError(t, err, errs.ErrUserDataBackupInvalid.Message)
+}
+
+func TestUserDataBackupServiceRestoreBackupArchive_InvalidAvatarExtension(t *testing.T) {
+	data := createTestUserDataBackupArchive(t, map[string]string{
+		"backup.json":        "{\"version\":1,\"uid\":\"1\",\"settings\":{\"customAvatarType\":\"png/../../x\"}}",
+		"avatar.png/../../x": "data",
+	})
+
+	err := UserDataBackups.RestoreBackupArchive(core.NewNullContext(), &models.User{Uid: 2}, data)
+	assert.EqualError(t, err, errs.ErrUserDataBackupInvalid.Message)
+}
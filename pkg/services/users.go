@@ -471,6 +471,30 @@ func (s *UserService) RemoveUserAvatar(c core.Context, uid int64, fileExtension
 	})
 }
 
+// UpdateUserTransactionLockTime updates the transaction lock date of user
+func (s *UserService) UpdateUserTransactionLockTime(c core.Context, uid int64, lockTime int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	updateModel := &models.User{
+		TransactionLockTime: lockTime,
+		UpdatedUnixTime:     time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(uid).Cols("transaction_lock_time", "updated_unix_time").Where("deleted=?", false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrUserNotFound
+		}
+
+		return nil
+	})
+}
+
 // UpdateUserLastLoginTime updates the last login time field
 func (s *UserService) UpdateUserLastLoginTime(c core.Context, uid int64) error {
 	if uid <= 0 {
@@ -128,24 +128,34 @@ const (
 
 // Exchange rates data source types
 const (
+	ReserveBankOfAustraliaDataSource  string = "reserve_bank_of_australia"
 	BankOfCanadaDataSource            string = "bank_of_canada"
+	PeoplesBankOfChinaDataSource      string = "peoples_bank_of_china"
 	CzechNationalBankDataSource       string = "czech_national_bank"
 	DanmarksNationalbankDataSource    string = "danmarks_national_bank"
 	EuroCentralBankDataSource         string = "euro_central_bank"
 	NationalBankOfGeorgiaDataSource   string = "national_bank_of_georgia"
 	CentralBankOfHungaryDataSource    string = "central_bank_of_hungary"
 	BankOfIsraelDataSource            string = "bank_of_israel"
+	BankOfJapanDataSource             string = "bank_of_japan"
 	CentralBankOfMyanmarDataSource    string = "central_bank_of_myanmar"
 	NorgesBankDataSource              string = "norges_bank"
 	NationalBankOfPolandDataSource    string = "national_bank_of_poland"
 	NationalBankOfRomaniaDataSource   string = "national_bank_of_romania"
 	BankOfRussiaDataSource            string = "bank_of_russia"
 	SwissNationalBankDataSource       string = "swiss_national_bank"
+	CentralBankOfTurkeyDataSource     string = "central_bank_of_turkey"
 	NationalBankOfUkraineDataSource   string = "national_bank_of_ukraine"
+	BankOfEnglandDataSource           string = "bank_of_england"
 	CentralBankOfUzbekistanDataSource string = "central_bank_of_uzbekistan"
 	UserCustomExchangeRatesDataSource string = "user_custom"
 )
 
+// Security prices data source types
+const (
+	UserCustomSecurityPricesDataSource string = "user_custom"
+)
+
 const (
 	defaultHttpAddr string = "0.0.0.0"
 	defaultHttpPort uint16 = 8080
@@ -173,6 +183,10 @@ const (
 	defaultInMemoryDuplicateCheckerCleanupInterval uint32 = 60  // 1 minutes
 	defaultDuplicateSubmissionsInterval            uint32 = 300 // 5 minutes
 
+	defaultMaxScheduledTransactionCatchUpDays uint32 = 31  // days
+	maximumScheduledTransactionCatchUpDays    uint32 = 366 // days
+	defaultDeletedDataRetentionDays           uint32 = 30  // days
+
 	defaultSecretKey                     string = "ezbookkeeping"
 	defaultTokenExpiredTime              uint32 = 2592000 // 30 days
 	defaultTokenMinRefreshInterval       uint32 = 86400   // 1 day
@@ -391,8 +405,15 @@ type Config struct {
 	DuplicateSubmissionsIntervalDuration            time.Duration
 
 	// Cron
-	EnableRemoveExpiredTokens        bool
-	EnableCreateScheduledTransaction bool
+	EnableRemoveExpiredTokens            bool
+	EnableCreateScheduledTransaction     bool
+	EnableScheduledTransactionCatchUp    bool
+	CatchUpScheduledTransactionOnStartup bool
+	MaxScheduledTransactionCatchUpDays   uint32
+	EnablePurgeExpiredDeletedData        bool
+	DeletedDataRetentionDays             uint32
+	EnableSaveExchangeRatesSnapshot      bool
+	EnableUpdateNetWorthSnapshots        bool
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -478,10 +499,15 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource                       string
+	ExchangeRatesFallbackDataSources              []string
+	ExchangeRatesDataSourceMaxAgeHours            map[string]uint32
 	ExchangeRatesRequestTimeout                   uint32
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+
+	// Security Prices
+	SecurityPricesDataSource string
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -655,6 +681,12 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadSecurityPricesConfiguration(config, cfgFile, "security_prices")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -1029,6 +1061,23 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableScheduledTransactionCatchUp = getConfigItemBoolValue(configFile, sectionName, "enable_scheduled_transaction_catch_up", false)
+	config.CatchUpScheduledTransactionOnStartup = getConfigItemBoolValue(configFile, sectionName, "catch_up_scheduled_transaction_on_startup", false)
+	config.MaxScheduledTransactionCatchUpDays = getConfigItemUint32Value(configFile, sectionName, "max_scheduled_transaction_catch_up_days", defaultMaxScheduledTransactionCatchUpDays)
+	config.EnablePurgeExpiredDeletedData = getConfigItemBoolValue(configFile, sectionName, "enable_purge_expired_deleted_data", false)
+	config.DeletedDataRetentionDays = getConfigItemUint32Value(configFile, sectionName, "deleted_data_retention_days", defaultDeletedDataRetentionDays)
+	config.EnableSaveExchangeRatesSnapshot = getConfigItemBoolValue(configFile, sectionName, "enable_save_exchange_rates_snapshot", false)
+	config.EnableUpdateNetWorthSnapshots = getConfigItemBoolValue(configFile, sectionName, "enable_update_net_worth_snapshots", false)
+
+	if config.MaxScheduledTransactionCatchUpDays < 1 {
+		config.MaxScheduledTransactionCatchUpDays = defaultMaxScheduledTransactionCatchUpDays
+	} else if config.MaxScheduledTransactionCatchUpDays > maximumScheduledTransactionCatchUpDays {
+		config.MaxScheduledTransactionCatchUpDays = maximumScheduledTransactionCatchUpDays
+	}
+
+	if config.DeletedDataRetentionDays < 1 {
+		config.DeletedDataRetentionDays = defaultDeletedDataRetentionDays
+	}
 
 	return nil
 }
@@ -1262,36 +1311,104 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
 
-	if dataSource == BankOfCanadaDataSource ||
+	if isValidExchangeRatesDataSource(dataSource) {
+		config.ExchangeRatesDataSource = dataSource
+	} else {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	allDataSources := map[string]bool{
+		dataSource: true,
+	}
+
+	fallbackDataSources := strings.Split(getConfigItemStringValue(configFile, sectionName, "fallback_data_sources"), ",")
+	config.ExchangeRatesFallbackDataSources = make([]string, 0, len(fallbackDataSources))
+
+	for i := 0; i < len(fallbackDataSources); i++ {
+		fallbackDataSource := strings.TrimSpace(fallbackDataSources[i])
+
+		if fallbackDataSource == "" {
+			continue
+		}
+
+		if !isValidExchangeRatesDataSource(fallbackDataSource) || allDataSources[fallbackDataSource] {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		allDataSources[fallbackDataSource] = true
+		config.ExchangeRatesFallbackDataSources = append(config.ExchangeRatesFallbackDataSources, fallbackDataSource)
+	}
+
+	dataSourceMaxAges := strings.Split(getConfigItemStringValue(configFile, sectionName, "data_source_max_age_hours"), ",")
+	config.ExchangeRatesDataSourceMaxAgeHours = make(map[string]uint32, len(dataSourceMaxAges))
+
+	for i := 0; i < len(dataSourceMaxAges); i++ {
+		dataSourceMaxAge := strings.TrimSpace(dataSourceMaxAges[i])
+
+		if dataSourceMaxAge == "" {
+			continue
+		}
+
+		items := strings.Split(dataSourceMaxAge, ":")
+
+		if len(items) != 2 || !allDataSources[strings.TrimSpace(items[0])] {
+			return errs.ErrInvalidExchangeRatesDataSourceMaxAge
+		}
+
+		maxAgeHours, err := strconv.ParseUint(strings.TrimSpace(items[1]), 10, 32)
+
+		if err != nil {
+			return errs.ErrInvalidExchangeRatesDataSourceMaxAge
+		}
+
+		config.ExchangeRatesDataSourceMaxAgeHours[strings.TrimSpace(items[0])] = uint32(maxAgeHours)
+	}
+
+	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+
+	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
+		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
+	}
+
+	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+
+	return nil
+}
+
+func isValidExchangeRatesDataSource(dataSource string) bool {
+	return dataSource == ReserveBankOfAustraliaDataSource ||
+		dataSource == BankOfCanadaDataSource ||
+		dataSource == PeoplesBankOfChinaDataSource ||
 		dataSource == CzechNationalBankDataSource ||
 		dataSource == DanmarksNationalbankDataSource ||
 		dataSource == EuroCentralBankDataSource ||
 		dataSource == NationalBankOfGeorgiaDataSource ||
 		dataSource == CentralBankOfHungaryDataSource ||
 		dataSource == BankOfIsraelDataSource ||
+		dataSource == BankOfJapanDataSource ||
 		dataSource == CentralBankOfMyanmarDataSource ||
 		dataSource == NorgesBankDataSource ||
 		dataSource == NationalBankOfPolandDataSource ||
 		dataSource == NationalBankOfRomaniaDataSource ||
 		dataSource == BankOfRussiaDataSource ||
 		dataSource == SwissNationalBankDataSource ||
+		dataSource == CentralBankOfTurkeyDataSource ||
 		dataSource == NationalBankOfUkraineDataSource ||
+		dataSource == BankOfEnglandDataSource ||
 		dataSource == CentralBankOfUzbekistanDataSource ||
-		dataSource == UserCustomExchangeRatesDataSource {
-		config.ExchangeRatesDataSource = dataSource
-	} else {
-		return errs.ErrInvalidExchangeRatesDataSource
-	}
+		dataSource == UserCustomExchangeRatesDataSource
+}
 
-	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
-	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+func loadSecurityPricesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source", UserCustomSecurityPricesDataSource)
 
-	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
-		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
+	if dataSource == UserCustomSecurityPricesDataSource {
+		config.SecurityPricesDataSource = dataSource
+	} else {
+		return errs.ErrInvalidSecurityPricesDataSource
 	}
 
-	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
-
 	return nil
 }
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/ini.v1"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
 func TestLLMConfig_GetOpenAIBaseURL(t *testing.T) {
@@ -46,3 +48,47 @@ func TestLoadLLMConfiguration_ChatCompletionsStream(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, llmConfig.ChatCompletionsStream)
 }
+
+func TestLoadCronConfiguration_DeletedDataRetentionDays(t *testing.T) {
+	configFile, err := ini.Load([]byte("[cron_test]\nenable_purge_expired_deleted_data = true\ndeleted_data_retention_days = 7\n"))
+	assert.NoError(t, err)
+
+	config := &Config{}
+	err = loadCronConfiguration(config, configFile, "cron_test")
+	assert.NoError(t, err)
+	assert.True(t, config.EnablePurgeExpiredDeletedData)
+	assert.Equal(t, uint32(7), config.DeletedDataRetentionDays)
+
+	configFile, err = ini.Load([]byte("[cron_test]\ndeleted_data_retention_days = 0\n"))
+	assert.NoError(t, err)
+
+	config = &Config{}
+	err = loadCronConfiguration(config, configFile, "cron_test")
+	assert.NoError(t, err)
+	assert.False(t, config.EnablePurgeExpiredDeletedData)
+	assert.Equal(t, defaultDeletedDataRetentionDays, config.DeletedDataRetentionDays)
+}
+
+func TestLoadExchangeRatesConfiguration_FallbackDataSources(t *testing.T) {
+	configFile, err := ini.Load([]byte("[exchange_rates_test]\ndata_source = euro_central_bank\nfallback_data_sources = norges_bank, bank_of_canada\ndata_source_max_age_hours = euro_central_bank:72, norges_bank:96\n"))
+	assert.NoError(t, err)
+
+	config := &Config{}
+	err = loadExchangeRatesConfiguration(config, configFile, "exchange_rates_test")
+	assert.NoError(t, err)
+	assert.Equal(t, EuroCentralBankDataSource, config.ExchangeRatesDataSource)
+	assert.Equal(t, []string{NorgesBankDataSource, BankOfCanadaDataSource}, config.ExchangeRatesFallbackDataSources)
+	assert.Equal(t, map[string]uint32{EuroCentralBankDataSource: 72, NorgesBankDataSource: 96}, config.ExchangeRatesDataSourceMaxAgeHours)
+
+	configFile, err = ini.Load([]byte("[exchange_rates_test]\ndata_source = euro_central_bank\nfallback_data_sources = euro_central_bank\n"))
+	assert.NoError(t, err)
+
+	err = loadExchangeRatesConfiguration(&Config{}, configFile, "exchange_rates_test")
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSource, err)
+
+	configFile, err = ini.Load([]byte("[exchange_rates_test]\ndata_source = euro_central_bank\ndata_source_max_age_hours = norges_bank:96\n"))
+	assert.NoError(t, err)
+
+	err = loadExchangeRatesConfiguration(&Config{}, configFile, "exchange_rates_test")
+	assert.Equal(t, errs.ErrInvalidExchangeRatesDataSourceMaxAge, err)
+}
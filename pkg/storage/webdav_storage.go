@@ -107,7 +107,7 @@ func (s *WebDAVObjectStorage) Read(ctx core.Context, path string) (ObjectInStora
 		return nil, errs.ErrSystemError
 	}
 
-	return newByteSliceObject(body), nil
+	return NewByteSliceObject(body), nil
 }
 
 // Save returns whether save the object instance successfully
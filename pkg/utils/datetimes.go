@@ -155,6 +155,12 @@ func FormatUnixTimeToNumericYearMonth(unixTime int64, timezone *time.Location) i
 	return int32(t.Year())*100 + int32(t.Month())
 }
 
+// AddMonthsToNumericYearMonth returns the numeric year and month after adding the specified months to the numeric year and month
+func AddMonthsToNumericYearMonth(yearMonth int32, months int32) int32 {
+	totalMonths := (yearMonth/100)*12 + (yearMonth%100 - 1) + months
+	return (totalMonths/12)*100 + totalMonths%12 + 1
+}
+
 // FormatUnixTimeToNumericYearMonthDay returns numeric year, month and day of specified unix time
 func FormatUnixTimeToNumericYearMonthDay(unixTime int64, timezone *time.Location) int32 {
 	t := parseFromUnixTime(unixTime)
@@ -133,6 +133,15 @@ func TestFormatUnixTimeToNumericYearMonth(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestAddMonthsToNumericYearMonth(t *testing.T) {
+	assert.Equal(t, int32(202403), AddMonthsToNumericYearMonth(202403, 0))
+	assert.Equal(t, int32(202404), AddMonthsToNumericYearMonth(202403, 1))
+	assert.Equal(t, int32(202501), AddMonthsToNumericYearMonth(202412, 1))
+	assert.Equal(t, int32(202502), AddMonthsToNumericYearMonth(202403, 11))
+	assert.Equal(t, int32(202312), AddMonthsToNumericYearMonth(202401, -1))
+	assert.Equal(t, int32(202204), AddMonthsToNumericYearMonth(202403, -23))
+}
+
 func TestFormatUnixTimeToNumericYearMonthDay(t *testing.T) {
 	unixTime := int64(1617228083)
 	utcTimezone := time.FixedZone("Test Timezone", 0)      // UTC
@@ -16,4 +16,8 @@ const (
 	UUID_TYPE_PICTURE     UuidType = 8
 	UUID_TYPE_EXPLORER    UuidType = 9
 	UUID_TYPE_TAG_GROUP   UuidType = 10
+	UUID_TYPE_BUDGET      UuidType = 11
+	UUID_TYPE_LEDGER      UuidType = 12
+	UUID_TYPE_RULE        UuidType = 13
+	UUID_TYPE_PAYEE       UuidType = 14
 )
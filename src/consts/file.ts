@@ -345,6 +345,11 @@ export const SUPPORTED_IMPORT_FILE_CATEGORY_AND_TYPES: ImportFileCategoryAndType
                 name: 'Beancount Data File',
                 extensions: '.beancount'
             },
+            {
+                type: 'ledger',
+                name: 'ledger / hledger Journal File',
+                extensions: '.ledger,.journal,.hledger,.dat'
+            },
             {
                 type: 'feidee_mymoney_csv',
                 name: 'Feidee MyMoney (App) Data Export File',
@@ -480,10 +480,32 @@ export default {
             return axios.get<BlobPart>('v1/data/export.tsv?' + params, {
                 timeout: DEFAULT_EXPORT_API_TIMEOUT
             } as ApiRequestConfig);
+        } else if (fileType === 'ofx' || fileType === 'qif' || fileType === 'beancount' || fileType === 'ledger') {
+            return axios.get<BlobPart>(`v1/data/export.${fileType}?` + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
+        } else if (fileType === 'gnucash' || fileType === 'xlsx') {
+            return axios.get<BlobPart>(`v1/data/export.${fileType}?` + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT,
+                responseType: 'blob'
+            } as ApiRequestConfig);
         } else {
             return Promise.reject('Parameter Invalid');
         }
     },
+    backupUserData: (): Promise<AxiosResponse<BlobPart>> => {
+        return axios.get<BlobPart>('v1/data/backup.zip', {
+            timeout: DEFAULT_EXPORT_API_TIMEOUT,
+            responseType: 'blob'
+        } as ApiRequestConfig);
+    },
+    restoreUserData: ({ backupFile }: { backupFile: File }): ApiResponsePromise<boolean> => {
+        return axios.postForm<ApiResponse<boolean>>('v1/data/restore.json', {
+            file: backupFile
+        }, {
+            timeout: DEFAULT_UPLOAD_API_TIMEOUT
+        } as ApiRequestConfig);
+    },
     clearAllData: (req: ClearDataRequest): ApiResponsePromise<boolean> => {
         return axios.post<ApiResponse<boolean>>('v1/data/clear/all.json', req, {
             timeout: DEFAULT_CLEAR_ALL_TRANSACTIONS_API_TIMEOUT